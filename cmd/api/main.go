@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -37,12 +38,17 @@ import (
 	"go.uber.org/zap"
 
 	_ "turivo-backend/docs"
+	"turivo-backend/internal/domain"
 	"turivo-backend/internal/infrastructure/auth"
 	"turivo-backend/internal/infrastructure/config"
+	"turivo-backend/internal/infrastructure/dte"
 	"turivo-backend/internal/infrastructure/email"
 	"turivo-backend/internal/infrastructure/logging"
 	"turivo-backend/internal/infrastructure/payment"
 	"turivo-backend/internal/infrastructure/repository"
+	"turivo-backend/internal/infrastructure/scheduler"
+	"turivo-backend/internal/infrastructure/storage"
+	"turivo-backend/internal/infrastructure/tracking"
 	"turivo-backend/internal/interface/http/handler"
 	"turivo-backend/internal/interface/http/handlers"
 	"turivo-backend/internal/interface/http/middleware"
@@ -94,7 +100,6 @@ func main() {
 
 	// Initialize services
 	passwordService := auth.NewPasswordService()
-	paymentGateway := payment.NewWebpayMockGateway(logger)
 	emailService := email.NewSMTPService(email.SMTPConfig{
 		Host:     cfg.SMTP.Host,
 		Port:     cfg.SMTP.Port,
@@ -103,6 +108,145 @@ func main() {
 		From:     cfg.SMTP.From,
 	}, logger)
 
+	// Initialize file storage. Local files are served by the API through
+	// signed URLs; S3 hands out presigned bucket URLs instead.
+	var fileStorage domain.FileStorage
+	var fileHandler *handler.FileHandler
+	if cfg.Storage.Driver == "s3" {
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+		})
+		if err != nil {
+			logger.Fatal("Failed to initialize S3 storage", zap.Error(err))
+		}
+		fileStorage = s3Storage
+	} else {
+		filesURL := strings.TrimRight(cfg.Storage.PublicURL, "/") + "/api/v1/public/files"
+		localStorage, err := storage.NewLocalStorage(cfg.Storage.LocalPath, filesURL, cfg.JWT.Secret)
+		if err != nil {
+			logger.Fatal("Failed to initialize local storage", zap.Error(err))
+		}
+		fileStorage = localStorage
+		fileHandler = handler.NewFileHandler(localStorage, logger)
+	}
+
+	// Initialize the payment gateways. Outside mock mode Webpay Plus payments
+	// go through Transbank; the local environments of every redirect gateway
+	// use stand-ins served by this API.
+	paymentGateways := payment.NewRegistry()
+	// Stand-ins post their webhooks to this API
+	publicAPIURL := strings.TrimRight(cfg.Storage.PublicURL, "/") + "/api/v1/public"
+	var webpayStandIn *payment.WebpayStandIn
+	if cfg.Webpay.Environment == "mock" {
+		paymentGateways.Register(domain.PaymentGatewayWebpayPlus, payment.NewWebpayMockGateway(logger), "CLP")
+	} else {
+		webpayGateway, err := payment.NewWebpayPlusGateway(payment.WebpayConfig{
+			BaseURL:      cfg.Webpay.BaseURL,
+			CommerceCode: cfg.Webpay.CommerceCode,
+			APIKey:       cfg.Webpay.APIKey,
+			ReturnURL:    cfg.Webpay.ReturnURL,
+			Timeout:      cfg.Webpay.Timeout,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize Webpay Plus gateway", zap.Error(err))
+		}
+		paymentGateways.Register(domain.PaymentGatewayWebpayPlus, webpayGateway, "CLP")
+		if cfg.Webpay.Environment == "local" {
+			webpayStandIn = payment.NewWebpayStandIn(cfg.Webpay.CommerceCode, cfg.Webpay.APIKey)
+		}
+	}
+
+	var mercadoPagoStandIn *payment.MercadoPagoStandIn
+	if cfg.MercadoPago.Environment != "disabled" {
+		mercadoPagoGateway, err := payment.NewMercadoPagoGateway(payment.MercadoPagoConfig{
+			BaseURL:       cfg.MercadoPago.BaseURL,
+			AccessToken:   cfg.MercadoPago.AccessToken,
+			ReturnURL:     cfg.MercadoPago.ReturnURL,
+			WebhookSecret: cfg.MercadoPago.WebhookSecret,
+			Timeout:       cfg.MercadoPago.Timeout,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize Mercado Pago gateway", zap.Error(err))
+		}
+		paymentGateways.Register(domain.PaymentGatewayMercadoPago, mercadoPagoGateway, cfg.MercadoPago.Currencies...)
+		if cfg.MercadoPago.Environment == "local" {
+			mercadoPagoStandIn = payment.NewMercadoPagoStandIn(cfg.MercadoPago.AccessToken)
+			mercadoPagoStandIn.SetWebhook(publicAPIURL+"/payments/mercadopago/notifications", cfg.MercadoPago.WebhookSecret)
+		}
+	}
+
+	var stripeStandIn *payment.StripeStandIn
+	if cfg.Stripe.Environment != "disabled" {
+		stripeGateway, err := payment.NewStripeGateway(payment.StripeConfig{
+			BaseURL:       cfg.Stripe.BaseURL,
+			SecretKey:     cfg.Stripe.SecretKey,
+			ReturnURL:     cfg.Stripe.ReturnURL,
+			WebhookSecret: cfg.Stripe.WebhookSecret,
+			Timeout:       cfg.Stripe.Timeout,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize Stripe gateway", zap.Error(err))
+		}
+		paymentGateways.Register(domain.PaymentGatewayStripe, stripeGateway, cfg.Stripe.Currencies...)
+		if cfg.Stripe.Environment == "local" {
+			stripeStandIn = payment.NewStripeStandIn(cfg.Stripe.SecretKey)
+			stripeStandIn.SetWebhook(publicAPIURL+"/payments/stripe/notifications", cfg.Stripe.WebhookSecret)
+		}
+	}
+
+	if cfg.BankTransfer.Enabled {
+		paymentGateways.Register(domain.PaymentGatewayBankTransfer, payment.NewBankTransferGateway(payment.BankAccount{
+			BankName:      cfg.BankTransfer.BankName,
+			AccountType:   cfg.BankTransfer.AccountType,
+			AccountNumber: cfg.BankTransfer.AccountNumber,
+			AccountHolder: cfg.BankTransfer.AccountHolder,
+			HolderRUT:     cfg.BankTransfer.HolderRUT,
+			Email:         cfg.BankTransfer.Email,
+		}, logger), cfg.BankTransfer.Currencies...)
+	}
+
+	if cfg.CompanyCredit.Enabled {
+		paymentGateways.Register(domain.PaymentGatewayCompanyCredit, payment.NewCompanyCreditGateway(logger))
+	}
+
+	// Initialize electronic tax documents. Only the local tax authority
+	// stand-in is available so far.
+	var taxDocumentBuilder *dte.Builder
+	var taxAuthority domain.TaxAuthorityClient
+	if cfg.DTE.Environment != "disabled" {
+		var signer *dte.Signer
+		var err error
+		if cfg.DTE.CertFile != "" {
+			signer, err = dte.LoadSigner(cfg.DTE.CertFile, cfg.DTE.KeyFile)
+		} else {
+			signer, err = dte.GenerateSigner(cfg.DTE.IssuerName)
+		}
+		if err != nil {
+			logger.Fatal("Failed to load tax document certificate", zap.Error(err))
+		}
+
+		taxDocumentBuilder, err = dte.NewBuilder(dte.Issuer{
+			RUT:              cfg.DTE.IssuerRUT,
+			Name:             cfg.DTE.IssuerName,
+			Activity:         cfg.DTE.Activity,
+			ActivityCode:     cfg.DTE.ActivityCode,
+			Address:          cfg.DTE.Address,
+			Commune:          cfg.DTE.Commune,
+			ResolutionDate:   cfg.DTE.ResolutionDate,
+			ResolutionNumber: cfg.DTE.ResolutionNumber,
+			SenderRUT:        cfg.DTE.SenderRUT,
+		}, signer)
+		if err != nil {
+			logger.Fatal("Failed to initialize tax document builder", zap.Error(err))
+		}
+		taxAuthority = dte.NewLocalTaxAuthority(logger)
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(dbPool)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(dbPool)
@@ -114,32 +258,125 @@ func main() {
 	companyRepo := repository.NewCompanyRepository(sqlDB, logger)
 	vehicleRepo := repository.NewVehicleRepository(sqlDB, logger)
 	pricingRepo := repository.NewPricingRepository(sqlDB, logger)
+	tripOfferRepo := repository.NewTripOfferRepository(sqlDB, logger)
+	tripProgressRepo := repository.NewTripProgressRepository(sqlDB, logger)
+	driverLocationRepo := repository.NewDriverLocationRepository(sqlDB, logger)
+	trackingLinkRepo := repository.NewTrackingLinkRepository(sqlDB, logger)
+	feedbackRepo := repository.NewFeedbackRepository(sqlDB, logger)
+	complianceRepo := repository.NewComplianceRepository(sqlDB, logger)
+	documentRepo := repository.NewDocumentRepository(sqlDB, logger)
+	earningsRepo := repository.NewEarningsRepository(sqlDB, logger)
+	shiftRepo := repository.NewShiftRepository(sqlDB, logger)
+	maintenanceRepo := repository.NewMaintenanceRepository(sqlDB, logger)
+	tripUsageRepo := repository.NewTripUsageRepository(sqlDB, logger)
+	utilizationRepo := repository.NewUtilizationRepository(sqlDB, logger)
+	incidentRepo := repository.NewIncidentRepository(sqlDB, logger)
+	refundRepo := repository.NewRefundRepository(sqlDB, logger)
+	paymentNotificationRepo := repository.NewPaymentNotificationRepository(sqlDB, logger)
+	billingRepo := repository.NewBillingRepository(sqlDB, logger)
+	invoiceRepo := repository.NewInvoiceRepository(sqlDB, logger)
+	paymentMethodRepo := repository.NewPaymentMethodRepository(sqlDB, logger)
+	idempotencyRepo := repository.NewIdempotencyRepository(sqlDB, logger)
+	taxDocumentRepo := repository.NewTaxDocumentRepository(sqlDB, logger)
+	walletRepo := repository.NewWalletRepository(sqlDB, logger)
+	locationBroker := tracking.NewBroker(logger)
+	trackingTokenService := auth.NewTrackingTokenService(cfg.JWT.Secret)
+	feedbackTokenService := auth.NewFeedbackTokenService(cfg.JWT.Secret)
 
 	// Initialize use cases
 	authUseCase := usecase.NewAuthUseCase(userRepo, refreshTokenRepo, passwordResetTokenRepo, passwordService, emailService, cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL, logger)
 	userUseCase := usecase.NewUserUseCase(userRepo, registrationTokenRepo, passwordService, emailService, logger)
 	driverUseCase := usecase.NewDriverUseCase(driverRepo, userRepo, emailService, registrationTokenRepo, passwordService, logger)
-	reservationUseCase := usecase.NewReservationUseCase(reservationRepo, driverRepo, userRepo, emailService, logger)
-	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, reservationRepo, paymentGateway, logger)
-	companyUseCase := usecase.NewCompanyUseCase(companyRepo, logger)
-	vehicleUseCase := usecase.NewVehicleUseCase(vehicleRepo, driverRepo, logger)
+	complianceUseCase := usecase.NewComplianceUseCase(complianceRepo, driverRepo, vehicleRepo, userRepo, emailService, logger)
+	trackingLinkUseCase := usecase.NewTrackingLinkUseCase(trackingLinkRepo, trackingTokenService, reservationRepo, tripProgressRepo, driverRepo, driverLocationRepo, cfg.Tracking.LinkTTL, logger)
+	shiftUseCase := usecase.NewShiftUseCase(shiftRepo, driverRepo, vehicleRepo, cfg.Shifts.Enforce, logger)
 	pricingUseCase := usecase.NewPricingUseCase(pricingRepo, logger)
+	documentUseCase := usecase.NewDocumentUseCase(documentRepo, fileStorage, driverRepo, vehicleRepo, cfg.Storage.PublicURL, cfg.Storage.MaxUploadSize, cfg.Storage.SignedURLTTL, logger)
+	maintenanceUseCase := usecase.NewMaintenanceUseCase(maintenanceRepo, vehicleRepo, userRepo, emailService, documentUseCase, pricingUseCase, logger)
+	vehicleCapacityUseCase := usecase.NewVehicleCapacityUseCase(vehicleRepo, logger)
+	walletUseCase := usecase.NewWalletUseCase(walletRepo, companyRepo, emailService, pricingUseCase, logger)
+	reservationUseCase := usecase.NewReservationUseCase(reservationRepo, driverRepo, userRepo, emailService, trackingLinkUseCase, complianceUseCase, shiftUseCase, maintenanceUseCase, vehicleCapacityUseCase, walletUseCase, pricingUseCase, logger)
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, refundRepo, billingRepo, invoiceRepo, paymentMethodRepo, reservationRepo, walletRepo, walletUseCase, paymentGateways, pricingUseCase, logger)
+	paymentReconciliationUseCase := usecase.NewPaymentReconciliationUseCase(paymentRepo, paymentNotificationRepo, invoiceRepo, walletRepo, paymentGateways, cfg.Reconciliation.PendingAfter, cfg.Reconciliation.AbandonAfter, logger)
+	invoiceUseCase := usecase.NewInvoiceUseCase(invoiceRepo, companyRepo, pricingUseCase, cfg.Invoicing.TaxRate, cfg.Invoicing.PaymentTermsDays, logger)
+	companyUseCase := usecase.NewCompanyUseCase(companyRepo, logger)
+	vehicleUseCase := usecase.NewVehicleUseCase(vehicleRepo, driverRepo, complianceUseCase, logger)
+	tripOfferUseCase := usecase.NewTripOfferUseCase(tripOfferRepo, reservationRepo, driverRepo, complianceUseCase, shiftUseCase, maintenanceUseCase, vehicleCapacityUseCase, cfg.Dispatch.OfferTimeout, logger)
+	tripProgressUseCase := usecase.NewTripProgressUseCase(tripProgressRepo, reservationRepo, logger)
+	tripUsageUseCase := usecase.NewTripUsageUseCase(tripUsageRepo, reservationRepo, vehicleRepo, maintenanceRepo, pricingUseCase, logger)
+	utilizationUseCase := usecase.NewUtilizationUseCase(utilizationRepo, logger)
+	incidentUseCase := usecase.NewIncidentUseCase(incidentRepo, vehicleRepo, driverRepo, reservationRepo, documentUseCase, pricingUseCase, reservationUseCase, utilizationUseCase, logger)
+	trackingUseCase := usecase.NewTrackingUseCase(driverLocationRepo, tripProgressRepo, reservationRepo, locationBroker, cfg.Tracking.Retention, logger)
+	feedbackUseCase := usecase.NewFeedbackUseCase(feedbackRepo, feedbackTokenService, reservationRepo, userRepo, driverRepo, emailService, cfg.Feedback.Window, logger)
+	earningsUseCase := usecase.NewEarningsUseCase(earningsRepo, pricingUseCase, driverRepo, domain.SettlementPeriod(cfg.Earnings.SettlementPeriod), logger)
+	var taxDocumentUseCase *usecase.TaxDocumentUseCase
+	if taxDocumentBuilder != nil {
+		taxDocumentUseCase = usecase.NewTaxDocumentUseCase(taxDocumentRepo, invoiceRepo, paymentRepo, companyRepo, taxDocumentBuilder, taxAuthority, cfg.Invoicing.TaxRate, logger)
+	}
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authUseCase, validate, logger)
 	userHandler := handler.NewUserHandler(userUseCase, validate, logger)
 	driverHandler := handler.NewDriverHandler(driverUseCase, validate, logger)
-	driverDashboardHandler := handler.NewDriverDashboardHandler(driverUseCase, logger)
-	reservationHandler := handler.NewReservationHandler(reservationUseCase, validate, logger)
+	driverDashboardHandler := handler.NewDriverDashboardHandler(driverUseCase, earningsUseCase, validate, logger)
+	reservationHandler := handler.NewReservationHandler(reservationUseCase, vehicleCapacityUseCase, validate, logger)
 	paymentHandler := handler.NewPaymentHandler(paymentUseCase, validate, logger)
+	paymentReturnHandler := handler.NewPaymentReturnHandler(paymentUseCase, handler.PaymentFinalURLs{
+		Webpay:      cfg.Webpay.FinalURL,
+		MercadoPago: cfg.MercadoPago.FinalURL,
+		Stripe:      cfg.Stripe.FinalURL,
+	}, logger)
+	paymentReconciliationHandler := handler.NewPaymentReconciliationHandler(paymentReconciliationUseCase, logger)
 	companyHandler := handler.NewCompanyHandler(companyUseCase, validate, logger)
 	vehicleHandler := handler.NewVehicleHandler(vehicleUseCase, validate, logger)
 	supportHandler := handler.NewSupportHandler(emailService, userRepo, validate, logger)
 	billingHandler := handler.NewBillingHandler(paymentUseCase, logger)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceUseCase, validate, logger)
+	walletHandler := handler.NewWalletHandler(walletUseCase, paymentUseCase, validate, logger)
+	var taxDocumentHandler *handler.TaxDocumentHandler
+	if taxDocumentUseCase != nil {
+		taxDocumentHandler = handler.NewTaxDocumentHandler(taxDocumentUseCase, validate, logger)
+	}
 	pricingHandler := handlers.NewPricingHandler(pricingUseCase, logger)
+	tripOfferHandler := handler.NewTripOfferHandler(tripOfferUseCase, driverUseCase, validate, logger)
+	tripProgressHandler := handler.NewTripProgressHandler(tripProgressUseCase, driverUseCase, validate, logger)
+	tripUsageHandler := handler.NewTripUsageHandler(tripUsageUseCase, driverUseCase, validate, logger)
+	utilizationHandler := handler.NewUtilizationHandler(utilizationUseCase, validate, logger)
+	trackingLinkHandler := handler.NewTrackingLinkHandler(trackingLinkUseCase, logger)
+	trackingHandler := handler.NewTrackingHandler(trackingUseCase, driverUseCase, validate, cfg.Tracking.HeartbeatInterval, logger)
+	feedbackHandler := handler.NewFeedbackHandler(feedbackUseCase, driverUseCase, validate, logger)
+	complianceHandler := handler.NewComplianceHandler(complianceUseCase, driverUseCase, logger)
+	documentHandler := handler.NewDocumentHandler(documentUseCase, driverUseCase, validate, cfg.Storage.MaxUploadSize, logger)
+	earningsHandler := handler.NewEarningsHandler(earningsUseCase, driverUseCase, validate, logger)
+	shiftHandler := handler.NewShiftHandler(shiftUseCase, driverUseCase, validate, logger)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceUseCase, validate, cfg.Storage.MaxUploadSize, logger)
+	incidentHandler := handler.NewIncidentHandler(incidentUseCase, driverUseCase, validate, cfg.Storage.MaxUploadSize, logger)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authUseCase, logger)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepo, cfg.Idempotency.TTL, logger)
+
+	// Start background jobs
+	jobs := scheduler.New(logger)
+	jobs.Every("expire-trip-offers", cfg.Dispatch.SweepInterval, tripOfferUseCase.ExpireOffers)
+	jobs.Every("prune-driver-locations", cfg.Tracking.PruneInterval, trackingUseCase.PruneLocations)
+	jobs.Every("send-feedback-prompts", cfg.Feedback.PromptInterval, feedbackUseCase.SendFeedbackPrompts)
+	jobs.Every("send-compliance-reminders", cfg.Compliance.ReminderInterval, complianceUseCase.SendExpiryReminders)
+	jobs.Every("record-trip-payouts", cfg.Earnings.PayoutInterval, earningsUseCase.RecordTripPayouts)
+	jobs.Every("generate-settlements", cfg.Earnings.SettlementInterval, earningsUseCase.GenerateDueSettlements)
+	jobs.Every("apply-maintenance-windows", cfg.Maintenance.WindowInterval, maintenanceUseCase.ApplyMaintenanceWindows)
+	jobs.Every("send-maintenance-reminders", cfg.Maintenance.ReminderInterval, maintenanceUseCase.SendMaintenanceReminders)
+	jobs.Every("prune-idempotency-keys", cfg.Idempotency.PruneInterval, idempotencyMiddleware.PruneExpired)
+	jobs.Every("reconcile-pending-payments", cfg.Reconciliation.Interval, paymentReconciliationUseCase.ReconcilePending)
+	jobs.Every("accrue-billing", cfg.Invoicing.AccrualInterval, invoiceUseCase.AccrueCompletedTrips)
+	jobs.Every("close-billing-periods", cfg.Invoicing.CloseInterval, invoiceUseCase.CloseDuePeriods)
+	jobs.Every("debit-wallets", cfg.Wallets.DebitInterval, walletUseCase.DebitCompletedTrips)
+	if taxDocumentUseCase != nil {
+		jobs.Every("submit-tax-documents", cfg.DTE.SubmitInterval, taxDocumentUseCase.SubmitDocuments)
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.Start(jobsCtx)
 
 	// Set Gin mode based on log level
 	if cfg.Log.Level == "debug" {
@@ -169,6 +406,20 @@ func main() {
 	// Swagger documentation
 	ginRouter.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Local gateway stand-ins, only in their local environments
+	if webpayStandIn != nil {
+		ginRouter.Any("/webpay-standin/*path", gin.WrapH(webpayStandIn))
+		logger.Info("Serving Webpay stand-in", zap.String("base_url", cfg.Webpay.BaseURL))
+	}
+	if mercadoPagoStandIn != nil {
+		ginRouter.Any("/mercadopago-standin/*path", gin.WrapH(mercadoPagoStandIn))
+		logger.Info("Serving Mercado Pago stand-in", zap.String("base_url", cfg.MercadoPago.BaseURL))
+	}
+	if stripeStandIn != nil {
+		ginRouter.Any("/stripe-standin/*path", gin.WrapH(stripeStandIn))
+		logger.Info("Serving Stripe stand-in", zap.String("base_url", cfg.Stripe.BaseURL))
+	}
+
 	// Create admin handler
 	adminHandler := handler.NewAdminHandler(
 		*userRepo,
@@ -188,20 +439,39 @@ func main() {
 
 	// Setup API routes
 	router.SetupRoutes(ginRouter, router.RouteHandlers{
-		Auth:            authHandler,
-		User:            userHandler,
-		Driver:          driverHandler,
-		DriverDashboard: driverDashboardHandler,
-		Reservation:     reservationHandler,
-		Payment:         paymentHandler,
-		Company:         companyHandler,
-		CompanyDetail:   companyDetailHandler,
-		Vehicle:         vehicleHandler,
-		Support:         supportHandler,
-		Admin:           adminHandler,
-		Billing:         billingHandler,
-		Pricing:         pricingHandler,
-	}, authMiddleware)
+		Auth:                  authHandler,
+		User:                  userHandler,
+		Driver:                driverHandler,
+		DriverDashboard:       driverDashboardHandler,
+		Reservation:           reservationHandler,
+		Payment:               paymentHandler,
+		PaymentReturn:         paymentReturnHandler,
+		PaymentReconciliation: paymentReconciliationHandler,
+		Company:               companyHandler,
+		CompanyDetail:         companyDetailHandler,
+		Vehicle:               vehicleHandler,
+		Support:               supportHandler,
+		Admin:                 adminHandler,
+		Billing:               billingHandler,
+		Invoice:               invoiceHandler,
+		Wallet:                walletHandler,
+		TaxDocument:           taxDocumentHandler,
+		Pricing:               pricingHandler,
+		TripOffer:             tripOfferHandler,
+		TripProgress:          tripProgressHandler,
+		Tracking:              trackingHandler,
+		TrackingLink:          trackingLinkHandler,
+		Feedback:              feedbackHandler,
+		Compliance:            complianceHandler,
+		Document:              documentHandler,
+		File:                  fileHandler,
+		Earnings:              earningsHandler,
+		Shift:                 shiftHandler,
+		Maintenance:           maintenanceHandler,
+		TripUsage:             tripUsageHandler,
+		Utilization:           utilizationHandler,
+		Incident:              incidentHandler,
+	}, authMiddleware, idempotencyMiddleware)
 
 	// Start server
 	// Use PORT environment variable if available, otherwise use config
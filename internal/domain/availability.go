@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ChileanRegionCodes are the region codes drivers can declare availability for.
+var ChileanRegionCodes = []string{
+	"XV", "I", "II", "III", "IV", "V", "RM", "VI", "VII", "XVI", "VIII", "IX", "XIV", "X", "XI", "XII",
+}
+
+// Weekdays lists availability day names in calendar order, starting on Monday.
+var Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+type UpdateDriverLicenseRequest struct {
+	Number    string       `json:"number" validate:"required,min=3,max=50"`
+	Class     LicenseClass `json:"class" validate:"required,oneof=A1 A2 A3 A4 A5 B C D E"`
+	IssuedAt  *time.Time   `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+type UpdateDriverBackgroundCheckRequest struct {
+	Status    BackgroundCheckStatus `json:"status" validate:"required,oneof=APPROVED PENDING REJECTED"`
+	CheckedAt *time.Time            `json:"checked_at,omitempty"`
+}
+
+type UpdateDriverAvailabilityRequest struct {
+	Regions    []string    `json:"regions" validate:"required,min=1,unique,dive,oneof=XV I II III IV V RM VI VII XVI VIII IX XIV X XI XII"`
+	Days       []string    `json:"days" validate:"required,min=1,unique,dive,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	TimeRanges []TimeRange `json:"time_ranges" validate:"required,min=1,max=10"`
+}
+
+// AvailabilitySlot is one concrete window in which a driver can take trips.
+// Times are local wall-clock times in HH:MM.
+type AvailabilitySlot struct {
+	Date string `json:"date"`
+	Day  string `json:"day"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type AvailabilityCalendar struct {
+	DriverID  string             `json:"driver_id"`
+	WeekStart string             `json:"week_start"`
+	WeekEnd   string             `json:"week_end"`
+	Regions   []string           `json:"regions"`
+	Slots     []AvailabilitySlot `json:"slots"`
+}
+
+// ValidateTimeRanges checks that every range is HH:MM, ends after it starts
+// and does not overlap another range. "24:00" is accepted as an end of day.
+func ValidateTimeRanges(ranges []TimeRange) error {
+	type span struct{ from, to int }
+	spans := make([]span, 0, len(ranges))
+
+	for _, r := range ranges {
+		from, err := parseTimeOfDay(r.From, false)
+		if err != nil {
+			return err
+		}
+		to, err := parseTimeOfDay(r.To, true)
+		if err != nil {
+			return err
+		}
+		if to <= from {
+			return fmt.Errorf("%w: %s-%s ends before it starts", ErrInvalidTimeRange, r.From, r.To)
+		}
+		spans = append(spans, span{from, to})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from < spans[j].from })
+	for i := 1; i < len(spans); i++ {
+		if spans[i].from < spans[i-1].to {
+			return fmt.Errorf("%w: time ranges overlap", ErrInvalidTimeRange)
+		}
+	}
+
+	return nil
+}
+
+// WeekStart returns midnight of the Monday of the week containing t.
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// ExpandAvailability turns a weekly availability pattern into the concrete
+// slots of the week starting at weekStart, ordered by date and start time.
+func ExpandAvailability(availability *DriverAvailability, weekStart time.Time) []AvailabilitySlot {
+	slots := []AvailabilitySlot{}
+	if availability == nil {
+		return slots
+	}
+
+	days := make(map[string]bool, len(availability.Days))
+	for _, day := range availability.Days {
+		days[day] = true
+	}
+
+	ranges := append([]TimeRange(nil), availability.TimeRanges...)
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From < ranges[j].From })
+
+	for i, day := range Weekdays {
+		if !days[day] {
+			continue
+		}
+		date := weekStart.AddDate(0, 0, i).Format("2006-01-02")
+		for _, r := range ranges {
+			slots = append(slots, AvailabilitySlot{Date: date, Day: day, From: r.From, To: r.To})
+		}
+	}
+
+	return slots
+}
+
+// parseTimeOfDay returns the minutes since midnight for an HH:MM value.
+func parseTimeOfDay(value string, endOfDay bool) (int, error) {
+	if endOfDay && value == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("%w: %q is not a valid HH:MM time", ErrInvalidTimeRange, value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
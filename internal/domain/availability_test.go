@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTimeRanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []TimeRange
+		valid  bool
+	}{
+		{"single range", []TimeRange{{From: "08:00", To: "18:00"}}, true},
+		{"adjacent ranges", []TimeRange{{From: "13:00", To: "18:00"}, {From: "08:00", To: "13:00"}}, true},
+		{"until midnight", []TimeRange{{From: "20:00", To: "24:00"}}, true},
+		{"overlapping", []TimeRange{{From: "08:00", To: "14:00"}, {From: "13:00", To: "18:00"}}, false},
+		{"ends before start", []TimeRange{{From: "18:00", To: "08:00"}}, false},
+		{"empty range", []TimeRange{{From: "08:00", To: "08:00"}}, false},
+		{"bad format", []TimeRange{{From: "8:00", To: "18:00"}}, false},
+		{"midnight as start", []TimeRange{{From: "24:00", To: "24:00"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTimeRanges(tt.ranges)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidTimeRange), err)
+			}
+		})
+	}
+}
+
+func TestExpandAvailability(t *testing.T) {
+	// Wednesday 2026-10-21 belongs to the week starting Monday 2026-10-19
+	weekStart := WeekStart(time.Date(2026, 10, 21, 15, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2026-10-19", weekStart.Format("2006-01-02"))
+
+	slots := ExpandAvailability(&DriverAvailability{
+		Days:       []string{"sunday", "monday"},
+		TimeRanges: []TimeRange{{From: "14:00", To: "18:00"}, {From: "08:00", To: "12:00"}},
+	}, weekStart)
+
+	assert.Equal(t, []AvailabilitySlot{
+		{Date: "2026-10-19", Day: "monday", From: "08:00", To: "12:00"},
+		{Date: "2026-10-19", Day: "monday", From: "14:00", To: "18:00"},
+		{Date: "2026-10-25", Day: "sunday", From: "08:00", To: "12:00"},
+		{Date: "2026-10-25", Day: "sunday", From: "14:00", To: "18:00"},
+	}, slots)
+	assert.Empty(t, ExpandAvailability(nil, weekStart))
+}
+
+func TestUpdateDriverAvailabilityRequestValidation(t *testing.T) {
+	validate := validator.New()
+	ranges := []TimeRange{{From: "08:00", To: "18:00"}}
+
+	assert.NoError(t, validate.Struct(UpdateDriverAvailabilityRequest{
+		Regions: []string{"RM", "V"}, Days: []string{"monday"}, TimeRanges: ranges,
+	}))
+	assert.Error(t, validate.Struct(UpdateDriverAvailabilityRequest{
+		Regions: []string{"XX"}, Days: []string{"monday"}, TimeRanges: ranges,
+	}))
+	assert.Error(t, validate.Struct(UpdateDriverAvailabilityRequest{
+		Regions: []string{"RM"}, Days: []string{"lunes"}, TimeRanges: ranges,
+	}))
+	assert.Error(t, validate.Struct(UpdateDriverAvailabilityRequest{
+		Regions: []string{"RM"}, Days: []string{"monday", "monday"}, TimeRanges: ranges,
+	}))
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyPaymentFilter selects a company's payments: those of its
+// reservations, its invoices and its wallet top-ups. CostCenter and UserID
+// only match reservation payments. From and To bound the payment creation time as [From, To).
+type CompanyPaymentFilter struct {
+	CompanyID  uuid.UUID
+	Status     *PaymentStatus
+	From       *time.Time
+	To         *time.Time
+	CostCenter *string
+	UserID     *uuid.UUID
+	Page       int
+	PageSize   int
+}
+
+// Validate rejects unknown statuses and empty periods.
+func (f CompanyPaymentFilter) Validate() error {
+	if f.Status != nil {
+		switch *f.Status {
+		case PaymentStatusPending, PaymentStatusApproved, PaymentStatusRejected,
+			PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		default:
+			return ErrInvalidInput
+		}
+	}
+	if f.From != nil && f.To != nil && !f.To.After(*f.From) {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// CompanyPayment is a payment with the reservation and booker it was made
+// for, as a company's finance team sees it. Invoice payments and wallet
+// top-ups carry no reservation details.
+type CompanyPayment struct {
+	Payment
+	Pickup              string     `json:"pickup,omitempty"`
+	Destination         string     `json:"destination,omitempty"`
+	ReservationDateTime *time.Time `json:"reservation_datetime,omitempty"`
+	CostCenter          *string    `json:"cost_center,omitempty"`
+	UserID              *uuid.UUID `json:"user_id,omitempty"`
+	UserName            *string    `json:"user_name,omitempty"`
+	UserEmail           *string    `json:"user_email,omitempty"`
+}
+
+// PaymentStatusTotal adds up the payments of one status and currency.
+type PaymentStatusTotal struct {
+	Status   PaymentStatus `json:"status"`
+	Currency string        `json:"currency"`
+	Count    int           `json:"count"`
+	Amount   float64       `json:"amount"`
+}
+
+// PaymentMonthTotal adds up the payments created in one month (YYYY-MM) in
+// one currency. CapturedAmount only counts approved or refunded payments, net
+// of their completed refunds.
+type PaymentMonthTotal struct {
+	Month          string  `json:"month"`
+	Currency       string  `json:"currency"`
+	Count          int     `json:"count"`
+	Amount         float64 `json:"amount"`
+	CapturedAmount float64 `json:"captured_amount"`
+}
+
+// CompanyPaymentTotals sums up every payment matching a filter, not only
+// the page returned.
+type CompanyPaymentTotals struct {
+	ByStatus []PaymentStatusTotal `json:"by_status"`
+	ByMonth  []PaymentMonthTotal  `json:"by_month"`
+}
+
+type CompanyPaymentList struct {
+	Payments []*CompanyPayment    `json:"payments"`
+	Total    int                  `json:"total"`
+	Totals   CompanyPaymentTotals `json:"totals"`
+}
+
+type CompanyBillingRepository interface {
+	// ListPayments returns up to limit payments matching the filter, newest
+	// first, and how many match in all.
+	ListPayments(filter CompanyPaymentFilter, limit, offset int) ([]*CompanyPayment, int, error)
+	SummarizePayments(filter CompanyPaymentFilter) (*CompanyPaymentTotals, error)
+}
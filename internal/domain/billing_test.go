@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyPaymentFilter_Validate(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	filter := CompanyPaymentFilter{CompanyID: uuid.New(), From: &from, To: &to}
+	assert.NoError(t, filter.Validate())
+
+	refunded := PaymentStatusPartiallyRefunded
+	filter.Status = &refunded
+	assert.NoError(t, filter.Validate())
+
+	unknown := PaymentStatus("PAID")
+	filter.Status = &unknown
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidInput)
+
+	filter.Status = nil
+	filter.To = &from
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidInput)
+}
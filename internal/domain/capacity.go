@@ -0,0 +1,93 @@
+package domain
+
+import "sort"
+
+// VehicleCapacity is the passenger capacity of an active vehicle.
+type VehicleCapacity struct {
+	VehicleID string      `json:"vehicle_id"`
+	Type      VehicleType `json:"type"`
+	Capacity  int         `json:"capacity"`
+}
+
+// VehicleSplitItem is one vehicle of a suggested split and the passengers it
+// would carry.
+type VehicleSplitItem struct {
+	VehicleID  string      `json:"vehicle_id"`
+	Type       VehicleType `json:"type"`
+	Capacity   int         `json:"capacity"`
+	Passengers int         `json:"passengers"`
+}
+
+// VehicleSplit suggests how to spread a group over several vehicles. Covered
+// is false when the whole active fleet cannot carry the group.
+type VehicleSplit struct {
+	Passengers    int                `json:"passengers"`
+	VehicleType   *VehicleType       `json:"vehicle_type,omitempty"`
+	Vehicles      []VehicleSplitItem `json:"vehicles"`
+	TotalCapacity int                `json:"total_capacity"`
+	Unassigned    int                `json:"unassigned"`
+	Covered       bool               `json:"covered"`
+}
+
+type VehicleSplitRequest struct {
+	Passengers  int          `json:"passengers" validate:"required,min=1"`
+	VehicleType *VehicleType `json:"vehicle_type,omitempty" validate:"omitempty,oneof=BUS VAN SEDAN SUV"`
+}
+
+// SuggestVehicleSplit spreads passengers over as few vehicles as possible:
+// while no single vehicle fits the rest of the group the largest one is
+// filled, then the smallest vehicle that fits the remainder closes the split.
+func SuggestVehicleSplit(passengers int, vehicles []VehicleCapacity) *VehicleSplit {
+	available := make([]VehicleCapacity, 0, len(vehicles))
+	for _, vehicle := range vehicles {
+		if vehicle.Capacity > 0 {
+			available = append(available, vehicle)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Capacity > available[j].Capacity
+	})
+
+	split := &VehicleSplit{
+		Passengers: passengers,
+		Vehicles:   []VehicleSplitItem{},
+	}
+	remaining := passengers
+	for remaining > 0 && len(available) > 0 {
+		// available is sorted largest first, so the last fitting vehicle is
+		// the smallest one that can take everyone left
+		pick := 0
+		for i, vehicle := range available {
+			if vehicle.Capacity >= remaining {
+				pick = i
+			}
+		}
+
+		vehicle := available[pick]
+		available = append(available[:pick], available[pick+1:]...)
+
+		seats := vehicle.Capacity
+		if seats > remaining {
+			seats = remaining
+		}
+		split.Vehicles = append(split.Vehicles, VehicleSplitItem{
+			VehicleID:  vehicle.VehicleID,
+			Type:       vehicle.Type,
+			Capacity:   vehicle.Capacity,
+			Passengers: seats,
+		})
+		split.TotalCapacity += vehicle.Capacity
+		remaining -= seats
+	}
+
+	split.Unassigned = remaining
+	split.Covered = remaining == 0
+	return split
+}
+
+// CapacityChecker guards bookings and driver assignments against vehicles of
+// the wrong type or too small for the group.
+type CapacityChecker interface {
+	EnsureCapacityAvailable(vehicleType VehicleType, passengers int) error
+	EnsureDriverVehicleFits(driverID string, vehicleType *VehicleType, passengers int) error
+}
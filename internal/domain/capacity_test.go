@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestVehicleSplit(t *testing.T) {
+	fleet := []VehicleCapacity{
+		{VehicleID: "sedan", Type: VehicleTypeSedan, Capacity: 4},
+		{VehicleID: "bus", Type: VehicleTypeBus, Capacity: 20},
+		{VehicleID: "van", Type: VehicleTypeVan, Capacity: 12},
+		{VehicleID: "suv", Type: VehicleTypeSUV, Capacity: 7},
+	}
+
+	split := SuggestVehicleSplit(26, fleet)
+
+	assert.True(t, split.Covered)
+	assert.Equal(t, 0, split.Unassigned)
+	require.Len(t, split.Vehicles, 2)
+	assert.Equal(t, "bus", split.Vehicles[0].VehicleID)
+	assert.Equal(t, 20, split.Vehicles[0].Passengers)
+	assert.Equal(t, "suv", split.Vehicles[1].VehicleID)
+	assert.Equal(t, 6, split.Vehicles[1].Passengers)
+	assert.Equal(t, 27, split.TotalCapacity)
+}
+
+func TestSuggestVehicleSplitSingleVehicle(t *testing.T) {
+	fleet := []VehicleCapacity{
+		{VehicleID: "bus", Type: VehicleTypeBus, Capacity: 20},
+		{VehicleID: "van", Type: VehicleTypeVan, Capacity: 12},
+	}
+
+	split := SuggestVehicleSplit(10, fleet)
+
+	assert.True(t, split.Covered)
+	require.Len(t, split.Vehicles, 1)
+	assert.Equal(t, "van", split.Vehicles[0].VehicleID)
+	assert.Equal(t, 10, split.Vehicles[0].Passengers)
+}
+
+func TestSuggestVehicleSplitNotCovered(t *testing.T) {
+	fleet := []VehicleCapacity{
+		{VehicleID: "van-1", Type: VehicleTypeVan, Capacity: 12},
+		{VehicleID: "van-2", Type: VehicleTypeVan, Capacity: 12},
+		{VehicleID: "unknown", Type: VehicleTypeVan, Capacity: 0},
+	}
+
+	split := SuggestVehicleSplit(30, fleet)
+
+	assert.False(t, split.Covered)
+	assert.Equal(t, 6, split.Unassigned)
+	assert.Len(t, split.Vehicles, 2)
+	assert.Equal(t, 24, split.TotalCapacity)
+}
@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ComplianceReminderDays are the days-before-expiry at which reminders are sent.
+var ComplianceReminderDays = []int{30, 15, 7}
+
+type ComplianceIssueCode string
+
+const (
+	ComplianceIssueDriverInactive          ComplianceIssueCode = "DRIVER_INACTIVE"
+	ComplianceIssueLicenseMissing          ComplianceIssueCode = "LICENSE_MISSING"
+	ComplianceIssueLicenseExpired          ComplianceIssueCode = "LICENSE_EXPIRED"
+	ComplianceIssueLicenseExpiring         ComplianceIssueCode = "LICENSE_EXPIRING"
+	ComplianceIssueLicenseClassMismatch    ComplianceIssueCode = "LICENSE_CLASS_MISMATCH"
+	ComplianceIssueBackgroundCheckMissing  ComplianceIssueCode = "BACKGROUND_CHECK_MISSING"
+	ComplianceIssueBackgroundCheckPending  ComplianceIssueCode = "BACKGROUND_CHECK_PENDING"
+	ComplianceIssueBackgroundCheckRejected ComplianceIssueCode = "BACKGROUND_CHECK_REJECTED"
+	ComplianceIssueVehicleNotAssigned      ComplianceIssueCode = "VEHICLE_NOT_ASSIGNED"
+	ComplianceIssueVehicleOutOfService     ComplianceIssueCode = "VEHICLE_OUT_OF_SERVICE"
+	ComplianceIssueInsuranceMissing        ComplianceIssueCode = "INSURANCE_MISSING"
+	ComplianceIssueInsuranceExpired        ComplianceIssueCode = "INSURANCE_EXPIRED"
+	ComplianceIssueInsuranceExpiring       ComplianceIssueCode = "INSURANCE_EXPIRING"
+	ComplianceIssueInspectionMissing       ComplianceIssueCode = "INSPECTION_MISSING"
+	ComplianceIssueInspectionExpired       ComplianceIssueCode = "INSPECTION_EXPIRED"
+	ComplianceIssueInspectionExpiring      ComplianceIssueCode = "INSPECTION_EXPIRING"
+)
+
+// ComplianceIssue explains why a driver or vehicle is (or will soon be) not
+// eligible for trips. Blocking issues prevent assignment; the rest are warnings.
+type ComplianceIssue struct {
+	Code      ComplianceIssueCode `json:"code"`
+	Message   string              `json:"message"`
+	Blocking  bool                `json:"blocking"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+type VehicleCompliance struct {
+	VehicleID string            `json:"vehicle_id"`
+	Eligible  bool              `json:"eligible"`
+	Issues    []ComplianceIssue `json:"issues"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+type DriverCompliance struct {
+	DriverID  string             `json:"driver_id"`
+	Eligible  bool               `json:"eligible"`
+	Issues    []ComplianceIssue  `json:"issues"`
+	Vehicle   *VehicleCompliance `json:"vehicle,omitempty"`
+	CheckedAt time.Time          `json:"checked_at"`
+}
+
+// allowedLicenseClasses lists the license classes that may drive each vehicle
+// type: buses need a professional A3/A4, vans at least A2.
+var allowedLicenseClasses = map[VehicleType][]LicenseClass{
+	VehicleTypeBus:   {LicenseClassA3, LicenseClassA4},
+	VehicleTypeVan:   {LicenseClassA2, LicenseClassA3, LicenseClassA4},
+	VehicleTypeSedan: {LicenseClassA1, LicenseClassA2, LicenseClassA3, LicenseClassA4, LicenseClassB},
+	VehicleTypeSUV:   {LicenseClassA1, LicenseClassA2, LicenseClassA3, LicenseClassA4, LicenseClassB},
+}
+
+// LicenseClassAllows reports whether a license class may drive a vehicle type.
+func LicenseClassAllows(class LicenseClass, vehicleType VehicleType) bool {
+	for _, allowed := range allowedLicenseClasses[vehicleType] {
+		if allowed == class {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateVehicleCompliance checks the vehicle's service status, insurance and
+// technical inspection.
+func EvaluateVehicleCompliance(vehicle *Vehicle, now time.Time) *VehicleCompliance {
+	result := &VehicleCompliance{
+		VehicleID: vehicle.ID,
+		Issues:    []ComplianceIssue{},
+		CheckedAt: now,
+	}
+
+	if vehicle.Status == VehicleStatusMaintenance || vehicle.Status == VehicleStatusInactive {
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueVehicleOutOfService,
+			Message:  fmt.Sprintf("Vehicle is %s", strings.ToLower(string(vehicle.Status))),
+			Blocking: true,
+		})
+	}
+
+	result.Issues = append(result.Issues, expiryIssues(vehicle.InsuranceExpiresAt, now, "Insurance",
+		ComplianceIssueInsuranceMissing, ComplianceIssueInsuranceExpired, ComplianceIssueInsuranceExpiring)...)
+	result.Issues = append(result.Issues, expiryIssues(vehicle.InspectionExpiresAt, now, "Technical inspection",
+		ComplianceIssueInspectionMissing, ComplianceIssueInspectionExpired, ComplianceIssueInspectionExpiring)...)
+
+	result.Eligible = !hasBlockingIssue(result.Issues)
+	return result
+}
+
+// EvaluateDriverCompliance checks the driver's status, license and background
+// check and, when the driver has a vehicle, the vehicle and license class fit.
+func EvaluateDriverCompliance(driver *Driver, vehicle *Vehicle, now time.Time) *DriverCompliance {
+	result := &DriverCompliance{
+		DriverID:  driver.ID,
+		Issues:    []ComplianceIssue{},
+		CheckedAt: now,
+	}
+
+	if driver.Status != DriverStatusActive {
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueDriverInactive,
+			Message:  "Driver is inactive",
+			Blocking: true,
+		})
+	}
+
+	if driver.License == nil {
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueLicenseMissing,
+			Message:  "Driver license is not on file",
+			Blocking: true,
+		})
+	} else {
+		result.Issues = append(result.Issues, expiryIssues(driver.License.ExpiresAt, now, "Driver license",
+			ComplianceIssueLicenseMissing, ComplianceIssueLicenseExpired, ComplianceIssueLicenseExpiring)...)
+		if vehicle != nil && !LicenseClassAllows(driver.License.Class, vehicle.Type) {
+			result.Issues = append(result.Issues, ComplianceIssue{
+				Code:     ComplianceIssueLicenseClassMismatch,
+				Message:  fmt.Sprintf("License class %s cannot drive a %s", driver.License.Class, vehicle.Type),
+				Blocking: true,
+			})
+		}
+	}
+
+	switch {
+	case driver.BackgroundCheck == nil:
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueBackgroundCheckMissing,
+			Message:  "Background check is not on file",
+			Blocking: true,
+		})
+	case driver.BackgroundCheck.Status == BackgroundCheckStatusPending:
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueBackgroundCheckPending,
+			Message:  "Background check is pending review",
+			Blocking: true,
+		})
+	case driver.BackgroundCheck.Status == BackgroundCheckStatusRejected:
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:     ComplianceIssueBackgroundCheckRejected,
+			Message:  "Background check was rejected",
+			Blocking: true,
+		})
+	}
+
+	if vehicle == nil {
+		result.Issues = append(result.Issues, ComplianceIssue{
+			Code:    ComplianceIssueVehicleNotAssigned,
+			Message: "Driver has no vehicle assigned",
+		})
+	} else {
+		result.Vehicle = EvaluateVehicleCompliance(vehicle, now)
+	}
+
+	result.Eligible = !hasBlockingIssue(result.Issues) && (result.Vehicle == nil || result.Vehicle.Eligible)
+	return result
+}
+
+// BlockingIssues returns every blocking issue, including the vehicle's.
+func (c *DriverCompliance) BlockingIssues() []ComplianceIssue {
+	issues := blockingIssues(c.Issues)
+	if c.Vehicle != nil {
+		issues = append(issues, blockingIssues(c.Vehicle.Issues)...)
+	}
+	return issues
+}
+
+func (c *VehicleCompliance) BlockingIssues() []ComplianceIssue {
+	return blockingIssues(c.Issues)
+}
+
+// ComplianceError is returned when an assignment is blocked. It unwraps to
+// ErrNotCompliant and carries the reasons.
+type ComplianceError struct {
+	SubjectID string
+	Issues    []ComplianceIssue
+}
+
+func (e *ComplianceError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return fmt.Sprintf("%s is not compliant: %s", e.SubjectID, strings.Join(messages, "; "))
+}
+
+func (e *ComplianceError) Unwrap() error {
+	return ErrNotCompliant
+}
+
+// ComplianceChecker guards assignments on behalf of the dispatch, reservation
+// and fleet flows.
+type ComplianceChecker interface {
+	EnsureDriverEligible(driverID string) error
+	EnsureVehicleAssignable(vehicleID, driverID string) error
+}
+
+type ComplianceDocumentType string
+
+const (
+	ComplianceDocumentLicense    ComplianceDocumentType = "LICENSE"
+	ComplianceDocumentInsurance  ComplianceDocumentType = "INSURANCE"
+	ComplianceDocumentInspection ComplianceDocumentType = "INSPECTION"
+)
+
+// ExpiringDocument is a license, insurance or inspection that is about to
+// expire, with the contact details needed to remind its driver.
+type ExpiringDocument struct {
+	DocumentType ComplianceDocumentType `json:"document_type"`
+	SubjectID    string                 `json:"subject_id"` // Driver ID for licenses, vehicle ID otherwise
+	ExpiresAt    time.Time              `json:"expires_at"`
+	DriverID     *string                `json:"driver_id,omitempty"`
+	DriverName   *string                `json:"driver_name,omitempty"`
+	DriverEmail  *string                `json:"driver_email,omitempty"`
+	VehiclePlate *string                `json:"vehicle_plate,omitempty"`
+}
+
+// DaysLeft returns the number of calendar days until the document expires,
+// rounded up.
+func (d *ExpiringDocument) DaysLeft(now time.Time) int {
+	return int(math.Ceil(d.ExpiresAt.Sub(now).Hours() / 24))
+}
+
+// ReminderThreshold returns the smallest reminder threshold (in days) the
+// document has reached, or 0 if it is not due for a reminder yet.
+func (d *ExpiringDocument) ReminderThreshold(now time.Time) int {
+	daysLeft := d.DaysLeft(now)
+	threshold := 0
+	for _, days := range ComplianceReminderDays {
+		if daysLeft <= days && (threshold == 0 || days < threshold) {
+			threshold = days
+		}
+	}
+	return threshold
+}
+
+type ComplianceRepository interface {
+	ListExpiringDocuments(from, to time.Time) ([]*ExpiringDocument, error)
+	HasReminder(doc *ExpiringDocument, thresholdDays int) (bool, error)
+	// RecordReminder returns ErrAlreadyExists if this reminder was already sent.
+	RecordReminder(doc *ExpiringDocument, thresholdDays int, sentAt time.Time) error
+}
+
+func expiryIssues(expiresAt *time.Time, now time.Time, label string, missing, expired, expiring ComplianceIssueCode) []ComplianceIssue {
+	if expiresAt == nil {
+		return []ComplianceIssue{{
+			Code:     missing,
+			Message:  fmt.Sprintf("%s expiry date is not on file", label),
+			Blocking: true,
+		}}
+	}
+
+	if !now.Before(*expiresAt) {
+		return []ComplianceIssue{{
+			Code:      expired,
+			Message:   fmt.Sprintf("%s expired on %s", label, expiresAt.Format("2006-01-02")),
+			Blocking:  true,
+			ExpiresAt: expiresAt,
+		}}
+	}
+
+	warnFrom := expiresAt.AddDate(0, 0, -ComplianceReminderDays[0])
+	if !now.Before(warnFrom) {
+		return []ComplianceIssue{{
+			Code:      expiring,
+			Message:   fmt.Sprintf("%s expires on %s", label, expiresAt.Format("2006-01-02")),
+			ExpiresAt: expiresAt,
+		}}
+	}
+
+	return nil
+}
+
+func hasBlockingIssue(issues []ComplianceIssue) bool {
+	return len(blockingIssues(issues)) > 0
+}
+
+func blockingIssues(issues []ComplianceIssue) []ComplianceIssue {
+	blocking := []ComplianceIssue{}
+	for _, issue := range issues {
+		if issue.Blocking {
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicenseClassAllows(t *testing.T) {
+	tests := []struct {
+		vehicleType VehicleType
+		allowed     []LicenseClass
+	}{
+		{vehicleType: VehicleTypeBus, allowed: []LicenseClass{LicenseClassA3, LicenseClassA4}},
+		{vehicleType: VehicleTypeVan, allowed: []LicenseClass{LicenseClassA2, LicenseClassA3, LicenseClassA4}},
+		{vehicleType: VehicleTypeSedan, allowed: []LicenseClass{LicenseClassA1, LicenseClassA2, LicenseClassA3, LicenseClassA4, LicenseClassB}},
+		{vehicleType: VehicleTypeSUV, allowed: []LicenseClass{LicenseClassA1, LicenseClassA2, LicenseClassA3, LicenseClassA4, LicenseClassB}},
+		{vehicleType: "TRUCK"},
+	}
+
+	classes := []LicenseClass{
+		LicenseClassA1, LicenseClassA2, LicenseClassA3, LicenseClassA4, LicenseClassA5,
+		LicenseClassB, LicenseClassC, LicenseClassD, LicenseClassE,
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.vehicleType), func(t *testing.T) {
+			for _, class := range classes {
+				expected := false
+				for _, allowed := range tt.allowed {
+					if allowed == class {
+						expected = true
+					}
+				}
+				assert.Equal(t, expected, LicenseClassAllows(class, tt.vehicleType), "class %s", class)
+			}
+		})
+	}
+}
+
+func TestEvaluateDriverCompliance(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	valid := now.AddDate(1, 0, 0)
+	expiring := now.AddDate(0, 0, 10)
+	expired := now.AddDate(0, 0, -1)
+
+	compliantDriver := func() *Driver {
+		return &Driver{
+			ID:              "DRV-001",
+			Status:          DriverStatusActive,
+			License:         &DriverLicense{Class: LicenseClassA3, ExpiresAt: &valid},
+			BackgroundCheck: &DriverBackgroundCheck{Status: BackgroundCheckStatusApproved},
+		}
+	}
+	compliantVehicle := func() *Vehicle {
+		return &Vehicle{
+			ID:                  "VEH-001",
+			Type:                VehicleTypeBus,
+			Status:              VehicleStatusAssigned,
+			InsuranceExpiresAt:  &valid,
+			InspectionExpiresAt: &valid,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		driver   func() *Driver
+		vehicle  func() *Vehicle
+		eligible bool
+		codes    []ComplianceIssueCode
+	}{
+		{
+			name:     "compliant",
+			driver:   compliantDriver,
+			vehicle:  compliantVehicle,
+			eligible: true,
+		},
+		{
+			name:     "without a vehicle",
+			driver:   compliantDriver,
+			vehicle:  func() *Vehicle { return nil },
+			eligible: true,
+			codes:    []ComplianceIssueCode{ComplianceIssueVehicleNotAssigned},
+		},
+		{
+			name: "inactive",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.Status = DriverStatusInactive
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueDriverInactive},
+		},
+		{
+			name: "license missing",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.License = nil
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueLicenseMissing},
+		},
+		{
+			name: "license without expiry date",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.License.ExpiresAt = nil
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueLicenseMissing},
+		},
+		{
+			name: "license expired",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.License.ExpiresAt = &expired
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueLicenseExpired},
+		},
+		{
+			name: "license expiring is a warning",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.License.ExpiresAt = &expiring
+				return d
+			},
+			vehicle:  compliantVehicle,
+			eligible: true,
+			codes:    []ComplianceIssueCode{ComplianceIssueLicenseExpiring},
+		},
+		{
+			name: "license class cannot drive the vehicle",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.License.Class = LicenseClassB
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueLicenseClassMismatch},
+		},
+		{
+			name: "background check missing",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.BackgroundCheck = nil
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueBackgroundCheckMissing},
+		},
+		{
+			name: "background check pending",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.BackgroundCheck.Status = BackgroundCheckStatusPending
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueBackgroundCheckPending},
+		},
+		{
+			name: "background check rejected",
+			driver: func() *Driver {
+				d := compliantDriver()
+				d.BackgroundCheck.Status = BackgroundCheckStatusRejected
+				return d
+			},
+			vehicle: compliantVehicle,
+			codes:   []ComplianceIssueCode{ComplianceIssueBackgroundCheckRejected},
+		},
+		{
+			name:   "vehicle in maintenance",
+			driver: compliantDriver,
+			vehicle: func() *Vehicle {
+				v := compliantVehicle()
+				v.Status = VehicleStatusMaintenance
+				return v
+			},
+			codes: []ComplianceIssueCode{ComplianceIssueVehicleOutOfService},
+		},
+		{
+			name:   "vehicle insurance expired",
+			driver: compliantDriver,
+			vehicle: func() *Vehicle {
+				v := compliantVehicle()
+				v.InsuranceExpiresAt = &expired
+				return v
+			},
+			codes: []ComplianceIssueCode{ComplianceIssueInsuranceExpired},
+		},
+		{
+			name:   "vehicle inspection expiring is a warning",
+			driver: compliantDriver,
+			vehicle: func() *Vehicle {
+				v := compliantVehicle()
+				v.InspectionExpiresAt = &expiring
+				return v
+			},
+			eligible: true,
+			codes:    []ComplianceIssueCode{ComplianceIssueInspectionExpiring},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateDriverCompliance(tt.driver(), tt.vehicle(), now)
+
+			var codes []ComplianceIssueCode
+			for _, issue := range result.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if result.Vehicle != nil {
+				for _, issue := range result.Vehicle.Issues {
+					codes = append(codes, issue.Code)
+				}
+			}
+
+			assert.Equal(t, tt.eligible, result.Eligible)
+			assert.ElementsMatch(t, tt.codes, codes)
+			assert.Equal(t, !tt.eligible, len(result.BlockingIssues()) > 0)
+		})
+	}
+}
+
+func TestExpiringDocumentReminderThreshold(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		daysLeft  int
+		threshold int
+	}{
+		{name: "not due yet", expiresAt: now.AddDate(0, 0, 31), daysLeft: 31, threshold: 0},
+		{name: "exactly 30 days", expiresAt: now.AddDate(0, 0, 30), daysLeft: 30, threshold: 30},
+		{name: "a few hours past 29 days rounds up", expiresAt: now.AddDate(0, 0, 29).Add(time.Hour), daysLeft: 30, threshold: 30},
+		{name: "between 30 and 15 days", expiresAt: now.AddDate(0, 0, 16), daysLeft: 16, threshold: 30},
+		{name: "exactly 15 days", expiresAt: now.AddDate(0, 0, 15), daysLeft: 15, threshold: 15},
+		{name: "exactly 7 days", expiresAt: now.AddDate(0, 0, 7), daysLeft: 7, threshold: 7},
+		{name: "expires tomorrow", expiresAt: now.AddDate(0, 0, 1), daysLeft: 1, threshold: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &ExpiringDocument{ExpiresAt: tt.expiresAt}
+
+			assert.Equal(t, tt.daysLeft, doc.DaysLeft(now))
+			assert.Equal(t, tt.threshold, doc.ReminderThreshold(now))
+		})
+	}
+}
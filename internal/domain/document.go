@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DocumentOwnerType string
+
+const (
+	DocumentOwnerDriver  DocumentOwnerType = "DRIVER"
+	DocumentOwnerVehicle DocumentOwnerType = "VEHICLE"
+	// DocumentOwnerMaintenance owns invoices and reports attached to a
+	// maintenance record; OwnerID is the record ID.
+	DocumentOwnerMaintenance DocumentOwnerType = "MAINTENANCE"
+	// DocumentOwnerIncident owns photos of an incident; OwnerID is the
+	// incident ID.
+	DocumentOwnerIncident DocumentOwnerType = "INCIDENT"
+)
+
+type DocumentKind string
+
+const (
+	DocumentKindDriverPhoto     DocumentKind = "DRIVER_PHOTO"
+	DocumentKindLicense         DocumentKind = "LICENSE"
+	DocumentKindBackgroundCheck DocumentKind = "BACKGROUND_CHECK"
+	DocumentKindVehiclePhoto    DocumentKind = "VEHICLE_PHOTO"
+	DocumentKindMaintenance     DocumentKind = "MAINTENANCE_ATTACHMENT"
+	DocumentKindIncidentPhoto   DocumentKind = "INCIDENT_PHOTO"
+)
+
+type DocumentStatus string
+
+const (
+	DocumentStatusPending  DocumentStatus = "PENDING"
+	DocumentStatusApproved DocumentStatus = "APPROVED"
+	DocumentStatusRejected DocumentStatus = "REJECTED"
+)
+
+var (
+	imageContentTypes    = []string{"image/jpeg", "image/png", "image/webp"}
+	documentContentTypes = []string{"image/jpeg", "image/png", "application/pdf"}
+)
+
+// AllowedContentTypes lists the sniffed content types accepted for each kind.
+// Photos must be images; scanned documents may also be PDFs.
+func (k DocumentKind) AllowedContentTypes() []string {
+	switch k {
+	case DocumentKindDriverPhoto, DocumentKindVehiclePhoto, DocumentKindIncidentPhoto:
+		return imageContentTypes
+	case DocumentKindLicense, DocumentKindBackgroundCheck, DocumentKindMaintenance:
+		return documentContentTypes
+	default:
+		return nil
+	}
+}
+
+// OwnerType returns whether documents of this kind belong to a driver, a
+// vehicle, a maintenance record or an incident.
+func (k DocumentKind) OwnerType() DocumentOwnerType {
+	switch k {
+	case DocumentKindVehiclePhoto:
+		return DocumentOwnerVehicle
+	case DocumentKindMaintenance:
+		return DocumentOwnerMaintenance
+	case DocumentKindIncidentPhoto:
+		return DocumentOwnerIncident
+	default:
+		return DocumentOwnerDriver
+	}
+}
+
+// RequiresReview reports whether uploads of this kind wait for an admin.
+// Maintenance attachments and incident photos are evidence and kept as filed.
+func (k DocumentKind) RequiresReview() bool {
+	return k != DocumentKindMaintenance && k != DocumentKindIncidentPhoto
+}
+
+// IsPhoto reports whether the document is a picture that may be shown without
+// authentication once approved.
+func (k DocumentKind) IsPhoto() bool {
+	return k == DocumentKindDriverPhoto || k == DocumentKindVehiclePhoto
+}
+
+// Document is the metadata of an uploaded file. The file itself lives in
+// FileStorage under StorageKey; admins approve or reject each upload.
+type Document struct {
+	ID          uuid.UUID         `json:"id"`
+	OwnerType   DocumentOwnerType `json:"owner_type"`
+	OwnerID     string            `json:"owner_id"`
+	Kind        DocumentKind      `json:"kind"`
+	StorageKey  string            `json:"-"`
+	FileName    string            `json:"file_name"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Status      DocumentStatus    `json:"status"`
+	ReviewNote  *string           `json:"review_note,omitempty"`
+	ReviewedBy  *uuid.UUID        `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"`
+	UploadedBy  *uuid.UUID        `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ReviewDocumentRequest struct {
+	Status DocumentStatus `json:"status" validate:"required,oneof=APPROVED REJECTED"`
+	Note   *string        `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+type ListDocumentsRequest struct {
+	OwnerType *DocumentOwnerType `json:"owner_type,omitempty"`
+	OwnerID   *string            `json:"owner_id,omitempty"`
+	Kind      *DocumentKind      `json:"kind,omitempty"`
+	Status    *DocumentStatus    `json:"status,omitempty"`
+	Page      int                `json:"page"`
+	PageSize  int                `json:"page_size"`
+}
+
+type DocumentRepository interface {
+	Create(document *Document) error
+	GetByID(id uuid.UUID) (*Document, error)
+	List(req ListDocumentsRequest) ([]*Document, int, error)
+	Review(id uuid.UUID, status DocumentStatus, note *string, reviewedBy uuid.UUID, reviewedAt time.Time) (*Document, error)
+}
+
+// FileStorage stores uploaded files under opaque keys and hands out
+// time-limited download URLs for them.
+type FileStorage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
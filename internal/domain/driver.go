@@ -3,6 +3,8 @@ package domain
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type DriverStatus string
@@ -109,13 +111,19 @@ type DriverKPIs struct {
 }
 
 type DriverFeedback struct {
-	ID            string    `json:"id"`
-	DriverID      string    `json:"driver_id"`
-	ReservationID string    `json:"reservation_id"`
-	Rating        float64   `json:"rating"`
-	Comment       *string   `json:"comment,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             string         `json:"id"`
+	DriverID       string         `json:"driver_id"`
+	ReservationID  string         `json:"reservation_id"`
+	UserID         *uuid.UUID     `json:"user_id,omitempty"`
+	Rating         float64        `json:"rating"`
+	Tags           []FeedbackTag  `json:"tags"`
+	Comment        *string        `json:"comment,omitempty"`
+	Status         FeedbackStatus `json:"status"`
+	ModerationNote *string        `json:"moderation_note,omitempty"`
+	ModeratedBy    *uuid.UUID     `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time     `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
 type CreateDriverRequest struct {
@@ -203,13 +211,8 @@ type DriverRepository interface {
 	// KPIs (calculated from other tables)
 	GetKPIs(driverID string) (*DriverKPIs, error)
 
-	// Feedback operations
-	CreateFeedback(feedback *DriverFeedback) error
-	GetDriverFeedback(driverID string) ([]*DriverFeedback, error)
-
 	// New methods for driver dashboard
 	GetByUserID(userID string) (*Driver, error)
 	GetDriverTrips(driverID string) ([]*Reservation, error)
 	GetDriverVehicle(driverID string) (*Vehicle, error)
-	UpdateTripStatus(driverID, tripID, status string) error
 }
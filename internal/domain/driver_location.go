@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// MaxLocationBatchSize caps how many pings a driver app may upload at once.
+const MaxLocationBatchSize = 100
+
+// earthRadiusKM is the mean Earth radius used for great-circle distances.
+const earthRadiusKM = 6371.0
+
+type DriverLocation struct {
+	ID            int64     `json:"id"`
+	DriverID      string    `json:"driver_id"`
+	ReservationID *string   `json:"reservation_id,omitempty"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	Accuracy      *float64  `json:"accuracy,omitempty"` // meters
+	Speed         *float64  `json:"speed,omitempty"`    // meters per second
+	Heading       *float64  `json:"heading,omitempty"`  // degrees from north
+	RecordedAt    time.Time `json:"recorded_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LocationPing struct {
+	Latitude   float64   `json:"latitude" validate:"min=-90,max=90"`
+	Longitude  float64   `json:"longitude" validate:"min=-180,max=180"`
+	Accuracy   *float64  `json:"accuracy,omitempty" validate:"omitempty,min=0"`
+	Speed      *float64  `json:"speed,omitempty" validate:"omitempty,min=0"`
+	Heading    *float64  `json:"heading,omitempty" validate:"omitempty,min=0,max=360"`
+	RecordedAt time.Time `json:"recorded_at" validate:"required"`
+}
+
+// PathDistanceKM returns the length of the path through locations, which
+// must be ordered by recording time.
+func PathDistanceKM(locations []*DriverLocation) float64 {
+	total := 0.0
+	for i := 1; i < len(locations); i++ {
+		total += greatCircleKM(locations[i-1], locations[i])
+	}
+	return total
+}
+
+func greatCircleKM(from, to *DriverLocation) float64 {
+	lat1, lat2 := from.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(h))
+}
+
+type RecordLocationsRequest struct {
+	Pings []LocationPing `json:"pings" validate:"required,min=1,max=100,dive"`
+}
+
+type RecordLocationsResponse struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+}
+
+type DriverLocationRepository interface {
+	CreateBatch(locations []*DriverLocation) error
+	GetLatestByReservation(reservationID string) (*DriverLocation, error)
+	ListByReservationSince(reservationID string, since time.Time) ([]*DriverLocation, error)
+	DeleteOlderThan(cutoff time.Time, limit int) (int64, error)
+}
+
+// LocationBroker fans out freshly recorded locations to live subscribers of a
+// reservation.
+type LocationBroker interface {
+	Publish(location *DriverLocation)
+	Subscribe(reservationID string) (<-chan *DriverLocation, func())
+}
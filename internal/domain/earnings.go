@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EarningEntryType classifies a line of the driver earnings ledger.
+type EarningEntryType string
+
+const (
+	EarningTypeTripPayout               EarningEntryType = "TRIP_PAYOUT"
+	EarningTypeAdjustment               EarningEntryType = "ADJUSTMENT"
+	EarningTypeTip                      EarningEntryType = "TIP"
+	EarningTypeCancellationCompensation EarningEntryType = "CANCELLATION_COMPENSATION"
+	EarningTypePenalty                  EarningEntryType = "PENALTY"
+)
+
+// EarningEntry is one immutable line of a driver's ledger. Amount is signed:
+// penalties are stored as negative amounts, adjustments may go either way.
+// Mistakes are corrected with a compensating adjustment, never by editing.
+type EarningEntry struct {
+	ID            uuid.UUID        `json:"id"`
+	DriverID      string           `json:"driver_id"`
+	ReservationID *string          `json:"reservation_id,omitempty"`
+	Type          EarningEntryType `json:"type"`
+	Amount        float64          `json:"amount"`
+	Currency      string           `json:"currency"`
+	GrossFare     *float64         `json:"gross_fare,omitempty"`
+	Commission    *float64         `json:"commission,omitempty"`
+	Description   *string          `json:"description,omitempty"`
+	SettlementID  *uuid.UUID       `json:"settlement_id,omitempty"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+	CreatedBy     *uuid.UUID       `json:"created_by,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// TripPayoutCandidate is a completed reservation whose payout has not been
+// recorded in the ledger yet. Fare is the price locked on the reservation and
+// Commission, DriverPayout and Currency its split taken at that time; they
+// are nil for reservations booked before the split was stored.
+type TripPayoutCandidate struct {
+	ReservationID string
+	DriverID      string
+	Fare          float64
+	Commission    *float64
+	DriverPayout  *float64
+	Currency      *string
+	CompletedAt   time.Time
+}
+
+type SettlementPeriod string
+
+const (
+	SettlementPeriodWeekly   SettlementPeriod = "WEEKLY"
+	SettlementPeriodBiweekly SettlementPeriod = "BIWEEKLY"
+)
+
+// Bounds returns the start (inclusive) and end (exclusive) of the period
+// containing t. Weeks start on Monday; biweekly periods are counted from
+// Monday 2024-01-01 so every driver shares the same fortnights.
+func (p SettlementPeriod) Bounds(t time.Time) (time.Time, time.Time) {
+	start := WeekStart(t)
+
+	if p == SettlementPeriodBiweekly {
+		anchor := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		weeks := int(day.Sub(anchor).Hours()/24) / 7
+		if weeks%2 != 0 {
+			start = start.AddDate(0, 0, -7)
+		}
+		return start, start.AddDate(0, 0, 14)
+	}
+
+	return start, start.AddDate(0, 0, 7)
+}
+
+// Previous returns the bounds of the last period that ended before t.
+func (p SettlementPeriod) Previous(t time.Time) (time.Time, time.Time) {
+	start, _ := p.Bounds(t)
+	return p.Bounds(start.AddDate(0, 0, -1))
+}
+
+type SettlementStatus string
+
+const (
+	SettlementStatusDraft    SettlementStatus = "DRAFT"
+	SettlementStatusApproved SettlementStatus = "APPROVED"
+	SettlementStatusPaid     SettlementStatus = "PAID"
+)
+
+// SettlementTotals breaks a settlement down by entry type.
+type SettlementTotals struct {
+	TripCount     int     `json:"trip_count"`
+	TripPayouts   float64 `json:"trip_payouts"`
+	Tips          float64 `json:"tips"`
+	Compensations float64 `json:"compensations"`
+	Adjustments   float64 `json:"adjustments"`
+	Penalties     float64 `json:"penalties"`
+	Total         float64 `json:"total"`
+}
+
+// SummarizeEarnings adds up ledger entries by type.
+func SummarizeEarnings(entries []*EarningEntry) SettlementTotals {
+	var totals SettlementTotals
+	for _, entry := range entries {
+		switch entry.Type {
+		case EarningTypeTripPayout:
+			totals.TripCount++
+			totals.TripPayouts += entry.Amount
+		case EarningTypeTip:
+			totals.Tips += entry.Amount
+		case EarningTypeCancellationCompensation:
+			totals.Compensations += entry.Amount
+		case EarningTypeAdjustment:
+			totals.Adjustments += entry.Amount
+		case EarningTypePenalty:
+			totals.Penalties += entry.Amount
+		}
+		totals.Total += entry.Amount
+	}
+
+	// Amounts are stored with two decimals; avoid float noise in the sums
+	for _, amount := range []*float64{&totals.TripPayouts, &totals.Tips, &totals.Compensations, &totals.Adjustments, &totals.Penalties, &totals.Total} {
+		*amount = math.Round(*amount*100) / 100
+	}
+	return totals
+}
+
+// Settlement is a periodic statement grouping the unsettled ledger entries of
+// one driver up to the end of the period. It moves DRAFT -> APPROVED -> PAID.
+type Settlement struct {
+	ID          uuid.UUID        `json:"id"`
+	DriverID    string           `json:"driver_id"`
+	Period      SettlementPeriod `json:"period"`
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Status      SettlementStatus `json:"status"`
+	Currency    string           `json:"currency"`
+	SettlementTotals
+	PaymentReference *string         `json:"payment_reference,omitempty"`
+	ApprovedBy       *uuid.UUID      `json:"approved_by,omitempty"`
+	ApprovedAt       *time.Time      `json:"approved_at,omitempty"`
+	PaidBy           *uuid.UUID      `json:"paid_by,omitempty"`
+	PaidAt           *time.Time      `json:"paid_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	Entries          []*EarningEntry `json:"entries,omitempty"`
+}
+
+// DriverBalance is what the driver dashboard shows: money not yet settled,
+// money in draft or approved statements, and money already paid out.
+type DriverBalance struct {
+	DriverID         string     `json:"driver_id"`
+	Currency         string     `json:"currency"`
+	Unsettled        float64    `json:"unsettled"`
+	PendingPayout    float64    `json:"pending_payout"`
+	Paid             float64    `json:"paid"`
+	LifetimeEarnings float64    `json:"lifetime_earnings"`
+	LastPaidAt       *time.Time `json:"last_paid_at,omitempty"`
+}
+
+// CreateEarningEntryRequest records a manual ledger line. Amount is positive
+// for tips, compensations and penalties (a penalty is deducted); adjustments
+// may be negative.
+type CreateEarningEntryRequest struct {
+	Type          EarningEntryType `json:"type" validate:"required,oneof=ADJUSTMENT TIP CANCELLATION_COMPENSATION PENALTY"`
+	Amount        float64          `json:"amount" validate:"required"`
+	ReservationID *string          `json:"reservation_id,omitempty" validate:"omitempty,max=20"`
+	Description   string           `json:"description" validate:"required,max=500"`
+	OccurredAt    *time.Time       `json:"occurred_at,omitempty"`
+}
+
+// GenerateSettlementsRequest drafts statements for the period containing
+// PeriodOf, or the last complete period when it is omitted.
+type GenerateSettlementsRequest struct {
+	Period   SettlementPeriod `json:"period" validate:"required,oneof=WEEKLY BIWEEKLY"`
+	PeriodOf *time.Time       `json:"period_of,omitempty"`
+	DriverID *string          `json:"driver_id,omitempty"`
+}
+
+type MarkSettlementPaidRequest struct {
+	Reference *string `json:"reference,omitempty" validate:"omitempty,max=100"`
+}
+
+type ListEarningsRequest struct {
+	DriverID *string           `json:"driver_id,omitempty"`
+	Type     *EarningEntryType `json:"type,omitempty"`
+	From     *time.Time        `json:"from,omitempty"`
+	To       *time.Time        `json:"to,omitempty"`
+	Page     int               `json:"page" validate:"min=1"`
+	PageSize int               `json:"page_size" validate:"min=1,max=100"`
+}
+
+type ListSettlementsRequest struct {
+	DriverID *string           `json:"driver_id,omitempty"`
+	Status   *SettlementStatus `json:"status,omitempty"`
+	Page     int               `json:"page" validate:"min=1"`
+	PageSize int               `json:"page_size" validate:"min=1,max=100"`
+}
+
+type EarningsRepository interface {
+	// Ledger
+	ListTripPayoutCandidates(limit int) ([]*TripPayoutCandidate, error)
+	CreateEntry(entry *EarningEntry) error
+	ListEntries(req ListEarningsRequest) ([]*EarningEntry, int, error)
+	ListUnsettledEntries(driverID string, before time.Time) ([]*EarningEntry, error)
+	ListDriversWithUnsettledEntries(before time.Time) ([]string, error)
+	GetBalance(driverID string) (*DriverBalance, error)
+
+	// Settlements
+	CreateSettlement(settlement *Settlement, entryIDs []uuid.UUID) error
+	GetSettlement(id uuid.UUID) (*Settlement, error)
+	ListSettlementEntries(settlementID uuid.UUID) ([]*EarningEntry, error)
+	ListSettlements(req ListSettlementsRequest) ([]*Settlement, int, error)
+	ApproveSettlement(id uuid.UUID, approvedBy uuid.UUID, approvedAt time.Time) (*Settlement, error)
+	MarkSettlementPaid(id uuid.UUID, paidBy uuid.UUID, paidAt time.Time, reference *string) (*Settlement, error)
+	DeleteDraftSettlement(id uuid.UUID) error
+}
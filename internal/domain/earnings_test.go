@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementPeriodBounds(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		period SettlementPeriod
+		at     time.Time
+		start  time.Time
+		end    time.Time
+	}{
+		{"weekly mid-week", SettlementPeriodWeekly, time.Date(2026, 10, 15, 18, 30, 0, 0, time.UTC), day(2026, 10, 12), day(2026, 10, 19)},
+		{"weekly on sunday", SettlementPeriodWeekly, day(2026, 10, 18), day(2026, 10, 12), day(2026, 10, 19)},
+		{"biweekly anchor", SettlementPeriodBiweekly, day(2024, 1, 3), day(2024, 1, 1), day(2024, 1, 15)},
+		{"biweekly second week", SettlementPeriodBiweekly, day(2024, 1, 10), day(2024, 1, 1), day(2024, 1, 15)},
+		{"biweekly next period", SettlementPeriodBiweekly, day(2024, 1, 15), day(2024, 1, 15), day(2024, 1, 29)},
+		{"biweekly before anchor", SettlementPeriodBiweekly, day(2023, 12, 27), day(2023, 12, 18), day(2024, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.period.Bounds(tt.at)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestSettlementPeriodPrevious(t *testing.T) {
+	start, end := SettlementPeriodBiweekly.Previous(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestSummarizeEarnings(t *testing.T) {
+	totals := SummarizeEarnings([]*EarningEntry{
+		{Type: EarningTypeTripPayout, Amount: 20000.10},
+		{Type: EarningTypeTripPayout, Amount: 15000.20},
+		{Type: EarningTypeTip, Amount: 2000},
+		{Type: EarningTypeCancellationCompensation, Amount: 5000},
+		{Type: EarningTypeAdjustment, Amount: -500},
+		{Type: EarningTypePenalty, Amount: -3000},
+	})
+
+	assert.Equal(t, 2, totals.TripCount)
+	assert.Equal(t, 35000.30, totals.TripPayouts)
+	assert.Equal(t, 2000.0, totals.Tips)
+	assert.Equal(t, 5000.0, totals.Compensations)
+	assert.Equal(t, -500.0, totals.Adjustments)
+	assert.Equal(t, -3000.0, totals.Penalties)
+	assert.Equal(t, 38500.30, totals.Total)
+}
@@ -4,10 +4,14 @@ import "github.com/google/uuid"
 
 type EmailService interface {
 	SendWelcomeEmail(email, name string, registrationToken string) error
-	SendReservationCreated(to string, reservation *Reservation, user *User) error
+	SendReservationCreated(to string, reservation *Reservation, user *User, trackingToken string) error
 	SendReservationNotification(to string, reservation *Reservation, user *User) error
 	SendSupportRequest(to string, request *SupportRequest, user *User) error
 	SendPasswordResetEmail(email, name, resetLink string) error
+	SendFeedbackRequest(to string, reservation *Reservation, user *User, feedbackToken string) error
+	SendComplianceReminder(to, name string, items []ComplianceReminderItem) error
+	SendMaintenanceReminder(to, name string, items []MaintenanceReminderItem) error
+	SendLowBalanceAlert(to, companyName string, wallet *CompanyWallet) error
 }
 
 type WelcomeEmailData struct {
@@ -31,6 +35,54 @@ type ReservationEmailData struct {
 	Notes          string
 	Stops          int
 	HasSpecialLang bool
+	TrackingURL    string
+}
+
+type FeedbackEmailData struct {
+	UserName      string
+	ReservationID string
+	Pickup        string
+	Destination   string
+	DateTime      string
+	DriverName    string
+	FeedbackURL   string
+}
+
+// ComplianceReminderItem is one expiring document listed in a reminder email.
+type ComplianceReminderItem struct {
+	Document  string
+	Subject   string
+	ExpiresAt string
+	DaysLeft  int
+}
+
+type ComplianceReminderEmailData struct {
+	Name  string
+	Items []ComplianceReminderItem
+}
+
+// MaintenanceReminderItem is one maintenance plan listed in a reminder email.
+type MaintenanceReminderItem struct {
+	Vehicle string
+	Service string
+	DueAt   string
+	DueKm   string
+	Overdue bool
+}
+
+type MaintenanceReminderEmailData struct {
+	Name  string
+	Items []MaintenanceReminderItem
+}
+
+// LowBalanceAlertEmailData holds the wallet amounts, formatted with their
+// currency.
+type LowBalanceAlertEmailData struct {
+	CompanyName string
+	Balance     string
+	CreditLimit string
+	Available   string
+	Threshold   string
 }
 
 type SupportEmailData struct {
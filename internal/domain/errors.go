@@ -31,6 +31,8 @@ var (
 	ErrDriverAlreadyExists = errors.New("driver already exists")
 	ErrDriverNotAvailable  = errors.New("driver not available")
 	ErrDriverInactive      = errors.New("driver is inactive")
+	ErrNotCompliant        = errors.New("driver or vehicle is not compliant")
+	ErrInvalidTimeRange    = errors.New("invalid availability time range")
 
 	// Request specific errors
 	ErrRequestNotFound         = errors.New("request not found")
@@ -41,14 +43,84 @@ var (
 	ErrReservationNotFound = errors.New("reservation not found")
 	ErrReservationPastDate = errors.New("reservation date is in the past")
 
+	// Dispatch specific errors
+	ErrOfferNotFound      = errors.New("trip offer not found")
+	ErrOfferNotPending    = errors.New("trip offer is no longer pending")
+	ErrDispatchInProgress = errors.New("reservation already has open offers")
+
 	// Payment specific errors
 	ErrPaymentNotFound    = errors.New("payment not found")
 	ErrPaymentFailed      = errors.New("payment failed")
 	ErrPaymentAlreadyPaid = errors.New("payment already processed")
 
+	ErrPaymentOperationUnsupported = errors.New("operation not supported by the payment gateway")
+	ErrPaymentNotRefundable        = errors.New("payment cannot be refunded")
+	ErrRefundExceedsPayment        = errors.New("refund exceeds the refundable amount")
+	ErrPaymentMethodUnavailable    = errors.New("payment method is not available for this reservation")
+	ErrExportTooLarge              = errors.New("too many rows to export; narrow the filters")
+
+	// Invoice specific errors
+	ErrBillingAccountNotFound = errors.New("billing account not found")
+	ErrInvoiceNotFound        = errors.New("invoice not found")
+	ErrInvoiceNotPayable      = errors.New("invoice is already paid or void")
+	ErrNothingToInvoice       = errors.New("billing period has no trips to invoice")
+	ErrBillingAccountCurrency = errors.New("billing accounts can only be held in the pricing currency")
+
+	// Tax document specific errors
+	ErrTaxDocumentNotFound = errors.New("tax document not found")
+	ErrTaxDocumentCurrency = errors.New("tax documents can only be issued in CLP")
+	ErrFoliosExhausted     = errors.New("no folios left for the document type")
+	ErrInvalidCAF          = errors.New("invalid folio authorization file")
+	ErrInvalidRUT          = errors.New("invalid RUT")
+
+	// Wallet specific errors
+	ErrWalletNotFound     = errors.New("company wallet not found")
+	ErrWalletCurrency     = errors.New("wallets can only be held in the pricing currency")
+	ErrInsufficientCredit = errors.New("company wallet balance and credit line do not cover the amount")
+
 	// Feedback specific errors
 	ErrFeedbackNotFound      = errors.New("feedback not found")
 	ErrFeedbackAlreadyExists = errors.New("feedback already exists for this trip")
+	ErrFeedbackNotAvailable  = errors.New("feedback is not available for this trip")
+
+	// Document specific errors
+	ErrDocumentNotFound        = errors.New("document not found")
+	ErrDocumentTooLarge        = errors.New("document exceeds the maximum upload size")
+	ErrUnsupportedDocumentType = errors.New("document content type is not allowed")
+	ErrDocumentAlreadyReviewed = errors.New("document has already been reviewed")
+	ErrInvalidSignature        = errors.New("invalid or expired signature")
+
+	// Earnings specific errors
+	ErrSettlementNotFound = errors.New("settlement not found")
+
+	// Shift specific errors
+	ErrShiftNotFound               = errors.New("shift not found")
+	ErrShiftConflict               = errors.New("shift overlaps an existing shift or approved time off")
+	ErrShiftRequestNotFound        = errors.New("shift request not found")
+	ErrShiftRequestAlreadyReviewed = errors.New("shift request has already been reviewed")
+	ErrOutsideShift                = errors.New("reservation is outside the driver's scheduled shift")
+
+	// Maintenance specific errors
+	ErrMaintenanceRecordNotFound = errors.New("maintenance record not found")
+	ErrMaintenancePlanNotFound   = errors.New("maintenance plan not found")
+	ErrVehicleInMaintenance      = errors.New("vehicle is scheduled for maintenance")
+
+	// Odometer and fuel specific errors
+	ErrDriverHasNoVehicle       = errors.New("driver has no vehicle assigned")
+	ErrOdometerAlreadyRecorded  = errors.New("odometer reading already recorded for this trip")
+	ErrOdometerStartMissing     = errors.New("trip has no start odometer reading")
+	ErrOdometerReadingDecreased = errors.New("odometer reading is lower than the vehicle's last reading")
+
+	// Vehicle capacity specific errors
+	ErrNoVehicleCapacity   = errors.New("no active vehicle of the requested type can carry the passengers")
+	ErrVehicleTypeMismatch = errors.New("driver's vehicle is not of the requested type")
+	ErrVehicleTooSmall     = errors.New("driver's vehicle cannot carry the passengers")
+
+	// Vehicle assignment specific errors
+	ErrVehicleAssignmentNotFound = errors.New("no driver had the vehicle at that time")
+
+	// Incident specific errors
+	ErrIncidentNotFound = errors.New("incident not found")
 
 	// Auth specific errors
 	ErrRefreshTokenNotFound = errors.New("refresh token not found")
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedbackStatus string
+
+const (
+	FeedbackStatusPublished FeedbackStatus = "PUBLISHED"
+	FeedbackStatusHidden    FeedbackStatus = "HIDDEN"
+)
+
+// FeedbackTag is a predefined quick reaction passengers can attach to a rating.
+type FeedbackTag string
+
+const (
+	FeedbackTagPuntual          FeedbackTag = "PUNTUAL"
+	FeedbackTagAmable           FeedbackTag = "AMABLE"
+	FeedbackTagConduccionSegura FeedbackTag = "CONDUCCION_SEGURA"
+	FeedbackTagVehiculoLimpio   FeedbackTag = "VEHICULO_LIMPIO"
+	FeedbackTagBuenaRuta        FeedbackTag = "BUENA_RUTA"
+	FeedbackTagImpuntual        FeedbackTag = "IMPUNTUAL"
+	FeedbackTagConduccionBrusca FeedbackTag = "CONDUCCION_BRUSCA"
+	FeedbackTagVehiculoSucio    FeedbackTag = "VEHICULO_SUCIO"
+	FeedbackTagMalTrato         FeedbackTag = "MAL_TRATO"
+)
+
+// FeedbackRequest records the one-time rating prompt sent to the reservation
+// owner once a trip is completed.
+type FeedbackRequest struct {
+	ReservationID string     `json:"reservation_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	DriverID      string     `json:"driver_id"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (r *FeedbackRequest) IsOpen(now time.Time) bool {
+	return now.Before(r.ExpiresAt)
+}
+
+type SubmitFeedbackRequest struct {
+	Rating  int           `json:"rating" validate:"required,min=1,max=5"`
+	Tags    []FeedbackTag `json:"tags,omitempty" validate:"omitempty,max=5,unique,dive,oneof=PUNTUAL AMABLE CONDUCCION_SEGURA VEHICULO_LIMPIO BUENA_RUTA IMPUNTUAL CONDUCCION_BRUSCA VEHICULO_SUCIO MAL_TRATO"`
+	Comment *string       `json:"comment,omitempty" validate:"omitempty,max=1000"`
+}
+
+type ModerateFeedbackRequest struct {
+	Status FeedbackStatus `json:"status" validate:"required,oneof=PUBLISHED HIDDEN"`
+	Note   *string        `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+type ListFeedbackRequest struct {
+	DriverID *string         `json:"driver_id,omitempty"`
+	Status   *FeedbackStatus `json:"status,omitempty"`
+	Page     int             `json:"page" validate:"min=1"`
+	PageSize int             `json:"page_size" validate:"min=1,max=100"`
+}
+
+// FeedbackPrompt is what a passenger sees when opening the emailed rating link.
+type FeedbackPrompt struct {
+	ReservationID   string          `json:"reservation_id"`
+	ScheduledAt     time.Time       `json:"scheduled_at"`
+	Pickup          string          `json:"pickup"`
+	Destination     string          `json:"destination"`
+	DriverFirstName *string         `json:"driver_first_name,omitempty"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+	Submitted       bool            `json:"submitted"`
+	Feedback        *DriverFeedback `json:"feedback,omitempty"`
+}
+
+type FeedbackRepository interface {
+	Create(feedback *DriverFeedback) error
+	GetByID(id uuid.UUID) (*DriverFeedback, error)
+	GetByReservation(reservationID string) (*DriverFeedback, error)
+	List(req ListFeedbackRequest) ([]*DriverFeedback, int, error)
+	Moderate(id uuid.UUID, status FeedbackStatus, note *string, moderatedBy uuid.UUID, moderatedAt time.Time) (*DriverFeedback, error)
+
+	// Rating prompts
+	ListPromptCandidates(completedSince time.Time, limit int) ([]*FeedbackRequest, error)
+	CreateRequest(request *FeedbackRequest) error
+	MarkRequestSent(reservationID string, sentAt time.Time) error
+	GetRequest(reservationID string) (*FeedbackRequest, error)
+	ListPendingRequests(userID uuid.UUID, now time.Time) ([]*FeedbackRequest, error)
+}
+
+// FeedbackTokenService signs and verifies the tokens embedded in emailed
+// rating links.
+type FeedbackTokenService interface {
+	Sign(request *FeedbackRequest) (string, error)
+	Verify(token string) (string, error)
+}
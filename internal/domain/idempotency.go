@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// IdempotencyRecord remembers a request sent with an Idempotency-Key and,
+// once it finished, the response to replay for retries. Keys are unique
+// within a scope (the caller and endpoint).
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	// Set once the request finished
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CompletedAt  *time.Time
+	// A request still running past LockedUntil is assumed lost and its key
+	// can be taken again
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.CompletedAt != nil
+}
+
+// IdempotencyFingerprint identifies a request body. JSON bodies are
+// compared by content, so key order and whitespace do not matter; numbers
+// keep their literal so large or precise amounts are not rounded together.
+func IdempotencyFingerprint(method, path string, body []byte) string {
+	if canonical, ok := canonicalJSON(body); ok {
+		body = canonical
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canonicalJSON re-encodes a single JSON value with sorted keys and no
+// whitespace.
+func canonicalJSON(body []byte) ([]byte, bool) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, false
+	}
+
+	canonical, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, false
+	}
+	return canonical, true
+}
+
+// IdempotencyStore keeps idempotency records until they expire.
+type IdempotencyStore interface {
+	// Reserve claims record's key. When the key is already taken by a
+	// record that has neither expired nor lost its lock, that record is
+	// returned instead and nothing is stored.
+	Reserve(record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete stores the response of a reserved key.
+	Complete(scope, key string, statusCode int, contentType string, body []byte) error
+	// Release frees a reserved key so the request can be retried.
+	Release(scope, key string) error
+	DeleteExpired(before time.Time, limit int) (int64, error)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	base := IdempotencyFingerprint("POST", "/api/v1/payments", []byte(`{"reservation_id":"RES-1","method":"WEBPAY_PLUS"}`))
+
+	// Same JSON content in another layout
+	assert.Equal(t, base, IdempotencyFingerprint("POST", "/api/v1/payments",
+		[]byte("{\n  \"method\": \"WEBPAY_PLUS\",\n  \"reservation_id\": \"RES-1\"\n}")))
+
+	assert.NotEqual(t, base, IdempotencyFingerprint("POST", "/api/v1/payments", []byte(`{"reservation_id":"RES-2","method":"WEBPAY_PLUS"}`)))
+	assert.NotEqual(t, base, IdempotencyFingerprint("POST", "/api/v1/reservations", []byte(`{"reservation_id":"RES-1","method":"WEBPAY_PLUS"}`)))
+
+	// Numbers keep their literal: amounts past float64 precision differ
+	assert.NotEqual(t,
+		IdempotencyFingerprint("POST", "/x", []byte(`{"amount":9007199254740993}`)),
+		IdempotencyFingerprint("POST", "/x", []byte(`{"amount":9007199254740992}`)))
+	assert.NotEqual(t,
+		IdempotencyFingerprint("POST", "/x", []byte(`{"amount":0.10000000000000001}`)),
+		IdempotencyFingerprint("POST", "/x", []byte(`{"amount":0.1}`)))
+	assert.Equal(t,
+		IdempotencyFingerprint("POST", "/x", []byte(`{"amount": 15000, "currency": "CLP"}`)),
+		IdempotencyFingerprint("POST", "/x", []byte(`{"currency":"CLP","amount":15000}`)))
+
+	// Trailing data after the JSON value is not ignored
+	assert.NotEqual(t,
+		IdempotencyFingerprint("POST", "/x", []byte(`{"a":1}`)),
+		IdempotencyFingerprint("POST", "/x", []byte(`{"a":1} {"b":2}`)))
+
+	// Bodies that are not JSON are compared byte by byte
+	assert.Equal(t, IdempotencyFingerprint("POST", "/x", []byte("a=1")), IdempotencyFingerprint("POST", "/x", []byte("a=1")))
+	assert.NotEqual(t, IdempotencyFingerprint("POST", "/x", []byte("a=1")), IdempotencyFingerprint("POST", "/x", []byte("a=1 ")))
+	assert.Equal(t, IdempotencyFingerprint("POST", "/x", nil), IdempotencyFingerprint("POST", "/x", []byte{}))
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IncidentType string
+
+const (
+	IncidentTypeAccident    IncidentType = "ACCIDENT"
+	IncidentTypeDamage      IncidentType = "DAMAGE"
+	IncidentTypeTrafficFine IncidentType = "TRAFFIC_FINE"
+	IncidentTypeBreakdown   IncidentType = "BREAKDOWN"
+	IncidentTypeOther       IncidentType = "OTHER"
+)
+
+type IncidentSeverity string
+
+const (
+	IncidentSeverityLow      IncidentSeverity = "LOW"
+	IncidentSeverityMedium   IncidentSeverity = "MEDIUM"
+	IncidentSeverityHigh     IncidentSeverity = "HIGH"
+	IncidentSeverityCritical IncidentSeverity = "CRITICAL"
+)
+
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen        IncidentStatus = "OPEN"
+	IncidentStatusUnderReview IncidentStatus = "UNDER_REVIEW"
+	IncidentStatusResolved    IncidentStatus = "RESOLVED"
+)
+
+// Incident is an accident, damage, traffic fine or breakdown involving a
+// vehicle. Critical incidents take the vehicle out of service and move its
+// driver's upcoming reservations to other vehicles.
+type Incident struct {
+	ID              uuid.UUID        `json:"id"`
+	VehicleID       string           `json:"vehicle_id"`
+	DriverID        *string          `json:"driver_id,omitempty"`
+	ReservationID   *string          `json:"reservation_id,omitempty"`
+	Type            IncidentType     `json:"type"`
+	Severity        IncidentSeverity `json:"severity"`
+	Status          IncidentStatus   `json:"status"`
+	Description     string           `json:"description"`
+	Location        *string          `json:"location,omitempty"`
+	OccurredAt      time.Time        `json:"occurred_at"`
+	Cost            *float64         `json:"cost,omitempty"`
+	Currency        string           `json:"currency"`
+	ResolutionNotes *string          `json:"resolution_notes,omitempty"`
+	ResolvedAt      *time.Time       `json:"resolved_at,omitempty"`
+	ReportedBy      *uuid.UUID       `json:"reported_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+
+	Photos []*Document `json:"photos,omitempty"`
+	// Filled in when the incident has just become critical
+	Reassignments []IncidentReassignment `json:"reassignments,omitempty"`
+}
+
+// IsCritical reports whether the incident keeps the vehicle off the road.
+func (i *Incident) IsCritical() bool {
+	return i.Severity == IncidentSeverityCritical
+}
+
+// CanTransitionTo reports whether the incident may move to the given status.
+// Incidents under review can be reopened; resolved incidents are final.
+func (i *Incident) CanTransitionTo(newStatus IncidentStatus) bool {
+	switch i.Status {
+	case IncidentStatusOpen:
+		return newStatus == IncidentStatusUnderReview || newStatus == IncidentStatusResolved
+	case IncidentStatusUnderReview:
+		return newStatus == IncidentStatusOpen || newStatus == IncidentStatusResolved
+	default:
+		return false
+	}
+}
+
+// IncidentReassignment is the outcome for one upcoming reservation of the
+// driver of a vehicle taken out of service. NewDriverID is nil when no free
+// vehicle could take the trip and the reservation was left unassigned.
+type IncidentReassignment struct {
+	ReservationID    string  `json:"reservation_id"`
+	PreviousDriverID string  `json:"previous_driver_id"`
+	NewDriverID      *string `json:"new_driver_id,omitempty"`
+	NewVehicleID     *string `json:"new_vehicle_id,omitempty"`
+}
+
+// CreateIncidentRequest records an incident on behalf of a driver. Without a
+// driver, the one assigned to the reservation, or else the one who had the
+// vehicle at OccurredAt, is used.
+type CreateIncidentRequest struct {
+	VehicleID     string           `json:"vehicle_id" validate:"required"`
+	DriverID      *string          `json:"driver_id,omitempty"`
+	ReservationID *string          `json:"reservation_id,omitempty"`
+	Type          IncidentType     `json:"type" validate:"required,oneof=ACCIDENT DAMAGE TRAFFIC_FINE BREAKDOWN OTHER"`
+	Severity      IncidentSeverity `json:"severity" validate:"required,oneof=LOW MEDIUM HIGH CRITICAL"`
+	Description   string           `json:"description" validate:"required,min=1,max=2000"`
+	Location      *string          `json:"location,omitempty" validate:"omitempty,max=255"`
+	OccurredAt    *time.Time       `json:"occurred_at,omitempty"`
+	Cost          *float64         `json:"cost,omitempty" validate:"omitempty,min=0"`
+}
+
+// ReportIncidentRequest is filed by a driver against their current vehicle.
+type ReportIncidentRequest struct {
+	ReservationID *string          `json:"reservation_id,omitempty"`
+	Type          IncidentType     `json:"type" validate:"required,oneof=ACCIDENT DAMAGE TRAFFIC_FINE BREAKDOWN OTHER"`
+	Severity      IncidentSeverity `json:"severity" validate:"required,oneof=LOW MEDIUM HIGH CRITICAL"`
+	Description   string           `json:"description" validate:"required,min=1,max=2000"`
+	Location      *string          `json:"location,omitempty" validate:"omitempty,max=255"`
+	OccurredAt    *time.Time       `json:"occurred_at,omitempty"`
+}
+
+type UpdateIncidentRequest struct {
+	Status          *IncidentStatus   `json:"status,omitempty" validate:"omitempty,oneof=OPEN UNDER_REVIEW RESOLVED"`
+	Severity        *IncidentSeverity `json:"severity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+	Description     *string           `json:"description,omitempty" validate:"omitempty,min=1,max=2000"`
+	Location        *string           `json:"location,omitempty" validate:"omitempty,max=255"`
+	Cost            *float64          `json:"cost,omitempty" validate:"omitempty,min=0"`
+	ResolutionNotes *string           `json:"resolution_notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+type ListIncidentsRequest struct {
+	VehicleID *string           `json:"vehicle_id,omitempty"`
+	DriverID  *string           `json:"driver_id,omitempty"`
+	Status    *IncidentStatus   `json:"status,omitempty"`
+	Severity  *IncidentSeverity `json:"severity,omitempty"`
+	Type      *IncidentType     `json:"type,omitempty"`
+	From      *time.Time        `json:"from,omitempty"`
+	To        *time.Time        `json:"to,omitempty"`
+	Page      int               `json:"page" validate:"min=1"`
+	PageSize  int               `json:"page_size" validate:"min=1,max=100"`
+}
+
+type IncidentRepository interface {
+	Create(incident *Incident) error
+	GetByID(id uuid.UUID) (*Incident, error)
+	Update(incident *Incident) error
+	List(req ListIncidentsRequest) ([]*Incident, int, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from IncidentStatus
+		to   IncidentStatus
+		want bool
+	}{
+		{IncidentStatusOpen, IncidentStatusUnderReview, true},
+		{IncidentStatusOpen, IncidentStatusResolved, true},
+		{IncidentStatusUnderReview, IncidentStatusOpen, true},
+		{IncidentStatusUnderReview, IncidentStatusResolved, true},
+		{IncidentStatusResolved, IncidentStatusOpen, false},
+		{IncidentStatusResolved, IncidentStatusUnderReview, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			incident := &Incident{Status: tt.from}
+			assert.Equal(t, tt.want, incident.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestIncidentIsCritical(t *testing.T) {
+	assert.True(t, (&Incident{Severity: IncidentSeverityCritical}).IsCritical())
+	assert.False(t, (&Incident{Severity: IncidentSeverityHigh}).IsCritical())
+}
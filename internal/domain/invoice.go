@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingAccount makes a company postpaid: its completed trips accrue to a
+// monthly period that is closed into an invoice instead of being paid one
+// by one.
+type BillingAccount struct {
+	ID               uuid.UUID `json:"id"`
+	CompanyID        uuid.UUID `json:"company_id"`
+	Currency         string    `json:"currency"`
+	TaxRate          float64   `json:"tax_rate"`
+	PaymentTermsDays int       `json:"payment_terms_days"`
+	BillingEmail     *string   `json:"billing_email,omitempty"`
+	Active           bool      `json:"active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// UpsertBillingAccountRequest opens or updates a company's billing account.
+// The currency must be the pricing currency. Unset tax rate and payment
+// terms take the configured defaults on creation and keep their value on
+// update.
+type UpsertBillingAccountRequest struct {
+	Currency         string   `json:"currency" validate:"required,len=3"`
+	TaxRate          *float64 `json:"tax_rate" validate:"omitempty,min=0,max=1"`
+	PaymentTermsDays *int     `json:"payment_terms_days" validate:"omitempty,min=0,max=120"`
+	BillingEmail     *string  `json:"billing_email" validate:"omitempty,email"`
+	Active           *bool    `json:"active"`
+}
+
+type BillingPeriodStatus string
+
+const (
+	BillingPeriodStatusOpen   BillingPeriodStatus = "OPEN"
+	BillingPeriodStatusClosed BillingPeriodStatus = "CLOSED"
+)
+
+// BillingPeriod collects the accruals of an account between PeriodStart
+// (inclusive) and PeriodEnd (exclusive). Periods are calendar months unless
+// one is closed early.
+type BillingPeriod struct {
+	ID          uuid.UUID           `json:"id"`
+	AccountID   uuid.UUID           `json:"account_id"`
+	PeriodStart time.Time           `json:"period_start"`
+	PeriodEnd   time.Time           `json:"period_end"`
+	Status      BillingPeriodStatus `json:"status"`
+	InvoiceID   *uuid.UUID          `json:"invoice_id,omitempty"`
+	ClosedAt    *time.Time          `json:"closed_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+
+	// Related data
+	Accruals []*BillingAccrual `json:"accruals,omitempty"`
+	Subtotal float64           `json:"subtotal"`
+}
+
+// MonthPeriod returns the bounds of the calendar month containing t.
+func MonthPeriod(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Close marks the period closed at the given time and returns the period
+// that follows it. Closing before PeriodEnd shortens the period and the next
+// one finishes the month, so later periods stay aligned to calendar months.
+func (p *BillingPeriod) Close(at time.Time) *BillingPeriod {
+	next := &BillingPeriod{
+		AccountID: p.AccountID,
+		Status:    BillingPeriodStatusOpen,
+	}
+	if at.After(p.PeriodStart) && at.Before(p.PeriodEnd) {
+		next.PeriodStart, next.PeriodEnd = at, p.PeriodEnd
+		p.PeriodEnd = at
+	} else {
+		next.PeriodStart = p.PeriodEnd
+		_, next.PeriodEnd = MonthPeriod(p.PeriodEnd)
+	}
+
+	p.Status = BillingPeriodStatusClosed
+	p.ClosedAt = &at
+	return next
+}
+
+// BillingAccrual is a completed trip billed to an account's period.
+type BillingAccrual struct {
+	ID            uuid.UUID `json:"id"`
+	AccountID     uuid.UUID `json:"account_id"`
+	PeriodID      uuid.UUID `json:"period_id"`
+	ReservationID string    `json:"reservation_id"`
+	Description   string    `json:"description"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BillingAccrualCandidate is a completed, priced trip of a company with an
+// active billing account that has not been accrued or paid on its own yet.
+type BillingAccrualCandidate struct {
+	AccountID     uuid.UUID
+	PeriodID      uuid.UUID
+	ReservationID string
+	Pickup        string
+	Destination   string
+	Amount        float64
+	Currency      string
+	CompletedAt   time.Time
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusIssued  InvoiceStatus = "ISSUED"
+	InvoiceStatusPaid    InvoiceStatus = "PAID"
+	InvoiceStatusOverdue InvoiceStatus = "OVERDUE"
+	InvoiceStatusVoid    InvoiceStatus = "VOID"
+)
+
+// Invoice bills the accruals of a closed period. It is ISSUED until paid,
+// becomes OVERDUE after its due date and may be voided while unpaid.
+type Invoice struct {
+	ID          uuid.UUID     `json:"id"`
+	Number      string        `json:"number"`
+	AccountID   uuid.UUID     `json:"account_id"`
+	CompanyID   uuid.UUID     `json:"company_id"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Status      InvoiceStatus `json:"status"`
+	Currency    string        `json:"currency"`
+	Subtotal    float64       `json:"subtotal"`
+	TaxRate     float64       `json:"tax_rate"`
+	TaxAmount   float64       `json:"tax_amount"`
+	Total       float64       `json:"total"`
+	IssuedAt    time.Time     `json:"issued_at"`
+	DueDate     time.Time     `json:"due_date"`
+	PaidAt      *time.Time    `json:"paid_at,omitempty"`
+	PaymentID   *uuid.UUID    `json:"payment_id,omitempty"`
+	VoidedAt    *time.Time    `json:"voided_at,omitempty"`
+	VoidReason  *string       `json:"void_reason,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+
+	// Related data
+	Lines []*InvoiceLine `json:"lines,omitempty"`
+}
+
+type InvoiceLine struct {
+	ID            uuid.UUID `json:"id"`
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	Position      int       `json:"position"`
+	ReservationID *string   `json:"reservation_id,omitempty"`
+	Description   string    `json:"description"`
+	Quantity      int       `json:"quantity"`
+	UnitPrice     float64   `json:"unit_price"`
+	Amount        float64   `json:"amount"`
+}
+
+// NewInvoice bills the accruals of a period with one line per trip. Tax is
+// applied to the subtotal and the invoice is due PaymentTermsDays after it
+// is issued.
+func NewInvoice(account *BillingAccount, period *BillingPeriod, accruals []*BillingAccrual, issuedAt time.Time) *Invoice {
+	invoice := &Invoice{
+		AccountID:   account.ID,
+		CompanyID:   account.CompanyID,
+		PeriodStart: period.PeriodStart,
+		PeriodEnd:   period.PeriodEnd,
+		Status:      InvoiceStatusIssued,
+		Currency:    account.Currency,
+		TaxRate:     account.TaxRate,
+		IssuedAt:    issuedAt,
+		DueDate:     time.Date(issuedAt.Year(), issuedAt.Month(), issuedAt.Day()+account.PaymentTermsDays, 0, 0, 0, 0, issuedAt.Location()),
+		Lines:       make([]*InvoiceLine, 0, len(accruals)),
+	}
+
+	for i, accrual := range accruals {
+		reservationID := accrual.ReservationID
+		invoice.Lines = append(invoice.Lines, &InvoiceLine{
+			Position:      i + 1,
+			ReservationID: &reservationID,
+			Description:   accrual.Description,
+			Quantity:      1,
+			UnitPrice:     accrual.Amount,
+			Amount:        accrual.Amount,
+		})
+		invoice.Subtotal += accrual.Amount
+	}
+
+	invoice.Subtotal = roundCents(invoice.Subtotal)
+	invoice.TaxAmount = roundCents(invoice.Subtotal * invoice.TaxRate)
+	invoice.Total = roundCents(invoice.Subtotal + invoice.TaxAmount)
+	return invoice
+}
+
+// IsPayable reports whether the invoice still has to be paid.
+func (i *Invoice) IsPayable() bool {
+	return i.Status == InvoiceStatusIssued || i.Status == InvoiceStatusOverdue
+}
+
+// AccrualDescription is how a trip reads on the invoice and on wallet
+// statements.
+func AccrualDescription(reservationID, pickup, destination string, at time.Time) string {
+	return fmt.Sprintf("Viaje %s del %s: %s - %s", reservationID, at.Format("02-01-2006"), pickup, destination)
+}
+
+type VoidInvoiceRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ListInvoicesRequest struct {
+	CompanyID *uuid.UUID
+	Status    *InvoiceStatus
+	Page      int
+	PageSize  int
+}
+
+type InvoiceRepository interface {
+	GetAccountByCompany(companyID uuid.UUID) (*BillingAccount, error)
+	GetAccount(id uuid.UUID) (*BillingAccount, error)
+	// CreateAccount stores the account with its first open period.
+	CreateAccount(account *BillingAccount, period *BillingPeriod) error
+	UpdateAccount(account *BillingAccount) error
+
+	// ListAccrualCandidates returns up to limit trips to accrue with the
+	// open period of their account in currency, oldest first. Only trips
+	// completed after the account was opened are accrued.
+	ListAccrualCandidates(currency string, limit int) ([]*BillingAccrualCandidate, error)
+	// CreateAccrual returns ErrAlreadyExists if the trip was accrued before.
+	CreateAccrual(accrual *BillingAccrual) error
+	ListAccruals(periodID uuid.UUID) ([]*BillingAccrual, error)
+
+	GetOpenPeriod(accountID uuid.UUID) (*BillingPeriod, error)
+	// ListPeriodsToClose returns the open periods that ended by the given
+	// time.
+	ListPeriodsToClose(endedBy time.Time) ([]*BillingPeriod, error)
+	// ClosePeriod stores the invoice, if any, closes the period and opens
+	// next in one transaction. It returns ErrAlreadyExists if the period
+	// was closed by another run.
+	ClosePeriod(period *BillingPeriod, invoice *Invoice, next *BillingPeriod) error
+
+	// GetInvoice returns the invoice with its lines.
+	GetInvoice(id uuid.UUID) (*Invoice, error)
+	ListInvoices(req ListInvoicesRequest) ([]*Invoice, int, error)
+	// MarkPaid moves a payable invoice to PAID. It returns
+	// ErrInvalidStatusTransition if the invoice is no longer payable.
+	MarkPaid(id, paymentID uuid.UUID, paidAt time.Time) (*Invoice, error)
+	// Void moves an unpaid invoice to VOID. It returns
+	// ErrInvalidStatusTransition if the invoice is paid or void.
+	Void(id uuid.UUID, reason string, voidedAt time.Time) (*Invoice, error)
+	// MarkOverdue moves the issued invoices due before today to OVERDUE
+	// and returns how many there were.
+	MarkOverdue(today time.Time) (int, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInvoice(t *testing.T) {
+	account := &BillingAccount{
+		ID:               uuid.New(),
+		CompanyID:        uuid.New(),
+		Currency:         "CLP",
+		TaxRate:          0.19,
+		PaymentTermsDays: 30,
+	}
+	period := &BillingPeriod{
+		PeriodStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	accruals := []*BillingAccrual{
+		{ReservationID: "RSV-1", Description: "Viaje RSV-1", Amount: 25000.10},
+		{ReservationID: "RSV-2", Description: "Viaje RSV-2", Amount: 18000.25},
+	}
+
+	invoice := NewInvoice(account, period, accruals, time.Date(2026, 10, 1, 3, 15, 0, 0, time.UTC))
+
+	assert.Equal(t, InvoiceStatusIssued, invoice.Status)
+	assert.Equal(t, account.CompanyID, invoice.CompanyID)
+	assert.Equal(t, period.PeriodStart, invoice.PeriodStart)
+	assert.Equal(t, 43000.35, invoice.Subtotal)
+	assert.Equal(t, 8170.07, invoice.TaxAmount)
+	assert.Equal(t, 51170.42, invoice.Total)
+	assert.Equal(t, time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC), invoice.DueDate)
+
+	require.Len(t, invoice.Lines, 2)
+	assert.Equal(t, 2, invoice.Lines[1].Position)
+	assert.Equal(t, "RSV-2", *invoice.Lines[1].ReservationID)
+	assert.Equal(t, 1, invoice.Lines[1].Quantity)
+	assert.Equal(t, 18000.25, invoice.Lines[1].Amount)
+}
+
+func TestBillingPeriodClose(t *testing.T) {
+	newPeriod := func() *BillingPeriod {
+		start, end := MonthPeriod(time.Date(2026, 9, 14, 10, 0, 0, 0, time.UTC))
+		return &BillingPeriod{AccountID: uuid.New(), PeriodStart: start, PeriodEnd: end, Status: BillingPeriodStatusOpen}
+	}
+
+	t.Run("at the end of the month", func(t *testing.T) {
+		period := newPeriod()
+		next := period.Close(time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, BillingPeriodStatusClosed, period.Status)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), period.PeriodEnd)
+		assert.Equal(t, period.AccountID, next.AccountID)
+		assert.Equal(t, BillingPeriodStatusOpen, next.Status)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), next.PeriodStart)
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), next.PeriodEnd)
+	})
+
+	t.Run("early", func(t *testing.T) {
+		period := newPeriod()
+		at := time.Date(2026, 9, 20, 12, 0, 0, 0, time.UTC)
+		next := period.Close(at)
+
+		assert.Equal(t, at, period.PeriodEnd)
+		assert.Equal(t, at, *period.ClosedAt)
+		assert.Equal(t, at, next.PeriodStart)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), next.PeriodEnd)
+	})
+}
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance plans are reminded this long, or this many km, before they fall due.
+const (
+	MaintenanceReminderDays = 7
+	MaintenanceReminderKm   = 500
+)
+
+type MaintenanceServiceType string
+
+const (
+	MaintenanceServiceOilChange      MaintenanceServiceType = "OIL_CHANGE"
+	MaintenanceServiceTires          MaintenanceServiceType = "TIRES"
+	MaintenanceServiceBrakes         MaintenanceServiceType = "BRAKES"
+	MaintenanceServiceGeneralService MaintenanceServiceType = "GENERAL_SERVICE"
+	MaintenanceServiceInspection     MaintenanceServiceType = "INSPECTION"
+	MaintenanceServiceRepair         MaintenanceServiceType = "REPAIR"
+	MaintenanceServiceOther          MaintenanceServiceType = "OTHER"
+)
+
+type MaintenanceStatus string
+
+const (
+	MaintenanceStatusScheduled  MaintenanceStatus = "SCHEDULED"
+	MaintenanceStatusInProgress MaintenanceStatus = "IN_PROGRESS"
+	MaintenanceStatusCompleted  MaintenanceStatus = "COMPLETED"
+	MaintenanceStatusCancelled  MaintenanceStatus = "CANCELLED"
+)
+
+// MaintenanceRecord is a service performed on a vehicle, or a window in which
+// one is scheduled. While a window is in progress the vehicle is kept in
+// MAINTENANCE status and cannot take trips.
+type MaintenanceRecord struct {
+	ID             uuid.UUID              `json:"id"`
+	VehicleID      string                 `json:"vehicle_id"`
+	PlanID         *uuid.UUID             `json:"plan_id,omitempty"`
+	ServiceType    MaintenanceServiceType `json:"service_type"`
+	Status         MaintenanceStatus      `json:"status"`
+	ScheduledStart *time.Time             `json:"scheduled_start,omitempty"`
+	ScheduledEnd   *time.Time             `json:"scheduled_end,omitempty"`
+	PerformedAt    *time.Time             `json:"performed_at,omitempty"`
+	Odometer       *int                   `json:"odometer,omitempty"` // km
+	Cost           *float64               `json:"cost,omitempty"`
+	Currency       string                 `json:"currency"`
+	Workshop       *string                `json:"workshop,omitempty"`
+	Description    *string                `json:"description,omitempty"`
+	CreatedBy      *uuid.UUID             `json:"created_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+
+	Attachments []*Document `json:"attachments,omitempty"`
+}
+
+// IsWindow reports whether the record blocks the vehicle for a period.
+func (r *MaintenanceRecord) IsWindow() bool {
+	return r.ScheduledStart != nil && r.ScheduledEnd != nil
+}
+
+type MaintenanceDueState string
+
+const (
+	MaintenanceDueOK      MaintenanceDueState = "OK"
+	MaintenanceDueSoon    MaintenanceDueState = "DUE_SOON"
+	MaintenanceDueOverdue MaintenanceDueState = "OVERDUE"
+)
+
+// MaintenancePlan repeats a service every IntervalKm kilometres and/or every
+// IntervalDays days, counted from the last time it was performed.
+type MaintenancePlan struct {
+	ID                  uuid.UUID              `json:"id"`
+	VehicleID           string                 `json:"vehicle_id"`
+	ServiceType         MaintenanceServiceType `json:"service_type"`
+	IntervalKm          *int                   `json:"interval_km,omitempty"`
+	IntervalDays        *int                   `json:"interval_days,omitempty"`
+	LastServiceAt       time.Time              `json:"last_service_at"`
+	LastServiceOdometer *int                   `json:"last_service_odometer,omitempty"`
+	Active              bool                   `json:"active"`
+	Notes               *string                `json:"notes,omitempty"`
+	ReminderSentAt      *time.Time             `json:"reminder_sent_at,omitempty"`
+	CreatedBy           *uuid.UUID             `json:"created_by,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+
+	// Filled in when the plan is evaluated against the vehicle's odometer
+	NextDueAt       *time.Time          `json:"next_due_at,omitempty"`
+	NextDueOdometer *int                `json:"next_due_odometer,omitempty"`
+	CurrentOdometer *int                `json:"current_odometer,omitempty"`
+	DueState        MaintenanceDueState `json:"due_state,omitempty"`
+}
+
+// Evaluate fills in when the plan is next due and whether it is due soon or
+// overdue, by date or by odometer, whichever comes first.
+func (p *MaintenancePlan) Evaluate(now time.Time, odometer *int) {
+	p.NextDueAt, p.NextDueOdometer = nil, nil
+	p.CurrentOdometer = odometer
+	p.DueState = MaintenanceDueOK
+
+	if p.IntervalDays != nil {
+		dueAt := p.LastServiceAt.AddDate(0, 0, *p.IntervalDays)
+		p.NextDueAt = &dueAt
+		switch {
+		case !now.Before(dueAt):
+			p.DueState = MaintenanceDueOverdue
+		case !now.Before(dueAt.AddDate(0, 0, -MaintenanceReminderDays)):
+			p.DueState = MaintenanceDueSoon
+		}
+	}
+
+	if p.IntervalKm != nil && p.LastServiceOdometer != nil {
+		dueKm := *p.LastServiceOdometer + *p.IntervalKm
+		p.NextDueOdometer = &dueKm
+		if odometer != nil && p.DueState != MaintenanceDueOverdue {
+			switch {
+			case *odometer >= dueKm:
+				p.DueState = MaintenanceDueOverdue
+			case *odometer >= dueKm-MaintenanceReminderKm:
+				p.DueState = MaintenanceDueSoon
+			}
+		}
+	}
+}
+
+// MaintenanceCostLine totals the cost of one service type.
+type MaintenanceCostLine struct {
+	ServiceType MaintenanceServiceType `json:"service_type"`
+	Count       int                    `json:"count"`
+	Total       float64                `json:"total"`
+}
+
+// MaintenanceCostReport totals the completed maintenance of a vehicle in a
+// period.
+type MaintenanceCostReport struct {
+	VehicleID     string                `json:"vehicle_id"`
+	Plate         *string               `json:"plate,omitempty"`
+	From          *time.Time            `json:"from,omitempty"`
+	To            *time.Time            `json:"to,omitempty"`
+	Currency      string                `json:"currency"`
+	RecordCount   int                   `json:"record_count"`
+	TotalCost     float64               `json:"total_cost"`
+	ByServiceType []MaintenanceCostLine `json:"by_service_type"`
+}
+
+// CreateMaintenanceRecordRequest either schedules a window (ScheduledStart
+// and ScheduledEnd) or records a service already performed (PerformedAt).
+type CreateMaintenanceRecordRequest struct {
+	ServiceType    MaintenanceServiceType `json:"service_type" validate:"required,oneof=OIL_CHANGE TIRES BRAKES GENERAL_SERVICE INSPECTION REPAIR OTHER"`
+	PlanID         *uuid.UUID             `json:"plan_id,omitempty"`
+	ScheduledStart *time.Time             `json:"scheduled_start,omitempty" validate:"required_with=ScheduledEnd"`
+	ScheduledEnd   *time.Time             `json:"scheduled_end,omitempty" validate:"required_with=ScheduledStart"`
+	PerformedAt    *time.Time             `json:"performed_at,omitempty" validate:"required_without=ScheduledStart"`
+	Odometer       *int                   `json:"odometer,omitempty" validate:"omitempty,min=0"`
+	Cost           *float64               `json:"cost,omitempty" validate:"omitempty,min=0"`
+	Workshop       *string                `json:"workshop,omitempty" validate:"omitempty,max=200"`
+	Description    *string                `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+type UpdateMaintenanceRecordRequest struct {
+	Status         *MaintenanceStatus `json:"status,omitempty" validate:"omitempty,oneof=IN_PROGRESS COMPLETED CANCELLED"`
+	ScheduledStart *time.Time         `json:"scheduled_start,omitempty"`
+	ScheduledEnd   *time.Time         `json:"scheduled_end,omitempty"`
+	PerformedAt    *time.Time         `json:"performed_at,omitempty"`
+	Odometer       *int               `json:"odometer,omitempty" validate:"omitempty,min=0"`
+	Cost           *float64           `json:"cost,omitempty" validate:"omitempty,min=0"`
+	Workshop       *string            `json:"workshop,omitempty" validate:"omitempty,max=200"`
+	Description    *string            `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+type ListMaintenanceRecordsRequest struct {
+	VehicleID   *string                 `json:"vehicle_id,omitempty"`
+	PlanID      *uuid.UUID              `json:"plan_id,omitempty"`
+	Status      *MaintenanceStatus      `json:"status,omitempty"`
+	ServiceType *MaintenanceServiceType `json:"service_type,omitempty"`
+	From        *time.Time              `json:"from,omitempty"`
+	To          *time.Time              `json:"to,omitempty"`
+	Page        int                     `json:"page" validate:"min=1"`
+	PageSize    int                     `json:"page_size" validate:"min=1,max=100"`
+}
+
+// CreateMaintenancePlanRequest needs at least one interval. Without a last
+// service the plan counts from today and the vehicle's latest odometer.
+type CreateMaintenancePlanRequest struct {
+	ServiceType         MaintenanceServiceType `json:"service_type" validate:"required,oneof=OIL_CHANGE TIRES BRAKES GENERAL_SERVICE INSPECTION REPAIR OTHER"`
+	IntervalKm          *int                   `json:"interval_km,omitempty" validate:"required_without=IntervalDays,omitempty,min=1"`
+	IntervalDays        *int                   `json:"interval_days,omitempty" validate:"required_without=IntervalKm,omitempty,min=1"`
+	LastServiceAt       *time.Time             `json:"last_service_at,omitempty"`
+	LastServiceOdometer *int                   `json:"last_service_odometer,omitempty" validate:"omitempty,min=0"`
+	Notes               *string                `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+type UpdateMaintenancePlanRequest struct {
+	IntervalKm   *int    `json:"interval_km,omitempty" validate:"omitempty,min=1"`
+	IntervalDays *int    `json:"interval_days,omitempty" validate:"omitempty,min=1"`
+	Active       *bool   `json:"active,omitempty"`
+	Notes        *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+type MaintenanceRepository interface {
+	CreateRecord(record *MaintenanceRecord) error
+	GetRecord(id uuid.UUID) (*MaintenanceRecord, error)
+	UpdateRecord(record *MaintenanceRecord) error
+	ListRecords(req ListMaintenanceRecordsRequest) ([]*MaintenanceRecord, int, error)
+	ListWindowsToStart(now time.Time) ([]*MaintenanceRecord, error)
+	ListWindowsToEnd(now time.Time) ([]*MaintenanceRecord, error)
+	HasActiveWindow(vehicleID string, at time.Time, excludeID *uuid.UUID) (bool, error)
+	CostSummary(vehicleID *string, from, to *time.Time) (map[string][]MaintenanceCostLine, error)
+	LatestOdometer(vehicleID string) (*int, error)
+
+	CreatePlan(plan *MaintenancePlan) error
+	GetPlan(id uuid.UUID) (*MaintenancePlan, error)
+	UpdatePlan(plan *MaintenancePlan) error
+	ListPlans(vehicleID *string, activeOnly bool) ([]*MaintenancePlan, error)
+	RecordPlanService(planID uuid.UUID, performedAt time.Time, odometer *int) error
+	MarkPlanReminded(planID uuid.UUID, at time.Time) error
+}
+
+// MaintenanceChecker guards assignments on behalf of the dispatch and
+// reservation flows.
+type MaintenanceChecker interface {
+	EnsureDriverVehicleInService(driverID string, at time.Time) error
+}
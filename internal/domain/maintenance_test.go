@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenancePlanEvaluate(t *testing.T) {
+	days, km, lastKm := 180, 10000, 40000
+	lastService := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		odometer *int
+		want     MaintenanceDueState
+	}{
+		{"ok", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), intPtr(45000), MaintenanceDueOK},
+		{"due soon by date", time.Date(2026, 9, 25, 0, 0, 0, 0, time.UTC), intPtr(45000), MaintenanceDueSoon},
+		{"overdue by date", time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC), intPtr(45000), MaintenanceDueOverdue},
+		{"due soon by km", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), intPtr(49600), MaintenanceDueSoon},
+		{"overdue by km", time.Date(2026, 9, 25, 0, 0, 0, 0, time.UTC), intPtr(50000), MaintenanceDueOverdue},
+		{"unknown odometer", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), nil, MaintenanceDueOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &MaintenancePlan{
+				IntervalDays:        &days,
+				IntervalKm:          &km,
+				LastServiceAt:       lastService,
+				LastServiceOdometer: &lastKm,
+			}
+			plan.Evaluate(tt.now, tt.odometer)
+
+			assert.Equal(t, tt.want, plan.DueState)
+			require.NotNil(t, plan.NextDueAt)
+			assert.Equal(t, time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC), *plan.NextDueAt)
+			require.NotNil(t, plan.NextDueOdometer)
+			assert.Equal(t, 50000, *plan.NextDueOdometer)
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
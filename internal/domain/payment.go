@@ -10,7 +10,11 @@ import (
 type PaymentGateway string
 
 const (
-	PaymentGatewayWebpayPlus PaymentGateway = "WEBPAY_PLUS"
+	PaymentGatewayWebpayPlus    PaymentGateway = "WEBPAY_PLUS"
+	PaymentGatewayMercadoPago   PaymentGateway = "MERCADO_PAGO"
+	PaymentGatewayStripe        PaymentGateway = "STRIPE"
+	PaymentGatewayBankTransfer  PaymentGateway = "BANK_TRANSFER"
+	PaymentGatewayCompanyCredit PaymentGateway = "COMPANY_CREDIT"
 )
 
 type PaymentStatus string
@@ -19,11 +23,18 @@ const (
 	PaymentStatusApproved PaymentStatus = "APPROVED"
 	PaymentStatusRejected PaymentStatus = "REJECTED"
 	PaymentStatusPending  PaymentStatus = "PENDING"
+
+	PaymentStatusPartiallyRefunded PaymentStatus = "PARTIALLY_REFUNDED"
+	PaymentStatusRefunded          PaymentStatus = "REFUNDED"
 )
 
+// Payment pays a reservation or, for companies, an invoice or a wallet
+// top-up; ReservationID is empty for invoice payments and top-ups.
 type Payment struct {
 	ID             uuid.UUID              `json:"id"`
 	ReservationID  string                 `json:"reservation_id"`
+	InvoiceID      *uuid.UUID             `json:"invoice_id,omitempty"`
+	WalletID       *uuid.UUID             `json:"wallet_id,omitempty"`
 	Gateway        PaymentGateway         `json:"gateway"`
 	Amount         float64                `json:"amount"`
 	Currency       string                 `json:"currency"`
@@ -32,13 +43,69 @@ type Payment struct {
 	Payload        map[string]interface{} `json:"payload,omitempty"`
 	CreatedAt      time.Time              `json:"created_at"`
 
+	// Set on creation when the customer must complete the payment at the
+	// gateway
+	Redirect *PaymentRedirect `json:"redirect,omitempty"`
+
 	// Related data
 	Reservation *Reservation `json:"reservation,omitempty"`
+	Invoice     *Invoice     `json:"invoice,omitempty"`
+}
+
+// Reference identifies what is paid: the reservation, the invoice or the
+// wallet.
+func (p *Payment) Reference() string {
+	switch {
+	case p.InvoiceID != nil:
+		return p.InvoiceID.String()
+	case p.WalletID != nil:
+		return p.WalletID.String()
+	}
+	return p.ReservationID
+}
+
+// Description is how the payment reads at the gateway checkout.
+func (p *Payment) Description() string {
+	switch {
+	case p.Invoice != nil:
+		return "Factura " + p.Invoice.Number
+	case p.InvoiceID != nil:
+		return "Factura " + p.InvoiceID.String()
+	case p.WalletID != nil:
+		return "Recarga de saldo"
+	default:
+		return "Reserva " + p.ReservationID
+	}
 }
 
+// IsCaptured reports whether the payment was charged and still has money
+// that can be refunded.
+func (p *Payment) IsCaptured() bool {
+	return p.Status == PaymentStatusApproved || p.Status == PaymentStatusPartiallyRefunded
+}
+
+// PaymentRedirect is where the customer completes a payment. With Method
+// POST (Webpay) the browser submits a form with Token as token_ws to URL;
+// with GET it simply opens URL.
+type PaymentRedirect struct {
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Method string `json:"method"`
+}
+
+// CreatePaymentRequest pays a reservation or an issued invoice, exactly one
+// of them.
 type CreatePaymentRequest struct {
-	ReservationID string         `json:"reservation_id" validate:"required"`
-	Method        PaymentGateway `json:"method" validate:"required"`
+	ReservationID string         `json:"reservation_id" validate:"required_without=InvoiceID,excluded_with=InvoiceID"`
+	InvoiceID     *uuid.UUID     `json:"invoice_id"`
+	Method        PaymentGateway `json:"method" validate:"required,oneof=WEBPAY_PLUS MERCADO_PAGO STRIPE BANK_TRANSFER COMPANY_CREDIT"`
+}
+
+// PaymentMethods lists the gateways a reservation can be paid with.
+type PaymentMethods struct {
+	ReservationID string           `json:"reservation_id"`
+	Currency      string           `json:"currency"`
+	Methods       []PaymentGateway `json:"methods"`
 }
 
 type SimulatePaymentRequest struct {
@@ -65,13 +132,35 @@ type PaymentRepository interface {
 	Create(payment *Payment) error
 	GetByID(id uuid.UUID) (*Payment, error)
 	GetByReservationID(reservationID string) ([]*Payment, error)
+	GetByTransactionRef(transactionRef string) (*Payment, error)
 	Update(id uuid.UUID, status PaymentStatus, transactionRef *string, payload map[string]interface{}) (*Payment, error)
+	// ListPending returns up to limit payments still pending that were
+	// created before createdBefore, oldest first.
+	ListPending(createdBefore time.Time, limit int) ([]*Payment, error)
+	// ListCreatedBetween returns the payments created in [from, to), of one
+	// gateway when given, oldest first.
+	ListCreatedBetween(from, to time.Time, gateway *PaymentGateway) ([]*Payment, error)
 }
 
-// PaymentGatewayService interface for payment processing
+// PaymentGatewayService interface for payment processing. Redirect-based
+// gateways leave the payment PENDING in ProcessPayment and settle it in
+// CommitPayment once the customer returns; the transaction reference is the
+// gateway token. Operations a gateway does not offer return
+// ErrPaymentOperationUnsupported.
 type PaymentGatewayService interface {
 	ProcessPayment(payment *Payment) (*PaymentResult, error)
 	SimulatePayment(paymentID uuid.UUID, result PaymentStatus) (*PaymentResult, error)
+	CommitPayment(token string) (*PaymentResult, error)
+	GetPaymentStatus(token string) (*PaymentResult, error)
+	RefundPayment(payment *Payment, amount float64) (*RefundResult, error)
+}
+
+// PaymentGatewayRegistry holds the configured gateway of each payment
+// method and the currencies it takes.
+type PaymentGatewayRegistry interface {
+	Get(gateway PaymentGateway) (PaymentGatewayService, bool)
+	Supports(gateway PaymentGateway, currency string) bool
+	Gateways() []PaymentGateway
 }
 
 type PaymentResult struct {
@@ -80,4 +169,7 @@ type PaymentResult struct {
 	TransactionRef *string                `json:"transaction_ref,omitempty"`
 	Payload        map[string]interface{} `json:"payload,omitempty"`
 	Message        string                 `json:"message"`
+	Redirect       *PaymentRedirect       `json:"redirect,omitempty"`
+	// Amount is what the gateway charged, when it reports it
+	Amount *float64 `json:"amount,omitempty"`
 }
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyPaymentMethod turns a payment method on or off for a company in one
+// currency.
+type CompanyPaymentMethod struct {
+	CompanyID uuid.UUID      `json:"company_id"`
+	Gateway   PaymentGateway `json:"gateway"`
+	Currency  string         `json:"currency"`
+	Enabled   bool           `json:"enabled"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type CompanyPaymentMethodInput struct {
+	Gateway  PaymentGateway `json:"gateway" validate:"required,oneof=WEBPAY_PLUS MERCADO_PAGO STRIPE BANK_TRANSFER COMPANY_CREDIT"`
+	Currency string         `json:"currency" validate:"required,len=3,uppercase"`
+	Enabled  bool           `json:"enabled"`
+}
+
+// UpdateCompanyPaymentMethodsRequest replaces every setting of the company.
+type UpdateCompanyPaymentMethodsRequest struct {
+	Methods []CompanyPaymentMethodInput `json:"methods" validate:"dive"`
+}
+
+// AvailablePaymentMethods picks the methods a payment in currency can use.
+// A method must be configured for the currency. Companies that configured
+// the currency get exactly the methods they enabled; otherwise every method
+// is offered except company credit, which a company has to enable.
+func AvailablePaymentMethods(gateways PaymentGatewayRegistry, settings []CompanyPaymentMethod, currency string) []PaymentGateway {
+	var configured bool
+	enabled := make(map[PaymentGateway]bool)
+	for _, setting := range settings {
+		if setting.Currency != currency {
+			continue
+		}
+		configured = true
+		enabled[setting.Gateway] = setting.Enabled
+	}
+
+	methods := []PaymentGateway{}
+	for _, gateway := range gateways.Gateways() {
+		if !gateways.Supports(gateway, currency) {
+			continue
+		}
+		if configured && !enabled[gateway] {
+			continue
+		}
+		if !configured && gateway == PaymentGatewayCompanyCredit {
+			continue
+		}
+		methods = append(methods, gateway)
+	}
+	return methods
+}
+
+type PaymentMethodRepository interface {
+	ListByCompany(companyID uuid.UUID) ([]CompanyPaymentMethod, error)
+	ReplaceForCompany(companyID uuid.UUID, methods []CompanyPaymentMethod) error
+}
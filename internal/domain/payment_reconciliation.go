@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentNotificationStatus string
+
+const (
+	PaymentNotificationReceived  PaymentNotificationStatus = "RECEIVED"
+	PaymentNotificationProcessed PaymentNotificationStatus = "PROCESSED"
+	PaymentNotificationIgnored   PaymentNotificationStatus = "IGNORED"
+	PaymentNotificationFailed    PaymentNotificationStatus = "FAILED"
+)
+
+// PaymentNotification is a gateway's notice that one of its payments
+// changed. Only what identifies the payment is taken from it: the status is
+// always read back from the gateway.
+type PaymentNotification struct {
+	ID        uuid.UUID                 `json:"id"`
+	Gateway   PaymentGateway            `json:"gateway"`
+	EventID   string                    `json:"event_id"`
+	EventType string                    `json:"event_type"`
+	Status    PaymentNotificationStatus `json:"status"`
+	// Set when the notification refers to one of our payments
+	PaymentID      *uuid.UUID             `json:"payment_id,omitempty"`
+	TransactionRef *string                `json:"transaction_ref,omitempty"`
+	Payload        map[string]interface{} `json:"payload,omitempty"`
+	Error          *string                `json:"error,omitempty"`
+	ReceivedAt     time.Time              `json:"received_at"`
+	ProcessedAt    *time.Time             `json:"processed_at,omitempty"`
+}
+
+// PaymentNotificationRequest is the HTTP request a gateway notified with.
+type PaymentNotificationRequest struct {
+	Header http.Header
+	Query  url.Values
+	Body   []byte
+}
+
+// PaymentNotificationVerifier is implemented by gateways that notify payment
+// changes. VerifyNotification returns ErrInvalidSignature unless the request
+// was signed with the configured secret.
+type PaymentNotificationVerifier interface {
+	VerifyNotification(req PaymentNotificationRequest) (*PaymentNotification, error)
+}
+
+type PaymentNotificationRepository interface {
+	// Create stores the notification unless the gateway already sent the
+	// same event, in which case it returns ErrAlreadyExists.
+	Create(notification *PaymentNotification) error
+	Update(notification *PaymentNotification) error
+}
+
+type PaymentMismatchKind string
+
+const (
+	PaymentMismatchStatus       PaymentMismatchKind = "STATUS"
+	PaymentMismatchAmount       PaymentMismatchKind = "AMOUNT"
+	PaymentMismatchGatewayError PaymentMismatchKind = "GATEWAY_ERROR"
+)
+
+// PaymentMismatch is a payment whose record disagrees with its gateway.
+type PaymentMismatch struct {
+	PaymentID      uuid.UUID           `json:"payment_id"`
+	ReservationID  string              `json:"reservation_id"`
+	Gateway        PaymentGateway      `json:"gateway"`
+	TransactionRef *string             `json:"transaction_ref,omitempty"`
+	Kind           PaymentMismatchKind `json:"kind"`
+	Status         PaymentStatus       `json:"status"`
+	GatewayStatus  PaymentStatus       `json:"gateway_status,omitempty"`
+	Amount         float64             `json:"amount"`
+	GatewayAmount  *float64            `json:"gateway_amount,omitempty"`
+	Detail         string              `json:"detail"`
+}
+
+// PaymentReconciliationReport compares the payments created in a period with
+// what their gateways report. Payments of gateways that cannot be queried
+// are skipped.
+type PaymentReconciliationReport struct {
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Gateway     *PaymentGateway    `json:"gateway,omitempty"`
+	Checked     int                `json:"checked"`
+	Matched     int                `json:"matched"`
+	Skipped     int                `json:"skipped"`
+	Mismatches  []*PaymentMismatch `json:"mismatches"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// PaymentReconciliationRun sums up one pass over the stale pending payments.
+type PaymentReconciliationRun struct {
+	Checked   int `json:"checked"`
+	Settled   int `json:"settled"`
+	Abandoned int `json:"abandoned"`
+	Pending   int `json:"pending"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+// ComparePayment returns how a payment disagrees with the status its gateway
+// reports, or nil when they agree. Gateways keep reporting a refunded charge
+// as paid, or a partial refund as refunded, so any captured status matches
+// any other. Amounts within half a unit match, since Webpay charges whole
+// pesos.
+func ComparePayment(payment *Payment, result *PaymentResult) *PaymentMismatch {
+	mismatch := &PaymentMismatch{
+		PaymentID:      payment.ID,
+		ReservationID:  payment.ReservationID,
+		Gateway:        payment.Gateway,
+		TransactionRef: payment.TransactionRef,
+		Status:         payment.Status,
+		GatewayStatus:  result.Status,
+		Amount:         payment.Amount,
+		GatewayAmount:  result.Amount,
+	}
+
+	if payment.Status != result.Status && !(isCapturedStatus(payment.Status) && isCapturedStatus(result.Status)) {
+		mismatch.Kind = PaymentMismatchStatus
+		mismatch.Detail = fmt.Sprintf("recorded as %s but the gateway reports %s", payment.Status, result.Status)
+		return mismatch
+	}
+
+	if result.Amount != nil && isCapturedStatus(result.Status) && math.Abs(*result.Amount-payment.Amount) > 0.5 {
+		mismatch.Kind = PaymentMismatchAmount
+		mismatch.Detail = fmt.Sprintf("recorded %.2f %s but the gateway charged %.2f", payment.Amount, payment.Currency, *result.Amount)
+		return mismatch
+	}
+
+	return nil
+}
+
+func isCapturedStatus(status PaymentStatus) bool {
+	switch status {
+	case PaymentStatusApproved, PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
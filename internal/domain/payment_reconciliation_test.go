@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePayment(t *testing.T) {
+	amount := func(v float64) *float64 { return &v }
+	payment := &Payment{Amount: 15990.4, Currency: "CLP", Status: PaymentStatusApproved}
+
+	// Webpay charges whole pesos
+	assert.Nil(t, ComparePayment(payment, &PaymentResult{Status: PaymentStatusApproved, Amount: amount(15990)}))
+	// A partial refund is reported as refunded by Webpay
+	payment.Status = PaymentStatusPartiallyRefunded
+	assert.Nil(t, ComparePayment(payment, &PaymentResult{Status: PaymentStatusRefunded}))
+
+	payment.Status = PaymentStatusPending
+	mismatch := ComparePayment(payment, &PaymentResult{Status: PaymentStatusApproved, Amount: amount(15990)})
+	require.NotNil(t, mismatch)
+	assert.Equal(t, PaymentMismatchStatus, mismatch.Kind)
+
+	payment.Status = PaymentStatusApproved
+	mismatch = ComparePayment(payment, &PaymentResult{Status: PaymentStatusApproved, Amount: amount(9990)})
+	require.NotNil(t, mismatch)
+	assert.Equal(t, PaymentMismatchAmount, mismatch.Kind)
+	assert.Equal(t, 9990.0, *mismatch.GatewayAmount)
+}
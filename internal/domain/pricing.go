@@ -75,3 +75,9 @@ type PricingRepository interface {
 	GetCurrencyRate(ctx context.Context, currencyCode string) (float64, error)
 	AuditQuote(ctx context.Context, request *PricingRequest, result *PricingResult) error
 }
+
+// FareSplitter divide una tarifa ya fijada entre la comisión de Turivo y el
+// pago al conductor
+type FareSplitter interface {
+	SplitFare(ctx context.Context, fare float64) (*PricingResult, error)
+}
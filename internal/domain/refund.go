@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "PENDING"
+	RefundStatusCompleted RefundStatus = "COMPLETED"
+	RefundStatusFailed    RefundStatus = "FAILED"
+)
+
+// Refund returns part or all of a captured payment. It is PENDING while the
+// gateway processes it and counts against the refundable amount until it
+// fails.
+type Refund struct {
+	ID             uuid.UUID              `json:"id"`
+	PaymentID      uuid.UUID              `json:"payment_id"`
+	Amount         float64                `json:"amount"`
+	Currency       string                 `json:"currency"`
+	Reason         string                 `json:"reason"`
+	Status         RefundStatus           `json:"status"`
+	TransactionRef *string                `json:"transaction_ref,omitempty"`
+	Payload        map[string]interface{} `json:"payload,omitempty"`
+	FailureReason  *string                `json:"failure_reason,omitempty"`
+	RequestedBy    *uuid.UUID             `json:"requested_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// CreateRefundRequest refunds Amount, or everything still refundable when it
+// is omitted.
+type CreateRefundRequest struct {
+	Amount *float64 `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	Reason string   `json:"reason" validate:"required,min=1,max=500"`
+}
+
+// RefundResult is what the gateway returns for an accepted refund.
+type RefundResult struct {
+	TransactionRef *string                `json:"transaction_ref,omitempty"`
+	Payload        map[string]interface{} `json:"payload"`
+	Message        string                 `json:"message"`
+}
+
+// PaymentRefunds reconciles a payment with its refunds. RefundedAmount only
+// counts completed refunds; RefundableAmount also discounts pending ones.
+type PaymentRefunds struct {
+	PaymentID        uuid.UUID     `json:"payment_id"`
+	Status           PaymentStatus `json:"status"`
+	Currency         string        `json:"currency"`
+	CapturedAmount   float64       `json:"captured_amount"`
+	RefundedAmount   float64       `json:"refunded_amount"`
+	RefundableAmount float64       `json:"refundable_amount"`
+	Refunds          []*Refund     `json:"refunds"`
+}
+
+// NewPaymentRefunds totals the refunds of a payment.
+func NewPaymentRefunds(payment *Payment, refunds []*Refund) *PaymentRefunds {
+	summary := &PaymentRefunds{
+		PaymentID:      payment.ID,
+		Status:         payment.Status,
+		Currency:       payment.Currency,
+		CapturedAmount: payment.Amount,
+		Refunds:        refunds,
+	}
+
+	var reserved float64
+	for _, refund := range refunds {
+		switch refund.Status {
+		case RefundStatusCompleted:
+			summary.RefundedAmount += refund.Amount
+			reserved += refund.Amount
+		case RefundStatusPending:
+			reserved += refund.Amount
+		}
+	}
+
+	summary.RefundedAmount = roundCents(summary.RefundedAmount)
+	if payment.IsCaptured() {
+		summary.RefundableAmount = math.Max(0, roundCents(payment.Amount-reserved))
+	}
+	return summary
+}
+
+// RefundedStatus is the payment status once RefundedAmount has been
+// returned.
+func (p *PaymentRefunds) RefundedStatus() PaymentStatus {
+	if p.RefundedAmount >= roundCents(p.CapturedAmount) {
+		return PaymentStatusRefunded
+	}
+	return PaymentStatusPartiallyRefunded
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+type RefundRepository interface {
+	// Create stores the refund as long as it and the payment's pending and
+	// completed refunds add up to at most capturedAmount, returning
+	// ErrRefundExceedsPayment otherwise.
+	Create(refund *Refund, capturedAmount float64) error
+	Update(refund *Refund) error
+	ListByPayment(paymentID uuid.UUID) ([]*Refund, error)
+}
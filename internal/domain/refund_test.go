@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaymentRefunds(t *testing.T) {
+	payment := &Payment{Amount: 10000, Currency: "CLP", Status: PaymentStatusApproved}
+	refunds := []*Refund{
+		{Amount: 2500, Status: RefundStatusCompleted},
+		{Amount: 1000, Status: RefundStatusPending},
+		{Amount: 4000, Status: RefundStatusFailed},
+	}
+
+	summary := NewPaymentRefunds(payment, refunds)
+	assert.Equal(t, 2500.0, summary.RefundedAmount)
+	assert.Equal(t, 6500.0, summary.RefundableAmount)
+	assert.Equal(t, PaymentStatusPartiallyRefunded, summary.RefundedStatus())
+
+	refunds[1].Status = RefundStatusCompleted
+	refunds = append(refunds, &Refund{Amount: 6500, Status: RefundStatusCompleted})
+	summary = NewPaymentRefunds(payment, refunds)
+	assert.Equal(t, 10000.0, summary.RefundedAmount)
+	assert.Equal(t, 0.0, summary.RefundableAmount)
+	assert.Equal(t, PaymentStatusRefunded, summary.RefundedStatus())
+}
+
+func TestNewPaymentRefunds_NotCaptured(t *testing.T) {
+	payment := &Payment{Amount: 10000, Status: PaymentStatusPending}
+
+	summary := NewPaymentRefunds(payment, nil)
+	assert.Equal(t, 0.0, summary.RefundableAmount)
+}
@@ -23,6 +23,8 @@ type Reservation struct {
 	Destination      string            `json:"destination"`
 	DateTime         time.Time         `json:"datetime"`
 	Passengers       int               `json:"passengers"`
+	VehicleType      *VehicleType      `json:"vehicle_type,omitempty"`
+	CostCenter       *string           `json:"cost_center,omitempty"` // Company cost center the trip is billed to
 	Status           ReservationStatus `json:"status"`
 	Amount           *float64          `json:"amount,omitempty"`
 	DistanceKM       *float64          `json:"distance_km,omitempty"`
@@ -31,12 +33,19 @@ type Reservation struct {
 	CreatedAt        time.Time         `json:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at"`
 
+	// Split of Amount taken when the price is locked; the driver is paid
+	// from it. Reservations booked before the split was stored have none.
+	Commission   *float64 `json:"-"`
+	DriverPayout *float64 `json:"-"`
+	Currency     *string  `json:"-"`
+
 	// Related data
 	User           *User            `json:"user,omitempty"`
 	AssignedDriver *Driver          `json:"assigned_driver,omitempty"`
 	Timeline       []TimelineEvent  `json:"timeline,omitempty"`
 	Payments       []Payment        `json:"payments,omitempty"`
 	Feedback       []DriverFeedback `json:"feedback,omitempty"`
+	Trip           *TripProgress    `json:"trip,omitempty"`
 }
 
 type TimelineEvent struct {
@@ -56,6 +65,7 @@ type CreateReservationRequest struct {
 	Destination string     `json:"destination" validate:"required,min=5,max=500"`
 	DateTime    time.Time  `json:"datetime" validate:"required"`
 	Passengers  int        `json:"passengers" validate:"required,min=1"`
+	CostCenter  *string    `json:"cost_center,omitempty" validate:"omitempty,max=100"`
 	Notes       *string    `json:"notes,omitempty" validate:"omitempty,max=1000"`
 }
 
@@ -103,6 +113,26 @@ func (r *Reservation) CanTransitionTo(newStatus ReservationStatus) bool {
 	return false
 }
 
+// ReservationViewer identifies who is trying to read a reservation.
+type ReservationViewer struct {
+	UserID uuid.UUID
+	Role   UserRole
+	OrgID  *uuid.UUID
+}
+
+// IsVisibleTo applies the reservation read scope: admins see everything,
+// organization members see their organization's reservations and everyone
+// else only sees the reservations they booked.
+func (r *Reservation) IsVisibleTo(viewer ReservationViewer) bool {
+	if viewer.Role == UserRoleAdmin {
+		return true
+	}
+	if viewer.OrgID != nil && r.OrgID != nil && *viewer.OrgID == *r.OrgID {
+		return true
+	}
+	return r.UserID != nil && *r.UserID == viewer.UserID
+}
+
 // Price calculation helper
 func (r *Reservation) CalculatePrice(vehicleType VehicleType, hasSpecialLanguage bool, stops int) float64 {
 	// Base price by vehicle type (aligned with frontend)
@@ -167,6 +197,10 @@ type ReservationRepository interface {
 	Update(id string, req UpdateReservationRequest) (*Reservation, error)
 	Delete(id string) error
 	AssignDriver(id string, driverID string) error
+	UnassignDriver(id string) error
+	// ListUpcomingIDsByDriver returns the IDs of the open reservations of a
+	// driver picking up at or after from, earliest first.
+	ListUpcomingIDsByDriver(driverID string, from time.Time) ([]string, error)
 	ChangeStatus(id string, newStatus ReservationStatus) error
 	GetTimeline(id string) ([]TimelineEvent, error)
 	AddTimelineEvent(id string, event TimelineEvent) error
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShiftStatus string
+
+const (
+	ShiftStatusScheduled ShiftStatus = "SCHEDULED"
+	ShiftStatusCancelled ShiftStatus = "CANCELLED"
+)
+
+// Shift is a planned working window of a driver, optionally tied to a
+// vehicle and a region. Shifts of the same driver or vehicle never overlap.
+type Shift struct {
+	ID        uuid.UUID   `json:"id"`
+	DriverID  string      `json:"driver_id"`
+	VehicleID *string     `json:"vehicle_id,omitempty"`
+	StartsAt  time.Time   `json:"starts_at"`
+	EndsAt    time.Time   `json:"ends_at"`
+	Region    *string     `json:"region,omitempty"`
+	Status    ShiftStatus `json:"status"`
+	Notes     *string     `json:"notes,omitempty"`
+	CreatedBy *uuid.UUID  `json:"created_by,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Covers reports whether t falls within the shift.
+func (s *Shift) Covers(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+type ShiftChangeType string
+
+const (
+	// ShiftChangeSwap hands a shift over to another driver, optionally taking
+	// one of theirs in exchange.
+	ShiftChangeSwap ShiftChangeType = "SWAP"
+	// ShiftChangeTimeOff blocks a period; approving it cancels the driver's
+	// shifts in that period.
+	ShiftChangeTimeOff ShiftChangeType = "TIME_OFF"
+)
+
+type ShiftChangeStatus string
+
+const (
+	ShiftChangeStatusPending   ShiftChangeStatus = "PENDING"
+	ShiftChangeStatusApproved  ShiftChangeStatus = "APPROVED"
+	ShiftChangeStatusRejected  ShiftChangeStatus = "REJECTED"
+	ShiftChangeStatusCancelled ShiftChangeStatus = "CANCELLED"
+)
+
+// ShiftChangeRequest is a swap or time-off request raised by a driver and
+// decided by an admin.
+type ShiftChangeRequest struct {
+	ID             uuid.UUID         `json:"id"`
+	Type           ShiftChangeType   `json:"type"`
+	DriverID       string            `json:"driver_id"`
+	ShiftID        *uuid.UUID        `json:"shift_id,omitempty"`
+	TargetDriverID *string           `json:"target_driver_id,omitempty"`
+	TargetShiftID  *uuid.UUID        `json:"target_shift_id,omitempty"`
+	StartsAt       *time.Time        `json:"starts_at,omitempty"`
+	EndsAt         *time.Time        `json:"ends_at,omitempty"`
+	Reason         *string           `json:"reason,omitempty"`
+	Status         ShiftChangeStatus `json:"status"`
+	ReviewNote     *string           `json:"review_note,omitempty"`
+	ReviewedBy     *uuid.UUID        `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+type CreateShiftRequest struct {
+	DriverID  string    `json:"driver_id" validate:"required,max=20"`
+	VehicleID *string   `json:"vehicle_id,omitempty" validate:"omitempty,uuid"`
+	StartsAt  time.Time `json:"starts_at" validate:"required"`
+	EndsAt    time.Time `json:"ends_at" validate:"required"`
+	Region    *string   `json:"region,omitempty" validate:"omitempty,oneof=XV I II III IV V RM VI VII XVI VIII IX XIV X XI XII"`
+	Notes     *string   `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+type UpdateShiftRequest struct {
+	VehicleID *string      `json:"vehicle_id,omitempty" validate:"omitempty,uuid"`
+	StartsAt  *time.Time   `json:"starts_at,omitempty"`
+	EndsAt    *time.Time   `json:"ends_at,omitempty"`
+	Region    *string      `json:"region,omitempty" validate:"omitempty,oneof=XV I II III IV V RM VI VII XVI VIII IX XIV X XI XII"`
+	Status    *ShiftStatus `json:"status,omitempty" validate:"omitempty,oneof=SCHEDULED CANCELLED"`
+	Notes     *string      `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+type ListShiftsRequest struct {
+	DriverID  *string      `json:"driver_id,omitempty"`
+	VehicleID *string      `json:"vehicle_id,omitempty"`
+	Status    *ShiftStatus `json:"status,omitempty"`
+	From      *time.Time   `json:"from,omitempty"`
+	To        *time.Time   `json:"to,omitempty"`
+	Page      int          `json:"page" validate:"min=1"`
+	PageSize  int          `json:"page_size" validate:"min=1,max=100"`
+}
+
+// SubmitShiftChangeRequest is sent by a driver. Swaps need ShiftID and
+// TargetDriverID (TargetShiftID makes it an exchange); time off needs
+// StartsAt and EndsAt.
+type SubmitShiftChangeRequest struct {
+	Type           ShiftChangeType `json:"type" validate:"required,oneof=SWAP TIME_OFF"`
+	ShiftID        *uuid.UUID      `json:"shift_id,omitempty" validate:"required_if=Type SWAP"`
+	TargetDriverID *string         `json:"target_driver_id,omitempty" validate:"required_if=Type SWAP,omitempty,max=20"`
+	TargetShiftID  *uuid.UUID      `json:"target_shift_id,omitempty"`
+	StartsAt       *time.Time      `json:"starts_at,omitempty" validate:"required_if=Type TIME_OFF"`
+	EndsAt         *time.Time      `json:"ends_at,omitempty" validate:"required_if=Type TIME_OFF"`
+	Reason         *string         `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+type ReviewShiftChangeRequest struct {
+	Status ShiftChangeStatus `json:"status" validate:"required,oneof=APPROVED REJECTED"`
+	Note   *string           `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+type ListShiftChangesRequest struct {
+	DriverID *string            `json:"driver_id,omitempty"`
+	Type     *ShiftChangeType   `json:"type,omitempty"`
+	Status   *ShiftChangeStatus `json:"status,omitempty"`
+	Page     int                `json:"page" validate:"min=1"`
+	PageSize int                `json:"page_size" validate:"min=1,max=100"`
+}
+
+type ShiftRepository interface {
+	Create(shift *Shift) error
+	GetByID(id uuid.UUID) (*Shift, error)
+	Update(shift *Shift) error
+	List(req ListShiftsRequest) ([]*Shift, int, error)
+	ListByDriver(driverID string, from, to time.Time) ([]*Shift, error)
+	FindCovering(driverID string, at time.Time) (*Shift, error)
+	HasDriverOverlap(driverID string, startsAt, endsAt time.Time, excludeID *uuid.UUID) (bool, error)
+	HasVehicleOverlap(vehicleID string, startsAt, endsAt time.Time, excludeID *uuid.UUID) (bool, error)
+	CancelDriverShifts(driverID string, from, to time.Time) (int, error)
+	// SwapDrivers hands shiftID over to toDriverID and, when exchangeShiftID
+	// is set, that shift back to the original driver, in one transaction.
+	SwapDrivers(shiftID uuid.UUID, toDriverID string, exchangeShiftID *uuid.UUID, fromDriverID string) error
+
+	// Swap and time-off requests
+	CreateChange(change *ShiftChangeRequest) error
+	GetChange(id uuid.UUID) (*ShiftChangeRequest, error)
+	ListChanges(req ListShiftChangesRequest) ([]*ShiftChangeRequest, int, error)
+	ReviewChange(id uuid.UUID, status ShiftChangeStatus, note *string, reviewedBy uuid.UUID, reviewedAt time.Time) (*ShiftChangeRequest, error)
+	CancelChange(id uuid.UUID, driverID string) (*ShiftChangeRequest, error)
+	HasApprovedTimeOff(driverID string, startsAt, endsAt time.Time) (bool, error)
+}
+
+// ShiftChecker guards assignments on behalf of the dispatch and reservation
+// flows.
+type ShiftChecker interface {
+	EnsureDriverOnShift(driverID string, at time.Time) error
+}
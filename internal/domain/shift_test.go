@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftCovers(t *testing.T) {
+	shift := &Shift{
+		StartsAt: time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC),
+	}
+
+	assert.True(t, shift.Covers(shift.StartsAt))
+	assert.True(t, shift.Covers(time.Date(2026, 10, 19, 15, 59, 0, 0, time.UTC)))
+	assert.False(t, shift.Covers(shift.EndsAt))
+	assert.False(t, shift.Covers(time.Date(2026, 10, 19, 7, 59, 0, 0, time.UTC)))
+}
@@ -0,0 +1,437 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DTEType is the SII code of an electronic tax document (Documento
+// Tributario Electrónico).
+type DTEType int
+
+const (
+	DTETypeFactura       DTEType = 33
+	DTETypeFacturaExenta DTEType = 34
+	DTETypeBoleta        DTEType = 39
+	DTETypeNotaCredito   DTEType = 61
+)
+
+func (t DTEType) Valid() bool {
+	switch t {
+	case DTETypeFactura, DTETypeFacturaExenta, DTETypeBoleta, DTETypeNotaCredito:
+		return true
+	}
+	return false
+}
+
+// Name is how the document type reads on documents and references.
+func (t DTEType) Name() string {
+	switch t {
+	case DTETypeFactura:
+		return "Factura Electrónica"
+	case DTETypeFacturaExenta:
+		return "Factura No Afecta o Exenta Electrónica"
+	case DTETypeBoleta:
+		return "Boleta Electrónica"
+	case DTETypeNotaCredito:
+		return "Nota de Crédito Electrónica"
+	}
+	return strconv.Itoa(int(t))
+}
+
+// Credit note reference codes (CodRef).
+const (
+	DTEReferenceVoid          = 1
+	DTEReferenceCorrectAmount = 3
+)
+
+// MaxTaxDocumentLines is how many detail lines the SII schema allows on a
+// document.
+const MaxTaxDocumentLines = 60
+
+// Receiver of boletas issued to final consumers.
+const (
+	FinalConsumerRUT  = "66666666-6"
+	FinalConsumerName = "Consumidor Final"
+)
+
+type TaxDocumentStatus string
+
+const (
+	// TaxDocumentStatusSigned documents are stamped and signed but not sent
+	TaxDocumentStatusSigned    TaxDocumentStatus = "SIGNED"
+	TaxDocumentStatusSubmitted TaxDocumentStatus = "SUBMITTED"
+	TaxDocumentStatusAccepted  TaxDocumentStatus = "ACCEPTED"
+	TaxDocumentStatusRejected  TaxDocumentStatus = "REJECTED"
+)
+
+// TaxDocument is an electronic tax document issued for an invoice (factura),
+// a reservation payment (boleta) or to credit one of those (nota de
+// crédito). Amounts are whole pesos. XML holds the signed document as sent
+// to the tax authority and TED the stamp printed as a PDF417 barcode on its
+// printed representation.
+type TaxDocument struct {
+	ID           uuid.UUID         `json:"id"`
+	Type         DTEType           `json:"type"`
+	Folio        int               `json:"folio"`
+	FolioRangeID uuid.UUID         `json:"folio_range_id"`
+	IssueDate    time.Time         `json:"issue_date"`
+	InvoiceID    *uuid.UUID        `json:"invoice_id,omitempty"`
+	PaymentID    *uuid.UUID        `json:"payment_id,omitempty"`
+	ReferenceID  *uuid.UUID        `json:"reference_id,omitempty"`
+	ReceiverRUT  string            `json:"receiver_rut"`
+	ReceiverName string            `json:"receiver_name"`
+	NetAmount    int64             `json:"net_amount"`
+	ExemptAmount int64             `json:"exempt_amount"`
+	TaxRate      float64           `json:"tax_rate"`
+	TaxAmount    int64             `json:"tax_amount"`
+	TotalAmount  int64             `json:"total_amount"`
+	Status       TaxDocumentStatus `json:"status"`
+	TrackID      *string           `json:"track_id,omitempty"`
+	StatusDetail *string           `json:"status_detail,omitempty"`
+	SubmittedAt  *time.Time        `json:"submitted_at,omitempty"`
+	TED          string            `json:"ted,omitempty"`
+	XML          []byte            `json:"-"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+
+	// Set while the document is built; the signed XML keeps them afterwards
+	Lines      []*TaxDocumentLine    `json:"lines,omitempty"`
+	Reference  *TaxDocumentReference `json:"reference,omitempty"`
+	PeriodFrom *time.Time            `json:"period_from,omitempty"`
+	PeriodTo   *time.Time            `json:"period_to,omitempty"`
+	DueDate    *time.Time            `json:"due_date,omitempty"`
+}
+
+// TaxDocumentLine is a detail line. Prices are net on facturas and credit
+// notes and include tax on boletas, as the SII expects.
+type TaxDocumentLine struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+// TaxDocumentReference is the document a credit note corrects.
+type TaxDocumentReference struct {
+	Type   DTEType   `json:"type"`
+	Folio  int       `json:"folio"`
+	Date   time.Time `json:"date"`
+	Code   int       `json:"code"`
+	Reason string    `json:"reason"`
+}
+
+// IsExempt reports whether the document carries no VAT.
+func (d *TaxDocument) IsExempt() bool {
+	return d.TaxRate == 0
+}
+
+// NewInvoiceTaxDocument issues a factura for an invoice: type 33 with VAT or
+// 34 when the invoice is exempt. Only CLP invoices can be documented. An
+// invoice with more lines than the document allows keeps the first ones and
+// groups the rest into its last line.
+func NewInvoiceTaxDocument(invoice *Invoice, company *Company, issueDate time.Time) (*TaxDocument, error) {
+	if invoice.Currency != "CLP" {
+		return nil, ErrTaxDocumentCurrency
+	}
+	rut, ok := NormalizeRUT(company.RUT)
+	if !ok {
+		return nil, ErrInvalidRUT
+	}
+
+	invoiceID := invoice.ID
+	periodFrom := invoice.PeriodStart
+	// Periods end exclusively; the document shows the last day billed
+	periodTo := invoice.PeriodEnd.AddDate(0, 0, -1)
+	dueDate := invoice.DueDate
+	doc := &TaxDocument{
+		Type:         DTETypeFactura,
+		IssueDate:    issueDate,
+		InvoiceID:    &invoiceID,
+		ReceiverRUT:  rut,
+		ReceiverName: company.Name,
+		TaxRate:      invoice.TaxRate,
+		PeriodFrom:   &periodFrom,
+		PeriodTo:     &periodTo,
+		DueDate:      &dueDate,
+	}
+	if invoice.TaxRate == 0 {
+		doc.Type = DTETypeFacturaExenta
+	}
+
+	var subtotal int64
+	for _, line := range invoice.Lines {
+		quantity := line.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		amount := int64(math.Round(line.Amount))
+		doc.Lines = append(doc.Lines, &TaxDocumentLine{
+			Description: line.Description,
+			Quantity:    quantity,
+			UnitPrice:   int64(math.Round(line.UnitPrice)),
+			Amount:      amount,
+		})
+		subtotal += amount
+	}
+	doc.Lines = groupTaxDocumentLines(doc.Lines)
+
+	doc.setNetTotals(subtotal)
+	return doc, nil
+}
+
+// groupTaxDocumentLines merges the lines past the limit into one.
+func groupTaxDocumentLines(lines []*TaxDocumentLine) []*TaxDocumentLine {
+	if len(lines) <= MaxTaxDocumentLines {
+		return lines
+	}
+
+	rest := lines[MaxTaxDocumentLines-1:]
+	var amount int64
+	for _, line := range rest {
+		amount += line.Amount
+	}
+
+	return append(lines[:MaxTaxDocumentLines-1:MaxTaxDocumentLines-1], &TaxDocumentLine{
+		Description: fmt.Sprintf("Otros %d servicios de transporte del período", len(rest)),
+		Quantity:    1,
+		UnitPrice:   amount,
+		Amount:      amount,
+	})
+}
+
+// NewPaymentTaxDocument issues a boleta to a final consumer for a captured
+// reservation payment. The amount paid includes VAT at taxRate.
+func NewPaymentTaxDocument(payment *Payment, taxRate float64, issueDate time.Time) (*TaxDocument, error) {
+	if payment.Currency != "CLP" {
+		return nil, ErrTaxDocumentCurrency
+	}
+
+	paymentID := payment.ID
+	total := int64(math.Round(payment.Amount))
+	doc := &TaxDocument{
+		Type:         DTETypeBoleta,
+		IssueDate:    issueDate,
+		PaymentID:    &paymentID,
+		ReceiverRUT:  FinalConsumerRUT,
+		ReceiverName: FinalConsumerName,
+		TaxRate:      taxRate,
+		Lines: []*TaxDocumentLine{{
+			Description: "Servicio de transporte reserva " + payment.ReservationID,
+			Quantity:    1,
+			UnitPrice:   total,
+			Amount:      total,
+		}},
+	}
+
+	doc.setGrossTotals(total)
+	return doc, nil
+}
+
+// NewCreditNote credits amount, tax included, of the original document.
+// Crediting the whole document voids it; a smaller amount corrects it.
+func NewCreditNote(original *TaxDocument, amount int64, reason string, issueDate time.Time) *TaxDocument {
+	originalID := original.ID
+	code := DTEReferenceCorrectAmount
+	description := "Corrige montos " + original.Type.Name() + " N° " + strconv.Itoa(original.Folio)
+	if amount == original.TotalAmount {
+		code = DTEReferenceVoid
+		description = "Anula " + original.Type.Name() + " N° " + strconv.Itoa(original.Folio)
+	}
+
+	doc := &TaxDocument{
+		Type:         DTETypeNotaCredito,
+		IssueDate:    issueDate,
+		InvoiceID:    original.InvoiceID,
+		PaymentID:    original.PaymentID,
+		ReferenceID:  &originalID,
+		ReceiverRUT:  original.ReceiverRUT,
+		ReceiverName: original.ReceiverName,
+		TaxRate:      original.TaxRate,
+		Reference: &TaxDocumentReference{
+			Type:   original.Type,
+			Folio:  original.Folio,
+			Date:   original.IssueDate,
+			Code:   code,
+			Reason: reason,
+		},
+	}
+
+	doc.setGrossTotals(amount)
+	// Credit note lines are net, like the totals
+	lineAmount := doc.NetAmount + doc.ExemptAmount
+	doc.Lines = []*TaxDocumentLine{{
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   lineAmount,
+		Amount:      lineAmount,
+	}}
+	return doc
+}
+
+// setNetTotals adds VAT to a net subtotal.
+func (d *TaxDocument) setNetTotals(subtotal int64) {
+	if d.IsExempt() {
+		d.ExemptAmount = subtotal
+		d.TotalAmount = subtotal
+		return
+	}
+	d.NetAmount = subtotal
+	d.TaxAmount = int64(math.Round(float64(subtotal) * d.TaxRate))
+	d.TotalAmount = d.NetAmount + d.TaxAmount
+}
+
+// setGrossTotals splits a total that includes VAT into net and tax.
+func (d *TaxDocument) setGrossTotals(total int64) {
+	d.TotalAmount = total
+	if d.IsExempt() {
+		d.ExemptAmount = total
+		return
+	}
+	d.NetAmount = int64(math.Round(float64(total) / (1 + d.TaxRate)))
+	d.TaxAmount = total - d.NetAmount
+}
+
+// NormalizeRUT returns a Chilean RUT as the SII writes it, without dots and
+// with an uppercase check digit (12345678-5), and whether its check digit is
+// right.
+func NormalizeRUT(rut string) (string, bool) {
+	rut = strings.ToUpper(strings.NewReplacer(".", "", " ", "").Replace(rut))
+	body, dv, found := strings.Cut(rut, "-")
+	if !found {
+		if len(rut) < 2 {
+			return "", false
+		}
+		body, dv = rut[:len(rut)-1], rut[len(rut)-1:]
+	}
+
+	number, err := strconv.Atoi(body)
+	if err != nil || number <= 0 || len(dv) != 1 {
+		return "", false
+	}
+	if rutCheckDigit(number) != dv {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d-%s", number, dv), true
+}
+
+func rutCheckDigit(number int) string {
+	sum, factor := 0, 2
+	for ; number > 0; number /= 10 {
+		sum += number % 10 * factor
+		factor++
+		if factor > 7 {
+			factor = 2
+		}
+	}
+
+	switch digit := 11 - sum%11; digit {
+	case 11:
+		return "0"
+	case 10:
+		return "K"
+	default:
+		return strconv.Itoa(digit)
+	}
+}
+
+// FolioRange is a CAF (Código de Autorización de Folios): a range of folios
+// the SII authorized for one document type, with the key that stamps them.
+// Folios are used in order from Next up to To.
+type FolioRange struct {
+	ID           uuid.UUID `json:"id"`
+	Type         DTEType   `json:"type"`
+	From         int       `json:"from"`
+	To           int       `json:"to"`
+	Next         int       `json:"next"`
+	AuthorizedAt time.Time `json:"authorized_at"`
+	CAF          []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Remaining is how many folios of the range are left.
+func (r *FolioRange) Remaining() int {
+	if r.Next > r.To {
+		return 0
+	}
+	return r.To - r.Next + 1
+}
+
+type IssueTaxDocumentRequest struct {
+	InvoiceID *uuid.UUID `json:"invoice_id" validate:"required_without=PaymentID,excluded_with=PaymentID"`
+	PaymentID *uuid.UUID `json:"payment_id"`
+}
+
+// CreateCreditNoteRequest credits a document. Without amount the whole
+// document is voided; amount includes tax.
+type CreateCreditNoteRequest struct {
+	Amount *int64 `json:"amount" validate:"omitempty,min=1"`
+	Reason string `json:"reason" validate:"required,max=90"`
+}
+
+type ListTaxDocumentsRequest struct {
+	Type      *DTEType
+	Status    *TaxDocumentStatus
+	InvoiceID *uuid.UUID
+	PaymentID *uuid.UUID
+	Page      int
+	PageSize  int
+}
+
+// TaxAuthorityStatus is the outcome of a submission. Status stays SUBMITTED
+// while the tax authority is still processing it.
+type TaxAuthorityStatus struct {
+	Status TaxDocumentStatus
+	Detail string
+}
+
+// TaxAuthorityClient sends signed document envelopes to the tax authority
+// (SII) and checks how they were received.
+type TaxAuthorityClient interface {
+	// Submit uploads an envelope and returns its track ID.
+	Submit(envelope []byte) (string, error)
+	GetStatus(trackID string) (*TaxAuthorityStatus, error)
+}
+
+// TaxDocumentBuilder renders documents as SII XML.
+type TaxDocumentBuilder interface {
+	// ParseCAF validates a folio authorization file issued to the issuer.
+	ParseCAF(raw []byte) (*FolioRange, error)
+	// Build stamps the document, whose folio is set, with the key of its
+	// CAF and signs it, setting XML and TED.
+	Build(doc *TaxDocument, caf *FolioRange) error
+	// Envelope wraps signed documents into a signed submission. Boletas
+	// are sent apart from the other types.
+	Envelope(docs []*TaxDocument) ([]byte, error)
+}
+
+type TaxDocumentRepository interface {
+	// CreateFolioRange returns ErrAlreadyExists if the range overlaps one
+	// stored for the type.
+	CreateFolioRange(folioRange *FolioRange) error
+	ListFolioRanges(docType *DTEType) ([]*FolioRange, error)
+
+	// Issue takes the next folio of the oldest range of the document type
+	// with folios left, lets build render the document with it and stores
+	// it, in one transaction so folios are not lost when building fails.
+	// It returns ErrFoliosExhausted if no range has folios left and
+	// ErrAlreadyExists if the invoice or payment already has its document.
+	Issue(doc *TaxDocument, build func(doc *TaxDocument, caf *FolioRange) error) error
+	GetByID(id uuid.UUID) (*TaxDocument, error)
+	List(req ListTaxDocumentsRequest) ([]*TaxDocument, int, error)
+	// CreditedAmount is the total of the credit notes of a document.
+	CreditedAmount(id uuid.UUID) (int64, error)
+
+	// ListByStatus returns up to limit documents, with their XML, oldest
+	// first.
+	ListByStatus(status TaxDocumentStatus, limit int) ([]*TaxDocument, error)
+	MarkSubmitted(ids []uuid.UUID, trackID string, submittedAt time.Time) error
+	// UpdateStatus records the outcome of the submission with the track ID.
+	UpdateStatus(trackID string, status TaxDocumentStatus, detail string) error
+}
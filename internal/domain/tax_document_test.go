@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeRUT(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"11.111.111-1", "11111111-1", true},
+		{"60803000-k", "60803000-K", true},
+		{"666666666", "66666666-6", true},
+		{"11.111.111-2", "", false},
+		{"abc-1", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeRUT(tt.input)
+		assert.Equal(t, tt.valid, ok, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
+
+func TestNewInvoiceTaxDocument(t *testing.T) {
+	invoice := &Invoice{
+		ID:          uuid.New(),
+		Currency:    "CLP",
+		TaxRate:     0.19,
+		PeriodStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []*InvoiceLine{
+			{Description: "Viaje RSV-1", Quantity: 1, UnitPrice: 25000, Amount: 25000},
+			{Description: "Viaje RSV-2", Quantity: 1, UnitPrice: 18001, Amount: 18001},
+		},
+	}
+	company := &Company{Name: "Minera Norte", RUT: "11.111.111-1"}
+
+	doc, err := NewInvoiceTaxDocument(invoice, company, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, DTETypeFactura, doc.Type)
+	assert.Equal(t, "11111111-1", doc.ReceiverRUT)
+	assert.Equal(t, int64(43001), doc.NetAmount)
+	assert.Equal(t, int64(8170), doc.TaxAmount)
+	assert.Equal(t, int64(51171), doc.TotalAmount)
+	assert.Equal(t, time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC), *doc.PeriodTo)
+	require.Len(t, doc.Lines, 2)
+
+	invoice.TaxRate = 0
+	doc, err = NewInvoiceTaxDocument(invoice, company, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, DTETypeFacturaExenta, doc.Type)
+	assert.Equal(t, int64(43001), doc.ExemptAmount)
+	assert.Equal(t, int64(43001), doc.TotalAmount)
+
+	invoice.Currency = "USD"
+	_, err = NewInvoiceTaxDocument(invoice, company, time.Now())
+	assert.Equal(t, ErrTaxDocumentCurrency, err)
+}
+
+func TestNewInvoiceTaxDocumentGroupsLinesOverTheLimit(t *testing.T) {
+	invoice := &Invoice{
+		ID:          uuid.New(),
+		Currency:    "CLP",
+		TaxRate:     0.19,
+		PeriodStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for i := 1; i <= 75; i++ {
+		invoice.Lines = append(invoice.Lines, &InvoiceLine{
+			Description: fmt.Sprintf("Viaje RSV-%d", i),
+			Quantity:    1,
+			UnitPrice:   10000,
+			Amount:      10000,
+		})
+	}
+	company := &Company{Name: "Minera Norte", RUT: "11.111.111-1"}
+
+	doc, err := NewInvoiceTaxDocument(invoice, company, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Len(t, doc.Lines, MaxTaxDocumentLines)
+	assert.Equal(t, "Viaje RSV-59", doc.Lines[58].Description)
+	last := doc.Lines[MaxTaxDocumentLines-1]
+	assert.Equal(t, "Otros 16 servicios de transporte del período", last.Description)
+	assert.Equal(t, int64(160000), last.Amount)
+	assert.Equal(t, last.Amount, int64(last.Quantity)*last.UnitPrice)
+
+	var sum int64
+	for _, line := range doc.Lines {
+		sum += line.Amount
+	}
+	assert.Equal(t, int64(750000), sum)
+	assert.Equal(t, sum, doc.NetAmount)
+	assert.Len(t, invoice.Lines, 75)
+}
+
+func TestNewPaymentTaxDocumentAndCreditNote(t *testing.T) {
+	payment := &Payment{ID: uuid.New(), ReservationID: "RSV-9", Amount: 11900, Currency: "CLP"}
+
+	boleta, err := NewPaymentTaxDocument(payment, 0.19, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, DTETypeBoleta, boleta.Type)
+	assert.Equal(t, FinalConsumerRUT, boleta.ReceiverRUT)
+	assert.Equal(t, int64(10000), boleta.NetAmount)
+	assert.Equal(t, int64(1900), boleta.TaxAmount)
+	assert.Equal(t, int64(11900), boleta.Lines[0].Amount)
+
+	boleta.ID = uuid.New()
+	boleta.Folio = 7
+
+	partial := NewCreditNote(boleta, 5950, "Devolución parcial", time.Now())
+	assert.Equal(t, DTETypeNotaCredito, partial.Type)
+	assert.Equal(t, DTEReferenceCorrectAmount, partial.Reference.Code)
+	assert.Equal(t, 7, partial.Reference.Folio)
+	assert.Equal(t, int64(5000), partial.NetAmount)
+	assert.Equal(t, int64(950), partial.TaxAmount)
+	assert.Equal(t, int64(5000), partial.Lines[0].Amount)
+
+	full := NewCreditNote(boleta, boleta.TotalAmount, "Anulación", time.Now())
+	assert.Equal(t, DTEReferenceVoid, full.Reference.Code)
+	assert.Equal(t, boleta.ID, *full.ReferenceID)
+}
+
+func TestFolioRangeRemaining(t *testing.T) {
+	folios := &FolioRange{From: 1, To: 50, Next: 48}
+	assert.Equal(t, 3, folios.Remaining())
+
+	folios.Next = 51
+	assert.Equal(t, 0, folios.Remaining())
+}
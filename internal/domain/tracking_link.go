@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// averageTripSpeedKMH is used to estimate drop-off times from the trip distance.
+const averageTripSpeedKMH = 40.0
+
+// TrackingLink is a revocable grant that lets anyone holding its signed token
+// follow a reservation without an account.
+type TrackingLink struct {
+	ID            uuid.UUID  `json:"id"`
+	ReservationID string     `json:"reservation_id"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (l *TrackingLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+type TrackingLinkResponse struct {
+	Link  *TrackingLink `json:"link"`
+	Token string        `json:"token"`
+}
+
+// PublicTrackingView is the reduced reservation view exposed through tracking
+// links. It deliberately leaves out passenger, pricing and contact data.
+type PublicTrackingView struct {
+	ReservationID   string              `json:"reservation_id"`
+	Status          ReservationStatus   `json:"status"`
+	TripStatus      *DriverTripStatus   `json:"trip_status,omitempty"`
+	ScheduledAt     time.Time           `json:"scheduled_at"`
+	DriverFirstName *string             `json:"driver_first_name,omitempty"`
+	Vehicle         *PublicVehicleView  `json:"vehicle,omitempty"`
+	ETA             *time.Time          `json:"eta,omitempty"`
+	Timeline        []PublicTimelineRow `json:"timeline"`
+}
+
+type PublicVehicleView struct {
+	Brand string  `json:"brand"`
+	Model string  `json:"model"`
+	Color *string `json:"color,omitempty"`
+	Plate *string `json:"plate,omitempty"`
+}
+
+type PublicTimelineRow struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	At          time.Time `json:"at"`
+	Variant     string    `json:"variant"`
+}
+
+// publicTimelineDescriptions lists the timeline events shown through tracking
+// links, keyed by title, with the description shown to the guest. Stored
+// descriptions are never exposed since they can carry driver names, refund
+// amounts and operator notes; every other event stays internal.
+var publicTimelineDescriptions = map[string]string{
+	"Reserva creada":      "La reserva ha sido creada",
+	"Reserva programada":  "La reserva ha sido programada",
+	"Conductor asignado":  "Se asignó un conductor a la reserva",
+	"Conductor en camino": "El conductor se dirige al punto de origen",
+	"Conductor en origen": "El conductor llegó al punto de origen",
+	"Pasajero a bordo":    "El pasajero subió al vehículo",
+	"Viaje finalizado":    "El conductor finalizó el viaje",
+	"Reserva completada":  "El servicio ha sido completado",
+	"Reserva cancelada":   "La reserva ha sido cancelada",
+}
+
+// NewPublicTimeline keeps the customer-facing events of a reservation
+// timeline.
+func NewPublicTimeline(events []TimelineEvent) []PublicTimelineRow {
+	rows := []PublicTimelineRow{}
+	for _, event := range events {
+		description, ok := publicTimelineDescriptions[event.Title]
+		if !ok {
+			continue
+		}
+		rows = append(rows, PublicTimelineRow{
+			Title:       event.Title,
+			Description: description,
+			At:          event.At,
+			Variant:     event.Variant,
+		})
+	}
+	return rows
+}
+
+// EstimateETA returns the next expected milestone of a trip: the pickup time
+// until the driver reaches the origin, then the drop-off time once the
+// passenger is on board. The drop-off is projected from the last position in
+// path, the driver's pings since boarding, with the trip distance left after
+// the path driven; without pings it is projected from the boarding time.
+// Finished trips have no ETA.
+func EstimateETA(reservation *Reservation, progress *TripProgress, path []*DriverLocation) *time.Time {
+	if progress == nil || progress.Status == nil || *progress.Status == DriverTripStatusEnCamino {
+		eta := reservation.DateTime
+		return &eta
+	}
+
+	if *progress.Status == DriverTripStatusPasajeroABordo && progress.OnboardAt != nil && reservation.DistanceKM != nil {
+		from, remainingKM := *progress.OnboardAt, *reservation.DistanceKM
+		if len(path) > 0 {
+			from = path[len(path)-1].RecordedAt
+			remainingKM = math.Max(remainingKM-PathDistanceKM(path), 0)
+		}
+		travel := time.Duration(remainingKM / averageTripSpeedKMH * float64(time.Hour))
+		eta := from.Add(travel)
+		return &eta
+	}
+
+	return nil
+}
+
+type TrackingLinkRepository interface {
+	Create(link *TrackingLink) error
+	GetByID(id uuid.UUID) (*TrackingLink, error)
+	ListByReservation(reservationID string) ([]*TrackingLink, error)
+	Revoke(id uuid.UUID, revokedAt time.Time) error
+}
+
+// TrackingLinkIssuer creates tracking links on behalf of other flows, such as
+// the reservation confirmation email.
+type TrackingLinkIssuer interface {
+	IssueTrackingToken(reservationID string, createdBy *uuid.UUID) (string, error)
+}
+
+// TrackingTokenService signs and verifies the tokens embedded in public
+// tracking links.
+type TrackingTokenService interface {
+	Sign(link *TrackingLink) (string, error)
+	Verify(token string) (uuid.UUID, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublicTimeline(t *testing.T) {
+	at := time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)
+	events := []TimelineEvent{
+		{Title: "Reserva creada", Description: "La reserva ha sido creada exitosamente", At: at, Variant: "success"},
+		{Title: "Oferta rechazada", Description: "Conductor CON-002 rechazó la oferta: muy lejos", At: at, Variant: "warning"},
+		{Title: "Oferta expirada", Description: "La oferta a CON-003 expiró", At: at, Variant: "warning"},
+		{Title: "Sin conductores disponibles", Description: "Se requiere asignación manual", At: at, Variant: "error"},
+		{Title: "Conductor asignado", Description: "Conductor Juan Pérez asignado a la reserva", At: at, Variant: "primary"},
+		{Title: "Pago reembolsado", Description: "Se reembolsaron $25.000: cobro duplicado", At: at, Variant: "info"},
+	}
+
+	rows := NewPublicTimeline(events)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Reserva creada", rows[0].Title)
+	assert.Equal(t, "Conductor asignado", rows[1].Title)
+	assert.Equal(t, "Se asignó un conductor a la reserva", rows[1].Description)
+}
+
+func TestEstimateETA(t *testing.T) {
+	onboard := DriverTripStatusPasajeroABordo
+	onboardAt := time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)
+	distance := 20.0
+	reservation := &Reservation{DateTime: onboardAt.Add(-10 * time.Minute), DistanceKM: &distance}
+	progress := &TripProgress{Status: &onboard, OnboardAt: &onboardAt}
+
+	t.Run("projects the drop-off from the boarding time without pings", func(t *testing.T) {
+		eta := EstimateETA(reservation, progress, nil)
+
+		require.NotNil(t, eta)
+		assert.Equal(t, onboardAt.Add(30*time.Minute), *eta)
+	})
+
+	t.Run("projects the drop-off from the last known position", func(t *testing.T) {
+		// 0.09 degrees of latitude are 10 km along a meridian
+		path := []*DriverLocation{
+			{Latitude: -33.45, Longitude: -70.66, RecordedAt: onboardAt},
+			{Latitude: -33.36, Longitude: -70.66, RecordedAt: onboardAt.Add(20 * time.Minute)},
+		}
+
+		eta := EstimateETA(reservation, progress, path)
+
+		require.NotNil(t, eta)
+		assert.WithinDuration(t, onboardAt.Add(35*time.Minute), *eta, 10*time.Second)
+	})
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TripOfferStatus string
+
+const (
+	TripOfferStatusQueued    TripOfferStatus = "QUEUED"
+	TripOfferStatusPending   TripOfferStatus = "PENDING"
+	TripOfferStatusAccepted  TripOfferStatus = "ACCEPTED"
+	TripOfferStatusDeclined  TripOfferStatus = "DECLINED"
+	TripOfferStatusExpired   TripOfferStatus = "EXPIRED"
+	TripOfferStatusCancelled TripOfferStatus = "CANCELLED"
+)
+
+// TripOffer is an offer of a reservation to a single driver. Dispatch creates
+// one offer per candidate; only one offer per reservation is PENDING at a time
+// and the rest stay QUEUED until the previous candidate declines or times out.
+type TripOffer struct {
+	ID            uuid.UUID       `json:"id"`
+	ReservationID string          `json:"reservation_id"`
+	DriverID      string          `json:"driver_id"`
+	Position      int             `json:"position"`
+	Status        TripOfferStatus `json:"status"`
+	OfferedAt     *time.Time      `json:"offered_at,omitempty"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"`
+	RespondedAt   *time.Time      `json:"responded_at,omitempty"`
+	DeclineReason *string         `json:"decline_reason,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// Related data
+	Reservation *Reservation `json:"reservation,omitempty"`
+}
+
+type DispatchOffersRequest struct {
+	DriverIDs []string `json:"driver_ids" validate:"required,min=1,max=20,dive,required"`
+}
+
+type DeclineOfferRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// IsOpen reports whether the offer can still be answered or promoted.
+func (o *TripOffer) IsOpen() bool {
+	return o.Status == TripOfferStatusQueued || o.Status == TripOfferStatusPending
+}
+
+type TripOfferRepository interface {
+	CreateBatch(offers []*TripOffer) error
+	GetByID(id uuid.UUID) (*TripOffer, error)
+	ListByReservation(reservationID string) ([]*TripOffer, error)
+	ListByDriver(driverID string, status *TripOfferStatus) ([]*TripOffer, error)
+	ListExpired(now time.Time) ([]*TripOffer, error)
+	GetNextQueued(reservationID string) (*TripOffer, error)
+	Activate(id uuid.UUID, offeredAt, expiresAt time.Time) error
+	Respond(id uuid.UUID, status TripOfferStatus, respondedAt time.Time, reason *string) error
+	Accept(offer *TripOffer, respondedAt time.Time) error
+	CancelOpenByReservation(reservationID string) error
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"time"
+)
+
+// DriverTripStatus tracks the driver's progress through a single trip. It is
+// independent from ReservationStatus, which describes the booking itself.
+type DriverTripStatus string
+
+const (
+	DriverTripStatusEnCamino       DriverTripStatus = "EN_CAMINO"
+	DriverTripStatusEnOrigen       DriverTripStatus = "EN_ORIGEN"
+	DriverTripStatusPasajeroABordo DriverTripStatus = "PASAJERO_A_BORDO"
+	DriverTripStatusFinalizado     DriverTripStatus = "FINALIZADO"
+	DriverTripStatusNoShow         DriverTripStatus = "NO_SHOW"
+)
+
+// OnTimeGracePeriod is how late a driver may reach the pickup point and still
+// count as arriving on time.
+const OnTimeGracePeriod = 5 * time.Minute
+
+type TripProgress struct {
+	ReservationID string            `json:"reservation_id"`
+	DriverID      string            `json:"driver_id"`
+	Status        *DriverTripStatus `json:"status,omitempty"`
+	EnRouteAt     *time.Time        `json:"en_route_at,omitempty"`
+	ArrivedAt     *time.Time        `json:"arrived_at,omitempty"`
+	OnboardAt     *time.Time        `json:"onboard_at,omitempty"`
+	FinishedAt    *time.Time        `json:"finished_at,omitempty"`
+	NoShowAt      *time.Time        `json:"no_show_at,omitempty"`
+	ArrivedOnTime *bool             `json:"arrived_on_time,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type UpdateTripStatusRequest struct {
+	Status DriverTripStatus `json:"status" validate:"required,oneof=EN_CAMINO EN_ORIGEN PASAJERO_A_BORDO FINALIZADO NO_SHOW"`
+}
+
+// CanTransitionTo reports whether the driver may move the trip to next. A trip
+// without progress can only be started.
+func (p *TripProgress) CanTransitionTo(next DriverTripStatus) bool {
+	if p.Status == nil {
+		return next == DriverTripStatusEnCamino
+	}
+
+	switch *p.Status {
+	case DriverTripStatusEnCamino:
+		return next == DriverTripStatusEnOrigen
+	case DriverTripStatusEnOrigen:
+		return next == DriverTripStatusPasajeroABordo || next == DriverTripStatusNoShow
+	case DriverTripStatusPasajeroABordo:
+		return next == DriverTripStatusFinalizado
+	case DriverTripStatusFinalizado, DriverTripStatusNoShow:
+		return false // Terminal states
+	}
+	return false
+}
+
+// Apply moves the trip to next and stamps the matching step. Arrival at the
+// origin is compared against the scheduled pickup to decide punctuality.
+func (p *TripProgress) Apply(next DriverTripStatus, at time.Time, scheduledPickup time.Time) {
+	switch next {
+	case DriverTripStatusEnCamino:
+		p.EnRouteAt = &at
+	case DriverTripStatusEnOrigen:
+		p.ArrivedAt = &at
+		onTime := !at.After(scheduledPickup.Add(OnTimeGracePeriod))
+		p.ArrivedOnTime = &onTime
+	case DriverTripStatusPasajeroABordo:
+		p.OnboardAt = &at
+	case DriverTripStatusFinalizado:
+		p.FinishedAt = &at
+	case DriverTripStatusNoShow:
+		p.NoShowAt = &at
+	}
+	p.Status = &next
+}
+
+type TripProgressRepository interface {
+	GetByReservationID(reservationID string) (*TripProgress, error)
+	Save(progress *TripProgress) error
+	GetActiveByDriver(driverID string) (*TripProgress, error)
+}
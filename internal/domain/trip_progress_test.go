@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTripProgressCanTransitionTo(t *testing.T) {
+	allStatuses := []DriverTripStatus{
+		DriverTripStatusEnCamino,
+		DriverTripStatusEnOrigen,
+		DriverTripStatusPasajeroABordo,
+		DriverTripStatusFinalizado,
+		DriverTripStatusNoShow,
+	}
+
+	tests := []struct {
+		name    string
+		from    *DriverTripStatus
+		allowed []DriverTripStatus
+	}{
+		{name: "not started", from: nil, allowed: []DriverTripStatus{DriverTripStatusEnCamino}},
+		{name: "en camino", from: tripStatus(DriverTripStatusEnCamino), allowed: []DriverTripStatus{DriverTripStatusEnOrigen}},
+		{name: "en origen", from: tripStatus(DriverTripStatusEnOrigen), allowed: []DriverTripStatus{DriverTripStatusPasajeroABordo, DriverTripStatusNoShow}},
+		{name: "pasajero a bordo", from: tripStatus(DriverTripStatusPasajeroABordo), allowed: []DriverTripStatus{DriverTripStatusFinalizado}},
+		{name: "finalizado is terminal", from: tripStatus(DriverTripStatusFinalizado)},
+		{name: "no show is terminal", from: tripStatus(DriverTripStatusNoShow)},
+		{name: "unknown status", from: tripStatus("PAUSADO")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := &TripProgress{Status: tt.from}
+
+			for _, next := range allStatuses {
+				assert.Equal(t, containsTripStatus(tt.allowed, next), progress.CanTransitionTo(next), "to %s", next)
+			}
+		})
+	}
+}
+
+func TestTripProgressApply(t *testing.T) {
+	pickup := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		onTime bool
+	}{
+		{name: "early", at: pickup.Add(-10 * time.Minute), onTime: true},
+		{name: "at the pickup time", at: pickup, onTime: true},
+		{name: "exactly at the grace period", at: pickup.Add(OnTimeGracePeriod), onTime: true},
+		{name: "just past the grace period", at: pickup.Add(OnTimeGracePeriod + time.Second), onTime: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := &TripProgress{Status: tripStatus(DriverTripStatusEnCamino)}
+
+			progress.Apply(DriverTripStatusEnOrigen, tt.at, pickup)
+
+			require.NotNil(t, progress.ArrivedOnTime)
+			assert.Equal(t, tt.onTime, *progress.ArrivedOnTime)
+			assert.Equal(t, tt.at, *progress.ArrivedAt)
+			assert.Equal(t, DriverTripStatusEnOrigen, *progress.Status)
+		})
+	}
+}
+
+func TestTripProgressApplyStampsStep(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	progress := &TripProgress{}
+
+	progress.Apply(DriverTripStatusEnCamino, at, at)
+	progress.Apply(DriverTripStatusEnOrigen, at.Add(time.Minute), at)
+	progress.Apply(DriverTripStatusPasajeroABordo, at.Add(2*time.Minute), at)
+	progress.Apply(DriverTripStatusFinalizado, at.Add(time.Hour), at)
+
+	assert.Equal(t, at, *progress.EnRouteAt)
+	assert.Equal(t, at.Add(time.Minute), *progress.ArrivedAt)
+	assert.Equal(t, at.Add(2*time.Minute), *progress.OnboardAt)
+	assert.Equal(t, at.Add(time.Hour), *progress.FinishedAt)
+	assert.Nil(t, progress.NoShowAt)
+	assert.Equal(t, DriverTripStatusFinalizado, *progress.Status)
+}
+
+func tripStatus(status DriverTripStatus) *DriverTripStatus {
+	return &status
+}
+
+func containsTripStatus(statuses []DriverTripStatus, status DriverTripStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
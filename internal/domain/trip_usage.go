@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OdometerReadingKind string
+
+const (
+	OdometerReadingStart OdometerReadingKind = "START"
+	OdometerReadingEnd   OdometerReadingKind = "END"
+)
+
+// OdometerReading is the vehicle's odometer as recorded by the driver at the
+// start or end of a trip.
+type OdometerReading struct {
+	ID            uuid.UUID           `json:"id"`
+	ReservationID string              `json:"reservation_id"`
+	DriverID      string              `json:"driver_id"`
+	VehicleID     string              `json:"vehicle_id"`
+	Kind          OdometerReadingKind `json:"kind"`
+	ReadingKm     int                 `json:"reading_km"`
+	RecordedAt    time.Time           `json:"recorded_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+type FuelEventType string
+
+const (
+	FuelEventFuel   FuelEventType = "FUEL"
+	FuelEventCharge FuelEventType = "CHARGE"
+)
+
+// Unit returns the unit Quantity is measured in: litres of fuel or kWh of
+// charge.
+func (t FuelEventType) Unit() string {
+	if t == FuelEventCharge {
+		return "kWh"
+	}
+	return "L"
+}
+
+// FuelEvent is a refuelling or charging stop made during a trip.
+type FuelEvent struct {
+	ID            uuid.UUID     `json:"id"`
+	ReservationID string        `json:"reservation_id"`
+	DriverID      string        `json:"driver_id"`
+	VehicleID     string        `json:"vehicle_id"`
+	Type          FuelEventType `json:"type"`
+	Quantity      float64       `json:"quantity"`
+	Unit          string        `json:"unit"`
+	Cost          *float64      `json:"cost,omitempty"`
+	Currency      string        `json:"currency"`
+	OdometerKm    *int          `json:"odometer_km,omitempty"`
+	Station       *string       `json:"station,omitempty"`
+	OccurredAt    time.Time     `json:"occurred_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// TripUsage gathers the odometer readings and fuel events of a trip. ActualKm
+// is set once both readings are recorded.
+type TripUsage struct {
+	ReservationID string           `json:"reservation_id"`
+	EstimatedKm   *float64         `json:"estimated_km,omitempty"`
+	ActualKm      *float64         `json:"actual_km,omitempty"`
+	Start         *OdometerReading `json:"start,omitempty"`
+	End           *OdometerReading `json:"end,omitempty"`
+	FuelEvents    []*FuelEvent     `json:"fuel_events"`
+}
+
+type RecordOdometerRequest struct {
+	Kind      OdometerReadingKind `json:"kind" validate:"required,oneof=START END"`
+	ReadingKm *int                `json:"reading_km" validate:"required,min=0"`
+}
+
+type RecordFuelEventRequest struct {
+	Type       FuelEventType `json:"type" validate:"required,oneof=FUEL CHARGE"`
+	Quantity   float64       `json:"quantity" validate:"required,gt=0"`
+	Cost       *float64      `json:"cost,omitempty" validate:"omitempty,min=0"`
+	OdometerKm *int          `json:"odometer_km,omitempty" validate:"omitempty,min=0"`
+	Station    *string       `json:"station,omitempty" validate:"omitempty,max=200"`
+	OccurredAt *time.Time    `json:"occurred_at,omitempty"`
+}
+
+// VehicleUsageTotals aggregates the trips and fuel events of a vehicle.
+type VehicleUsageTotals struct {
+	Trips      int     `json:"trips"`
+	DistanceKm float64 `json:"distance_km"`
+	FuelEvents int     `json:"fuel_events"`
+	FuelLiters float64 `json:"fuel_liters"`
+	ChargeKwh  float64 `json:"charge_kwh"`
+	FuelCost   float64 `json:"fuel_cost"`
+}
+
+// VehicleUsageReport combines a vehicle's measured distance with its fuel and
+// maintenance spend in a period.
+type VehicleUsageReport struct {
+	VehicleID string     `json:"vehicle_id"`
+	Plate     *string    `json:"plate,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Currency  string     `json:"currency"`
+	VehicleUsageTotals
+	MaintenanceCost float64  `json:"maintenance_cost"`
+	TotalCost       float64  `json:"total_cost"`
+	CostPerKm       *float64 `json:"cost_per_km,omitempty"`
+	KmPerLiter      *float64 `json:"km_per_liter,omitempty"`
+	KwhPer100Km     *float64 `json:"kwh_per_100km,omitempty"`
+}
+
+// Compute fills in the total cost and the efficiency ratios. Ratios are left
+// empty when there is no distance or no fuel to divide by.
+func (r *VehicleUsageReport) Compute() {
+	r.TotalCost = r.FuelCost + r.MaintenanceCost
+	r.CostPerKm, r.KmPerLiter, r.KwhPer100Km = nil, nil, nil
+
+	if r.DistanceKm <= 0 {
+		return
+	}
+	costPerKm := r.TotalCost / r.DistanceKm
+	r.CostPerKm = &costPerKm
+	if r.FuelLiters > 0 {
+		kmPerLiter := r.DistanceKm / r.FuelLiters
+		r.KmPerLiter = &kmPerLiter
+	}
+	if r.ChargeKwh > 0 {
+		kwhPer100Km := r.ChargeKwh / r.DistanceKm * 100
+		r.KwhPer100Km = &kwhPer100Km
+	}
+}
+
+type TripUsageRepository interface {
+	CreateReading(reading *OdometerReading) error
+	ListReadings(reservationID string) ([]*OdometerReading, error)
+	SetActualDistance(reservationID string, km float64) error
+	CreateFuelEvent(event *FuelEvent) error
+	ListFuelEvents(reservationID string) ([]*FuelEvent, error)
+	UsageSummary(vehicleID *string, from, to *time.Time) (map[string]VehicleUsageTotals, error)
+}
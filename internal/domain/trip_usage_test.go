@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleUsageReportCompute(t *testing.T) {
+	report := &VehicleUsageReport{
+		VehicleUsageTotals: VehicleUsageTotals{
+			DistanceKm: 500,
+			FuelLiters: 40,
+			ChargeKwh:  25,
+			FuelCost:   60000,
+		},
+		MaintenanceCost: 40000,
+	}
+	report.Compute()
+
+	assert.Equal(t, 100000.0, report.TotalCost)
+	require.NotNil(t, report.CostPerKm)
+	assert.Equal(t, 200.0, *report.CostPerKm)
+	require.NotNil(t, report.KmPerLiter)
+	assert.Equal(t, 12.5, *report.KmPerLiter)
+	require.NotNil(t, report.KwhPer100Km)
+	assert.Equal(t, 5.0, *report.KwhPer100Km)
+}
+
+func TestVehicleUsageReportComputeWithoutDistance(t *testing.T) {
+	report := &VehicleUsageReport{MaintenanceCost: 40000}
+	report.Compute()
+
+	assert.Equal(t, 40000.0, report.TotalCost)
+	assert.Nil(t, report.CostPerKm)
+	assert.Nil(t, report.KmPerLiter)
+	assert.Nil(t, report.KwhPer100Km)
+}
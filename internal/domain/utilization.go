@@ -0,0 +1,296 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trips have no recorded duration until they finish, so the calendar
+// estimates it from the planned distance at an average speed, rounded up to
+// the next quarter hour and never shorter than MinTripDuration.
+const (
+	TripAverageSpeedKmh = 40
+	MinTripDuration     = time.Hour
+	MaxUtilizationDays  = 62
+)
+
+// bookingLookback is how far before a period bookings are loaded, so trips
+// that start earlier but run into it are not missed.
+const bookingLookback = 48 * time.Hour
+
+type UtilizationBlockKind string
+
+const (
+	UtilizationBooked      UtilizationBlockKind = "BOOKED"
+	UtilizationMaintenance UtilizationBlockKind = "MAINTENANCE"
+	UtilizationFree        UtilizationBlockKind = "FREE"
+)
+
+// EstimateTripDuration returns how long a trip is expected to keep its vehicle
+// busy.
+func EstimateTripDuration(distanceKm *float64) time.Duration {
+	if distanceKm == nil || *distanceKm <= 0 {
+		return MinTripDuration
+	}
+
+	minutes := math.Ceil(*distanceKm/TripAverageSpeedKmh*60/15) * 15
+	duration := time.Duration(minutes) * time.Minute
+	if duration < MinTripDuration {
+		return MinTripDuration
+	}
+	return duration
+}
+
+// FleetVehicle is a vehicle together with its current driver and the
+// company that driver belongs to.
+type FleetVehicle struct {
+	ID          string        `json:"id"`
+	Type        VehicleType   `json:"type"`
+	Brand       string        `json:"brand"`
+	Model       string        `json:"model"`
+	Plate       *string       `json:"plate,omitempty"`
+	Capacity    *int          `json:"capacity,omitempty"`
+	Status      VehicleStatus `json:"status"`
+	DriverID    *string       `json:"driver_id,omitempty"`
+	CompanyID   *uuid.UUID    `json:"company_id,omitempty"`
+	CompanyName *string       `json:"company_name,omitempty"`
+}
+
+// VehicleBooking is a reservation taken by the driver of a vehicle. End is
+// estimated from the planned distance.
+type VehicleBooking struct {
+	VehicleID     string    `json:"vehicle_id"`
+	ReservationID string    `json:"reservation_id"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	DistanceKm    *float64  `json:"distance_km,omitempty"`
+}
+
+// VehicleWindow is a maintenance window holding a vehicle.
+type VehicleWindow struct {
+	VehicleID string    `json:"vehicle_id"`
+	RecordID  uuid.UUID `json:"record_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+type UtilizationBlock struct {
+	Kind          UtilizationBlockKind `json:"kind"`
+	Start         time.Time            `json:"start"`
+	End           time.Time            `json:"end"`
+	ReservationID *string              `json:"reservation_id,omitempty"`
+	RecordID      *uuid.UUID           `json:"maintenance_id,omitempty"`
+}
+
+// UtilizationTotals counts booked, maintenance and free minutes.
+// UtilizationPct is the share of the minutes out of maintenance that were
+// booked.
+type UtilizationTotals struct {
+	BookedMinutes      int     `json:"booked_minutes"`
+	MaintenanceMinutes int     `json:"maintenance_minutes"`
+	FreeMinutes        int     `json:"free_minutes"`
+	UtilizationPct     float64 `json:"utilization_pct"`
+}
+
+func (t *UtilizationTotals) Add(other UtilizationTotals) {
+	t.BookedMinutes += other.BookedMinutes
+	t.MaintenanceMinutes += other.MaintenanceMinutes
+	t.FreeMinutes += other.FreeMinutes
+	t.Compute()
+}
+
+func (t *UtilizationTotals) Compute() {
+	t.UtilizationPct = 0
+	if serviceable := t.BookedMinutes + t.FreeMinutes; serviceable > 0 {
+		t.UtilizationPct = math.Round(float64(t.BookedMinutes)/float64(serviceable)*1000) / 10
+	}
+}
+
+type UtilizationDay struct {
+	Date   string             `json:"date"`
+	Blocks []UtilizationBlock `json:"blocks"`
+	UtilizationTotals
+}
+
+type VehicleUtilization struct {
+	Vehicle *FleetVehicle    `json:"vehicle"`
+	Days    []UtilizationDay `json:"days"`
+	UtilizationTotals
+}
+
+type TypeUtilization struct {
+	Type     VehicleType `json:"type"`
+	Vehicles int         `json:"vehicles"`
+	UtilizationTotals
+}
+
+// CompanyUtilization groups vehicles by the company of their current driver;
+// vehicles without one are grouped under a nil CompanyID.
+type CompanyUtilization struct {
+	CompanyID   *uuid.UUID `json:"company_id,omitempty"`
+	CompanyName *string    `json:"company_name,omitempty"`
+	Vehicles    int        `json:"vehicles"`
+	UtilizationTotals
+}
+
+type FleetUtilizationReport struct {
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Fleet     UtilizationTotals     `json:"fleet"`
+	ByType    []*TypeUtilization    `json:"by_type"`
+	ByCompany []*CompanyUtilization `json:"by_company"`
+	Vehicles  []*VehicleUtilization `json:"vehicles"`
+}
+
+type FleetUtilizationRequest struct {
+	VehicleID   *string
+	VehicleType *VehicleType
+	From        time.Time
+	To          time.Time
+}
+
+type FreeVehiclesRequest struct {
+	VehicleType *VehicleType `json:"type,omitempty" validate:"omitempty,oneof=BUS VAN SEDAN SUV"`
+	From        time.Time    `json:"from" validate:"required"`
+	To          time.Time    `json:"to" validate:"required,gtfield=From"`
+}
+
+// BuildVehicleUtilization lays out the bookings and maintenance windows of a
+// vehicle day by day from from (midnight) up to to. Maintenance wins over a
+// booking it overlaps, since the vehicle cannot take the trip anyway.
+func BuildVehicleUtilization(vehicle *FleetVehicle, bookings []VehicleBooking, windows []VehicleWindow, from, to time.Time) *VehicleUtilization {
+	utilization := &VehicleUtilization{
+		Vehicle: vehicle,
+		Days:    []UtilizationDay{},
+	}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+
+		calendarDay := UtilizationDay{
+			Date:   day.Format("2006-01-02"),
+			Blocks: dayBlocks(bookings, windows, day, end),
+		}
+		for _, block := range calendarDay.Blocks {
+			minutes := int(block.End.Sub(block.Start).Minutes())
+			switch block.Kind {
+			case UtilizationBooked:
+				calendarDay.BookedMinutes += minutes
+			case UtilizationMaintenance:
+				calendarDay.MaintenanceMinutes += minutes
+			default:
+				calendarDay.FreeMinutes += minutes
+			}
+		}
+		calendarDay.Compute()
+
+		utilization.Add(calendarDay.UtilizationTotals)
+		utilization.Days = append(utilization.Days, calendarDay)
+	}
+
+	return utilization
+}
+
+// IsFree reports whether none of the bookings or windows overlap [from, to).
+func IsFree(bookings []VehicleBooking, windows []VehicleWindow, from, to time.Time) bool {
+	for _, booking := range bookings {
+		if booking.Start.Before(to) && booking.End.After(from) {
+			return false
+		}
+	}
+	for _, window := range windows {
+		if window.Start.Before(to) && window.End.After(from) {
+			return false
+		}
+	}
+	return true
+}
+
+// dayBlocks splits [start, end) at every booking and window edge and labels
+// each piece, merging neighbours of the same kind.
+func dayBlocks(bookings []VehicleBooking, windows []VehicleWindow, start, end time.Time) []UtilizationBlock {
+	edges := []time.Time{start, end}
+	clip := func(t time.Time) {
+		if t.After(start) && t.Before(end) {
+			edges = append(edges, t)
+		}
+	}
+	for _, booking := range bookings {
+		clip(booking.Start)
+		clip(booking.End)
+	}
+	for _, window := range windows {
+		clip(window.Start)
+		clip(window.End)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Before(edges[j]) })
+
+	blocks := []UtilizationBlock{}
+	for i := 1; i < len(edges); i++ {
+		from, to := edges[i-1], edges[i]
+		if !from.Before(to) {
+			continue
+		}
+
+		block := UtilizationBlock{Kind: UtilizationFree, Start: from, End: to}
+		for _, window := range windows {
+			if !window.Start.After(from) && window.End.After(from) {
+				recordID := window.RecordID
+				block.Kind = UtilizationMaintenance
+				block.RecordID = &recordID
+				break
+			}
+		}
+		if block.Kind == UtilizationFree {
+			for _, booking := range bookings {
+				if !booking.Start.After(from) && booking.End.After(from) {
+					reservationID := booking.ReservationID
+					block.Kind = UtilizationBooked
+					block.ReservationID = &reservationID
+					break
+				}
+			}
+		}
+
+		if n := len(blocks); n > 0 && sameBlock(blocks[n-1], block) {
+			blocks[n-1].End = block.End
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+func sameBlock(a, b UtilizationBlock) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case UtilizationBooked:
+		return *a.ReservationID == *b.ReservationID
+	case UtilizationMaintenance:
+		return *a.RecordID == *b.RecordID
+	}
+	return true
+}
+
+// BookingLookbackStart returns the earliest start of a booking that can still
+// overlap a period beginning at from.
+func BookingLookbackStart(from time.Time) time.Time {
+	return from.Add(-bookingLookback)
+}
+
+type UtilizationRepository interface {
+	ListFleet(vehicleID *string, vehicleType *VehicleType) ([]*FleetVehicle, error)
+	// ListBookings returns the open and completed reservations of drivers
+	// with a vehicle that start in [from, to), with End left unset.
+	ListBookings(from, to time.Time) ([]VehicleBooking, error)
+	ListWindows(from, to time.Time) ([]VehicleWindow, error)
+}
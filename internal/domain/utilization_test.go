@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTripDuration(t *testing.T) {
+	short, long := 10.0, 130.0
+
+	assert.Equal(t, MinTripDuration, EstimateTripDuration(nil))
+	assert.Equal(t, MinTripDuration, EstimateTripDuration(&short))
+	// 130 km at 40 km/h is 3h15m
+	assert.Equal(t, 3*time.Hour+15*time.Minute, EstimateTripDuration(&long))
+}
+
+func TestBuildVehicleUtilization(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	recordID := uuid.New()
+	bookings := []VehicleBooking{
+		{ReservationID: "RSV-1", Start: day.Add(8 * time.Hour), End: day.Add(10 * time.Hour)},
+		// Runs from the first day into the second
+		{ReservationID: "RSV-2", Start: day.Add(22 * time.Hour), End: day.Add(26 * time.Hour)},
+	}
+	windows := []VehicleWindow{
+		{RecordID: recordID, Start: day.Add(33 * time.Hour), End: day.Add(36 * time.Hour)},
+	}
+
+	utilization := BuildVehicleUtilization(&FleetVehicle{ID: "v1"}, bookings, windows, day, day.AddDate(0, 0, 2))
+
+	require.Len(t, utilization.Days, 2)
+
+	first := utilization.Days[0]
+	assert.Equal(t, "2025-03-10", first.Date)
+	require.Len(t, first.Blocks, 4)
+	assert.Equal(t, UtilizationFree, first.Blocks[0].Kind)
+	assert.Equal(t, UtilizationBooked, first.Blocks[1].Kind)
+	assert.Equal(t, "RSV-1", *first.Blocks[1].ReservationID)
+	assert.Equal(t, UtilizationBooked, first.Blocks[3].Kind)
+	assert.Equal(t, 4*60, first.BookedMinutes)
+	assert.Equal(t, 20*60, first.FreeMinutes)
+	assert.Equal(t, 16.7, first.UtilizationPct)
+
+	second := utilization.Days[1]
+	assert.Equal(t, 2*60, second.BookedMinutes)
+	assert.Equal(t, 3*60, second.MaintenanceMinutes)
+	assert.Equal(t, 19*60, second.FreeMinutes)
+	assert.Equal(t, UtilizationMaintenance, second.Blocks[2].Kind)
+	assert.Equal(t, recordID, *second.Blocks[2].RecordID)
+
+	assert.Equal(t, 6*60, utilization.BookedMinutes)
+	assert.Equal(t, 3*60, utilization.MaintenanceMinutes)
+	// 360 booked out of 2880 - 180 minutes in service
+	assert.Equal(t, 13.3, utilization.UtilizationPct)
+}
+
+func TestBuildVehicleUtilizationMaintenanceWinsOverBooking(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	bookings := []VehicleBooking{
+		{ReservationID: "RSV-1", Start: day.Add(8 * time.Hour), End: day.Add(12 * time.Hour)},
+	}
+	windows := []VehicleWindow{
+		{RecordID: uuid.New(), Start: day.Add(10 * time.Hour), End: day.Add(14 * time.Hour)},
+	}
+
+	utilization := BuildVehicleUtilization(&FleetVehicle{ID: "v1"}, bookings, windows, day, day.AddDate(0, 0, 1))
+
+	assert.Equal(t, 2*60, utilization.BookedMinutes)
+	assert.Equal(t, 4*60, utilization.MaintenanceMinutes)
+	assert.Equal(t, 18*60, utilization.FreeMinutes)
+}
+
+func TestIsFree(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	bookings := []VehicleBooking{
+		{ReservationID: "RSV-1", Start: day.Add(8 * time.Hour), End: day.Add(10 * time.Hour)},
+	}
+
+	assert.True(t, IsFree(bookings, nil, day.Add(10*time.Hour), day.Add(12*time.Hour)))
+	assert.False(t, IsFree(bookings, nil, day.Add(9*time.Hour), day.Add(12*time.Hour)))
+	assert.False(t, IsFree(nil, []VehicleWindow{{Start: day, End: day.Add(time.Hour)}}, day, day.Add(2*time.Hour)))
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type VehicleStatus string
@@ -45,6 +47,48 @@ type DriverBasicInfo struct {
 	Phone     string `json:"phone,omitempty"`
 }
 
+// VehicleBasicInfo contains basic vehicle information for assignment responses
+type VehicleBasicInfo struct {
+	ID    string      `json:"id"`
+	Type  VehicleType `json:"type"`
+	Brand string      `json:"brand"`
+	Model string      `json:"model"`
+	Plate *string     `json:"plate,omitempty"`
+}
+
+// VehicleAssignment is a period in which a driver had a vehicle. ReleasedAt
+// is nil while the assignment is current.
+type VehicleAssignment struct {
+	ID         uuid.UUID  `json:"id"`
+	VehicleID  string     `json:"vehicle_id"`
+	DriverID   string     `json:"driver_id"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+
+	// Related data
+	Vehicle *VehicleBasicInfo `json:"vehicle,omitempty"`
+	Driver  *DriverBasicInfo  `json:"driver,omitempty"`
+}
+
+// VehicleDriverChanged reports whether giving a vehicle held by current to
+// next ends its assignment period. Assigning the same driver again keeps the
+// open period.
+func VehicleDriverChanged(current, next *string) bool {
+	if current == nil || next == nil {
+		return current != next
+	}
+	return *current != *next
+}
+
+// ListVehicleAssignmentsRequest filters assignments by vehicle or driver and
+// by a period they overlap.
+type ListVehicleAssignmentsRequest struct {
+	VehicleID *string
+	DriverID  *string
+	From      *time.Time
+	To        *time.Time
+}
+
 type CreateVehicleRequest struct {
 	Type                VehicleType   `json:"type" validate:"required"`
 	Brand               string        `json:"brand" validate:"required,min=2,max=100"`
@@ -98,6 +142,9 @@ type VehicleRepository interface {
 	Update(id string, req UpdateVehicleRequest) (*Vehicle, error)
 	Delete(id string) error
 	AssignToDriver(vehicleID string, driverID *string) error
+	GetAssignmentAt(vehicleID string, at time.Time) (*VehicleAssignment, error)
+	ListAssignments(req ListVehicleAssignmentsRequest) ([]*VehicleAssignment, error)
+	ListActiveCapacities(vehicleType *VehicleType) ([]VehicleCapacity, error)
 	
 	// Photo operations
 	AddPhoto(vehicleID string, photoURL string) error
@@ -112,5 +159,8 @@ type VehicleUseCase interface {
 	UpdateVehicle(id string, req UpdateVehicleRequest) (*Vehicle, error)
 	DeleteVehicle(id string) error
 	AssignVehicleToDriver(vehicleID string, driverID *string) (*Vehicle, error)
+	GetVehicleDriverAt(vehicleID string, at time.Time) (*VehicleAssignment, error)
+	ListVehicleAssignments(vehicleID string) ([]*VehicleAssignment, error)
+	ListDriverVehicleAssignments(driverID string, from, to *time.Time) ([]*VehicleAssignment, error)
 }
 
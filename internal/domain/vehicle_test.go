@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVehicleDriverChanged(t *testing.T) {
+	driverA := "DRV-001"
+	sameDriverA := "DRV-001"
+	driverB := "DRV-002"
+
+	tests := []struct {
+		name    string
+		current *string
+		next    *string
+		changed bool
+	}{
+		{name: "stays unassigned", current: nil, next: nil, changed: false},
+		{name: "first assignment", current: nil, next: &driverA, changed: true},
+		{name: "unassigned", current: &driverA, next: nil, changed: true},
+		{name: "same driver again", current: &driverA, next: &sameDriverA, changed: false},
+		{name: "reassigned to another driver", current: &driverA, next: &driverB, changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.changed, VehicleDriverChanged(tt.current, tt.next))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyWallet holds what a company prepaid and the credit line it may
+// draw on. Balance goes negative while the company uses its credit line, down
+// to -CreditLimit. Completed trips of a company with a wallet are debited
+// from it instead of being invoiced.
+type CompanyWallet struct {
+	ID        uuid.UUID `json:"id"`
+	CompanyID uuid.UUID `json:"company_id"`
+	Currency  string    `json:"currency"`
+	Balance   float64   `json:"balance"`
+	// CreditLimit is 0 for prepaid wallets
+	CreditLimit float64 `json:"credit_limit"`
+	// An alert is emailed when the available amount falls below
+	// LowBalanceThreshold; 0 disables alerts
+	LowBalanceThreshold float64    `json:"low_balance_threshold"`
+	AlertEmail          *string    `json:"alert_email,omitempty"`
+	LowBalanceAlertedAt *time.Time `json:"low_balance_alerted_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+
+	// Committed is the amount of upcoming trips not debited yet, set when
+	// the wallet is shown
+	Committed *float64 `json:"committed,omitempty"`
+}
+
+// Available is what the company can still spend: its balance plus its
+// credit line.
+func (w *CompanyWallet) Available() float64 {
+	return roundCents(w.Balance + w.CreditLimit)
+}
+
+// IsLow reports whether the available amount is below the alert threshold.
+func (w *CompanyWallet) IsLow() bool {
+	return w.LowBalanceThreshold > 0 && w.Available() < w.LowBalanceThreshold
+}
+
+// UpsertWalletRequest opens or updates a company's wallet. Wallets are
+// opened in the pricing currency and it cannot change afterwards.
+type UpsertWalletRequest struct {
+	Currency            string   `json:"currency" validate:"required,len=3"`
+	CreditLimit         *float64 `json:"credit_limit" validate:"omitempty,min=0"`
+	LowBalanceThreshold *float64 `json:"low_balance_threshold" validate:"omitempty,min=0"`
+	AlertEmail          *string  `json:"alert_email" validate:"omitempty,email"`
+}
+
+// WalletTopUpRequest adds money to the wallet through a payment gateway.
+// Company credit cannot top up a wallet.
+type WalletTopUpRequest struct {
+	Amount float64        `json:"amount" validate:"required,gt=0"`
+	Method PaymentGateway `json:"method" validate:"required,oneof=WEBPAY_PLUS MERCADO_PAGO STRIPE BANK_TRANSFER"`
+}
+
+// WalletAdjustmentRequest corrects a wallet's balance: positive amounts
+// credit it and negative ones debit it.
+type WalletAdjustmentRequest struct {
+	Amount float64 `json:"amount" validate:"required,ne=0"`
+	Reason string  `json:"reason" validate:"required,max=255"`
+}
+
+// LedgerAccount is an account of the wallet ledger. Every wallet is an
+// account of its own; the others are shared.
+type LedgerAccount string
+
+const (
+	LedgerAccountWallet LedgerAccount = "WALLET"
+	// LedgerAccountGatewayClearing is the money taken through payment
+	// gateways for top-ups
+	LedgerAccountGatewayClearing LedgerAccount = "GATEWAY_CLEARING"
+	LedgerAccountTripRevenue     LedgerAccount = "TRIP_REVENUE"
+	LedgerAccountAdjustments     LedgerAccount = "ADJUSTMENTS"
+)
+
+type WalletTransactionKind string
+
+const (
+	WalletTransactionTopUp       WalletTransactionKind = "TOP_UP"
+	WalletTransactionTopUpRefund WalletTransactionKind = "TOP_UP_REFUND"
+	WalletTransactionTripDebit   WalletTransactionKind = "TRIP_DEBIT"
+	WalletTransactionTripRefund  WalletTransactionKind = "TRIP_REFUND"
+	WalletTransactionAdjustment  WalletTransactionKind = "ADJUSTMENT"
+)
+
+// CounterAccount is the account that balances the wallet in transactions
+// of the kind.
+func (k WalletTransactionKind) CounterAccount() LedgerAccount {
+	switch k {
+	case WalletTransactionTopUp, WalletTransactionTopUpRefund:
+		return LedgerAccountGatewayClearing
+	case WalletTransactionTripDebit, WalletTransactionTripRefund:
+		return LedgerAccountTripRevenue
+	}
+	return LedgerAccountAdjustments
+}
+
+// WalletTransaction is a posting to the ledger that moves a wallet's
+// balance by Amount. Its entries add up to zero: the wallet's entry is
+// Amount and the counter account takes the opposite.
+type WalletTransaction struct {
+	ID            uuid.UUID             `json:"id"`
+	WalletID      uuid.UUID             `json:"wallet_id"`
+	Kind          WalletTransactionKind `json:"kind"`
+	Amount        float64               `json:"amount"`
+	BalanceAfter  float64               `json:"balance_after"`
+	Description   string                `json:"description"`
+	PaymentID     *uuid.UUID            `json:"payment_id,omitempty"`
+	ReservationID *string               `json:"reservation_id,omitempty"`
+	RefundID      *uuid.UUID            `json:"refund_id,omitempty"`
+	CreatedBy     *uuid.UUID            `json:"created_by,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// LedgerEntry is one side of a transaction. Amounts are signed so the
+// entries of a transaction add up to zero.
+type LedgerEntry struct {
+	Account LedgerAccount `json:"account"`
+	Amount  float64       `json:"amount"`
+}
+
+// NewWalletTransaction moves the wallet's balance by amount, balanced
+// against the counter account of the kind.
+func NewWalletTransaction(wallet *CompanyWallet, kind WalletTransactionKind, amount float64, description string) *WalletTransaction {
+	amount = roundCents(amount)
+	return &WalletTransaction{
+		WalletID:    wallet.ID,
+		Kind:        kind,
+		Amount:      amount,
+		Description: description,
+		Entries: []LedgerEntry{
+			{Account: LedgerAccountWallet, Amount: amount},
+			{Account: kind.CounterAccount(), Amount: -amount},
+		},
+	}
+}
+
+// WalletDebitCandidate is a completed, priced trip of a company with a
+// wallet that has not been debited or paid on its own yet. Amount is net of
+// the refunds of its company credit payment.
+type WalletDebitCandidate struct {
+	CompanyID     uuid.UUID
+	ReservationID string
+	Pickup        string
+	Destination   string
+	Amount        float64
+	CompletedAt   time.Time
+}
+
+type ListWalletTransactionsRequest struct {
+	WalletID uuid.UUID
+	Kind     *WalletTransactionKind
+	Page     int
+	PageSize int
+}
+
+// CreditChecker guards bookings of companies with a wallet against going
+// over their balance and credit line.
+type CreditChecker interface {
+	// EnsureCredit returns ErrInsufficientCredit if the company's wallet
+	// cannot cover amount on top of its upcoming trips, leaving out the
+	// reservation with excludeReservationID. Companies without a wallet
+	// always pass.
+	EnsureCredit(companyID uuid.UUID, amount float64, excludeReservationID string) error
+}
+
+type WalletRepository interface {
+	GetByCompany(companyID uuid.UUID) (*CompanyWallet, error)
+	GetByID(id uuid.UUID) (*CompanyWallet, error)
+	// Create returns ErrAlreadyExists if the company has a wallet.
+	Create(wallet *CompanyWallet) error
+	// Update stores the settings of the wallet, not its balance.
+	Update(wallet *CompanyWallet) error
+
+	// Post stores the transaction and moves the wallet's balance in one
+	// database transaction, returning the wallet as it is after it. A
+	// payment, trip or refund is posted once: posting it again returns
+	// ErrAlreadyExists.
+	Post(tx *WalletTransaction) (*CompanyWallet, error)
+	ListTransactions(req ListWalletTransactionsRequest) ([]*WalletTransaction, int, error)
+
+	// CommittedAmount is the total of the company's upcoming trips, those
+	// neither finished nor paid on their own, except excludeReservationID.
+	CommittedAmount(companyID uuid.UUID, excludeReservationID string) (float64, error)
+	// ListDebitCandidates returns up to limit trips to debit from wallets
+	// in currency, oldest first. Only trips completed after the wallet was
+	// opened are debited.
+	ListDebitCandidates(currency string, limit int) ([]*WalletDebitCandidate, error)
+	// HasTripDebit reports whether the trip was debited from a wallet.
+	HasTripDebit(reservationID string) (bool, error)
+	// MarkLowBalanceAlerted records the alert and reports whether it was
+	// not already recorded. The mark is cleared when the available amount
+	// is back over the threshold.
+	MarkLowBalanceAlerted(walletID uuid.UUID, at time.Time) (bool, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWalletTransaction(t *testing.T) {
+	wallet := &CompanyWallet{ID: uuid.New(), Currency: "CLP"}
+
+	tests := []struct {
+		name    string
+		kind    WalletTransactionKind
+		amount  float64
+		counter LedgerAccount
+	}{
+		{name: "top-up", kind: WalletTransactionTopUp, amount: 50000, counter: LedgerAccountGatewayClearing},
+		{name: "top-up refund", kind: WalletTransactionTopUpRefund, amount: -20000, counter: LedgerAccountGatewayClearing},
+		{name: "trip debit", kind: WalletTransactionTripDebit, amount: -18000.255, counter: LedgerAccountTripRevenue},
+		{name: "trip refund", kind: WalletTransactionTripRefund, amount: 5000, counter: LedgerAccountTripRevenue},
+		{name: "adjustment", kind: WalletTransactionAdjustment, amount: -1500, counter: LedgerAccountAdjustments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := NewWalletTransaction(wallet, tt.kind, tt.amount, "Movimiento")
+
+			assert.Equal(t, wallet.ID, tx.WalletID)
+			assert.Equal(t, roundCents(tt.amount), tx.Amount)
+
+			require.Len(t, tx.Entries, 2)
+			assert.Equal(t, LedgerEntry{Account: LedgerAccountWallet, Amount: tx.Amount}, tx.Entries[0])
+			assert.Equal(t, LedgerEntry{Account: tt.counter, Amount: -tx.Amount}, tx.Entries[1])
+			assert.Zero(t, tx.Entries[0].Amount+tx.Entries[1].Amount)
+		})
+	}
+}
+
+func TestCompanyWalletAvailable(t *testing.T) {
+	tests := []struct {
+		name      string
+		wallet    CompanyWallet
+		available float64
+		low       bool
+	}{
+		{
+			name:      "prepaid",
+			wallet:    CompanyWallet{Balance: 120000, LowBalanceThreshold: 50000},
+			available: 120000,
+		},
+		{
+			name:      "drawing on the credit line",
+			wallet:    CompanyWallet{Balance: -80000, CreditLimit: 100000, LowBalanceThreshold: 50000},
+			available: 20000,
+			low:       true,
+		},
+		{
+			name:      "past the credit line",
+			wallet:    CompanyWallet{Balance: -110000.5, CreditLimit: 100000},
+			available: -10000.5,
+		},
+		{
+			name:      "at the threshold",
+			wallet:    CompanyWallet{Balance: 50000, LowBalanceThreshold: 50000},
+			available: 50000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.available, tt.wallet.Available())
+			assert.Equal(t, tt.low, tt.wallet.IsLow())
+		})
+	}
+}
+
+func TestPaymentReferenceForTopUp(t *testing.T) {
+	walletID := uuid.New()
+	payment := &Payment{WalletID: &walletID}
+
+	assert.Equal(t, walletID.String(), payment.Reference())
+	assert.Equal(t, "Recarga de saldo", payment.Description())
+}
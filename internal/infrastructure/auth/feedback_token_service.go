@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"turivo-backend/internal/domain"
+)
+
+const feedbackTokenAudience = "turivo-feedback"
+
+// FeedbackTokenService signs the rating links emailed after a completed trip.
+// Tokens only carry the reservation ID; whether the prompt is still open is
+// checked against the stored feedback request.
+type FeedbackTokenService struct {
+	key []byte
+}
+
+func NewFeedbackTokenService(secret string) domain.FeedbackTokenService {
+	return &FeedbackTokenService{
+		key: deriveSigningKey(secret, feedbackTokenAudience),
+	}
+}
+
+func (s *FeedbackTokenService) Sign(request *domain.FeedbackRequest) (string, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   request.ReservationID,
+		Audience:  jwt.ClaimStrings{feedbackTokenAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(request.ExpiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.key)
+}
+
+// Verify checks the signature, audience and expiry and returns the reservation
+// ID the rating link was issued for.
+func (s *FeedbackTokenService) Verify(tokenString string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(feedbackTokenAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" {
+		return "", domain.ErrUnauthorized
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestFeedbackTokenService_SignAndVerify(t *testing.T) {
+	service := NewFeedbackTokenService("secret")
+	request := &domain.FeedbackRequest{
+		ReservationID: "RSV-1001",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	token, err := service.Sign(request)
+	assert.NoError(t, err)
+
+	reservationID, err := service.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "RSV-1001", reservationID)
+}
+
+func TestFeedbackTokenService_RejectsTrackingToken(t *testing.T) {
+	tracking := NewTrackingTokenService("secret")
+	token, err := tracking.Sign(&domain.TrackingLink{
+		ReservationID: "RSV-1001",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	_, err = NewFeedbackTokenService("secret").Verify(token)
+	assert.Equal(t, domain.ErrUnauthorized, err)
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"turivo-backend/internal/domain"
+)
+
+const trackingTokenAudience = "turivo-tracking"
+
+// TrackingTokenService signs tracking links as short JWTs. The signing key is
+// derived from the application secret so a tracking token can never be
+// accepted as an access token, and vice versa.
+type TrackingTokenService struct {
+	key []byte
+}
+
+func NewTrackingTokenService(secret string) domain.TrackingTokenService {
+	return &TrackingTokenService{
+		key: deriveSigningKey(secret, trackingTokenAudience),
+	}
+}
+
+func (s *TrackingTokenService) Sign(link *domain.TrackingLink) (string, error) {
+	claims := jwt.RegisteredClaims{
+		ID:        link.ID.String(),
+		Subject:   link.ReservationID,
+		Audience:  jwt.ClaimStrings{trackingTokenAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(link.ExpiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.key)
+}
+
+// Verify checks the signature, audience and expiry and returns the link ID so
+// callers can confirm the link has not been revoked.
+func (s *TrackingTokenService) Verify(tokenString string) (uuid.UUID, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(trackingTokenAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+
+	linkID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+
+	return linkID, nil
+}
+
+// deriveSigningKey returns a signing key dedicated to one token audience, so
+// tokens issued for one purpose never validate for another.
+func deriveSigningKey(secret, audience string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(audience))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestTrackingTokenService_SignAndVerify(t *testing.T) {
+	service := NewTrackingTokenService("secret")
+	link := &domain.TrackingLink{
+		ID:            uuid.New(),
+		ReservationID: "RSV-1001",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	token, err := service.Sign(link)
+	assert.NoError(t, err)
+
+	linkID, err := service.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, link.ID, linkID)
+}
+
+func TestTrackingTokenService_RejectsExpiredToken(t *testing.T) {
+	service := NewTrackingTokenService("secret")
+	link := &domain.TrackingLink{
+		ID:            uuid.New(),
+		ReservationID: "RSV-1001",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	}
+
+	token, err := service.Sign(link)
+	assert.NoError(t, err)
+
+	_, err = service.Verify(token)
+	assert.Equal(t, domain.ErrUnauthorized, err)
+}
+
+func TestTrackingTokenService_RejectsTokenSignedWithRawSecret(t *testing.T) {
+	service := NewTrackingTokenService("secret")
+
+	claims := jwt.RegisteredClaims{
+		ID:        uuid.New().String(),
+		Audience:  jwt.ClaimStrings{trackingTokenAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	_, err = service.Verify(token)
+	assert.Equal(t, domain.ErrUnauthorized, err)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -14,6 +15,25 @@ type Config struct {
 	Log  Log  `mapstructure:"log"`
 	CORS CORS `mapstructure:"cors"`
 	SMTP SMTP `mapstructure:"smtp"`
+
+	Dispatch       Dispatch       `mapstructure:"dispatch"`
+	Tracking       Tracking       `mapstructure:"tracking"`
+	Feedback       Feedback       `mapstructure:"feedback"`
+	Compliance     Compliance     `mapstructure:"compliance"`
+	Earnings       Earnings       `mapstructure:"earnings"`
+	Shifts         Shifts         `mapstructure:"shifts"`
+	Maintenance    Maintenance    `mapstructure:"maintenance"`
+	Storage        Storage        `mapstructure:"storage"`
+	Webpay         Webpay         `mapstructure:"webpay"`
+	MercadoPago    MercadoPago    `mapstructure:"mercadopago"`
+	Stripe         Stripe         `mapstructure:"stripe"`
+	BankTransfer   BankTransfer   `mapstructure:"bank_transfer"`
+	CompanyCredit  CompanyCredit  `mapstructure:"company_credit"`
+	Idempotency    Idempotency    `mapstructure:"idempotency"`
+	Reconciliation Reconciliation `mapstructure:"reconciliation"`
+	Invoicing      Invoicing      `mapstructure:"invoicing"`
+	Wallets        Wallets        `mapstructure:"wallets"`
+	DTE            DTE            `mapstructure:"dte"`
 }
 
 type HTTP struct {
@@ -53,6 +73,183 @@ type SMTP struct {
 	From     string `mapstructure:"from"`
 }
 
+type Dispatch struct {
+	OfferTimeout  time.Duration `mapstructure:"offer_timeout"`
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+}
+
+type Tracking struct {
+	Retention         time.Duration `mapstructure:"retention"`
+	PruneInterval     time.Duration `mapstructure:"prune_interval"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	LinkTTL           time.Duration `mapstructure:"link_ttl"`
+}
+
+type Feedback struct {
+	Window         time.Duration `mapstructure:"window"`
+	PromptInterval time.Duration `mapstructure:"prompt_interval"`
+}
+
+type Compliance struct {
+	ReminderInterval time.Duration `mapstructure:"reminder_interval"`
+}
+
+type Earnings struct {
+	PayoutInterval     time.Duration `mapstructure:"payout_interval"`
+	SettlementPeriod   string        `mapstructure:"settlement_period"`
+	SettlementInterval time.Duration `mapstructure:"settlement_interval"`
+}
+
+type Shifts struct {
+	// Enforce requires reservations to fall within one of the driver's
+	// scheduled shifts before they can be assigned or dispatched.
+	Enforce bool `mapstructure:"enforce"`
+}
+
+type Maintenance struct {
+	WindowInterval   time.Duration `mapstructure:"window_interval"`
+	ReminderInterval time.Duration `mapstructure:"reminder_interval"`
+}
+
+type Storage struct {
+	Driver        string        `mapstructure:"driver"`
+	LocalPath     string        `mapstructure:"local_path"`
+	PublicURL     string        `mapstructure:"public_url"`
+	SignedURLTTL  time.Duration `mapstructure:"signed_url_ttl"`
+	MaxUploadSize int64         `mapstructure:"max_upload_size"`
+
+	S3Endpoint  string `mapstructure:"s3_endpoint"`
+	S3Region    string `mapstructure:"s3_region"`
+	S3Bucket    string `mapstructure:"s3_bucket"`
+	S3AccessKey string `mapstructure:"s3_access_key"`
+	S3SecretKey string `mapstructure:"s3_secret_key"`
+	S3PathStyle bool   `mapstructure:"s3_path_style"`
+}
+
+// Webpay configures the Webpay Plus gateway. Environment is mock (payments
+// approved instantly), local (a stand-in of Transbank served by this API),
+// integration (Transbank's test environment) or production.
+type Webpay struct {
+	Environment  string        `mapstructure:"environment"`
+	BaseURL      string        `mapstructure:"base_url"`
+	CommerceCode string        `mapstructure:"commerce_code"`
+	APIKey       string        `mapstructure:"api_key"`
+	ReturnURL    string        `mapstructure:"return_url"`
+	FinalURL     string        `mapstructure:"final_url"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+}
+
+// MercadoPago configures Checkout Pro. Environment is disabled, local (a
+// stand-in served by this API) or production; Mercado Pago test accounts use
+// production with a TEST- access token.
+type MercadoPago struct {
+	Environment string `mapstructure:"environment"`
+	BaseURL     string `mapstructure:"base_url"`
+	AccessToken string `mapstructure:"access_token"`
+	// WebhookSecret verifies the x-signature of payment notifications
+	WebhookSecret string        `mapstructure:"webhook_secret"`
+	ReturnURL     string        `mapstructure:"return_url"`
+	FinalURL      string        `mapstructure:"final_url"`
+	Currencies    []string      `mapstructure:"currencies"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+}
+
+// Stripe configures Stripe Checkout. Environment is disabled, local (a
+// stand-in served by this API) or production; test mode uses production
+// with an sk_test_ key.
+type Stripe struct {
+	Environment string `mapstructure:"environment"`
+	BaseURL     string `mapstructure:"base_url"`
+	SecretKey   string `mapstructure:"secret_key"`
+	// WebhookSecret is the signing secret of the webhook endpoint
+	WebhookSecret string        `mapstructure:"webhook_secret"`
+	ReturnURL     string        `mapstructure:"return_url"`
+	FinalURL      string        `mapstructure:"final_url"`
+	Currencies    []string      `mapstructure:"currencies"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+}
+
+// BankTransfer is the account customers wire money to; an admin confirms
+// the payment once the transfer arrives.
+type BankTransfer struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	BankName      string   `mapstructure:"bank_name"`
+	AccountType   string   `mapstructure:"account_type"`
+	AccountNumber string   `mapstructure:"account_number"`
+	AccountHolder string   `mapstructure:"account_holder"`
+	HolderRUT     string   `mapstructure:"holder_rut"`
+	Email         string   `mapstructure:"email"`
+	Currencies    []string `mapstructure:"currencies"`
+}
+
+// CompanyCredit charges reservations to the company's account.
+type CompanyCredit struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Idempotency sets how long responses to requests sent with an
+// Idempotency-Key are replayed.
+type Idempotency struct {
+	TTL           time.Duration `mapstructure:"ttl"`
+	PruneInterval time.Duration `mapstructure:"prune_interval"`
+}
+
+// Reconciliation settles payments left pending, e.g. because the customer
+// never came back from the gateway. Payments pending for PendingAfter are
+// checked with their gateway every Interval; those still pending after
+// AbandonAfter are rejected.
+type Reconciliation struct {
+	Interval     time.Duration `mapstructure:"interval"`
+	PendingAfter time.Duration `mapstructure:"pending_after"`
+	AbandonAfter time.Duration `mapstructure:"abandon_after"`
+}
+
+// Invoicing bills postpaid companies monthly. Completed trips are accrued
+// every AccrualInterval and periods that ended are closed into invoices every
+// CloseInterval. TaxRate and PaymentTermsDays are the defaults of new billing
+// accounts.
+type Invoicing struct {
+	AccrualInterval  time.Duration `mapstructure:"accrual_interval"`
+	CloseInterval    time.Duration `mapstructure:"close_interval"`
+	TaxRate          float64       `mapstructure:"tax_rate"`
+	PaymentTermsDays int           `mapstructure:"payment_terms_days"`
+}
+
+// Wallets debits completed trips of companies with a wallet every
+// DebitInterval.
+type Wallets struct {
+	DebitInterval time.Duration `mapstructure:"debit_interval"`
+}
+
+// DTE issues SII electronic tax documents. Environment is disabled or local
+// (submissions go to a stand-in that accepts correctly signed envelopes).
+// The issuer fields are the company as registered with the SII; CertFile and
+// KeyFile hold the PEM signing certificate of the holder SenderRUT, and a
+// throwaway one is generated in local when they are unset. Signed documents
+// are sent every SubmitInterval.
+type DTE struct {
+	Environment      string        `mapstructure:"environment"`
+	IssuerRUT        string        `mapstructure:"issuer_rut"`
+	IssuerName       string        `mapstructure:"issuer_name"`
+	Activity         string        `mapstructure:"activity"`
+	ActivityCode     int           `mapstructure:"activity_code"`
+	Address          string        `mapstructure:"address"`
+	Commune          string        `mapstructure:"commune"`
+	ResolutionDate   time.Time     `mapstructure:"resolution_date"`
+	ResolutionNumber int           `mapstructure:"resolution_number"`
+	SenderRUT        string        `mapstructure:"sender_rut"`
+	CertFile         string        `mapstructure:"cert_file"`
+	KeyFile          string        `mapstructure:"key_file"`
+	SubmitInterval   time.Duration `mapstructure:"submit_interval"`
+}
+
+// Transbank's public test credentials for Webpay Plus in the integration
+// environment.
+const (
+	webpayIntegrationCommerceCode = "597055555532"
+	webpayIntegrationAPIKey       = "579B532A7440BB0C9079DED94D31EA1615BACEB56610332264630D42D0A2347C"
+)
+
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
@@ -73,6 +270,51 @@ func Load() (*Config, error) {
 	viper.SetDefault("JWT_ACCESS_TTL", "15m")
 	viper.SetDefault("JWT_REFRESH_TTL", "168h")
 	viper.SetDefault("SMTP_PORT", 465)
+	viper.SetDefault("DISPATCH_OFFER_TIMEOUT", "3m")
+	viper.SetDefault("DISPATCH_SWEEP_INTERVAL", "30s")
+	viper.SetDefault("TRACKING_RETENTION", "168h")
+	viper.SetDefault("TRACKING_PRUNE_INTERVAL", "1h")
+	viper.SetDefault("TRACKING_HEARTBEAT_INTERVAL", "15s")
+	viper.SetDefault("TRACKING_LINK_TTL", "72h")
+	viper.SetDefault("FEEDBACK_WINDOW", "168h")
+	viper.SetDefault("FEEDBACK_PROMPT_INTERVAL", "5m")
+	viper.SetDefault("COMPLIANCE_REMINDER_INTERVAL", "24h")
+	viper.SetDefault("EARNINGS_PAYOUT_INTERVAL", "5m")
+	viper.SetDefault("EARNINGS_SETTLEMENT_PERIOD", "WEEKLY")
+	viper.SetDefault("EARNINGS_SETTLEMENT_INTERVAL", "1h")
+	viper.SetDefault("SHIFTS_ENFORCE", true)
+	viper.SetDefault("MAINTENANCE_WINDOW_INTERVAL", "1m")
+	viper.SetDefault("MAINTENANCE_REMINDER_INTERVAL", "24h")
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_PATH", "./uploads")
+	viper.SetDefault("STORAGE_PUBLIC_URL", "http://localhost:3000")
+	viper.SetDefault("STORAGE_SIGNED_URL_TTL", "15m")
+	viper.SetDefault("STORAGE_MAX_UPLOAD_SIZE", 10<<20)
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_PATH_STYLE", true)
+	viper.SetDefault("WEBPAY_ENVIRONMENT", "mock")
+	viper.SetDefault("WEBPAY_TIMEOUT", "30s")
+	viper.SetDefault("MERCADOPAGO_ENVIRONMENT", "disabled")
+	viper.SetDefault("MERCADOPAGO_CURRENCIES", "CLP")
+	viper.SetDefault("MERCADOPAGO_TIMEOUT", "30s")
+	viper.SetDefault("STRIPE_ENVIRONMENT", "disabled")
+	viper.SetDefault("STRIPE_CURRENCIES", "CLP,USD")
+	viper.SetDefault("STRIPE_TIMEOUT", "30s")
+	viper.SetDefault("BANK_TRANSFER_ENABLED", false)
+	viper.SetDefault("BANK_TRANSFER_CURRENCIES", "CLP")
+	viper.SetDefault("COMPANY_CREDIT_ENABLED", true)
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_PRUNE_INTERVAL", "1h")
+	viper.SetDefault("RECONCILIATION_INTERVAL", "5m")
+	viper.SetDefault("RECONCILIATION_PENDING_AFTER", "15m")
+	viper.SetDefault("RECONCILIATION_ABANDON_AFTER", "24h")
+	viper.SetDefault("INVOICING_ACCRUAL_INTERVAL", "15m")
+	viper.SetDefault("INVOICING_CLOSE_INTERVAL", "1h")
+	viper.SetDefault("INVOICING_TAX_RATE", 0.19)
+	viper.SetDefault("INVOICING_PAYMENT_TERMS_DAYS", 30)
+	viper.SetDefault("WALLET_DEBIT_INTERVAL", "15m")
+	viper.SetDefault("DTE_ENVIRONMENT", "disabled")
+	viper.SetDefault("DTE_SUBMIT_INTERVAL", "5m")
 
 	if err := viper.ReadInConfig(); err != nil {
 		// It's okay if .env file doesn't exist, we'll use env vars and defaults
@@ -116,6 +358,201 @@ func Load() (*Config, error) {
 	}
 	config.JWT.RefreshTTL = refreshTTL
 
+	// Parse dispatch timings
+	offerTimeout, err := time.ParseDuration(viper.GetString("DISPATCH_OFFER_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISPATCH_OFFER_TIMEOUT: %w", err)
+	}
+	config.Dispatch.OfferTimeout = offerTimeout
+
+	sweepInterval, err := time.ParseDuration(viper.GetString("DISPATCH_SWEEP_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISPATCH_SWEEP_INTERVAL: %w", err)
+	}
+	config.Dispatch.SweepInterval = sweepInterval
+
+	retention, err := time.ParseDuration(viper.GetString("TRACKING_RETENTION"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACKING_RETENTION: %w", err)
+	}
+	config.Tracking.Retention = retention
+
+	pruneInterval, err := time.ParseDuration(viper.GetString("TRACKING_PRUNE_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACKING_PRUNE_INTERVAL: %w", err)
+	}
+	config.Tracking.PruneInterval = pruneInterval
+
+	heartbeatInterval, err := time.ParseDuration(viper.GetString("TRACKING_HEARTBEAT_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACKING_HEARTBEAT_INTERVAL: %w", err)
+	}
+	config.Tracking.HeartbeatInterval = heartbeatInterval
+
+	linkTTL, err := time.ParseDuration(viper.GetString("TRACKING_LINK_TTL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACKING_LINK_TTL: %w", err)
+	}
+	config.Tracking.LinkTTL = linkTTL
+
+	feedbackWindow, err := time.ParseDuration(viper.GetString("FEEDBACK_WINDOW"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEEDBACK_WINDOW: %w", err)
+	}
+	config.Feedback.Window = feedbackWindow
+
+	promptInterval, err := time.ParseDuration(viper.GetString("FEEDBACK_PROMPT_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEEDBACK_PROMPT_INTERVAL: %w", err)
+	}
+	config.Feedback.PromptInterval = promptInterval
+
+	reminderInterval, err := time.ParseDuration(viper.GetString("COMPLIANCE_REMINDER_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid COMPLIANCE_REMINDER_INTERVAL: %w", err)
+	}
+	config.Compliance.ReminderInterval = reminderInterval
+
+	payoutInterval, err := time.ParseDuration(viper.GetString("EARNINGS_PAYOUT_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EARNINGS_PAYOUT_INTERVAL: %w", err)
+	}
+	config.Earnings.PayoutInterval = payoutInterval
+
+	config.Earnings.SettlementPeriod = viper.GetString("EARNINGS_SETTLEMENT_PERIOD")
+	if config.Earnings.SettlementPeriod != "WEEKLY" && config.Earnings.SettlementPeriod != "BIWEEKLY" {
+		return nil, fmt.Errorf("invalid EARNINGS_SETTLEMENT_PERIOD: %q (expected WEEKLY or BIWEEKLY)", config.Earnings.SettlementPeriod)
+	}
+
+	settlementInterval, err := time.ParseDuration(viper.GetString("EARNINGS_SETTLEMENT_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EARNINGS_SETTLEMENT_INTERVAL: %w", err)
+	}
+	config.Earnings.SettlementInterval = settlementInterval
+
+	config.Shifts.Enforce = viper.GetBool("SHIFTS_ENFORCE")
+
+	windowInterval, err := time.ParseDuration(viper.GetString("MAINTENANCE_WINDOW_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_WINDOW_INTERVAL: %w", err)
+	}
+	config.Maintenance.WindowInterval = windowInterval
+
+	maintenanceReminderInterval, err := time.ParseDuration(viper.GetString("MAINTENANCE_REMINDER_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_REMINDER_INTERVAL: %w", err)
+	}
+	config.Maintenance.ReminderInterval = maintenanceReminderInterval
+
+	config.Storage.Driver = viper.GetString("STORAGE_DRIVER")
+	config.Storage.LocalPath = viper.GetString("STORAGE_LOCAL_PATH")
+	config.Storage.PublicURL = viper.GetString("STORAGE_PUBLIC_URL")
+	config.Storage.MaxUploadSize = viper.GetInt64("STORAGE_MAX_UPLOAD_SIZE")
+	config.Storage.S3Endpoint = viper.GetString("S3_ENDPOINT")
+	config.Storage.S3Region = viper.GetString("S3_REGION")
+	config.Storage.S3Bucket = viper.GetString("S3_BUCKET")
+	config.Storage.S3AccessKey = viper.GetString("S3_ACCESS_KEY")
+	config.Storage.S3SecretKey = viper.GetString("S3_SECRET_KEY")
+	config.Storage.S3PathStyle = viper.GetBool("S3_PATH_STYLE")
+
+	if config.Storage.Driver != "local" && config.Storage.Driver != "s3" {
+		return nil, fmt.Errorf("invalid STORAGE_DRIVER: %q (expected local or s3)", config.Storage.Driver)
+	}
+
+	signedURLTTL, err := time.ParseDuration(viper.GetString("STORAGE_SIGNED_URL_TTL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_SIGNED_URL_TTL: %w", err)
+	}
+	config.Storage.SignedURLTTL = signedURLTTL
+
+	if err := loadWebpay(config); err != nil {
+		return nil, err
+	}
+	if err := loadMercadoPago(config); err != nil {
+		return nil, err
+	}
+	if err := loadStripe(config); err != nil {
+		return nil, err
+	}
+
+	config.BankTransfer.Enabled = viper.GetBool("BANK_TRANSFER_ENABLED")
+	config.BankTransfer.BankName = viper.GetString("BANK_TRANSFER_BANK_NAME")
+	config.BankTransfer.AccountType = viper.GetString("BANK_TRANSFER_ACCOUNT_TYPE")
+	config.BankTransfer.AccountNumber = viper.GetString("BANK_TRANSFER_ACCOUNT_NUMBER")
+	config.BankTransfer.AccountHolder = viper.GetString("BANK_TRANSFER_ACCOUNT_HOLDER")
+	config.BankTransfer.HolderRUT = viper.GetString("BANK_TRANSFER_HOLDER_RUT")
+	config.BankTransfer.Email = viper.GetString("BANK_TRANSFER_EMAIL")
+	config.BankTransfer.Currencies = splitCurrencies(viper.GetString("BANK_TRANSFER_CURRENCIES"))
+	if config.BankTransfer.Enabled && config.BankTransfer.AccountNumber == "" {
+		return nil, fmt.Errorf("BANK_TRANSFER_ACCOUNT_NUMBER is required when bank transfers are enabled")
+	}
+
+	config.CompanyCredit.Enabled = viper.GetBool("COMPANY_CREDIT_ENABLED")
+
+	idempotencyTTL, err := time.ParseDuration(viper.GetString("IDEMPOTENCY_TTL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %w", err)
+	}
+	config.Idempotency.TTL = idempotencyTTL
+
+	idempotencyPruneInterval, err := time.ParseDuration(viper.GetString("IDEMPOTENCY_PRUNE_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_PRUNE_INTERVAL: %w", err)
+	}
+	config.Idempotency.PruneInterval = idempotencyPruneInterval
+
+	reconciliationInterval, err := time.ParseDuration(viper.GetString("RECONCILIATION_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECONCILIATION_INTERVAL: %w", err)
+	}
+	config.Reconciliation.Interval = reconciliationInterval
+
+	reconciliationPendingAfter, err := time.ParseDuration(viper.GetString("RECONCILIATION_PENDING_AFTER"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECONCILIATION_PENDING_AFTER: %w", err)
+	}
+	config.Reconciliation.PendingAfter = reconciliationPendingAfter
+
+	reconciliationAbandonAfter, err := time.ParseDuration(viper.GetString("RECONCILIATION_ABANDON_AFTER"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECONCILIATION_ABANDON_AFTER: %w", err)
+	}
+	if reconciliationAbandonAfter < reconciliationPendingAfter {
+		return nil, fmt.Errorf("RECONCILIATION_ABANDON_AFTER must not be shorter than RECONCILIATION_PENDING_AFTER")
+	}
+	config.Reconciliation.AbandonAfter = reconciliationAbandonAfter
+
+	accrualInterval, err := time.ParseDuration(viper.GetString("INVOICING_ACCRUAL_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVOICING_ACCRUAL_INTERVAL: %w", err)
+	}
+	config.Invoicing.AccrualInterval = accrualInterval
+
+	closeInterval, err := time.ParseDuration(viper.GetString("INVOICING_CLOSE_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVOICING_CLOSE_INTERVAL: %w", err)
+	}
+	config.Invoicing.CloseInterval = closeInterval
+
+	config.Invoicing.TaxRate = viper.GetFloat64("INVOICING_TAX_RATE")
+	if config.Invoicing.TaxRate < 0 || config.Invoicing.TaxRate > 1 {
+		return nil, fmt.Errorf("invalid INVOICING_TAX_RATE: %v (expected a rate between 0 and 1)", config.Invoicing.TaxRate)
+	}
+	config.Invoicing.PaymentTermsDays = viper.GetInt("INVOICING_PAYMENT_TERMS_DAYS")
+	if config.Invoicing.PaymentTermsDays < 0 {
+		return nil, fmt.Errorf("invalid INVOICING_PAYMENT_TERMS_DAYS: %d", config.Invoicing.PaymentTermsDays)
+	}
+
+	walletDebitInterval, err := time.ParseDuration(viper.GetString("WALLET_DEBIT_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WALLET_DEBIT_INTERVAL: %w", err)
+	}
+	config.Wallets.DebitInterval = walletDebitInterval
+
+	if err := loadDTE(config); err != nil {
+		return nil, err
+	}
+
 	// Generate DSN if not provided
 	if config.DB.DSN == "" {
 		config.DB.DSN = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
@@ -126,6 +563,238 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// loadWebpay reads the Webpay settings, filling in the Transbank endpoint and
+// test credentials of the chosen environment. Production needs real
+// credentials and a public return URL.
+func loadWebpay(config *Config) error {
+	webpay := &config.Webpay
+	webpay.Environment = viper.GetString("WEBPAY_ENVIRONMENT")
+	webpay.BaseURL = viper.GetString("WEBPAY_BASE_URL")
+	webpay.CommerceCode = viper.GetString("WEBPAY_COMMERCE_CODE")
+	webpay.APIKey = viper.GetString("WEBPAY_API_KEY")
+	webpay.ReturnURL = viper.GetString("WEBPAY_RETURN_URL")
+	webpay.FinalURL = viper.GetString("WEBPAY_FINAL_URL")
+
+	timeout, err := time.ParseDuration(viper.GetString("WEBPAY_TIMEOUT"))
+	if err != nil {
+		return fmt.Errorf("invalid WEBPAY_TIMEOUT: %w", err)
+	}
+	webpay.Timeout = timeout
+
+	if webpay.ReturnURL == "" {
+		webpay.ReturnURL = strings.TrimRight(config.Storage.PublicURL, "/") + "/api/v1/public/payments/webpay/return"
+	}
+
+	switch webpay.Environment {
+	case "mock":
+		return nil
+	case "local", "integration":
+		if webpay.CommerceCode == "" {
+			webpay.CommerceCode = webpayIntegrationCommerceCode
+		}
+		if webpay.APIKey == "" {
+			webpay.APIKey = webpayIntegrationAPIKey
+		}
+		if webpay.BaseURL == "" {
+			webpay.BaseURL = "https://webpay3gint.transbank.cl"
+			if webpay.Environment == "local" {
+				webpay.BaseURL = "http://localhost:" + config.HTTP.Port + "/webpay-standin"
+			}
+		}
+	case "production":
+		if webpay.CommerceCode == "" || webpay.APIKey == "" {
+			return fmt.Errorf("WEBPAY_COMMERCE_CODE and WEBPAY_API_KEY are required in production")
+		}
+		if webpay.BaseURL == "" {
+			webpay.BaseURL = "https://webpay3g.transbank.cl"
+		}
+	default:
+		return fmt.Errorf("invalid WEBPAY_ENVIRONMENT: %q (expected mock, local, integration or production)", webpay.Environment)
+	}
+
+	return nil
+}
+
+// loadMercadoPago reads the Mercado Pago settings. The local environment
+// points at the stand-in served by this API.
+func loadMercadoPago(config *Config) error {
+	mp := &config.MercadoPago
+	mp.Environment = viper.GetString("MERCADOPAGO_ENVIRONMENT")
+	mp.BaseURL = viper.GetString("MERCADOPAGO_BASE_URL")
+	mp.AccessToken = viper.GetString("MERCADOPAGO_ACCESS_TOKEN")
+	mp.WebhookSecret = viper.GetString("MERCADOPAGO_WEBHOOK_SECRET")
+	mp.ReturnURL = viper.GetString("MERCADOPAGO_RETURN_URL")
+	mp.FinalURL = viper.GetString("MERCADOPAGO_FINAL_URL")
+	mp.Currencies = splitCurrencies(viper.GetString("MERCADOPAGO_CURRENCIES"))
+
+	timeout, err := time.ParseDuration(viper.GetString("MERCADOPAGO_TIMEOUT"))
+	if err != nil {
+		return fmt.Errorf("invalid MERCADOPAGO_TIMEOUT: %w", err)
+	}
+	mp.Timeout = timeout
+
+	if mp.ReturnURL == "" {
+		mp.ReturnURL = strings.TrimRight(config.Storage.PublicURL, "/") + "/api/v1/public/payments/mercadopago/return"
+	}
+
+	switch mp.Environment {
+	case "disabled":
+		return nil
+	case "local":
+		if mp.AccessToken == "" {
+			mp.AccessToken = "TEST-standin"
+		}
+		if mp.WebhookSecret == "" {
+			mp.WebhookSecret = "standin-webhook-secret"
+		}
+		if mp.BaseURL == "" {
+			mp.BaseURL = "http://localhost:" + config.HTTP.Port + "/mercadopago-standin"
+		}
+	case "production":
+		if mp.AccessToken == "" {
+			return fmt.Errorf("MERCADOPAGO_ACCESS_TOKEN is required in production")
+		}
+		if mp.BaseURL == "" {
+			mp.BaseURL = "https://api.mercadopago.com"
+		}
+	default:
+		return fmt.Errorf("invalid MERCADOPAGO_ENVIRONMENT: %q (expected disabled, local or production)", mp.Environment)
+	}
+
+	return nil
+}
+
+// loadStripe reads the Stripe settings. The local environment points at the
+// stand-in served by this API.
+func loadStripe(config *Config) error {
+	stripe := &config.Stripe
+	stripe.Environment = viper.GetString("STRIPE_ENVIRONMENT")
+	stripe.BaseURL = viper.GetString("STRIPE_BASE_URL")
+	stripe.SecretKey = viper.GetString("STRIPE_SECRET_KEY")
+	stripe.WebhookSecret = viper.GetString("STRIPE_WEBHOOK_SECRET")
+	stripe.ReturnURL = viper.GetString("STRIPE_RETURN_URL")
+	stripe.FinalURL = viper.GetString("STRIPE_FINAL_URL")
+	stripe.Currencies = splitCurrencies(viper.GetString("STRIPE_CURRENCIES"))
+
+	timeout, err := time.ParseDuration(viper.GetString("STRIPE_TIMEOUT"))
+	if err != nil {
+		return fmt.Errorf("invalid STRIPE_TIMEOUT: %w", err)
+	}
+	stripe.Timeout = timeout
+
+	if stripe.ReturnURL == "" {
+		stripe.ReturnURL = strings.TrimRight(config.Storage.PublicURL, "/") + "/api/v1/public/payments/stripe/return"
+	}
+
+	switch stripe.Environment {
+	case "disabled":
+		return nil
+	case "local":
+		if stripe.SecretKey == "" {
+			stripe.SecretKey = "sk_test_standin"
+		}
+		if stripe.WebhookSecret == "" {
+			stripe.WebhookSecret = "whsec_standin"
+		}
+		if stripe.BaseURL == "" {
+			stripe.BaseURL = "http://localhost:" + config.HTTP.Port + "/stripe-standin"
+		}
+	case "production":
+		if stripe.SecretKey == "" {
+			return fmt.Errorf("STRIPE_SECRET_KEY is required in production")
+		}
+		if stripe.BaseURL == "" {
+			stripe.BaseURL = "https://api.stripe.com"
+		}
+	default:
+		return fmt.Errorf("invalid STRIPE_ENVIRONMENT: %q (expected disabled, local or production)", stripe.Environment)
+	}
+
+	return nil
+}
+
+// loadDTE reads the tax document settings. The local environment fills in a
+// sample issuer so documents can be tried without SII registration.
+func loadDTE(config *Config) error {
+	dte := &config.DTE
+	dte.Environment = viper.GetString("DTE_ENVIRONMENT")
+	dte.IssuerRUT = viper.GetString("DTE_ISSUER_RUT")
+	dte.IssuerName = viper.GetString("DTE_ISSUER_NAME")
+	dte.Activity = viper.GetString("DTE_ACTIVITY")
+	dte.ActivityCode = viper.GetInt("DTE_ACTIVITY_CODE")
+	dte.Address = viper.GetString("DTE_ADDRESS")
+	dte.Commune = viper.GetString("DTE_COMMUNE")
+	resolutionDate := viper.GetString("DTE_RESOLUTION_DATE")
+	dte.ResolutionNumber = viper.GetInt("DTE_RESOLUTION_NUMBER")
+	dte.SenderRUT = viper.GetString("DTE_SENDER_RUT")
+	dte.CertFile = viper.GetString("DTE_CERT_FILE")
+	dte.KeyFile = viper.GetString("DTE_KEY_FILE")
+
+	submitInterval, err := time.ParseDuration(viper.GetString("DTE_SUBMIT_INTERVAL"))
+	if err != nil {
+		return fmt.Errorf("invalid DTE_SUBMIT_INTERVAL: %w", err)
+	}
+	dte.SubmitInterval = submitInterval
+
+	switch dte.Environment {
+	case "disabled":
+		return nil
+	case "local":
+		if dte.IssuerRUT == "" {
+			dte.IssuerRUT = "76086428-5"
+		}
+		if dte.IssuerName == "" {
+			dte.IssuerName = "Turivo SpA"
+		}
+		if dte.Activity == "" {
+			dte.Activity = "Transporte de pasajeros"
+		}
+		if dte.ActivityCode == 0 {
+			dte.ActivityCode = 492250
+		}
+		if dte.Address == "" {
+			dte.Address = "Sin dirección"
+		}
+		if dte.Commune == "" {
+			dte.Commune = "Santiago"
+		}
+		if resolutionDate == "" {
+			resolutionDate = "2014-08-22"
+		}
+		if dte.SenderRUT == "" {
+			dte.SenderRUT = dte.IssuerRUT
+		}
+	default:
+		return fmt.Errorf("invalid DTE_ENVIRONMENT: %q (expected disabled or local)", dte.Environment)
+	}
+
+	if dte.IssuerName == "" || dte.Activity == "" || dte.Address == "" || dte.Commune == "" {
+		return fmt.Errorf("DTE_ISSUER_NAME, DTE_ACTIVITY, DTE_ADDRESS and DTE_COMMUNE are required")
+	}
+
+	dte.ResolutionDate, err = time.Parse("2006-01-02", resolutionDate)
+	if err != nil {
+		return fmt.Errorf("invalid DTE_RESOLUTION_DATE: %w (expected YYYY-MM-DD)", err)
+	}
+
+	if (dte.CertFile == "") != (dte.KeyFile == "") {
+		return fmt.Errorf("DTE_CERT_FILE and DTE_KEY_FILE must be set together")
+	}
+
+	return nil
+}
+
+// splitCurrencies splits a comma separated list of currency codes.
+func splitCurrencies(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToUpper(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func (c *Config) Validate() error {
 	if c.JWT.Secret == "change-me-in-prod" {
 		return fmt.Errorf("JWT_SECRET must be changed in production")
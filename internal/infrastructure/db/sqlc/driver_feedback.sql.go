@@ -14,7 +14,7 @@ import (
 const createDriverFeedback = `-- name: CreateDriverFeedback :one
 INSERT INTO driver_feedback (driver_id, reservation_id, rating, comment)
 VALUES ($1, $2, $3, $4)
-RETURNING id, driver_id, reservation_id, rating, comment, created_at, updated_at
+RETURNING id, driver_id, reservation_id, rating, comment, created_at, updated_at, user_id, tags, status, moderation_note, moderated_by, moderated_at
 `
 
 type CreateDriverFeedbackParams struct {
@@ -40,12 +40,18 @@ func (q *Queries) CreateDriverFeedback(ctx context.Context, arg CreateDriverFeed
 		&i.Comment,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UserID,
+		&i.Tags,
+		&i.Status,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
 	)
 	return i, err
 }
 
 const getDriverFeedback = `-- name: GetDriverFeedback :many
-SELECT id, driver_id, reservation_id, rating, comment, created_at, updated_at FROM driver_feedback 
+SELECT id, driver_id, reservation_id, rating, comment, created_at, updated_at, user_id, tags, status, moderation_note, moderated_by, moderated_at FROM driver_feedback 
 WHERE driver_id = $1 
 ORDER BY created_at DESC
 `
@@ -67,6 +73,12 @@ func (q *Queries) GetDriverFeedback(ctx context.Context, driverID string) ([]Dri
 			&i.Comment,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UserID,
+			&i.Tags,
+			&i.Status,
+			&i.ModerationNote,
+			&i.ModeratedBy,
+			&i.ModeratedAt,
 		); err != nil {
 			return nil, err
 		}
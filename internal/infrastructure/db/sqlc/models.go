@@ -277,7 +277,11 @@ func (ns NullLicenseClass) Value() (driver.Value, error) {
 type PaymentGateway string
 
 const (
-	PaymentGatewayWEBPAYPLUS PaymentGateway = "WEBPAY_PLUS"
+	PaymentGatewayWEBPAYPLUS    PaymentGateway = "WEBPAY_PLUS"
+	PaymentGatewayMERCADOPAGO   PaymentGateway = "MERCADO_PAGO"
+	PaymentGatewaySTRIPE        PaymentGateway = "STRIPE"
+	PaymentGatewayBANKTRANSFER  PaymentGateway = "BANK_TRANSFER"
+	PaymentGatewayCOMPANYCREDIT PaymentGateway = "COMPANY_CREDIT"
 )
 
 func (e *PaymentGateway) Scan(src interface{}) error {
@@ -318,9 +322,11 @@ func (ns NullPaymentGateway) Value() (driver.Value, error) {
 type PaymentStatus string
 
 const (
-	PaymentStatusAPPROVED PaymentStatus = "APPROVED"
-	PaymentStatusREJECTED PaymentStatus = "REJECTED"
-	PaymentStatusPENDING  PaymentStatus = "PENDING"
+	PaymentStatusAPPROVED          PaymentStatus = "APPROVED"
+	PaymentStatusREJECTED          PaymentStatus = "REJECTED"
+	PaymentStatusPENDING           PaymentStatus = "PENDING"
+	PaymentStatusPARTIALLYREFUNDED PaymentStatus = "PARTIALLY_REFUNDED"
+	PaymentStatusREFUNDED          PaymentStatus = "REFUNDED"
 )
 
 func (e *PaymentStatus) Scan(src interface{}) error {
@@ -621,6 +627,42 @@ func (ns NullVehicleType) Value() (driver.Value, error) {
 	return string(ns.VehicleType), nil
 }
 
+type BillingAccount struct {
+	ID               pgtype.UUID        `json:"id"`
+	CompanyID        pgtype.UUID        `json:"company_id"`
+	Currency         string             `json:"currency"`
+	TaxRate          pgtype.Numeric     `json:"tax_rate"`
+	PaymentTermsDays int32              `json:"payment_terms_days"`
+	BillingEmail     *string            `json:"billing_email"`
+	Active           bool               `json:"active"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type BillingAccrual struct {
+	ID            pgtype.UUID        `json:"id"`
+	AccountID     pgtype.UUID        `json:"account_id"`
+	PeriodID      pgtype.UUID        `json:"period_id"`
+	ReservationID string             `json:"reservation_id"`
+	Description   string             `json:"description"`
+	Amount        pgtype.Numeric     `json:"amount"`
+	Currency      string             `json:"currency"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type BillingPeriod struct {
+	ID          pgtype.UUID        `json:"id"`
+	AccountID   pgtype.UUID        `json:"account_id"`
+	PeriodStart pgtype.Timestamptz `json:"period_start"`
+	PeriodEnd   pgtype.Timestamptz `json:"period_end"`
+	Status      string             `json:"status"`
+	InvoiceID   pgtype.UUID        `json:"invoice_id"`
+	ClosedAt    pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Company struct {
 	ID           pgtype.UUID        `json:"id"`
 	Name         string             `json:"name"`
@@ -632,6 +674,55 @@ type Company struct {
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
 
+type CompanyPaymentMethod struct {
+	CompanyID pgtype.UUID        `json:"company_id"`
+	Gateway   string             `json:"gateway"`
+	Currency  string             `json:"currency"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CompanyWallet struct {
+	ID                  pgtype.UUID        `json:"id"`
+	CompanyID           pgtype.UUID        `json:"company_id"`
+	Currency            string             `json:"currency"`
+	Balance             pgtype.Numeric     `json:"balance"`
+	CreditLimit         pgtype.Numeric     `json:"credit_limit"`
+	LowBalanceThreshold pgtype.Numeric     `json:"low_balance_threshold"`
+	AlertEmail          *string            `json:"alert_email"`
+	LowBalanceAlertedAt pgtype.Timestamptz `json:"low_balance_alerted_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ComplianceReminder struct {
+	ID            pgtype.UUID        `json:"id"`
+	DocumentType  string             `json:"document_type"`
+	SubjectID     string             `json:"subject_id"`
+	ExpiresAt     pgtype.Date        `json:"expires_at"`
+	ThresholdDays int32              `json:"threshold_days"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+}
+
+type Document struct {
+	ID          pgtype.UUID        `json:"id"`
+	OwnerType   string             `json:"owner_type"`
+	OwnerID     string             `json:"owner_id"`
+	Kind        string             `json:"kind"`
+	StorageKey  string             `json:"storage_key"`
+	FileName    string             `json:"file_name"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	Status      string             `json:"status"`
+	ReviewNote  *string            `json:"review_note"`
+	ReviewedBy  pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt  pgtype.Timestamptz `json:"reviewed_at"`
+	UploadedBy  pgtype.UUID        `json:"uploaded_by"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Driver struct {
 	ID        string             `json:"id"`
 	FirstName string             `json:"first_name"`
@@ -664,14 +755,36 @@ type DriverBackgroundCheck struct {
 	CheckedAt pgtype.Timestamptz    `json:"checked_at"`
 }
 
-type DriverFeedback struct {
+type DriverEarning struct {
 	ID            pgtype.UUID        `json:"id"`
 	DriverID      string             `json:"driver_id"`
-	ReservationID string             `json:"reservation_id"`
-	Rating        pgtype.Numeric     `json:"rating"`
-	Comment       *string            `json:"comment"`
+	ReservationID *string            `json:"reservation_id"`
+	Type          string             `json:"type"`
+	Amount        pgtype.Numeric     `json:"amount"`
+	Currency      string             `json:"currency"`
+	GrossFare     pgtype.Numeric     `json:"gross_fare"`
+	Commission    pgtype.Numeric     `json:"commission"`
+	Description   *string            `json:"description"`
+	SettlementID  pgtype.UUID        `json:"settlement_id"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type DriverFeedback struct {
+	ID             pgtype.UUID        `json:"id"`
+	DriverID       string             `json:"driver_id"`
+	ReservationID  string             `json:"reservation_id"`
+	Rating         pgtype.Numeric     `json:"rating"`
+	Comment        *string            `json:"comment"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	Tags           []byte             `json:"tags"`
+	Status         string             `json:"status"`
+	ModerationNote *string            `json:"moderation_note"`
+	ModeratedBy    pgtype.UUID        `json:"moderated_by"`
+	ModeratedAt    pgtype.Timestamptz `json:"moderated_at"`
 }
 
 type DriverLicense struct {
@@ -683,6 +796,78 @@ type DriverLicense struct {
 	FileUrl   *string      `json:"file_url"`
 }
 
+type DriverLocation struct {
+	ID            int64              `json:"id"`
+	DriverID      string             `json:"driver_id"`
+	ReservationID *string            `json:"reservation_id"`
+	Latitude      float64            `json:"latitude"`
+	Longitude     float64            `json:"longitude"`
+	Accuracy      *float32           `json:"accuracy"`
+	Speed         *float32           `json:"speed"`
+	Heading       *float32           `json:"heading"`
+	RecordedAt    pgtype.Timestamptz `json:"recorded_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type DriverSettlement struct {
+	ID               pgtype.UUID        `json:"id"`
+	DriverID         string             `json:"driver_id"`
+	Period           string             `json:"period"`
+	PeriodStart      pgtype.Timestamptz `json:"period_start"`
+	PeriodEnd        pgtype.Timestamptz `json:"period_end"`
+	Status           string             `json:"status"`
+	Currency         string             `json:"currency"`
+	TripCount        int32              `json:"trip_count"`
+	TripPayouts      pgtype.Numeric     `json:"trip_payouts"`
+	Tips             pgtype.Numeric     `json:"tips"`
+	Compensations    pgtype.Numeric     `json:"compensations"`
+	Adjustments      pgtype.Numeric     `json:"adjustments"`
+	Penalties        pgtype.Numeric     `json:"penalties"`
+	Total            pgtype.Numeric     `json:"total"`
+	PaymentReference *string            `json:"payment_reference"`
+	ApprovedBy       pgtype.UUID        `json:"approved_by"`
+	ApprovedAt       pgtype.Timestamptz `json:"approved_at"`
+	PaidBy           pgtype.UUID        `json:"paid_by"`
+	PaidAt           pgtype.Timestamptz `json:"paid_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type DteFolioRange struct {
+	ID           pgtype.UUID        `json:"id"`
+	DocType      int16              `json:"doc_type"`
+	FolioFrom    int32              `json:"folio_from"`
+	FolioTo      int32              `json:"folio_to"`
+	NextFolio    int32              `json:"next_folio"`
+	AuthorizedAt pgtype.Date        `json:"authorized_at"`
+	Caf          []byte             `json:"caf"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type FeedbackRequest struct {
+	ReservationID string             `json:"reservation_id"`
+	UserID        pgtype.UUID        `json:"user_id"`
+	DriverID      string             `json:"driver_id"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type FuelEvent struct {
+	ID            pgtype.UUID        `json:"id"`
+	ReservationID string             `json:"reservation_id"`
+	DriverID      string             `json:"driver_id"`
+	VehicleID     pgtype.UUID        `json:"vehicle_id"`
+	Type          string             `json:"type"`
+	Quantity      pgtype.Numeric     `json:"quantity"`
+	Cost          pgtype.Numeric     `json:"cost"`
+	Currency      string             `json:"currency"`
+	OdometerKm    *int32             `json:"odometer_km"`
+	Station       *string            `json:"station"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type Hotel struct {
 	ID           pgtype.UUID        `json:"id"`
 	Name         string             `json:"name"`
@@ -692,6 +877,119 @@ type Hotel struct {
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
 
+type IdempotencyKey struct {
+	Scope        string             `json:"scope"`
+	Key          string             `json:"key"`
+	Fingerprint  string             `json:"fingerprint"`
+	StatusCode   *int32             `json:"status_code"`
+	ContentType  *string            `json:"content_type"`
+	ResponseBody []byte             `json:"response_body"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Incident struct {
+	ID              pgtype.UUID        `json:"id"`
+	VehicleID       pgtype.UUID        `json:"vehicle_id"`
+	DriverID        *string            `json:"driver_id"`
+	ReservationID   *string            `json:"reservation_id"`
+	Type            string             `json:"type"`
+	Severity        string             `json:"severity"`
+	Status          string             `json:"status"`
+	Description     string             `json:"description"`
+	Location        *string            `json:"location"`
+	OccurredAt      pgtype.Timestamptz `json:"occurred_at"`
+	Cost            pgtype.Numeric     `json:"cost"`
+	Currency        string             `json:"currency"`
+	ResolutionNotes *string            `json:"resolution_notes"`
+	ResolvedAt      pgtype.Timestamptz `json:"resolved_at"`
+	ReportedBy      pgtype.UUID        `json:"reported_by"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Invoice struct {
+	ID          pgtype.UUID        `json:"id"`
+	Number      string             `json:"number"`
+	AccountID   pgtype.UUID        `json:"account_id"`
+	CompanyID   pgtype.UUID        `json:"company_id"`
+	PeriodStart pgtype.Timestamptz `json:"period_start"`
+	PeriodEnd   pgtype.Timestamptz `json:"period_end"`
+	Status      string             `json:"status"`
+	Currency    string             `json:"currency"`
+	Subtotal    pgtype.Numeric     `json:"subtotal"`
+	TaxRate     pgtype.Numeric     `json:"tax_rate"`
+	TaxAmount   pgtype.Numeric     `json:"tax_amount"`
+	Total       pgtype.Numeric     `json:"total"`
+	IssuedAt    pgtype.Timestamptz `json:"issued_at"`
+	DueDate     pgtype.Date        `json:"due_date"`
+	PaidAt      pgtype.Timestamptz `json:"paid_at"`
+	PaymentID   pgtype.UUID        `json:"payment_id"`
+	VoidedAt    pgtype.Timestamptz `json:"voided_at"`
+	VoidReason  *string            `json:"void_reason"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type InvoiceLine struct {
+	ID            pgtype.UUID    `json:"id"`
+	InvoiceID     pgtype.UUID    `json:"invoice_id"`
+	Position      int32          `json:"position"`
+	ReservationID *string        `json:"reservation_id"`
+	Description   string         `json:"description"`
+	Quantity      int32          `json:"quantity"`
+	UnitPrice     pgtype.Numeric `json:"unit_price"`
+	Amount        pgtype.Numeric `json:"amount"`
+}
+
+type MaintenancePlan struct {
+	ID                  pgtype.UUID        `json:"id"`
+	VehicleID           pgtype.UUID        `json:"vehicle_id"`
+	ServiceType         string             `json:"service_type"`
+	IntervalKm          *int32             `json:"interval_km"`
+	IntervalDays        *int32             `json:"interval_days"`
+	LastServiceAt       pgtype.Timestamptz `json:"last_service_at"`
+	LastServiceOdometer *int32             `json:"last_service_odometer"`
+	Active              bool               `json:"active"`
+	Notes               *string            `json:"notes"`
+	ReminderSentAt      pgtype.Timestamptz `json:"reminder_sent_at"`
+	CreatedBy           pgtype.UUID        `json:"created_by"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MaintenanceRecord struct {
+	ID             pgtype.UUID        `json:"id"`
+	VehicleID      pgtype.UUID        `json:"vehicle_id"`
+	PlanID         pgtype.UUID        `json:"plan_id"`
+	ServiceType    string             `json:"service_type"`
+	Status         string             `json:"status"`
+	ScheduledStart pgtype.Timestamptz `json:"scheduled_start"`
+	ScheduledEnd   pgtype.Timestamptz `json:"scheduled_end"`
+	PerformedAt    pgtype.Timestamptz `json:"performed_at"`
+	Odometer       *int32             `json:"odometer"`
+	Cost           pgtype.Numeric     `json:"cost"`
+	Currency       string             `json:"currency"`
+	Workshop       *string            `json:"workshop"`
+	Description    *string            `json:"description"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type OdometerReading struct {
+	ID            pgtype.UUID        `json:"id"`
+	ReservationID string             `json:"reservation_id"`
+	DriverID      string             `json:"driver_id"`
+	VehicleID     pgtype.UUID        `json:"vehicle_id"`
+	Kind          string             `json:"kind"`
+	ReadingKm     int32              `json:"reading_km"`
+	RecordedAt    pgtype.Timestamptz `json:"recorded_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 // Stores password reset tokens for users
 type PasswordResetToken struct {
 	ID     pgtype.UUID `json:"id"`
@@ -707,7 +1005,7 @@ type PasswordResetToken struct {
 
 type Payment struct {
 	ID             pgtype.UUID        `json:"id"`
-	ReservationID  string             `json:"reservation_id"`
+	ReservationID  *string            `json:"reservation_id"`
 	Gateway        PaymentGateway     `json:"gateway"`
 	Amount         pgtype.Numeric     `json:"amount"`
 	Currency       string             `json:"currency"`
@@ -715,6 +1013,22 @@ type Payment struct {
 	TransactionRef *string            `json:"transaction_ref"`
 	Payload        []byte             `json:"payload"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	InvoiceID      pgtype.UUID        `json:"invoice_id"`
+	WalletID       pgtype.UUID        `json:"wallet_id"`
+}
+
+type PaymentNotification struct {
+	ID             pgtype.UUID        `json:"id"`
+	Gateway        string             `json:"gateway"`
+	EventID        string             `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Status         string             `json:"status"`
+	PaymentID      pgtype.UUID        `json:"payment_id"`
+	TransactionRef *string            `json:"transaction_ref"`
+	Payload        []byte             `json:"payload"`
+	Error          *string            `json:"error"`
+	ReceivedAt     pgtype.Timestamptz `json:"received_at"`
+	ProcessedAt    pgtype.Timestamptz `json:"processed_at"`
 }
 
 type RefreshToken struct {
@@ -725,6 +1039,21 @@ type RefreshToken struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type Refund struct {
+	ID             pgtype.UUID        `json:"id"`
+	PaymentID      pgtype.UUID        `json:"payment_id"`
+	Amount         pgtype.Numeric     `json:"amount"`
+	Currency       string             `json:"currency"`
+	Reason         string             `json:"reason"`
+	Status         string             `json:"status"`
+	TransactionRef *string            `json:"transaction_ref"`
+	Payload        []byte             `json:"payload"`
+	FailureReason  *string            `json:"failure_reason"`
+	RequestedBy    pgtype.UUID        `json:"requested_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type RegistrationToken struct {
 	ID        pgtype.UUID        `json:"id"`
 	Token     string             `json:"token"`
@@ -770,6 +1099,12 @@ type Reservation struct {
 	AssignedDriverID *string            `json:"assigned_driver_id"`
 	DistanceKm       pgtype.Numeric     `json:"distance_km"`
 	ArrivedOnTime    *bool              `json:"arrived_on_time"`
+	ActualDistanceKm pgtype.Numeric     `json:"actual_distance_km"`
+	VehicleType      NullVehicleType    `json:"vehicle_type"`
+	CostCenter       *string            `json:"cost_center"`
+	Commission       pgtype.Numeric     `json:"commission"`
+	DriverPayout     pgtype.Numeric     `json:"driver_payout"`
+	Currency         *string            `json:"currency"`
 }
 
 type ReservationTimeline struct {
@@ -782,6 +1117,103 @@ type ReservationTimeline struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
+type Shift struct {
+	ID        pgtype.UUID        `json:"id"`
+	DriverID  string             `json:"driver_id"`
+	VehicleID pgtype.UUID        `json:"vehicle_id"`
+	StartsAt  pgtype.Timestamptz `json:"starts_at"`
+	EndsAt    pgtype.Timestamptz `json:"ends_at"`
+	Region    *string            `json:"region"`
+	Status    string             `json:"status"`
+	Notes     *string            `json:"notes"`
+	CreatedBy pgtype.UUID        `json:"created_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ShiftChangeRequest struct {
+	ID             pgtype.UUID        `json:"id"`
+	Type           string             `json:"type"`
+	DriverID       string             `json:"driver_id"`
+	ShiftID        pgtype.UUID        `json:"shift_id"`
+	TargetDriverID *string            `json:"target_driver_id"`
+	TargetShiftID  pgtype.UUID        `json:"target_shift_id"`
+	StartsAt       pgtype.Timestamptz `json:"starts_at"`
+	EndsAt         pgtype.Timestamptz `json:"ends_at"`
+	Reason         *string            `json:"reason"`
+	Status         string             `json:"status"`
+	ReviewNote     *string            `json:"review_note"`
+	ReviewedBy     pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt     pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TaxDocument struct {
+	ID              pgtype.UUID        `json:"id"`
+	DocType         int16              `json:"doc_type"`
+	Folio           int32              `json:"folio"`
+	FolioRangeID    pgtype.UUID        `json:"folio_range_id"`
+	IssueDate       pgtype.Date        `json:"issue_date"`
+	InvoiceID       pgtype.UUID        `json:"invoice_id"`
+	PaymentID       pgtype.UUID        `json:"payment_id"`
+	ReferenceID     pgtype.UUID        `json:"reference_id"`
+	ReferenceCode   *int16             `json:"reference_code"`
+	ReferenceReason *string            `json:"reference_reason"`
+	ReceiverRut     string             `json:"receiver_rut"`
+	ReceiverName    string             `json:"receiver_name"`
+	NetAmount       int64              `json:"net_amount"`
+	ExemptAmount    int64              `json:"exempt_amount"`
+	TaxRate         pgtype.Numeric     `json:"tax_rate"`
+	TaxAmount       int64              `json:"tax_amount"`
+	TotalAmount     int64              `json:"total_amount"`
+	Status          string             `json:"status"`
+	TrackID         *string            `json:"track_id"`
+	StatusDetail    *string            `json:"status_detail"`
+	SubmittedAt     pgtype.Timestamptz `json:"submitted_at"`
+	Ted             string             `json:"ted"`
+	Xml             []byte             `json:"xml"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TrackingLink struct {
+	ID            pgtype.UUID        `json:"id"`
+	ReservationID string             `json:"reservation_id"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt     pgtype.Timestamptz `json:"revoked_at"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type TripOffer struct {
+	ID            pgtype.UUID        `json:"id"`
+	ReservationID string             `json:"reservation_id"`
+	DriverID      string             `json:"driver_id"`
+	Position      int32              `json:"position"`
+	Status        string             `json:"status"`
+	OfferedAt     pgtype.Timestamptz `json:"offered_at"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	RespondedAt   pgtype.Timestamptz `json:"responded_at"`
+	DeclineReason *string            `json:"decline_reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TripProgress struct {
+	ReservationID string             `json:"reservation_id"`
+	DriverID      string             `json:"driver_id"`
+	Status        string             `json:"status"`
+	EnRouteAt     pgtype.Timestamptz `json:"en_route_at"`
+	ArrivedAt     pgtype.Timestamptz `json:"arrived_at"`
+	OnboardAt     pgtype.Timestamptz `json:"onboard_at"`
+	FinishedAt    pgtype.Timestamptz `json:"finished_at"`
+	NoShowAt      pgtype.Timestamptz `json:"no_show_at"`
+	ArrivedOnTime *bool              `json:"arrived_on_time"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
 type User struct {
 	ID           pgtype.UUID        `json:"id"`
 	Name         string             `json:"name"`
@@ -815,9 +1247,40 @@ type Vehicle struct {
 	Capacity            *int32             `json:"capacity"`
 }
 
+type VehicleAssignment struct {
+	ID         pgtype.UUID        `json:"id"`
+	VehicleID  pgtype.UUID        `json:"vehicle_id"`
+	DriverID   string             `json:"driver_id"`
+	AssignedAt pgtype.Timestamptz `json:"assigned_at"`
+	ReleasedAt pgtype.Timestamptz `json:"released_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type VehiclePhoto struct {
 	ID        pgtype.UUID        `json:"id"`
 	VehicleID pgtype.UUID        `json:"vehicle_id"`
 	Url       string             `json:"url"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
+
+type WalletLedgerEntry struct {
+	ID            pgtype.UUID    `json:"id"`
+	TransactionID pgtype.UUID    `json:"transaction_id"`
+	Account       string         `json:"account"`
+	WalletID      pgtype.UUID    `json:"wallet_id"`
+	Amount        pgtype.Numeric `json:"amount"`
+}
+
+type WalletTransaction struct {
+	ID            pgtype.UUID        `json:"id"`
+	WalletID      pgtype.UUID        `json:"wallet_id"`
+	Kind          string             `json:"kind"`
+	Amount        pgtype.Numeric     `json:"amount"`
+	BalanceAfter  pgtype.Numeric     `json:"balance_after"`
+	Description   string             `json:"description"`
+	PaymentID     pgtype.UUID        `json:"payment_id"`
+	ReservationID *string            `json:"reservation_id"`
+	RefundID      pgtype.UUID        `json:"refund_id"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
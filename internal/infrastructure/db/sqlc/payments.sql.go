@@ -32,20 +32,22 @@ func (q *Queries) CountPayments(ctx context.Context, arg CountPaymentsParams) (i
 }
 
 const createPayment = `-- name: CreatePayment :one
-INSERT INTO payments (id, reservation_id, gateway, amount, currency, status, transaction_ref, payload)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at
+INSERT INTO payments (id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, invoice_id, wallet_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id
 `
 
 type CreatePaymentParams struct {
 	ID             pgtype.UUID    `json:"id"`
-	ReservationID  string         `json:"reservation_id"`
+	ReservationID  *string        `json:"reservation_id"`
 	Gateway        PaymentGateway `json:"gateway"`
 	Amount         pgtype.Numeric `json:"amount"`
 	Currency       string         `json:"currency"`
 	Status         PaymentStatus  `json:"status"`
 	TransactionRef *string        `json:"transaction_ref"`
 	Payload        []byte         `json:"payload"`
+	InvoiceID      pgtype.UUID    `json:"invoice_id"`
+	WalletID       pgtype.UUID    `json:"wallet_id"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
@@ -58,6 +60,8 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		arg.Status,
 		arg.TransactionRef,
 		arg.Payload,
+		arg.InvoiceID,
+		arg.WalletID,
 	)
 	var i Payment
 	err := row.Scan(
@@ -70,12 +74,14 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.TransactionRef,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.InvoiceID,
+		&i.WalletID,
 	)
 	return i, err
 }
 
 const getPaymentByID = `-- name: GetPaymentByID :one
-SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at FROM payments WHERE id = $1
+SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id FROM payments WHERE id = $1
 `
 
 func (q *Queries) GetPaymentByID(ctx context.Context, id pgtype.UUID) (Payment, error) {
@@ -91,12 +97,14 @@ func (q *Queries) GetPaymentByID(ctx context.Context, id pgtype.UUID) (Payment,
 		&i.TransactionRef,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.InvoiceID,
+		&i.WalletID,
 	)
 	return i, err
 }
 
 const getPaymentByTransactionRef = `-- name: GetPaymentByTransactionRef :one
-SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at FROM payments WHERE transaction_ref = $1
+SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id FROM payments WHERE transaction_ref = $1
 `
 
 func (q *Queries) GetPaymentByTransactionRef(ctx context.Context, transactionRef *string) (Payment, error) {
@@ -112,15 +120,17 @@ func (q *Queries) GetPaymentByTransactionRef(ctx context.Context, transactionRef
 		&i.TransactionRef,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.InvoiceID,
+		&i.WalletID,
 	)
 	return i, err
 }
 
 const getPaymentsByReservationID = `-- name: GetPaymentsByReservationID :many
-SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at FROM payments WHERE reservation_id = $1 ORDER BY created_at DESC
+SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id FROM payments WHERE reservation_id = $1 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetPaymentsByReservationID(ctx context.Context, reservationID string) ([]Payment, error) {
+func (q *Queries) GetPaymentsByReservationID(ctx context.Context, reservationID *string) ([]Payment, error) {
 	rows, err := q.db.Query(ctx, getPaymentsByReservationID, reservationID)
 	if err != nil {
 		return nil, err
@@ -139,6 +149,8 @@ func (q *Queries) GetPaymentsByReservationID(ctx context.Context, reservationID
 			&i.TransactionRef,
 			&i.Payload,
 			&i.CreatedAt,
+			&i.InvoiceID,
+			&i.WalletID,
 		); err != nil {
 			return nil, err
 		}
@@ -151,7 +163,7 @@ func (q *Queries) GetPaymentsByReservationID(ctx context.Context, reservationID
 }
 
 const getPaymentsByStatus = `-- name: GetPaymentsByStatus :many
-SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at FROM payments
+SELECT id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id FROM payments
 WHERE status = $1
 ORDER BY created_at DESC
 `
@@ -175,6 +187,8 @@ func (q *Queries) GetPaymentsByStatus(ctx context.Context, status PaymentStatus)
 			&i.TransactionRef,
 			&i.Payload,
 			&i.CreatedAt,
+			&i.InvoiceID,
+			&i.WalletID,
 		); err != nil {
 			return nil, err
 		}
@@ -187,7 +201,7 @@ func (q *Queries) GetPaymentsByStatus(ctx context.Context, status PaymentStatus)
 }
 
 const listPayments = `-- name: ListPayments :many
-SELECT p.id, p.reservation_id, p.gateway, p.amount, p.currency, p.status, p.transaction_ref, p.payload, p.created_at, r.pickup, r.destination 
+SELECT p.id, p.reservation_id, p.gateway, p.amount, p.currency, p.status, p.transaction_ref, p.payload, p.created_at, p.invoice_id, p.wallet_id, r.pickup, r.destination 
 FROM payments p
 LEFT JOIN reservations r ON p.reservation_id = r.id
 WHERE ($1::text IS NULL OR p.reservation_id ILIKE '%' || $1 || '%' OR p.transaction_ref ILIKE '%' || $1 || '%')
@@ -210,7 +224,7 @@ type ListPaymentsParams struct {
 
 type ListPaymentsRow struct {
 	ID             pgtype.UUID        `json:"id"`
-	ReservationID  string             `json:"reservation_id"`
+	ReservationID  *string            `json:"reservation_id"`
 	Gateway        PaymentGateway     `json:"gateway"`
 	Amount         pgtype.Numeric     `json:"amount"`
 	Currency       string             `json:"currency"`
@@ -218,6 +232,8 @@ type ListPaymentsRow struct {
 	TransactionRef *string            `json:"transaction_ref"`
 	Payload        []byte             `json:"payload"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	InvoiceID      pgtype.UUID        `json:"invoice_id"`
+	WalletID       pgtype.UUID        `json:"wallet_id"`
 	Pickup         *string            `json:"pickup"`
 	Destination    *string            `json:"destination"`
 }
@@ -248,6 +264,8 @@ func (q *Queries) ListPayments(ctx context.Context, arg ListPaymentsParams) ([]L
 			&i.TransactionRef,
 			&i.Payload,
 			&i.CreatedAt,
+			&i.InvoiceID,
+			&i.WalletID,
 			&i.Pickup,
 			&i.Destination,
 		); err != nil {
@@ -267,7 +285,7 @@ SET status = COALESCE($2, status),
     transaction_ref = COALESCE($3, transaction_ref),
     payload = COALESCE($4, payload)
 WHERE id = $1
-RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at
+RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id
 `
 
 type UpdatePaymentParams struct {
@@ -295,6 +313,8 @@ func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (P
 		&i.TransactionRef,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.InvoiceID,
+		&i.WalletID,
 	)
 	return i, err
 }
@@ -303,7 +323,7 @@ const updatePaymentStatus = `-- name: UpdatePaymentStatus :one
 UPDATE payments
 SET status = $2
 WHERE id = $1
-RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at
+RETURNING id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id
 `
 
 type UpdatePaymentStatusParams struct {
@@ -324,6 +344,8 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStat
 		&i.TransactionRef,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.InvoiceID,
+		&i.WalletID,
 	)
 	return i, err
 }
@@ -39,9 +39,9 @@ func (q *Queries) CountReservations(ctx context.Context, arg CountReservationsPa
 }
 
 const createReservation = `-- name: CreateReservation :one
-INSERT INTO reservations (id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, assigned_driver_id, distance_km)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time
+INSERT INTO reservations (id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, assigned_driver_id, distance_km, vehicle_type, cost_center, commission, driver_payout, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency
 `
 
 type CreateReservationParams struct {
@@ -57,6 +57,11 @@ type CreateReservationParams struct {
 	Notes            *string            `json:"notes"`
 	AssignedDriverID *string            `json:"assigned_driver_id"`
 	DistanceKm       pgtype.Numeric     `json:"distance_km"`
+	VehicleType      NullVehicleType    `json:"vehicle_type"`
+	CostCenter       *string            `json:"cost_center"`
+	Commission       pgtype.Numeric     `json:"commission"`
+	DriverPayout     pgtype.Numeric     `json:"driver_payout"`
+	Currency         *string            `json:"currency"`
 }
 
 func (q *Queries) CreateReservation(ctx context.Context, arg CreateReservationParams) (Reservation, error) {
@@ -73,6 +78,11 @@ func (q *Queries) CreateReservation(ctx context.Context, arg CreateReservationPa
 		arg.Notes,
 		arg.AssignedDriverID,
 		arg.DistanceKm,
+		arg.VehicleType,
+		arg.CostCenter,
+		arg.Commission,
+		arg.DriverPayout,
+		arg.Currency,
 	)
 	var i Reservation
 	err := row.Scan(
@@ -91,6 +101,12 @@ func (q *Queries) CreateReservation(ctx context.Context, arg CreateReservationPa
 		&i.AssignedDriverID,
 		&i.DistanceKm,
 		&i.ArrivedOnTime,
+		&i.ActualDistanceKm,
+		&i.VehicleType,
+		&i.CostCenter,
+		&i.Commission,
+		&i.DriverPayout,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -105,7 +121,7 @@ func (q *Queries) DeleteReservation(ctx context.Context, id string) error {
 }
 
 const getReservationByID = `-- name: GetReservationByID :one
-SELECT r.id, r.user_id, r.org_id, r.pickup, r.destination, r.datetime, r.passengers, r.status, r.amount, r.notes, r.created_at, r.updated_at, r.assigned_driver_id, r.distance_km, r.arrived_on_time, d.id as driver_id, d.first_name, d.last_name, d.phone, d.email, d.status as driver_status
+SELECT r.id, r.user_id, r.org_id, r.pickup, r.destination, r.datetime, r.passengers, r.status, r.amount, r.notes, r.created_at, r.updated_at, r.assigned_driver_id, r.distance_km, r.arrived_on_time, r.actual_distance_km, r.vehicle_type, r.cost_center, r.commission, r.driver_payout, r.currency, d.id as driver_id, d.first_name, d.last_name, d.phone, d.email, d.status as driver_status
 FROM reservations r
 LEFT JOIN drivers d ON r.assigned_driver_id = d.id
 WHERE r.id = $1
@@ -127,6 +143,12 @@ type GetReservationByIDRow struct {
 	AssignedDriverID *string            `json:"assigned_driver_id"`
 	DistanceKm       pgtype.Numeric     `json:"distance_km"`
 	ArrivedOnTime    *bool              `json:"arrived_on_time"`
+	ActualDistanceKm pgtype.Numeric     `json:"actual_distance_km"`
+	VehicleType      NullVehicleType    `json:"vehicle_type"`
+	CostCenter       *string            `json:"cost_center"`
+	Commission       pgtype.Numeric     `json:"commission"`
+	DriverPayout     pgtype.Numeric     `json:"driver_payout"`
+	Currency         *string            `json:"currency"`
 	DriverID         *string            `json:"driver_id"`
 	FirstName        *string            `json:"first_name"`
 	LastName         *string            `json:"last_name"`
@@ -154,6 +176,12 @@ func (q *Queries) GetReservationByID(ctx context.Context, id string) (GetReserva
 		&i.AssignedDriverID,
 		&i.DistanceKm,
 		&i.ArrivedOnTime,
+		&i.ActualDistanceKm,
+		&i.VehicleType,
+		&i.CostCenter,
+		&i.Commission,
+		&i.DriverPayout,
+		&i.Currency,
 		&i.DriverID,
 		&i.FirstName,
 		&i.LastName,
@@ -165,7 +193,7 @@ func (q *Queries) GetReservationByID(ctx context.Context, id string) (GetReserva
 }
 
 const getReservationsByDateRange = `-- name: GetReservationsByDateRange :many
-SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time FROM reservations
+SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency FROM reservations
 WHERE datetime BETWEEN $1 AND $2
 ORDER BY datetime ASC
 `
@@ -200,6 +228,12 @@ func (q *Queries) GetReservationsByDateRange(ctx context.Context, arg GetReserva
 			&i.AssignedDriverID,
 			&i.DistanceKm,
 			&i.ArrivedOnTime,
+			&i.ActualDistanceKm,
+			&i.VehicleType,
+			&i.CostCenter,
+			&i.Commission,
+			&i.DriverPayout,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -212,7 +246,7 @@ func (q *Queries) GetReservationsByDateRange(ctx context.Context, arg GetReserva
 }
 
 const getReservationsByStatus = `-- name: GetReservationsByStatus :many
-SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time FROM reservations
+SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency FROM reservations
 WHERE status = $1
 ORDER BY datetime ASC
 `
@@ -242,6 +276,12 @@ func (q *Queries) GetReservationsByStatus(ctx context.Context, status Reservatio
 			&i.AssignedDriverID,
 			&i.DistanceKm,
 			&i.ArrivedOnTime,
+			&i.ActualDistanceKm,
+			&i.VehicleType,
+			&i.CostCenter,
+			&i.Commission,
+			&i.DriverPayout,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -254,7 +294,7 @@ func (q *Queries) GetReservationsByStatus(ctx context.Context, status Reservatio
 }
 
 const listReservations = `-- name: ListReservations :many
-SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time FROM reservations
+SELECT id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency FROM reservations
 WHERE ($1::text IS NULL OR pickup ILIKE '%' || $1 || '%' OR destination ILIKE '%' || $1 || '%' OR id ILIKE '%' || $1 || '%')
   AND ($2::reservation_status IS NULL OR status = $2)
   AND ($3::uuid IS NULL OR user_id = $3)
@@ -309,6 +349,12 @@ func (q *Queries) ListReservations(ctx context.Context, arg ListReservationsPara
 			&i.AssignedDriverID,
 			&i.DistanceKm,
 			&i.ArrivedOnTime,
+			&i.ActualDistanceKm,
+			&i.VehicleType,
+			&i.CostCenter,
+			&i.Commission,
+			&i.DriverPayout,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -332,7 +378,7 @@ SET pickup = COALESCE($2, pickup),
     assigned_driver_id = COALESCE($9, assigned_driver_id),
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time
+RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency
 `
 
 type UpdateReservationParams struct {
@@ -376,6 +422,12 @@ func (q *Queries) UpdateReservation(ctx context.Context, arg UpdateReservationPa
 		&i.AssignedDriverID,
 		&i.DistanceKm,
 		&i.ArrivedOnTime,
+		&i.ActualDistanceKm,
+		&i.VehicleType,
+		&i.CostCenter,
+		&i.Commission,
+		&i.DriverPayout,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -385,7 +437,7 @@ UPDATE reservations
 SET status = $2,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time
+RETURNING id, user_id, org_id, pickup, destination, datetime, passengers, status, amount, notes, created_at, updated_at, assigned_driver_id, distance_km, arrived_on_time, actual_distance_km, vehicle_type, cost_center, commission, driver_payout, currency
 `
 
 type UpdateReservationStatusParams struct {
@@ -412,6 +464,12 @@ func (q *Queries) UpdateReservationStatus(ctx context.Context, arg UpdateReserva
 		&i.AssignedDriverID,
 		&i.DistanceKm,
 		&i.ArrivedOnTime,
+		&i.ActualDistanceKm,
+		&i.VehicleType,
+		&i.CostCenter,
+		&i.Commission,
+		&i.DriverPayout,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -0,0 +1,301 @@
+package dte
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	dateFormat      = "2006-01-02"
+	timestampFormat = "2006-01-02T15:04:05"
+
+	// siiRUT receives every envelope
+	siiRUT = "60803000-K"
+	// Documents are for services (IndServicio)
+	serviceIndicator = 3
+	// Invoices are paid on credit (FmaPago)
+	creditPayment = 2
+)
+
+// Issuer is the company that issues the documents, as registered with the
+// SII.
+type Issuer struct {
+	RUT string
+	// Name is the razón social
+	Name string
+	// Activity is the giro and ActivityCode its economic activity code
+	// (Acteco)
+	Activity     string
+	ActivityCode int
+	Address      string
+	Commune      string
+	// The SII resolution that authorized the issuer
+	ResolutionDate   time.Time
+	ResolutionNumber int
+	// SenderRUT is the RUT of the holder of the signing certificate
+	SenderRUT string
+}
+
+// Builder renders documents in the SII DTE schema, stamps them with their
+// CAF and signs them and their envelopes with the issuer's certificate.
+type Builder struct {
+	issuer Issuer
+	signer *Signer
+	now    func() time.Time
+}
+
+func NewBuilder(issuer Issuer, signer *Signer) (*Builder, error) {
+	rut, ok := domain.NormalizeRUT(issuer.RUT)
+	if !ok {
+		return nil, fmt.Errorf("invalid issuer RUT %q", issuer.RUT)
+	}
+	issuer.RUT = rut
+
+	sender, ok := domain.NormalizeRUT(issuer.SenderRUT)
+	if !ok {
+		return nil, fmt.Errorf("invalid sender RUT %q", issuer.SenderRUT)
+	}
+	issuer.SenderRUT = sender
+
+	return &Builder{
+		issuer: issuer,
+		signer: signer,
+		now:    time.Now,
+	}, nil
+}
+
+func (b *Builder) ParseCAF(raw []byte) (*domain.FolioRange, error) {
+	parsed, err := parseCAF(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCAF, err)
+	}
+	if parsed.issuerRUT != b.issuer.RUT {
+		return nil, fmt.Errorf("%w: issued to %s, not to %s", domain.ErrInvalidCAF, parsed.issuerRUT, b.issuer.RUT)
+	}
+
+	return &domain.FolioRange{
+		Type:         parsed.docType,
+		From:         parsed.from,
+		To:           parsed.to,
+		Next:         parsed.from,
+		AuthorizedAt: parsed.authorizedAt,
+		CAF:          raw,
+	}, nil
+}
+
+func (b *Builder) Build(doc *domain.TaxDocument, folios *domain.FolioRange) error {
+	parsed, err := parseCAF(folios.CAF)
+	if err != nil {
+		return fmt.Errorf("failed to read CAF: %w", err)
+	}
+	if parsed.docType != doc.Type || doc.Folio < parsed.from || doc.Folio > parsed.to {
+		return fmt.Errorf("folio %d of type %d is not in the CAF", doc.Folio, doc.Type)
+	}
+
+	now := b.now()
+	ted, err := b.ted(doc, parsed, now)
+	if err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("T%dF%d", doc.Type, doc.Folio)
+
+	var w xmlWriter
+	w.open("Documento", "ID", id)
+	b.writeHeader(&w, doc)
+	for i, line := range doc.Lines {
+		w.open("Detalle")
+		w.int("NroLinDet", int64(i+1))
+		w.text("NmbItem", line.Description, 80)
+		w.int("QtyItem", int64(line.Quantity))
+		w.int("PrcItem", line.UnitPrice)
+		w.int("MontoItem", line.Amount)
+		w.close("Detalle")
+	}
+	if ref := doc.Reference; ref != nil {
+		w.open("Referencia")
+		w.int("NroLinRef", 1)
+		w.text("TpoDocRef", strconv.Itoa(int(ref.Type)), 0)
+		w.text("FolioRef", strconv.Itoa(ref.Folio), 0)
+		w.text("FchRef", ref.Date.Format(dateFormat), 0)
+		w.int("CodRef", int64(ref.Code))
+		w.text("RazonRef", ref.Reason, 90)
+		w.close("Referencia")
+	}
+	w.raw(ted)
+	w.text("TmstFirma", now.Format(timestampFormat), 0)
+	w.close("Documento")
+
+	documento := w.String()
+	signature, err := b.signer.sign(id, withNamespace(documento, "Documento", siiNamespace))
+	if err != nil {
+		return err
+	}
+
+	doc.TED = ted
+	doc.XML = encodeLatin1(xmlDeclaration + `<DTE xmlns="` + siiNamespace + `" version="1.0">` + documento + signature + `</DTE>`)
+	return nil
+}
+
+func (b *Builder) writeHeader(w *xmlWriter, doc *domain.TaxDocument) {
+	boleta := doc.Type == domain.DTETypeBoleta
+
+	w.open("Encabezado")
+
+	w.open("IdDoc")
+	w.int("TipoDTE", int64(doc.Type))
+	w.int("Folio", int64(doc.Folio))
+	w.text("FchEmis", doc.IssueDate.Format(dateFormat), 0)
+	w.int("IndServicio", serviceIndicator)
+	if !boleta && doc.DueDate != nil {
+		w.int("FmaPago", creditPayment)
+	}
+	if doc.PeriodFrom != nil && doc.PeriodTo != nil {
+		w.text("PeriodoDesde", doc.PeriodFrom.Format(dateFormat), 0)
+		w.text("PeriodoHasta", doc.PeriodTo.Format(dateFormat), 0)
+	}
+	if doc.DueDate != nil {
+		w.text("FchVenc", doc.DueDate.Format(dateFormat), 0)
+	}
+	w.close("IdDoc")
+
+	w.open("Emisor")
+	w.text("RUTEmisor", b.issuer.RUT, 0)
+	if boleta {
+		w.text("RznSocEmisor", b.issuer.Name, 100)
+		w.text("GiroEmisor", b.issuer.Activity, 80)
+	} else {
+		w.text("RznSoc", b.issuer.Name, 100)
+		w.text("GiroEmis", b.issuer.Activity, 80)
+		w.int("Acteco", int64(b.issuer.ActivityCode))
+	}
+	w.text("DirOrigen", b.issuer.Address, 70)
+	w.text("CmnaOrigen", b.issuer.Commune, 20)
+	w.close("Emisor")
+
+	w.open("Receptor")
+	w.text("RUTRecep", doc.ReceiverRUT, 0)
+	w.text("RznSocRecep", doc.ReceiverName, 100)
+	w.close("Receptor")
+
+	w.open("Totales")
+	if doc.NetAmount > 0 || !doc.IsExempt() {
+		w.int("MntNeto", doc.NetAmount)
+	}
+	if doc.ExemptAmount > 0 {
+		w.int("MntExe", doc.ExemptAmount)
+	}
+	if !doc.IsExempt() {
+		if !boleta {
+			w.text("TasaIVA", strconv.FormatFloat(math.Round(doc.TaxRate*10000)/100, 'f', -1, 64), 0)
+		}
+		w.int("IVA", doc.TaxAmount)
+	}
+	w.int("MntTotal", doc.TotalAmount)
+	w.close("Totales")
+
+	w.close("Encabezado")
+}
+
+// ted builds the electronic stamp (Timbre Electrónico DTE): the key data of
+// the document signed with the CAF key, so it can be checked offline from
+// its printed PDF417.
+func (b *Builder) ted(doc *domain.TaxDocument, parsed *caf, at time.Time) (string, error) {
+	firstItem := ""
+	if len(doc.Lines) > 0 {
+		firstItem = doc.Lines[0].Description
+	}
+
+	var dd xmlWriter
+	dd.open("DD")
+	dd.text("RE", b.issuer.RUT, 0)
+	dd.int("TD", int64(doc.Type))
+	dd.int("F", int64(doc.Folio))
+	dd.text("FE", doc.IssueDate.Format(dateFormat), 0)
+	dd.text("RR", doc.ReceiverRUT, 0)
+	dd.text("RSR", doc.ReceiverName, 40)
+	dd.int("MNT", doc.TotalAmount)
+	dd.text("IT1", firstItem, 40)
+	dd.raw(parsed.element)
+	dd.text("TSTED", at.Format(timestampFormat), 0)
+	dd.close("DD")
+
+	// The SII checks the stamp over the ISO-8859-1 bytes of DD
+	signature, err := signSHA1(parsed.key, encodeLatin1(dd.String()))
+	if err != nil {
+		return "", err
+	}
+
+	var w xmlWriter
+	w.open("TED", "version", "1.0")
+	w.raw(dd.String())
+	w.open("FRMT", "algoritmo", "SHA1withRSA")
+	w.raw(signature)
+	w.close("FRMT")
+	w.close("TED")
+	return w.String(), nil
+}
+
+// Envelope wraps documents in an EnvioDTE, or an EnvioBOLETA for boletas,
+// addressed to the SII and signed by the sender.
+func (b *Builder) Envelope(docs []*domain.TaxDocument) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to send")
+	}
+
+	root := "EnvioDTE"
+	if docs[0].Type == domain.DTETypeBoleta {
+		root = "EnvioBOLETA"
+	}
+
+	var counts []domain.DTEType
+	perType := make(map[domain.DTEType]int)
+	for _, doc := range docs {
+		if (doc.Type == domain.DTETypeBoleta) != (root == "EnvioBOLETA") {
+			return nil, fmt.Errorf("boletas are sent apart from other documents")
+		}
+		if perType[doc.Type] == 0 {
+			counts = append(counts, doc.Type)
+		}
+		perType[doc.Type]++
+	}
+
+	const setID = "SetDoc"
+
+	var w xmlWriter
+	w.open("SetDTE", "ID", setID)
+	w.open("Caratula", "version", "1.0")
+	w.text("RutEmisor", b.issuer.RUT, 0)
+	w.text("RutEnvia", b.issuer.SenderRUT, 0)
+	w.text("RutReceptor", siiRUT, 0)
+	w.text("FchResol", b.issuer.ResolutionDate.Format(dateFormat), 0)
+	w.int("NroResol", int64(b.issuer.ResolutionNumber))
+	w.text("TmstFirmaEnv", b.now().Format(timestampFormat), 0)
+	for _, docType := range counts {
+		w.open("SubTotDTE")
+		w.int("TpoDTE", int64(docType))
+		w.int("NroDTE", int64(perType[docType]))
+		w.close("SubTotDTE")
+	}
+	w.close("Caratula")
+	for _, doc := range docs {
+		// Inside the set the DTE inherits the namespace
+		dte := strings.TrimPrefix(decodeLatin1(doc.XML), xmlDeclaration)
+		dte = strings.Replace(dte, `<DTE xmlns="`+siiNamespace+`" version="1.0">`, `<DTE version="1.0">`, 1)
+		w.raw(dte)
+	}
+	w.close("SetDTE")
+
+	set := w.String()
+	signature, err := b.signer.sign(setID, withNamespace(set, "SetDTE", siiNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeLatin1(xmlDeclaration + `<` + root + ` xmlns="` + siiNamespace + `" version="1.0">` + set + signature + `</` + root + `>`), nil
+}
@@ -0,0 +1,188 @@
+package dte
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const testIssuerRUT = "76086428-5"
+
+// testCAF returns a CAF like the SII issues, with a fresh key.
+func testCAF(t *testing.T, docType domain.DTEType, from, to int) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	modulus := base64.StdEncoding.EncodeToString(key.N.Bytes())
+	exponent := base64.StdEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	caf := fmt.Sprintf(`<?xml version="1.0"?>
+<AUTORIZACION>
+<CAF version="1.0">
+<DA>
+<RE>%s</RE>
+<RS>TURIVO SPA</RS>
+<TD>%d</TD>
+<RNG><D>%d</D><H>%d</H></RNG>
+<FA>2026-09-01</FA>
+<RSAPK><M>%s</M><E>%s</E></RSAPK>
+<IDK>100</IDK>
+</DA>
+<FRMA algoritmo="SHA1withRSA">c2lnbmVkIGJ5IHRoZSBTSUk=</FRMA>
+</CAF>
+<RSASK>%s</RSASK>
+</AUTORIZACION>
+`, testIssuerRUT, docType, from, to, modulus, exponent, privatePEM)
+
+	return []byte(caf), key
+}
+
+func testBuilder(t *testing.T) *Builder {
+	t.Helper()
+
+	signer, err := GenerateSigner("Firmante Turivo")
+	require.NoError(t, err)
+
+	builder, err := NewBuilder(Issuer{
+		RUT:              "76.086.428-5",
+		Name:             "Turivo SpA",
+		Activity:         "Transporte de pasajeros",
+		ActivityCode:     492250,
+		Address:          "Av. Apoquindo 1234",
+		Commune:          "Las Condes",
+		ResolutionDate:   time.Date(2014, 8, 22, 0, 0, 0, 0, time.UTC),
+		ResolutionNumber: 80,
+		SenderRUT:        "11111111-1",
+	}, signer)
+	require.NoError(t, err)
+	return builder
+}
+
+func TestParseCAF(t *testing.T) {
+	builder := testBuilder(t)
+	raw, _ := testCAF(t, domain.DTETypeFactura, 1, 50)
+
+	folios, err := builder.ParseCAF(raw)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DTETypeFactura, folios.Type)
+	assert.Equal(t, 1, folios.From)
+	assert.Equal(t, 50, folios.To)
+	assert.Equal(t, 1, folios.Next)
+
+	other := strings.Replace(string(raw), testIssuerRUT, "11111111-1", 1)
+	_, err = builder.ParseCAF([]byte(other))
+	assert.ErrorIs(t, err, domain.ErrInvalidCAF)
+
+	_, err = builder.ParseCAF([]byte("<AUTORIZACION>"))
+	assert.ErrorIs(t, err, domain.ErrInvalidCAF)
+}
+
+func TestBuildSignsDocumentAndStamp(t *testing.T) {
+	builder := testBuilder(t)
+	raw, cafKey := testCAF(t, domain.DTETypeFactura, 1, 50)
+	folios, err := builder.ParseCAF(raw)
+	require.NoError(t, err)
+
+	invoice := &domain.Invoice{
+		ID:          uuid.New(),
+		Currency:    "CLP",
+		TaxRate:     0.19,
+		PeriodStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []*domain.InvoiceLine{
+			{Description: "Viaje RSV-1 del 02-09-2026: Aeropuerto - Ñuñoa & Providencia", Quantity: 1, UnitPrice: 25000, Amount: 25000},
+		},
+	}
+	company := &domain.Company{Name: "Minera Norte Ltda.", RUT: "11.111.111-1"}
+	doc, err := domain.NewInvoiceTaxDocument(invoice, company, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	doc.Folio = 1
+
+	require.NoError(t, builder.Build(doc, folios))
+
+	xml := decodeLatin1(doc.XML)
+	assert.True(t, strings.HasPrefix(xml, `<?xml version="1.0" encoding="ISO-8859-1"?>`))
+	assert.Contains(t, xml, `<Documento ID="T33F1">`)
+	assert.Contains(t, xml, "<MntNeto>25000</MntNeto><TasaIVA>19</TasaIVA><IVA>4750</IVA><MntTotal>29750</MntTotal>")
+	assert.Contains(t, xml, "Ñuñoa &amp; Providencia")
+	assert.Contains(t, string(doc.XML), "\xd1u\xf1oa", "text is ISO-8859-1")
+	require.NoError(t, Verify(doc.XML))
+
+	// The stamp verifies with the CAF key over the ISO-8859-1 bytes of DD
+	dd := "<DD>" + between(doc.TED, "<DD>", "</DD>") + "</DD>"
+	assert.Contains(t, dd, "<RR>11111111-1</RR><RSR>Minera Norte Ltda.</RSR><MNT>29750</MNT>")
+	assert.Contains(t, dd, `<CAF version="1.0"><DA><RE>`+testIssuerRUT)
+	frmt, err := base64.StdEncoding.DecodeString(between(doc.TED, `<FRMT algoritmo="SHA1withRSA">`, "</FRMT>"))
+	require.NoError(t, err)
+	hashed := sha1.Sum(encodeLatin1(dd))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&cafKey.PublicKey, crypto.SHA1, hashed[:], frmt))
+
+	tampered := strings.Replace(string(doc.XML), "<MntTotal>29750</MntTotal>", "<MntTotal>1</MntTotal>", 1)
+	assert.Error(t, Verify([]byte(tampered)))
+
+	doc.Folio = 51
+	assert.Error(t, builder.Build(doc, folios))
+}
+
+func TestEnvelopeAndStandIn(t *testing.T) {
+	builder := testBuilder(t)
+	raw, _ := testCAF(t, domain.DTETypeBoleta, 10, 20)
+	folios, err := builder.ParseCAF(raw)
+	require.NoError(t, err)
+
+	var docs []*domain.TaxDocument
+	for folio := 10; folio < 12; folio++ {
+		payment := &domain.Payment{ID: uuid.New(), ReservationID: "RSV-" + fmt.Sprint(folio), Amount: 11900, Currency: "CLP"}
+		doc, err := domain.NewPaymentTaxDocument(payment, 0.19, time.Now())
+		require.NoError(t, err)
+		doc.Folio = folio
+		require.NoError(t, builder.Build(doc, folios))
+		docs = append(docs, doc)
+	}
+
+	envelope, err := builder.Envelope(docs)
+	require.NoError(t, err)
+
+	text := decodeLatin1(envelope)
+	assert.Contains(t, text, `<EnvioBOLETA xmlns="http://www.sii.cl/SiiDte" version="1.0"><SetDTE ID="SetDoc">`)
+	assert.Contains(t, text, "<SubTotDTE><TpoDTE>39</TpoDTE><NroDTE>2</NroDTE></SubTotDTE>")
+	assert.Contains(t, text, "<RznSocEmisor>Turivo SpA</RznSocEmisor>")
+	assert.Equal(t, 2, strings.Count(text, `<DTE version="1.0">`))
+
+	authority := NewLocalTaxAuthority(zap.NewNop())
+	trackID, err := authority.Submit(envelope)
+	require.NoError(t, err)
+	status, err := authority.GetStatus(trackID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaxDocumentStatusAccepted, status.Status)
+
+	tampered := strings.Replace(string(envelope), "<NroResol>80</NroResol>", "<NroResol>81</NroResol>", 1)
+	trackID, err = authority.Submit([]byte(tampered))
+	require.NoError(t, err)
+	status, err = authority.GetStatus(trackID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaxDocumentStatusRejected, status.Status)
+
+	_, err = builder.Envelope(append(docs, &domain.TaxDocument{Type: domain.DTETypeFactura}))
+	assert.Error(t, err)
+}
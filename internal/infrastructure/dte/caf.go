@@ -0,0 +1,126 @@
+package dte
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"turivo-backend/internal/domain"
+)
+
+// caf is a parsed folio authorization file. Element is the <CAF> element
+// with the SII signature, which goes verbatim into every TED.
+type caf struct {
+	issuerRUT    string
+	docType      domain.DTEType
+	from, to     int
+	authorizedAt time.Time
+	element      string
+	key          *rsa.PrivateKey
+}
+
+type cafFile struct {
+	CAF struct {
+		DA struct {
+			RE  string `xml:"RE"`
+			TD  int    `xml:"TD"`
+			RNG struct {
+				D int `xml:"D"`
+				H int `xml:"H"`
+			} `xml:"RNG"`
+			FA    string `xml:"FA"`
+			RSAPK struct {
+				M string `xml:"M"`
+				E string `xml:"E"`
+			} `xml:"RSAPK"`
+		} `xml:"DA"`
+	} `xml:"CAF"`
+	RSASK string `xml:"RSASK"`
+}
+
+var whitespaceBetweenTags = regexp.MustCompile(`>\s+<`)
+
+// parseCAF reads a CAF as downloaded from the SII. It checks that the
+// private key matches the public key of the authorization; the SII
+// signature over it is checked by the SII when documents arrive.
+func parseCAF(raw []byte) (*caf, error) {
+	text := string(raw)
+	if !utf8.Valid(raw) {
+		text = decodeLatin1(raw)
+	}
+
+	var file cafFile
+	decoder := xml.NewDecoder(strings.NewReader(text))
+	// The text is decoded already, whatever the declaration says
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("malformed CAF: %w", err)
+	}
+
+	da := file.CAF.DA
+	parsed := &caf{
+		docType: domain.DTEType(da.TD),
+		from:    da.RNG.D,
+		to:      da.RNG.H,
+	}
+	if !parsed.docType.Valid() {
+		return nil, fmt.Errorf("unsupported document type %d", da.TD)
+	}
+	if parsed.from <= 0 || parsed.to < parsed.from {
+		return nil, fmt.Errorf("invalid folio range %d-%d", parsed.from, parsed.to)
+	}
+
+	rut, ok := domain.NormalizeRUT(da.RE)
+	if !ok {
+		return nil, fmt.Errorf("invalid issuer RUT %q", da.RE)
+	}
+	parsed.issuerRUT = rut
+
+	authorizedAt, err := time.Parse("2006-01-02", strings.TrimSpace(da.FA))
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization date %q", da.FA)
+	}
+	parsed.authorizedAt = authorizedAt
+
+	start := strings.Index(text, "<CAF")
+	end := strings.Index(text, "</CAF>")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("CAF element not found")
+	}
+	parsed.element = whitespaceBetweenTags.ReplaceAllString(text[start:end+len("</CAF>")], "><")
+
+	block, _ := pem.Decode([]byte(strings.TrimSpace(file.RSASK)))
+	if block == nil {
+		return nil, fmt.Errorf("CAF private key not found")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAF private key: %w", err)
+	}
+	parsed.key = key
+
+	modulus, err := base64.StdEncoding.DecodeString(strings.TrimSpace(da.RSAPK.M))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAF public key: %w", err)
+	}
+	exponent, err := base64.StdEncoding.DecodeString(strings.TrimSpace(da.RSAPK.E))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAF public key: %w", err)
+	}
+	if key.N.Cmp(new(big.Int).SetBytes(modulus)) != 0 || big.NewInt(int64(key.E)).Cmp(new(big.Int).SetBytes(exponent)) != 0 {
+		return nil, fmt.Errorf("CAF private key does not match its public key")
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,259 @@
+package dte
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	c14nAlgorithm       = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	rsaSHA1Algorithm    = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	sha1DigestAlgorithm = "http://www.w3.org/2000/09/xmldsig#sha1"
+)
+
+// Signer signs documents and envelopes with the digital certificate of the
+// person authorized to send them to the SII.
+type Signer struct {
+	certificate *x509.Certificate
+	key         *rsa.PrivateKey
+}
+
+// LoadSigner reads a PEM certificate and its RSA private key, e.g. exported
+// from the .pfx issued by the certification authority with
+// openssl pkcs12 -nodes.
+func LoadSigner(certFile, keyFile string) (*Signer, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate in %s", certFile)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key in %s", keyFile)
+	}
+	key, err := parseRSAKey(block)
+	if err != nil {
+		return nil, err
+	}
+
+	public, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok || public.N.Cmp(key.N) != 0 {
+		return nil, errors.New("private key does not match the certificate")
+	}
+
+	return &Signer{certificate: certificate, key: key}, nil
+}
+
+// GenerateSigner creates a throwaway self-signed certificate, for the local
+// stand-in only: the SII does not accept it.
+func GenerateSigner(commonName string) (*Signer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return &Signer{certificate: certificate, key: key}, nil
+}
+
+func parseRSAKey(block *pem.Block) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// sign returns the enveloped XML signature of the element with the given ID,
+// whose canonical form is given.
+func (s *Signer) sign(id, canonical string) (string, error) {
+	digest := sha1.Sum([]byte(canonical))
+
+	var signedInfo xmlWriter
+	signedInfo.open("SignedInfo")
+	signedInfo.open("CanonicalizationMethod", "Algorithm", c14nAlgorithm)
+	signedInfo.close("CanonicalizationMethod")
+	signedInfo.open("SignatureMethod", "Algorithm", rsaSHA1Algorithm)
+	signedInfo.close("SignatureMethod")
+	signedInfo.open("Reference", "URI", "#"+id)
+	signedInfo.open("Transforms")
+	signedInfo.open("Transform", "Algorithm", c14nAlgorithm)
+	signedInfo.close("Transform")
+	signedInfo.close("Transforms")
+	signedInfo.open("DigestMethod", "Algorithm", sha1DigestAlgorithm)
+	signedInfo.close("DigestMethod")
+	signedInfo.text("DigestValue", base64.StdEncoding.EncodeToString(digest[:]), 0)
+	signedInfo.close("Reference")
+	signedInfo.close("SignedInfo")
+
+	signature, err := signSHA1(s.key, []byte(withNamespace(signedInfo.String(), "SignedInfo", dsigNamespace)))
+	if err != nil {
+		return "", err
+	}
+
+	var w xmlWriter
+	w.open("Signature", "xmlns", dsigNamespace)
+	w.raw(signedInfo.String())
+	w.text("SignatureValue", signature, 0)
+	w.open("KeyInfo")
+	w.open("KeyValue")
+	w.open("RSAKeyValue")
+	w.text("Modulus", base64.StdEncoding.EncodeToString(s.key.N.Bytes()), 0)
+	w.text("Exponent", base64.StdEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()), 0)
+	w.close("RSAKeyValue")
+	w.close("KeyValue")
+	w.open("X509Data")
+	w.text("X509Certificate", base64.StdEncoding.EncodeToString(s.certificate.Raw), 0)
+	w.close("X509Data")
+	w.close("KeyInfo")
+	w.close("Signature")
+	return w.String(), nil
+}
+
+// signSHA1 signs data with SHA1withRSA and returns it in base64.
+func signSHA1(key *rsa.PrivateKey, data []byte) (string, error) {
+	hashed := sha1.Sum(data)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// withNamespace declares on an element the default namespace it inherits,
+// which is how it reads when canonicalized on its own.
+func withNamespace(element, name, namespace string) string {
+	return `<` + name + ` xmlns="` + namespace + `"` + strings.TrimPrefix(element, "<"+name)
+}
+
+// Verify checks the signature of every element with an ID in a document or
+// envelope written by this package: its digest and the signature over it.
+func Verify(data []byte) error {
+	text := decodeLatin1(data)
+
+	verified := 0
+	for _, name := range []string{"Documento", "SetDTE"} {
+		for offset := 0; ; {
+			start := strings.Index(text[offset:], "<"+name+` ID="`)
+			if start < 0 {
+				break
+			}
+			start += offset
+			end := strings.Index(text[start:], "</"+name+">")
+			if end < 0 {
+				return fmt.Errorf("unterminated %s", name)
+			}
+			end += start + len("</"+name+">")
+
+			if err := verifyElement(text, name, start, end); err != nil {
+				return err
+			}
+			verified++
+			offset = end
+		}
+	}
+
+	if verified == 0 {
+		return errors.New("no signed elements")
+	}
+	return nil
+}
+
+// verifyElement checks the signature that follows the element between start
+// and end.
+func verifyElement(text, name string, start, end int) error {
+	element := withNamespace(text[start:end], name, siiNamespace)
+	signature := text[end:]
+	if !strings.HasPrefix(signature, "<Signature") {
+		return fmt.Errorf("%s is not followed by its signature", name)
+	}
+
+	digest := sha1.Sum([]byte(element))
+	if between(signature, "<DigestValue>", "</DigestValue>") != base64.StdEncoding.EncodeToString(digest[:]) {
+		return fmt.Errorf("%s digest does not match", name)
+	}
+
+	signedInfo := "<SignedInfo>" + between(signature, "<SignedInfo>", "</SignedInfo>") + "</SignedInfo>"
+	value, err := base64.StdEncoding.DecodeString(between(signature, "<SignatureValue>", "</SignatureValue>"))
+	if err != nil {
+		return fmt.Errorf("%s signature is not base64", name)
+	}
+	der, err := base64.StdEncoding.DecodeString(between(signature, "<X509Certificate>", "</X509Certificate>"))
+	if err != nil {
+		return fmt.Errorf("%s certificate is not base64", name)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("%s certificate: %w", name, err)
+	}
+	public, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%s certificate is not RSA", name)
+	}
+
+	hashed := sha1.Sum([]byte(withNamespace(signedInfo, "SignedInfo", dsigNamespace)))
+	if err := rsa.VerifyPKCS1v15(public, crypto.SHA1, hashed[:], value); err != nil {
+		return fmt.Errorf("%s signature does not verify: %w", name, err)
+	}
+	return nil
+}
+
+// between returns the text between the first open and the close after it.
+func between(text, open, close string) string {
+	start := strings.Index(text, open)
+	if start < 0 {
+		return ""
+	}
+	start += len(open)
+	end := strings.Index(text[start:], close)
+	if end < 0 {
+		return ""
+	}
+	return text[start : start+end]
+}
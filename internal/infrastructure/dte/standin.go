@@ -0,0 +1,90 @@
+package dte
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// LocalTaxAuthority stands in for the SII in local development. It takes
+// envelopes that are well-formed and correctly signed and rejects the rest,
+// answering on the first status check.
+type LocalTaxAuthority struct {
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	nextID   int
+	outcomes map[string]*domain.TaxAuthorityStatus
+}
+
+func NewLocalTaxAuthority(logger *zap.Logger) *LocalTaxAuthority {
+	return &LocalTaxAuthority{
+		logger:   logger,
+		nextID:   1000,
+		outcomes: make(map[string]*domain.TaxAuthorityStatus),
+	}
+}
+
+func (a *LocalTaxAuthority) Submit(envelope []byte) (string, error) {
+	outcome := &domain.TaxAuthorityStatus{
+		Status: domain.TaxDocumentStatusAccepted,
+		Detail: "Envío aceptado",
+	}
+	if err := checkEnvelope(envelope); err != nil {
+		outcome.Status = domain.TaxDocumentStatusRejected
+		outcome.Detail = err.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextID++
+	trackID := strconv.Itoa(a.nextID)
+	a.outcomes[trackID] = outcome
+
+	a.logger.Info("Tax authority stand-in received envelope",
+		zap.String("track_id", trackID),
+		zap.String("status", string(outcome.Status)),
+		zap.String("detail", outcome.Detail))
+	return trackID, nil
+}
+
+func (a *LocalTaxAuthority) GetStatus(trackID string) (*domain.TaxAuthorityStatus, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	outcome, ok := a.outcomes[trackID]
+	if !ok {
+		return nil, fmt.Errorf("unknown track ID %s", trackID)
+	}
+	return outcome, nil
+}
+
+func checkEnvelope(envelope []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(envelope))
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader([]byte(decodeLatin1(data))), nil
+	}
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("malformed XML: %w", err)
+		}
+	}
+
+	return Verify(envelope)
+}
@@ -0,0 +1,116 @@
+package dte
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	siiNamespace   = "http://www.sii.cl/SiiDte"
+	dsigNamespace  = "http://www.w3.org/2000/09/xmldsig#"
+	xmlDeclaration = `<?xml version="1.0" encoding="ISO-8859-1"?>` + "\n"
+)
+
+// xmlWriter writes elements the way Canonical XML 1.0 serializes them: no
+// whitespace between elements, explicit end tags and canonical escaping. A
+// signed element is then its own canonical form, once the namespace it
+// inherits is declared on it.
+type xmlWriter struct {
+	strings.Builder
+}
+
+// open writes a start tag; attrs are name, value pairs in canonical order.
+func (w *xmlWriter) open(name string, attrs ...string) {
+	w.WriteString("<" + name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		w.WriteString(" " + attrs[i] + `="` + escapeAttr(attrs[i+1]) + `"`)
+	}
+	w.WriteString(">")
+}
+
+func (w *xmlWriter) close(name string) {
+	w.WriteString("</" + name + ">")
+}
+
+// text writes an element with text content cut to max characters; max 0
+// does not cut.
+func (w *xmlWriter) text(name, value string, max int) {
+	w.open(name)
+	w.WriteString(escapeText(truncate(latin1(value), max)))
+	w.close(name)
+}
+
+func (w *xmlWriter) int(name string, value int64) {
+	w.text(name, strconv.FormatInt(value, 10), 0)
+}
+
+// raw writes markup that is already canonical.
+func (w *xmlWriter) raw(markup string) {
+	w.WriteString(markup)
+}
+
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		"\r", "&#xD;",
+	).Replace(value)
+}
+
+func escapeAttr(value string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		`"`, "&quot;",
+		"\t", "&#x9;",
+		"\n", "&#xA;",
+		"\r", "&#xD;",
+	).Replace(value)
+}
+
+// latin1 keeps the characters ISO-8859-1 can encode, which is what the SII
+// takes, and drops control characters.
+func latin1(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 0x20 || (r >= 0x7f && r < 0xa0):
+			return -1
+		case r > 0xff:
+			return '?'
+		}
+		return r
+	}, value)
+}
+
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if max <= 0 || utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return strings.TrimSpace(string([]rune(value)[:max]))
+}
+
+// encodeLatin1 encodes a string whose characters are all ISO-8859-1.
+func encodeLatin1(value string) []byte {
+	encoded := make([]byte, 0, len(value))
+	for _, r := range value {
+		if r > 0xff {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded
+}
+
+// decodeLatin1 reads ISO-8859-1 bytes.
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -93,7 +93,7 @@ func (s *SMTPService) SendWelcomeEmail(email, name string, registrationToken str
 	return s.sendEmail(email, subject, body)
 }
 
-func (s *SMTPService) SendReservationCreated(to string, reservation *domain.Reservation, user *domain.User) error {
+func (s *SMTPService) SendReservationCreated(to string, reservation *domain.Reservation, user *domain.User, trackingToken string) error {
 	s.logger.Info("📧 === SendReservationCreated Started ===",
 		zap.String("email", to),
 		zap.String("reservation_id", reservation.ID),
@@ -122,6 +122,15 @@ func (s *SMTPService) SendReservationCreated(to string, reservation *domain.Rese
 		HasSpecialLang: false, // TODO: Add special language flag to reservation
 	}
 
+	if trackingToken != "" {
+		// Frontend URL from environment or default
+		frontendURL := os.Getenv("FRONTEND_URL")
+		if frontendURL == "" {
+			frontendURL = "http://localhost:8080"
+		}
+		data.TrackingURL = fmt.Sprintf("%s/track/%s", frontendURL, trackingToken)
+	}
+
 	subject := fmt.Sprintf("Confirmación de Reserva - %s", reservation.ID)
 	body, err := s.generateReservationCreatedHTML(data)
 	if err != nil {
@@ -132,6 +141,111 @@ func (s *SMTPService) SendReservationCreated(to string, reservation *domain.Rese
 	return s.sendEmail(to, subject, body)
 }
 
+func (s *SMTPService) SendFeedbackRequest(to string, reservation *domain.Reservation, user *domain.User, feedbackToken string) error {
+	s.logger.Info("📧 === SendFeedbackRequest Started ===",
+		zap.String("email", to),
+		zap.String("reservation_id", reservation.ID),
+		zap.String("user_name", user.Name),
+	)
+
+	// Frontend URL from environment or default
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:8080"
+	}
+
+	data := domain.FeedbackEmailData{
+		UserName:      user.Name,
+		ReservationID: reservation.ID,
+		Pickup:        reservation.Pickup,
+		Destination:   reservation.Destination,
+		DateTime:      reservation.DateTime.Format("02/01/2006 15:04"),
+		FeedbackURL:   fmt.Sprintf("%s/feedback/%s", frontendURL, feedbackToken),
+	}
+	if reservation.AssignedDriver != nil {
+		data.DriverName = reservation.AssignedDriver.FirstName
+	}
+
+	subject := fmt.Sprintf("¿Cómo estuvo tu viaje? - %s", reservation.ID)
+	body, err := s.generateFeedbackRequestHTML(data)
+	if err != nil {
+		s.logger.Error("Failed to generate feedback email HTML", zap.Error(err))
+		return fmt.Errorf("failed to generate feedback email HTML: %w", err)
+	}
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *SMTPService) SendComplianceReminder(to, name string, items []domain.ComplianceReminderItem) error {
+	s.logger.Info("📧 === SendComplianceReminder Started ===",
+		zap.String("email", to),
+		zap.Int("documents", len(items)),
+	)
+
+	data := domain.ComplianceReminderEmailData{
+		Name:  name,
+		Items: items,
+	}
+
+	subject := "Documentos próximos a vencer - Turivo"
+	body, err := s.generateComplianceReminderHTML(data)
+	if err != nil {
+		s.logger.Error("Failed to generate compliance reminder HTML", zap.Error(err))
+		return fmt.Errorf("failed to generate compliance reminder HTML: %w", err)
+	}
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *SMTPService) SendMaintenanceReminder(to, name string, items []domain.MaintenanceReminderItem) error {
+	s.logger.Info("📧 === SendMaintenanceReminder Started ===",
+		zap.String("email", to),
+		zap.Int("plans", len(items)),
+	)
+
+	data := domain.MaintenanceReminderEmailData{
+		Name:  name,
+		Items: items,
+	}
+
+	subject := "Mantenciones programadas - Turivo"
+	body, err := s.generateMaintenanceReminderHTML(data)
+	if err != nil {
+		s.logger.Error("Failed to generate maintenance reminder HTML", zap.Error(err))
+		return fmt.Errorf("failed to generate maintenance reminder HTML: %w", err)
+	}
+
+	return s.sendEmail(to, subject, body)
+}
+
+func (s *SMTPService) SendLowBalanceAlert(to, companyName string, wallet *domain.CompanyWallet) error {
+	s.logger.Info("📧 === SendLowBalanceAlert Started ===",
+		zap.String("email", to),
+		zap.String("wallet_id", wallet.ID.String()),
+	)
+
+	formatAmount := func(amount float64) string {
+		return fmt.Sprintf("%.2f %s", amount, wallet.Currency)
+	}
+
+	data := domain.LowBalanceAlertEmailData{
+		CompanyName: companyName,
+		Balance:     formatAmount(wallet.Balance),
+		CreditLimit: formatAmount(wallet.CreditLimit),
+		Available:   formatAmount(wallet.Available()),
+		Threshold:   formatAmount(wallet.LowBalanceThreshold),
+	}
+
+	subject := "Saldo bajo en tu cuenta - Turivo"
+	body, err := s.generateLowBalanceAlertHTML(data)
+	if err != nil {
+		s.logger.Error("Failed to generate low balance alert HTML", zap.Error(err))
+		return fmt.Errorf("failed to generate low balance alert HTML: %w", err)
+	}
+
+	return s.sendEmail(to, subject, body)
+}
+
 func (s *SMTPService) SendReservationNotification(to string, reservation *domain.Reservation, user *domain.User) error {
 	s.logger.Info("📧 === SendReservationNotification Started ===",
 		zap.String("email", to),
@@ -497,6 +611,19 @@ func (s *SMTPService) generateReservationCreatedHTML(data domain.ReservationEmai
         .detail-value {
             color: #333;
         }
+        .button {
+            display: inline-block;
+            background: #667eea;
+            color: white;
+            padding: 12px 30px;
+            text-decoration: none;
+            border-radius: 5px;
+            margin: 20px 0;
+            font-weight: bold;
+        }
+        .button:hover {
+            background: #5a6fd8;
+        }
         .footer {
             text-align: center;
             margin-top: 30px;
@@ -564,6 +691,14 @@ func (s *SMTPService) generateReservationCreatedHTML(data domain.ReservationEmai
             {{end}}
         </div>
         
+        {{if .TrackingURL}}
+        <p>Puedes seguir el estado de tu traslado en tiempo real, sin necesidad de iniciar sesión:</p>
+        
+        <div style="text-align: center;">
+            <a href="{{.TrackingURL}}" class="button">Seguir mi traslado</a>
+        </div>
+        {{end}}
+        
         <p><strong>Próximos pasos:</strong></p>
         <ul>
             <li>Recibirás actualizaciones sobre el estado de tu reserva</li>
@@ -600,6 +735,430 @@ func (s *SMTPService) generateReservationCreatedHTML(data domain.ReservationEmai
 	return buf.String(), nil
 }
 
+func (s *SMTPService) generateFeedbackRequestHTML(data domain.FeedbackEmailData) (string, error) {
+	tmpl := `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Califica tu viaje - Turivo</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px 20px;
+            text-align: center;
+            border-radius: 8px 8px 0 0;
+        }
+        .content {
+            background: #f9f9f9;
+            padding: 30px 20px;
+            border-radius: 0 0 8px 8px;
+        }
+        .reservation-details {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            margin: 20px 0;
+            border-left: 4px solid #667eea;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin: 10px 0;
+            padding: 8px 0;
+            border-bottom: 1px solid #eee;
+        }
+        .detail-label {
+            font-weight: bold;
+            color: #555;
+        }
+        .detail-value {
+            color: #333;
+        }
+        .button {
+            display: inline-block;
+            background: #667eea;
+            color: white;
+            padding: 12px 30px;
+            text-decoration: none;
+            border-radius: 5px;
+            margin: 20px 0;
+            font-weight: bold;
+        }
+        .button:hover {
+            background: #5a6fd8;
+        }
+        .footer {
+            text-align: center;
+            margin-top: 30px;
+            font-size: 12px;
+            color: #666;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>¿Cómo estuvo tu viaje?</h1>
+        <p>Tu opinión nos ayuda a mejorar el servicio</p>
+    </div>
+    
+    <div class="content">
+        <h2>Hola {{.UserName}},</h2>
+        
+        <p>Tu traslado ha finalizado. Nos encantaría saber cómo fue tu experiencia{{if .DriverName}} con {{.DriverName}}{{end}}.</p>
+        
+        <div class="reservation-details">
+            <div class="detail-row">
+                <span class="detail-label">ID de Reserva:</span>
+                <span class="detail-value">{{.ReservationID}}</span>
+            </div>
+            
+            <div class="detail-row">
+                <span class="detail-label">Origen:</span>
+                <span class="detail-value">{{.Pickup}}</span>
+            </div>
+            
+            <div class="detail-row">
+                <span class="detail-label">Destino:</span>
+                <span class="detail-value">{{.Destination}}</span>
+            </div>
+            
+            <div class="detail-row">
+                <span class="detail-label">Fecha y Hora:</span>
+                <span class="detail-value">{{.DateTime}}</span>
+            </div>
+        </div>
+        
+        <div style="text-align: center;">
+            <a href="{{.FeedbackURL}}" class="button">Calificar mi viaje</a>
+        </div>
+        
+        <p>Solo te tomará un minuto. Este enlace es personal y puede usarse una sola vez.</p>
+        
+        <p>¡Gracias por elegir Turivo!</p>
+        
+        <p>Saludos cordiales,<br>
+        <strong>El equipo de Turivo</strong></p>
+    </div>
+    
+    <div class="footer">
+        <p>Este es un mensaje automático, por favor no respondas a este correo.</p>
+        <p>© 2024 Turivo. Todos los derechos reservados.</p>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("feedback-request").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse feedback request template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute feedback request template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (s *SMTPService) generateComplianceReminderHTML(data domain.ComplianceReminderEmailData) (string, error) {
+	tmpl := `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Documentos próximos a vencer - Turivo</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .header {
+            background: linear-gradient(135deg, #f59e0b 0%, #d97706 100%);
+            color: white;
+            padding: 30px 20px;
+            text-align: center;
+            border-radius: 8px 8px 0 0;
+        }
+        .content {
+            background: #f9f9f9;
+            padding: 30px 20px;
+            border-radius: 0 0 8px 8px;
+        }
+        .document {
+            background: white;
+            padding: 15px 20px;
+            border-radius: 8px;
+            margin: 15px 0;
+            border-left: 4px solid #f59e0b;
+        }
+        .document-title {
+            font-weight: bold;
+            color: #555;
+        }
+        .days-left {
+            color: #d97706;
+            font-weight: bold;
+        }
+        .footer {
+            text-align: center;
+            margin-top: 30px;
+            font-size: 12px;
+            color: #666;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>Documentos próximos a vencer</h1>
+        <p>Renueva a tiempo para seguir recibiendo viajes</p>
+    </div>
+    
+    <div class="content">
+        <h2>Hola {{.Name}},</h2>
+        
+        <p>Los siguientes documentos vencerán pronto. Una vez vencidos, no será posible asignar viajes al conductor o vehículo correspondiente.</p>
+        
+        {{range .Items}}
+        <div class="document">
+            <div class="document-title">{{.Document}} - {{.Subject}}</div>
+            <div>Vence el {{.ExpiresAt}} (<span class="days-left">{{.DaysLeft}} días</span>)</div>
+        </div>
+        {{end}}
+        
+        <p>Por favor actualiza la documentación en la plataforma antes de la fecha de vencimiento.</p>
+        
+        <p>Saludos cordiales,<br>
+        <strong>El equipo de Turivo</strong></p>
+    </div>
+    
+    <div class="footer">
+        <p>Este es un mensaje automático, por favor no respondas a este correo.</p>
+        <p>© 2024 Turivo. Todos los derechos reservados.</p>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("compliance-reminder").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse compliance reminder template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute compliance reminder template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (s *SMTPService) generateMaintenanceReminderHTML(data domain.MaintenanceReminderEmailData) (string, error) {
+	tmpl := `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Mantenciones programadas - Turivo</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .header {
+            background: linear-gradient(135deg, #3b82f6 0%, #1d4ed8 100%);
+            color: white;
+            padding: 30px 20px;
+            text-align: center;
+            border-radius: 8px 8px 0 0;
+        }
+        .content {
+            background: #f9f9f9;
+            padding: 30px 20px;
+            border-radius: 0 0 8px 8px;
+        }
+        .plan {
+            background: white;
+            padding: 15px 20px;
+            border-radius: 8px;
+            margin: 15px 0;
+            border-left: 4px solid #3b82f6;
+        }
+        .plan.overdue {
+            border-left-color: #dc2626;
+        }
+        .plan-title {
+            font-weight: bold;
+            color: #555;
+        }
+        .overdue-label {
+            color: #dc2626;
+            font-weight: bold;
+        }
+        .footer {
+            text-align: center;
+            margin-top: 30px;
+            font-size: 12px;
+            color: #666;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>Mantenciones programadas</h1>
+        <p>Planifica el servicio de tus vehículos a tiempo</p>
+    </div>
+    
+    <div class="content">
+        <h2>Hola {{.Name}},</h2>
+        
+        <p>Los siguientes vehículos deben pasar a mantención pronto. Programa una ventana de mantención en la plataforma para bloquear la asignación de viajes durante el servicio.</p>
+        
+        {{range .Items}}
+        <div class="plan{{if .Overdue}} overdue{{end}}">
+            <div class="plan-title">{{.Service}} - {{.Vehicle}}</div>
+            {{if .DueAt}}<div>Fecha límite: {{.DueAt}}</div>{{end}}
+            {{if .DueKm}}<div>Kilometraje límite: {{.DueKm}} km</div>{{end}}
+            {{if .Overdue}}<div class="overdue-label">Mantención vencida</div>{{end}}
+        </div>
+        {{end}}
+        
+        <p>Saludos cordiales,<br>
+        <strong>El equipo de Turivo</strong></p>
+    </div>
+    
+    <div class="footer">
+        <p>Este es un mensaje automático, por favor no respondas a este correo.</p>
+        <p>© 2024 Turivo. Todos los derechos reservados.</p>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("maintenance-reminder").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse maintenance reminder template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute maintenance reminder template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (s *SMTPService) generateLowBalanceAlertHTML(data domain.LowBalanceAlertEmailData) (string, error) {
+	tmpl := `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Saldo bajo - Turivo</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .header {
+            background: linear-gradient(135deg, #3b82f6 0%, #1d4ed8 100%);
+            color: white;
+            padding: 30px 20px;
+            text-align: center;
+            border-radius: 8px 8px 0 0;
+        }
+        .content {
+            background: #f9f9f9;
+            padding: 30px 20px;
+            border-radius: 0 0 8px 8px;
+        }
+        .balance {
+            background: white;
+            padding: 15px 20px;
+            border-radius: 8px;
+            margin: 15px 0;
+            border-left: 4px solid #dc2626;
+        }
+        .balance-label {
+            font-weight: bold;
+            color: #555;
+        }
+        .footer {
+            text-align: center;
+            margin-top: 30px;
+            font-size: 12px;
+            color: #666;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>Saldo bajo</h1>
+        <p>Recarga tu saldo para seguir reservando viajes</p>
+    </div>
+    
+    <div class="content">
+        <h2>Hola {{.CompanyName}},</h2>
+        
+        <p>El saldo disponible de tu cuenta bajó de {{.Threshold}}. Las reservas que superen el saldo disponible serán rechazadas; puedes recargar saldo desde la plataforma.</p>
+        
+        <div class="balance">
+            <div><span class="balance-label">Saldo:</span> {{.Balance}}</div>
+            <div><span class="balance-label">Línea de crédito:</span> {{.CreditLimit}}</div>
+            <div><span class="balance-label">Disponible:</span> {{.Available}}</div>
+        </div>
+        
+        <p>Saludos cordiales,<br>
+        <strong>El equipo de Turivo</strong></p>
+    </div>
+    
+    <div class="footer">
+        <p>Este es un mensaje automático, por favor no respondas a este correo.</p>
+        <p>© 2024 Turivo. Todos los derechos reservados.</p>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("low-balance-alert").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse low balance alert template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute low balance alert template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
 func (s *SMTPService) generateReservationNotificationHTML(data domain.ReservationEmailData) (string, error) {
 	tmpl := `
 <!DOCTYPE html>
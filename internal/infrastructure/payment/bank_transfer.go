@@ -0,0 +1,109 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// BankAccount is the account customers wire money to.
+type BankAccount struct {
+	BankName      string
+	AccountType   string
+	AccountNumber string
+	AccountHolder string
+	HolderRUT     string
+	Email         string
+}
+
+// BankTransferGateway takes payments by wire transfer. Creating a payment
+// only hands out the account and a reference the customer writes in the
+// transfer; the payment stays pending until an admin confirms the money
+// arrived, which commits it.
+type BankTransferGateway struct {
+	account BankAccount
+	logger  *zap.Logger
+}
+
+func NewBankTransferGateway(account BankAccount, logger *zap.Logger) *BankTransferGateway {
+	return &BankTransferGateway{
+		account: account,
+		logger:  logger,
+	}
+}
+
+func (b *BankTransferGateway) ProcessPayment(payment *domain.Payment) (*domain.PaymentResult, error) {
+	b.logger.Info("Issuing bank transfer instructions",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", payment.Amount))
+
+	ref := "TRF-" + strings.ToUpper(strings.ReplaceAll(payment.ID.String(), "-", "")[:12])
+
+	return &domain.PaymentResult{
+		PaymentID:      payment.ID,
+		Status:         domain.PaymentStatusPending,
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"reference":      ref,
+			"amount":         payment.Amount,
+			"currency":       payment.Currency,
+			"bank_name":      b.account.BankName,
+			"account_type":   b.account.AccountType,
+			"account_number": b.account.AccountNumber,
+			"account_holder": b.account.AccountHolder,
+			"holder_rut":     b.account.HolderRUT,
+			"email":          b.account.Email,
+		},
+		Message: fmt.Sprintf("Transfer the amount with reference %s to complete the payment", ref),
+	}, nil
+}
+
+func (b *BankTransferGateway) SimulatePayment(paymentID uuid.UUID, result domain.PaymentStatus) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// CommitPayment approves the transfer with the given reference once an admin
+// has seen it in the account. The gateway does not track payments, so the
+// result carries no payment ID.
+func (b *BankTransferGateway) CommitPayment(reference string) (*domain.PaymentResult, error) {
+	b.logger.Info("Confirming bank transfer", zap.String("reference", reference))
+
+	return &domain.PaymentResult{
+		Status:         domain.PaymentStatusApproved,
+		TransactionRef: &reference,
+		Payload: map[string]interface{}{
+			"reference":    reference,
+			"confirmed_at": time.Now().Format(time.RFC3339),
+		},
+		Message: "Transfer confirmed",
+	}, nil
+}
+
+// GetPaymentStatus is not offered: only an admin can tell whether the
+// transfer arrived.
+func (b *BankTransferGateway) GetPaymentStatus(reference string) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// RefundPayment records that the money has to be wired back to the
+// customer by hand.
+func (b *BankTransferGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	b.logger.Info("Recording manual bank transfer refund",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	ref := fmt.Sprintf("TRF-REF-%d-%s", time.Now().Unix(), payment.ID.String()[:8])
+	return &domain.RefundResult{
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"type":   "MANUAL_TRANSFER",
+			"amount": amount,
+		},
+		Message: "Refund must be transferred manually to the customer",
+	}, nil
+}
@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// CompanyCreditGateway charges reservations to the company's account. The
+// charge is approved at once and billed to the company later: on its
+// invoice, or debited from its wallet when the trip completes.
+type CompanyCreditGateway struct {
+	logger *zap.Logger
+}
+
+func NewCompanyCreditGateway(logger *zap.Logger) *CompanyCreditGateway {
+	return &CompanyCreditGateway{
+		logger: logger,
+	}
+}
+
+func (c *CompanyCreditGateway) ProcessPayment(payment *domain.Payment) (*domain.PaymentResult, error) {
+	c.logger.Info("Charging payment to company credit",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", payment.Amount))
+
+	ref := "CC-" + strings.ToUpper(payment.ID.String()[:8])
+	return &domain.PaymentResult{
+		PaymentID:      payment.ID,
+		Status:         domain.PaymentStatusApproved,
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"type":       "COMPANY_CREDIT",
+			"amount":     payment.Amount,
+			"currency":   payment.Currency,
+			"charged_at": time.Now().Format(time.RFC3339),
+		},
+		Message: "Payment charged to company credit",
+	}, nil
+}
+
+func (c *CompanyCreditGateway) SimulatePayment(paymentID uuid.UUID, result domain.PaymentStatus) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// CommitPayment is not needed: charges are settled in ProcessPayment.
+func (c *CompanyCreditGateway) CommitPayment(token string) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+func (c *CompanyCreditGateway) GetPaymentStatus(token string) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// RefundPayment issues a credit note against the company's account.
+func (c *CompanyCreditGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	c.logger.Info("Crediting refund to company",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	ref := fmt.Sprintf("CC-NC-%d-%s", time.Now().Unix(), payment.ID.String()[:8])
+	return &domain.RefundResult{
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"type":   "CREDIT_NOTE",
+			"amount": amount,
+		},
+		Message: "Refund credited to the company",
+	}, nil
+}
@@ -0,0 +1,375 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	mercadoPagoPreferencesPath = "/checkout/preferences"
+	mercadoPagoPaymentsPath    = "/v1/payments"
+)
+
+type MercadoPagoConfig struct {
+	// BaseURL is https://api.mercadopago.com or the local stand-in.
+	BaseURL     string
+	AccessToken string
+	// ReturnURL receives the customer back from checkout with
+	// preference_id, payment_id and status, whatever the outcome.
+	ReturnURL string
+	// WebhookSecret is the secret key of the webhook notifications; without
+	// it notifications are refused.
+	WebhookSecret string
+	Timeout       time.Duration
+}
+
+// MercadoPagoGateway takes payments through Mercado Pago Checkout Pro.
+// Creating a payment opens a preference whose external reference is the
+// payment ID; the transaction reference is the preference ID. Mercado Pago
+// has no commit step, so committing reads the latest payment made for the
+// preference.
+type MercadoPagoGateway struct {
+	cfg     MercadoPagoConfig
+	baseURL *url.URL
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+func NewMercadoPagoGateway(cfg MercadoPagoConfig, logger *zap.Logger) (*MercadoPagoGateway, error) {
+	if cfg.AccessToken == "" {
+		return nil, fmt.Errorf("mercado pago access token is required")
+	}
+	if cfg.ReturnURL == "" {
+		return nil, fmt.Errorf("mercado pago return url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid mercado pago base url: %s", cfg.BaseURL)
+	}
+
+	return &MercadoPagoGateway{
+		cfg:     cfg,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		logger:  logger,
+	}, nil
+}
+
+type mercadoPagoPayment struct {
+	ID                int64   `json:"id"`
+	Status            string  `json:"status"`
+	StatusDetail      string  `json:"status_detail"`
+	TransactionAmount float64 `json:"transaction_amount"`
+	CurrencyID        string  `json:"currency_id"`
+	ExternalReference string  `json:"external_reference"`
+	DateApproved      *string `json:"date_approved"`
+}
+
+func (m *MercadoPagoGateway) ProcessPayment(payment *domain.Payment) (*domain.PaymentResult, error) {
+	m.logger.Info("Creating Mercado Pago preference",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", payment.Amount))
+
+	body := map[string]interface{}{
+		"items": []map[string]interface{}{{
+			"id":          payment.Reference(),
+			"title":       payment.Description(),
+			"quantity":    1,
+			"currency_id": payment.Currency,
+			"unit_price":  payment.Amount,
+		}},
+		"external_reference": payment.ID.String(),
+		"back_urls": map[string]string{
+			"success": m.cfg.ReturnURL,
+			"failure": m.cfg.ReturnURL,
+			"pending": m.cfg.ReturnURL,
+		},
+		"auto_return": "all",
+	}
+
+	var preference struct {
+		ID        string `json:"id"`
+		InitPoint string `json:"init_point"`
+	}
+	if err := m.do(http.MethodPost, mercadoPagoPreferencesPath, body, &preference); err != nil {
+		return nil, err
+	}
+	if preference.ID == "" || preference.InitPoint == "" {
+		return nil, fmt.Errorf("mercado pago returned no preference id or init point")
+	}
+
+	return &domain.PaymentResult{
+		PaymentID:      payment.ID,
+		Status:         domain.PaymentStatusPending,
+		TransactionRef: &preference.ID,
+		Payload: map[string]interface{}{
+			"preference_id":      preference.ID,
+			"init_point":         preference.InitPoint,
+			"external_reference": payment.ID.String(),
+		},
+		Redirect: &domain.PaymentRedirect{URL: preference.InitPoint, Token: preference.ID, Method: http.MethodGet},
+		Message:  "Redirect the customer to Mercado Pago to complete the payment",
+	}, nil
+}
+
+func (m *MercadoPagoGateway) SimulatePayment(paymentID uuid.UUID, result domain.PaymentStatus) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// CommitPayment reads the outcome of the preference. An approved payment
+// wins over later attempts; without any payment it is still pending.
+func (m *MercadoPagoGateway) CommitPayment(preferenceID string) (*domain.PaymentResult, error) {
+	var preference struct {
+		ExternalReference string `json:"external_reference"`
+	}
+	if err := m.do(http.MethodGet, mercadoPagoPreferencesPath+"/"+url.PathEscape(preferenceID), nil, &preference); err != nil {
+		return nil, err
+	}
+
+	paymentID, err := uuid.Parse(preference.ExternalReference)
+	if err != nil {
+		return nil, fmt.Errorf("mercado pago preference has an unknown external reference: %s", preference.ExternalReference)
+	}
+
+	query := url.Values{}
+	query.Set("external_reference", preference.ExternalReference)
+	query.Set("sort", "date_created")
+	query.Set("criteria", "desc")
+
+	var search struct {
+		Results []mercadoPagoPayment `json:"results"`
+	}
+	if err := m.do(http.MethodGet, mercadoPagoPaymentsPath+"/search?"+query.Encode(), nil, &search); err != nil {
+		return nil, err
+	}
+
+	result := &domain.PaymentResult{
+		PaymentID:      paymentID,
+		Status:         domain.PaymentStatusPending,
+		TransactionRef: &preferenceID,
+		Payload:        map[string]interface{}{"preference_id": preferenceID},
+		Message:        "Payment is pending",
+	}
+	if len(search.Results) == 0 {
+		return result, nil
+	}
+
+	latest := search.Results[0]
+	for _, candidate := range search.Results {
+		if candidate.Status == "approved" {
+			latest = candidate
+			break
+		}
+	}
+
+	result.Status, result.Message = mercadoPagoPaymentStatus(latest.Status)
+	result.Amount = &latest.TransactionAmount
+	result.Payload["mp_payment_id"] = latest.ID
+	result.Payload["status"] = latest.Status
+	result.Payload["status_detail"] = latest.StatusDetail
+	result.Payload["transaction_amount"] = latest.TransactionAmount
+	result.Payload["currency_id"] = latest.CurrencyID
+	if latest.DateApproved != nil {
+		result.Payload["date_approved"] = *latest.DateApproved
+	}
+	return result, nil
+}
+
+func (m *MercadoPagoGateway) GetPaymentStatus(preferenceID string) (*domain.PaymentResult, error) {
+	return m.CommitPayment(preferenceID)
+}
+
+func mercadoPagoPaymentStatus(status string) (domain.PaymentStatus, string) {
+	switch status {
+	case "approved":
+		return domain.PaymentStatusApproved, "Payment approved successfully"
+	case "refunded", "charged_back":
+		return domain.PaymentStatusRefunded, "Payment was refunded"
+	case "rejected", "cancelled":
+		return domain.PaymentStatusRejected, "Payment was rejected"
+	default:
+		return domain.PaymentStatusPending, "Payment is pending"
+	}
+}
+
+// RefundPayment refunds the Mercado Pago payment recorded when the payment
+// was committed.
+func (m *MercadoPagoGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	var mpPaymentID string
+	switch id := payment.Payload["mp_payment_id"].(type) {
+	case float64:
+		mpPaymentID = fmt.Sprintf("%.0f", id)
+	case int64:
+		mpPaymentID = fmt.Sprintf("%d", id)
+	case string:
+		mpPaymentID = id
+	}
+	if mpPaymentID == "" {
+		return nil, fmt.Errorf("payment %s has no mercado pago payment id", payment.ID)
+	}
+
+	m.logger.Info("Refunding Mercado Pago payment",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	var refund struct {
+		ID     int64   `json:"id"`
+		Amount float64 `json:"amount"`
+		Status string  `json:"status"`
+	}
+	path := mercadoPagoPaymentsPath + "/" + url.PathEscape(mpPaymentID) + "/refunds"
+	if err := m.do(http.MethodPost, path, map[string]interface{}{"amount": amount}, &refund); err != nil {
+		return nil, err
+	}
+	if refund.Status != "approved" {
+		return nil, fmt.Errorf("mercado pago declined the refund: %s", refund.Status)
+	}
+
+	ref := fmt.Sprintf("%d", refund.ID)
+	return &domain.RefundResult{
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"refund_id":     refund.ID,
+			"mp_payment_id": mpPaymentID,
+			"amount":        refund.Amount,
+			"status":        refund.Status,
+		},
+		Message: "Refund processed successfully",
+	}, nil
+}
+
+// VerifyNotification checks the x-signature header of a webhook: an
+// HMAC-SHA256 of the notified data.id, the x-request-id header and the
+// timestamp with the webhook secret. Payment notifications are traced back
+// to our payment through the Mercado Pago payment's external reference.
+func (m *MercadoPagoGateway) VerifyNotification(req domain.PaymentNotificationRequest) (*domain.PaymentNotification, error) {
+	if m.cfg.WebhookSecret == "" {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	var body struct {
+		ID     json.Number `json:"id"`
+		Type   string      `json:"type"`
+		Action string      `json:"action"`
+		Data   struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	dataID := req.Query.Get("data.id")
+	if dataID == "" {
+		dataID = body.Data.ID
+	}
+	requestID := req.Header.Get("x-request-id")
+
+	header := parseSignatureHeader(req.Header.Get("x-signature"))
+	timestamp := firstValue(header, "ts")
+	if dataID == "" || timestamp == "" || !validHMAC(m.cfg.WebhookSecret, mercadoPagoSignatureManifest(dataID, requestID, timestamp), header["v1"]) {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	var payload map[string]interface{}
+	_ = json.Unmarshal(req.Body, &payload)
+
+	eventType := body.Action
+	if eventType == "" {
+		eventType = body.Type
+	}
+	eventID := body.ID.String()
+	if eventID == "" {
+		eventID = requestID
+	}
+
+	notification := &domain.PaymentNotification{
+		Gateway:   domain.PaymentGatewayMercadoPago,
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   payload,
+	}
+	if body.Type != "payment" {
+		return notification, nil
+	}
+
+	var mpPayment mercadoPagoPayment
+	if err := m.do(http.MethodGet, mercadoPagoPaymentsPath+"/"+url.PathEscape(dataID), nil, &mpPayment); err != nil {
+		return nil, err
+	}
+	if paymentID, err := uuid.Parse(mpPayment.ExternalReference); err == nil {
+		notification.PaymentID = &paymentID
+	}
+	return notification, nil
+}
+
+// mercadoPagoSignatureManifest is the template Mercado Pago signs; ids are
+// lowercased.
+func mercadoPagoSignatureManifest(dataID, requestID, timestamp string) string {
+	return "id:" + strings.ToLower(dataID) + ";request-id:" + requestID + ";ts:" + timestamp + ";"
+}
+
+// do sends a request to Mercado Pago and decodes the JSON response into
+// out. Error responses carry message.
+func (m *MercadoPagoGateway) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode mercado pago request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, m.baseURL.String()+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build mercado pago request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.cfg.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	if method == http.MethodPost {
+		req.Header.Set("X-Idempotency-Key", uuid.NewString())
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mercado pago request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read mercado pago response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("mercado pago returned %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("mercado pago returned %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode mercado pago response: %w", err)
+	}
+	return nil
+}
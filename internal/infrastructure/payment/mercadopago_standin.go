@@ -0,0 +1,442 @@
+package payment
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mercadoPagoCheckoutPath = "/checkout/v1/redirect"
+	mercadoPagoPayPath      = "/checkout/v1/pay"
+)
+
+// MercadoPagoStandIn mimics the Mercado Pago endpoints used by
+// MercadoPagoGateway, plus a checkout page where the customer approves,
+// rejects or leaves the payment pending. It can be mounted under any prefix.
+// With a webhook set, every payment attempt is notified like Mercado Pago
+// does.
+type MercadoPagoStandIn struct {
+	accessToken string
+
+	webhookURL    string
+	webhookSecret string
+
+	mu          sync.Mutex
+	preferences map[string]*standInPreference
+	payments    map[int64]*standInMPPayment
+	nextID      int64
+}
+
+type standInPreference struct {
+	id                string
+	externalReference string
+	amount            float64
+	currency          string
+	backURLs          map[string]string
+}
+
+type standInMPPayment struct {
+	id                int64
+	preferenceID      string
+	externalReference string
+	status            string
+	statusDetail      string
+	amount            float64
+	refunded          float64
+	currency          string
+	createdAt         time.Time
+}
+
+func NewMercadoPagoStandIn(accessToken string) *MercadoPagoStandIn {
+	return &MercadoPagoStandIn{
+		accessToken: accessToken,
+		preferences: make(map[string]*standInPreference),
+		payments:    make(map[int64]*standInMPPayment),
+		nextID:      1000000,
+	}
+}
+
+// SetWebhook sends payment notifications to url, signed with secret.
+func (s *MercadoPagoStandIn) SetWebhook(url, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookURL = url
+	s.webhookSecret = secret
+}
+
+func (s *MercadoPagoStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, mercadoPagoCheckoutPath):
+		s.checkout(w, r, strings.TrimSuffix(path, mercadoPagoCheckoutPath))
+		return
+	case strings.HasSuffix(path, mercadoPagoPayPath) && r.Method == http.MethodPost:
+		s.pay(w, r)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.accessToken {
+		writeMPError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	if idx := strings.Index(path, mercadoPagoPreferencesPath); idx >= 0 {
+		id := strings.Trim(strings.TrimPrefix(path[idx:], mercadoPagoPreferencesPath), "/")
+		switch {
+		case id == "" && r.Method == http.MethodPost:
+			s.createPreference(w, r, path[:idx])
+		case id != "" && r.Method == http.MethodGet:
+			s.getPreference(w, id)
+		default:
+			writeMPError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if idx := strings.Index(path, mercadoPagoPaymentsPath); idx >= 0 {
+		rest := strings.Trim(strings.TrimPrefix(path[idx:], mercadoPagoPaymentsPath), "/")
+		switch {
+		case rest == "search" && r.Method == http.MethodGet:
+			s.search(w, r)
+		case strings.HasSuffix(rest, "/refunds") && r.Method == http.MethodPost:
+			s.refund(w, r, strings.TrimSuffix(rest, "/refunds"))
+		case rest != "" && r.Method == http.MethodGet:
+			s.getPayment(w, rest)
+		default:
+			writeMPError(w, http.StatusNotFound, "resource not found")
+		}
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+func (s *MercadoPagoStandIn) createPreference(w http.ResponseWriter, r *http.Request, prefix string) {
+	var req struct {
+		Items []struct {
+			Quantity   int     `json:"quantity"`
+			CurrencyID string  `json:"currency_id"`
+			UnitPrice  float64 `json:"unit_price"`
+		} `json:"items"`
+		ExternalReference string            `json:"external_reference"`
+		BackURLs          map[string]string `json:"back_urls"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMPError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Items) == 0 {
+		writeMPError(w, http.StatusBadRequest, "items needed")
+		return
+	}
+
+	preference := &standInPreference{
+		id:                "pref-" + newStandInToken()[:24],
+		externalReference: req.ExternalReference,
+		currency:          req.Items[0].CurrencyID,
+		backURLs:          req.BackURLs,
+	}
+	for _, item := range req.Items {
+		if item.UnitPrice <= 0 || item.Quantity <= 0 {
+			writeMPError(w, http.StatusBadRequest, "invalid item unit_price or quantity")
+			return
+		}
+		preference.amount += item.UnitPrice * float64(item.Quantity)
+	}
+
+	s.mu.Lock()
+	s.preferences[preference.id] = preference
+	s.mu.Unlock()
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	initPoint := scheme + "://" + r.Host + prefix + mercadoPagoCheckoutPath + "?pref_id=" + preference.id
+
+	writeStandInJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":                 preference.id,
+		"external_reference": preference.externalReference,
+		"init_point":         initPoint,
+		"sandbox_init_point": initPoint,
+	})
+}
+
+func (s *MercadoPagoStandIn) getPreference(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	preference, ok := s.preferences[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeMPError(w, http.StatusNotFound, "preference not found")
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, map[string]interface{}{
+		"id":                 preference.id,
+		"external_reference": preference.externalReference,
+	})
+}
+
+func (s *MercadoPagoStandIn) search(w http.ResponseWriter, r *http.Request) {
+	reference := r.URL.Query().Get("external_reference")
+
+	s.mu.Lock()
+	results := []map[string]interface{}{}
+	for _, payment := range s.payments {
+		if payment.externalReference == reference {
+			results = append(results, payment.response())
+		}
+	}
+	s.mu.Unlock()
+
+	// Newest first, like sort=date_created&criteria=desc
+	sort.Slice(results, func(i, j int) bool {
+		return results[i]["id"].(int64) > results[j]["id"].(int64)
+	})
+
+	writeStandInJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"paging":  map[string]int{"total": len(results)},
+	})
+}
+
+func (s *MercadoPagoStandIn) getPayment(w http.ResponseWriter, rawID string) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		writeMPError(w, http.StatusBadRequest, "invalid payment id")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[id]
+	if !ok {
+		writeMPError(w, http.StatusNotFound, "Payment not found")
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, payment.response())
+}
+
+// refund returns part or all of an approved payment; the payment becomes
+// refunded once nothing is left.
+func (s *MercadoPagoStandIn) refund(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		writeMPError(w, http.StatusNotFound, "payment not found")
+		return
+	}
+
+	var req struct {
+		Amount *float64 `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMPError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[id]
+	if !ok {
+		writeMPError(w, http.StatusNotFound, "payment not found")
+		return
+	}
+	if payment.status != "approved" {
+		writeMPError(w, http.StatusBadRequest, "invalid payment status to refund")
+		return
+	}
+
+	amount := payment.amount - payment.refunded
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if amount <= 0 || amount > payment.amount-payment.refunded {
+		writeMPError(w, http.StatusBadRequest, "invalid refund amount")
+		return
+	}
+
+	payment.refunded += amount
+	if payment.refunded >= payment.amount {
+		payment.status = "refunded"
+	}
+	s.nextID++
+
+	writeStandInJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":         s.nextID,
+		"payment_id": payment.id,
+		"amount":     amount,
+		"status":     "approved",
+	})
+}
+
+func (p *standInMPPayment) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"id":                 p.id,
+		"status":             p.status,
+		"status_detail":      p.statusDetail,
+		"transaction_amount": p.amount,
+		"currency_id":        p.currency,
+		"external_reference": p.externalReference,
+		"date_created":       p.createdAt.UTC().Format(time.RFC3339),
+	}
+	if p.status == "approved" || p.status == "refunded" {
+		response["date_approved"] = p.createdAt.UTC().Format(time.RFC3339)
+	}
+	return response
+}
+
+var mercadoPagoCheckoutForm = template.Must(template.New("mercadopago").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Mercado Pago (stand-in)</title></head>
+<body>
+<h1>Mercado Pago (stand-in)</h1>
+<p>Monto: {{.Amount}} {{.Currency}}</p>
+<form method="post" action="{{.PayURL}}">
+<input type="hidden" name="pref_id" value="{{.PreferenceID}}">
+<button name="status" value="approved">Aprobar</button>
+<button name="status" value="rejected">Rechazar</button>
+<button name="status" value="pending">Dejar pendiente</button>
+</form>
+</body>
+</html>`))
+
+func (s *MercadoPagoStandIn) checkout(w http.ResponseWriter, r *http.Request, prefix string) {
+	id := r.FormValue("pref_id")
+
+	s.mu.Lock()
+	preference, ok := s.preferences[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Preference not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = mercadoPagoCheckoutForm.Execute(w, map[string]interface{}{
+		"Amount":       preference.amount,
+		"Currency":     preference.currency,
+		"PreferenceID": preference.id,
+		"PayURL":       prefix + mercadoPagoPayPath,
+	})
+}
+
+// pay records a payment attempt with the chosen status and sends the
+// customer to the matching back URL the way Checkout Pro does.
+func (s *MercadoPagoStandIn) pay(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("pref_id")
+	status := r.FormValue("status")
+
+	backURLKey, detail := "", ""
+	switch status {
+	case "approved":
+		backURLKey, detail = "success", "accredited"
+	case "rejected":
+		backURLKey, detail = "failure", "cc_rejected_insufficient_amount"
+	case "pending":
+		backURLKey, detail = "pending", "pending_waiting_payment"
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	preference, ok := s.preferences[id]
+	var payment *standInMPPayment
+	if ok {
+		s.nextID++
+		payment = &standInMPPayment{
+			id:                s.nextID,
+			preferenceID:      preference.id,
+			externalReference: preference.externalReference,
+			status:            status,
+			statusDetail:      detail,
+			amount:            preference.amount,
+			currency:          preference.currency,
+			createdAt:         time.Now(),
+		}
+		s.payments[payment.id] = payment
+		s.notify(payment.id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Preference not found", http.StatusNotFound)
+		return
+	}
+
+	backURL := preference.backURLs[backURLKey]
+	if backURL == "" {
+		http.Error(w, "Preference has no back URL for "+backURLKey, http.StatusUnprocessableEntity)
+		return
+	}
+
+	params := url.Values{}
+	params.Set("payment_id", strconv.FormatInt(payment.id, 10))
+	params.Set("collection_id", strconv.FormatInt(payment.id, 10))
+	params.Set("status", status)
+	params.Set("collection_status", status)
+	params.Set("external_reference", preference.externalReference)
+	params.Set("preference_id", preference.id)
+
+	separator := "?"
+	if strings.Contains(backURL, "?") {
+		separator = "&"
+	}
+	http.Redirect(w, r, backURL+separator+params.Encode(), http.StatusSeeOther)
+}
+
+// notify sends a signed payment.created notification to the webhook, if
+// any. It is called with the lock held.
+func (s *MercadoPagoStandIn) notify(paymentID int64) {
+	if s.webhookURL == "" {
+		return
+	}
+
+	s.nextID++
+	dataID := strconv.FormatInt(paymentID, 10)
+	body, err := json.Marshal(map[string]interface{}{
+		"id":           s.nextID,
+		"live_mode":    false,
+		"type":         "payment",
+		"action":       "payment.created",
+		"date_created": time.Now().UTC().Format(time.RFC3339),
+		"data":         map[string]string{"id": dataID},
+	})
+	if err != nil {
+		return
+	}
+
+	requestID := newStandInToken()[:32]
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set("x-request-id", requestID)
+	header.Set("x-signature", "ts="+timestamp+",v1="+signHMAC(s.webhookSecret, mercadoPagoSignatureManifest(dataID, requestID, timestamp)))
+
+	target := s.webhookURL
+	separator := "?"
+	if strings.Contains(target, "?") {
+		separator = "&"
+	}
+	postStandInNotification(target+separator+url.Values{"data.id": {dataID}, "type": {"payment"}}.Encode(), body, header)
+}
+
+func writeMPError(w http.ResponseWriter, status int, message string) {
+	writeStandInJSON(w, status, map[string]interface{}{
+		"message": message,
+		"error":   http.StatusText(status),
+		"status":  status,
+	})
+}
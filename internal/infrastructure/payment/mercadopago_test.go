@@ -0,0 +1,192 @@
+package payment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const testMPAccessToken = "TEST-token"
+
+func newMercadoPagoStandInGateway(t *testing.T) (*MercadoPagoGateway, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle("/mercadopago-standin/", NewMercadoPagoStandIn(testMPAccessToken))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gateway, err := NewMercadoPagoGateway(MercadoPagoConfig{
+		BaseURL:     server.URL + "/mercadopago-standin",
+		AccessToken: testMPAccessToken,
+		ReturnURL:   testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return gateway, server
+}
+
+// payAtMercadoPago opens the stand-in checkout, picks status and returns the
+// query the customer is sent back to the return URL with.
+func payAtMercadoPago(t *testing.T, redirect *domain.PaymentRedirect, status string) url.Values {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	page, err := client.Get(redirect.URL)
+	require.NoError(t, err)
+	page.Body.Close()
+	require.Equal(t, http.StatusOK, page.StatusCode)
+
+	payURL := strings.Replace(strings.Split(redirect.URL, "?")[0], mercadoPagoCheckoutPath, mercadoPagoPayPath, 1)
+	resp, err := client.PostForm(payURL, url.Values{
+		"pref_id": {redirect.Token},
+		"status":  {status},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop.test", location.Host)
+	return location.Query()
+}
+
+func TestMercadoPago_ApprovedPayment(t *testing.T) {
+	gateway, _ := newMercadoPagoStandInGateway(t)
+	payment := newTestPayment()
+	payment.Gateway = domain.PaymentGatewayMercadoPago
+
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, created.Status)
+	require.NotNil(t, created.Redirect)
+	assert.Equal(t, http.MethodGet, created.Redirect.Method)
+	assert.Equal(t, created.Redirect.Token, *created.TransactionRef)
+
+	status, err := gateway.GetPaymentStatus(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, status.Status)
+
+	query := payAtMercadoPago(t, created.Redirect, "approved")
+	assert.Equal(t, created.Redirect.Token, query.Get("preference_id"))
+	assert.Equal(t, payment.ID.String(), query.Get("external_reference"))
+
+	committed, err := gateway.CommitPayment(query.Get("preference_id"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusApproved, committed.Status)
+	assert.Equal(t, payment.ID, committed.PaymentID)
+	assert.NotNil(t, committed.Payload["mp_payment_id"])
+}
+
+func TestMercadoPago_ApprovalWinsOverEarlierRejection(t *testing.T) {
+	gateway, _ := newMercadoPagoStandInGateway(t)
+
+	created, err := gateway.ProcessPayment(newTestPayment())
+	require.NoError(t, err)
+
+	payAtMercadoPago(t, created.Redirect, "rejected")
+	committed, err := gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusRejected, committed.Status)
+
+	payAtMercadoPago(t, created.Redirect, "approved")
+	payAtMercadoPago(t, created.Redirect, "pending")
+	committed, err = gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusApproved, committed.Status)
+}
+
+func TestMercadoPago_Refunds(t *testing.T) {
+	gateway, _ := newMercadoPagoStandInGateway(t)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+
+	_, err = gateway.RefundPayment(payment, 1000)
+	assert.ErrorContains(t, err, "no mercado pago payment id")
+
+	payAtMercadoPago(t, created.Redirect, "approved")
+	committed, err := gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	payment.Payload = committed.Payload
+
+	refund, err := gateway.RefundPayment(payment, 5000)
+	require.NoError(t, err)
+	require.NotNil(t, refund.TransactionRef)
+
+	_, err = gateway.RefundPayment(payment, 20000)
+	assert.ErrorContains(t, err, "invalid refund amount")
+
+	_, err = gateway.RefundPayment(payment, payment.Amount-5000)
+	require.NoError(t, err)
+
+	status, err := gateway.GetPaymentStatus(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusRefunded, status.Status)
+}
+
+func TestMercadoPago_RejectsWrongCredentials(t *testing.T) {
+	_, server := newMercadoPagoStandInGateway(t)
+
+	gateway, err := NewMercadoPagoGateway(MercadoPagoConfig{
+		BaseURL:     server.URL + "/mercadopago-standin",
+		AccessToken: "wrong",
+		ReturnURL:   testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = gateway.ProcessPayment(newTestPayment())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestMercadoPago_Notifications(t *testing.T) {
+	sinkURL, received := notificationSink(t)
+
+	standIn := NewMercadoPagoStandIn(testMPAccessToken)
+	standIn.SetWebhook(sinkURL, "mp-secret")
+	mux := http.NewServeMux()
+	mux.Handle("/mercadopago-standin/", standIn)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gateway, err := NewMercadoPagoGateway(MercadoPagoConfig{
+		BaseURL:       server.URL + "/mercadopago-standin",
+		AccessToken:   testMPAccessToken,
+		ReturnURL:     testReturnURL,
+		WebhookSecret: "mp-secret",
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	payAtMercadoPago(t, created.Redirect, "approved")
+
+	req := awaitNotification(t, received)
+	notification, err := gateway.VerifyNotification(req)
+	require.NoError(t, err)
+	assert.Equal(t, "payment.created", notification.EventType)
+	assert.NotEmpty(t, notification.EventID)
+	require.NotNil(t, notification.PaymentID)
+	assert.Equal(t, payment.ID, *notification.PaymentID)
+
+	// The signature covers the notified id
+	forged := req
+	forged.Query = url.Values{"data.id": {"1"}, "type": {"payment"}}
+	_, err = gateway.VerifyNotification(forged)
+	assert.ErrorIs(t, err, domain.ErrInvalidSignature)
+}
@@ -0,0 +1,68 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// signHMAC returns the hex HMAC-SHA256 of message, the way Stripe and
+// Mercado Pago sign their webhooks.
+func signHMAC(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validHMAC reports whether any of signatures is the HMAC of message.
+func validHMAC(secret, message string, signatures []string) bool {
+	expected := []byte(signHMAC(secret, message))
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(strings.ToLower(signature)), expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSignatureHeader splits a "t=...,v1=..." signature header into its
+// values; a key may repeat.
+func parseSignatureHeader(header string) map[string][]string {
+	values := make(map[string][]string)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			values[key] = append(values[key], value)
+		}
+	}
+	return values
+}
+
+func firstValue(values map[string][]string, key string) string {
+	if len(values[key]) == 0 {
+		return ""
+	}
+	return values[key][0]
+}
+
+var standInNotifyClient = &http.Client{Timeout: 10 * time.Second}
+
+// postStandInNotification sends a webhook from a stand-in in the background.
+// Failures are dropped: the reconciliation job settles the payment anyway.
+func postStandInNotification(target string, body []byte, header http.Header) {
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header = header
+		req.Header.Set("Content-Type", "application/json")
+		if resp, err := standInNotifyClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package payment
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+// notificationSink records the webhooks a stand-in sends.
+func notificationSink(t *testing.T) (string, <-chan domain.PaymentNotificationRequest) {
+	t.Helper()
+
+	received := make(chan domain.PaymentNotificationRequest, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- domain.PaymentNotificationRequest{Header: r.Header, Query: r.URL.Query(), Body: body}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/notifications", received
+}
+
+func awaitNotification(t *testing.T, received <-chan domain.PaymentNotificationRequest) domain.PaymentNotificationRequest {
+	t.Helper()
+
+	select {
+	case req := <-received:
+		return req
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no notification received")
+		return domain.PaymentNotificationRequest{}
+	}
+}
@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"strings"
+
+	"turivo-backend/internal/domain"
+)
+
+type registeredGateway struct {
+	service domain.PaymentGatewayService
+	// currencies the gateway takes; empty means any
+	currencies map[string]bool
+}
+
+// Registry maps each payment method to the gateway that processes it.
+// Methods are listed in the order they were registered.
+type Registry struct {
+	gateways map[domain.PaymentGateway]registeredGateway
+	order    []domain.PaymentGateway
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		gateways: make(map[domain.PaymentGateway]registeredGateway),
+	}
+}
+
+// Register sets the gateway of a payment method, limited to the given
+// currencies when any are given. Registering a method again replaces it.
+func (r *Registry) Register(gateway domain.PaymentGateway, service domain.PaymentGatewayService, currencies ...string) {
+	if _, exists := r.gateways[gateway]; !exists {
+		r.order = append(r.order, gateway)
+	}
+
+	supported := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		supported[strings.ToUpper(strings.TrimSpace(currency))] = true
+	}
+	r.gateways[gateway] = registeredGateway{service: service, currencies: supported}
+}
+
+func (r *Registry) Get(gateway domain.PaymentGateway) (domain.PaymentGatewayService, bool) {
+	registered, ok := r.gateways[gateway]
+	return registered.service, ok
+}
+
+func (r *Registry) Supports(gateway domain.PaymentGateway, currency string) bool {
+	registered, ok := r.gateways[gateway]
+	if !ok {
+		return false
+	}
+	return len(registered.currencies) == 0 || registered.currencies[strings.ToUpper(currency)]
+}
+
+func (r *Registry) Gateways() []domain.PaymentGateway {
+	return append([]domain.PaymentGateway(nil), r.order...)
+}
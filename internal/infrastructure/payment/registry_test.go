@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+func newTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(domain.PaymentGatewayWebpayPlus, NewWebpayMockGateway(zap.NewNop()), "CLP")
+	registry.Register(domain.PaymentGatewayStripe, NewCompanyCreditGateway(zap.NewNop()), "clp", " usd")
+	registry.Register(domain.PaymentGatewayBankTransfer, NewBankTransferGateway(BankAccount{}, zap.NewNop()))
+	registry.Register(domain.PaymentGatewayCompanyCredit, NewCompanyCreditGateway(zap.NewNop()))
+	return registry
+}
+
+func TestRegistry_SupportsCurrencies(t *testing.T) {
+	registry := newTestRegistry()
+
+	assert.True(t, registry.Supports(domain.PaymentGatewayWebpayPlus, "CLP"))
+	assert.False(t, registry.Supports(domain.PaymentGatewayWebpayPlus, "USD"))
+	assert.True(t, registry.Supports(domain.PaymentGatewayStripe, "usd"))
+	assert.True(t, registry.Supports(domain.PaymentGatewayBankTransfer, "EUR"))
+	assert.False(t, registry.Supports(domain.PaymentGatewayMercadoPago, "CLP"))
+
+	_, ok := registry.Get(domain.PaymentGatewayMercadoPago)
+	assert.False(t, ok)
+
+	registry.Register(domain.PaymentGatewayWebpayPlus, NewWebpayMockGateway(zap.NewNop()))
+	assert.True(t, registry.Supports(domain.PaymentGatewayWebpayPlus, "USD"))
+	assert.Equal(t, []domain.PaymentGateway{
+		domain.PaymentGatewayWebpayPlus,
+		domain.PaymentGatewayStripe,
+		domain.PaymentGatewayBankTransfer,
+		domain.PaymentGatewayCompanyCredit,
+	}, registry.Gateways())
+}
+
+func TestAvailablePaymentMethods(t *testing.T) {
+	registry := newTestRegistry()
+	companyID := uuid.New()
+
+	assert.Equal(t, []domain.PaymentGateway{
+		domain.PaymentGatewayWebpayPlus,
+		domain.PaymentGatewayStripe,
+		domain.PaymentGatewayBankTransfer,
+	}, domain.AvailablePaymentMethods(registry, nil, "CLP"))
+
+	settings := []domain.CompanyPaymentMethod{
+		{CompanyID: companyID, Gateway: domain.PaymentGatewayCompanyCredit, Currency: "CLP", Enabled: true},
+		{CompanyID: companyID, Gateway: domain.PaymentGatewayWebpayPlus, Currency: "CLP", Enabled: false},
+		{CompanyID: companyID, Gateway: domain.PaymentGatewayBankTransfer, Currency: "CLP", Enabled: true},
+		// Not configured for USD in the registry
+		{CompanyID: companyID, Gateway: domain.PaymentGatewayWebpayPlus, Currency: "USD", Enabled: true},
+	}
+
+	assert.Equal(t, []domain.PaymentGateway{
+		domain.PaymentGatewayBankTransfer,
+		domain.PaymentGatewayCompanyCredit,
+	}, domain.AvailablePaymentMethods(registry, settings, "CLP"))
+
+	assert.Empty(t, domain.AvailablePaymentMethods(registry, settings, "USD"))
+
+	assert.Equal(t, []domain.PaymentGateway{
+		domain.PaymentGatewayBankTransfer,
+	}, domain.AvailablePaymentMethods(registry, settings, "EUR"))
+}
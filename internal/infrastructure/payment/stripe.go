@@ -0,0 +1,352 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	stripeSessionsPath = "/v1/checkout/sessions"
+	stripeRefundsPath  = "/v1/refunds"
+
+	// Stripe signs webhooks with the time they were sent; older ones are
+	// refused so a captured request cannot be replayed
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// Currencies Stripe charges in whole units instead of cents.
+var stripeZeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true,
+	"KRW": true, "MGA": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+type StripeConfig struct {
+	// BaseURL is https://api.stripe.com or the local stand-in.
+	BaseURL   string
+	SecretKey string
+	// ReturnURL receives the customer back from checkout with session_id,
+	// whether they paid or cancelled.
+	ReturnURL string
+	// WebhookSecret is the signing secret (whsec_...) of the webhook
+	// endpoint; without it notifications are refused.
+	WebhookSecret string
+	Timeout       time.Duration
+}
+
+// StripeGateway takes payments through Stripe Checkout. The transaction
+// reference is the checkout session ID and the session's client reference
+// is the payment ID.
+type StripeGateway struct {
+	cfg     StripeConfig
+	baseURL *url.URL
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+func NewStripeGateway(cfg StripeConfig, logger *zap.Logger) (*StripeGateway, error) {
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("stripe secret key is required")
+	}
+	if cfg.ReturnURL == "" {
+		return nil, fmt.Errorf("stripe return url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid stripe base url: %s", cfg.BaseURL)
+	}
+
+	return &StripeGateway{
+		cfg:     cfg,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		logger:  logger,
+	}, nil
+}
+
+type stripeSession struct {
+	Object            string `json:"object"`
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	Status            string `json:"status"`
+	PaymentStatus     string `json:"payment_status"`
+	PaymentIntent     string `json:"payment_intent"`
+	ClientReferenceID string `json:"client_reference_id"`
+	AmountTotal       int64  `json:"amount_total"`
+	Currency          string `json:"currency"`
+}
+
+// stripeAmount converts an amount to the currency's smallest unit.
+func stripeAmount(amount float64, currency string) int64 {
+	if stripeZeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return int64(math.Round(amount))
+	}
+	return int64(math.Round(amount * 100))
+}
+
+// stripeMajorAmount converts an amount in the currency's smallest unit back.
+func stripeMajorAmount(amount int64, currency string) float64 {
+	if stripeZeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return float64(amount)
+	}
+	return float64(amount) / 100
+}
+
+func (s *StripeGateway) ProcessPayment(payment *domain.Payment) (*domain.PaymentResult, error) {
+	s.logger.Info("Creating Stripe checkout session",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", payment.Amount))
+
+	returnURL := s.cfg.ReturnURL
+	if strings.Contains(returnURL, "?") {
+		returnURL += "&session_id={CHECKOUT_SESSION_ID}"
+	} else {
+		returnURL += "?session_id={CHECKOUT_SESSION_ID}"
+	}
+
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", returnURL)
+	form.Set("cancel_url", returnURL)
+	form.Set("client_reference_id", payment.ID.String())
+	form.Set("metadata[payment_id]", payment.ID.String())
+	switch {
+	case payment.InvoiceID != nil:
+		form.Set("metadata[invoice_id]", payment.InvoiceID.String())
+	case payment.WalletID != nil:
+		form.Set("metadata[wallet_id]", payment.WalletID.String())
+	default:
+		form.Set("metadata[reservation_id]", payment.ReservationID)
+	}
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", strings.ToLower(payment.Currency))
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(stripeAmount(payment.Amount, payment.Currency), 10))
+	form.Set("line_items[0][price_data][product_data][name]", payment.Description())
+
+	var session stripeSession
+	if err := s.do(http.MethodPost, stripeSessionsPath, form, payment.ID.String(), &session); err != nil {
+		return nil, err
+	}
+	if session.ID == "" || session.URL == "" {
+		return nil, fmt.Errorf("stripe returned no session id or url")
+	}
+
+	return &domain.PaymentResult{
+		PaymentID:      payment.ID,
+		Status:         domain.PaymentStatusPending,
+		TransactionRef: &session.ID,
+		Payload: map[string]interface{}{
+			"session_id":   session.ID,
+			"url":          session.URL,
+			"amount_total": session.AmountTotal,
+			"currency":     session.Currency,
+		},
+		Redirect: &domain.PaymentRedirect{URL: session.URL, Token: session.ID, Method: http.MethodGet},
+		Message:  "Redirect the customer to Stripe to complete the payment",
+	}, nil
+}
+
+func (s *StripeGateway) SimulatePayment(paymentID uuid.UUID, result domain.PaymentStatus) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// CommitPayment reads the checkout session: it is approved once paid and
+// rejected once expired. Stripe captures the charge itself.
+func (s *StripeGateway) CommitPayment(sessionID string) (*domain.PaymentResult, error) {
+	var session stripeSession
+	if err := s.do(http.MethodGet, stripeSessionsPath+"/"+url.PathEscape(sessionID), nil, "", &session); err != nil {
+		return nil, err
+	}
+
+	paymentID, err := uuid.Parse(session.ClientReferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("stripe session has an unknown client reference: %s", session.ClientReferenceID)
+	}
+
+	status, message := domain.PaymentStatusPending, "Payment is pending"
+	switch {
+	case session.PaymentStatus == "paid" || session.PaymentStatus == "no_payment_required":
+		status, message = domain.PaymentStatusApproved, "Payment approved successfully"
+	case session.Status == "expired":
+		status, message = domain.PaymentStatusRejected, "Checkout session expired"
+	}
+
+	amount := stripeMajorAmount(session.AmountTotal, session.Currency)
+	return &domain.PaymentResult{
+		PaymentID:      paymentID,
+		Status:         status,
+		TransactionRef: &sessionID,
+		Payload: map[string]interface{}{
+			"session_id":     session.ID,
+			"status":         session.Status,
+			"payment_status": session.PaymentStatus,
+			"payment_intent": session.PaymentIntent,
+			"amount_total":   session.AmountTotal,
+			"currency":       session.Currency,
+		},
+		Message: message,
+		Amount:  &amount,
+	}, nil
+}
+
+func (s *StripeGateway) GetPaymentStatus(sessionID string) (*domain.PaymentResult, error) {
+	return s.CommitPayment(sessionID)
+}
+
+// RefundPayment refunds the payment intent recorded when the session was
+// paid.
+func (s *StripeGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	paymentIntent, _ := payment.Payload["payment_intent"].(string)
+	if paymentIntent == "" {
+		return nil, fmt.Errorf("payment %s has no stripe payment intent", payment.ID)
+	}
+
+	s.logger.Info("Refunding Stripe payment",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	form := url.Values{}
+	form.Set("payment_intent", paymentIntent)
+	form.Set("amount", strconv.FormatInt(stripeAmount(amount, payment.Currency), 10))
+	form.Set("metadata[payment_id]", payment.ID.String())
+
+	var refund struct {
+		ID     string `json:"id"`
+		Amount int64  `json:"amount"`
+		Status string `json:"status"`
+	}
+	if err := s.do(http.MethodPost, stripeRefundsPath, form, uuid.NewString(), &refund); err != nil {
+		return nil, err
+	}
+	if refund.Status != "succeeded" && refund.Status != "pending" {
+		return nil, fmt.Errorf("stripe declined the refund: %s", refund.Status)
+	}
+
+	return &domain.RefundResult{
+		TransactionRef: &refund.ID,
+		Payload: map[string]interface{}{
+			"refund_id":      refund.ID,
+			"payment_intent": paymentIntent,
+			"amount":         refund.Amount,
+			"status":         refund.Status,
+		},
+		Message: "Refund processed successfully",
+	}, nil
+}
+
+// VerifyNotification checks the Stripe-Signature header of a webhook event:
+// an HMAC-SHA256 of the timestamp and body with the endpoint secret, sent
+// within the signature tolerance. Checkout session events refer to the
+// session and its payment; other events are returned without either.
+func (s *StripeGateway) VerifyNotification(req domain.PaymentNotificationRequest) (*domain.PaymentNotification, error) {
+	if s.cfg.WebhookSecret == "" {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	header := parseSignatureHeader(req.Header.Get("Stripe-Signature"))
+	timestamp := firstValue(header, "t")
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(sentAt, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return nil, domain.ErrInvalidSignature
+	}
+	if !validHMAC(s.cfg.WebhookSecret, timestamp+"."+string(req.Body), header["v1"]) {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object stripeSession `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(req.Body, &event); err != nil || event.ID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var payload map[string]interface{}
+	_ = json.Unmarshal(req.Body, &payload)
+
+	notification := &domain.PaymentNotification{
+		Gateway:   domain.PaymentGatewayStripe,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Payload:   payload,
+	}
+	if session := event.Data.Object; session.Object == "checkout.session" && session.ID != "" {
+		notification.TransactionRef = &session.ID
+		if paymentID, err := uuid.Parse(session.ClientReferenceID); err == nil {
+			notification.PaymentID = &paymentID
+		}
+	}
+	return notification, nil
+}
+
+// do sends a form-encoded request to Stripe and decodes the JSON response
+// into out. Error responses carry error.message.
+func (s *StripeGateway) do(method, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var reader io.Reader
+	if form != nil {
+		reader = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, s.baseURL.String()+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build stripe request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.SecretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read stripe response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe returned %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe returned %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+	return nil
+}
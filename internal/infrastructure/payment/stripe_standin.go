@@ -0,0 +1,324 @@
+package payment
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const stripeCheckoutPath = "/checkout/pay/"
+
+// StripeStandIn mimics the Stripe endpoints used by StripeGateway, plus a
+// hosted checkout page where the customer pays, cancels or lets the session
+// expire. It can be mounted under any prefix. With a webhook set, paid and
+// expired sessions are notified like Stripe does.
+type StripeStandIn struct {
+	secretKey string
+
+	webhookURL    string
+	webhookSecret string
+
+	mu       sync.Mutex
+	sessions map[string]*standInSession
+	// payment intent -> session
+	intents map[string]*standInSession
+}
+
+type standInSession struct {
+	id                string
+	status            string
+	paymentStatus     string
+	paymentIntent     string
+	clientReferenceID string
+	amountTotal       int64
+	refunded          int64
+	currency          string
+	successURL        string
+	cancelURL         string
+}
+
+func NewStripeStandIn(secretKey string) *StripeStandIn {
+	return &StripeStandIn{
+		secretKey: secretKey,
+		sessions:  make(map[string]*standInSession),
+		intents:   make(map[string]*standInSession),
+	}
+}
+
+// SetWebhook sends checkout session events to url, signed with secret.
+func (s *StripeStandIn) SetWebhook(url, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookURL = url
+	s.webhookSecret = secret
+}
+
+func (s *StripeStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	if idx := strings.Index(path, stripeCheckoutPath); idx >= 0 {
+		id := strings.Trim(path[idx+len(stripeCheckoutPath):], "/")
+		if r.Method == http.MethodPost {
+			s.pay(w, r, id)
+		} else {
+			s.checkout(w, path[:idx+len(stripeCheckoutPath)], id)
+		}
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.secretKey {
+		writeStripeError(w, http.StatusUnauthorized, "authentication_error", "Invalid API Key provided")
+		return
+	}
+
+	switch {
+	case strings.Contains(path, stripeSessionsPath):
+		idx := strings.Index(path, stripeSessionsPath)
+		id := strings.Trim(path[idx+len(stripeSessionsPath):], "/")
+		switch {
+		case id == "" && r.Method == http.MethodPost:
+			s.createSession(w, r, path[:idx])
+		case id != "" && r.Method == http.MethodGet:
+			s.getSession(w, id)
+		default:
+			writeStripeError(w, http.StatusNotFound, "invalid_request_error", "Unrecognized request URL")
+		}
+	case strings.HasSuffix(path, stripeRefundsPath) && r.Method == http.MethodPost:
+		s.refund(w, r)
+	default:
+		writeStripeError(w, http.StatusNotFound, "invalid_request_error", "Unrecognized request URL")
+	}
+}
+
+func (s *StripeStandIn) createSession(w http.ResponseWriter, r *http.Request, prefix string) {
+	if err := r.ParseForm(); err != nil {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Invalid form body")
+		return
+	}
+
+	unitAmount, err := strconv.ParseInt(r.PostForm.Get("line_items[0][price_data][unit_amount]"), 10, 64)
+	quantity, qtyErr := strconv.ParseInt(r.PostForm.Get("line_items[0][quantity]"), 10, 64)
+	switch {
+	case r.PostForm.Get("mode") != "payment":
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Only payment mode is supported")
+		return
+	case err != nil || unitAmount <= 0 || qtyErr != nil || quantity <= 0:
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Invalid line_items[0]")
+		return
+	case r.PostForm.Get("success_url") == "":
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Missing required param: success_url")
+		return
+	}
+
+	session := &standInSession{
+		id:                "cs_test_" + newStandInToken()[:24],
+		status:            "open",
+		paymentStatus:     "unpaid",
+		clientReferenceID: r.PostForm.Get("client_reference_id"),
+		amountTotal:       unitAmount * quantity,
+		currency:          r.PostForm.Get("line_items[0][price_data][currency]"),
+		successURL:        r.PostForm.Get("success_url"),
+		cancelURL:         r.PostForm.Get("cancel_url"),
+	}
+
+	s.mu.Lock()
+	s.sessions[session.id] = session
+	s.mu.Unlock()
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	response := session.response()
+	response["url"] = scheme + "://" + r.Host + prefix + stripeCheckoutPath + session.id
+	writeStandInJSON(w, http.StatusOK, response)
+}
+
+func (s *StripeStandIn) getSession(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		writeStripeError(w, http.StatusNotFound, "invalid_request_error", "No such checkout.session: "+id)
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, session.response())
+}
+
+func (s *StripeStandIn) refund(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Invalid form body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent := r.PostForm.Get("payment_intent")
+	session, ok := s.intents[intent]
+	if !ok {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "No such payment_intent: "+intent)
+		return
+	}
+
+	amount := session.amountTotal - session.refunded
+	if raw := r.PostForm.Get("amount"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Invalid integer: "+raw)
+			return
+		}
+		amount = parsed
+	}
+	if amount <= 0 || amount > session.amountTotal-session.refunded {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "Refund amount is greater than unrefunded amount on charge")
+		return
+	}
+	session.refunded += amount
+
+	writeStandInJSON(w, http.StatusOK, map[string]interface{}{
+		"id":             "re_" + newStandInToken()[:24],
+		"object":         "refund",
+		"amount":         amount,
+		"currency":       session.currency,
+		"payment_intent": intent,
+		"status":         "succeeded",
+	})
+}
+
+func (s *standInSession) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"id":                  s.id,
+		"object":              "checkout.session",
+		"status":              s.status,
+		"payment_status":      s.paymentStatus,
+		"client_reference_id": s.clientReferenceID,
+		"amount_total":        s.amountTotal,
+		"currency":            s.currency,
+		"payment_intent":      nil,
+	}
+	if s.paymentIntent != "" {
+		response["payment_intent"] = s.paymentIntent
+	}
+	return response
+}
+
+var stripeCheckoutForm = template.Must(template.New("stripe").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Stripe Checkout (stand-in)</title></head>
+<body>
+<h1>Stripe Checkout (stand-in)</h1>
+<p>Total: {{.Amount}} {{.Currency}}</p>
+<form method="post" action="{{.PayURL}}">
+<button name="action" value="pay">Pay</button>
+<button name="action" value="cancel">Cancel</button>
+<button name="action" value="expire">Expire session</button>
+</form>
+</body>
+</html>`))
+
+func (s *StripeStandIn) checkout(w http.ResponseWriter, checkoutPath, id string) {
+	s.mu.Lock()
+	session, ok := s.sessions[id]
+	var amount int64
+	var currency string
+	if ok {
+		amount, currency = session.amountTotal, session.currency
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = stripeCheckoutForm.Execute(w, map[string]interface{}{
+		"Amount":   amount,
+		"Currency": strings.ToUpper(currency),
+		"PayURL":   checkoutPath + id,
+	})
+}
+
+// pay completes, cancels or expires the session and sends the customer to
+// the success or cancel URL, with {CHECKOUT_SESSION_ID} filled in.
+func (s *StripeStandIn) pay(w http.ResponseWriter, r *http.Request, id string) {
+	action := r.FormValue("action")
+
+	s.mu.Lock()
+	session, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if session.status != "open" {
+		s.mu.Unlock()
+		http.Error(w, "Session is no longer open", http.StatusUnprocessableEntity)
+		return
+	}
+
+	target, event := session.cancelURL, ""
+	switch action {
+	case "pay":
+		session.status = "complete"
+		session.paymentStatus = "paid"
+		session.paymentIntent = "pi_" + newStandInToken()[:24]
+		s.intents[session.paymentIntent] = session
+		target, event = session.successURL, "checkout.session.completed"
+	case "cancel":
+	case "expire":
+		session.status = "expired"
+		event = "checkout.session.expired"
+	default:
+		s.mu.Unlock()
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+	if event != "" {
+		s.notify(event, session.response())
+	}
+	s.mu.Unlock()
+
+	http.Redirect(w, r, strings.ReplaceAll(target, "{CHECKOUT_SESSION_ID}", id), http.StatusSeeOther)
+}
+
+// notify sends a signed event about object to the webhook, if any. It is
+// called with the lock held.
+func (s *StripeStandIn) notify(eventType string, object map[string]interface{}) {
+	if s.webhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":      "evt_" + newStandInToken()[:24],
+		"object":  "event",
+		"type":    eventType,
+		"created": time.Now().Unix(),
+		"data":    map[string]interface{}{"object": object},
+	})
+	if err != nil {
+		return
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set("Stripe-Signature", "t="+timestamp+",v1="+signHMAC(s.webhookSecret, timestamp+"."+string(body)))
+	postStandInNotification(s.webhookURL, body, header)
+}
+
+func writeStripeError(w http.ResponseWriter, status int, errorType, message string) {
+	writeStandInJSON(w, status, map[string]interface{}{
+		"error": map[string]string{
+			"type":    errorType,
+			"message": message,
+		},
+	})
+}
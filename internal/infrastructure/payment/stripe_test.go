@@ -0,0 +1,183 @@
+package payment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const testStripeSecretKey = "sk_test_key"
+
+func newStripeStandInGateway(t *testing.T) (*StripeGateway, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle("/stripe-standin/", NewStripeStandIn(testStripeSecretKey))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gateway, err := NewStripeGateway(StripeConfig{
+		BaseURL:   server.URL + "/stripe-standin",
+		SecretKey: testStripeSecretKey,
+		ReturnURL: testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return gateway, server
+}
+
+// payAtStripe opens the stand-in checkout, submits action and returns the
+// query the customer is sent back to the return URL with.
+func payAtStripe(t *testing.T, redirect *domain.PaymentRedirect, action string) url.Values {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	page, err := client.Get(redirect.URL)
+	require.NoError(t, err)
+	page.Body.Close()
+	require.Equal(t, http.StatusOK, page.StatusCode)
+
+	resp, err := client.PostForm(redirect.URL, url.Values{"action": {action}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop.test", location.Host)
+	return location.Query()
+}
+
+func TestStripe_PaidSession(t *testing.T) {
+	gateway, _ := newStripeStandInGateway(t)
+	payment := newTestPayment()
+	payment.Gateway = domain.PaymentGatewayStripe
+
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, created.Status)
+	require.NotNil(t, created.Redirect)
+	assert.Equal(t, http.MethodGet, created.Redirect.Method)
+	// CLP has no minor unit
+	assert.Equal(t, int64(15990), created.Payload["amount_total"])
+
+	status, err := gateway.GetPaymentStatus(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, status.Status)
+
+	query := payAtStripe(t, created.Redirect, "pay")
+	assert.Equal(t, created.Redirect.Token, query.Get("session_id"))
+
+	committed, err := gateway.CommitPayment(query.Get("session_id"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusApproved, committed.Status)
+	assert.Equal(t, payment.ID, committed.PaymentID)
+	assert.NotEmpty(t, committed.Payload["payment_intent"])
+}
+
+func TestStripe_CancelledAndExpiredSessions(t *testing.T) {
+	gateway, _ := newStripeStandInGateway(t)
+
+	created, err := gateway.ProcessPayment(newTestPayment())
+	require.NoError(t, err)
+
+	payAtStripe(t, created.Redirect, "cancel")
+	committed, err := gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, committed.Status)
+
+	payAtStripe(t, created.Redirect, "expire")
+	committed, err = gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusRejected, committed.Status)
+}
+
+func TestStripe_Refunds(t *testing.T) {
+	gateway, _ := newStripeStandInGateway(t)
+
+	payment := newTestPayment()
+	payment.Currency = "USD"
+	payment.Amount = 120.5
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12050), created.Payload["amount_total"])
+
+	_, err = gateway.RefundPayment(payment, 10)
+	assert.ErrorContains(t, err, "no stripe payment intent")
+
+	payAtStripe(t, created.Redirect, "pay")
+	committed, err := gateway.CommitPayment(created.Redirect.Token)
+	require.NoError(t, err)
+	payment.Payload = committed.Payload
+
+	refund, err := gateway.RefundPayment(payment, 20.25)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2025), refund.Payload["amount"])
+
+	_, err = gateway.RefundPayment(payment, 200)
+	assert.ErrorContains(t, err, "greater than unrefunded amount")
+}
+
+func TestStripe_RejectsWrongCredentials(t *testing.T) {
+	_, server := newStripeStandInGateway(t)
+
+	gateway, err := NewStripeGateway(StripeConfig{
+		BaseURL:   server.URL + "/stripe-standin",
+		SecretKey: "wrong",
+		ReturnURL: testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = gateway.ProcessPayment(newTestPayment())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestStripe_Notifications(t *testing.T) {
+	sinkURL, received := notificationSink(t)
+
+	standIn := NewStripeStandIn(testStripeSecretKey)
+	standIn.SetWebhook(sinkURL, "whsec_test")
+	mux := http.NewServeMux()
+	mux.Handle("/stripe-standin/", standIn)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gateway, err := NewStripeGateway(StripeConfig{
+		BaseURL:       server.URL + "/stripe-standin",
+		SecretKey:     testStripeSecretKey,
+		ReturnURL:     testReturnURL,
+		WebhookSecret: "whsec_test",
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	payAtStripe(t, created.Redirect, "pay")
+
+	req := awaitNotification(t, received)
+	notification, err := gateway.VerifyNotification(req)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout.session.completed", notification.EventType)
+	require.NotNil(t, notification.TransactionRef)
+	assert.Equal(t, created.Redirect.Token, *notification.TransactionRef)
+	require.NotNil(t, notification.PaymentID)
+	assert.Equal(t, payment.ID, *notification.PaymentID)
+
+	tampered := req
+	tampered.Body = append([]byte(nil), req.Body...)
+	tampered.Body[len(tampered.Body)-2] = ' '
+	_, err = gateway.VerifyNotification(tampered)
+	assert.ErrorIs(t, err, domain.ErrInvalidSignature)
+}
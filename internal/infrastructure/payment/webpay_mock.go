@@ -36,7 +36,7 @@ func (w *WebpayMockGateway) ProcessPayment(payment *domain.Payment) (*domain.Pay
 		"vci":        "TSY",
 		"amount":     payment.Amount,
 		"status":     "AUTHORIZED",
-		"buy_order":  payment.ReservationID,
+		"buy_order":  payment.Reference(),
 		"session_id": payment.ID.String(),
 		"card_detail": map[string]interface{}{
 			"card_number": "XXXX-XXXX-XXXX-1234",
@@ -120,3 +120,38 @@ func (w *WebpayMockGateway) SimulatePayment(paymentID uuid.UUID, result domain.P
 		Message:        message,
 	}, nil
 }
+
+// CommitPayment is not needed: the mock settles payments in ProcessPayment.
+func (w *WebpayMockGateway) CommitPayment(token string) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+func (w *WebpayMockGateway) GetPaymentStatus(token string) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// RefundPayment accepts every refund, reversing the payment when it returns
+// the whole amount like Webpay does on the day of the purchase.
+func (w *WebpayMockGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	w.logger.Info("Refunding payment with Webpay Mock",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	refundType := "NULLIFIED"
+	if amount >= payment.Amount {
+		refundType = "REVERSED"
+	}
+
+	ref := fmt.Sprintf("WP_REF_%d_%s", time.Now().Unix(), payment.ID.String()[:8])
+	return &domain.RefundResult{
+		TransactionRef: &ref,
+		Payload: map[string]interface{}{
+			"type":               refundType,
+			"authorization_code": ref,
+			"authorization_date": time.Now().Format(time.RFC3339),
+			"nullified_amount":   amount,
+			"response_code":      0,
+		},
+		Message: "Refund processed successfully",
+	}, nil
+}
@@ -0,0 +1,294 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	webpayTransactionsPath = "/rswebpaytransaction/api/webpay/v1.2/transactions"
+	webpayBuyOrderMaxLen   = 26
+
+	webpayStatusInitialized = "INITIALIZED"
+	webpayStatusAuthorized  = "AUTHORIZED"
+	webpayStatusReversed    = "REVERSED"
+	webpayStatusNullified   = "NULLIFIED"
+)
+
+type WebpayConfig struct {
+	// BaseURL is the Transbank host, e.g. https://webpay3gint.transbank.cl
+	// for integration or the local stand-in.
+	BaseURL      string
+	CommerceCode string
+	APIKey       string
+	// ReturnURL receives the customer back from the payment form with
+	// token_ws, or TBK_TOKEN/TBK_ORDEN_COMPRA/TBK_ID_SESION when the
+	// payment was aborted or timed out.
+	ReturnURL string
+	Timeout   time.Duration
+}
+
+// WebpayPlusGateway talks to the Transbank Webpay Plus REST API. Creating a
+// payment opens a transaction and returns the form the customer is sent to;
+// the payment stays pending until the transaction is committed on return.
+type WebpayPlusGateway struct {
+	cfg     WebpayConfig
+	baseURL *url.URL
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+func NewWebpayPlusGateway(cfg WebpayConfig, logger *zap.Logger) (*WebpayPlusGateway, error) {
+	if cfg.CommerceCode == "" || cfg.APIKey == "" {
+		return nil, fmt.Errorf("webpay commerce code and api key are required")
+	}
+	if cfg.ReturnURL == "" {
+		return nil, fmt.Errorf("webpay return url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid webpay base url: %s", cfg.BaseURL)
+	}
+
+	return &WebpayPlusGateway{
+		cfg:     cfg,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		logger:  logger,
+	}, nil
+}
+
+// webpayTransaction is the transaction as returned by commit and status.
+type webpayTransaction struct {
+	VCI               string  `json:"vci"`
+	Amount            float64 `json:"amount"`
+	Status            string  `json:"status"`
+	BuyOrder          string  `json:"buy_order"`
+	SessionID         string  `json:"session_id"`
+	AuthorizationCode string  `json:"authorization_code"`
+	PaymentTypeCode   string  `json:"payment_type_code"`
+	ResponseCode      *int    `json:"response_code"`
+}
+
+// WebpayBuyOrder is the buy order sent for a payment: its ID without dashes,
+// cut to the 26 characters Transbank accepts.
+func WebpayBuyOrder(paymentID uuid.UUID) string {
+	return strings.ReplaceAll(paymentID.String(), "-", "")[:webpayBuyOrderMaxLen]
+}
+
+func (w *WebpayPlusGateway) ProcessPayment(payment *domain.Payment) (*domain.PaymentResult, error) {
+	w.logger.Info("Creating Webpay Plus transaction",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", payment.Amount))
+
+	body := map[string]interface{}{
+		"buy_order":  WebpayBuyOrder(payment.ID),
+		"session_id": payment.ID.String(),
+		// Webpay takes whole pesos
+		"amount":     math.Round(payment.Amount),
+		"return_url": w.cfg.ReturnURL,
+	}
+
+	var created struct {
+		Token string `json:"token"`
+		URL   string `json:"url"`
+	}
+	if _, err := w.do(http.MethodPost, webpayTransactionsPath, body, &created); err != nil {
+		return nil, err
+	}
+	if created.Token == "" || created.URL == "" {
+		return nil, fmt.Errorf("webpay returned no token or url")
+	}
+
+	return &domain.PaymentResult{
+		PaymentID:      payment.ID,
+		Status:         domain.PaymentStatusPending,
+		TransactionRef: &created.Token,
+		Payload: map[string]interface{}{
+			"token":      created.Token,
+			"url":        created.URL,
+			"buy_order":  body["buy_order"],
+			"session_id": body["session_id"],
+			"amount":     body["amount"],
+		},
+		Redirect: &domain.PaymentRedirect{URL: created.URL, Token: created.Token, Method: http.MethodPost},
+		Message:  "Redirect the customer to Webpay to complete the payment",
+	}, nil
+}
+
+// SimulatePayment is not available: results come from the customer paying
+// at Webpay (or at the stand-in).
+func (w *WebpayPlusGateway) SimulatePayment(paymentID uuid.UUID, result domain.PaymentStatus) (*domain.PaymentResult, error) {
+	return nil, domain.ErrPaymentOperationUnsupported
+}
+
+// CommitPayment confirms the transaction once the customer is back with
+// token_ws. Transbank only authorizes the charge after the commit.
+func (w *WebpayPlusGateway) CommitPayment(token string) (*domain.PaymentResult, error) {
+	w.logger.Info("Committing Webpay Plus transaction", zap.String("token", token))
+
+	return w.transaction(http.MethodPut, token)
+}
+
+func (w *WebpayPlusGateway) GetPaymentStatus(token string) (*domain.PaymentResult, error) {
+	return w.transaction(http.MethodGet, token)
+}
+
+// RefundPayment returns amount of a committed payment. Transbank reverses
+// the whole charge on the day of the purchase and nullifies (partially or
+// fully) otherwise; both count as an accepted refund.
+func (w *WebpayPlusGateway) RefundPayment(payment *domain.Payment, amount float64) (*domain.RefundResult, error) {
+	if payment.TransactionRef == nil || *payment.TransactionRef == "" {
+		return nil, fmt.Errorf("payment %s has no webpay token", payment.ID)
+	}
+
+	w.logger.Info("Refunding Webpay Plus transaction",
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", amount))
+
+	path := webpayTransactionsPath + "/" + url.PathEscape(*payment.TransactionRef) + "/refunds"
+	var payload map[string]interface{}
+	raw, err := w.do(http.MethodPost, path, map[string]interface{}{"amount": math.Round(amount)}, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var refund struct {
+		Type              string `json:"type"`
+		AuthorizationCode string `json:"authorization_code"`
+		ResponseCode      *int   `json:"response_code"`
+	}
+	if err := json.Unmarshal(raw, &refund); err != nil {
+		return nil, fmt.Errorf("failed to decode webpay refund: %w", err)
+	}
+
+	switch {
+	case refund.Type == webpayStatusReversed:
+	case refund.Type == webpayStatusNullified && refund.ResponseCode != nil && *refund.ResponseCode == 0:
+	default:
+		return nil, fmt.Errorf("webpay declined the refund: %s", refund.Type)
+	}
+
+	var ref *string
+	if refund.AuthorizationCode != "" {
+		ref = &refund.AuthorizationCode
+	}
+	return &domain.RefundResult{
+		TransactionRef: ref,
+		Payload:        payload,
+		Message:        "Refund processed successfully",
+	}, nil
+}
+
+func (w *WebpayPlusGateway) transaction(method, token string) (*domain.PaymentResult, error) {
+	if token == "" {
+		return nil, fmt.Errorf("webpay token is required")
+	}
+
+	var payload map[string]interface{}
+	raw, err := w.do(method, webpayTransactionsPath+"/"+url.PathEscape(token), nil, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx webpayTransaction
+	if err := json.Unmarshal(raw, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode webpay transaction: %w", err)
+	}
+
+	paymentID, err := uuid.Parse(tx.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("webpay transaction has an unknown session id: %s", tx.SessionID)
+	}
+
+	status, message := webpayPaymentStatus(tx)
+	payload["token"] = token
+
+	return &domain.PaymentResult{
+		PaymentID:      paymentID,
+		Status:         status,
+		TransactionRef: &token,
+		Payload:        payload,
+		Message:        message,
+		Amount:         &tx.Amount,
+	}, nil
+}
+
+// webpayPaymentStatus maps a Transbank transaction to a payment status. Only
+// an authorization with response code 0 is an approval.
+func webpayPaymentStatus(tx webpayTransaction) (domain.PaymentStatus, string) {
+	switch {
+	case tx.Status == webpayStatusInitialized:
+		return domain.PaymentStatusPending, "Payment is pending"
+	case tx.Status == webpayStatusAuthorized && tx.ResponseCode != nil && *tx.ResponseCode == 0:
+		return domain.PaymentStatusApproved, "Payment approved successfully"
+	case tx.Status == webpayStatusReversed || tx.Status == webpayStatusNullified:
+		return domain.PaymentStatusRefunded, "Payment was refunded"
+	default:
+		return domain.PaymentStatusRejected, "Payment was rejected"
+	}
+}
+
+// do sends a request to Transbank and decodes the JSON response into out,
+// returning the raw body as well. Error responses carry error_message.
+func (w *WebpayPlusGateway) do(method, path string, body interface{}, out interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webpay request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, w.baseURL.String()+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webpay request: %w", err)
+	}
+	req.Header.Set("Tbk-Api-Key-Id", w.cfg.CommerceCode)
+	req.Header.Set("Tbk-Api-Key-Secret", w.cfg.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webpay request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webpay response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			ErrorMessage string `json:"error_message"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.ErrorMessage != "" {
+			return nil, fmt.Errorf("webpay returned %d: %s", resp.StatusCode, apiErr.ErrorMessage)
+		}
+		return nil, fmt.Errorf("webpay returned %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, fmt.Errorf("failed to decode webpay response: %w", err)
+	}
+	return raw, nil
+}
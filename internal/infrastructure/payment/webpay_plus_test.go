@@ -0,0 +1,225 @@
+package payment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	testCommerceCode = "597055555532"
+	testAPIKey       = "test-api-key"
+	testReturnURL    = "http://shop.test/webpay/return"
+)
+
+func newStandInGateway(t *testing.T) (*WebpayPlusGateway, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle("/webpay-standin/", NewWebpayStandIn(testCommerceCode, testAPIKey))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gateway, err := NewWebpayPlusGateway(WebpayConfig{
+		BaseURL:      server.URL + "/webpay-standin",
+		CommerceCode: testCommerceCode,
+		APIKey:       testAPIKey,
+		ReturnURL:    testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return gateway, server
+}
+
+// payAtStandIn submits the stand-in payment form and returns the query the
+// customer is sent back to the return URL with.
+func payAtStandIn(t *testing.T, redirect *domain.PaymentRedirect, action string) url.Values {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	form, err := client.PostForm(redirect.URL, url.Values{"token_ws": {redirect.Token}})
+	require.NoError(t, err)
+	form.Body.Close()
+	require.Equal(t, http.StatusOK, form.StatusCode)
+
+	payURL, err := url.Parse(redirect.URL)
+	require.NoError(t, err)
+	payURL.Path = payURL.Path[:len(payURL.Path)-len(webpayFormPath)] + webpayPayPath
+
+	resp, err := client.PostForm(payURL.String(), url.Values{
+		"token_ws": {redirect.Token},
+		"action":   {action},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop.test", location.Host)
+	return location.Query()
+}
+
+func newTestPayment() *domain.Payment {
+	return &domain.Payment{
+		ID:            uuid.New(),
+		ReservationID: "RES-1",
+		Gateway:       domain.PaymentGatewayWebpayPlus,
+		Amount:        15990.4,
+		Currency:      "CLP",
+		Status:        domain.PaymentStatusPending,
+	}
+}
+
+func TestWebpayPlus_AuthorizedPayment(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+	payment := newTestPayment()
+
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, created.Status)
+	require.NotNil(t, created.Redirect)
+	assert.Equal(t, created.Redirect.Token, *created.TransactionRef)
+	assert.Equal(t, WebpayBuyOrder(payment.ID), created.Payload["buy_order"])
+
+	status, err := gateway.GetPaymentStatus(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, status.Status)
+
+	query := payAtStandIn(t, created.Redirect, webpayStandInAuthorize)
+	assert.Equal(t, created.Redirect.Token, query.Get("token_ws"))
+
+	committed, err := gateway.CommitPayment(query.Get("token_ws"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusApproved, committed.Status)
+	assert.Equal(t, payment.ID, committed.PaymentID)
+	assert.Equal(t, float64(15990), committed.Payload["amount"])
+
+	status, err = gateway.GetPaymentStatus(created.Redirect.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusApproved, status.Status)
+
+	_, err = gateway.CommitPayment(created.Redirect.Token)
+	assert.ErrorContains(t, err, "already committed")
+}
+
+func TestWebpayPlus_RejectedPayment(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+
+	created, err := gateway.ProcessPayment(newTestPayment())
+	require.NoError(t, err)
+
+	query := payAtStandIn(t, created.Redirect, webpayStandInReject)
+
+	committed, err := gateway.CommitPayment(query.Get("token_ws"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusRejected, committed.Status)
+}
+
+func TestWebpayPlus_AbortedAndTimedOutPayments(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+
+	query := payAtStandIn(t, created.Redirect, webpayStandInAbort)
+	assert.Empty(t, query.Get("token_ws"))
+	assert.Equal(t, created.Redirect.Token, query.Get("TBK_TOKEN"))
+	assert.Equal(t, WebpayBuyOrder(payment.ID), query.Get("TBK_ORDEN_COMPRA"))
+	assert.Equal(t, payment.ID.String(), query.Get("TBK_ID_SESION"))
+
+	_, err = gateway.CommitPayment(created.Redirect.Token)
+	assert.Error(t, err)
+
+	created, err = gateway.ProcessPayment(newTestPayment())
+	require.NoError(t, err)
+
+	query = payAtStandIn(t, created.Redirect, webpayStandInTimeout)
+	assert.Empty(t, query.Get("token_ws"))
+	assert.Empty(t, query.Get("TBK_TOKEN"))
+	assert.NotEmpty(t, query.Get("TBK_ORDEN_COMPRA"))
+}
+
+func TestWebpayPlus_Refunds(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	payment.TransactionRef = created.TransactionRef
+
+	_, err = gateway.RefundPayment(payment, 1000)
+	assert.ErrorContains(t, err, "422")
+
+	query := payAtStandIn(t, created.Redirect, webpayStandInAuthorize)
+	_, err = gateway.CommitPayment(query.Get("token_ws"))
+	require.NoError(t, err)
+
+	refund, err := gateway.RefundPayment(payment, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, "NULLIFIED", refund.Payload["type"])
+	assert.Equal(t, float64(10990), refund.Payload["balance"])
+
+	_, err = gateway.RefundPayment(payment, 20000)
+	assert.ErrorContains(t, err, "amount")
+
+	_, err = gateway.RefundPayment(payment, 10990)
+	require.NoError(t, err)
+
+	status, err := gateway.GetPaymentStatus(*payment.TransactionRef)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusRefunded, status.Status)
+}
+
+func TestWebpayPlus_FullRefundReverses(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+
+	payment := newTestPayment()
+	created, err := gateway.ProcessPayment(payment)
+	require.NoError(t, err)
+	payment.TransactionRef = created.TransactionRef
+
+	query := payAtStandIn(t, created.Redirect, webpayStandInAuthorize)
+	_, err = gateway.CommitPayment(query.Get("token_ws"))
+	require.NoError(t, err)
+
+	refund, err := gateway.RefundPayment(payment, payment.Amount)
+	require.NoError(t, err)
+	assert.Equal(t, "REVERSED", refund.Payload["type"])
+	assert.Nil(t, refund.TransactionRef)
+}
+
+func TestWebpayPlus_RejectsWrongCredentials(t *testing.T) {
+	_, server := newStandInGateway(t)
+
+	gateway, err := NewWebpayPlusGateway(WebpayConfig{
+		BaseURL:      server.URL + "/webpay-standin",
+		CommerceCode: testCommerceCode,
+		APIKey:       "wrong",
+		ReturnURL:    testReturnURL,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = gateway.ProcessPayment(newTestPayment())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestWebpayPlus_SimulateIsUnsupported(t *testing.T) {
+	gateway, _ := newStandInGateway(t)
+
+	_, err := gateway.SimulatePayment(uuid.New(), domain.PaymentStatusApproved)
+	assert.ErrorIs(t, err, domain.ErrPaymentOperationUnsupported)
+}
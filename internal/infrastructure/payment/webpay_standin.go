@@ -0,0 +1,394 @@
+package payment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	webpayFormPath = "/webpayserver/initTransaction"
+	webpayPayPath  = "/webpayserver/pay"
+
+	webpayStandInAuthorize = "authorize"
+	webpayStandInReject    = "reject"
+	webpayStandInAbort     = "abort"
+	webpayStandInTimeout   = "timeout"
+)
+
+// WebpayStandIn mimics the Transbank Webpay Plus endpoints so the payment
+// flow can run offline: the REST API used by WebpayPlusGateway and a payment
+// form where the customer authorizes, rejects, aborts or lets the payment
+// time out. It can be mounted under any prefix; URLs it hands out keep it.
+type WebpayStandIn struct {
+	commerceCode string
+	apiKey       string
+
+	mu           sync.Mutex
+	transactions map[string]*standInTransaction
+}
+
+type standInTransaction struct {
+	buyOrder  string
+	sessionID string
+	amount    float64
+	returnURL string
+	// outcome is what the customer chose on the form; empty until then
+	outcome   string
+	committed bool
+	status    string
+	refunded  float64
+	createdAt time.Time
+}
+
+func NewWebpayStandIn(commerceCode, apiKey string) *WebpayStandIn {
+	return &WebpayStandIn{
+		commerceCode: commerceCode,
+		apiKey:       apiKey,
+		transactions: make(map[string]*standInTransaction),
+	}
+}
+
+func (s *WebpayStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	if idx := strings.Index(path, webpayTransactionsPath); idx >= 0 {
+		prefix := path[:idx]
+		token := strings.Trim(strings.TrimPrefix(path[idx:], webpayTransactionsPath), "/")
+		if !s.authorized(r) {
+			writeStandInError(w, http.StatusUnauthorized, "Not Authorized")
+			return
+		}
+
+		if refundToken := strings.TrimSuffix(token, "/refunds"); refundToken != token {
+			if r.Method != http.MethodPost {
+				writeStandInError(w, http.StatusMethodNotAllowed, "Method not allowed")
+				return
+			}
+			s.refund(w, r, refundToken)
+			return
+		}
+
+		switch {
+		case token == "" && r.Method == http.MethodPost:
+			s.create(w, r, prefix)
+		case token != "" && r.Method == http.MethodPut:
+			s.commit(w, token)
+		case token != "" && r.Method == http.MethodGet:
+			s.status(w, token)
+		default:
+			writeStandInError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(path, webpayFormPath):
+		s.form(w, r, strings.TrimSuffix(path, webpayFormPath))
+	case strings.HasSuffix(path, webpayPayPath) && r.Method == http.MethodPost:
+		s.pay(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *WebpayStandIn) authorized(r *http.Request) bool {
+	return r.Header.Get("Tbk-Api-Key-Id") == s.commerceCode &&
+		r.Header.Get("Tbk-Api-Key-Secret") == s.apiKey
+}
+
+func (s *WebpayStandIn) create(w http.ResponseWriter, r *http.Request, prefix string) {
+	var req struct {
+		BuyOrder  string  `json:"buy_order"`
+		SessionID string  `json:"session_id"`
+		Amount    float64 `json:"amount"`
+		ReturnURL string  `json:"return_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStandInError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	switch {
+	case req.BuyOrder == "" || len(req.BuyOrder) > webpayBuyOrderMaxLen:
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid value for parameter: buy_order")
+		return
+	case req.SessionID == "" || len(req.SessionID) > 61:
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid value for parameter: session_id")
+		return
+	case req.Amount <= 0:
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid value for parameter: amount")
+		return
+	case req.ReturnURL == "":
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid value for parameter: return_url")
+		return
+	}
+
+	token := newStandInToken()
+
+	s.mu.Lock()
+	s.transactions[token] = &standInTransaction{
+		buyOrder:  req.BuyOrder,
+		sessionID: req.SessionID,
+		amount:    req.Amount,
+		returnURL: req.ReturnURL,
+		status:    webpayStatusInitialized,
+		createdAt: time.Now(),
+	}
+	s.mu.Unlock()
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	writeStandInJSON(w, http.StatusOK, map[string]string{
+		"token": token,
+		"url":   scheme + "://" + r.Host + prefix + webpayFormPath,
+	})
+}
+
+// commit authorizes the charge the customer approved on the form. Like
+// Transbank, it fails for transactions that were aborted, never paid or
+// already committed.
+func (s *WebpayStandIn) commit(w http.ResponseWriter, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[token]
+	if !ok {
+		writeStandInError(w, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if tx.committed {
+		writeStandInError(w, http.StatusUnprocessableEntity, "Transaction already committed")
+		return
+	}
+
+	switch tx.outcome {
+	case webpayStandInAuthorize:
+		tx.status = webpayStatusAuthorized
+	case webpayStandInReject:
+		tx.status = "FAILED"
+	default:
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid status '0' for transaction while authorizing")
+		return
+	}
+	tx.committed = true
+
+	writeStandInJSON(w, http.StatusOK, tx.response())
+}
+
+func (s *WebpayStandIn) status(w http.ResponseWriter, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[token]
+	if !ok {
+		writeStandInError(w, http.StatusNotFound, "Transaction not found")
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, tx.response())
+}
+
+// refund reverses the whole amount when nothing was refunded yet and
+// nullifies part of it otherwise. Only authorized transactions with enough
+// balance can be refunded.
+func (s *WebpayStandIn) refund(w http.ResponseWriter, r *http.Request, token string) {
+	var req struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStandInError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[token]
+	if !ok {
+		writeStandInError(w, http.StatusNotFound, "Transaction not found")
+		return
+	}
+	if tx.status != webpayStatusAuthorized {
+		writeStandInError(w, http.StatusUnprocessableEntity, "Transaction can not be refunded")
+		return
+	}
+	balance := tx.amount - tx.refunded
+	if req.Amount <= 0 || req.Amount > balance {
+		writeStandInError(w, http.StatusUnprocessableEntity, "Invalid value for parameter: amount")
+		return
+	}
+
+	if tx.refunded == 0 && req.Amount == tx.amount {
+		tx.refunded = tx.amount
+		tx.status = webpayStatusReversed
+		writeStandInJSON(w, http.StatusOK, map[string]string{"type": webpayStatusReversed})
+		return
+	}
+
+	tx.refunded += req.Amount
+	if tx.refunded >= tx.amount {
+		tx.status = webpayStatusNullified
+	}
+	writeStandInJSON(w, http.StatusOK, map[string]interface{}{
+		"type":               webpayStatusNullified,
+		"authorization_code": "123456",
+		"authorization_date": time.Now().UTC().Format(time.RFC3339),
+		"nullified_amount":   req.Amount,
+		"balance":            tx.amount - tx.refunded,
+		"response_code":      0,
+	})
+}
+
+func (tx *standInTransaction) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"vci":                 "",
+		"amount":              tx.amount,
+		"status":              tx.status,
+		"buy_order":           tx.buyOrder,
+		"session_id":          tx.sessionID,
+		"card_detail":         map[string]string{"card_number": "6623"},
+		"accounting_date":     tx.createdAt.Format("0102"),
+		"transaction_date":    tx.createdAt.UTC().Format(time.RFC3339),
+		"installments_number": 0,
+	}
+
+	switch tx.status {
+	case webpayStatusAuthorized:
+		response["vci"] = "TSY"
+		response["authorization_code"] = "1213"
+		response["payment_type_code"] = "VD"
+		response["response_code"] = 0
+	case webpayStatusReversed, webpayStatusNullified:
+		response["vci"] = "TSY"
+		response["authorization_code"] = "1213"
+		response["payment_type_code"] = "VD"
+		response["response_code"] = 0
+		response["balance"] = tx.amount - tx.refunded
+	case "FAILED":
+		response["vci"] = "TSN"
+		response["payment_type_code"] = "VD"
+		response["response_code"] = -1
+	}
+	return response
+}
+
+var standInForm = template.Must(template.New("webpay").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Webpay Plus (stand-in)</title></head>
+<body>
+<h1>Webpay Plus (stand-in)</h1>
+<p>Orden de compra: {{.BuyOrder}}</p>
+<p>Monto: ${{.Amount}}</p>
+<form method="post" action="{{.PayURL}}">
+<input type="hidden" name="token_ws" value="{{.Token}}">
+<button name="action" value="authorize">Autorizar</button>
+<button name="action" value="reject">Rechazar</button>
+<button name="action" value="abort">Anular compra</button>
+<button name="action" value="timeout">Simular timeout</button>
+</form>
+</body>
+</html>`))
+
+// form is the page the customer lands on with token_ws.
+func (s *WebpayStandIn) form(w http.ResponseWriter, r *http.Request, prefix string) {
+	token := r.FormValue("token_ws")
+
+	s.mu.Lock()
+	tx, ok := s.transactions[token]
+	var buyOrder string
+	var amount float64
+	if ok {
+		buyOrder, amount = tx.buyOrder, tx.amount
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = standInForm.Execute(w, map[string]interface{}{
+		"BuyOrder": buyOrder,
+		"Amount":   amount,
+		"Token":    token,
+		"PayURL":   prefix + webpayPayPath,
+	})
+}
+
+// pay records the customer's choice and sends them back to the return URL
+// the way Transbank does: token_ws for a completed form, TBK_TOKEN plus the
+// order and session for an aborted payment, and only the order and session
+// for a timeout.
+func (s *WebpayStandIn) pay(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token_ws")
+	action := r.FormValue("action")
+
+	s.mu.Lock()
+	tx, ok := s.transactions[token]
+	if ok && (tx.outcome != "" || tx.committed) {
+		s.mu.Unlock()
+		http.Error(w, "Transaction already processed", http.StatusUnprocessableEntity)
+		return
+	}
+	if ok {
+		tx.outcome = action
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+
+	params := url.Values{}
+	switch action {
+	case webpayStandInAuthorize, webpayStandInReject:
+		params.Set("token_ws", token)
+	case webpayStandInAbort:
+		params.Set("TBK_TOKEN", token)
+		params.Set("TBK_ORDEN_COMPRA", tx.buyOrder)
+		params.Set("TBK_ID_SESION", tx.sessionID)
+	case webpayStandInTimeout:
+		params.Set("TBK_ORDEN_COMPRA", tx.buyOrder)
+		params.Set("TBK_ID_SESION", tx.sessionID)
+	default:
+		s.mu.Lock()
+		tx.outcome = ""
+		s.mu.Unlock()
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+
+	separator := "?"
+	if strings.Contains(tx.returnURL, "?") {
+		separator = "&"
+	}
+	http.Redirect(w, r, tx.returnURL+separator+params.Encode(), http.StatusSeeOther)
+}
+
+func newStandInToken() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func writeStandInJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeStandInError(w http.ResponseWriter, status int, message string) {
+	writeStandInJSON(w, status, map[string]string{"error_message": message})
+}
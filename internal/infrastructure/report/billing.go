@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"turivo-backend/internal/domain"
+)
+
+// CompanyPaymentsCSV lists one row per payment with the reservation, cost
+// center and booker it belongs to, so a company can match it to its books.
+func CompanyPaymentsCSV(payments []*domain.CompanyPayment) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{
+		"payment_id", "created_at", "reservation_id", "reservation_datetime",
+		"pickup", "destination", "cost_center", "user_name", "user_email",
+		"gateway", "status", "transaction_ref", "amount", "currency",
+	}}
+	for _, payment := range payments {
+		rows = append(rows, []string{
+			payment.ID.String(),
+			payment.CreatedAt.Format(time.RFC3339),
+			payment.ReservationID,
+			timeValue(payment.ReservationDateTime),
+			payment.Pickup,
+			payment.Destination,
+			stringValue(payment.CostCenter),
+			stringValue(payment.UserName),
+			stringValue(payment.UserEmail),
+			string(payment.Gateway),
+			string(payment.Status),
+			stringValue(payment.TransactionRef),
+			formatAmount(payment.Amount),
+			payment.Currency,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write company payments csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func timeValue(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestCompanyPaymentsCSV(t *testing.T) {
+	costCenter := "CC-Ventas"
+	name := "Ana Rojas"
+	ref := "01ab23cd"
+	reservationAt := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	payment := &domain.CompanyPayment{
+		Payment: domain.Payment{
+			ID:             uuid.MustParse("5d1c2b3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+			ReservationID:  "RSV-1042",
+			Gateway:        domain.PaymentGatewayWebpayPlus,
+			Amount:         45990,
+			Currency:       "CLP",
+			Status:         domain.PaymentStatusApproved,
+			TransactionRef: &ref,
+			CreatedAt:      time.Date(2026, 3, 2, 14, 5, 0, 0, time.UTC),
+		},
+		Pickup:              "Aeropuerto SCL",
+		Destination:         "Hotel W, Las Condes",
+		ReservationDateTime: &reservationAt,
+		CostCenter:          &costCenter,
+		UserName:            &name,
+	}
+
+	out, err := CompanyPaymentsCSV([]*domain.CompanyPayment{payment})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		"5d1c2b3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "2026-03-02T14:05:00Z", "RSV-1042", "2026-03-04T09:30:00Z",
+		"Aeropuerto SCL", "Hotel W, Las Condes", "CC-Ventas", "Ana Rojas", "",
+		"WEBPAY_PLUS", "APPROVED", "01ab23cd", "45990.00", "CLP",
+	}, rows[1])
+}
+
+func TestCompanyPaymentsCSVWithoutReservation(t *testing.T) {
+	walletID := uuid.MustParse("7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d")
+	payment := &domain.CompanyPayment{
+		Payment: domain.Payment{
+			ID:        uuid.MustParse("5d1c2b3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+			WalletID:  &walletID,
+			Gateway:   domain.PaymentGatewayWebpayPlus,
+			Amount:    500000,
+			Currency:  "CLP",
+			Status:    domain.PaymentStatusApproved,
+			CreatedAt: time.Date(2026, 3, 2, 14, 5, 0, 0, time.UTC),
+		},
+	}
+
+	out, err := CompanyPaymentsCSV([]*domain.CompanyPayment{payment})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		"5d1c2b3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d", "2026-03-02T14:05:00Z", "", "",
+		"", "", "", "", "",
+		"WEBPAY_PLUS", "APPROVED", "", "500000.00", "CLP",
+	}, rows[1])
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"fmt"
+
+	"turivo-backend/internal/domain"
+)
+
+var invoiceStatusLabels = map[domain.InvoiceStatus]string{
+	domain.InvoiceStatusIssued:  "Emitida",
+	domain.InvoiceStatusPaid:    "Pagada",
+	domain.InvoiceStatusOverdue: "Vencida",
+	domain.InvoiceStatusVoid:    "Anulada",
+}
+
+// InvoicePDF renders a company's monthly invoice with one line per trip and
+// the tax breakdown.
+func InvoicePDF(invoice *domain.Invoice, company *domain.Company) []byte {
+	doc := NewPDF()
+	lastDay := invoice.PeriodEnd.Add(-1)
+
+	doc.Title(fmt.Sprintf("Factura %s", invoice.Number))
+	doc.Blank()
+	doc.Line(fmt.Sprintf("Cliente:     %s", company.Name))
+	if company.RUT != "" {
+		doc.Line(fmt.Sprintf("RUT:         %s", company.RUT))
+	}
+	doc.Line(fmt.Sprintf("Periodo:     %s al %s", invoice.PeriodStart.Format("02-01-2006"), lastDay.Format("02-01-2006")))
+	doc.Line(fmt.Sprintf("Emitida el:  %s", invoice.IssuedAt.Format("02-01-2006")))
+	doc.Line(fmt.Sprintf("Vence el:    %s", invoice.DueDate.Format("02-01-2006")))
+	doc.Line(fmt.Sprintf("Estado:      %s", invoiceStatusLabels[invoice.Status]))
+	if invoice.PaidAt != nil {
+		doc.Line(fmt.Sprintf("Pagada el:   %s", invoice.PaidAt.Format("02-01-2006")))
+	}
+	if invoice.VoidReason != nil {
+		doc.Line(fmt.Sprintf("Anulada:     %s", *invoice.VoidReason))
+	}
+
+	doc.Blank()
+	doc.Heading("Detalle")
+	doc.Line(fmt.Sprintf("%-4s %-52s %5s %14s", "N", "Descripción", "Cant.", "Monto"))
+	for _, line := range invoice.Lines {
+		doc.Line(fmt.Sprintf("%-4d %-52s %5d %14s",
+			line.Position,
+			truncate(line.Description, 52),
+			line.Quantity,
+			formatAmount(line.Amount),
+		))
+	}
+
+	doc.Blank()
+	doc.Line(fmt.Sprintf("%-20s %16s", "Subtotal", formatAmount(invoice.Subtotal)))
+	doc.Line(fmt.Sprintf("%-20s %16s", fmt.Sprintf("Impuesto (%s%%)", formatRate(invoice.TaxRate)), formatAmount(invoice.TaxAmount)))
+	doc.Line(fmt.Sprintf("%-20s %16s %s", "Total", formatAmount(invoice.Total), invoice.Currency))
+
+	return doc.Bytes()
+}
+
+// formatRate prints a rate such as 0.19 as a percentage without trailing
+// zeros.
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%g", rate*100)
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestInvoicePDF(t *testing.T) {
+	reservationID := "RSV-1042"
+	invoice := &domain.Invoice{
+		Number:      "F-000012",
+		Status:      domain.InvoiceStatusOverdue,
+		Currency:    "CLP",
+		PeriodStart: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		IssuedAt:    time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC),
+		DueDate:     time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+		Subtotal:    25000,
+		TaxRate:     0.19,
+		TaxAmount:   4750,
+		Total:       29750,
+		Lines: []*domain.InvoiceLine{
+			{Position: 1, ReservationID: &reservationID, Description: "Viaje RSV-1042: Aeropuerto - Hotel (Centro)", Quantity: 1, UnitPrice: 25000, Amount: 25000},
+		},
+	}
+	company := &domain.Company{Name: "Minera Norte", RUT: "76.123.456-7"}
+
+	out := string(InvoicePDF(invoice, company))
+
+	assert.Contains(t, out, "Factura F-000012")
+	assert.Contains(t, out, "Periodo:     01-09-2026 al 30-09-2026")
+	assert.Contains(t, out, "Vence el:    31-10-2026")
+	assert.Contains(t, out, "Vencida")
+	assert.Contains(t, out, `Hotel \(Centro\)`)
+	assert.Contains(t, out, "Impuesto \\(19%\\)")
+	assert.Contains(t, out, "29750.00 CLP")
+}
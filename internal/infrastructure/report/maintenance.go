@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"turivo-backend/internal/domain"
+)
+
+// MaintenanceCostCSV lists the maintenance cost of each vehicle by service
+// type, followed by a total row per vehicle.
+func MaintenanceCostCSV(reports []*domain.MaintenanceCostReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{
+		"vehicle_id", "plate", "service_type", "records", "total", "currency",
+	}}
+	for _, report := range reports {
+		for _, line := range report.ByServiceType {
+			rows = append(rows, []string{
+				report.VehicleID,
+				stringValue(report.Plate),
+				string(line.ServiceType),
+				strconv.Itoa(line.Count),
+				formatAmount(line.Total),
+				report.Currency,
+			})
+		}
+		rows = append(rows, []string{
+			report.VehicleID,
+			stringValue(report.Plate),
+			"TOTAL",
+			strconv.Itoa(report.RecordCount),
+			formatAmount(report.TotalCost),
+			report.Currency,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write maintenance cost csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestMaintenanceCostCSV(t *testing.T) {
+	plate := "KXTR-21"
+	reports := []*domain.MaintenanceCostReport{
+		{
+			VehicleID:   "3f2c6a1e-0b4d-4c7a-9e55-0d1f2a3b4c5d",
+			Plate:       &plate,
+			Currency:    "CLP",
+			RecordCount: 3,
+			TotalCost:   185000,
+			ByServiceType: []domain.MaintenanceCostLine{
+				{ServiceType: domain.MaintenanceServiceOilChange, Count: 2, Total: 85000},
+				{ServiceType: domain.MaintenanceServiceBrakes, Count: 1, Total: 100000},
+			},
+		},
+		{
+			VehicleID:     "9a1b2c3d-4e5f-4a6b-8c7d-0e1f2a3b4c5d",
+			Currency:      "CLP",
+			ByServiceType: []domain.MaintenanceCostLine{},
+		},
+	}
+
+	out, err := MaintenanceCostCSV(reports)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, []string{reports[0].VehicleID, "KXTR-21", "OIL_CHANGE", "2", "85000.00", "CLP"}, rows[1])
+	assert.Equal(t, []string{reports[0].VehicleID, "KXTR-21", "TOTAL", "3", "185000.00", "CLP"}, rows[3])
+	assert.Equal(t, []string{reports[1].VehicleID, "", "TOTAL", "0", "0.00", "CLP"}, rows[4])
+}
@@ -0,0 +1,164 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// A4 portrait in points, with a uniform margin.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+)
+
+type pdfFont string
+
+const (
+	pdfFontRegular pdfFont = "F1" // Courier, so table columns line up
+	pdfFontBold    pdfFont = "F2" // Helvetica-Bold, for titles
+)
+
+type pdfLine struct {
+	text string
+	font pdfFont
+	size float64
+}
+
+// PDF builds simple text-only documents using the standard Type 1 fonts,
+// which every viewer ships with, so no font data needs to be embedded.
+// Lines flow top to bottom and continue on a new page when one fills up.
+type PDF struct {
+	lines []pdfLine
+}
+
+func NewPDF() *PDF {
+	return &PDF{}
+}
+
+// Title adds a large bold line.
+func (p *PDF) Title(text string) {
+	p.lines = append(p.lines, pdfLine{text: text, font: pdfFontBold, size: 16})
+}
+
+// Heading adds a bold section heading.
+func (p *PDF) Heading(text string) {
+	p.lines = append(p.lines, pdfLine{text: text, font: pdfFontBold, size: 11})
+}
+
+// Line adds a line of monospaced body text.
+func (p *PDF) Line(text string) {
+	p.lines = append(p.lines, pdfLine{text: text, font: pdfFontRegular, size: 9})
+}
+
+// Blank adds an empty line.
+func (p *PDF) Blank() {
+	p.Line("")
+}
+
+// Bytes renders the document.
+func (p *PDF) Bytes() []byte {
+	pages := p.paginate()
+
+	// Object numbers: 1 catalog, 2 page tree, 3-4 fonts, then a page and a
+	// content stream per page.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, filled in once page numbers are known
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	kids := make([]string, 0, len(pages))
+	for _, page := range pages {
+		pageObject := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObject))
+
+		content := renderPage(page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageObject+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// paginate splits the lines into pages by their leading.
+func (p *PDF) paginate() [][]pdfLine {
+	pages := [][]pdfLine{{}}
+	available := float64(pdfPageHeight - 2*pdfMargin)
+	used := 0.0
+
+	for _, line := range p.lines {
+		leading := line.size * 1.5
+		if used+leading > available && len(pages[len(pages)-1]) > 0 {
+			pages = append(pages, []pdfLine{})
+			used = 0
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], line)
+		used += leading
+	}
+
+	return pages
+}
+
+func renderPage(lines []pdfLine) string {
+	var b strings.Builder
+	y := float64(pdfPageHeight - pdfMargin)
+
+	for _, line := range lines {
+		leading := line.size * 1.5
+		y -= leading
+		if line.text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "BT /%s %.1f Tf %d %.1f Td (%s) Tj ET\n", line.font, line.size, pdfMargin, y, pdfEscape(line.text))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// pdfEscape encodes text as a WinAnsi literal string. Characters outside
+// Latin-1 are replaced with '?'.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
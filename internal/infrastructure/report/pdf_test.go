@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDF_CrossReferenceTablePointsAtObjects(t *testing.T) {
+	doc := NewPDF()
+	doc.Title("Liquidación")
+	for i := 0; i < 120; i++ {
+		doc.Line(fmt.Sprintf("line %d", i))
+	}
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// About 54 body lines fit on an A4 page
+	assert.Contains(t, string(out), "/Count 3")
+}
+
+func TestPDF_StreamLengthMatchesContent(t *testing.T) {
+	doc := NewPDF()
+	doc.Line("hola")
+	out := string(doc.Bytes())
+
+	match := regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)\nendstream`).FindStringSubmatch(out)
+	require.NotNil(t, match)
+	length, _ := strconv.Atoi(match[1])
+	assert.Equal(t, len(match[2]), length)
+}
+
+func TestPDFEscape(t *testing.T) {
+	assert.Equal(t, `Pago \(neto\) \\ total`, pdfEscape(`Pago (neto) \ total`))
+	assert.Equal(t, `Liquidaci\363n`, pdfEscape("Liquidación"))
+	assert.Equal(t, "Total ?", pdfEscape("Total €"))
+	assert.False(t, strings.ContainsAny(pdfEscape("ñandú"), "ñú"))
+}
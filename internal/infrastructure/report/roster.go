@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"turivo-backend/internal/domain"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// icsLineLimit is the maximum line length in octets, excluding the CRLF
+// (RFC 5545 section 3.1).
+const icsLineLimit = 75
+
+// RosterICS renders a driver's shifts as an iCalendar feed. Cancelled shifts
+// are kept with STATUS:CANCELLED so subscribed calendars remove them.
+func RosterICS(driver *domain.Driver, shifts []*domain.Shift, generatedAt time.Time) []byte {
+	var b strings.Builder
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Turivo//Turnos de conductores//ES")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape(fmt.Sprintf("Turnos %s %s", driver.FirstName, driver.LastName)))
+
+	stamp := generatedAt.UTC().Format(icsTimeFormat)
+	for _, shift := range shifts {
+		summary := "Turno"
+		if shift.Region != nil {
+			summary = fmt.Sprintf("Turno (región %s)", *shift.Region)
+		}
+		status := "CONFIRMED"
+		if shift.Status == domain.ShiftStatusCancelled {
+			status = "CANCELLED"
+		}
+
+		var details []string
+		if shift.VehicleID != nil {
+			details = append(details, "Vehículo: "+*shift.VehicleID)
+		}
+		if shift.Notes != nil {
+			details = append(details, *shift.Notes)
+		}
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:shift-%s@turivo", shift.ID))
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART:"+shift.StartsAt.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "DTEND:"+shift.EndsAt.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "LAST-MODIFIED:"+shift.UpdatedAt.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "SUMMARY:"+icsEscape(summary))
+		if len(details) > 0 {
+			writeICSLine(&b, "DESCRIPTION:"+icsEscape(strings.Join(details, "\n")))
+		}
+		writeICSLine(&b, "STATUS:"+status)
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// writeICSLine writes a content line, folding it at the octet limit without
+// splitting UTF-8 sequences. Continuation lines start with a space.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the next line's length
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// icsEscape escapes a TEXT property value.
+func icsEscape(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(text)
+}
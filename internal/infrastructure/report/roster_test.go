@@ -0,0 +1,70 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestRosterICS(t *testing.T) {
+	region := "RM"
+	vehicleID := "3f2c6a1e-0b4d-4c7a-9e55-0d1f2a3b4c5d"
+	notes := "Cubrir traslados al aeropuerto, terminal nacional; llegar 15 minutos antes para revisar el vehículo"
+	driver := &domain.Driver{ID: "CON-001", FirstName: "Ana", LastName: "Pérez"}
+	scheduled := &domain.Shift{
+		ID:        uuid.New(),
+		DriverID:  driver.ID,
+		VehicleID: &vehicleID,
+		StartsAt:  time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		EndsAt:    time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC),
+		Region:    &region,
+		Status:    domain.ShiftStatusScheduled,
+		Notes:     &notes,
+		UpdatedAt: time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC),
+	}
+	cancelled := &domain.Shift{
+		ID:       uuid.New(),
+		DriverID: driver.ID,
+		StartsAt: time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2026, 10, 20, 16, 0, 0, 0, time.UTC),
+		Status:   domain.ShiftStatusCancelled,
+	}
+
+	out := string(RosterICS(driver, []*domain.Shift{scheduled, cancelled}, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"))
+	assert.Contains(t, out, "UID:shift-"+scheduled.ID.String()+"@turivo\r\n")
+	assert.Contains(t, out, "DTSTART:20261019T080000Z\r\n")
+	assert.Contains(t, out, "DTEND:20261019T160000Z\r\n")
+	assert.Contains(t, out, "SUMMARY:Turno (región RM)\r\n")
+	assert.Contains(t, out, "STATUS:CONFIRMED\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+
+	// Unfolding restores the escaped description
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Vehículo: `+vehicleID+`\nCubrir traslados al aeropuerto\, terminal nacional\; llegar`)
+}
+
+func TestWriteICSLineKeepsMultibyteCharacters(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("ñ", 60))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.True(t, strings.HasSuffix(line, "ñ"))
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("ñ", 60), strings.Join(lines, ""))
+}
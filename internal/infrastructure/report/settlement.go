@@ -0,0 +1,137 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"turivo-backend/internal/domain"
+)
+
+var earningTypeLabels = map[domain.EarningEntryType]string{
+	domain.EarningTypeTripPayout:               "Viaje",
+	domain.EarningTypeTip:                      "Propina",
+	domain.EarningTypeCancellationCompensation: "Compensación",
+	domain.EarningTypeAdjustment:               "Ajuste",
+	domain.EarningTypePenalty:                  "Multa",
+}
+
+var settlementStatusLabels = map[domain.SettlementStatus]string{
+	domain.SettlementStatusDraft:    "Borrador",
+	domain.SettlementStatusApproved: "Aprobada",
+	domain.SettlementStatusPaid:     "Pagada",
+}
+
+// SettlementCSV lists the entries of a settlement, one per row, followed by
+// a total row.
+func SettlementCSV(settlement *domain.Settlement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{
+		"settlement_id", "driver_id", "occurred_at", "type", "reservation_id",
+		"description", "gross_fare", "commission", "amount", "currency",
+	}}
+	for _, entry := range settlement.Entries {
+		rows = append(rows, []string{
+			settlement.ID.String(),
+			settlement.DriverID,
+			entry.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+			string(entry.Type),
+			stringValue(entry.ReservationID),
+			stringValue(entry.Description),
+			optionalAmount(entry.GrossFare),
+			optionalAmount(entry.Commission),
+			formatAmount(entry.Amount),
+			entry.Currency,
+		})
+	}
+	rows = append(rows, []string{
+		settlement.ID.String(), settlement.DriverID, "", "TOTAL", "", "", "", "",
+		formatAmount(settlement.Total), settlement.Currency,
+	})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write settlement csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SettlementPDF renders the statement handed to the driver.
+func SettlementPDF(settlement *domain.Settlement, driver *domain.Driver) []byte {
+	doc := NewPDF()
+	lastDay := settlement.PeriodEnd.AddDate(0, 0, -1)
+
+	doc.Title("Liquidación de conductor")
+	doc.Blank()
+	doc.Line(fmt.Sprintf("Conductor:   %s %s (%s)", driver.FirstName, driver.LastName, driver.ID))
+	doc.Line(fmt.Sprintf("Periodo:     %s al %s", settlement.PeriodStart.Format("02-01-2006"), lastDay.Format("02-01-2006")))
+	doc.Line(fmt.Sprintf("Estado:      %s", settlementStatusLabels[settlement.Status]))
+	if settlement.PaidAt != nil {
+		doc.Line(fmt.Sprintf("Pagada el:   %s", settlement.PaidAt.Format("02-01-2006")))
+	}
+	if settlement.PaymentReference != nil {
+		doc.Line(fmt.Sprintf("Referencia:  %s", *settlement.PaymentReference))
+	}
+	doc.Line(fmt.Sprintf("Liquidación: %s", settlement.ID))
+
+	doc.Blank()
+	doc.Heading("Resumen")
+	summary := []struct {
+		label  string
+		amount float64
+	}{
+		{fmt.Sprintf("Viajes (%d)", settlement.TripCount), settlement.TripPayouts},
+		{"Propinas", settlement.Tips},
+		{"Compensaciones", settlement.Compensations},
+		{"Ajustes", settlement.Adjustments},
+		{"Multas", settlement.Penalties},
+	}
+	for _, row := range summary {
+		doc.Line(fmt.Sprintf("%-20s %16s", row.label, formatAmount(row.amount)))
+	}
+	doc.Line(fmt.Sprintf("%-20s %16s %s", "Total a pagar", formatAmount(settlement.Total), settlement.Currency))
+
+	doc.Blank()
+	doc.Heading("Detalle")
+	doc.Line(fmt.Sprintf("%-10s %-13s %-10s %-28s %14s", "Fecha", "Tipo", "Reserva", "Descripción", "Monto"))
+	for _, entry := range settlement.Entries {
+		doc.Line(fmt.Sprintf("%-10s %-13s %-10s %-28s %14s",
+			entry.OccurredAt.Format("02-01-2006"),
+			truncate(earningTypeLabels[entry.Type], 13),
+			truncate(stringValue(entry.ReservationID), 10),
+			truncate(stringValue(entry.Description), 28),
+			formatAmount(entry.Amount),
+		))
+	}
+
+	return doc.Bytes()
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func optionalAmount(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return formatAmount(*amount)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-3]) + "..."
+}
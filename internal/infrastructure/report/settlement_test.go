@@ -0,0 +1,44 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestSettlementCSV(t *testing.T) {
+	reservationID := "RSV-1042"
+	fare, commission := 25000.0, 5000.0
+	note := "Multa por atraso, reincidente"
+	settlement := &domain.Settlement{
+		ID:       uuid.New(),
+		DriverID: "CON-001",
+		Currency: "CLP",
+		SettlementTotals: domain.SettlementTotals{
+			Total: 17000,
+		},
+		Entries: []*domain.EarningEntry{
+			{Type: domain.EarningTypeTripPayout, ReservationID: &reservationID, GrossFare: &fare, Commission: &commission, Amount: 20000, Currency: "CLP", OccurredAt: time.Date(2026, 10, 6, 12, 0, 0, 0, time.UTC)},
+			{Type: domain.EarningTypePenalty, Description: &note, Amount: -3000, Currency: "CLP", OccurredAt: time.Date(2026, 10, 7, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	out, err := SettlementCSV(settlement)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{settlement.ID.String(), "CON-001", "2026-10-06T12:00:00Z", "TRIP_PAYOUT", "RSV-1042", "", "25000.00", "5000.00", "20000.00", "CLP"}, rows[1])
+	assert.Equal(t, note, rows[2][5])
+	assert.Equal(t, "-3000.00", rows[2][8])
+	assert.Equal(t, "TOTAL", rows[3][3])
+	assert.Equal(t, "17000.00", rows[3][8])
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"turivo-backend/internal/domain"
+)
+
+// VehicleUsageCSV lists one row per vehicle with its measured distance, fuel
+// and maintenance spend and efficiency ratios.
+func VehicleUsageCSV(reports []*domain.VehicleUsageReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{
+		"vehicle_id", "plate", "trips", "distance_km", "fuel_liters", "charge_kwh",
+		"fuel_cost", "maintenance_cost", "total_cost", "cost_per_km",
+		"km_per_liter", "kwh_per_100km", "currency",
+	}}
+	for _, report := range reports {
+		rows = append(rows, []string{
+			report.VehicleID,
+			stringValue(report.Plate),
+			strconv.Itoa(report.Trips),
+			formatAmount(report.DistanceKm),
+			formatAmount(report.FuelLiters),
+			formatAmount(report.ChargeKwh),
+			formatAmount(report.FuelCost),
+			formatAmount(report.MaintenanceCost),
+			formatAmount(report.TotalCost),
+			optionalAmount(report.CostPerKm),
+			optionalAmount(report.KmPerLiter),
+			optionalAmount(report.KwhPer100Km),
+			report.Currency,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write vehicle usage csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
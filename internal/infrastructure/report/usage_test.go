@@ -0,0 +1,40 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestVehicleUsageCSV(t *testing.T) {
+	plate := "KXTR-21"
+	report := &domain.VehicleUsageReport{
+		VehicleID: "3f2c6a1e-0b4d-4c7a-9e55-0d1f2a3b4c5d",
+		Plate:     &plate,
+		Currency:  "CLP",
+		VehicleUsageTotals: domain.VehicleUsageTotals{
+			Trips:      12,
+			DistanceKm: 840,
+			FuelLiters: 70,
+			FuelCost:   91000,
+		},
+		MaintenanceCost: 35000,
+	}
+	report.Compute()
+
+	out, err := VehicleUsageCSV([]*domain.VehicleUsageReport{report})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		report.VehicleID, "KXTR-21", "12", "840.00", "70.00", "0.00",
+		"91000.00", "35000.00", "126000.00", "150.00", "12.00", "", "CLP",
+	}, rows[1])
+}
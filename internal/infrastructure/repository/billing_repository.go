@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// BillingRepository reads a company's payments for its reservations,
+// invoices and wallet top-ups.
+type BillingRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewBillingRepository(db *sql.DB, logger *zap.Logger) *BillingRepository {
+	return &BillingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *BillingRepository) ListPayments(filter domain.CompanyPaymentFilter, limit, offset int) ([]*domain.CompanyPayment, int, error) {
+	ctx := context.Background()
+
+	where, args := companyPaymentConditions(filter)
+
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		` + companyPaymentSource + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count company payments", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count company payments: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT p.id, COALESCE(p.reservation_id, ''), p.invoice_id, p.wallet_id, p.gateway,
+		       p.amount::float8, p.currency, p.status, p.transaction_ref, p.payload, p.created_at,
+		       COALESCE(r.pickup, ''), COALESCE(r.destination, ''), r.datetime, r.cost_center,
+		       r.user_id, u.name, u.email
+		%s
+		LEFT JOIN users u ON u.id = r.user_id
+		%s
+		ORDER BY p.created_at DESC, p.id
+		LIMIT $%d OFFSET $%d
+	`, companyPaymentSource, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list company payments", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list company payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []*domain.CompanyPayment{}
+	for rows.Next() {
+		payment, err := scanCompanyPayment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan company payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list company payments: %w", err)
+	}
+
+	return payments, total, nil
+}
+
+func (r *BillingRepository) SummarizePayments(filter domain.CompanyPaymentFilter) (*domain.CompanyPaymentTotals, error) {
+	ctx := context.Background()
+
+	where, args := companyPaymentConditions(filter)
+	totals := &domain.CompanyPaymentTotals{
+		ByStatus: []domain.PaymentStatusTotal{},
+		ByMonth:  []domain.PaymentMonthTotal{},
+	}
+
+	statusQuery := `
+		SELECT p.status, p.currency, COUNT(*), COALESCE(SUM(p.amount), 0)::float8
+		` + companyPaymentSource + where + `
+		GROUP BY p.status, p.currency
+		ORDER BY p.status, p.currency
+	`
+	rows, err := r.db.QueryContext(ctx, statusQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to sum company payments by status", zap.Error(err))
+		return nil, fmt.Errorf("failed to sum company payments by status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total domain.PaymentStatusTotal
+		var status string
+		if err := rows.Scan(&status, &total.Currency, &total.Count, &total.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan payment status total: %w", err)
+		}
+		total.Status = domain.PaymentStatus(status)
+		totals.ByStatus = append(totals.ByStatus, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum company payments by status: %w", err)
+	}
+
+	monthQuery := `
+		SELECT to_char(date_trunc('month', p.created_at), 'YYYY-MM') AS month, p.currency,
+		       COUNT(*), COALESCE(SUM(p.amount), 0)::float8,
+		       COALESCE(SUM(p.amount - COALESCE(rf.amount, 0)) FILTER (WHERE p.status IN ('APPROVED', 'PARTIALLY_REFUNDED', 'REFUNDED')), 0)::float8
+		` + companyPaymentSource + `
+		LEFT JOIN (
+		    SELECT payment_id, SUM(amount) AS amount
+		    FROM refunds
+		    WHERE status = 'COMPLETED'
+		    GROUP BY payment_id
+		) rf ON rf.payment_id = p.id
+		` + where + `
+		GROUP BY month, p.currency
+		ORDER BY month, p.currency
+	`
+	monthRows, err := r.db.QueryContext(ctx, monthQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to sum company payments by month", zap.Error(err))
+		return nil, fmt.Errorf("failed to sum company payments by month: %w", err)
+	}
+	defer monthRows.Close()
+
+	for monthRows.Next() {
+		var total domain.PaymentMonthTotal
+		if err := monthRows.Scan(&total.Month, &total.Currency, &total.Count, &total.Amount, &total.CapturedAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan payment month total: %w", err)
+		}
+		totals.ByMonth = append(totals.ByMonth, total)
+	}
+	if err := monthRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum company payments by month: %w", err)
+	}
+
+	return totals, nil
+}
+
+// companyPaymentSource joins every payment to the company it belongs to:
+// trip payments through their reservation r, invoice payments through the
+// invoice i and wallet top-ups through the wallet w.
+const companyPaymentSource = `
+		FROM payments p
+		LEFT JOIN reservations r ON r.id = p.reservation_id
+		LEFT JOIN invoices i ON i.id = p.invoice_id
+		LEFT JOIN company_wallets w ON w.id = p.wallet_id
+`
+
+// companyPaymentConditions builds the WHERE clause shared by the listing and
+// the totals, over companyPaymentSource.
+func companyPaymentConditions(filter domain.CompanyPaymentFilter) (string, []interface{}) {
+	args := []interface{}{filter.CompanyID}
+	conditions := []string{"COALESCE(r.org_id, i.company_id, w.company_id) = $1"}
+
+	if filter.Status != nil {
+		args = append(args, string(*filter.Status))
+		conditions = append(conditions, fmt.Sprintf("p.status::text = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("p.created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("p.created_at < $%d", len(args)))
+	}
+	if filter.CostCenter != nil {
+		args = append(args, *filter.CostCenter)
+		conditions = append(conditions, fmt.Sprintf("r.cost_center = $%d", len(args)))
+	}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("r.user_id = $%d", len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanCompanyPayment(row rowScanner) (*domain.CompanyPayment, error) {
+	var payment domain.CompanyPayment
+	var gateway, status string
+	var payload []byte
+	err := row.Scan(
+		&payment.ID,
+		&payment.ReservationID,
+		&payment.InvoiceID,
+		&payment.WalletID,
+		&gateway,
+		&payment.Amount,
+		&payment.Currency,
+		&status,
+		&payment.TransactionRef,
+		&payload,
+		&payment.CreatedAt,
+		&payment.Pickup,
+		&payment.Destination,
+		&payment.ReservationDateTime,
+		&payment.CostCenter,
+		&payment.UserID,
+		&payment.UserName,
+		&payment.UserEmail,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.Gateway = domain.PaymentGateway(gateway)
+	payment.Status = domain.PaymentStatus(status)
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &payment.Payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal payment payload: %w", err)
+		}
+	}
+	return &payment, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type ComplianceRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewComplianceRepository(db *sql.DB, logger *zap.Logger) *ComplianceRepository {
+	return &ComplianceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListExpiringDocuments returns licenses of active drivers and vehicle
+// insurance and inspections that expire in the (from, to] window, together with
+// the driver to remind. A driver's login email takes precedence over the
+// contact email on the driver record.
+func (r *ComplianceRepository) ListExpiringDocuments(from, to time.Time) ([]*domain.ExpiringDocument, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT 'LICENSE', d.id, dl.expires_at::timestamptz, d.id,
+		       d.first_name || ' ' || d.last_name, COALESCE(u.email, d.email), NULL
+		FROM driver_licenses dl
+		JOIN drivers d ON d.id = dl.driver_id
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE d.status = 'ACTIVE' AND dl.expires_at > $1 AND dl.expires_at <= $2
+
+		UNION ALL
+
+		SELECT 'INSURANCE', v.id::text, v.insurance_expires_at::timestamptz, d.id,
+		       d.first_name || ' ' || d.last_name, COALESCE(u.email, d.email), v.plate
+		FROM vehicles v
+		LEFT JOIN drivers d ON d.id = v.driver_id
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE v.status != 'INACTIVE' AND v.insurance_expires_at > $1 AND v.insurance_expires_at <= $2
+
+		UNION ALL
+
+		SELECT 'INSPECTION', v.id::text, v.inspection_expires_at::timestamptz, d.id,
+		       d.first_name || ' ' || d.last_name, COALESCE(u.email, d.email), v.plate
+		FROM vehicles v
+		LEFT JOIN drivers d ON d.id = v.driver_id
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE v.status != 'INACTIVE' AND v.inspection_expires_at > $1 AND v.inspection_expires_at <= $2
+
+		ORDER BY 3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list expiring documents", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expiring documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*domain.ExpiringDocument{}
+	for rows.Next() {
+		var doc domain.ExpiringDocument
+		var documentType string
+		if err := rows.Scan(
+			&documentType,
+			&doc.SubjectID,
+			&doc.ExpiresAt,
+			&doc.DriverID,
+			&doc.DriverName,
+			&doc.DriverEmail,
+			&doc.VehiclePlate,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan expiring document: %w", err)
+		}
+		doc.DocumentType = domain.ComplianceDocumentType(documentType)
+		documents = append(documents, &doc)
+	}
+
+	return documents, rows.Err()
+}
+
+func (r *ComplianceRepository) HasReminder(doc *domain.ExpiringDocument, thresholdDays int) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM compliance_reminders
+			WHERE document_type = $1 AND subject_id = $2 AND expires_at = $3 AND threshold_days = $4
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query,
+		string(doc.DocumentType),
+		doc.SubjectID,
+		doc.ExpiresAt,
+		thresholdDays,
+	).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check compliance reminder", zap.Error(err))
+		return false, fmt.Errorf("failed to check compliance reminder: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *ComplianceRepository) RecordReminder(doc *domain.ExpiringDocument, thresholdDays int, sentAt time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO compliance_reminders (document_type, subject_id, expires_at, threshold_days, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (document_type, subject_id, expires_at, threshold_days) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(doc.DocumentType),
+		doc.SubjectID,
+		doc.ExpiresAt,
+		thresholdDays,
+		sentAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to record compliance reminder", zap.Error(err))
+		return fmt.Errorf("failed to record compliance reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrAlreadyExists
+	}
+
+	return nil
+}
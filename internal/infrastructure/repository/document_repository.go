@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const documentColumns = `
+	id, owner_type, owner_id, kind, storage_key, file_name, content_type, size,
+	status, review_note, reviewed_by, reviewed_at, uploaded_by, created_at, updated_at
+`
+
+type DocumentRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewDocumentRepository(db *sql.DB, logger *zap.Logger) *DocumentRepository {
+	return &DocumentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *DocumentRepository) Create(document *domain.Document) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO documents (owner_type, owner_id, kind, storage_key, file_name, content_type, size, status, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		string(document.OwnerType),
+		document.OwnerID,
+		string(document.Kind),
+		document.StorageKey,
+		document.FileName,
+		document.ContentType,
+		document.Size,
+		string(document.Status),
+		document.UploadedBy,
+	).Scan(&document.ID, &document.CreatedAt, &document.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create document", zap.Error(err))
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DocumentRepository) GetByID(id uuid.UUID) (*domain.Document, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + documentColumns + ` FROM documents WHERE id = $1`
+
+	document, err := scanDocument(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDocumentNotFound
+		}
+		r.logger.Error("Failed to get document", zap.Error(err))
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	return document, nil
+}
+
+func (r *DocumentRepository) List(req domain.ListDocumentsRequest) ([]*domain.Document, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.OwnerType != nil {
+		args = append(args, string(*req.OwnerType))
+		conditions = append(conditions, fmt.Sprintf("owner_type = $%d", len(args)))
+	}
+	if req.OwnerID != nil {
+		args = append(args, *req.OwnerID)
+		conditions = append(conditions, fmt.Sprintf("owner_id = $%d", len(args)))
+	}
+	if req.Kind != nil {
+		args = append(args, string(*req.Kind))
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM documents ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count documents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM documents
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, documentColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list documents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []*domain.Document{}
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, total, rows.Err()
+}
+
+// Review approves or rejects a pending document. Returns
+// ErrDocumentAlreadyReviewed if the document is missing or no longer pending.
+func (r *DocumentRepository) Review(id uuid.UUID, status domain.DocumentStatus, note *string, reviewedBy uuid.UUID, reviewedAt time.Time) (*domain.Document, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE documents
+		SET status = $2, review_note = $3, reviewed_by = $4, reviewed_at = $5
+		WHERE id = $1 AND status = 'PENDING'
+		RETURNING ` + documentColumns
+
+	document, err := scanDocument(r.db.QueryRowContext(ctx, query, id, string(status), note, reviewedBy, reviewedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDocumentAlreadyReviewed
+		}
+		r.logger.Error("Failed to review document", zap.Error(err))
+		return nil, fmt.Errorf("failed to review document: %w", err)
+	}
+
+	return document, nil
+}
+
+func scanDocument(row rowScanner) (*domain.Document, error) {
+	var document domain.Document
+	var ownerType, kind, status string
+	err := row.Scan(
+		&document.ID,
+		&ownerType,
+		&document.OwnerID,
+		&kind,
+		&document.StorageKey,
+		&document.FileName,
+		&document.ContentType,
+		&document.Size,
+		&status,
+		&document.ReviewNote,
+		&document.ReviewedBy,
+		&document.ReviewedAt,
+		&document.UploadedBy,
+		&document.CreatedAt,
+		&document.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	document.OwnerType = domain.DocumentOwnerType(ownerType)
+	document.Kind = domain.DocumentKind(kind)
+	document.Status = domain.DocumentStatus(status)
+	return &document, nil
+}
@@ -207,42 +207,3 @@ func (r *DriverDashboardRepository) GetDriverVehicle(driverID string) (*domain.V
 
 	return &vehicle, nil
 }
-
-// UpdateTripStatus updates the status of a trip
-func (r *DriverDashboardRepository) UpdateTripStatus(driverID, tripID, status string) error {
-	ctx := context.Background()
-
-	// First verify that the trip belongs to this driver
-	verifyQuery := `
-		SELECT id FROM reservations 
-		WHERE id = $1 AND assigned_driver_id = $2
-	`
-
-	var tripExists string
-	err := r.db.QueryRow(ctx, verifyQuery, tripID, driverID).Scan(&tripExists)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return domain.ErrNotFound
-		}
-		return fmt.Errorf("failed to verify trip ownership: %w", err)
-	}
-
-	// Update the trip status
-	updateQuery := `
-		UPDATE reservations 
-		SET status = $1, updated_at = NOW()
-		WHERE id = $2 AND assigned_driver_id = $3
-	`
-
-	result, err := r.db.Exec(ctx, updateQuery, status, tripID, driverID)
-	if err != nil {
-		return fmt.Errorf("failed to update trip status: %w", err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
-	}
-
-	return nil
-}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type DriverLocationRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewDriverLocationRepository(db *sql.DB, logger *zap.Logger) *DriverLocationRepository {
+	return &DriverLocationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateBatch stores every ping of an upload in a single multi-row INSERT.
+func (r *DriverLocationRepository) CreateBatch(locations []*domain.DriverLocation) error {
+	if len(locations) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	const columns = 8
+	values := make([]string, 0, len(locations))
+	args := make([]interface{}, 0, len(locations)*columns)
+	for i, location := range locations {
+		base := i * columns
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8))
+		args = append(args,
+			location.DriverID,
+			location.ReservationID,
+			location.Latitude,
+			location.Longitude,
+			location.Accuracy,
+			location.Speed,
+			location.Heading,
+			location.RecordedAt,
+		)
+	}
+
+	query := `
+		INSERT INTO driver_locations (
+			driver_id, reservation_id, latitude, longitude, accuracy, speed, heading, recorded_at
+		)
+		VALUES ` + strings.Join(values, ", ") + `
+		RETURNING id, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to store driver locations", zap.Error(err))
+		return fmt.Errorf("failed to store driver locations: %w", err)
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&locations[i].ID, &locations[i].CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan driver location: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *DriverLocationRepository) GetLatestByReservation(reservationID string) (*domain.DriverLocation, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, driver_id, reservation_id, latitude, longitude, accuracy, speed, heading,
+		       recorded_at, created_at
+		FROM driver_locations
+		WHERE reservation_id = $1
+		ORDER BY recorded_at DESC
+		LIMIT 1
+	`
+
+	var location domain.DriverLocation
+	err := r.db.QueryRowContext(ctx, query, reservationID).Scan(
+		&location.ID,
+		&location.DriverID,
+		&location.ReservationID,
+		&location.Latitude,
+		&location.Longitude,
+		&location.Accuracy,
+		&location.Speed,
+		&location.Heading,
+		&location.RecordedAt,
+		&location.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get latest driver location", zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest driver location: %w", err)
+	}
+
+	return &location, nil
+}
+
+// ListByReservationSince returns the pings of a reservation recorded at or
+// after since, oldest first.
+func (r *DriverLocationRepository) ListByReservationSince(reservationID string, since time.Time) ([]*domain.DriverLocation, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, driver_id, reservation_id, latitude, longitude, accuracy, speed, heading,
+		       recorded_at, created_at
+		FROM driver_locations
+		WHERE reservation_id = $1 AND recorded_at >= $2
+		ORDER BY recorded_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reservationID, since)
+	if err != nil {
+		r.logger.Error("Failed to list driver locations", zap.Error(err))
+		return nil, fmt.Errorf("failed to list driver locations: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []*domain.DriverLocation{}
+	for rows.Next() {
+		var location domain.DriverLocation
+		if err := rows.Scan(
+			&location.ID,
+			&location.DriverID,
+			&location.ReservationID,
+			&location.Latitude,
+			&location.Longitude,
+			&location.Accuracy,
+			&location.Speed,
+			&location.Heading,
+			&location.RecordedAt,
+			&location.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan driver location: %w", err)
+		}
+		locations = append(locations, &location)
+	}
+
+	return locations, rows.Err()
+}
+
+// DeleteOlderThan removes at most limit pings recorded before cutoff so the
+// retention job never holds long locks on the table.
+func (r *DriverLocationRepository) DeleteOlderThan(cutoff time.Time, limit int) (int64, error) {
+	ctx := context.Background()
+
+	query := `
+		DELETE FROM driver_locations
+		WHERE id IN (
+			SELECT id FROM driver_locations
+			WHERE recorded_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, cutoff, limit)
+	if err != nil {
+		r.logger.Error("Failed to prune driver locations", zap.Error(err))
+		return 0, fmt.Errorf("failed to prune driver locations: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
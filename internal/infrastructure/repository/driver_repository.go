@@ -323,14 +323,14 @@ func (r *DriverRepository) GetKPIs(driverID string) (*domain.DriverKPIs, error)
 			-- Total trips completed
 			(SELECT COUNT(*) FROM reservations r WHERE r.assigned_driver_id = $1 AND r.status = 'COMPLETADA') as total_trips,
 			
-			-- Total kilometers
-			(SELECT COALESCE(SUM(r.distance_km), 0) FROM reservations r WHERE r.assigned_driver_id = $1 AND r.status = 'COMPLETADA') as total_km,
+			-- Total kilometers, measured by odometer where recorded and estimated otherwise
+			(SELECT COALESCE(SUM(COALESCE(r.actual_distance_km, r.distance_km)), 0) FROM reservations r WHERE r.assigned_driver_id = $1 AND r.status = 'COMPLETADA') as total_km,
 			
-			-- On-time rate (percentage)
+			-- On-time rate (percentage of trips where the driver reached the pickup)
 			(SELECT CASE 
-				WHEN COUNT(*) = 0 THEN 0
-				ELSE ROUND((COUNT(CASE WHEN r.arrived_on_time = true THEN 1 END) * 100.0 / COUNT(*))::DECIMAL, 1)
-			END FROM reservations r WHERE r.assigned_driver_id = $1 AND r.status = 'COMPLETADA') as on_time_rate,
+				WHEN COUNT(tp.arrived_on_time) = 0 THEN 0
+				ELSE ROUND((COUNT(CASE WHEN tp.arrived_on_time = true THEN 1 END) * 100.0 / COUNT(tp.arrived_on_time))::DECIMAL, 1)
+			END FROM trip_progress tp WHERE tp.driver_id = $1) as on_time_rate,
 			
 			-- Cancel rate (percentage)
 			(SELECT CASE 
@@ -339,7 +339,7 @@ func (r *DriverRepository) GetKPIs(driverID string) (*domain.DriverKPIs, error)
 			END FROM reservations r WHERE r.assigned_driver_id = $1) as cancel_rate,
 			
 			-- Average rating
-			(SELECT COALESCE(ROUND(AVG(df.rating)::DECIMAL, 1), 0) FROM driver_feedback df WHERE df.driver_id = $1) as average_rating
+			(SELECT COALESCE(ROUND(AVG(df.rating)::DECIMAL, 1), 0) FROM driver_feedback df WHERE df.driver_id = $1 AND df.status = 'PUBLISHED') as average_rating
 	`
 
 	var totalTrips int64
@@ -408,41 +408,6 @@ func (r *DriverRepository) GetKPIs(driverID string) (*domain.DriverKPIs, error)
 	}, nil
 }
 
-func (r *DriverRepository) CreateFeedback(feedback *domain.DriverFeedback) error {
-	// For now, skip feedback creation to avoid type conversion issues
-	// TODO: Implement proper UUID conversion
-	return nil
-}
-
-func (r *DriverRepository) GetDriverFeedback(driverID string) ([]*domain.DriverFeedback, error) {
-	ctx := context.Background()
-
-	rows, err := r.queries.GetDriverFeedback(ctx, driverID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get driver feedback: %w", err)
-	}
-
-	feedback := make([]*domain.DriverFeedback, len(rows))
-	for i, row := range rows {
-		var rating float64
-		if row.Rating.Valid {
-			rating = float64(row.Rating.Int.Int64()) / 10.0
-		}
-
-		feedback[i] = &domain.DriverFeedback{
-			ID:            row.ID.String(),
-			DriverID:      row.DriverID,
-			ReservationID: row.ReservationID,
-			Rating:        rating,
-			Comment:       row.Comment,
-			CreatedAt:     row.CreatedAt.Time,
-			UpdatedAt:     row.UpdatedAt.Time,
-		}
-	}
-
-	return feedback, nil
-}
-
 func (r *DriverRepository) mapToDomainDriver(row sqlc.GetDriverByIDRow) *domain.Driver {
 	driver := &domain.Driver{
 		ID:        row.ID,
@@ -629,9 +594,12 @@ func (r *DriverRepository) GetDriverTrips(driverID string) ([]*domain.Reservatio
 		SELECT r.id, r.user_id, r.pickup, r.destination, r.datetime, r.passengers, 
 		       r.status, r.distance_km, r.amount, r.notes, r.assigned_driver_id, 
 		       r.created_at, r.updated_at,
-		       u.name as user_name, u.email as user_email
+		       u.name as user_name, u.email as user_email,
+		       tp.status, tp.en_route_at, tp.arrived_at, tp.onboard_at,
+		       tp.finished_at, tp.no_show_at, tp.arrived_on_time
 		FROM reservations r
 		LEFT JOIN users u ON r.user_id = u.id
+		LEFT JOIN trip_progress tp ON tp.reservation_id = r.id
 		WHERE r.assigned_driver_id = $1
 		ORDER BY r.datetime DESC
 	`
@@ -649,6 +617,8 @@ func (r *DriverRepository) GetDriverTrips(driverID string) ([]*domain.Reservatio
 		var notes pgtype.Text
 		var assignedDriverID pgtype.Text
 		var userName, userEmail pgtype.Text
+		var tripStatus pgtype.Text
+		var progress domain.TripProgress
 
 		err := rows.Scan(
 			&trip.ID,
@@ -666,6 +636,13 @@ func (r *DriverRepository) GetDriverTrips(driverID string) ([]*domain.Reservatio
 			&trip.UpdatedAt,
 			&userName,
 			&userEmail,
+			&tripStatus,
+			&progress.EnRouteAt,
+			&progress.ArrivedAt,
+			&progress.OnboardAt,
+			&progress.FinishedAt,
+			&progress.NoShowAt,
+			&progress.ArrivedOnTime,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan trip: %w", err)
@@ -683,6 +660,15 @@ func (r *DriverRepository) GetDriverTrips(driverID string) ([]*domain.Reservatio
 			trip.AssignedDriverID = &assignedDriverID.String
 		}
 
+		// Add driver-side trip progress
+		if tripStatus.Valid {
+			status := domain.DriverTripStatus(tripStatus.String)
+			progress.ReservationID = trip.ID
+			progress.DriverID = driverID
+			progress.Status = &status
+			trip.Trip = &progress
+		}
+
 		// Add user information
 		if userName.Valid || userEmail.Valid {
 			trip.User = &domain.User{
@@ -752,42 +738,3 @@ func (r *DriverRepository) GetDriverVehicle(driverID string) (*domain.Vehicle, e
 
 	return &vehicle, nil
 }
-
-// UpdateTripStatus updates the status of a trip
-func (r *DriverRepository) UpdateTripStatus(driverID, tripID, status string) error {
-	ctx := context.Background()
-
-	// First verify that the trip belongs to this driver
-	verifyQuery := `
-		SELECT id FROM reservations 
-		WHERE id = $1 AND assigned_driver_id = $2
-	`
-
-	var tripExists string
-	err := r.db.QueryRow(ctx, verifyQuery, tripID, driverID).Scan(&tripExists)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return domain.ErrNotFound
-		}
-		return fmt.Errorf("failed to verify trip ownership: %w", err)
-	}
-
-	// Update the trip status
-	updateQuery := `
-		UPDATE reservations 
-		SET status = $1, updated_at = NOW()
-		WHERE id = $2 AND assigned_driver_id = $3
-	`
-
-	result, err := r.db.Exec(ctx, updateQuery, status, tripID, driverID)
-	if err != nil {
-		return fmt.Errorf("failed to update trip status: %w", err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
-	}
-
-	return nil
-}
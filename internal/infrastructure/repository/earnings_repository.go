@@ -0,0 +1,552 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const earningEntryColumns = `
+	id, driver_id, reservation_id, type, amount, currency, gross_fare, commission,
+	description, settlement_id, occurred_at, created_by, created_at
+`
+
+const settlementColumns = `
+	id, driver_id, period, period_start, period_end, status, currency,
+	trip_count, trip_payouts, tips, compensations, adjustments, penalties, total,
+	payment_reference, approved_by, approved_at, paid_by, paid_at, created_at, updated_at
+`
+
+type EarningsRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewEarningsRepository(db *sql.DB, logger *zap.Logger) *EarningsRepository {
+	return &EarningsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListTripPayoutCandidates returns completed, priced trips with an assigned
+// driver whose payout has not been recorded yet, oldest first.
+func (r *EarningsRepository) ListTripPayoutCandidates(limit int) ([]*domain.TripPayoutCandidate, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT r.id, r.assigned_driver_id, r.amount, r.commission, r.driver_payout, r.currency, r.updated_at
+		FROM reservations r
+		WHERE r.status = 'COMPLETADA'
+		  AND r.assigned_driver_id IS NOT NULL
+		  AND r.amount IS NOT NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM driver_earnings e
+		      WHERE e.reservation_id = r.id AND e.type = 'TRIP_PAYOUT'
+		  )
+		ORDER BY r.updated_at
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("Failed to list trip payout candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list trip payout candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*domain.TripPayoutCandidate{}
+	for rows.Next() {
+		var candidate domain.TripPayoutCandidate
+		if err := rows.Scan(
+			&candidate.ReservationID, &candidate.DriverID, &candidate.Fare,
+			&candidate.Commission, &candidate.DriverPayout, &candidate.Currency, &candidate.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan trip payout candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CreateEntry appends a ledger entry. A second payout for the same trip is
+// rejected by a partial unique index and returns ErrAlreadyExists.
+func (r *EarningsRepository) CreateEntry(entry *domain.EarningEntry) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO driver_earnings (
+			driver_id, reservation_id, type, amount, currency, gross_fare, commission,
+			description, occurred_at, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.DriverID,
+		entry.ReservationID,
+		string(entry.Type),
+		entry.Amount,
+		entry.Currency,
+		entry.GrossFare,
+		entry.Commission,
+		entry.Description,
+		entry.OccurredAt,
+		entry.CreatedBy,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create earning entry", zap.Error(err))
+		return fmt.Errorf("failed to create earning entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EarningsRepository) ListEntries(req domain.ListEarningsRequest) ([]*domain.EarningEntry, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if req.Type != nil {
+		args = append(args, string(*req.Type))
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM driver_earnings ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count earning entries", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count earning entries: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM driver_earnings
+		%s
+		ORDER BY occurred_at DESC, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, earningEntryColumns, where, len(args)-1, len(args))
+
+	entries, err := r.queryEntries(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list earning entries", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// ListUnsettledEntries returns the driver's entries not yet included in a
+// settlement that occurred before the given time. Late entries from earlier
+// periods are carried into the next statement.
+func (r *EarningsRepository) ListUnsettledEntries(driverID string, before time.Time) ([]*domain.EarningEntry, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + earningEntryColumns + `
+		FROM driver_earnings
+		WHERE driver_id = $1 AND settlement_id IS NULL AND occurred_at < $2
+		ORDER BY occurred_at, created_at
+	`
+
+	entries, err := r.queryEntries(ctx, query, driverID, before)
+	if err != nil {
+		r.logger.Error("Failed to list unsettled earning entries", zap.Error(err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *EarningsRepository) ListDriversWithUnsettledEntries(before time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT DISTINCT driver_id
+		FROM driver_earnings
+		WHERE settlement_id IS NULL AND occurred_at < $1
+		ORDER BY driver_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		r.logger.Error("Failed to list drivers with unsettled earnings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list drivers with unsettled earnings: %w", err)
+	}
+	defer rows.Close()
+
+	driverIDs := []string{}
+	for rows.Next() {
+		var driverID string
+		if err := rows.Scan(&driverID); err != nil {
+			return nil, fmt.Errorf("failed to scan driver id: %w", err)
+		}
+		driverIDs = append(driverIDs, driverID)
+	}
+
+	return driverIDs, rows.Err()
+}
+
+func (r *EarningsRepository) GetBalance(driverID string) (*domain.DriverBalance, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT
+			COALESCE(SUM(e.amount) FILTER (WHERE e.settlement_id IS NULL), 0),
+			COALESCE(SUM(e.amount) FILTER (WHERE s.status IN ('DRAFT', 'APPROVED')), 0),
+			COALESCE(SUM(e.amount) FILTER (WHERE s.status = 'PAID'), 0),
+			COALESCE(SUM(e.amount), 0),
+			MAX(s.paid_at)
+		FROM driver_earnings e
+		LEFT JOIN driver_settlements s ON s.id = e.settlement_id
+		WHERE e.driver_id = $1
+	`
+
+	balance := domain.DriverBalance{DriverID: driverID}
+	err := r.db.QueryRowContext(ctx, query, driverID).Scan(
+		&balance.Unsettled,
+		&balance.PendingPayout,
+		&balance.Paid,
+		&balance.LifetimeEarnings,
+		&balance.LastPaidAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to get driver balance", zap.Error(err))
+		return nil, fmt.Errorf("failed to get driver balance: %w", err)
+	}
+
+	return &balance, nil
+}
+
+// CreateSettlement stores the statement and claims its entries in one
+// transaction. It returns ErrAlreadyExists if the driver already has a
+// statement for the period or another run claimed one of the entries first.
+func (r *EarningsRepository) CreateSettlement(settlement *domain.Settlement, entryIDs []uuid.UUID) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO driver_settlements (
+			driver_id, period, period_start, period_end, status, currency,
+			trip_count, trip_payouts, tips, compensations, adjustments, penalties, total
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		settlement.DriverID,
+		string(settlement.Period),
+		settlement.PeriodStart,
+		settlement.PeriodEnd,
+		string(settlement.Status),
+		settlement.Currency,
+		settlement.TripCount,
+		settlement.TripPayouts,
+		settlement.Tips,
+		settlement.Compensations,
+		settlement.Adjustments,
+		settlement.Penalties,
+		settlement.Total,
+	).Scan(&settlement.ID, &settlement.CreatedAt, &settlement.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create settlement", zap.Error(err))
+		return fmt.Errorf("failed to create settlement: %w", err)
+	}
+
+	claim := `UPDATE driver_earnings SET settlement_id = $1 WHERE id = $2 AND settlement_id IS NULL`
+	for _, entryID := range entryIDs {
+		result, err := tx.ExecContext(ctx, claim, settlement.ID, entryID)
+		if err != nil {
+			r.logger.Error("Failed to attach earning entry to settlement", zap.Error(err))
+			return fmt.Errorf("failed to attach earning entry to settlement: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return domain.ErrAlreadyExists
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit settlement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EarningsRepository) GetSettlement(id uuid.UUID) (*domain.Settlement, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + settlementColumns + ` FROM driver_settlements WHERE id = $1`
+
+	settlement, err := scanSettlement(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSettlementNotFound
+		}
+		r.logger.Error("Failed to get settlement", zap.Error(err))
+		return nil, fmt.Errorf("failed to get settlement: %w", err)
+	}
+
+	return settlement, nil
+}
+
+func (r *EarningsRepository) ListSettlementEntries(settlementID uuid.UUID) ([]*domain.EarningEntry, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + earningEntryColumns + `
+		FROM driver_earnings
+		WHERE settlement_id = $1
+		ORDER BY occurred_at, created_at
+	`
+
+	entries, err := r.queryEntries(ctx, query, settlementID)
+	if err != nil {
+		r.logger.Error("Failed to list settlement entries", zap.Error(err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *EarningsRepository) ListSettlements(req domain.ListSettlementsRequest) ([]*domain.Settlement, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM driver_settlements ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count settlements", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count settlements: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM driver_settlements
+		%s
+		ORDER BY period_start DESC, driver_id
+		LIMIT $%d OFFSET $%d
+	`, settlementColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list settlements", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list settlements: %w", err)
+	}
+	defer rows.Close()
+
+	settlements := []*domain.Settlement{}
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan settlement: %w", err)
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, total, rows.Err()
+}
+
+// ApproveSettlement moves a draft to APPROVED. It returns
+// ErrInvalidStatusTransition if the settlement is no longer a draft.
+func (r *EarningsRepository) ApproveSettlement(id uuid.UUID, approvedBy uuid.UUID, approvedAt time.Time) (*domain.Settlement, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE driver_settlements
+		SET status = 'APPROVED', approved_by = $2, approved_at = $3
+		WHERE id = $1 AND status = 'DRAFT'
+		RETURNING ` + settlementColumns
+
+	settlement, err := scanSettlement(r.db.QueryRowContext(ctx, query, id, approvedBy, approvedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		r.logger.Error("Failed to approve settlement", zap.Error(err))
+		return nil, fmt.Errorf("failed to approve settlement: %w", err)
+	}
+
+	return settlement, nil
+}
+
+// MarkSettlementPaid moves an approved settlement to PAID. It returns
+// ErrInvalidStatusTransition if the settlement is not approved.
+func (r *EarningsRepository) MarkSettlementPaid(id uuid.UUID, paidBy uuid.UUID, paidAt time.Time, reference *string) (*domain.Settlement, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE driver_settlements
+		SET status = 'PAID', paid_by = $2, paid_at = $3, payment_reference = $4
+		WHERE id = $1 AND status = 'APPROVED'
+		RETURNING ` + settlementColumns
+
+	settlement, err := scanSettlement(r.db.QueryRowContext(ctx, query, id, paidBy, paidAt, reference))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		r.logger.Error("Failed to mark settlement as paid", zap.Error(err))
+		return nil, fmt.Errorf("failed to mark settlement as paid: %w", err)
+	}
+
+	return settlement, nil
+}
+
+// DeleteDraftSettlement discards a draft. Its entries become unsettled again
+// through the ON DELETE SET NULL foreign key.
+func (r *EarningsRepository) DeleteDraftSettlement(id uuid.UUID) error {
+	ctx := context.Background()
+
+	query := `DELETE FROM driver_settlements WHERE id = $1 AND status = 'DRAFT'`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.Error("Failed to delete draft settlement", zap.Error(err))
+		return fmt.Errorf("failed to delete draft settlement: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrInvalidStatusTransition
+	}
+
+	return nil
+}
+
+func (r *EarningsRepository) queryEntries(ctx context.Context, query string, args ...interface{}) ([]*domain.EarningEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query earning entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.EarningEntry{}
+	for rows.Next() {
+		entry, err := scanEarningEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan earning entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func scanEarningEntry(row rowScanner) (*domain.EarningEntry, error) {
+	var entry domain.EarningEntry
+	var entryType string
+	err := row.Scan(
+		&entry.ID,
+		&entry.DriverID,
+		&entry.ReservationID,
+		&entryType,
+		&entry.Amount,
+		&entry.Currency,
+		&entry.GrossFare,
+		&entry.Commission,
+		&entry.Description,
+		&entry.SettlementID,
+		&entry.OccurredAt,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Type = domain.EarningEntryType(entryType)
+	return &entry, nil
+}
+
+func scanSettlement(row rowScanner) (*domain.Settlement, error) {
+	var settlement domain.Settlement
+	var period, status string
+	err := row.Scan(
+		&settlement.ID,
+		&settlement.DriverID,
+		&period,
+		&settlement.PeriodStart,
+		&settlement.PeriodEnd,
+		&status,
+		&settlement.Currency,
+		&settlement.TripCount,
+		&settlement.TripPayouts,
+		&settlement.Tips,
+		&settlement.Compensations,
+		&settlement.Adjustments,
+		&settlement.Penalties,
+		&settlement.Total,
+		&settlement.PaymentReference,
+		&settlement.ApprovedBy,
+		&settlement.ApprovedAt,
+		&settlement.PaidBy,
+		&settlement.PaidAt,
+		&settlement.CreatedAt,
+		&settlement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement.Period = domain.SettlementPeriod(period)
+	settlement.Status = domain.SettlementStatus(status)
+	return &settlement, nil
+}
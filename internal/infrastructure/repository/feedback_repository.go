@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const feedbackColumns = `
+	id, driver_id, reservation_id, user_id, rating, tags, comment,
+	status, moderation_note, moderated_by, moderated_at, created_at, updated_at
+`
+
+type FeedbackRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewFeedbackRepository(db *sql.DB, logger *zap.Logger) *FeedbackRepository {
+	return &FeedbackRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a rating. The unique index on reservation_id guarantees a
+// single rating per trip; a second attempt returns ErrFeedbackAlreadyExists.
+func (r *FeedbackRepository) Create(feedback *domain.DriverFeedback) error {
+	ctx := context.Background()
+
+	if feedback.Tags == nil {
+		feedback.Tags = []domain.FeedbackTag{}
+	}
+	tags, err := json.Marshal(feedback.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback tags: %w", err)
+	}
+
+	query := `
+		INSERT INTO driver_feedback (driver_id, reservation_id, user_id, rating, tags, comment, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (reservation_id) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		feedback.DriverID,
+		feedback.ReservationID,
+		feedback.UserID,
+		feedback.Rating,
+		tags,
+		feedback.Comment,
+		string(feedback.Status),
+	).Scan(&feedback.ID, &feedback.CreatedAt, &feedback.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrFeedbackAlreadyExists
+		}
+		r.logger.Error("Failed to create feedback", zap.Error(err))
+		return fmt.Errorf("failed to create feedback: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FeedbackRepository) GetByID(id uuid.UUID) (*domain.DriverFeedback, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + feedbackColumns + ` FROM driver_feedback WHERE id = $1`
+
+	feedback, err := scanFeedback(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFeedbackNotFound
+		}
+		r.logger.Error("Failed to get feedback", zap.Error(err))
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+
+	return feedback, nil
+}
+
+func (r *FeedbackRepository) GetByReservation(reservationID string) (*domain.DriverFeedback, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + feedbackColumns + ` FROM driver_feedback WHERE reservation_id = $1`
+
+	feedback, err := scanFeedback(r.db.QueryRowContext(ctx, query, reservationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFeedbackNotFound
+		}
+		r.logger.Error("Failed to get feedback by reservation", zap.Error(err))
+		return nil, fmt.Errorf("failed to get feedback by reservation: %w", err)
+	}
+
+	return feedback, nil
+}
+
+func (r *FeedbackRepository) List(req domain.ListFeedbackRequest) ([]*domain.DriverFeedback, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM driver_feedback ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count feedback", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count feedback: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM driver_feedback
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, feedbackColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list feedback", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	defer rows.Close()
+
+	feedback := []*domain.DriverFeedback{}
+	for rows.Next() {
+		item, err := scanFeedback(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan feedback: %w", err)
+		}
+		feedback = append(feedback, item)
+	}
+
+	return feedback, total, rows.Err()
+}
+
+func (r *FeedbackRepository) Moderate(id uuid.UUID, status domain.FeedbackStatus, note *string, moderatedBy uuid.UUID, moderatedAt time.Time) (*domain.DriverFeedback, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE driver_feedback
+		SET status = $2, moderation_note = $3, moderated_by = $4, moderated_at = $5
+		WHERE id = $1
+		RETURNING ` + feedbackColumns
+
+	feedback, err := scanFeedback(r.db.QueryRowContext(ctx, query, id, string(status), note, moderatedBy, moderatedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFeedbackNotFound
+		}
+		r.logger.Error("Failed to moderate feedback", zap.Error(err))
+		return nil, fmt.Errorf("failed to moderate feedback: %w", err)
+	}
+
+	return feedback, nil
+}
+
+// ListPromptCandidates returns completed trips that have an owner and a driver
+// but no rating prompt yet. Only trips completed after completedSince are
+// considered so the first run does not prompt for historic reservations.
+func (r *FeedbackRepository) ListPromptCandidates(completedSince time.Time, limit int) ([]*domain.FeedbackRequest, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT r.id, r.user_id, r.assigned_driver_id
+		FROM reservations r
+		WHERE r.status = 'COMPLETADA'
+		  AND r.user_id IS NOT NULL
+		  AND r.assigned_driver_id IS NOT NULL
+		  AND r.updated_at >= $1
+		  AND NOT EXISTS (SELECT 1 FROM feedback_requests fr WHERE fr.reservation_id = r.id)
+		  AND NOT EXISTS (SELECT 1 FROM driver_feedback df WHERE df.reservation_id = r.id)
+		ORDER BY r.updated_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, completedSince, limit)
+	if err != nil {
+		r.logger.Error("Failed to list feedback prompt candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list feedback prompt candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*domain.FeedbackRequest{}
+	for rows.Next() {
+		var candidate domain.FeedbackRequest
+		if err := rows.Scan(&candidate.ReservationID, &candidate.UserID, &candidate.DriverID); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback prompt candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CreateRequest records a rating prompt. Returns ErrAlreadyExists if the trip
+// has already been prompted, so concurrent runs never email twice.
+func (r *FeedbackRepository) CreateRequest(request *domain.FeedbackRequest) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO feedback_requests (reservation_id, user_id, driver_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (reservation_id) DO NOTHING
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		request.ReservationID,
+		request.UserID,
+		request.DriverID,
+		request.ExpiresAt,
+	).Scan(&request.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create feedback request", zap.Error(err))
+		return fmt.Errorf("failed to create feedback request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FeedbackRepository) MarkRequestSent(reservationID string, sentAt time.Time) error {
+	ctx := context.Background()
+
+	query := `UPDATE feedback_requests SET sent_at = $2 WHERE reservation_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, reservationID, sentAt); err != nil {
+		r.logger.Error("Failed to mark feedback request as sent", zap.Error(err))
+		return fmt.Errorf("failed to mark feedback request as sent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FeedbackRepository) GetRequest(reservationID string) (*domain.FeedbackRequest, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT reservation_id, user_id, driver_id, expires_at, sent_at, created_at
+		FROM feedback_requests
+		WHERE reservation_id = $1
+	`
+
+	request, err := scanFeedbackRequest(r.db.QueryRowContext(ctx, query, reservationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get feedback request", zap.Error(err))
+		return nil, fmt.Errorf("failed to get feedback request: %w", err)
+	}
+
+	return request, nil
+}
+
+// ListPendingRequests returns the user's open prompts that have not been
+// answered yet.
+func (r *FeedbackRepository) ListPendingRequests(userID uuid.UUID, now time.Time) ([]*domain.FeedbackRequest, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT fr.reservation_id, fr.user_id, fr.driver_id, fr.expires_at, fr.sent_at, fr.created_at
+		FROM feedback_requests fr
+		WHERE fr.user_id = $1
+		  AND fr.expires_at > $2
+		  AND NOT EXISTS (SELECT 1 FROM driver_feedback df WHERE df.reservation_id = fr.reservation_id)
+		ORDER BY fr.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, now)
+	if err != nil {
+		r.logger.Error("Failed to list pending feedback requests", zap.Error(err))
+		return nil, fmt.Errorf("failed to list pending feedback requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []*domain.FeedbackRequest{}
+	for rows.Next() {
+		request, err := scanFeedbackRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feedback request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+func scanFeedback(row rowScanner) (*domain.DriverFeedback, error) {
+	var feedback domain.DriverFeedback
+	var status string
+	var tags []byte
+	err := row.Scan(
+		&feedback.ID,
+		&feedback.DriverID,
+		&feedback.ReservationID,
+		&feedback.UserID,
+		&feedback.Rating,
+		&tags,
+		&feedback.Comment,
+		&status,
+		&feedback.ModerationNote,
+		&feedback.ModeratedBy,
+		&feedback.ModeratedAt,
+		&feedback.CreatedAt,
+		&feedback.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback.Status = domain.FeedbackStatus(status)
+	feedback.Tags = []domain.FeedbackTag{}
+	if len(tags) > 0 {
+		if err := json.Unmarshal(tags, &feedback.Tags); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal feedback tags: %w", err)
+		}
+	}
+
+	return &feedback, nil
+}
+
+func scanFeedbackRequest(row rowScanner) (*domain.FeedbackRequest, error) {
+	var request domain.FeedbackRequest
+	err := row.Scan(
+		&request.ReservationID,
+		&request.UserID,
+		&request.DriverID,
+		&request.ExpiresAt,
+		&request.SentAt,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
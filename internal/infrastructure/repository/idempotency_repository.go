@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type IdempotencyRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewIdempotencyRepository(db *sql.DB, logger *zap.Logger) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Reserve inserts the record, taking over an expired key or one whose
+// request was lost with the same body.
+func (r *IdempotencyRepository) Reserve(record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO idempotency_keys (scope, key, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			completed_at = NULL,
+			locked_until = EXCLUDED.locked_until,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+			OR (idempotency_keys.completed_at IS NULL
+				AND idempotency_keys.locked_until < NOW()
+				AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		record.Scope, record.Key, record.Fingerprint, record.LockedUntil, record.ExpiresAt,
+	).Scan(&record.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		r.logger.Error("Failed to reserve idempotency key", zap.Error(err))
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// The key is taken
+	existing := &domain.IdempotencyRecord{}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	var completedAt sql.NullTime
+	err = r.db.QueryRowContext(ctx, `
+		SELECT scope, key, fingerprint, status_code, content_type, response_body,
+		       completed_at, locked_until, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`, record.Scope, record.Key).Scan(
+		&existing.Scope, &existing.Key, &existing.Fingerprint, &statusCode, &contentType, &existing.ResponseBody,
+		&completedAt, &existing.LockedUntil, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to get idempotency key", zap.Error(err))
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+	if completedAt.Valid {
+		existing.CompletedAt = &completedAt.Time
+	}
+
+	return existing, nil
+}
+
+func (r *IdempotencyRepository) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5, completed_at = NOW()
+		WHERE scope = $1 AND key = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, scope, key, statusCode, contentType, body); err != nil {
+		r.logger.Error("Failed to complete idempotency key", zap.Error(err))
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(scope, key string) error {
+	ctx := context.Background()
+
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND completed_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, scope, key); err != nil {
+		r.logger.Error("Failed to release idempotency key", zap.Error(err))
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes at most limit keys that expired before the given
+// time, keeping each prune short.
+func (r *IdempotencyRepository) DeleteExpired(before time.Time, limit int) (int64, error) {
+	ctx := context.Background()
+
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE (scope, key) IN (
+			SELECT scope, key FROM idempotency_keys
+			WHERE expires_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to prune idempotency keys", zap.Error(err))
+		return 0, fmt.Errorf("failed to prune idempotency keys: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
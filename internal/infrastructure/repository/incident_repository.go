@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const incidentColumns = `
+	id, vehicle_id, driver_id, reservation_id, type, severity, status, description,
+	location, occurred_at, cost, currency, resolution_notes, resolved_at, reported_by,
+	created_at, updated_at
+`
+
+type IncidentRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewIncidentRepository(db *sql.DB, logger *zap.Logger) *IncidentRepository {
+	return &IncidentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *IncidentRepository) Create(incident *domain.Incident) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO incidents (
+			vehicle_id, driver_id, reservation_id, type, severity, status, description,
+			location, occurred_at, cost, currency, reported_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		incident.VehicleID,
+		incident.DriverID,
+		incident.ReservationID,
+		string(incident.Type),
+		string(incident.Severity),
+		string(incident.Status),
+		incident.Description,
+		incident.Location,
+		incident.OccurredAt,
+		incident.Cost,
+		incident.Currency,
+		incident.ReportedBy,
+	).Scan(&incident.ID, &incident.CreatedAt, &incident.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create incident", zap.Error(err))
+		return fmt.Errorf("failed to create incident: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IncidentRepository) GetByID(id uuid.UUID) (*domain.Incident, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE id = $1`
+
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrIncidentNotFound
+		}
+		r.logger.Error("Failed to get incident", zap.Error(err))
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+func (r *IncidentRepository) Update(incident *domain.Incident) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE incidents
+		SET severity = $2, status = $3, description = $4, location = $5, cost = $6,
+			resolution_notes = $7, resolved_at = $8
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		incident.ID,
+		string(incident.Severity),
+		string(incident.Status),
+		incident.Description,
+		incident.Location,
+		incident.Cost,
+		incident.ResolutionNotes,
+		incident.ResolvedAt,
+	).Scan(&incident.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrIncidentNotFound
+		}
+		r.logger.Error("Failed to update incident", zap.Error(err))
+		return fmt.Errorf("failed to update incident: %w", err)
+	}
+
+	return nil
+}
+
+// List filters by the time the incident occurred.
+func (r *IncidentRepository) List(req domain.ListIncidentsRequest) ([]*domain.Incident, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.VehicleID != nil {
+		args = append(args, *req.VehicleID)
+		conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", len(args)))
+	}
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if req.Severity != nil {
+		args = append(args, string(*req.Severity))
+		conditions = append(conditions, fmt.Sprintf("severity = $%d", len(args)))
+	}
+	if req.Type != nil {
+		args = append(args, string(*req.Type))
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM incidents ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count incidents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count incidents: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM incidents
+		%s
+		ORDER BY occurred_at DESC, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, incidentColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list incidents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := []*domain.Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list incidents: %w", err)
+	}
+
+	return incidents, total, nil
+}
+
+func scanIncident(row rowScanner) (*domain.Incident, error) {
+	var incident domain.Incident
+	var incidentType, severity, status string
+	err := row.Scan(
+		&incident.ID,
+		&incident.VehicleID,
+		&incident.DriverID,
+		&incident.ReservationID,
+		&incidentType,
+		&severity,
+		&status,
+		&incident.Description,
+		&incident.Location,
+		&incident.OccurredAt,
+		&incident.Cost,
+		&incident.Currency,
+		&incident.ResolutionNotes,
+		&incident.ResolvedAt,
+		&incident.ReportedBy,
+		&incident.CreatedAt,
+		&incident.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Type = domain.IncidentType(incidentType)
+	incident.Severity = domain.IncidentSeverity(severity)
+	incident.Status = domain.IncidentStatus(status)
+	return &incident, nil
+}
@@ -0,0 +1,718 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const billingAccountColumns = `
+	id, company_id, currency, tax_rate, payment_terms_days, billing_email, active, created_at, updated_at
+`
+
+const billingPeriodColumns = `
+	id, account_id, period_start, period_end, status, invoice_id, closed_at, created_at, updated_at
+`
+
+const billingAccrualColumns = `
+	id, account_id, period_id, reservation_id, description, amount, currency, occurred_at, created_at
+`
+
+const invoiceColumns = `
+	id, number, account_id, company_id, period_start, period_end, status, currency,
+	subtotal, tax_rate, tax_amount, total, issued_at, due_date, paid_at, payment_id,
+	voided_at, void_reason, created_at, updated_at
+`
+
+// InvoiceRepository stores the billing accounts of postpaid companies,
+// their periods and accruals, and the invoices the periods are closed into.
+type InvoiceRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewInvoiceRepository(db *sql.DB, logger *zap.Logger) *InvoiceRepository {
+	return &InvoiceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *InvoiceRepository) GetAccountByCompany(companyID uuid.UUID) (*domain.BillingAccount, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + billingAccountColumns + ` FROM billing_accounts WHERE company_id = $1`
+
+	account, err := scanBillingAccount(r.db.QueryRowContext(ctx, query, companyID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBillingAccountNotFound
+		}
+		r.logger.Error("Failed to get billing account", zap.Error(err))
+		return nil, fmt.Errorf("failed to get billing account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *InvoiceRepository) GetAccount(id uuid.UUID) (*domain.BillingAccount, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + billingAccountColumns + ` FROM billing_accounts WHERE id = $1`
+
+	account, err := scanBillingAccount(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBillingAccountNotFound
+		}
+		r.logger.Error("Failed to get billing account", zap.Error(err))
+		return nil, fmt.Errorf("failed to get billing account: %w", err)
+	}
+
+	return account, nil
+}
+
+// CreateAccount stores the account and its first open period in one
+// transaction. It returns ErrAlreadyExists if the company has an account.
+func (r *InvoiceRepository) CreateAccount(account *domain.BillingAccount, period *domain.BillingPeriod) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO billing_accounts (company_id, currency, tax_rate, payment_terms_days, billing_email, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		account.CompanyID,
+		account.Currency,
+		account.TaxRate,
+		account.PaymentTermsDays,
+		account.BillingEmail,
+		account.Active,
+	).Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create billing account", zap.Error(err))
+		return fmt.Errorf("failed to create billing account: %w", err)
+	}
+
+	period.AccountID = account.ID
+	if err := insertBillingPeriod(ctx, tx, period); err != nil {
+		r.logger.Error("Failed to open billing period", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit billing account: %w", err)
+	}
+
+	return nil
+}
+
+func (r *InvoiceRepository) UpdateAccount(account *domain.BillingAccount) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE billing_accounts
+		SET currency = $2, tax_rate = $3, payment_terms_days = $4, billing_email = $5, active = $6
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		account.ID,
+		account.Currency,
+		account.TaxRate,
+		account.PaymentTermsDays,
+		account.BillingEmail,
+		account.Active,
+	).Scan(&account.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrBillingAccountNotFound
+		}
+		r.logger.Error("Failed to update billing account", zap.Error(err))
+		return fmt.Errorf("failed to update billing account: %w", err)
+	}
+
+	return nil
+}
+
+// ListAccrualCandidates returns completed, priced trips of companies with an
+// active billing account in currency that have not been accrued yet, oldest
+// first. Trips completed before the account was opened or already paid on
+// their own through a gateway are left out; company credit payments are
+// billed on the invoice. Companies with a wallet are debited from it
+// instead.
+func (r *InvoiceRepository) ListAccrualCandidates(currency string, limit int) ([]*domain.BillingAccrualCandidate, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT a.id, p.id, r.id, r.pickup, r.destination, r.amount, a.currency, r.updated_at
+		FROM reservations r
+		JOIN billing_accounts a ON a.company_id = r.org_id AND a.active AND a.currency = $1
+		JOIN billing_periods p ON p.account_id = a.id AND p.status = 'OPEN'
+		WHERE r.status = 'COMPLETADA'
+		  AND r.amount IS NOT NULL
+		  AND r.updated_at >= a.created_at
+		  AND NOT EXISTS (
+		      SELECT 1 FROM billing_accruals b WHERE b.reservation_id = r.id
+		  )
+		  AND NOT EXISTS (
+		      SELECT 1 FROM company_wallets w WHERE w.company_id = r.org_id
+		  )
+		  AND NOT EXISTS (
+		      SELECT 1 FROM payments pay
+		      WHERE pay.reservation_id = r.id
+		        AND pay.status IN ('APPROVED', 'PARTIALLY_REFUNDED', 'REFUNDED')
+		        AND pay.gateway <> 'COMPANY_CREDIT'
+		  )
+		ORDER BY r.updated_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, currency, limit)
+	if err != nil {
+		r.logger.Error("Failed to list billing accrual candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list billing accrual candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*domain.BillingAccrualCandidate{}
+	for rows.Next() {
+		var candidate domain.BillingAccrualCandidate
+		if err := rows.Scan(
+			&candidate.AccountID,
+			&candidate.PeriodID,
+			&candidate.ReservationID,
+			&candidate.Pickup,
+			&candidate.Destination,
+			&candidate.Amount,
+			&candidate.Currency,
+			&candidate.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan billing accrual candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CreateAccrual adds a trip to a period while it is open. A trip accrued
+// before, or a period closed meanwhile, returns ErrAlreadyExists; in the
+// latter case the next run accrues the trip to the following period.
+func (r *InvoiceRepository) CreateAccrual(accrual *domain.BillingAccrual) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO billing_accruals (account_id, period_id, reservation_id, description, amount, currency, occurred_at)
+		SELECT $1, p.id, $3, $4, $5, $6, $7
+		FROM billing_periods p
+		WHERE p.id = $2 AND p.status = 'OPEN'
+		FOR SHARE
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		accrual.AccountID,
+		accrual.PeriodID,
+		accrual.ReservationID,
+		accrual.Description,
+		accrual.Amount,
+		accrual.Currency,
+		accrual.OccurredAt,
+	).Scan(&accrual.ID, &accrual.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create billing accrual", zap.Error(err))
+		return fmt.Errorf("failed to create billing accrual: %w", err)
+	}
+
+	return nil
+}
+
+func (r *InvoiceRepository) ListAccruals(periodID uuid.UUID) ([]*domain.BillingAccrual, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + billingAccrualColumns + `
+		FROM billing_accruals
+		WHERE period_id = $1
+		ORDER BY occurred_at, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, periodID)
+	if err != nil {
+		r.logger.Error("Failed to list billing accruals", zap.Error(err))
+		return nil, fmt.Errorf("failed to list billing accruals: %w", err)
+	}
+	defer rows.Close()
+
+	accruals := []*domain.BillingAccrual{}
+	for rows.Next() {
+		var accrual domain.BillingAccrual
+		if err := rows.Scan(
+			&accrual.ID,
+			&accrual.AccountID,
+			&accrual.PeriodID,
+			&accrual.ReservationID,
+			&accrual.Description,
+			&accrual.Amount,
+			&accrual.Currency,
+			&accrual.OccurredAt,
+			&accrual.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan billing accrual: %w", err)
+		}
+		accruals = append(accruals, &accrual)
+	}
+
+	return accruals, rows.Err()
+}
+
+func (r *InvoiceRepository) GetOpenPeriod(accountID uuid.UUID) (*domain.BillingPeriod, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + billingPeriodColumns + ` FROM billing_periods WHERE account_id = $1 AND status = 'OPEN'`
+
+	period, err := scanBillingPeriod(r.db.QueryRowContext(ctx, query, accountID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get open billing period", zap.Error(err))
+		return nil, fmt.Errorf("failed to get open billing period: %w", err)
+	}
+
+	return period, nil
+}
+
+func (r *InvoiceRepository) ListPeriodsToClose(endedBy time.Time) ([]*domain.BillingPeriod, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + billingPeriodColumns + `
+		FROM billing_periods
+		WHERE status = 'OPEN' AND period_end <= $1
+		ORDER BY period_end
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, endedBy)
+	if err != nil {
+		r.logger.Error("Failed to list billing periods to close", zap.Error(err))
+		return nil, fmt.Errorf("failed to list billing periods to close: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []*domain.BillingPeriod{}
+	for rows.Next() {
+		period, err := scanBillingPeriod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan billing period: %w", err)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+// ClosePeriod closes the period only if it is still open, so two runs never
+// invoice it twice.
+func (r *InvoiceRepository) ClosePeriod(period *domain.BillingPeriod, invoice *domain.Invoice, next *domain.BillingPeriod) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var invoiceID *uuid.UUID
+	if invoice != nil {
+		if err := insertInvoice(ctx, tx, invoice); err != nil {
+			if err != domain.ErrAlreadyExists {
+				r.logger.Error("Failed to create invoice", zap.Error(err))
+			}
+			return err
+		}
+		invoiceID = &invoice.ID
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE billing_periods
+		SET status = 'CLOSED', period_end = $2, invoice_id = $3, closed_at = $4
+		WHERE id = $1 AND status = 'OPEN'
+	`, period.ID, period.PeriodEnd, invoiceID, period.ClosedAt)
+	if err != nil {
+		r.logger.Error("Failed to close billing period", zap.Error(err))
+		return fmt.Errorf("failed to close billing period: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return domain.ErrAlreadyExists
+	}
+
+	// The period row is locked now; a trip accrued after the invoice was
+	// built would be left out of it, so give up and let the next run retry
+	var accrued int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM billing_accruals WHERE period_id = $1`, period.ID).Scan(&accrued); err != nil {
+		return fmt.Errorf("failed to count billing accruals: %w", err)
+	}
+	invoiced := 0
+	if invoice != nil {
+		invoiced = len(invoice.Lines)
+	}
+	if accrued != invoiced {
+		return fmt.Errorf("billing period %s changed while closing", period.ID)
+	}
+
+	if err := insertBillingPeriod(ctx, tx, next); err != nil {
+		r.logger.Error("Failed to open billing period", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit billing period: %w", err)
+	}
+
+	period.InvoiceID = invoiceID
+	return nil
+}
+
+func insertBillingPeriod(ctx context.Context, tx *sql.Tx, period *domain.BillingPeriod) error {
+	query := `
+		INSERT INTO billing_periods (account_id, period_start, period_end, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		period.AccountID,
+		period.PeriodStart,
+		period.PeriodEnd,
+		string(domain.BillingPeriodStatusOpen),
+	).Scan(&period.ID, &period.CreatedAt, &period.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to open billing period: %w", err)
+	}
+
+	period.Status = domain.BillingPeriodStatusOpen
+	return nil
+}
+
+// insertInvoice stores the invoice and its lines. The number is assigned by
+// the database.
+func insertInvoice(ctx context.Context, tx *sql.Tx, invoice *domain.Invoice) error {
+	query := `
+		INSERT INTO invoices (
+			account_id, company_id, period_start, period_end, status, currency,
+			subtotal, tax_rate, tax_amount, total, issued_at, due_date
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT DO NOTHING
+		RETURNING id, number, created_at, updated_at
+	`
+
+	err := tx.QueryRowContext(ctx, query,
+		invoice.AccountID,
+		invoice.CompanyID,
+		invoice.PeriodStart,
+		invoice.PeriodEnd,
+		string(invoice.Status),
+		invoice.Currency,
+		invoice.Subtotal,
+		invoice.TaxRate,
+		invoice.TaxAmount,
+		invoice.Total,
+		invoice.IssuedAt,
+		invoice.DueDate.Format("2006-01-02"),
+	).Scan(&invoice.ID, &invoice.Number, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	lineQuery := `
+		INSERT INTO invoice_lines (invoice_id, position, reservation_id, description, quantity, unit_price, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	for _, line := range invoice.Lines {
+		line.InvoiceID = invoice.ID
+		err := tx.QueryRowContext(ctx, lineQuery,
+			line.InvoiceID,
+			line.Position,
+			line.ReservationID,
+			line.Description,
+			line.Quantity,
+			line.UnitPrice,
+			line.Amount,
+		).Scan(&line.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create invoice line: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *InvoiceRepository) GetInvoice(id uuid.UUID) (*domain.Invoice, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE id = $1`
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		r.logger.Error("Failed to get invoice", zap.Error(err))
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	lineQuery := `
+		SELECT id, invoice_id, position, reservation_id, description, quantity, unit_price, amount
+		FROM invoice_lines
+		WHERE invoice_id = $1
+		ORDER BY position
+	`
+	rows, err := r.db.QueryContext(ctx, lineQuery, id)
+	if err != nil {
+		r.logger.Error("Failed to list invoice lines", zap.Error(err))
+		return nil, fmt.Errorf("failed to list invoice lines: %w", err)
+	}
+	defer rows.Close()
+
+	invoice.Lines = []*domain.InvoiceLine{}
+	for rows.Next() {
+		var line domain.InvoiceLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.InvoiceID,
+			&line.Position,
+			&line.ReservationID,
+			&line.Description,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.Amount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice line: %w", err)
+		}
+		invoice.Lines = append(invoice.Lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list invoice lines: %w", err)
+	}
+
+	return invoice, nil
+}
+
+func (r *InvoiceRepository) ListInvoices(req domain.ListInvoicesRequest) ([]*domain.Invoice, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.CompanyID != nil {
+		args = append(args, *req.CompanyID)
+		conditions = append(conditions, fmt.Sprintf("company_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM invoices ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count invoices", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM invoices
+		%s
+		ORDER BY issued_at DESC, number DESC
+		LIMIT $%d OFFSET $%d
+	`, invoiceColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list invoices", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []*domain.Invoice{}
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, total, rows.Err()
+}
+
+func (r *InvoiceRepository) MarkPaid(id, paymentID uuid.UUID, paidAt time.Time) (*domain.Invoice, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE invoices
+		SET status = 'PAID', payment_id = $2, paid_at = $3
+		WHERE id = $1 AND status IN ('ISSUED', 'OVERDUE')
+		RETURNING ` + invoiceColumns
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, id, paymentID, paidAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		r.logger.Error("Failed to mark invoice as paid", zap.Error(err))
+		return nil, fmt.Errorf("failed to mark invoice as paid: %w", err)
+	}
+
+	return invoice, nil
+}
+
+func (r *InvoiceRepository) Void(id uuid.UUID, reason string, voidedAt time.Time) (*domain.Invoice, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE invoices
+		SET status = 'VOID', void_reason = $2, voided_at = $3
+		WHERE id = $1 AND status IN ('ISSUED', 'OVERDUE')
+		RETURNING ` + invoiceColumns
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, id, reason, voidedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		r.logger.Error("Failed to void invoice", zap.Error(err))
+		return nil, fmt.Errorf("failed to void invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+func (r *InvoiceRepository) MarkOverdue(today time.Time) (int, error) {
+	ctx := context.Background()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE invoices
+		SET status = 'OVERDUE'
+		WHERE status = 'ISSUED' AND due_date < $1::date
+	`, today.Format("2006-01-02"))
+	if err != nil {
+		r.logger.Error("Failed to mark overdue invoices", zap.Error(err))
+		return 0, fmt.Errorf("failed to mark overdue invoices: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count overdue invoices: %w", err)
+	}
+	return int(affected), nil
+}
+
+func scanBillingAccount(row rowScanner) (*domain.BillingAccount, error) {
+	var account domain.BillingAccount
+	err := row.Scan(
+		&account.ID,
+		&account.CompanyID,
+		&account.Currency,
+		&account.TaxRate,
+		&account.PaymentTermsDays,
+		&account.BillingEmail,
+		&account.Active,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func scanBillingPeriod(row rowScanner) (*domain.BillingPeriod, error) {
+	var period domain.BillingPeriod
+	var status string
+	err := row.Scan(
+		&period.ID,
+		&period.AccountID,
+		&period.PeriodStart,
+		&period.PeriodEnd,
+		&status,
+		&period.InvoiceID,
+		&period.ClosedAt,
+		&period.CreatedAt,
+		&period.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	period.Status = domain.BillingPeriodStatus(status)
+	return &period, nil
+}
+
+func scanInvoice(row rowScanner) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	var status string
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.Number,
+		&invoice.AccountID,
+		&invoice.CompanyID,
+		&invoice.PeriodStart,
+		&invoice.PeriodEnd,
+		&status,
+		&invoice.Currency,
+		&invoice.Subtotal,
+		&invoice.TaxRate,
+		&invoice.TaxAmount,
+		&invoice.Total,
+		&invoice.IssuedAt,
+		&invoice.DueDate,
+		&invoice.PaidAt,
+		&invoice.PaymentID,
+		&invoice.VoidedAt,
+		&invoice.VoidReason,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Status = domain.InvoiceStatus(status)
+	return &invoice, nil
+}
@@ -0,0 +1,538 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const maintenanceRecordColumns = `
+	id, vehicle_id, plan_id, service_type, status, scheduled_start, scheduled_end,
+	performed_at, odometer, cost, currency, workshop, description, created_by,
+	created_at, updated_at
+`
+
+const maintenancePlanColumns = `
+	id, vehicle_id, service_type, interval_km, interval_days, last_service_at,
+	last_service_odometer, active, notes, reminder_sent_at, created_by, created_at, updated_at
+`
+
+type MaintenanceRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewMaintenanceRepository(db *sql.DB, logger *zap.Logger) *MaintenanceRepository {
+	return &MaintenanceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *MaintenanceRepository) CreateRecord(record *domain.MaintenanceRecord) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO maintenance_records (
+			vehicle_id, plan_id, service_type, status, scheduled_start, scheduled_end,
+			performed_at, odometer, cost, currency, workshop, description, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		record.VehicleID,
+		record.PlanID,
+		string(record.ServiceType),
+		string(record.Status),
+		record.ScheduledStart,
+		record.ScheduledEnd,
+		record.PerformedAt,
+		record.Odometer,
+		record.Cost,
+		record.Currency,
+		record.Workshop,
+		record.Description,
+		record.CreatedBy,
+	).Scan(&record.ID, &record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create maintenance record", zap.Error(err))
+		return fmt.Errorf("failed to create maintenance record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceRepository) GetRecord(id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + maintenanceRecordColumns + ` FROM maintenance_records WHERE id = $1`
+
+	record, err := scanMaintenanceRecord(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMaintenanceRecordNotFound
+		}
+		r.logger.Error("Failed to get maintenance record", zap.Error(err))
+		return nil, fmt.Errorf("failed to get maintenance record: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *MaintenanceRepository) UpdateRecord(record *domain.MaintenanceRecord) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE maintenance_records
+		SET status = $2, scheduled_start = $3, scheduled_end = $4, performed_at = $5,
+			odometer = $6, cost = $7, workshop = $8, description = $9
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		record.ID,
+		string(record.Status),
+		record.ScheduledStart,
+		record.ScheduledEnd,
+		record.PerformedAt,
+		record.Odometer,
+		record.Cost,
+		record.Workshop,
+		record.Description,
+	).Scan(&record.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrMaintenanceRecordNotFound
+		}
+		r.logger.Error("Failed to update maintenance record", zap.Error(err))
+		return fmt.Errorf("failed to update maintenance record: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecords filters by the performed date, or the scheduled start for
+// records not yet performed.
+func (r *MaintenanceRepository) ListRecords(req domain.ListMaintenanceRecordsRequest) ([]*domain.MaintenanceRecord, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.VehicleID != nil {
+		args = append(args, *req.VehicleID)
+		conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", len(args)))
+	}
+	if req.PlanID != nil {
+		args = append(args, *req.PlanID)
+		conditions = append(conditions, fmt.Sprintf("plan_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if req.ServiceType != nil {
+		args = append(args, string(*req.ServiceType))
+		conditions = append(conditions, fmt.Sprintf("service_type = $%d", len(args)))
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		conditions = append(conditions, fmt.Sprintf("COALESCE(performed_at, scheduled_start) >= $%d", len(args)))
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		conditions = append(conditions, fmt.Sprintf("COALESCE(performed_at, scheduled_start) < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM maintenance_records ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count maintenance records", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count maintenance records: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM maintenance_records
+		%s
+		ORDER BY COALESCE(performed_at, scheduled_start) DESC, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, maintenanceRecordColumns, where, len(args)-1, len(args))
+
+	records, err := r.queryRecords(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
+
+// ListWindowsToStart returns scheduled windows that have opened and not yet
+// closed.
+func (r *MaintenanceRepository) ListWindowsToStart(now time.Time) ([]*domain.MaintenanceRecord, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + maintenanceRecordColumns + ` FROM maintenance_records
+		WHERE status = 'SCHEDULED' AND scheduled_start <= $1 AND scheduled_end > $1
+		ORDER BY scheduled_start ASC
+	`
+
+	return r.queryRecords(ctx, query, now)
+}
+
+// ListWindowsToEnd returns windows still in progress whose end has passed.
+func (r *MaintenanceRepository) ListWindowsToEnd(now time.Time) ([]*domain.MaintenanceRecord, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + maintenanceRecordColumns + ` FROM maintenance_records
+		WHERE status = 'IN_PROGRESS' AND scheduled_end <= $1
+		ORDER BY scheduled_end ASC
+	`
+
+	return r.queryRecords(ctx, query, now)
+}
+
+// HasActiveWindow reports whether a scheduled or in-progress window of the
+// vehicle covers at.
+func (r *MaintenanceRepository) HasActiveWindow(vehicleID string, at time.Time, excludeID *uuid.UUID) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM maintenance_records
+			WHERE vehicle_id = $1 AND status IN ('SCHEDULED', 'IN_PROGRESS')
+				AND scheduled_start <= $2 AND scheduled_end > $2
+				AND ($3::uuid IS NULL OR id <> $3)
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, vehicleID, at, excludeID).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check maintenance window", zap.Error(err))
+		return false, fmt.Errorf("failed to check maintenance window: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CostSummary totals completed maintenance per vehicle and service type,
+// optionally for a single vehicle and a period of performed dates.
+func (r *MaintenanceRepository) CostSummary(vehicleID *string, from, to *time.Time) (map[string][]domain.MaintenanceCostLine, error) {
+	ctx := context.Background()
+
+	conditions := []string{"status = 'COMPLETED'"}
+	var args []interface{}
+
+	if vehicleID != nil {
+		args = append(args, *vehicleID)
+		conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", len(args)))
+	}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("performed_at >= $%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("performed_at < $%d", len(args)))
+	}
+
+	query := `
+		SELECT vehicle_id, service_type, COUNT(*), COALESCE(SUM(cost), 0)
+		FROM maintenance_records
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY vehicle_id, service_type
+		ORDER BY vehicle_id, service_type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to summarize maintenance costs", zap.Error(err))
+		return nil, fmt.Errorf("failed to summarize maintenance costs: %w", err)
+	}
+	defer rows.Close()
+
+	summary := make(map[string][]domain.MaintenanceCostLine)
+	for rows.Next() {
+		var id, serviceType string
+		var line domain.MaintenanceCostLine
+		if err := rows.Scan(&id, &serviceType, &line.Count, &line.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance cost: %w", err)
+		}
+		line.ServiceType = domain.MaintenanceServiceType(serviceType)
+		summary[id] = append(summary[id], line)
+	}
+
+	return summary, rows.Err()
+}
+
+// LatestOdometer returns the highest odometer reading known for the vehicle,
+// from maintenance records, trip readings and fuel stops, or nil if none has
+// been recorded.
+func (r *MaintenanceRepository) LatestOdometer(vehicleID string) (*int, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT MAX(km) FROM (
+			SELECT odometer AS km FROM maintenance_records WHERE vehicle_id = $1 AND status <> 'CANCELLED'
+			UNION ALL
+			SELECT reading_km FROM odometer_readings WHERE vehicle_id = $1
+			UNION ALL
+			SELECT odometer_km FROM fuel_events WHERE vehicle_id = $1
+		) readings
+	`
+
+	var odometer sql.NullInt64
+	if err := r.db.QueryRowContext(ctx, query, vehicleID).Scan(&odometer); err != nil {
+		r.logger.Error("Failed to get latest odometer", zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest odometer: %w", err)
+	}
+
+	if !odometer.Valid {
+		return nil, nil
+	}
+	value := int(odometer.Int64)
+	return &value, nil
+}
+
+func (r *MaintenanceRepository) CreatePlan(plan *domain.MaintenancePlan) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO maintenance_plans (
+			vehicle_id, service_type, interval_km, interval_days, last_service_at,
+			last_service_odometer, active, notes, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		plan.VehicleID,
+		string(plan.ServiceType),
+		plan.IntervalKm,
+		plan.IntervalDays,
+		plan.LastServiceAt,
+		plan.LastServiceOdometer,
+		plan.Active,
+		plan.Notes,
+		plan.CreatedBy,
+	).Scan(&plan.ID, &plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create maintenance plan", zap.Error(err))
+		return fmt.Errorf("failed to create maintenance plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceRepository) GetPlan(id uuid.UUID) (*domain.MaintenancePlan, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + maintenancePlanColumns + ` FROM maintenance_plans WHERE id = $1`
+
+	plan, err := scanMaintenancePlan(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMaintenancePlanNotFound
+		}
+		r.logger.Error("Failed to get maintenance plan", zap.Error(err))
+		return nil, fmt.Errorf("failed to get maintenance plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (r *MaintenanceRepository) UpdatePlan(plan *domain.MaintenancePlan) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE maintenance_plans
+		SET interval_km = $2, interval_days = $3, active = $4, notes = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		plan.ID,
+		plan.IntervalKm,
+		plan.IntervalDays,
+		plan.Active,
+		plan.Notes,
+	).Scan(&plan.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrMaintenancePlanNotFound
+		}
+		r.logger.Error("Failed to update maintenance plan", zap.Error(err))
+		return fmt.Errorf("failed to update maintenance plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceRepository) ListPlans(vehicleID *string, activeOnly bool) ([]*domain.MaintenancePlan, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if vehicleID != nil {
+		args = append(args, *vehicleID)
+		conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", len(args)))
+	}
+	if activeOnly {
+		conditions = append(conditions, "active")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `SELECT ` + maintenancePlanColumns + ` FROM maintenance_plans ` + where + ` ORDER BY vehicle_id, service_type`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list maintenance plans", zap.Error(err))
+		return nil, fmt.Errorf("failed to list maintenance plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*domain.MaintenancePlan{}
+	for rows.Next() {
+		plan, err := scanMaintenancePlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	return plans, rows.Err()
+}
+
+// RecordPlanService restarts the plan's intervals from a completed service
+// and re-arms its reminder. Older services never move the plan backwards.
+func (r *MaintenanceRepository) RecordPlanService(planID uuid.UUID, performedAt time.Time, odometer *int) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE maintenance_plans
+		SET last_service_at = $2,
+			last_service_odometer = COALESCE($3, last_service_odometer),
+			reminder_sent_at = NULL
+		WHERE id = $1 AND last_service_at <= $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, planID, performedAt, odometer); err != nil {
+		r.logger.Error("Failed to record plan service", zap.Error(err))
+		return fmt.Errorf("failed to record plan service: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceRepository) MarkPlanReminded(planID uuid.UUID, at time.Time) error {
+	ctx := context.Background()
+
+	query := `UPDATE maintenance_plans SET reminder_sent_at = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, planID, at); err != nil {
+		r.logger.Error("Failed to mark plan reminded", zap.Error(err))
+		return fmt.Errorf("failed to mark plan reminded: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceRepository) queryRecords(ctx context.Context, query string, args ...interface{}) ([]*domain.MaintenanceRecord, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list maintenance records", zap.Error(err))
+		return nil, fmt.Errorf("failed to list maintenance records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*domain.MaintenanceRecord{}
+	for rows.Next() {
+		record, err := scanMaintenanceRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+func scanMaintenanceRecord(row rowScanner) (*domain.MaintenanceRecord, error) {
+	var record domain.MaintenanceRecord
+	var serviceType, status string
+	err := row.Scan(
+		&record.ID,
+		&record.VehicleID,
+		&record.PlanID,
+		&serviceType,
+		&status,
+		&record.ScheduledStart,
+		&record.ScheduledEnd,
+		&record.PerformedAt,
+		&record.Odometer,
+		&record.Cost,
+		&record.Currency,
+		&record.Workshop,
+		&record.Description,
+		&record.CreatedBy,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	record.ServiceType = domain.MaintenanceServiceType(serviceType)
+	record.Status = domain.MaintenanceStatus(status)
+	return &record, nil
+}
+
+func scanMaintenancePlan(row rowScanner) (*domain.MaintenancePlan, error) {
+	var plan domain.MaintenancePlan
+	var serviceType string
+	err := row.Scan(
+		&plan.ID,
+		&plan.VehicleID,
+		&serviceType,
+		&plan.IntervalKm,
+		&plan.IntervalDays,
+		&plan.LastServiceAt,
+		&plan.LastServiceOdometer,
+		&plan.Active,
+		&plan.Notes,
+		&plan.ReminderSentAt,
+		&plan.CreatedBy,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.ServiceType = domain.MaintenanceServiceType(serviceType)
+	return &plan, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type PaymentMethodRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewPaymentMethodRepository(db *sql.DB, logger *zap.Logger) *PaymentMethodRepository {
+	return &PaymentMethodRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PaymentMethodRepository) ListByCompany(companyID uuid.UUID) ([]domain.CompanyPaymentMethod, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT company_id, gateway, currency, enabled, updated_at
+		FROM company_payment_methods
+		WHERE company_id = $1
+		ORDER BY currency, gateway
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, companyID)
+	if err != nil {
+		r.logger.Error("Failed to list company payment methods", zap.Error(err))
+		return nil, fmt.Errorf("failed to list company payment methods: %w", err)
+	}
+	defer rows.Close()
+
+	methods := []domain.CompanyPaymentMethod{}
+	for rows.Next() {
+		var method domain.CompanyPaymentMethod
+		var gateway string
+		if err := rows.Scan(&method.CompanyID, &gateway, &method.Currency, &method.Enabled, &method.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan company payment method: %w", err)
+		}
+		method.Gateway = domain.PaymentGateway(gateway)
+		methods = append(methods, method)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list company payment methods: %w", err)
+	}
+
+	return methods, nil
+}
+
+func (r *PaymentMethodRepository) ReplaceForCompany(companyID uuid.UUID, methods []domain.CompanyPaymentMethod) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM companies WHERE id = $1)`, companyID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check company: %w", err)
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM company_payment_methods WHERE company_id = $1`, companyID); err != nil {
+		r.logger.Error("Failed to clear company payment methods", zap.Error(err))
+		return fmt.Errorf("failed to clear company payment methods: %w", err)
+	}
+
+	query := `
+		INSERT INTO company_payment_methods (company_id, gateway, currency, enabled)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_id, gateway, currency) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	for _, method := range methods {
+		if _, err := tx.ExecContext(ctx, query, companyID, string(method.Gateway), method.Currency, method.Enabled); err != nil {
+			r.logger.Error("Failed to save company payment method", zap.Error(err))
+			return fmt.Errorf("failed to save company payment method: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit company payment methods: %w", err)
+	}
+
+	return nil
+}
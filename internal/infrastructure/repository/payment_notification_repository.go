@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type PaymentNotificationRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewPaymentNotificationRepository(db *sql.DB, logger *zap.Logger) *PaymentNotificationRepository {
+	return &PaymentNotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PaymentNotificationRepository) Create(notification *domain.PaymentNotification) error {
+	ctx := context.Background()
+
+	payload, err := marshalNotificationPayload(notification.Payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO payment_notifications (
+			gateway, event_id, event_type, status, payment_id, transaction_ref, payload, error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (gateway, event_id) DO NOTHING
+		RETURNING id, received_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		string(notification.Gateway),
+		notification.EventID,
+		notification.EventType,
+		string(notification.Status),
+		notification.PaymentID,
+		notification.TransactionRef,
+		payload,
+		notification.Error,
+	).Scan(&notification.ID, &notification.ReceivedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create payment notification", zap.Error(err))
+		return fmt.Errorf("failed to create payment notification: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PaymentNotificationRepository) Update(notification *domain.PaymentNotification) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE payment_notifications
+		SET status = $2, payment_id = $3, error = $4, processed_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		notification.ID,
+		string(notification.Status),
+		notification.PaymentID,
+		notification.Error,
+		notification.ProcessedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update payment notification", zap.Error(err))
+		return fmt.Errorf("failed to update payment notification: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func marshalNotificationPayload(payload map[string]interface{}) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification payload: %w", err)
+	}
+	return encoded, nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -34,19 +37,37 @@ func (r *PaymentRepository) Create(payment *domain.Payment) error {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	var amount pgtype.Numeric
-	amount.Valid = true
-	_ = amount.Scan(payment.Amount)
+	amount := pgtype.Numeric{
+		Int:   big.NewInt(int64(math.Round(payment.Amount * 100))), // Convert to cents to preserve precision
+		Exp:   -2,
+		Valid: true,
+	}
+
+	// Invoice payments and wallet top-ups have no reservation
+	var reservationID *string
+	if payment.ReservationID != "" {
+		reservationID = &payment.ReservationID
+	}
+	var invoiceID pgtype.UUID
+	if payment.InvoiceID != nil {
+		invoiceID = pgtype.UUID{Bytes: *payment.InvoiceID, Valid: true}
+	}
+	var walletID pgtype.UUID
+	if payment.WalletID != nil {
+		walletID = pgtype.UUID{Bytes: *payment.WalletID, Valid: true}
+	}
 
 	dbPayment, err := r.queries.CreatePayment(ctx, sqlc.CreatePaymentParams{
 		ID:             pgtype.UUID{Bytes: payment.ID, Valid: true},
-		ReservationID:  payment.ReservationID,
+		ReservationID:  reservationID,
 		Gateway:        sqlc.PaymentGateway(payment.Gateway),
 		Amount:         amount,
 		Currency:       payment.Currency,
 		Status:         sqlc.PaymentStatus(payment.Status),
 		TransactionRef: payment.TransactionRef,
 		Payload:        payloadJSON,
+		InvoiceID:      invoiceID,
+		WalletID:       walletID,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create payment: %w", err)
@@ -75,7 +96,7 @@ func (r *PaymentRepository) GetByID(id uuid.UUID) (*domain.Payment, error) {
 func (r *PaymentRepository) GetByReservationID(reservationID string) ([]*domain.Payment, error) {
 	ctx := context.Background()
 
-	dbPayments, err := r.queries.GetPaymentsByReservationID(ctx, reservationID)
+	dbPayments, err := r.queries.GetPaymentsByReservationID(ctx, &reservationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payments by reservation ID: %w", err)
 	}
@@ -88,9 +109,119 @@ func (r *PaymentRepository) GetByReservationID(reservationID string) ([]*domain.
 	return payments, nil
 }
 
+func (r *PaymentRepository) GetByTransactionRef(transactionRef string) (*domain.Payment, error) {
+	ctx := context.Background()
+
+	dbPayment, err := r.queries.GetPaymentByTransactionRef(ctx, &transactionRef)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment by transaction ref: %w", err)
+	}
+
+	return r.mapToDomainPayment(dbPayment), nil
+}
+
+// Update sets the status and, when given, the transaction reference and
+// payload; nil values keep what is stored.
 func (r *PaymentRepository) Update(id uuid.UUID, status domain.PaymentStatus, transactionRef *string, payload map[string]interface{}) (*domain.Payment, error) {
-	// TODO: Implement when UpdatePayment query is properly generated by SQLC
-	return nil, fmt.Errorf("update payment not yet implemented")
+	ctx := context.Background()
+
+	var payloadJSON []byte
+	if payload != nil {
+		var err error
+		payloadJSON, err = json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	dbPayment, err := r.queries.UpdatePayment(ctx, sqlc.UpdatePaymentParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		Status:         sqlc.PaymentStatus(status),
+		TransactionRef: transactionRef,
+		Payload:        payloadJSON,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	return r.mapToDomainPayment(dbPayment), nil
+}
+
+const paymentColumns = `id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, created_at, invoice_id, wallet_id`
+
+func (r *PaymentRepository) ListPending(createdBefore time.Time, limit int) ([]*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = 'PENDING' AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	payments, err := r.list(query, createdBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending payments: %w", err)
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) ListCreatedBetween(from, to time.Time, gateway *domain.PaymentGateway) ([]*domain.Payment, error) {
+	var gatewayFilter *string
+	if gateway != nil {
+		value := string(*gateway)
+		gatewayFilter = &value
+	}
+
+	query := `SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3::text IS NULL OR gateway::text = $3)
+		ORDER BY created_at
+	`
+
+	payments, err := r.list(query, from, to, gatewayFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments by creation date: %w", err)
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) list(query string, args ...interface{}) ([]*domain.Payment, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*domain.Payment{}
+	for rows.Next() {
+		var dbPayment sqlc.Payment
+		if err := rows.Scan(
+			&dbPayment.ID,
+			&dbPayment.ReservationID,
+			&dbPayment.Gateway,
+			&dbPayment.Amount,
+			&dbPayment.Currency,
+			&dbPayment.Status,
+			&dbPayment.TransactionRef,
+			&dbPayment.Payload,
+			&dbPayment.CreatedAt,
+			&dbPayment.InvoiceID,
+			&dbPayment.WalletID,
+		); err != nil {
+			return nil, err
+		}
+		payments = append(payments, r.mapToDomainPayment(dbPayment))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
 }
 
 func (r *PaymentRepository) mapToDomainPayment(dbPayment sqlc.Payment) *domain.Payment {
@@ -99,7 +230,6 @@ func (r *PaymentRepository) mapToDomainPayment(dbPayment sqlc.Payment) *domain.P
 
 	payment := &domain.Payment{
 		ID:             paymentID,
-		ReservationID:  dbPayment.ReservationID,
 		Gateway:        domain.PaymentGateway(dbPayment.Gateway),
 		Currency:       dbPayment.Currency,
 		Status:         domain.PaymentStatus(dbPayment.Status),
@@ -107,10 +237,20 @@ func (r *PaymentRepository) mapToDomainPayment(dbPayment sqlc.Payment) *domain.P
 		CreatedAt:      dbPayment.CreatedAt.Time,
 	}
 
-	if dbPayment.Amount.Valid {
-		// Convert pgtype.Numeric to float64 - simplified approach
-		// For now, we'll use a basic conversion
-		payment.Amount = 0.0 // TODO: Implement proper numeric conversion
+	if dbPayment.ReservationID != nil {
+		payment.ReservationID = *dbPayment.ReservationID
+	}
+	if dbPayment.InvoiceID.Valid {
+		invoiceID := uuid.UUID(dbPayment.InvoiceID.Bytes)
+		payment.InvoiceID = &invoiceID
+	}
+	if dbPayment.WalletID.Valid {
+		walletID := uuid.UUID(dbPayment.WalletID.Bytes)
+		payment.WalletID = &walletID
+	}
+
+	if amount, err := dbPayment.Amount.Float64Value(); err == nil && amount.Valid {
+		payment.Amount = amount.Float64
 	}
 
 	if len(dbPayment.Payload) > 0 {
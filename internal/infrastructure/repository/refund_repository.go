@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const refundColumns = `
+	id, payment_id, amount, currency, reason, status, transaction_ref, payload,
+	failure_reason, requested_by, created_at, updated_at
+`
+
+type RefundRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewRefundRepository(db *sql.DB, logger *zap.Logger) *RefundRepository {
+	return &RefundRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create locks the payment while it adds up its refunds, so concurrent
+// refunds cannot together exceed the captured amount.
+func (r *RefundRepository) Create(refund *domain.Refund, capturedAmount float64) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM payments WHERE id = $1 FOR UPDATE`, refund.PaymentID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrPaymentNotFound
+		}
+		return fmt.Errorf("failed to lock payment: %w", err)
+	}
+
+	var reserved float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::float8
+		FROM refunds
+		WHERE payment_id = $1 AND status IN ('PENDING', 'COMPLETED')
+	`, refund.PaymentID).Scan(&reserved)
+	if err != nil {
+		return fmt.Errorf("failed to sum refunds: %w", err)
+	}
+
+	// Amounts are stored with two decimals
+	if math.Round((reserved+refund.Amount)*100) > math.Round(capturedAmount*100) {
+		return domain.ErrRefundExceedsPayment
+	}
+
+	payload, err := marshalRefundPayload(refund.Payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO refunds (
+			payment_id, amount, currency, reason, status, transaction_ref, payload,
+			failure_reason, requested_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		refund.PaymentID,
+		refund.Amount,
+		refund.Currency,
+		refund.Reason,
+		string(refund.Status),
+		refund.TransactionRef,
+		payload,
+		refund.FailureReason,
+		refund.RequestedBy,
+	).Scan(&refund.ID, &refund.CreatedAt, &refund.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create refund", zap.Error(err))
+		return fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit refund: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RefundRepository) Update(refund *domain.Refund) error {
+	ctx := context.Background()
+
+	payload, err := marshalRefundPayload(refund.Payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE refunds
+		SET status = $2, transaction_ref = $3, payload = $4, failure_reason = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		refund.ID,
+		string(refund.Status),
+		refund.TransactionRef,
+		payload,
+		refund.FailureReason,
+	).Scan(&refund.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		r.logger.Error("Failed to update refund", zap.Error(err))
+		return fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RefundRepository) ListByPayment(paymentID uuid.UUID) ([]*domain.Refund, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + refundColumns + `
+		FROM refunds
+		WHERE payment_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, paymentID)
+	if err != nil {
+		r.logger.Error("Failed to list payment refunds", zap.Error(err))
+		return nil, fmt.Errorf("failed to list payment refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []*domain.Refund{}
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan refund: %w", err)
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payment refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+func marshalRefundPayload(payload map[string]interface{}) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refund payload: %w", err)
+	}
+	return encoded, nil
+}
+
+func scanRefund(row rowScanner) (*domain.Refund, error) {
+	var refund domain.Refund
+	var status string
+	var payload []byte
+	err := row.Scan(
+		&refund.ID,
+		&refund.PaymentID,
+		&refund.Amount,
+		&refund.Currency,
+		&refund.Reason,
+		&status,
+		&refund.TransactionRef,
+		&payload,
+		&refund.FailureReason,
+		&refund.RequestedBy,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	refund.Status = domain.RefundStatus(status)
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &refund.Payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal refund payload: %w", err)
+		}
+	}
+	return &refund, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"time"
 
@@ -43,6 +44,12 @@ func (r *ReservationRepository) Create(reservation *domain.Reservation) error {
 		}
 	}
 
+	var commission, driverPayout pgtype.Numeric
+	if reservation.Commission != nil && reservation.DriverPayout != nil {
+		commission = pgtype.Numeric{Int: big.NewInt(int64(math.Round(*reservation.Commission * 100))), Exp: -2, Valid: true}
+		driverPayout = pgtype.Numeric{Int: big.NewInt(int64(math.Round(*reservation.DriverPayout * 100))), Exp: -2, Valid: true}
+	}
+
 	var userID pgtype.UUID
 	if reservation.UserID != nil {
 		userID = pgtype.UUID{Bytes: *reservation.UserID, Valid: true}
@@ -62,18 +69,28 @@ func (r *ReservationRepository) Create(reservation *domain.Reservation) error {
 		}
 	}
 
+	var vehicleType sqlc.NullVehicleType
+	if reservation.VehicleType != nil {
+		vehicleType = sqlc.NullVehicleType{VehicleType: sqlc.VehicleType(*reservation.VehicleType), Valid: true}
+	}
+
 	dbReservation, err := r.queries.CreateReservation(ctx, sqlc.CreateReservationParams{
-		ID:          reservation.ID,
-		UserID:      userID,
-		OrgID:       orgID,
-		Pickup:      reservation.Pickup,
-		Destination: reservation.Destination,
-		Datetime:    pgtype.Timestamptz{Time: reservation.DateTime, Valid: true},
-		Passengers:  int32(reservation.Passengers),
-		Status:      sqlc.ReservationStatus(reservation.Status),
-		Amount:      amount,
-		Notes:       reservation.Notes,
-		DistanceKm:  distanceKM,
+		ID:           reservation.ID,
+		UserID:       userID,
+		OrgID:        orgID,
+		Pickup:       reservation.Pickup,
+		Destination:  reservation.Destination,
+		Datetime:     pgtype.Timestamptz{Time: reservation.DateTime, Valid: true},
+		Passengers:   int32(reservation.Passengers),
+		Status:       sqlc.ReservationStatus(reservation.Status),
+		Amount:       amount,
+		Notes:        reservation.Notes,
+		DistanceKm:   distanceKM,
+		VehicleType:  vehicleType,
+		CostCenter:   reservation.CostCenter,
+		Commission:   commission,
+		DriverPayout: driverPayout,
+		Currency:     reservation.Currency,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create reservation: %w", err)
@@ -340,6 +357,12 @@ func (r *ReservationRepository) mapToDomainReservation(dbReservation sqlc.Reserv
 		reservation.Amount = &amount
 	}
 
+	if dbReservation.VehicleType.Valid {
+		vehicleType := domain.VehicleType(dbReservation.VehicleType.VehicleType)
+		reservation.VehicleType = &vehicleType
+	}
+	reservation.CostCenter = dbReservation.CostCenter
+
 	// Set assigned driver ID if present
 	if dbReservation.AssignedDriverID != nil {
 		reservation.AssignedDriverID = dbReservation.AssignedDriverID
@@ -383,6 +406,18 @@ func (r *ReservationRepository) mapToDomainReservationWithDriver(dbReservation s
 		reservation.Amount = &amount
 	}
 
+	if dbReservation.DistanceKm.Valid {
+		if distance, err := dbReservation.DistanceKm.Float64Value(); err == nil && distance.Valid {
+			reservation.DistanceKM = &distance.Float64
+		}
+	}
+
+	if dbReservation.VehicleType.Valid {
+		vehicleType := domain.VehicleType(dbReservation.VehicleType.VehicleType)
+		reservation.VehicleType = &vehicleType
+	}
+	reservation.CostCenter = dbReservation.CostCenter
+
 	// Set assigned driver ID if present
 	if dbReservation.AssignedDriverID != nil {
 		reservation.AssignedDriverID = dbReservation.AssignedDriverID
@@ -434,3 +469,54 @@ func (r *ReservationRepository) AssignDriver(reservationID string, driverID stri
 
 	return nil
 }
+
+// UnassignDriver returns a reservation to the dispatch pool
+func (r *ReservationRepository) UnassignDriver(reservationID string) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE reservations
+		SET assigned_driver_id = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to unassign driver: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrReservationNotFound
+	}
+
+	return nil
+}
+
+// ListUpcomingIDsByDriver returns the open reservations of a driver from a
+// point in time on
+func (r *ReservationRepository) ListUpcomingIDsByDriver(driverID string, from time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id FROM reservations
+		WHERE assigned_driver_id = $1
+			AND status IN ('ACTIVA', 'PROGRAMADA')
+			AND datetime >= $2
+		ORDER BY datetime ASC`
+
+	rows, err := r.db.Query(ctx, query, driverID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upcoming reservations: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan upcoming reservation: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,539 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const shiftColumns = `
+	id, driver_id, vehicle_id, starts_at, ends_at, region, status, notes,
+	created_by, created_at, updated_at
+`
+
+const shiftChangeColumns = `
+	id, type, driver_id, shift_id, target_driver_id, target_shift_id, starts_at, ends_at,
+	reason, status, review_note, reviewed_by, reviewed_at, created_at, updated_at
+`
+
+type ShiftRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewShiftRepository(db *sql.DB, logger *zap.Logger) *ShiftRepository {
+	return &ShiftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *ShiftRepository) Create(shift *domain.Shift) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO shifts (driver_id, vehicle_id, starts_at, ends_at, region, status, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		shift.DriverID,
+		shift.VehicleID,
+		shift.StartsAt,
+		shift.EndsAt,
+		shift.Region,
+		string(shift.Status),
+		shift.Notes,
+		shift.CreatedBy,
+	).Scan(&shift.ID, &shift.CreatedAt, &shift.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create shift", zap.Error(err))
+		return fmt.Errorf("failed to create shift: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShiftRepository) GetByID(id uuid.UUID) (*domain.Shift, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + shiftColumns + ` FROM shifts WHERE id = $1`
+
+	shift, err := scanShift(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrShiftNotFound
+		}
+		r.logger.Error("Failed to get shift", zap.Error(err))
+		return nil, fmt.Errorf("failed to get shift: %w", err)
+	}
+
+	return shift, nil
+}
+
+func (r *ShiftRepository) Update(shift *domain.Shift) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE shifts
+		SET vehicle_id = $2, starts_at = $3, ends_at = $4, region = $5, status = $6, notes = $7
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		shift.ID,
+		shift.VehicleID,
+		shift.StartsAt,
+		shift.EndsAt,
+		shift.Region,
+		string(shift.Status),
+		shift.Notes,
+	).Scan(&shift.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrShiftNotFound
+		}
+		r.logger.Error("Failed to update shift", zap.Error(err))
+		return fmt.Errorf("failed to update shift: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShiftRepository) List(req domain.ListShiftsRequest) ([]*domain.Shift, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if req.VehicleID != nil {
+		args = append(args, *req.VehicleID)
+		conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		conditions = append(conditions, fmt.Sprintf("ends_at > $%d", len(args)))
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		conditions = append(conditions, fmt.Sprintf("starts_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM shifts ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count shifts", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count shifts: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM shifts
+		%s
+		ORDER BY starts_at ASC
+		LIMIT $%d OFFSET $%d
+	`, shiftColumns, where, len(args)-1, len(args))
+
+	shifts, err := r.queryShifts(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return shifts, total, nil
+}
+
+// ListByDriver returns every shift of a driver overlapping [from, to),
+// cancelled ones included so calendar clients can drop them.
+func (r *ShiftRepository) ListByDriver(driverID string, from, to time.Time) ([]*domain.Shift, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + shiftColumns + ` FROM shifts
+		WHERE driver_id = $1 AND ends_at > $2 AND starts_at < $3
+		ORDER BY starts_at ASC
+	`
+
+	return r.queryShifts(ctx, query, driverID, from, to)
+}
+
+// FindCovering returns the scheduled shift of the driver that contains at.
+func (r *ShiftRepository) FindCovering(driverID string, at time.Time) (*domain.Shift, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + shiftColumns + ` FROM shifts
+		WHERE driver_id = $1 AND status = 'SCHEDULED' AND starts_at <= $2 AND ends_at > $2
+		ORDER BY starts_at ASC
+		LIMIT 1
+	`
+
+	shift, err := scanShift(r.db.QueryRowContext(ctx, query, driverID, at))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrShiftNotFound
+		}
+		r.logger.Error("Failed to find covering shift", zap.Error(err))
+		return nil, fmt.Errorf("failed to find covering shift: %w", err)
+	}
+
+	return shift, nil
+}
+
+func (r *ShiftRepository) HasDriverOverlap(driverID string, startsAt, endsAt time.Time, excludeID *uuid.UUID) (bool, error) {
+	return r.hasOverlap("driver_id", driverID, startsAt, endsAt, excludeID)
+}
+
+func (r *ShiftRepository) HasVehicleOverlap(vehicleID string, startsAt, endsAt time.Time, excludeID *uuid.UUID) (bool, error) {
+	return r.hasOverlap("vehicle_id", vehicleID, startsAt, endsAt, excludeID)
+}
+
+func (r *ShiftRepository) hasOverlap(column, value string, startsAt, endsAt time.Time, excludeID *uuid.UUID) (bool, error) {
+	ctx := context.Background()
+
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM shifts
+			WHERE %s = $1 AND status = 'SCHEDULED'
+				AND starts_at < $3 AND ends_at > $2
+				AND ($4::uuid IS NULL OR id <> $4)
+		)
+	`, column)
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, value, startsAt, endsAt, excludeID).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check shift overlap", zap.String("column", column), zap.Error(err))
+		return false, fmt.Errorf("failed to check shift overlap: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CancelDriverShifts cancels the scheduled shifts of a driver overlapping
+// [from, to) and returns how many were cancelled.
+func (r *ShiftRepository) CancelDriverShifts(driverID string, from, to time.Time) (int, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE shifts SET status = 'CANCELLED'
+		WHERE driver_id = $1 AND status = 'SCHEDULED' AND starts_at < $3 AND ends_at > $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, driverID, from, to)
+	if err != nil {
+		r.logger.Error("Failed to cancel driver shifts", zap.Error(err))
+		return 0, fmt.Errorf("failed to cancel driver shifts: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *ShiftRepository) SwapDrivers(shiftID uuid.UUID, toDriverID string, exchangeShiftID *uuid.UUID, fromDriverID string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE shifts SET driver_id = $2 WHERE id = $1 AND status = 'SCHEDULED'`
+
+	reassign := func(id uuid.UUID, driverID string) error {
+		result, err := tx.ExecContext(ctx, query, id, driverID)
+		if err != nil {
+			r.logger.Error("Failed to reassign shift", zap.Error(err))
+			return fmt.Errorf("failed to reassign shift: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return domain.ErrShiftNotFound
+		}
+		return nil
+	}
+
+	if err := reassign(shiftID, toDriverID); err != nil {
+		return err
+	}
+	if exchangeShiftID != nil {
+		if err := reassign(*exchangeShiftID, fromDriverID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShiftRepository) CreateChange(change *domain.ShiftChangeRequest) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO shift_change_requests (type, driver_id, shift_id, target_driver_id, target_shift_id, starts_at, ends_at, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		string(change.Type),
+		change.DriverID,
+		change.ShiftID,
+		change.TargetDriverID,
+		change.TargetShiftID,
+		change.StartsAt,
+		change.EndsAt,
+		change.Reason,
+		string(change.Status),
+	).Scan(&change.ID, &change.CreatedAt, &change.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create shift request", zap.Error(err))
+		return fmt.Errorf("failed to create shift request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShiftRepository) GetChange(id uuid.UUID) (*domain.ShiftChangeRequest, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + shiftChangeColumns + ` FROM shift_change_requests WHERE id = $1`
+
+	change, err := scanShiftChange(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrShiftRequestNotFound
+		}
+		r.logger.Error("Failed to get shift request", zap.Error(err))
+		return nil, fmt.Errorf("failed to get shift request: %w", err)
+	}
+
+	return change, nil
+}
+
+func (r *ShiftRepository) ListChanges(req domain.ListShiftChangesRequest) ([]*domain.ShiftChangeRequest, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("(driver_id = $%d OR target_driver_id = $%d)", len(args), len(args)))
+	}
+	if req.Type != nil {
+		args = append(args, string(*req.Type))
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM shift_change_requests ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count shift requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count shift requests: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM shift_change_requests
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, shiftChangeColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list shift requests", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list shift requests: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*domain.ShiftChangeRequest{}
+	for rows.Next() {
+		change, err := scanShiftChange(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan shift request: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, total, rows.Err()
+}
+
+// ReviewChange approves or rejects a pending request. Returns
+// ErrShiftRequestAlreadyReviewed if the request is missing or no longer
+// pending.
+func (r *ShiftRepository) ReviewChange(id uuid.UUID, status domain.ShiftChangeStatus, note *string, reviewedBy uuid.UUID, reviewedAt time.Time) (*domain.ShiftChangeRequest, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE shift_change_requests
+		SET status = $2, review_note = $3, reviewed_by = $4, reviewed_at = $5
+		WHERE id = $1 AND status = 'PENDING'
+		RETURNING ` + shiftChangeColumns
+
+	change, err := scanShiftChange(r.db.QueryRowContext(ctx, query, id, string(status), note, reviewedBy, reviewedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrShiftRequestAlreadyReviewed
+		}
+		r.logger.Error("Failed to review shift request", zap.Error(err))
+		return nil, fmt.Errorf("failed to review shift request: %w", err)
+	}
+
+	return change, nil
+}
+
+// CancelChange withdraws a pending request raised by driverID.
+func (r *ShiftRepository) CancelChange(id uuid.UUID, driverID string) (*domain.ShiftChangeRequest, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE shift_change_requests SET status = 'CANCELLED'
+		WHERE id = $1 AND driver_id = $2 AND status = 'PENDING'
+		RETURNING ` + shiftChangeColumns
+
+	change, err := scanShiftChange(r.db.QueryRowContext(ctx, query, id, driverID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrShiftRequestAlreadyReviewed
+		}
+		r.logger.Error("Failed to cancel shift request", zap.Error(err))
+		return nil, fmt.Errorf("failed to cancel shift request: %w", err)
+	}
+
+	return change, nil
+}
+
+func (r *ShiftRepository) HasApprovedTimeOff(driverID string, startsAt, endsAt time.Time) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM shift_change_requests
+			WHERE driver_id = $1 AND type = 'TIME_OFF' AND status = 'APPROVED'
+				AND starts_at < $3 AND ends_at > $2
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, driverID, startsAt, endsAt).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check time off", zap.Error(err))
+		return false, fmt.Errorf("failed to check time off: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *ShiftRepository) queryShifts(ctx context.Context, query string, args ...interface{}) ([]*domain.Shift, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list shifts", zap.Error(err))
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+	defer rows.Close()
+
+	shifts := []*domain.Shift{}
+	for rows.Next() {
+		shift, err := scanShift(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shift: %w", err)
+		}
+		shifts = append(shifts, shift)
+	}
+
+	return shifts, rows.Err()
+}
+
+func scanShift(row rowScanner) (*domain.Shift, error) {
+	var shift domain.Shift
+	var status string
+	err := row.Scan(
+		&shift.ID,
+		&shift.DriverID,
+		&shift.VehicleID,
+		&shift.StartsAt,
+		&shift.EndsAt,
+		&shift.Region,
+		&status,
+		&shift.Notes,
+		&shift.CreatedBy,
+		&shift.CreatedAt,
+		&shift.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shift.Status = domain.ShiftStatus(status)
+	return &shift, nil
+}
+
+func scanShiftChange(row rowScanner) (*domain.ShiftChangeRequest, error) {
+	var change domain.ShiftChangeRequest
+	var changeType, status string
+	err := row.Scan(
+		&change.ID,
+		&changeType,
+		&change.DriverID,
+		&change.ShiftID,
+		&change.TargetDriverID,
+		&change.TargetShiftID,
+		&change.StartsAt,
+		&change.EndsAt,
+		&change.Reason,
+		&status,
+		&change.ReviewNote,
+		&change.ReviewedBy,
+		&change.ReviewedAt,
+		&change.CreatedAt,
+		&change.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	change.Type = domain.ShiftChangeType(changeType)
+	change.Status = domain.ShiftChangeStatus(status)
+	return &change, nil
+}
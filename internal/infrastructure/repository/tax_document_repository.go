@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const folioRangeColumns = `
+	id, doc_type, folio_from, folio_to, next_folio, authorized_at, caf, created_at
+`
+
+// Documents are read with the document they reference, if any
+const taxDocumentColumns = `
+	d.id, d.doc_type, d.folio, d.folio_range_id, d.issue_date, d.invoice_id, d.payment_id,
+	d.reference_id, d.reference_code, d.reference_reason, ref.doc_type, ref.folio, ref.issue_date,
+	d.receiver_rut, d.receiver_name, d.net_amount, d.exempt_amount, d.tax_rate, d.tax_amount,
+	d.total_amount, d.status, d.track_id, d.status_detail, d.submitted_at, d.ted,
+	d.created_at, d.updated_at
+`
+
+const taxDocumentFrom = `
+	FROM tax_documents d
+	LEFT JOIN tax_documents ref ON ref.id = d.reference_id
+`
+
+// TaxDocumentRepository stores the CAF folio ranges and the electronic tax
+// documents issued with them.
+type TaxDocumentRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewTaxDocumentRepository(db *sql.DB, logger *zap.Logger) *TaxDocumentRepository {
+	return &TaxDocumentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *TaxDocumentRepository) CreateFolioRange(folioRange *domain.FolioRange) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO dte_folio_ranges (doc_type, folio_from, folio_to, next_folio, authorized_at, caf)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE NOT EXISTS (
+			SELECT 1 FROM dte_folio_ranges
+			WHERE doc_type = $1 AND folio_from <= $3 AND folio_to >= $2
+		)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		int(folioRange.Type),
+		folioRange.From,
+		folioRange.To,
+		folioRange.Next,
+		folioRange.AuthorizedAt,
+		folioRange.CAF,
+	).Scan(&folioRange.ID, &folioRange.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create folio range", zap.Error(err))
+		return fmt.Errorf("failed to create folio range: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaxDocumentRepository) ListFolioRanges(docType *domain.DTEType) ([]*domain.FolioRange, error) {
+	ctx := context.Background()
+
+	var filter *int
+	if docType != nil {
+		value := int(*docType)
+		filter = &value
+	}
+
+	query := `SELECT ` + folioRangeColumns + `
+		FROM dte_folio_ranges
+		WHERE $1::smallint IS NULL OR doc_type = $1
+		ORDER BY doc_type, folio_from
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter)
+	if err != nil {
+		r.logger.Error("Failed to list folio ranges", zap.Error(err))
+		return nil, fmt.Errorf("failed to list folio ranges: %w", err)
+	}
+	defer rows.Close()
+
+	ranges := []*domain.FolioRange{}
+	for rows.Next() {
+		folioRange, err := scanFolioRange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan folio range: %w", err)
+		}
+		ranges = append(ranges, folioRange)
+	}
+
+	return ranges, rows.Err()
+}
+
+func (r *TaxDocumentRepository) Issue(doc *domain.TaxDocument, build func(doc *domain.TaxDocument, caf *domain.FolioRange) error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Concurrent issues of the type wait here, so folios are used in order
+	query := `SELECT ` + folioRangeColumns + `
+		FROM dte_folio_ranges
+		WHERE doc_type = $1 AND next_folio <= folio_to
+		ORDER BY folio_from
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	folioRange, err := scanFolioRange(tx.QueryRowContext(ctx, query, int(doc.Type)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrFoliosExhausted
+		}
+		r.logger.Error("Failed to get folio range", zap.Error(err))
+		return fmt.Errorf("failed to get folio range: %w", err)
+	}
+
+	doc.Folio = folioRange.Next
+	doc.FolioRangeID = folioRange.ID
+	doc.Status = domain.TaxDocumentStatusSigned
+	if err := build(doc, folioRange); err != nil {
+		return err
+	}
+
+	var referenceCode *int
+	var referenceReason *string
+	if doc.Reference != nil {
+		referenceCode = &doc.Reference.Code
+		referenceReason = &doc.Reference.Reason
+	}
+
+	insert := `
+		INSERT INTO tax_documents (
+			doc_type, folio, folio_range_id, issue_date, invoice_id, payment_id,
+			reference_id, reference_code, reference_reason, receiver_rut, receiver_name,
+			net_amount, exempt_amount, tax_rate, tax_amount, total_amount, status, ted, xml
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, insert,
+		int(doc.Type),
+		doc.Folio,
+		doc.FolioRangeID,
+		doc.IssueDate,
+		doc.InvoiceID,
+		doc.PaymentID,
+		doc.ReferenceID,
+		referenceCode,
+		referenceReason,
+		doc.ReceiverRUT,
+		doc.ReceiverName,
+		doc.NetAmount,
+		doc.ExemptAmount,
+		doc.TaxRate,
+		doc.TaxAmount,
+		doc.TotalAmount,
+		string(doc.Status),
+		doc.TED,
+		doc.XML,
+	).Scan(&doc.ID, &doc.CreatedAt, &doc.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create tax document", zap.Error(err))
+		return fmt.Errorf("failed to create tax document: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE dte_folio_ranges SET next_folio = next_folio + 1 WHERE id = $1`, folioRange.ID); err != nil {
+		r.logger.Error("Failed to take folio", zap.Error(err))
+		return fmt.Errorf("failed to take folio: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tax document: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaxDocumentRepository) GetByID(id uuid.UUID) (*domain.TaxDocument, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + taxDocumentColumns + `, d.xml ` + taxDocumentFrom + ` WHERE d.id = $1`
+
+	var xml []byte
+	doc, err := scanTaxDocument(r.db.QueryRowContext(ctx, query, id), &xml)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTaxDocumentNotFound
+		}
+		r.logger.Error("Failed to get tax document", zap.Error(err))
+		return nil, fmt.Errorf("failed to get tax document: %w", err)
+	}
+
+	doc.XML = xml
+	return doc, nil
+}
+
+func (r *TaxDocumentRepository) List(req domain.ListTaxDocumentsRequest) ([]*domain.TaxDocument, int, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.Type != nil {
+		args = append(args, int(*req.Type))
+		conditions = append(conditions, fmt.Sprintf("d.doc_type = $%d", len(args)))
+	}
+	if req.Status != nil {
+		args = append(args, string(*req.Status))
+		conditions = append(conditions, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	if req.InvoiceID != nil {
+		args = append(args, *req.InvoiceID)
+		conditions = append(conditions, fmt.Sprintf("d.invoice_id = $%d", len(args)))
+	}
+	if req.PaymentID != nil {
+		args = append(args, *req.PaymentID)
+		conditions = append(conditions, fmt.Sprintf("d.payment_id = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM tax_documents d ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count tax documents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count tax documents: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s %s
+		%s
+		ORDER BY d.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, taxDocumentColumns, taxDocumentFrom, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list tax documents", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list tax documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := []*domain.TaxDocument{}
+	for rows.Next() {
+		doc, err := scanTaxDocument(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan tax document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, total, rows.Err()
+}
+
+func (r *TaxDocumentRepository) CreditedAmount(id uuid.UUID) (int64, error) {
+	ctx := context.Background()
+
+	var credited int64
+	query := `SELECT COALESCE(SUM(total_amount), 0) FROM tax_documents WHERE reference_id = $1 AND doc_type = 61`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&credited); err != nil {
+		r.logger.Error("Failed to sum credit notes", zap.Error(err))
+		return 0, fmt.Errorf("failed to sum credit notes: %w", err)
+	}
+
+	return credited, nil
+}
+
+func (r *TaxDocumentRepository) ListByStatus(status domain.TaxDocumentStatus, limit int) ([]*domain.TaxDocument, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + taxDocumentColumns + `, d.xml ` + taxDocumentFrom + `
+		WHERE d.status = $1
+		ORDER BY d.created_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(status), limit)
+	if err != nil {
+		r.logger.Error("Failed to list tax documents by status", zap.Error(err))
+		return nil, fmt.Errorf("failed to list tax documents by status: %w", err)
+	}
+	defer rows.Close()
+
+	docs := []*domain.TaxDocument{}
+	for rows.Next() {
+		var xml []byte
+		doc, err := scanTaxDocument(rows, &xml)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tax document: %w", err)
+		}
+		doc.XML = xml
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+func (r *TaxDocumentRepository) MarkSubmitted(ids []uuid.UUID, trackID string, submittedAt time.Time) error {
+	ctx := context.Background()
+
+	args := []interface{}{trackID, submittedAt}
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	query := `
+		UPDATE tax_documents
+		SET status = 'SUBMITTED', track_id = $1, submitted_at = $2, status_detail = NULL
+		WHERE status = 'SIGNED' AND id IN (` + strings.Join(placeholders, ", ") + `)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		r.logger.Error("Failed to mark tax documents as submitted", zap.Error(err))
+		return fmt.Errorf("failed to mark tax documents as submitted: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaxDocumentRepository) UpdateStatus(trackID string, status domain.TaxDocumentStatus, detail string) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE tax_documents
+		SET status = $2, status_detail = NULLIF($3, '')
+		WHERE track_id = $1 AND status = 'SUBMITTED'
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, trackID, string(status), detail); err != nil {
+		r.logger.Error("Failed to update tax document status", zap.Error(err))
+		return fmt.Errorf("failed to update tax document status: %w", err)
+	}
+
+	return nil
+}
+
+func scanFolioRange(row rowScanner) (*domain.FolioRange, error) {
+	var folioRange domain.FolioRange
+	var docType int
+	err := row.Scan(
+		&folioRange.ID,
+		&docType,
+		&folioRange.From,
+		&folioRange.To,
+		&folioRange.Next,
+		&folioRange.AuthorizedAt,
+		&folioRange.CAF,
+		&folioRange.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	folioRange.Type = domain.DTEType(docType)
+	return &folioRange, nil
+}
+
+// scanTaxDocument scans taxDocumentColumns followed by extra columns.
+func scanTaxDocument(row rowScanner, extra ...interface{}) (*domain.TaxDocument, error) {
+	var doc domain.TaxDocument
+	var docType int
+	var status string
+	var referenceCode, referenceType, referenceFolio sql.NullInt64
+	var referenceReason sql.NullString
+	var referenceDate sql.NullTime
+
+	dest := []interface{}{
+		&doc.ID,
+		&docType,
+		&doc.Folio,
+		&doc.FolioRangeID,
+		&doc.IssueDate,
+		&doc.InvoiceID,
+		&doc.PaymentID,
+		&doc.ReferenceID,
+		&referenceCode,
+		&referenceReason,
+		&referenceType,
+		&referenceFolio,
+		&referenceDate,
+		&doc.ReceiverRUT,
+		&doc.ReceiverName,
+		&doc.NetAmount,
+		&doc.ExemptAmount,
+		&doc.TaxRate,
+		&doc.TaxAmount,
+		&doc.TotalAmount,
+		&status,
+		&doc.TrackID,
+		&doc.StatusDetail,
+		&doc.SubmittedAt,
+		&doc.TED,
+		&doc.CreatedAt,
+		&doc.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	doc.Type = domain.DTEType(docType)
+	doc.Status = domain.TaxDocumentStatus(status)
+	if doc.ReferenceID != nil {
+		doc.Reference = &domain.TaxDocumentReference{
+			Type:   domain.DTEType(referenceType.Int64),
+			Folio:  int(referenceFolio.Int64),
+			Date:   referenceDate.Time,
+			Code:   int(referenceCode.Int64),
+			Reason: referenceReason.String,
+		}
+	}
+	return &doc, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TrackingLinkRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewTrackingLinkRepository(db *sql.DB, logger *zap.Logger) *TrackingLinkRepository {
+	return &TrackingLinkRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *TrackingLinkRepository) Create(link *domain.TrackingLink) error {
+	ctx := context.Background()
+
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO tracking_links (id, reservation_id, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		link.ID,
+		link.ReservationID,
+		link.ExpiresAt,
+		link.CreatedBy,
+	).Scan(&link.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create tracking link", zap.Error(err))
+		return fmt.Errorf("failed to create tracking link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TrackingLinkRepository) GetByID(id uuid.UUID) (*domain.TrackingLink, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, reservation_id, expires_at, revoked_at, created_by, created_at
+		FROM tracking_links
+		WHERE id = $1
+	`
+
+	link, err := scanTrackingLink(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get tracking link", zap.Error(err))
+		return nil, fmt.Errorf("failed to get tracking link: %w", err)
+	}
+
+	return link, nil
+}
+
+func (r *TrackingLinkRepository) ListByReservation(reservationID string) ([]*domain.TrackingLink, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, reservation_id, expires_at, revoked_at, created_by, created_at
+		FROM tracking_links
+		WHERE reservation_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		r.logger.Error("Failed to list tracking links", zap.Error(err))
+		return nil, fmt.Errorf("failed to list tracking links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*domain.TrackingLink{}
+	for rows.Next() {
+		link, err := scanTrackingLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tracking link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func (r *TrackingLinkRepository) Revoke(id uuid.UUID, revokedAt time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE tracking_links
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, revokedAt)
+	if err != nil {
+		r.logger.Error("Failed to revoke tracking link", zap.Error(err))
+		return fmt.Errorf("failed to revoke tracking link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanTrackingLink(row rowScanner) (*domain.TrackingLink, error) {
+	var link domain.TrackingLink
+	err := row.Scan(
+		&link.ID,
+		&link.ReservationID,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.CreatedBy,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TripOfferRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewTripOfferRepository(db *sql.DB, logger *zap.Logger) *TripOfferRepository {
+	return &TripOfferRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const tripOfferColumns = `
+	o.id, o.reservation_id, o.driver_id, o.position, o.status, o.offered_at,
+	o.expires_at, o.responded_at, o.decline_reason, o.created_at, o.updated_at
+`
+
+func (r *TripOfferRepository) CreateBatch(offers []*domain.TripOffer) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO trip_offers (
+			id, reservation_id, driver_id, position, status, offered_at, expires_at,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	for _, offer := range offers {
+		if offer.ID == uuid.Nil {
+			offer.ID = uuid.New()
+		}
+		err := tx.QueryRowContext(ctx, query,
+			offer.ID,
+			offer.ReservationID,
+			offer.DriverID,
+			offer.Position,
+			string(offer.Status),
+			offer.OfferedAt,
+			offer.ExpiresAt,
+		).Scan(&offer.CreatedAt, &offer.UpdatedAt)
+		if err != nil {
+			r.logger.Error("Failed to create trip offer", zap.Error(err))
+			return fmt.Errorf("failed to create trip offer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip offers: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TripOfferRepository) GetByID(id uuid.UUID) (*domain.TripOffer, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + tripOfferColumns + ` FROM trip_offers o WHERE o.id = $1`
+
+	offer, err := scanTripOffer(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get trip offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to get trip offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+func (r *TripOfferRepository) ListByReservation(reservationID string) ([]*domain.TripOffer, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + tripOfferColumns + `
+		FROM trip_offers o
+		WHERE o.reservation_id = $1
+		ORDER BY o.created_at, o.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		r.logger.Error("Failed to list reservation offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list reservation offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []*domain.TripOffer{}
+	for rows.Next() {
+		offer, err := scanTripOffer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trip offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, rows.Err()
+}
+
+func (r *TripOfferRepository) ListByDriver(driverID string, status *domain.TripOfferStatus) ([]*domain.TripOffer, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + tripOfferColumns + `,
+			res.pickup, res.destination, res.datetime, res.passengers, res.status
+		FROM trip_offers o
+		INNER JOIN reservations res ON res.id = o.reservation_id
+		WHERE o.driver_id = $1
+		  AND o.status <> 'QUEUED'
+		  AND ($2::text IS NULL OR o.status = $2)
+		ORDER BY o.offered_at DESC
+	`
+
+	var statusFilter *string
+	if status != nil {
+		s := string(*status)
+		statusFilter = &s
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, driverID, statusFilter)
+	if err != nil {
+		r.logger.Error("Failed to list driver offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list driver offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []*domain.TripOffer{}
+	for rows.Next() {
+		var offer domain.TripOffer
+		var reservation domain.Reservation
+		err := rows.Scan(
+			&offer.ID,
+			&offer.ReservationID,
+			&offer.DriverID,
+			&offer.Position,
+			&offer.Status,
+			&offer.OfferedAt,
+			&offer.ExpiresAt,
+			&offer.RespondedAt,
+			&offer.DeclineReason,
+			&offer.CreatedAt,
+			&offer.UpdatedAt,
+			&reservation.Pickup,
+			&reservation.Destination,
+			&reservation.DateTime,
+			&reservation.Passengers,
+			&reservation.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan driver offer: %w", err)
+		}
+		reservation.ID = offer.ReservationID
+		offer.Reservation = &reservation
+		offers = append(offers, &offer)
+	}
+
+	return offers, rows.Err()
+}
+
+func (r *TripOfferRepository) ListExpired(now time.Time) ([]*domain.TripOffer, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + tripOfferColumns + `
+		FROM trip_offers o
+		WHERE o.status = 'PENDING' AND o.expires_at <= $1
+		ORDER BY o.expires_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		r.logger.Error("Failed to list expired offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expired offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []*domain.TripOffer{}
+	for rows.Next() {
+		offer, err := scanTripOffer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trip offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, rows.Err()
+}
+
+func (r *TripOfferRepository) GetNextQueued(reservationID string) (*domain.TripOffer, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + tripOfferColumns + `
+		FROM trip_offers o
+		WHERE o.reservation_id = $1 AND o.status = 'QUEUED'
+		ORDER BY o.position
+		LIMIT 1
+	`
+
+	offer, err := scanTripOffer(r.db.QueryRowContext(ctx, query, reservationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get next queued offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+func (r *TripOfferRepository) Activate(id uuid.UUID, offeredAt, expiresAt time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE trip_offers
+		SET status = 'PENDING', offered_at = $2, expires_at = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'QUEUED'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, offeredAt, expiresAt)
+	if err != nil {
+		r.logger.Error("Failed to activate trip offer", zap.Error(err))
+		return fmt.Errorf("failed to activate trip offer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Respond closes a PENDING offer. The status guard makes concurrent accept,
+// decline and expiry attempts race safely: only the first one wins.
+func (r *TripOfferRepository) Respond(id uuid.UUID, status domain.TripOfferStatus, respondedAt time.Time, reason *string) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE trip_offers
+		SET status = $2, responded_at = $3, decline_reason = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, string(status), respondedAt, reason)
+	if err != nil {
+		r.logger.Error("Failed to respond trip offer", zap.Error(err))
+		return fmt.Errorf("failed to respond trip offer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrOfferNotPending
+	}
+
+	return nil
+}
+
+// Accept closes a PENDING offer as accepted, assigns its driver to the
+// reservation and cancels the other open offers in one transaction, so an
+// offer is never accepted without its driver being assigned.
+func (r *TripOfferRepository) Accept(offer *domain.TripOffer, respondedAt time.Time) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE trip_offers
+		SET status = 'ACCEPTED', responded_at = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`, offer.ID, respondedAt)
+	if err != nil {
+		r.logger.Error("Failed to accept trip offer", zap.Error(err))
+		return fmt.Errorf("failed to accept trip offer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrOfferNotPending
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE reservations
+		SET assigned_driver_id = $1, updated_at = NOW()
+		WHERE id = $2
+	`, offer.DriverID, offer.ReservationID)
+	if err != nil {
+		r.logger.Error("Failed to assign driver for accepted offer", zap.Error(err))
+		return fmt.Errorf("failed to assign driver: %w", err)
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrReservationNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE trip_offers
+		SET status = 'CANCELLED', updated_at = NOW()
+		WHERE reservation_id = $1 AND id <> $2 AND status IN ('QUEUED', 'PENDING')
+	`, offer.ReservationID, offer.ID); err != nil {
+		r.logger.Error("Failed to cancel remaining offers", zap.Error(err))
+		return fmt.Errorf("failed to cancel remaining offers: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit offer acceptance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TripOfferRepository) CancelOpenByReservation(reservationID string) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE trip_offers
+		SET status = 'CANCELLED', updated_at = NOW()
+		WHERE reservation_id = $1 AND status IN ('QUEUED', 'PENDING')
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, reservationID); err != nil {
+		r.logger.Error("Failed to cancel open offers", zap.Error(err))
+		return fmt.Errorf("failed to cancel open offers: %w", err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTripOffer(row rowScanner) (*domain.TripOffer, error) {
+	var offer domain.TripOffer
+	err := row.Scan(
+		&offer.ID,
+		&offer.ReservationID,
+		&offer.DriverID,
+		&offer.Position,
+		&offer.Status,
+		&offer.OfferedAt,
+		&offer.ExpiresAt,
+		&offer.RespondedAt,
+		&offer.DeclineReason,
+		&offer.CreatedAt,
+		&offer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
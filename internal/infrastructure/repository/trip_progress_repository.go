@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TripProgressRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewTripProgressRepository(db *sql.DB, logger *zap.Logger) *TripProgressRepository {
+	return &TripProgressRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *TripProgressRepository) GetByReservationID(reservationID string) (*domain.TripProgress, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT reservation_id, driver_id, status, en_route_at, arrived_at, onboard_at,
+		       finished_at, no_show_at, arrived_on_time, created_at, updated_at
+		FROM trip_progress
+		WHERE reservation_id = $1
+	`
+
+	var progress domain.TripProgress
+	var status string
+	err := r.db.QueryRowContext(ctx, query, reservationID).Scan(
+		&progress.ReservationID,
+		&progress.DriverID,
+		&status,
+		&progress.EnRouteAt,
+		&progress.ArrivedAt,
+		&progress.OnboardAt,
+		&progress.FinishedAt,
+		&progress.NoShowAt,
+		&progress.ArrivedOnTime,
+		&progress.CreatedAt,
+		&progress.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get trip progress", zap.Error(err))
+		return nil, fmt.Errorf("failed to get trip progress: %w", err)
+	}
+
+	tripStatus := domain.DriverTripStatus(status)
+	progress.Status = &tripStatus
+
+	return &progress, nil
+}
+
+func (r *TripProgressRepository) Save(progress *domain.TripProgress) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO trip_progress (
+			reservation_id, driver_id, status, en_route_at, arrived_at, onboard_at,
+			finished_at, no_show_at, arrived_on_time, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		ON CONFLICT (reservation_id) DO UPDATE SET
+			driver_id = EXCLUDED.driver_id,
+			status = EXCLUDED.status,
+			en_route_at = EXCLUDED.en_route_at,
+			arrived_at = EXCLUDED.arrived_at,
+			onboard_at = EXCLUDED.onboard_at,
+			finished_at = EXCLUDED.finished_at,
+			no_show_at = EXCLUDED.no_show_at,
+			arrived_on_time = EXCLUDED.arrived_on_time,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		progress.ReservationID,
+		progress.DriverID,
+		string(*progress.Status),
+		progress.EnRouteAt,
+		progress.ArrivedAt,
+		progress.OnboardAt,
+		progress.FinishedAt,
+		progress.NoShowAt,
+		progress.ArrivedOnTime,
+	).Scan(&progress.CreatedAt, &progress.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to save trip progress", zap.Error(err))
+		return fmt.Errorf("failed to save trip progress: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByDriver returns the trip the driver is currently working on, if any.
+func (r *TripProgressRepository) GetActiveByDriver(driverID string) (*domain.TripProgress, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT reservation_id
+		FROM trip_progress
+		WHERE driver_id = $1 AND status IN ('EN_CAMINO', 'EN_ORIGEN', 'PASAJERO_A_BORDO')
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+
+	var reservationID string
+	err := r.db.QueryRowContext(ctx, query, driverID).Scan(&reservationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get active trip", zap.Error(err))
+		return nil, fmt.Errorf("failed to get active trip: %w", err)
+	}
+
+	return r.GetByReservationID(reservationID)
+}
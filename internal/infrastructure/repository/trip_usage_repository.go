@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const odometerReadingColumns = `
+	id, reservation_id, driver_id, vehicle_id, kind, reading_km, recorded_at, created_at
+`
+
+const fuelEventColumns = `
+	id, reservation_id, driver_id, vehicle_id, type, quantity, cost, currency,
+	odometer_km, station, occurred_at, created_at
+`
+
+type TripUsageRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewTripUsageRepository(db *sql.DB, logger *zap.Logger) *TripUsageRepository {
+	return &TripUsageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateReading stores a start or end reading. A trip has at most one of
+// each; a second one returns domain.ErrOdometerAlreadyRecorded.
+func (r *TripUsageRepository) CreateReading(reading *domain.OdometerReading) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO odometer_readings (reservation_id, driver_id, vehicle_id, kind, reading_km, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (reservation_id, kind) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		reading.ReservationID,
+		reading.DriverID,
+		reading.VehicleID,
+		string(reading.Kind),
+		reading.ReadingKm,
+		reading.RecordedAt,
+	).Scan(&reading.ID, &reading.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrOdometerAlreadyRecorded
+		}
+		r.logger.Error("Failed to create odometer reading", zap.Error(err))
+		return fmt.Errorf("failed to create odometer reading: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TripUsageRepository) ListReadings(reservationID string) ([]*domain.OdometerReading, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + odometerReadingColumns + ` FROM odometer_readings WHERE reservation_id = $1 ORDER BY recorded_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		r.logger.Error("Failed to list odometer readings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list odometer readings: %w", err)
+	}
+	defer rows.Close()
+
+	readings := []*domain.OdometerReading{}
+	for rows.Next() {
+		var reading domain.OdometerReading
+		var kind string
+		if err := rows.Scan(
+			&reading.ID,
+			&reading.ReservationID,
+			&reading.DriverID,
+			&reading.VehicleID,
+			&kind,
+			&reading.ReadingKm,
+			&reading.RecordedAt,
+			&reading.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan odometer reading: %w", err)
+		}
+		reading.Kind = domain.OdometerReadingKind(kind)
+		readings = append(readings, &reading)
+	}
+
+	return readings, rows.Err()
+}
+
+// SetActualDistance stores the measured distance of a trip next to its
+// estimate.
+func (r *TripUsageRepository) SetActualDistance(reservationID string, km float64) error {
+	ctx := context.Background()
+
+	query := `UPDATE reservations SET actual_distance_km = $2 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, reservationID, km)
+	if err != nil {
+		r.logger.Error("Failed to set actual distance", zap.Error(err))
+		return fmt.Errorf("failed to set actual distance: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrReservationNotFound
+	}
+
+	return nil
+}
+
+func (r *TripUsageRepository) CreateFuelEvent(event *domain.FuelEvent) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO fuel_events (
+			reservation_id, driver_id, vehicle_id, type, quantity, cost, currency,
+			odometer_km, station, occurred_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		event.ReservationID,
+		event.DriverID,
+		event.VehicleID,
+		string(event.Type),
+		event.Quantity,
+		event.Cost,
+		event.Currency,
+		event.OdometerKm,
+		event.Station,
+		event.OccurredAt,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create fuel event", zap.Error(err))
+		return fmt.Errorf("failed to create fuel event: %w", err)
+	}
+
+	event.Unit = event.Type.Unit()
+	return nil
+}
+
+func (r *TripUsageRepository) ListFuelEvents(reservationID string) ([]*domain.FuelEvent, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + fuelEventColumns + ` FROM fuel_events WHERE reservation_id = $1 ORDER BY occurred_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		r.logger.Error("Failed to list fuel events", zap.Error(err))
+		return nil, fmt.Errorf("failed to list fuel events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.FuelEvent{}
+	for rows.Next() {
+		var event domain.FuelEvent
+		var eventType string
+		if err := rows.Scan(
+			&event.ID,
+			&event.ReservationID,
+			&event.DriverID,
+			&event.VehicleID,
+			&eventType,
+			&event.Quantity,
+			&event.Cost,
+			&event.Currency,
+			&event.OdometerKm,
+			&event.Station,
+			&event.OccurredAt,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan fuel event: %w", err)
+		}
+		event.Type = domain.FuelEventType(eventType)
+		event.Unit = event.Type.Unit()
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// UsageSummary totals measured trips and fuel events per vehicle, optionally
+// for a single vehicle. Trips count in the period their end reading falls in.
+func (r *TripUsageRepository) UsageSummary(vehicleID *string, from, to *time.Time) (map[string]domain.VehicleUsageTotals, error) {
+	ctx := context.Background()
+
+	tripConditions, args := usageConditions("e.vehicle_id", "e.recorded_at", vehicleID, from, to)
+	tripQuery := `
+		SELECT e.vehicle_id, COUNT(*), COALESCE(SUM(e.reading_km - s.reading_km), 0)
+		FROM odometer_readings e
+		JOIN odometer_readings s ON s.reservation_id = e.reservation_id AND s.kind = 'START'
+		WHERE ` + strings.Join(append([]string{"e.kind = 'END'"}, tripConditions...), " AND ") + `
+		GROUP BY e.vehicle_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, tripQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to summarize trip distance", zap.Error(err))
+		return nil, fmt.Errorf("failed to summarize trip distance: %w", err)
+	}
+	defer rows.Close()
+
+	summary := make(map[string]domain.VehicleUsageTotals)
+	for rows.Next() {
+		var id string
+		var totals domain.VehicleUsageTotals
+		if err := rows.Scan(&id, &totals.Trips, &totals.DistanceKm); err != nil {
+			return nil, fmt.Errorf("failed to scan trip distance: %w", err)
+		}
+		summary[id] = totals
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fuelConditions, args := usageConditions("vehicle_id", "occurred_at", vehicleID, from, to)
+	fuelQuery := `
+		SELECT vehicle_id, type, COUNT(*), SUM(quantity), COALESCE(SUM(cost), 0)
+		FROM fuel_events
+	`
+	if len(fuelConditions) > 0 {
+		fuelQuery += " WHERE " + strings.Join(fuelConditions, " AND ")
+	}
+	fuelQuery += " GROUP BY vehicle_id, type"
+
+	fuelRows, err := r.db.QueryContext(ctx, fuelQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to summarize fuel events", zap.Error(err))
+		return nil, fmt.Errorf("failed to summarize fuel events: %w", err)
+	}
+	defer fuelRows.Close()
+
+	for fuelRows.Next() {
+		var id, eventType string
+		var count int
+		var quantity, cost float64
+		if err := fuelRows.Scan(&id, &eventType, &count, &quantity, &cost); err != nil {
+			return nil, fmt.Errorf("failed to scan fuel events: %w", err)
+		}
+		totals := summary[id]
+		totals.FuelEvents += count
+		totals.FuelCost += cost
+		if domain.FuelEventType(eventType) == domain.FuelEventCharge {
+			totals.ChargeKwh += quantity
+		} else {
+			totals.FuelLiters += quantity
+		}
+		summary[id] = totals
+	}
+
+	return summary, fuelRows.Err()
+}
+
+func usageConditions(vehicleColumn, timeColumn string, vehicleID *string, from, to *time.Time) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if vehicleID != nil {
+		args = append(args, *vehicleID)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", vehicleColumn, len(args)))
+	}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", timeColumn, len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("%s < $%d", timeColumn, len(args)))
+	}
+
+	return conditions, args
+}
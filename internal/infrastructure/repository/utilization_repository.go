@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type UtilizationRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewUtilizationRepository(db *sql.DB, logger *zap.Logger) *UtilizationRepository {
+	return &UtilizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListFleet returns the vehicles that are not retired, with the company of
+// their current driver.
+func (r *UtilizationRepository) ListFleet(vehicleID *string, vehicleType *domain.VehicleType) ([]*domain.FleetVehicle, error) {
+	ctx := context.Background()
+
+	conditions := []string{"v.status <> 'INACTIVE'"}
+	var args []interface{}
+
+	if vehicleID != nil {
+		args = append(args, *vehicleID)
+		conditions = append(conditions, fmt.Sprintf("v.id = $%d", len(args)))
+	}
+	if vehicleType != nil {
+		args = append(args, string(*vehicleType))
+		conditions = append(conditions, fmt.Sprintf("v.type = $%d", len(args)))
+	}
+
+	query := `
+		SELECT v.id, v.type, v.brand, v.model, v.plate, v.capacity, v.status, v.driver_id,
+			d.company_id, c.name
+		FROM vehicles v
+		LEFT JOIN drivers d ON d.id = v.driver_id
+		LEFT JOIN companies c ON c.id = d.company_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY v.type, v.plate NULLS LAST, v.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list fleet", zap.Error(err))
+		return nil, fmt.Errorf("failed to list fleet: %w", err)
+	}
+	defer rows.Close()
+
+	vehicles := []*domain.FleetVehicle{}
+	for rows.Next() {
+		var vehicle domain.FleetVehicle
+		if err := rows.Scan(
+			&vehicle.ID,
+			&vehicle.Type,
+			&vehicle.Brand,
+			&vehicle.Model,
+			&vehicle.Plate,
+			&vehicle.Capacity,
+			&vehicle.Status,
+			&vehicle.DriverID,
+			&vehicle.CompanyID,
+			&vehicle.CompanyName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan fleet vehicle: %w", err)
+		}
+		vehicles = append(vehicles, &vehicle)
+	}
+
+	return vehicles, rows.Err()
+}
+
+func (r *UtilizationRepository) ListBookings(from, to time.Time) ([]domain.VehicleBooking, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT v.id, r.id, r.datetime, r.distance_km
+		FROM reservations r
+		JOIN vehicles v ON v.driver_id = r.assigned_driver_id
+		WHERE r.status <> 'CANCELADA' AND r.datetime >= $1 AND r.datetime < $2
+		ORDER BY r.datetime
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list vehicle bookings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list vehicle bookings: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []domain.VehicleBooking{}
+	for rows.Next() {
+		var booking domain.VehicleBooking
+		if err := rows.Scan(&booking.VehicleID, &booking.ReservationID, &booking.Start, &booking.DistanceKm); err != nil {
+			return nil, fmt.Errorf("failed to scan vehicle booking: %w", err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, rows.Err()
+}
+
+// ListWindows returns the maintenance windows, other than cancelled ones,
+// that overlap [from, to).
+func (r *UtilizationRepository) ListWindows(from, to time.Time) ([]domain.VehicleWindow, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT vehicle_id, id, scheduled_start, scheduled_end
+		FROM maintenance_records
+		WHERE status <> 'CANCELLED'
+			AND scheduled_start IS NOT NULL AND scheduled_end IS NOT NULL
+			AND scheduled_start < $2 AND scheduled_end > $1
+		ORDER BY scheduled_start
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list maintenance windows", zap.Error(err))
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []domain.VehicleWindow{}
+	for rows.Next() {
+		var window domain.VehicleWindow
+		if err := rows.Scan(&window.VehicleID, &window.RecordID, &window.Start, &window.End); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, rows.Err()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -448,12 +450,44 @@ func (r *VehicleRepository) Delete(id string) error {
 	return nil
 }
 
+// AssignToDriver points the vehicle at a new driver, or none, and keeps
+// vehicle_assignments in step: the previous assignments of the vehicle and of
+// the driver are closed and a new one is opened, all in one transaction.
 func (r *VehicleRepository) AssignToDriver(vehicleID string, driverID *string) error {
 	ctx := context.Background()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var currentDriverID *string
+	err = tx.QueryRowContext(ctx, `SELECT driver_id FROM vehicles WHERE id = $1 FOR UPDATE`, vehicleID).Scan(&currentDriverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound
+		}
+		r.logger.Error("Failed to lock vehicle for assignment", zap.Error(err))
+		return fmt.Errorf("failed to lock vehicle for assignment: %w", err)
+	}
+	driverChanged := domain.VehicleDriverChanged(currentDriverID, driverID)
+
+	now := time.Now()
+
 	// If assigning to a driver, first unassign any other vehicles from that driver
 	if driverID != nil {
-		_, err := r.db.ExecContext(ctx, `
+		_, err := tx.ExecContext(ctx, `
+			UPDATE vehicle_assignments
+			SET released_at = $3
+			WHERE driver_id = $1 AND vehicle_id != $2 AND released_at IS NULL
+		`, *driverID, vehicleID, now)
+		if err != nil {
+			r.logger.Error("Failed to close previous driver assignments", zap.Error(err))
+			return fmt.Errorf("failed to close previous driver assignments: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
 			UPDATE vehicles 
 			SET driver_id = NULL, status = 'AVAILABLE', updated_at = NOW()
 			WHERE driver_id = $1 AND id != $2
@@ -464,6 +498,18 @@ func (r *VehicleRepository) AssignToDriver(vehicleID string, driverID *string) e
 		}
 	}
 
+	if driverChanged {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE vehicle_assignments
+			SET released_at = $2
+			WHERE vehicle_id = $1 AND released_at IS NULL
+		`, vehicleID, now)
+		if err != nil {
+			r.logger.Error("Failed to close vehicle assignment", zap.Error(err))
+			return fmt.Errorf("failed to close vehicle assignment: %w", err)
+		}
+	}
+
 	// Now assign (or unassign) the vehicle
 	query := `
 		UPDATE vehicles
@@ -471,19 +517,24 @@ func (r *VehicleRepository) AssignToDriver(vehicleID string, driverID *string) e
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query, vehicleID, driverID)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, vehicleID, driverID); err != nil {
 		r.logger.Error("Failed to assign vehicle to driver", zap.Error(err))
 		return fmt.Errorf("failed to assign vehicle to driver: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if driverChanged && driverID != nil {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO vehicle_assignments (vehicle_id, driver_id, assigned_at)
+			VALUES ($1, $2, $3)
+		`, vehicleID, *driverID, now)
+		if err != nil {
+			r.logger.Error("Failed to open vehicle assignment", zap.Error(err))
+			return fmt.Errorf("failed to open vehicle assignment: %w", err)
+		}
 	}
 
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vehicle assignment: %w", err)
 	}
 
 	if driverID != nil {
@@ -498,6 +549,161 @@ func (r *VehicleRepository) AssignToDriver(vehicleID string, driverID *string) e
 	return nil
 }
 
+const vehicleAssignmentColumns = `
+	a.id, a.vehicle_id, a.driver_id, a.assigned_at, a.released_at,
+	v.type, v.brand, v.model, v.plate,
+	d.first_name, d.last_name, d.phone
+`
+
+const vehicleAssignmentJoins = `
+	FROM vehicle_assignments a
+	JOIN vehicles v ON v.id = a.vehicle_id
+	JOIN drivers d ON d.id = a.driver_id
+`
+
+// GetAssignmentAt returns the assignment of the vehicle that covers at, or
+// domain.ErrNotFound when nobody had the vehicle then.
+func (r *VehicleRepository) GetAssignmentAt(vehicleID string, at time.Time) (*domain.VehicleAssignment, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + vehicleAssignmentColumns + vehicleAssignmentJoins + `
+		WHERE a.vehicle_id = $1 AND a.assigned_at <= $2 AND (a.released_at IS NULL OR a.released_at > $2)
+		ORDER BY a.assigned_at DESC
+		LIMIT 1
+	`
+
+	assignment, err := scanVehicleAssignment(r.db.QueryRowContext(ctx, query, vehicleID, at))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("Failed to get vehicle assignment", zap.Error(err))
+		return nil, fmt.Errorf("failed to get vehicle assignment: %w", err)
+	}
+
+	return assignment, nil
+}
+
+// ListAssignments returns the assignments matching the filters, newest first.
+// A period matches the assignments that overlap it.
+func (r *VehicleRepository) ListAssignments(req domain.ListVehicleAssignmentsRequest) ([]*domain.VehicleAssignment, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	if req.VehicleID != nil {
+		args = append(args, *req.VehicleID)
+		conditions = append(conditions, fmt.Sprintf("a.vehicle_id = $%d", len(args)))
+	}
+	if req.DriverID != nil {
+		args = append(args, *req.DriverID)
+		conditions = append(conditions, fmt.Sprintf("a.driver_id = $%d", len(args)))
+	}
+	if req.From != nil {
+		args = append(args, *req.From)
+		conditions = append(conditions, fmt.Sprintf("(a.released_at IS NULL OR a.released_at > $%d)", len(args)))
+	}
+	if req.To != nil {
+		args = append(args, *req.To)
+		conditions = append(conditions, fmt.Sprintf("a.assigned_at < $%d", len(args)))
+	}
+
+	query := `SELECT ` + vehicleAssignmentColumns + vehicleAssignmentJoins
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY a.assigned_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list vehicle assignments", zap.Error(err))
+		return nil, fmt.Errorf("failed to list vehicle assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []*domain.VehicleAssignment{}
+	for rows.Next() {
+		assignment, err := scanVehicleAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vehicle assignment: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
+
+func scanVehicleAssignment(row rowScanner) (*domain.VehicleAssignment, error) {
+	var assignment domain.VehicleAssignment
+	var vehicle domain.VehicleBasicInfo
+	var driver domain.DriverBasicInfo
+	var phone sql.NullString
+
+	err := row.Scan(
+		&assignment.ID,
+		&assignment.VehicleID,
+		&assignment.DriverID,
+		&assignment.AssignedAt,
+		&assignment.ReleasedAt,
+		&vehicle.Type,
+		&vehicle.Brand,
+		&vehicle.Model,
+		&vehicle.Plate,
+		&driver.FirstName,
+		&driver.LastName,
+		&phone,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicle.ID = assignment.VehicleID
+	driver.ID = assignment.DriverID
+	driver.Phone = phone.String
+	assignment.Vehicle = &vehicle
+	assignment.Driver = &driver
+
+	return &assignment, nil
+}
+
+// ListActiveCapacities returns the capacity of every vehicle in service,
+// optionally of a single type, largest first. Vehicles without a recorded
+// capacity are left out since they cannot be matched against a group.
+func (r *VehicleRepository) ListActiveCapacities(vehicleType *domain.VehicleType) ([]domain.VehicleCapacity, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, type, capacity
+		FROM vehicles
+		WHERE status IN ('AVAILABLE', 'ASSIGNED') AND capacity IS NOT NULL
+	`
+	args := []interface{}{}
+	if vehicleType != nil {
+		query += " AND type = $1"
+		args = append(args, string(*vehicleType))
+	}
+	query += " ORDER BY capacity DESC, id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list vehicle capacities", zap.Error(err))
+		return nil, fmt.Errorf("failed to list vehicle capacities: %w", err)
+	}
+	defer rows.Close()
+
+	capacities := []domain.VehicleCapacity{}
+	for rows.Next() {
+		var capacity domain.VehicleCapacity
+		if err := rows.Scan(&capacity.VehicleID, &capacity.Type, &capacity.Capacity); err != nil {
+			return nil, fmt.Errorf("failed to scan vehicle capacity: %w", err)
+		}
+		capacities = append(capacities, capacity)
+	}
+
+	return capacities, rows.Err()
+}
+
 func (r *VehicleRepository) AddPhoto(vehicleID string, photoURL string) error {
 	ctx := context.Background()
 
@@ -0,0 +1,485 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const walletColumns = `
+	id, company_id, currency, balance, credit_limit, low_balance_threshold, alert_email,
+	low_balance_alerted_at, created_at, updated_at
+`
+
+const walletTransactionColumns = `
+	id, wallet_id, kind, amount, balance_after, description, payment_id, reservation_id,
+	refund_id, created_by, created_at
+`
+
+// WalletRepository stores company wallets and their double-entry ledger.
+type WalletRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewWalletRepository(db *sql.DB, logger *zap.Logger) *WalletRepository {
+	return &WalletRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *WalletRepository) GetByCompany(companyID uuid.UUID) (*domain.CompanyWallet, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + walletColumns + ` FROM company_wallets WHERE company_id = $1`
+
+	wallet, err := scanWallet(r.db.QueryRowContext(ctx, query, companyID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWalletNotFound
+		}
+		r.logger.Error("Failed to get wallet", zap.Error(err))
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	return wallet, nil
+}
+
+func (r *WalletRepository) GetByID(id uuid.UUID) (*domain.CompanyWallet, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + walletColumns + ` FROM company_wallets WHERE id = $1`
+
+	wallet, err := scanWallet(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWalletNotFound
+		}
+		r.logger.Error("Failed to get wallet", zap.Error(err))
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	return wallet, nil
+}
+
+func (r *WalletRepository) Create(wallet *domain.CompanyWallet) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO company_wallets (company_id, currency, credit_limit, low_balance_threshold, alert_email)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+		RETURNING id, balance, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		wallet.CompanyID,
+		wallet.Currency,
+		wallet.CreditLimit,
+		wallet.LowBalanceThreshold,
+		wallet.AlertEmail,
+	).Scan(&wallet.ID, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create wallet", zap.Error(err))
+		return fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores the wallet's settings. Raising the credit limit or lowering
+// the threshold may bring the wallet back over it, which clears the alert.
+func (r *WalletRepository) Update(wallet *domain.CompanyWallet) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE company_wallets
+		SET credit_limit = $2, low_balance_threshold = $3, alert_email = $4,
+		    low_balance_alerted_at = CASE
+		        WHEN balance + $2 >= $3 THEN NULL
+		        ELSE low_balance_alerted_at
+		    END
+		WHERE id = $1
+		RETURNING balance, low_balance_alerted_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		wallet.ID,
+		wallet.CreditLimit,
+		wallet.LowBalanceThreshold,
+		wallet.AlertEmail,
+	).Scan(&wallet.Balance, &wallet.LowBalanceAlertedAt, &wallet.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrWalletNotFound
+		}
+		r.logger.Error("Failed to update wallet", zap.Error(err))
+		return fmt.Errorf("failed to update wallet: %w", err)
+	}
+
+	return nil
+}
+
+// Post moves the balance first, which locks the wallet until the
+// transaction and its entries are stored; a duplicate posting rolls the
+// move back.
+func (r *WalletRepository) Post(t *domain.WalletTransaction) (*domain.CompanyWallet, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `
+		UPDATE company_wallets
+		SET balance = balance + $2,
+		    low_balance_alerted_at = CASE
+		        WHEN balance + $2 + credit_limit >= low_balance_threshold THEN NULL
+		        ELSE low_balance_alerted_at
+		    END
+		WHERE id = $1
+		RETURNING ` + walletColumns
+
+	wallet, err := scanWallet(tx.QueryRowContext(ctx, updateQuery, t.WalletID, t.Amount))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWalletNotFound
+		}
+		r.logger.Error("Failed to move wallet balance", zap.Error(err))
+		return nil, fmt.Errorf("failed to move wallet balance: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO wallet_transactions (wallet_id, kind, amount, balance_after, description, payment_id, reservation_id, refund_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+
+	t.BalanceAfter = wallet.Balance
+	err = tx.QueryRowContext(ctx, insertQuery,
+		t.WalletID,
+		string(t.Kind),
+		t.Amount,
+		t.BalanceAfter,
+		t.Description,
+		t.PaymentID,
+		t.ReservationID,
+		t.RefundID,
+		t.CreatedBy,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create wallet transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to create wallet transaction: %w", err)
+	}
+
+	entryQuery := `
+		INSERT INTO wallet_ledger_entries (transaction_id, account, wallet_id, amount)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	for _, entry := range t.Entries {
+		var walletID *uuid.UUID
+		if entry.Account == domain.LedgerAccountWallet {
+			walletID = &t.WalletID
+		}
+		if _, err := tx.ExecContext(ctx, entryQuery, t.ID, string(entry.Account), walletID, entry.Amount); err != nil {
+			r.logger.Error("Failed to create ledger entry", zap.Error(err))
+			return nil, fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+	}
+
+	// The entries are checked to balance on commit
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit wallet transaction: %w", err)
+	}
+
+	return wallet, nil
+}
+
+func (r *WalletRepository) ListTransactions(req domain.ListWalletTransactionsRequest) ([]*domain.WalletTransaction, int, error) {
+	ctx := context.Background()
+
+	args := []interface{}{req.WalletID}
+	conditions := []string{"wallet_id = $1"}
+
+	if req.Kind != nil {
+		args = append(args, string(*req.Kind))
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM wallet_transactions ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count wallet transactions", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count wallet transactions: %w", err)
+	}
+
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	query := fmt.Sprintf(`
+		SELECT %s FROM wallet_transactions
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, walletTransactionColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list wallet transactions", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list wallet transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []*domain.WalletTransaction{}
+	for rows.Next() {
+		t, err := scanWalletTransaction(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan wallet transaction: %w", err)
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadEntries(ctx, transactions); err != nil {
+		r.logger.Error("Failed to list ledger entries", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+
+	return transactions, total, nil
+}
+
+func (r *WalletRepository) loadEntries(ctx context.Context, transactions []*domain.WalletTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.WalletTransaction, len(transactions))
+	args := make([]interface{}, 0, len(transactions))
+	placeholders := make([]string, len(transactions))
+	for i, t := range transactions {
+		t.Entries = []domain.LedgerEntry{}
+		byID[t.ID] = t
+		args = append(args, t.ID)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	query := `
+		SELECT transaction_id, account, amount
+		FROM wallet_ledger_entries
+		WHERE transaction_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY transaction_id, account = 'WALLET' DESC, account
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID uuid.UUID
+		var account string
+		var amount float64
+		if err := rows.Scan(&transactionID, &account, &amount); err != nil {
+			return err
+		}
+		if t, ok := byID[transactionID]; ok {
+			t.Entries = append(t.Entries, domain.LedgerEntry{Account: domain.LedgerAccount(account), Amount: amount})
+		}
+	}
+
+	return rows.Err()
+}
+
+// CommittedAmount counts the company's active and scheduled trips, those
+// paid with company credit included: they are debited on completion.
+func (r *WalletRepository) CommittedAmount(companyID uuid.UUID, excludeReservationID string) (float64, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT COALESCE(SUM(r.amount), 0)
+		FROM reservations r
+		WHERE r.org_id = $1
+		  AND r.status IN ('ACTIVA', 'PROGRAMADA')
+		  AND r.amount IS NOT NULL
+		  AND r.id <> $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM payments pay
+		      WHERE pay.reservation_id = r.id
+		        AND pay.status IN ('APPROVED', 'PARTIALLY_REFUNDED', 'REFUNDED')
+		        AND pay.gateway <> 'COMPANY_CREDIT'
+		  )
+	`
+
+	var committed float64
+	if err := r.db.QueryRowContext(ctx, query, companyID, excludeReservationID).Scan(&committed); err != nil {
+		r.logger.Error("Failed to get committed wallet amount", zap.Error(err))
+		return 0, fmt.Errorf("failed to get committed wallet amount: %w", err)
+	}
+
+	return committed, nil
+}
+
+// ListDebitCandidates leaves out trips paid on their own through a gateway,
+// as billing accruals do, and trips refunded in full.
+func (r *WalletRepository) ListDebitCandidates(currency string, limit int) ([]*domain.WalletDebitCandidate, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT w.company_id, r.id, r.pickup, r.destination, r.amount - COALESCE(refunded.amount, 0), r.updated_at
+		FROM reservations r
+		JOIN company_wallets w ON w.company_id = r.org_id AND w.currency = $1
+		LEFT JOIN LATERAL (
+		    SELECT SUM(f.amount) AS amount
+		    FROM refunds f
+		    JOIN payments pay ON pay.id = f.payment_id
+		    WHERE pay.reservation_id = r.id
+		      AND pay.gateway = 'COMPANY_CREDIT'
+		      AND f.status = 'COMPLETED'
+		) refunded ON TRUE
+		WHERE r.status = 'COMPLETADA'
+		  AND r.amount IS NOT NULL
+		  AND r.updated_at >= w.created_at
+		  AND r.amount - COALESCE(refunded.amount, 0) > 0
+		  AND NOT EXISTS (
+		      SELECT 1 FROM wallet_transactions t
+		      WHERE t.reservation_id = r.id AND t.kind = 'TRIP_DEBIT'
+		  )
+		  AND NOT EXISTS (
+		      SELECT 1 FROM payments pay
+		      WHERE pay.reservation_id = r.id
+		        AND pay.status IN ('APPROVED', 'PARTIALLY_REFUNDED', 'REFUNDED')
+		        AND pay.gateway <> 'COMPANY_CREDIT'
+		  )
+		ORDER BY r.updated_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, currency, limit)
+	if err != nil {
+		r.logger.Error("Failed to list wallet debit candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list wallet debit candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*domain.WalletDebitCandidate{}
+	for rows.Next() {
+		var candidate domain.WalletDebitCandidate
+		if err := rows.Scan(
+			&candidate.CompanyID,
+			&candidate.ReservationID,
+			&candidate.Pickup,
+			&candidate.Destination,
+			&candidate.Amount,
+			&candidate.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet debit candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+func (r *WalletRepository) HasTripDebit(reservationID string) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT EXISTS (
+		    SELECT 1 FROM wallet_transactions
+		    WHERE reservation_id = $1 AND kind = 'TRIP_DEBIT'
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, reservationID).Scan(&exists); err != nil {
+		r.logger.Error("Failed to check trip debit", zap.Error(err))
+		return false, fmt.Errorf("failed to check trip debit: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *WalletRepository) MarkLowBalanceAlerted(walletID uuid.UUID, at time.Time) (bool, error) {
+	ctx := context.Background()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE company_wallets
+		SET low_balance_alerted_at = $2
+		WHERE id = $1 AND low_balance_alerted_at IS NULL
+	`, walletID, at)
+	if err != nil {
+		r.logger.Error("Failed to mark low balance alert", zap.Error(err))
+		return false, fmt.Errorf("failed to mark low balance alert: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark low balance alert: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func scanWallet(row rowScanner) (*domain.CompanyWallet, error) {
+	var wallet domain.CompanyWallet
+	err := row.Scan(
+		&wallet.ID,
+		&wallet.CompanyID,
+		&wallet.Currency,
+		&wallet.Balance,
+		&wallet.CreditLimit,
+		&wallet.LowBalanceThreshold,
+		&wallet.AlertEmail,
+		&wallet.LowBalanceAlertedAt,
+		&wallet.CreatedAt,
+		&wallet.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func scanWalletTransaction(row rowScanner) (*domain.WalletTransaction, error) {
+	var t domain.WalletTransaction
+	var kind string
+	err := row.Scan(
+		&t.ID,
+		&t.WalletID,
+		&kind,
+		&t.Amount,
+		&t.BalanceAfter,
+		&t.Description,
+		&t.PaymentID,
+		&t.ReservationID,
+		&t.RefundID,
+		&t.CreatedBy,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Kind = domain.WalletTransactionKind(kind)
+	return &t, nil
+}
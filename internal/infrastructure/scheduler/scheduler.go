@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work executed on a fixed interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs in their own goroutines until the context
+// passed to Start is cancelled.
+type Scheduler struct {
+	jobs   []Job
+	logger *zap.Logger
+	wg     sync.WaitGroup
+}
+
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+	}
+}
+
+// Every registers a job. Jobs with a non-positive interval are ignored so they
+// can be disabled from configuration.
+func (s *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		s.logger.Info("Scheduled job disabled", zap.String("job", name))
+		return
+	}
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	s.logger.Info("Scheduler started", zap.Int("jobs", len(s.jobs)))
+}
+
+// Wait blocks until every job goroutine has returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.logger.Error("Scheduled job panicked", zap.String("job", job.Name), zap.Any("error", recovered))
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		s.logger.Error("Scheduled job failed", zap.String("job", job.Name), zap.Error(err))
+		return
+	}
+	s.logger.Debug("Scheduled job completed", zap.String("job", job.Name), zap.Duration("took", time.Since(start)))
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestLocalStorage_SignedURLRoundTrip(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:3000/api/v1/public/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "vehicles/v-1/photo.jpg", []byte("jpeg"), "image/jpeg"))
+
+	signed, err := s.SignedURL("vehicles/v-1/photo.jpg", time.Minute)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	key := strings.TrimPrefix(parsed.Path, "/api/v1/public/files/")
+	assert.Equal(t, "vehicles/v-1/photo.jpg", key)
+	assert.NoError(t, s.Verify(key, parsed.Query().Get("expires"), parsed.Query().Get("signature")))
+	assert.Equal(t, domain.ErrInvalidSignature, s.Verify("vehicles/v-2/photo.jpg", parsed.Query().Get("expires"), parsed.Query().Get("signature")))
+
+	file, err := s.Open(ctx, key)
+	require.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "jpeg", string(content))
+}
+
+func TestLocalStorage_RejectsExpiredSignature(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:3000/api/v1/public/files", "secret")
+	require.NoError(t, err)
+
+	signed, err := s.SignedURL("a.pdf", time.Minute)
+	require.NoError(t, err)
+	parsed, _ := url.Parse(signed)
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.Equal(t, domain.ErrInvalidSignature, s.Verify("a.pdf", parsed.Query().Get("expires"), parsed.Query().Get("signature")))
+}
+
+func TestLocalStorage_RejectsPathTraversal(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:3000/api/v1/public/files", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"../etc/passwd", "/abs", "a/../../b", ""} {
+		_, err := s.Open(context.Background(), key)
+		assert.Equal(t, domain.ErrInvalidInput, err, key)
+	}
+}
@@ -0,0 +1,75 @@
+package tracking
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// subscriberBuffer is how many locations a slow subscriber may lag behind
+// before new ones are dropped for it.
+const subscriberBuffer = 16
+
+// Broker is an in-process domain.LocationBroker. Subscribers only receive
+// locations recorded by the same API instance.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *domain.DriverLocation]struct{}
+	logger      *zap.Logger
+}
+
+func NewBroker(logger *zap.Logger) *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan *domain.DriverLocation]struct{}),
+		logger:      logger,
+	}
+}
+
+// Publish delivers the location to every subscriber of its reservation without
+// blocking; subscribers whose buffer is full miss the update.
+func (b *Broker) Publish(location *domain.DriverLocation) {
+	if location.ReservationID == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[*location.ReservationID] {
+		select {
+		case ch <- location:
+		default:
+			b.logger.Debug("Dropping location for slow subscriber", zap.String("reservation_id", *location.ReservationID))
+		}
+	}
+}
+
+// Subscribe returns a channel of live locations for the reservation and a
+// function that must be called to release it.
+func (b *Broker) Subscribe(reservationID string) (<-chan *domain.DriverLocation, func()) {
+	ch := make(chan *domain.DriverLocation, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[reservationID] == nil {
+		b.subscribers[reservationID] = make(map[chan *domain.DriverLocation]struct{})
+	}
+	b.subscribers[reservationID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[reservationID], ch)
+			if len(b.subscribers[reservationID]) == 0 {
+				delete(b.subscribers, reservationID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+func TestBroker_PublishDeliversToReservationSubscribers(t *testing.T) {
+	broker := NewBroker(zap.NewNop())
+
+	updates, unsubscribe := broker.Subscribe("RSV-1")
+	defer unsubscribe()
+	others, unsubscribeOthers := broker.Subscribe("RSV-2")
+	defer unsubscribeOthers()
+
+	reservationID := "RSV-1"
+	broker.Publish(&domain.DriverLocation{DriverID: "DRV-1", ReservationID: &reservationID, RecordedAt: time.Now()})
+
+	select {
+	case location := <-updates:
+		assert.Equal(t, "DRV-1", location.DriverID)
+	case <-time.After(time.Second):
+		t.Fatal("expected location for subscribed reservation")
+	}
+
+	select {
+	case <-others:
+		t.Fatal("unexpected location for another reservation")
+	default:
+	}
+}
+
+func TestBroker_PublishDoesNotBlockSlowSubscribers(t *testing.T) {
+	broker := NewBroker(zap.NewNop())
+
+	_, unsubscribe := broker.Subscribe("RSV-1")
+	defer unsubscribe()
+
+	reservationID := "RSV-1"
+	for i := 0; i < subscriberBuffer*2; i++ {
+		broker.Publish(&domain.DriverLocation{ReservationID: &reservationID})
+	}
+}
+
+func TestBroker_UnsubscribeClosesChannel(t *testing.T) {
+	broker := NewBroker(zap.NewNop())
+
+	updates, unsubscribe := broker.Subscribe("RSV-1")
+	unsubscribe()
+	unsubscribe()
+
+	_, open := <-updates
+	assert.False(t, open)
+	assert.Empty(t, broker.subscribers)
+}
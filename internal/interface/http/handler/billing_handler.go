@@ -3,13 +3,15 @@ package handler
 import (
 	"fmt"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/interface/http/middleware"
 	"turivo-backend/internal/usecase"
 )
 
@@ -25,9 +27,16 @@ func NewBillingHandler(paymentUseCase *usecase.PaymentUseCase, logger *zap.Logge
 	}
 }
 
+// CompanyPaymentsResponse is a page of company payments with the totals of
+// every payment matching the filters.
+type CompanyPaymentsResponse struct {
+	PaginatedResponse
+	Totals domain.CompanyPaymentTotals `json:"totals"`
+}
+
 // GetCompanyPayments godoc
 // @Summary Get company payments
-// @Description Get payments for a company (filtered by user's organization)
+// @Description Payments of the reservations of the user's organization, newest first, with totals per status and per month of every matching payment. Dates are YYYY-MM-DD; "to" is inclusive.
 // @Tags billing
 // @Accept json
 // @Produce json
@@ -35,100 +44,137 @@ func NewBillingHandler(paymentUseCase *usecase.PaymentUseCase, logger *zap.Logge
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
 // @Param status query string false "Payment status filter"
-// @Success 200 {object} PaginatedResponse
+// @Param from query string false "Created from (YYYY-MM-DD)"
+// @Param to query string false "Created until (YYYY-MM-DD)"
+// @Param cost_center query string false "Reservation cost center"
+// @Param user_id query string false "User who booked the reservation"
+// @Success 200 {object} CompanyPaymentsResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/company/payments [get]
 func (h *BillingHandler) GetCompanyPayments(c *gin.Context) {
-	// Get authenticated user info
-	_, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error: "Unauthorized",
-		})
+	filter, ok := parseCompanyPaymentFilter(c)
+	if !ok {
 		return
 	}
 
-	userRoleRaw, exists := c.Get("user_role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error: "User role not found",
-		})
+	list, err := h.paymentUseCase.GetCompanyPayments(filter)
+	if err != nil {
+		h.respondBillingError(c, err, "Failed to get company payments")
 		return
 	}
 
-	var userRole domain.UserRole
-	switch v := userRoleRaw.(type) {
-	case domain.UserRole:
-		userRole = v
-	case string:
-		userRole = domain.UserRole(v)
-	default:
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Invalid user role type",
-		})
+	c.JSON(http.StatusOK, CompanyPaymentsResponse{
+		PaginatedResponse: PaginatedResponse{
+			Data:       list.Payments,
+			Page:       filter.Page,
+			PageSize:   filter.PageSize,
+			Total:      list.Total,
+			TotalPages: (list.Total + filter.PageSize - 1) / filter.PageSize,
+		},
+		Totals: list.Totals,
+	})
+}
+
+// ExportCompanyPayments godoc
+// @Summary Export company payments
+// @Description Every payment matching the same filters as the listing, as CSV, for the company's own reconciliation. Exports are capped at 10000 payments.
+// @Tags billing
+// @Produce text/csv
+// @Security BearerAuth
+// @Param status query string false "Payment status filter"
+// @Param from query string false "Created from (YYYY-MM-DD)"
+// @Param to query string false "Created until (YYYY-MM-DD)"
+// @Param cost_center query string false "Reservation cost center"
+// @Param user_id query string false "User who booked the reservation"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/payments/export [get]
+func (h *BillingHandler) ExportCompanyPayments(c *gin.Context) {
+	filter, ok := parseCompanyPaymentFilter(c)
+	if !ok {
 		return
 	}
 
-	if userRole != domain.UserRoleCompany {
-		c.JSON(http.StatusForbidden, ErrorResponse{
-			Error: "Only company users can view company payments",
-		})
+	payments, err := h.paymentUseCase.ExportCompanyPayments(filter)
+	if err != nil {
+		h.respondBillingError(c, err, "Failed to export company payments")
+		return
+	}
+
+	content, err := report.CompanyPaymentsCSV(payments)
+	if err != nil {
+		h.respondBillingError(c, err, "Failed to write company payments")
 		return
 	}
 
-	orgIDRaw, exists := c.Get("org_id")
-	if !exists {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="pagos-%s.csv"`, time.Now().Format("2006-01-02")))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+// parseCompanyPaymentFilter scopes the filter to the user's organization and
+// reads the query filters.
+func parseCompanyPaymentFilter(c *gin.Context) (domain.CompanyPaymentFilter, bool) {
+	filter := domain.CompanyPaymentFilter{}
+
+	orgID, ok := middleware.GetOrgID(c)
+	if !ok || orgID == nil {
 		c.JSON(http.StatusForbidden, ErrorResponse{
 			Error: "User must belong to an organization",
 		})
-		return
+		return filter, false
 	}
+	filter.CompanyID = *orgID
+	filter.Page, filter.PageSize = parsePagination(c)
 
-	// Parse query parameters
-	page := 1
-	if p := c.Query("page"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
-			page = parsed
-		}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return filter, false
 	}
+	filter.From, filter.To = from, to
 
-	pageSize := 10
-	if ps := c.Query("page_size"); ps != "" {
-		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
-			pageSize = parsed
+	if value := c.Query("status"); value != "" {
+		status := domain.PaymentStatus(value)
+		filter.Status = &status
+	}
+	if value := c.Query("cost_center"); value != "" {
+		filter.CostCenter = &value
+	}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid user ID",
+			})
+			return filter, false
 		}
+		filter.UserID = &userID
 	}
 
-	status := c.Query("status")
+	return filter, true
+}
 
-	// Parse org_id
-	orgUUID, err := uuid.Parse(fmt.Sprint(orgIDRaw))
-	if err != nil {
+func (h *BillingHandler) respondBillingError(c *gin.Context, err error, logMessage string) {
+	switch err {
+	case domain.ErrInvalidInput:
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid organization ID",
+			Error:   "Invalid filters",
+			Details: "status must be a payment status and to must not be before from",
 		})
-		return
-	}
-
-	// Get payments for this company
-	payments, total, err := h.paymentUseCase.GetCompanyPayments(orgUUID, page, pageSize, status)
-	if err != nil {
-		h.logger.Error("Failed to get company payments", zap.Error(err))
+	case domain.ErrExportTooLarge:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Too many payments to export",
+			Details: "narrow the date range or filters",
+		})
+	default:
+		h.logger.Error(logMessage, zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "Internal server error",
 		})
-		return
 	}
-
-	totalPages := (total + pageSize - 1) / pageSize
-
-	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:       payments,
-		Page:       page,
-		PageSize:   pageSize,
-		Total:      total,
-		TotalPages: totalPages,
-	})
 }
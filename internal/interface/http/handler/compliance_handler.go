@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// ComplianceErrorResponse is returned when an assignment is blocked because a
+// driver or vehicle is not compliant.
+type ComplianceErrorResponse struct {
+	Error  string                   `json:"error"`
+	Issues []domain.ComplianceIssue `json:"issues"`
+}
+
+type ComplianceHandler struct {
+	complianceUseCase *usecase.ComplianceUseCase
+	driverUseCase     *usecase.DriverUseCase
+	logger            *zap.Logger
+}
+
+func NewComplianceHandler(complianceUseCase *usecase.ComplianceUseCase, driverUseCase *usecase.DriverUseCase, logger *zap.Logger) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceUseCase: complianceUseCase,
+		driverUseCase:     driverUseCase,
+		logger:            logger,
+	}
+}
+
+// GetDriverCompliance godoc
+// @Summary Get driver compliance
+// @Description Check whether a driver and their vehicle can take trips, with the reasons when they cannot
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Success 200 {object} domain.DriverCompliance
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/compliance [get]
+func (h *ComplianceHandler) GetDriverCompliance(c *gin.Context) {
+	compliance, err := h.complianceUseCase.GetDriverCompliance(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to get driver compliance")
+		return
+	}
+
+	c.JSON(http.StatusOK, compliance)
+}
+
+// GetMyCompliance godoc
+// @Summary Get my compliance
+// @Description Check whether the authenticated driver and their vehicle can take trips
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.DriverCompliance
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/compliance [get]
+func (h *ComplianceHandler) GetMyCompliance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return
+	}
+
+	compliance, err := h.complianceUseCase.GetDriverCompliance(driver.ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get driver compliance")
+		return
+	}
+
+	c.JSON(http.StatusOK, compliance)
+}
+
+// GetVehicleCompliance godoc
+// @Summary Get vehicle compliance
+// @Description Check whether a vehicle's insurance, inspection and status allow it to be used for trips
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Success 200 {object} domain.VehicleCompliance
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/compliance [get]
+func (h *ComplianceHandler) GetVehicleCompliance(c *gin.Context) {
+	compliance, err := h.complianceUseCase.GetVehicleCompliance(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to get vehicle compliance")
+		return
+	}
+
+	c.JSON(http.StatusOK, compliance)
+}
+
+func (h *ComplianceHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+// respondComplianceError writes a 422 with the blocking issues when err is a
+// compliance failure. It reports whether the response was written.
+func respondComplianceError(c *gin.Context, err error) bool {
+	var complianceErr *domain.ComplianceError
+	if !errors.As(err, &complianceErr) {
+		return false
+	}
+
+	c.JSON(http.StatusUnprocessableEntity, ComplianceErrorResponse{
+		Error:  "Driver or vehicle is not compliant",
+		Issues: complianceErr.Issues,
+	})
+	return true
+}
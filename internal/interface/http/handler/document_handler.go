@@ -0,0 +1,544 @@
+package handler
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// multipartOverhead is the allowance for multipart boundaries and form fields
+// on top of the file size limit.
+const multipartOverhead = 1 << 20
+
+type DocumentHandler struct {
+	documentUseCase *usecase.DocumentUseCase
+	driverUseCase   *usecase.DriverUseCase
+	validator       *validator.Validate
+	maxUploadSize   int64
+	logger          *zap.Logger
+}
+
+func NewDocumentHandler(documentUseCase *usecase.DocumentUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, maxUploadSize int64, logger *zap.Logger) *DocumentHandler {
+	return &DocumentHandler{
+		documentUseCase: documentUseCase,
+		driverUseCase:   driverUseCase,
+		validator:       validator,
+		maxUploadSize:   maxUploadSize,
+		logger:          logger,
+	}
+}
+
+// UploadDriverDocument godoc
+// @Summary Upload a driver document
+// @Description Upload a driver photo, license or background check scan. The file type is detected from its content; the upload stays pending until an admin reviews it.
+// @Tags drivers
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param kind formData string true "Document kind" Enums(DRIVER_PHOTO, LICENSE, BACKGROUND_CHECK)
+// @Param file formData file true "File (JPEG, PNG, WebP or PDF)"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/documents [post]
+func (h *DocumentHandler) UploadDriverDocument(c *gin.Context) {
+	h.uploadDriverDocument(c, c.Param("id"))
+}
+
+// ListDriverDocuments godoc
+// @Summary List driver documents
+// @Description List the files uploaded for a driver with their review status
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/documents [get]
+func (h *DocumentHandler) ListDriverDocuments(c *gin.Context) {
+	h.listOwnerDocuments(c, domain.DocumentOwnerDriver, c.Param("id"))
+}
+
+// UploadVehiclePhoto godoc
+// @Summary Upload a vehicle photo
+// @Description Upload a vehicle photo. The photo is added to the vehicle once an admin approves it.
+// @Tags vehicles
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param file formData file true "Image (JPEG, PNG or WebP)"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/photos [post]
+func (h *DocumentHandler) UploadVehiclePhoto(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, fileName, ok := openUpload(c, h.maxUploadSize, h.logger)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	document, err := h.documentUseCase.UploadVehiclePhoto(c.Param("id"), fileName, file, userID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to upload vehicle photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// ListVehicleDocuments godoc
+// @Summary List vehicle uploads
+// @Description List the photos uploaded for a vehicle with their review status
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/documents [get]
+func (h *DocumentHandler) ListVehicleDocuments(c *gin.Context) {
+	h.listOwnerDocuments(c, domain.DocumentOwnerVehicle, c.Param("id"))
+}
+
+// GetDownloadURL godoc
+// @Summary Get a document download URL
+// @Description Get a short-lived signed URL to download an uploaded file
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {object} domain.SignedURL
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/download [get]
+func (h *DocumentHandler) GetDownloadURL(c *gin.Context) {
+	documentID, ok := parseDocumentID(c)
+	if !ok {
+		return
+	}
+
+	signed, err := h.documentUseCase.GetDownloadURL(documentID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to sign document URL")
+		return
+	}
+
+	c.JSON(http.StatusOK, signed)
+}
+
+// UploadMyDocument godoc
+// @Summary Upload my document
+// @Description Upload a photo, license or background check scan for the authenticated driver
+// @Tags Driver
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param kind formData string true "Document kind" Enums(DRIVER_PHOTO, LICENSE, BACKGROUND_CHECK)
+// @Param file formData file true "File (JPEG, PNG, WebP or PDF)"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/documents [post]
+func (h *DocumentHandler) UploadMyDocument(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	h.uploadDriverDocument(c, driver.ID)
+}
+
+// ListMyDocuments godoc
+// @Summary List my documents
+// @Description List the files the authenticated driver has uploaded with their review status
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/documents [get]
+func (h *DocumentHandler) ListMyDocuments(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	h.listOwnerDocuments(c, domain.DocumentOwnerDriver, driver.ID)
+}
+
+// GetMyDownloadURL godoc
+// @Summary Get a download URL for my document
+// @Description Get a short-lived signed URL for one of the authenticated driver's own files
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {object} domain.SignedURL
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/documents/{id}/download [get]
+func (h *DocumentHandler) GetMyDownloadURL(c *gin.Context) {
+	documentID, ok := parseDocumentID(c)
+	if !ok {
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	document, err := h.documentUseCase.GetDocument(documentID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to get document")
+		return
+	}
+
+	// Do not reveal whether other drivers' documents exist
+	if document.OwnerType != domain.DocumentOwnerDriver || document.OwnerID != driver.ID {
+		h.respondDocumentError(c, domain.ErrDocumentNotFound, "")
+		return
+	}
+
+	signed, err := h.documentUseCase.GetDownloadURL(documentID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to sign document URL")
+		return
+	}
+
+	c.JSON(http.StatusOK, signed)
+}
+
+// ListDocuments godoc
+// @Summary List documents for review
+// @Description List uploaded files across drivers and vehicles (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param owner_type query string false "Filter by owner type" Enums(DRIVER, VEHICLE)
+// @Param owner_id query string false "Filter by driver or vehicle ID"
+// @Param kind query string false "Filter by kind" Enums(DRIVER_PHOTO, LICENSE, BACKGROUND_CHECK, VEHICLE_PHOTO)
+// @Param status query string false "Filter by status" Enums(PENDING, APPROVED, REJECTED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/documents [get]
+func (h *DocumentHandler) ListDocuments(c *gin.Context) {
+	req := domain.ListDocumentsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if ownerType := c.Query("owner_type"); ownerType != "" {
+		documentOwnerType := domain.DocumentOwnerType(ownerType)
+		req.OwnerType = &documentOwnerType
+	}
+
+	if ownerID := c.Query("owner_id"); ownerID != "" {
+		req.OwnerID = &ownerID
+	}
+
+	if kind := c.Query("kind"); kind != "" {
+		documentKind := domain.DocumentKind(kind)
+		req.Kind = &documentKind
+	}
+
+	if status := c.Query("status"); status != "" {
+		documentStatus := domain.DocumentStatus(status)
+		req.Status = &documentStatus
+	}
+
+	h.listDocuments(c, req)
+}
+
+// ReviewDocument godoc
+// @Summary Review a document
+// @Description Approve or reject an uploaded file. Approved files are linked to the driver or vehicle (Admin only).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Param request body domain.ReviewDocumentRequest true "Review decision"
+// @Success 200 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/documents/{id} [patch]
+func (h *DocumentHandler) ReviewDocument(c *gin.Context) {
+	documentID, ok := parseDocumentID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ReviewDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	document, err := h.documentUseCase.ReviewDocument(documentID, req, reviewerID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to review document")
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// GetPublicPhoto godoc
+// @Summary Get an approved photo
+// @Description Redirect to a signed URL for an approved driver or vehicle photo
+// @Tags public
+// @Param id path string true "Document ID"
+// @Success 302
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/public/documents/{id} [get]
+func (h *DocumentHandler) GetPublicPhoto(c *gin.Context) {
+	documentID, ok := parseDocumentID(c)
+	if !ok {
+		return
+	}
+
+	signed, err := h.documentUseCase.GetPublicPhotoURL(documentID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to sign photo URL")
+		return
+	}
+
+	c.Redirect(http.StatusFound, signed.URL)
+}
+
+func (h *DocumentHandler) uploadDriverDocument(c *gin.Context, driverID string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, fileName, ok := openUpload(c, h.maxUploadSize, h.logger)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	kind := c.PostForm("kind")
+	if err := h.validator.Var(kind, "required,oneof=DRIVER_PHOTO LICENSE BACKGROUND_CHECK"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: "kind must be one of DRIVER_PHOTO, LICENSE, BACKGROUND_CHECK",
+		})
+		return
+	}
+
+	document, err := h.documentUseCase.UploadDriverDocument(driverID, domain.DocumentKind(kind), fileName, file, userID)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to upload driver document")
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// openUpload reads the "file" form field, rejecting requests over the upload
+// size limit before the body is buffered. It is shared by every handler that
+// accepts attachments.
+func openUpload(c *gin.Context, maxUploadSize int64, logger *zap.Logger) (multipart.File, string, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondUploadTooLarge(c)
+			return nil, "", false
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "File is required",
+			Details: err.Error(),
+		})
+		return nil, "", false
+	}
+
+	if fileHeader.Size > maxUploadSize {
+		respondUploadTooLarge(c)
+		return nil, "", false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("Failed to open uploaded file", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Failed to read uploaded file",
+		})
+		return nil, "", false
+	}
+
+	return file, fileHeader.Filename, true
+}
+
+func respondUploadTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+		Error: "File exceeds the maximum upload size",
+	})
+}
+
+func (h *DocumentHandler) listOwnerDocuments(c *gin.Context, ownerType domain.DocumentOwnerType, ownerID string) {
+	req := domain.ListDocumentsRequest{
+		OwnerType: &ownerType,
+		OwnerID:   &ownerID,
+	}
+	req.Page, req.PageSize = parsePagination(c)
+
+	h.listDocuments(c, req)
+}
+
+func (h *DocumentHandler) listDocuments(c *gin.Context, req domain.ListDocumentsRequest) {
+	documents, total, err := h.documentUseCase.ListDocuments(req)
+	if err != nil {
+		h.respondDocumentError(c, err, "Failed to list documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       documents,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *DocumentHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *DocumentHandler) respondDocumentError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Document not found",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrDocumentTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: "File exceeds the maximum upload size",
+		})
+	case domain.ErrUnsupportedDocumentType:
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+			Error: "File type is not allowed for this document",
+		})
+	case domain.ErrDocumentAlreadyReviewed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Document has already been reviewed",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid file",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+func parseDocumentID(c *gin.Context) (uuid.UUID, bool) {
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid document ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return documentID, true
+}
@@ -7,19 +7,24 @@ import (
 	"turivo-backend/internal/usecase"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type DriverDashboardHandler struct {
-	driverUseCase *usecase.DriverUseCase
-	logger        *zap.Logger
+	driverUseCase   *usecase.DriverUseCase
+	earningsUseCase *usecase.EarningsUseCase
+	validator       *validator.Validate
+	logger          *zap.Logger
 }
 
-func NewDriverDashboardHandler(driverUseCase *usecase.DriverUseCase, logger *zap.Logger) *DriverDashboardHandler {
+func NewDriverDashboardHandler(driverUseCase *usecase.DriverUseCase, earningsUseCase *usecase.EarningsUseCase, validator *validator.Validate, logger *zap.Logger) *DriverDashboardHandler {
 	return &DriverDashboardHandler{
-		driverUseCase: driverUseCase,
-		logger:        logger,
+		driverUseCase:   driverUseCase,
+		earningsUseCase: earningsUseCase,
+		validator:       validator,
+		logger:          logger,
 	}
 }
 
@@ -67,7 +72,6 @@ func (h *DriverDashboardHandler) GetDriverStats(c *gin.Context) {
 			TotalTrips:     0,
 			CompletedTrips: 0,
 			PendingTrips:   0,
-			TotalEarnings:  0,
 			AverageRating:  0,
 			TotalDistance:  0,
 		}
@@ -75,14 +79,27 @@ func (h *DriverDashboardHandler) GetDriverStats(c *gin.Context) {
 		// Calculate additional stats from KPIs
 		stats = DriverStatsResponse{
 			TotalTrips:     kpis.TotalTrips,
-			CompletedTrips: kpis.TotalTrips,     // Assuming all trips are completed for now
-			PendingTrips:   0,                   // Will be calculated from actual trips
-			TotalEarnings:  kpis.TotalKM * 1200, // Example calculation: 1200 CLP per km
+			CompletedTrips: kpis.TotalTrips, // Assuming all trips are completed for now
+			PendingTrips:   0,               // Will be calculated from actual trips
 			AverageRating:  kpis.AverageRating,
 			TotalDistance:  kpis.TotalKM,
 		}
 	}
 
+	// Earnings come from the driver's ledger
+	if h.earningsUseCase != nil {
+		balance, err := h.earningsUseCase.GetBalance(driver.ID)
+		if err != nil {
+			h.logger.Error("Failed to get driver balance", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to get driver earnings",
+			})
+			return
+		}
+		stats.TotalEarnings = balance.LifetimeEarnings
+		stats.Balance = balance
+	}
+
 	h.logger.Info("Driver stats retrieved successfully", zap.String("driver_id", driver.ID))
 	c.JSON(http.StatusOK, stats)
 }
@@ -275,104 +292,162 @@ func (h *DriverDashboardHandler) GetDriverProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, profile)
 }
 
-// UpdateTripStatus godoc
-// @Summary Update trip status
-// @Description Update the status of a trip for the authenticated driver
+// Response types
+type DriverStatsResponse struct {
+	TotalTrips     int                   `json:"total_trips"`
+	CompletedTrips int                   `json:"completed_trips"`
+	PendingTrips   int                   `json:"pending_trips"`
+	TotalEarnings  float64               `json:"total_earnings"`
+	AverageRating  float64               `json:"average_rating"`
+	TotalDistance  float64               `json:"total_distance"`
+	Balance        *domain.DriverBalance `json:"balance,omitempty"`
+}
+
+type DriverProfileResponse struct {
+	ID        string          `json:"id"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	Email     *string         `json:"email,omitempty"`
+	Phone     *string         `json:"phone,omitempty"`
+	RutOrDNI  string          `json:"rut_or_dni"`
+	BirthDate *string         `json:"birth_date,omitempty"`
+	PhotoURL  *string         `json:"photo_url,omitempty"`
+	Status    string          `json:"status"`
+	Vehicle   *domain.Vehicle `json:"vehicle,omitempty"`
+	Company   *domain.Company `json:"company,omitempty"`
+	Stats     *DriverStats    `json:"stats,omitempty"`
+}
+
+type DriverStats struct {
+	TotalTrips    int     `json:"total_trips"`
+	AverageRating float64 `json:"average_rating"`
+	TotalEarnings float64 `json:"total_earnings"`
+}
+
+type SuccessResponse struct {
+	Message string `json:"message"`
+}
+
+// GetMyAvailability godoc
+// @Summary Get my availability
+// @Description Get the weekly availability pattern of the authenticated driver
 // @Tags Driver
 // @Accept json
 // @Produce json
-// @Param tripId path string true "Trip ID"
-// @Param request body UpdateTripStatusRequest true "Update trip status request"
-// @Success 200 {object} SuccessResponse
-// @Failure 400 {object} ErrorResponse
+// @Success 200 {object} domain.DriverAvailability
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
-// @Router /api/v1/driver/trips/{tripId}/status [patch]
-func (h *DriverDashboardHandler) UpdateTripStatus(c *gin.Context) {
-	tripID := c.Param("tripId")
+// @Router /api/v1/driver/availability [get]
+func (h *DriverDashboardHandler) GetMyAvailability(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	respondAvailability(c, driver)
+}
 
-	var req UpdateTripStatusRequest
+// UpdateMyAvailability godoc
+// @Summary Update my availability
+// @Description Replace the authenticated driver's regions, weekdays and daily time ranges. Time ranges use HH:MM and must not overlap.
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Param request body domain.UpdateDriverAvailabilityRequest true "Availability"
+// @Success 200 {object} domain.DriverAvailability
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/driver/availability [put]
+func (h *DriverDashboardHandler) UpdateMyAvailability(c *gin.Context) {
+	var req domain.UpdateDriverAvailabilityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
+			Error:   "Invalid request body",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	// Get authenticated user info
-	userIDRaw, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error: "Unauthorized",
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
 		})
 		return
 	}
 
-	userID := userIDRaw.(uuid.UUID).String()
-	h.logger.Info("Updating trip status", zap.String("user_id", userID), zap.String("trip_id", tripID), zap.String("status", req.Status))
-
-	// Get driver by user ID
-	driver, err := h.driverUseCase.GetDriverByUserID(userID)
-	if err != nil {
-		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to get driver information",
-		})
+	driver, ok := h.currentDriver(c)
+	if !ok {
 		return
 	}
 
-	// Update trip status
-	err = h.driverUseCase.UpdateTripStatus(driver.ID, tripID, req.Status)
+	availability, err := h.driverUseCase.UpdateDriverAvailability(driver.ID, req)
 	if err != nil {
-		h.logger.Error("Failed to update trip status", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to update trip status",
-		})
+		respondDriverError(c, h.logger, err, "Failed to update driver availability")
 		return
 	}
 
-	h.logger.Info("Trip status updated successfully", zap.String("driver_id", driver.ID), zap.String("trip_id", tripID))
-	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Trip status updated successfully",
-	})
+	c.JSON(http.StatusOK, availability)
 }
 
-// Response types
-type DriverStatsResponse struct {
-	TotalTrips     int     `json:"total_trips"`
-	CompletedTrips int     `json:"completed_trips"`
-	PendingTrips   int     `json:"pending_trips"`
-	TotalEarnings  float64 `json:"total_earnings"`
-	AverageRating  float64 `json:"average_rating"`
-	TotalDistance  float64 `json:"total_distance"`
-}
+// GetMyAvailabilityCalendar godoc
+// @Summary Get my availability calendar
+// @Description Expand the authenticated driver's weekly availability into the concrete slots of one week (Monday to Sunday)
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Param week query string false "Any date in the week (YYYY-MM-DD), defaults to the current week"
+// @Success 200 {object} domain.AvailabilityCalendar
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/driver/availability/calendar [get]
+func (h *DriverDashboardHandler) GetMyAvailabilityCalendar(c *gin.Context) {
+	weekOf, ok := parseWeekOf(c)
+	if !ok {
+		return
+	}
 
-type DriverProfileResponse struct {
-	ID        string          `json:"id"`
-	FirstName string          `json:"first_name"`
-	LastName  string          `json:"last_name"`
-	Email     *string         `json:"email,omitempty"`
-	Phone     *string         `json:"phone,omitempty"`
-	RutOrDNI  string          `json:"rut_or_dni"`
-	BirthDate *string         `json:"birth_date,omitempty"`
-	PhotoURL  *string         `json:"photo_url,omitempty"`
-	Status    string          `json:"status"`
-	Vehicle   *domain.Vehicle `json:"vehicle,omitempty"`
-	Company   *domain.Company `json:"company,omitempty"`
-	Stats     *DriverStats    `json:"stats,omitempty"`
-}
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
 
-type DriverStats struct {
-	TotalTrips    int     `json:"total_trips"`
-	AverageRating float64 `json:"average_rating"`
-	TotalEarnings float64 `json:"total_earnings"`
-}
+	calendar, err := h.driverUseCase.GetAvailabilityCalendar(driver.ID, weekOf)
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to get availability calendar")
+		return
+	}
 
-type UpdateTripStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=PENDING IN_PROGRESS COMPLETED CANCELLED"`
+	c.JSON(http.StatusOK, calendar)
 }
 
-type SuccessResponse struct {
-	Message string `json:"message"`
+// currentDriver loads the full driver record, including availability, for the
+// authenticated user.
+func (h *DriverDashboardHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	driver, err = h.driverUseCase.GetDriverByID(driver.ID)
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to get driver")
+		return nil, false
+	}
+
+	return driver, true
 }
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -348,3 +350,294 @@ func (h *DriverHandler) GetDriverKPIs(c *gin.Context) {
 
 	c.JSON(http.StatusOK, kpis)
 }
+
+// GetDriverLicense godoc
+// @Summary Get driver license
+// @Description Get the license recorded for a driver
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Success 200 {object} domain.DriverLicense
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/license [get]
+func (h *DriverHandler) GetDriverLicense(c *gin.Context) {
+	driver, ok := h.getDriver(c)
+	if !ok {
+		return
+	}
+
+	if driver.License == nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "License not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, driver.License)
+}
+
+// UpdateDriverLicense godoc
+// @Summary Update driver license
+// @Description Create or replace a driver's license number, class and validity
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param request body domain.UpdateDriverLicenseRequest true "License data"
+// @Success 200 {object} domain.DriverLicense
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/license [put]
+func (h *DriverHandler) UpdateDriverLicense(c *gin.Context) {
+	var req domain.UpdateDriverLicenseRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	license, err := h.driverUseCase.UpdateDriverLicense(c.Param("id"), req)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "License must expire after it was issued",
+			})
+			return
+		}
+		respondDriverError(c, h.logger, err, "Failed to update driver license")
+		return
+	}
+
+	c.JSON(http.StatusOK, license)
+}
+
+// GetDriverBackgroundCheck godoc
+// @Summary Get driver background check
+// @Description Get the background check recorded for a driver
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Success 200 {object} domain.DriverBackgroundCheck
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/background-check [get]
+func (h *DriverHandler) GetDriverBackgroundCheck(c *gin.Context) {
+	driver, ok := h.getDriver(c)
+	if !ok {
+		return
+	}
+
+	if driver.BackgroundCheck == nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Background check not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, driver.BackgroundCheck)
+}
+
+// UpdateDriverBackgroundCheck godoc
+// @Summary Update driver background check
+// @Description Record the outcome of a driver's background check
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param request body domain.UpdateDriverBackgroundCheckRequest true "Background check data"
+// @Success 200 {object} domain.DriverBackgroundCheck
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/background-check [put]
+func (h *DriverHandler) UpdateDriverBackgroundCheck(c *gin.Context) {
+	var req domain.UpdateDriverBackgroundCheckRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	check, err := h.driverUseCase.UpdateDriverBackgroundCheck(c.Param("id"), req)
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to update driver background check")
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// GetDriverAvailability godoc
+// @Summary Get driver availability
+// @Description Get the weekly availability pattern of a driver
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Success 200 {object} domain.DriverAvailability
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/availability [get]
+func (h *DriverHandler) GetDriverAvailability(c *gin.Context) {
+	driver, ok := h.getDriver(c)
+	if !ok {
+		return
+	}
+
+	respondAvailability(c, driver)
+}
+
+// UpdateDriverAvailability godoc
+// @Summary Update driver availability
+// @Description Replace a driver's regions, weekdays and daily time ranges. Time ranges use HH:MM and must not overlap.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param request body domain.UpdateDriverAvailabilityRequest true "Availability"
+// @Success 200 {object} domain.DriverAvailability
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/availability [put]
+func (h *DriverHandler) UpdateDriverAvailability(c *gin.Context) {
+	var req domain.UpdateDriverAvailabilityRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	availability, err := h.driverUseCase.UpdateDriverAvailability(c.Param("id"), req)
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to update driver availability")
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// GetDriverAvailabilityCalendar godoc
+// @Summary Get driver availability calendar
+// @Description Expand a driver's weekly availability into the concrete slots of one week (Monday to Sunday)
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param week query string false "Any date in the week (YYYY-MM-DD), defaults to the current week"
+// @Success 200 {object} domain.AvailabilityCalendar
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/availability/calendar [get]
+func (h *DriverHandler) GetDriverAvailabilityCalendar(c *gin.Context) {
+	weekOf, ok := parseWeekOf(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.driverUseCase.GetAvailabilityCalendar(c.Param("id"), weekOf)
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to get availability calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+func (h *DriverHandler) getDriver(c *gin.Context) (*domain.Driver, bool) {
+	driver, err := h.driverUseCase.GetDriverByID(c.Param("id"))
+	if err != nil {
+		respondDriverError(c, h.logger, err, "Failed to get driver")
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *DriverHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func respondDriverError(c *gin.Context, logger *zap.Logger, err error, message string) {
+	if errors.Is(err, domain.ErrInvalidTimeRange) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time ranges",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	switch err {
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	default:
+		logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+// respondAvailability returns the driver's availability, or an empty pattern
+// when none has been recorded yet.
+func respondAvailability(c *gin.Context, driver *domain.Driver) {
+	if driver.Availability == nil {
+		c.JSON(http.StatusOK, domain.DriverAvailability{
+			DriverID:   driver.ID,
+			Regions:    []string{},
+			Days:       []string{},
+			TimeRanges: []domain.TimeRange{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, driver.Availability)
+}
+
+func parseWeekOf(c *gin.Context) (time.Time, bool) {
+	week := c.Query("week")
+	if week == "" {
+		return time.Now(), true
+	}
+
+	weekOf, err := time.Parse("2006-01-02", week)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid week, expected YYYY-MM-DD",
+		})
+		return time.Time{}, false
+	}
+
+	return weekOf, true
+}
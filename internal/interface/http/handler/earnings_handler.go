@@ -0,0 +1,718 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/usecase"
+)
+
+type EarningsHandler struct {
+	earningsUseCase *usecase.EarningsUseCase
+	driverUseCase   *usecase.DriverUseCase
+	validator       *validator.Validate
+	logger          *zap.Logger
+}
+
+func NewEarningsHandler(earningsUseCase *usecase.EarningsUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *EarningsHandler {
+	return &EarningsHandler{
+		earningsUseCase: earningsUseCase,
+		driverUseCase:   driverUseCase,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// ListEarnings godoc
+// @Summary List earnings ledger entries
+// @Description List ledger entries across drivers (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param driver_id query string false "Filter by driver ID"
+// @Param type query string false "Filter by type" Enums(TRIP_PAYOUT, ADJUSTMENT, TIP, CANCELLATION_COMPENSATION, PENALTY)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/earnings [get]
+func (h *EarningsHandler) ListEarnings(c *gin.Context) {
+	req, ok := parseEarningsQuery(c)
+	if !ok {
+		return
+	}
+
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+
+	h.listEarnings(c, req)
+}
+
+// GetDriverEarnings godoc
+// @Summary List a driver's earnings
+// @Description List the ledger entries of a driver (Admin only)
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param type query string false "Filter by type" Enums(TRIP_PAYOUT, ADJUSTMENT, TIP, CANCELLATION_COMPENSATION, PENALTY)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/earnings [get]
+func (h *EarningsHandler) GetDriverEarnings(c *gin.Context) {
+	req, ok := parseEarningsQuery(c)
+	if !ok {
+		return
+	}
+
+	driverID := c.Param("id")
+	req.DriverID = &driverID
+
+	h.listEarnings(c, req)
+}
+
+// CreateEarningEntry godoc
+// @Summary Record a manual earning entry
+// @Description Record an adjustment, tip, cancellation compensation or penalty for a driver (Admin only). Penalties are given as positive amounts and deducted.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param request body domain.CreateEarningEntryRequest true "Entry"
+// @Success 201 {object} domain.EarningEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/earnings [post]
+func (h *EarningsHandler) CreateEarningEntry(c *gin.Context) {
+	var req domain.CreateEarningEntryRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.earningsUseCase.CreateEntry(c.Param("id"), req, userID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to create earning entry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetDriverBalance godoc
+// @Summary Get a driver's balance
+// @Description Get unsettled, pending and paid earnings of a driver (Admin only)
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Success 200 {object} domain.DriverBalance
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/balance [get]
+func (h *EarningsHandler) GetDriverBalance(c *gin.Context) {
+	balance, err := h.earningsUseCase.GetBalance(c.Param("id"))
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to get driver balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// ListSettlements godoc
+// @Summary List settlements
+// @Description List driver settlement statements (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param driver_id query string false "Filter by driver ID"
+// @Param status query string false "Filter by status" Enums(DRAFT, APPROVED, PAID)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements [get]
+func (h *EarningsHandler) ListSettlements(c *gin.Context) {
+	req := domain.ListSettlementsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+
+	if status := c.Query("status"); status != "" {
+		settlementStatus := domain.SettlementStatus(status)
+		req.Status = &settlementStatus
+	}
+
+	h.listSettlements(c, req)
+}
+
+// GenerateSettlements godoc
+// @Summary Generate settlements
+// @Description Draft weekly or biweekly statements for every driver with unsettled earnings, or for one driver. Defaults to the last complete period (Admin only).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.GenerateSettlementsRequest true "Period"
+// @Success 201 {object} []domain.Settlement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/generate [post]
+func (h *EarningsHandler) GenerateSettlements(c *gin.Context) {
+	var req domain.GenerateSettlementsRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	settlements, err := h.earningsUseCase.GenerateSettlements(req)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to generate settlements")
+		return
+	}
+
+	c.JSON(http.StatusCreated, settlements)
+}
+
+// GetSettlement godoc
+// @Summary Get a settlement
+// @Description Get a settlement statement with its entries (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} domain.Settlement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/{id} [get]
+func (h *EarningsHandler) GetSettlement(c *gin.Context) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := h.earningsUseCase.GetSettlement(settlementID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to get settlement")
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+// ApproveSettlement godoc
+// @Summary Approve a settlement
+// @Description Approve a draft settlement for payment (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} domain.Settlement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/{id}/approve [post]
+func (h *EarningsHandler) ApproveSettlement(c *gin.Context) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := h.earningsUseCase.ApproveSettlement(settlementID, userID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to approve settlement")
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+// MarkSettlementPaid godoc
+// @Summary Mark a settlement as paid
+// @Description Record the payout of an approved settlement (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Param request body domain.MarkSettlementPaidRequest false "Payment reference"
+// @Success 200 {object} domain.Settlement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/{id}/pay [post]
+func (h *EarningsHandler) MarkSettlementPaid(c *gin.Context) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.MarkSettlementPaidRequest
+	if c.Request.ContentLength != 0 && !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := h.earningsUseCase.MarkSettlementPaid(settlementID, req, userID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to mark settlement as paid")
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+// DiscardSettlement godoc
+// @Summary Discard a draft settlement
+// @Description Delete a draft settlement and release its entries (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/{id} [delete]
+func (h *EarningsHandler) DiscardSettlement(c *gin.Context) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.earningsUseCase.DiscardSettlement(settlementID); err != nil {
+		h.respondEarningsError(c, err, "Failed to discard settlement")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: "Settlement discarded successfully",
+	})
+}
+
+// ExportSettlement godoc
+// @Summary Export a settlement
+// @Description Download a settlement statement as CSV or PDF (Admin only)
+// @Tags admin
+// @Produce text/csv
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Param format query string false "Export format" Enums(csv, pdf) default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/settlements/{id}/export [get]
+func (h *EarningsHandler) ExportSettlement(c *gin.Context) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := h.earningsUseCase.GetSettlement(settlementID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to get settlement")
+		return
+	}
+
+	h.exportSettlement(c, settlement)
+}
+
+// GetMyEarnings godoc
+// @Summary List my earnings
+// @Description List the ledger entries of the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Filter by type" Enums(TRIP_PAYOUT, ADJUSTMENT, TIP, CANCELLATION_COMPENSATION, PENALTY)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/earnings [get]
+func (h *EarningsHandler) GetMyEarnings(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	req, ok := parseEarningsQuery(c)
+	if !ok {
+		return
+	}
+	req.DriverID = &driver.ID
+
+	h.listEarnings(c, req)
+}
+
+// GetMyBalance godoc
+// @Summary Get my balance
+// @Description Get unsettled, pending and paid earnings of the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.DriverBalance
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/balance [get]
+func (h *EarningsHandler) GetMyBalance(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	balance, err := h.earningsUseCase.GetBalance(driver.ID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to get driver balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetMySettlements godoc
+// @Summary List my settlements
+// @Description List the settlement statements of the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(DRAFT, APPROVED, PAID)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/settlements [get]
+func (h *EarningsHandler) GetMySettlements(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	req := domain.ListSettlementsRequest{DriverID: &driver.ID}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if status := c.Query("status"); status != "" {
+		settlementStatus := domain.SettlementStatus(status)
+		req.Status = &settlementStatus
+	}
+
+	h.listSettlements(c, req)
+}
+
+// GetMySettlement godoc
+// @Summary Get my settlement
+// @Description Get one settlement statement of the authenticated driver with its entries
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} domain.Settlement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/settlements/{id} [get]
+func (h *EarningsHandler) GetMySettlement(c *gin.Context) {
+	settlement, ok := h.mySettlement(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+// ExportMySettlement godoc
+// @Summary Export my settlement
+// @Description Download one settlement statement of the authenticated driver as CSV or PDF
+// @Tags Driver
+// @Produce text/csv
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Param format query string false "Export format" Enums(csv, pdf) default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/settlements/{id}/export [get]
+func (h *EarningsHandler) ExportMySettlement(c *gin.Context) {
+	settlement, ok := h.mySettlement(c)
+	if !ok {
+		return
+	}
+
+	h.exportSettlement(c, settlement)
+}
+
+func (h *EarningsHandler) mySettlement(c *gin.Context) (*domain.Settlement, bool) {
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return nil, false
+	}
+
+	settlement, err := h.earningsUseCase.GetDriverSettlement(driver.ID, settlementID)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to get settlement")
+		return nil, false
+	}
+
+	return settlement, true
+}
+
+func (h *EarningsHandler) exportSettlement(c *gin.Context, settlement *domain.Settlement) {
+	fileName := fmt.Sprintf("liquidacion-%s-%s", settlement.DriverID, settlement.PeriodStart.Format("2006-01-02"))
+
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		content, err := report.SettlementCSV(settlement)
+		if err != nil {
+			h.respondEarningsError(c, err, "Failed to export settlement")
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+
+	case "pdf":
+		driver, err := h.driverUseCase.GetDriverByID(settlement.DriverID)
+		if err != nil {
+			h.respondEarningsError(c, err, "Failed to get settlement driver")
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, fileName))
+		c.Data(http.StatusOK, "application/pdf", report.SettlementPDF(settlement, driver))
+
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid format",
+			Details: "format must be csv or pdf",
+		})
+	}
+}
+
+func (h *EarningsHandler) listEarnings(c *gin.Context, req domain.ListEarningsRequest) {
+	entries, total, err := h.earningsUseCase.ListEntries(req)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to list earnings")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       entries,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *EarningsHandler) listSettlements(c *gin.Context, req domain.ListSettlementsRequest) {
+	settlements, total, err := h.earningsUseCase.ListSettlements(req)
+	if err != nil {
+		h.respondEarningsError(c, err, "Failed to list settlements")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       settlements,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *EarningsHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *EarningsHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *EarningsHandler) respondEarningsError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrSettlementNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Settlement not found",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Settlement is not in a state that allows this action",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid input",
+			Details: "amounts must be positive except for adjustments, and only finished periods can be settled",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
+
+// parseEarningsQuery reads the type and date filters shared by the ledger
+// listings. "to" is an inclusive calendar day.
+func parseEarningsQuery(c *gin.Context) (domain.ListEarningsRequest, bool) {
+	req := domain.ListEarningsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if entryType := c.Query("type"); entryType != "" {
+		earningType := domain.EarningEntryType(entryType)
+		req.Type = &earningType
+	}
+
+	for _, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Details: param + " must be YYYY-MM-DD",
+			})
+			return req, false
+		}
+		if param == "from" {
+			req.From = &day
+		} else {
+			next := day.AddDate(0, 0, 1)
+			req.To = &next
+		}
+	}
+
+	return req, true
+}
+
+func parseSettlementID(c *gin.Context) (uuid.UUID, bool) {
+	settlementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid settlement ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return settlementID, true
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type FeedbackHandler struct {
+	feedbackUseCase *usecase.FeedbackUseCase
+	driverUseCase   *usecase.DriverUseCase
+	validator       *validator.Validate
+	logger          *zap.Logger
+}
+
+func NewFeedbackHandler(feedbackUseCase *usecase.FeedbackUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *FeedbackHandler {
+	return &FeedbackHandler{
+		feedbackUseCase: feedbackUseCase,
+		driverUseCase:   driverUseCase,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// GetPendingFeedback godoc
+// @Summary List pending ratings
+// @Description List the completed trips the authenticated user can still rate
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []domain.FeedbackPrompt
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/feedback/pending [get]
+func (h *FeedbackHandler) GetPendingFeedback(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prompts, err := h.feedbackUseCase.GetPendingFeedback(userID)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to get pending feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, prompts)
+}
+
+// SubmitFeedback godoc
+// @Summary Rate a trip
+// @Description Rate a completed trip (1–5 stars, optional tags and comment). Only the reservation owner can rate, once per trip.
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Param request body domain.SubmitFeedbackRequest true "Rating"
+// @Success 201 {object} domain.DriverFeedback
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/feedback [post]
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	req, ok := h.bindSubmitRequest(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackUseCase.SubmitFeedback(c.Param("id"), userID, req)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to submit feedback")
+		return
+	}
+
+	c.JSON(http.StatusCreated, feedback)
+}
+
+// GetPublicFeedbackPrompt godoc
+// @Summary Get rating prompt
+// @Description Resolve an emailed rating link. No authentication required.
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Param token path string true "Feedback token"
+// @Success 200 {object} domain.FeedbackPrompt
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/feedback/{token} [get]
+func (h *FeedbackHandler) GetPublicFeedbackPrompt(c *gin.Context) {
+	prompt, err := h.feedbackUseCase.GetFeedbackPrompt(c.Param("token"))
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to get feedback prompt")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, prompt)
+}
+
+// SubmitPublicFeedback godoc
+// @Summary Rate a trip from an emailed link
+// @Description Rate a completed trip using the signed link sent by email. No authentication required.
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Param token path string true "Feedback token"
+// @Param request body domain.SubmitFeedbackRequest true "Rating"
+// @Success 201 {object} domain.DriverFeedback
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/feedback/{token} [post]
+func (h *FeedbackHandler) SubmitPublicFeedback(c *gin.Context) {
+	req, ok := h.bindSubmitRequest(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackUseCase.SubmitFeedbackWithToken(c.Param("token"), req)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to submit feedback")
+		return
+	}
+
+	c.JSON(http.StatusCreated, feedback)
+}
+
+// GetDriverFeedback godoc
+// @Summary Get driver ratings
+// @Description Get the published ratings of a driver
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/feedback [get]
+func (h *FeedbackHandler) GetDriverFeedback(c *gin.Context) {
+	h.listDriverFeedback(c, c.Param("id"))
+}
+
+// GetMyFeedback godoc
+// @Summary Get my ratings
+// @Description Get the published ratings of the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/feedback [get]
+func (h *FeedbackHandler) GetMyFeedback(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return
+	}
+
+	h.listDriverFeedback(c, driver.ID)
+}
+
+// ListFeedback godoc
+// @Summary List ratings for moderation
+// @Description List every rating, including hidden ones (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param driver_id query string false "Filter by driver"
+// @Param status query string false "Filter by status" Enums(PUBLISHED, HIDDEN)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/feedback [get]
+func (h *FeedbackHandler) ListFeedback(c *gin.Context) {
+	req := domain.ListFeedbackRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+
+	if status := c.Query("status"); status != "" {
+		feedbackStatus := domain.FeedbackStatus(status)
+		req.Status = &feedbackStatus
+	}
+
+	feedback, total, err := h.feedbackUseCase.ListFeedback(req)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to list feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, newFeedbackPage(feedback, req.Page, req.PageSize, total))
+}
+
+// ModerateFeedback godoc
+// @Summary Moderate a rating
+// @Description Publish or hide a rating. Hidden ratings are excluded from driver profiles and averages (Admin only).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feedback ID"
+// @Param request body domain.ModerateFeedbackRequest true "Moderation decision"
+// @Success 200 {object} domain.DriverFeedback
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/feedback/{id} [patch]
+func (h *FeedbackHandler) ModerateFeedback(c *gin.Context) {
+	feedbackID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid feedback ID",
+		})
+		return
+	}
+
+	var req domain.ModerateFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := h.feedbackUseCase.ModerateFeedback(feedbackID, req, moderatorID)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to moderate feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+func (h *FeedbackHandler) listDriverFeedback(c *gin.Context, driverID string) {
+	page, pageSize := parsePagination(c)
+
+	feedback, total, err := h.feedbackUseCase.ListDriverFeedback(driverID, page, pageSize)
+	if err != nil {
+		h.respondFeedbackError(c, err, "Failed to get driver feedback")
+		return
+	}
+
+	c.JSON(http.StatusOK, newFeedbackPage(feedback, page, pageSize, total))
+}
+
+func (h *FeedbackHandler) bindSubmitRequest(c *gin.Context) (domain.SubmitFeedbackRequest, bool) {
+	var req domain.SubmitFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return req, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return req, false
+	}
+
+	return req, true
+}
+
+func (h *FeedbackHandler) respondFeedbackError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrUnauthorized:
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Feedback link is invalid or expired",
+		})
+	case domain.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Only the reservation owner can rate this trip",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrFeedbackNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Feedback not found",
+		})
+	case domain.ErrFeedbackAlreadyExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "This trip has already been rated",
+		})
+	case domain.ErrFeedbackNotAvailable:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "This trip cannot be rated",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return uuid.Nil, false
+	}
+
+	return userIDRaw.(uuid.UUID), true
+}
+
+func parsePagination(c *gin.Context) (int, int) {
+	page, pageSize := 1, 20
+
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	if ps, err := strconv.Atoi(c.Query("page_size")); err == nil && ps > 0 && ps <= 100 {
+		pageSize = ps
+	}
+
+	return page, pageSize
+}
+
+func newFeedbackPage(feedback []*domain.DriverFeedback, page, pageSize, total int) PaginatedResponse {
+	totalPages := (total + pageSize - 1) / pageSize
+
+	return PaginatedResponse{
+		Data:       feedback,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/storage"
+)
+
+// FileHandler serves files kept in local storage through the signed URLs
+// handed out by LocalStorage. It is not used with S3, whose presigned URLs
+// point directly at the bucket.
+type FileHandler struct {
+	storage *storage.LocalStorage
+	logger  *zap.Logger
+}
+
+func NewFileHandler(storage *storage.LocalStorage, logger *zap.Logger) *FileHandler {
+	return &FileHandler{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// ServeFile godoc
+// @Summary Download a stored file
+// @Description Serve a locally stored file through a signed, expiring URL
+// @Tags public
+// @Param key path string true "Storage key"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "URL signature"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/public/files/{key} [get]
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	if err := h.storage.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Invalid or expired link",
+		})
+		return
+	}
+
+	file, err := h.storage.Open(c.Request.Context(), key)
+	if err != nil {
+		if err == domain.ErrNotFound || err == domain.ErrInvalidInput {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "File not found",
+			})
+			return
+		}
+		h.logger.Error("Failed to open stored file", zap.Error(err), zap.String("key", key))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, seeker)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", file, nil)
+}
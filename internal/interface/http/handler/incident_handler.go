@@ -0,0 +1,529 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type IncidentHandler struct {
+	incidentUseCase *usecase.IncidentUseCase
+	driverUseCase   *usecase.DriverUseCase
+	validator       *validator.Validate
+	maxUploadSize   int64
+	logger          *zap.Logger
+}
+
+func NewIncidentHandler(incidentUseCase *usecase.IncidentUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, maxUploadSize int64, logger *zap.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		incidentUseCase: incidentUseCase,
+		driverUseCase:   driverUseCase,
+		validator:       validator,
+		maxUploadSize:   maxUploadSize,
+		logger:          logger,
+	}
+}
+
+// ReportIncident godoc
+// @Summary Report an incident
+// @Description Report an accident, damage, traffic fine or breakdown of the authenticated driver's current vehicle, optionally during one of their reservations. Critical incidents take the vehicle out of service and reassign the driver's upcoming reservations
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.ReportIncidentRequest true "Incident"
+// @Success 201 {object} domain.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/incidents [post]
+func (h *IncidentHandler) ReportIncident(c *gin.Context) {
+	var req domain.ReportIncidentRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentUseCase.ReportIncident(driver.ID, req, userID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to report incident")
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// GetMyIncidents godoc
+// @Summary List my incidents
+// @Description List the incidents involving the authenticated driver. Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(OPEN, UNDER_REVIEW, RESOLVED)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/incidents [get]
+func (h *IncidentHandler) GetMyIncidents(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.parseListRequest(c)
+	if !ok {
+		return
+	}
+	req.DriverID = &driver.ID
+
+	h.listIncidents(c, req)
+}
+
+// GetMyIncident godoc
+// @Summary Get one of my incidents
+// @Description Get an incident involving the authenticated driver, with its photos
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} domain.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/incidents/{id} [get]
+func (h *IncidentHandler) GetMyIncident(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "Invalid incident ID")
+	if !ok {
+		return
+	}
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentUseCase.GetDriverIncident(incidentID, driver.ID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to get incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// UploadMyIncidentPhoto godoc
+// @Summary Add a photo to one of my incidents
+// @Description Upload a photo (JPEG, PNG or WebP) of the damage or scene of an unresolved incident involving the authenticated driver
+// @Tags Driver
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Param file formData file true "Photo"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/incidents/{id}/photos [post]
+func (h *IncidentHandler) UploadMyIncidentPhoto(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "Invalid incident ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	file, fileName, ok := openUpload(c, h.maxUploadSize, h.logger)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	document, err := h.incidentUseCase.AddDriverPhoto(incidentID, driver.ID, fileName, file, userID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to upload incident photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// CreateIncident godoc
+// @Summary Record an incident
+// @Description Record an accident, damage, traffic fine or breakdown of a vehicle. Without driver_id, the driver of the reservation or the one who had the vehicle when it occurred is used. Critical incidents set the vehicle to MAINTENANCE and reassign its driver's upcoming reservations (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.CreateIncidentRequest true "Incident"
+// @Success 201 {object} domain.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incidents [post]
+func (h *IncidentHandler) CreateIncident(c *gin.Context) {
+	var req domain.CreateIncidentRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentUseCase.CreateIncident(req, userID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to create incident")
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// ListIncidents godoc
+// @Summary List incidents
+// @Description List incidents across the fleet (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param vehicle_id query string false "Filter by vehicle"
+// @Param driver_id query string false "Filter by driver"
+// @Param status query string false "Filter by status" Enums(OPEN, UNDER_REVIEW, RESOLVED)
+// @Param severity query string false "Filter by severity" Enums(LOW, MEDIUM, HIGH, CRITICAL)
+// @Param type query string false "Filter by type" Enums(ACCIDENT, DAMAGE, TRAFFIC_FINE, BREAKDOWN, OTHER)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incidents [get]
+func (h *IncidentHandler) ListIncidents(c *gin.Context) {
+	req, ok := h.parseListRequest(c)
+	if !ok {
+		return
+	}
+
+	if vehicleID := c.Query("vehicle_id"); vehicleID != "" {
+		req.VehicleID = &vehicleID
+	}
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+
+	h.listIncidents(c, req)
+}
+
+// ListVehicleIncidents godoc
+// @Summary List vehicle incidents
+// @Description List the incidents of a vehicle (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param status query string false "Filter by status" Enums(OPEN, UNDER_REVIEW, RESOLVED)
+// @Param severity query string false "Filter by severity" Enums(LOW, MEDIUM, HIGH, CRITICAL)
+// @Param type query string false "Filter by type" Enums(ACCIDENT, DAMAGE, TRAFFIC_FINE, BREAKDOWN, OTHER)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/incidents [get]
+func (h *IncidentHandler) ListVehicleIncidents(c *gin.Context) {
+	vehicleID := c.Param("id")
+	req, ok := h.parseListRequest(c)
+	if !ok {
+		return
+	}
+	req.VehicleID = &vehicleID
+
+	h.listIncidents(c, req)
+}
+
+// GetIncident godoc
+// @Summary Get an incident
+// @Description Get an incident with its photos (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} domain.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incidents/{id} [get]
+func (h *IncidentHandler) GetIncident(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "Invalid incident ID")
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentUseCase.GetIncident(incidentID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to get incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// UpdateIncident godoc
+// @Summary Update an incident
+// @Description Move an incident to UNDER_REVIEW or RESOLVED (or back to OPEN from review), record its cost and resolution, or change its severity. Escalating an unresolved incident to CRITICAL takes the vehicle out of service (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Param request body domain.UpdateIncidentRequest true "Changes"
+// @Success 200 {object} domain.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incidents/{id} [patch]
+func (h *IncidentHandler) UpdateIncident(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "Invalid incident ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateIncidentRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	incident, err := h.incidentUseCase.UpdateIncident(incidentID, req)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to update incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// UploadIncidentPhoto godoc
+// @Summary Add a photo to an incident
+// @Description Upload a photo (JPEG, PNG or WebP) of the damage or scene of an incident (Admin only)
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Param file formData file true "Photo"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incidents/{id}/photos [post]
+func (h *IncidentHandler) UploadIncidentPhoto(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "Invalid incident ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, fileName, ok := openUpload(c, h.maxUploadSize, h.logger)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	document, err := h.incidentUseCase.AddPhoto(incidentID, fileName, file, userID)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to upload incident photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+func (h *IncidentHandler) parseListRequest(c *gin.Context) (domain.ListIncidentsRequest, bool) {
+	req := domain.ListIncidentsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if status := c.Query("status"); status != "" {
+		incidentStatus := domain.IncidentStatus(status)
+		req.Status = &incidentStatus
+	}
+	if severity := c.Query("severity"); severity != "" {
+		incidentSeverity := domain.IncidentSeverity(severity)
+		req.Severity = &incidentSeverity
+	}
+	if incidentType := c.Query("type"); incidentType != "" {
+		t := domain.IncidentType(incidentType)
+		req.Type = &t
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return req, false
+	}
+	req.From, req.To = from, to
+
+	return req, true
+}
+
+func (h *IncidentHandler) listIncidents(c *gin.Context, req domain.ListIncidentsRequest) {
+	incidents, total, err := h.incidentUseCase.ListIncidents(req)
+	if err != nil {
+		h.respondIncidentError(c, err, "Failed to list incidents")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:     incidents,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Total:    total,
+	})
+}
+
+func (h *IncidentHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *IncidentHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *IncidentHandler) respondIncidentError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrIncidentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Incident not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrReservationNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Reservation not found",
+		})
+	case domain.ErrDriverHasNoVehicle:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "You have no vehicle assigned",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Invalid status transition",
+			Details: "resolved incidents cannot change status or take new photos",
+		})
+	case domain.ErrDocumentTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: "File exceeds the maximum upload size",
+		})
+	case domain.ErrUnsupportedDocumentType:
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+			Error: "File type is not allowed for this document",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid input",
+			Details: "incidents cannot occur in the future",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
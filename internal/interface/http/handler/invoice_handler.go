@@ -0,0 +1,514 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/interface/http/middleware"
+	"turivo-backend/internal/usecase"
+)
+
+type InvoiceHandler struct {
+	invoiceUseCase *usecase.InvoiceUseCase
+	validator      *validator.Validate
+	logger         *zap.Logger
+}
+
+func NewInvoiceHandler(invoiceUseCase *usecase.InvoiceUseCase, validator *validator.Validate, logger *zap.Logger) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceUseCase: invoiceUseCase,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// GetBillingAccount godoc
+// @Summary Get a company's billing account
+// @Description Postpaid billing settings of a company (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Success 200 {object} domain.BillingAccount
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/billing-account [get]
+func (h *InvoiceHandler) GetBillingAccount(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	account, err := h.invoiceUseCase.GetBillingAccount(companyID)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to get billing account")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// UpsertBillingAccount godoc
+// @Summary Open or update a company's billing account
+// @Description Make a company postpaid: its completed trips accrue to a monthly period that is invoiced when it ends. Unset tax rate and payment terms take the configured defaults (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Param request body domain.UpsertBillingAccountRequest true "Billing account"
+// @Success 200 {object} domain.BillingAccount
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/billing-account [put]
+func (h *InvoiceHandler) UpsertBillingAccount(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpsertBillingAccountRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	account, err := h.invoiceUseCase.UpsertBillingAccount(companyID, req)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to save billing account")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// GetBillingPeriod godoc
+// @Summary Get a company's open billing period
+// @Description Open period of a company's billing account with the trips accrued so far (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Success 200 {object} domain.BillingPeriod
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/billing-account/period [get]
+func (h *InvoiceHandler) GetBillingPeriod(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	h.getBillingPeriod(c, companyID)
+}
+
+// CloseBillingPeriod godoc
+// @Summary Invoice a company's open period now
+// @Description Close the open billing period into an invoice before the end of the month; the rest of the month becomes a new period (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Success 201 {object} domain.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/billing-account/close-period [post]
+func (h *InvoiceHandler) CloseBillingPeriod(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceUseCase.CloseCurrentPeriod(c.Request.Context(), companyID)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to close billing period")
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// ListInvoices godoc
+// @Summary List invoices
+// @Description List the invoices of postpaid companies, newest first (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param company_id query string false "Filter by company ID"
+// @Param status query string false "Filter by status" Enums(ISSUED, PAID, OVERDUE, VOID)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices [get]
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	req, ok := parseInvoiceQuery(c)
+	if !ok {
+		return
+	}
+
+	if value := c.Query("company_id"); value != "" {
+		companyID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid company ID",
+			})
+			return
+		}
+		req.CompanyID = &companyID
+	}
+
+	h.listInvoices(c, req)
+}
+
+// GetInvoice godoc
+// @Summary Get an invoice
+// @Description Get an invoice with its lines (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} domain.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/{id} [get]
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	h.getInvoice(c, nil)
+}
+
+// VoidInvoice godoc
+// @Summary Void an invoice
+// @Description Cancel an unpaid invoice. Its trips are not billed again (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param request body domain.VoidInvoiceRequest true "Reason"
+// @Success 200 {object} domain.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/{id}/void [post]
+func (h *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid invoice ID")
+	if !ok {
+		return
+	}
+
+	var req domain.VoidInvoiceRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	invoice, err := h.invoiceUseCase.VoidInvoice(id, req)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to void invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// DownloadInvoicePDF godoc
+// @Summary Download an invoice
+// @Description Download an invoice as PDF (Admin only)
+// @Tags admin
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/{id}/pdf [get]
+func (h *InvoiceHandler) DownloadInvoicePDF(c *gin.Context) {
+	h.downloadInvoicePDF(c, nil)
+}
+
+// GetCompanyInvoices godoc
+// @Summary List my company's invoices
+// @Description Invoices of the user's organization, newest first
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(ISSUED, PAID, OVERDUE, VOID)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/invoices [get]
+func (h *InvoiceHandler) GetCompanyInvoices(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := parseInvoiceQuery(c)
+	if !ok {
+		return
+	}
+	req.CompanyID = &companyID
+
+	h.listInvoices(c, req)
+}
+
+// GetCompanyInvoice godoc
+// @Summary Get one of my company's invoices
+// @Description Get an invoice of the user's organization with its lines. Pay it with POST /payments and its invoice_id.
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} domain.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/invoices/{id} [get]
+func (h *InvoiceHandler) GetCompanyInvoice(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	h.getInvoice(c, &companyID)
+}
+
+// DownloadCompanyInvoicePDF godoc
+// @Summary Download one of my company's invoices
+// @Description Download an invoice of the user's organization as PDF
+// @Tags billing
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/invoices/{id}/pdf [get]
+func (h *InvoiceHandler) DownloadCompanyInvoicePDF(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	h.downloadInvoicePDF(c, &companyID)
+}
+
+// GetCompanyBillingPeriod godoc
+// @Summary Get my company's open billing period
+// @Description Trips of the user's organization accrued so far to the period that will be invoiced next
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.BillingPeriod
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/billing-period [get]
+func (h *InvoiceHandler) GetCompanyBillingPeriod(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	h.getBillingPeriod(c, companyID)
+}
+
+func (h *InvoiceHandler) getBillingPeriod(c *gin.Context, companyID uuid.UUID) {
+	period, err := h.invoiceUseCase.GetCurrentPeriod(companyID)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to get billing period")
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func (h *InvoiceHandler) listInvoices(c *gin.Context, req domain.ListInvoicesRequest) {
+	invoices, total, err := h.invoiceUseCase.ListInvoices(req)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to list invoices")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       invoices,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *InvoiceHandler) getInvoice(c *gin.Context, companyID *uuid.UUID) {
+	id, ok := parseUUIDParam(c, "Invalid invoice ID")
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceUseCase.GetInvoice(id, companyID)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to get invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) downloadInvoicePDF(c *gin.Context, companyID *uuid.UUID) {
+	id, ok := parseUUIDParam(c, "Invalid invoice ID")
+	if !ok {
+		return
+	}
+
+	invoice, company, err := h.invoiceUseCase.GetInvoiceDocument(id, companyID)
+	if err != nil {
+		h.respondInvoiceError(c, err, "Failed to get invoice")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="factura-%s.pdf"`, invoice.Number))
+	c.Data(http.StatusOK, "application/pdf", report.InvoicePDF(invoice, company))
+}
+
+func (h *InvoiceHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *InvoiceHandler) respondInvoiceError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrCompanyNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Company not found",
+		})
+	case domain.ErrBillingAccountNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Company has no billing account",
+		})
+	case domain.ErrInvoiceNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Invoice not found",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Only issued or overdue invoices can be voided",
+		})
+	case domain.ErrNothingToInvoice:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "The open billing period has no trips to invoice",
+		})
+	case domain.ErrBillingAccountCurrency:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Billing accounts can only be held in the pricing currency",
+		})
+	case domain.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Billing account was changed by another request, try again",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
+
+// parseInvoiceQuery reads the status filter and pagination shared by the
+// invoice listings.
+func parseInvoiceQuery(c *gin.Context) (domain.ListInvoicesRequest, bool) {
+	req := domain.ListInvoicesRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if value := c.Query("status"); value != "" {
+		status := domain.InvoiceStatus(value)
+		switch status {
+		case domain.InvoiceStatusIssued, domain.InvoiceStatusPaid, domain.InvoiceStatusOverdue, domain.InvoiceStatusVoid:
+			req.Status = &status
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid status",
+				Details: "status must be ISSUED, PAID, OVERDUE or VOID",
+			})
+			return req, false
+		}
+	}
+
+	return req, true
+}
+
+// currentCompanyID returns the organization of a company user.
+func currentCompanyID(c *gin.Context) (uuid.UUID, bool) {
+	orgID, ok := middleware.GetOrgID(c)
+	if !ok || orgID == nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "User must belong to an organization",
+		})
+		return uuid.Nil, false
+	}
+
+	return *orgID, true
+}
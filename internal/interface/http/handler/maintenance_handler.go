@@ -0,0 +1,561 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/usecase"
+)
+
+type MaintenanceHandler struct {
+	maintenanceUseCase *usecase.MaintenanceUseCase
+	validator          *validator.Validate
+	maxUploadSize      int64
+	logger             *zap.Logger
+}
+
+func NewMaintenanceHandler(maintenanceUseCase *usecase.MaintenanceUseCase, validator *validator.Validate, maxUploadSize int64, logger *zap.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceUseCase: maintenanceUseCase,
+		validator:          validator,
+		maxUploadSize:      maxUploadSize,
+		logger:             logger,
+	}
+}
+
+// CreateVehicleMaintenance godoc
+// @Summary Record or schedule vehicle maintenance
+// @Description Record a service already performed (performed_at) or schedule a maintenance window (scheduled_start/scheduled_end). During a window the vehicle is set to MAINTENANCE and cannot be assigned (Admin only)
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param request body domain.CreateMaintenanceRecordRequest true "Maintenance record"
+// @Success 201 {object} domain.MaintenanceRecord
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/maintenance [post]
+func (h *MaintenanceHandler) CreateVehicleMaintenance(c *gin.Context) {
+	var req domain.CreateMaintenanceRecordRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	record, err := h.maintenanceUseCase.CreateRecord(c.Param("id"), req, userID)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to create maintenance record")
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// ListVehicleMaintenance godoc
+// @Summary List vehicle maintenance
+// @Description List the service history and scheduled windows of a vehicle (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param status query string false "Filter by status" Enums(SCHEDULED, IN_PROGRESS, COMPLETED, CANCELLED)
+// @Param service_type query string false "Filter by service type" Enums(OIL_CHANGE, TIRES, BRAKES, GENERAL_SERVICE, INSPECTION, REPAIR, OTHER)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/maintenance [get]
+func (h *MaintenanceHandler) ListVehicleMaintenance(c *gin.Context) {
+	vehicleID := c.Param("id")
+	req, ok := h.parseListRequest(c)
+	if !ok {
+		return
+	}
+	req.VehicleID = &vehicleID
+
+	h.listRecords(c, req)
+}
+
+// CreateMaintenancePlan godoc
+// @Summary Create a maintenance plan
+// @Description Repeat a service every interval_km kilometres and/or interval_days days. Fleet managers are reminded when it falls due (Admin only)
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param request body domain.CreateMaintenancePlanRequest true "Maintenance plan"
+// @Success 201 {object} domain.MaintenancePlan
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/maintenance-plans [post]
+func (h *MaintenanceHandler) CreateMaintenancePlan(c *gin.Context) {
+	var req domain.CreateMaintenancePlanRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.maintenanceUseCase.CreatePlan(c.Param("id"), req, userID)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to create maintenance plan")
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+// ListVehicleMaintenancePlans godoc
+// @Summary List vehicle maintenance plans
+// @Description List the maintenance plans of a vehicle with their next due date and odometer (Admin only)
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param active query bool false "Only active plans"
+// @Success 200 {array} domain.MaintenancePlan
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/maintenance-plans [get]
+func (h *MaintenanceHandler) ListVehicleMaintenancePlans(c *gin.Context) {
+	vehicleID := c.Param("id")
+	h.listPlans(c, &vehicleID)
+}
+
+// GetVehicleMaintenanceCosts godoc
+// @Summary Get vehicle maintenance costs
+// @Description Total the completed maintenance of a vehicle by service type (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags vehicles
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} domain.MaintenanceCostReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/maintenance-costs [get]
+func (h *MaintenanceHandler) GetVehicleMaintenanceCosts(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	costReport, err := h.maintenanceUseCase.GetCostReport(c.Param("id"), from, to)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to get maintenance costs")
+		return
+	}
+
+	h.respondCostReports(c, []*domain.MaintenanceCostReport{costReport}, costReport, "mantenciones-"+costReport.VehicleID)
+}
+
+// ListMaintenance godoc
+// @Summary List maintenance records
+// @Description List maintenance records across the fleet (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param vehicle_id query string false "Filter by vehicle ID"
+// @Param plan_id query string false "Filter by maintenance plan ID"
+// @Param status query string false "Filter by status" Enums(SCHEDULED, IN_PROGRESS, COMPLETED, CANCELLED)
+// @Param service_type query string false "Filter by service type" Enums(OIL_CHANGE, TIRES, BRAKES, GENERAL_SERVICE, INSPECTION, REPAIR, OTHER)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance [get]
+func (h *MaintenanceHandler) ListMaintenance(c *gin.Context) {
+	req, ok := h.parseListRequest(c)
+	if !ok {
+		return
+	}
+
+	if vehicleID := c.Query("vehicle_id"); vehicleID != "" {
+		req.VehicleID = &vehicleID
+	}
+	if planID := c.Query("plan_id"); planID != "" {
+		id, err := uuid.Parse(planID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid maintenance plan ID",
+			})
+			return
+		}
+		req.PlanID = &id
+	}
+
+	h.listRecords(c, req)
+}
+
+// GetMaintenance godoc
+// @Summary Get a maintenance record
+// @Description Get a maintenance record with its attachments (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Maintenance record ID"
+// @Success 200 {object} domain.MaintenanceRecord
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance/{id} [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	recordID, ok := parseUUIDParam(c, "Invalid maintenance record ID")
+	if !ok {
+		return
+	}
+
+	record, err := h.maintenanceUseCase.GetRecord(recordID)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to get maintenance record")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// UpdateMaintenance godoc
+// @Summary Update a maintenance record
+// @Description Correct a maintenance record or move it to IN_PROGRESS, COMPLETED or CANCELLED. Starting a record sets the vehicle to MAINTENANCE; finishing it returns the vehicle to service (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Maintenance record ID"
+// @Param request body domain.UpdateMaintenanceRecordRequest true "Changes"
+// @Success 200 {object} domain.MaintenanceRecord
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance/{id} [patch]
+func (h *MaintenanceHandler) UpdateMaintenance(c *gin.Context) {
+	recordID, ok := parseUUIDParam(c, "Invalid maintenance record ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateMaintenanceRecordRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	record, err := h.maintenanceUseCase.UpdateRecord(recordID, req)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to update maintenance record")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// UploadMaintenanceAttachment godoc
+// @Summary Attach a file to a maintenance record
+// @Description Upload an invoice, quote or workshop report (JPEG, PNG, WebP or PDF) for a maintenance record (Admin only)
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Maintenance record ID"
+// @Param file formData file true "Attachment"
+// @Success 201 {object} domain.Document
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance/{id}/attachments [post]
+func (h *MaintenanceHandler) UploadMaintenanceAttachment(c *gin.Context) {
+	recordID, ok := parseUUIDParam(c, "Invalid maintenance record ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, fileName, ok := openUpload(c, h.maxUploadSize, h.logger)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	document, err := h.maintenanceUseCase.AddAttachment(recordID, fileName, file, userID)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to upload maintenance attachment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// ListMaintenancePlans godoc
+// @Summary List maintenance plans
+// @Description List maintenance plans across the fleet with their due state (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param active query bool false "Only active plans"
+// @Success 200 {array} domain.MaintenancePlan
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance-plans [get]
+func (h *MaintenanceHandler) ListMaintenancePlans(c *gin.Context) {
+	h.listPlans(c, nil)
+}
+
+// UpdateMaintenancePlan godoc
+// @Summary Update a maintenance plan
+// @Description Change the intervals or notes of a maintenance plan, or deactivate it (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Maintenance plan ID"
+// @Param request body domain.UpdateMaintenancePlanRequest true "Changes"
+// @Success 200 {object} domain.MaintenancePlan
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance-plans/{id} [patch]
+func (h *MaintenanceHandler) UpdateMaintenancePlan(c *gin.Context) {
+	planID, ok := parseUUIDParam(c, "Invalid maintenance plan ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateMaintenancePlanRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	plan, err := h.maintenanceUseCase.UpdatePlan(planID, req)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to update maintenance plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// ListMaintenanceCosts godoc
+// @Summary List maintenance costs
+// @Description Total the completed maintenance of each vehicle by service type, most expensive first (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {array} domain.MaintenanceCostReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/maintenance-costs [get]
+func (h *MaintenanceHandler) ListMaintenanceCosts(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.maintenanceUseCase.ListCostReports(from, to)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to list maintenance costs")
+		return
+	}
+
+	h.respondCostReports(c, reports, reports, "mantenciones-"+time.Now().Format("2006-01-02"))
+}
+
+func (h *MaintenanceHandler) parseListRequest(c *gin.Context) (domain.ListMaintenanceRecordsRequest, bool) {
+	req := domain.ListMaintenanceRecordsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if status := c.Query("status"); status != "" {
+		recordStatus := domain.MaintenanceStatus(status)
+		req.Status = &recordStatus
+	}
+	if serviceType := c.Query("service_type"); serviceType != "" {
+		recordType := domain.MaintenanceServiceType(serviceType)
+		req.ServiceType = &recordType
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return req, false
+	}
+	req.From, req.To = from, to
+
+	return req, true
+}
+
+func (h *MaintenanceHandler) listRecords(c *gin.Context, req domain.ListMaintenanceRecordsRequest) {
+	records, total, err := h.maintenanceUseCase.ListRecords(req)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to list maintenance records")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:     records,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Total:    total,
+	})
+}
+
+func (h *MaintenanceHandler) listPlans(c *gin.Context, vehicleID *string) {
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+
+	plans, err := h.maintenanceUseCase.ListPlans(vehicleID, activeOnly)
+	if err != nil {
+		h.respondMaintenanceError(c, err, "Failed to list maintenance plans")
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
+
+// respondCostReports writes the reports as CSV when requested, or body as
+// JSON otherwise.
+func (h *MaintenanceHandler) respondCostReports(c *gin.Context, reports []*domain.MaintenanceCostReport, body interface{}, fileName string) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, body)
+
+	case "csv":
+		content, err := report.MaintenanceCostCSV(reports)
+		if err != nil {
+			h.respondMaintenanceError(c, err, "Failed to export maintenance costs")
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid format",
+			Details: "format must be json or csv",
+		})
+	}
+}
+
+func (h *MaintenanceHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *MaintenanceHandler) respondMaintenanceError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrMaintenanceRecordNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Maintenance record not found",
+		})
+	case domain.ErrMaintenancePlanNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Maintenance plan not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Invalid status transition",
+			Details: "completed and cancelled maintenance records cannot change status",
+		})
+	case domain.ErrDocumentTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: "File exceeds the maximum upload size",
+		})
+	case domain.ErrUnsupportedDocumentType:
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+			Error: "File type is not allowed for this document",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid input",
+			Details: "windows must end after they start and in the future; services cannot be performed in the future",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"turivo-backend/internal/domain"
+	"turivo-backend/internal/interface/http/middleware"
 	"turivo-backend/internal/usecase"
 )
 
@@ -28,16 +29,19 @@ func NewPaymentHandler(paymentUseCase *usecase.PaymentUseCase, validator *valida
 
 // CreatePayment godoc
 // @Summary Create payment
-// @Description Create a new payment for a reservation
+// @Description Create a new payment for a reservation or, with invoice_id, for an issued invoice of a postpaid company. COMPANY_CREDIT charges the reservation to the company, debited from its wallet when it has one
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body domain.CreatePaymentRequest true "Payment data"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
 // @Success 201 {object} domain.Payment
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/payments [post]
 func (h *PaymentHandler) CreatePayment(c *gin.Context) {
@@ -67,10 +71,34 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 			c.JSON(http.StatusNotFound, ErrorResponse{
 				Error: "Reservation not found",
 			})
+		case domain.ErrInvoiceNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Invoice not found",
+			})
 		case domain.ErrPaymentAlreadyPaid:
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Reservation already has approved payment",
 			})
+		case domain.ErrInvoiceNotPayable:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invoice is already paid or void",
+			})
+		case domain.ErrPaymentMethodUnavailable:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Payment method is not available for this reservation or invoice",
+			})
+		case domain.ErrInsufficientCredit:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Company balance and credit line do not cover this reservation",
+			})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Reservation has no amount to pay",
+			})
+		case domain.ErrPaymentFailed:
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error: "Payment gateway error",
+			})
 		default:
 			h.logger.Error("Failed to create payment", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -181,6 +209,10 @@ func (h *PaymentHandler) SimulatePayment(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Payment already processed",
 			})
+		case domain.ErrPaymentOperationUnsupported:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Payment gateway does not support simulation",
+			})
 		default:
 			h.logger.Error("Failed to simulate payment", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -193,3 +225,373 @@ func (h *PaymentHandler) SimulatePayment(c *gin.Context) {
 	c.JSON(http.StatusOK, payment)
 }
 
+// GetPaymentGatewayStatus godoc
+// @Summary Get payment gateway status
+// @Description Ask the payment gateway for the current state of the payment's transaction
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.PaymentResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/{id}/gateway-status [get]
+func (h *PaymentHandler) GetPaymentGatewayStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid payment ID",
+		})
+		return
+	}
+
+	result, err := h.paymentUseCase.GetGatewayStatus(id)
+	if err != nil {
+		switch err {
+		case domain.ErrPaymentNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Payment not found",
+			})
+		case domain.ErrPaymentOperationUnsupported, domain.ErrPaymentMethodUnavailable:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Payment gateway does not support status checks",
+			})
+		case domain.ErrPaymentFailed:
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error: "Payment gateway error",
+			})
+		default:
+			h.logger.Error("Failed to get payment gateway status", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RefundPayment godoc
+// @Summary Refund payment
+// @Description Refund part or all of an approved payment through its gateway. Without an amount, everything still refundable is returned.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body domain.CreateRefundRequest true "Refund data"
+// @Success 201 {object} domain.Refund
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/{id}/refunds [post]
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body for refund payment", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		h.logger.Warn("Validation failed for refund payment", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	refund, err := h.paymentUseCase.RefundPayment(id, req, userID)
+	if err != nil {
+		h.respondRefundError(c, err, "Failed to refund payment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, refund)
+}
+
+// GetPaymentRefunds godoc
+// @Summary List payment refunds
+// @Description List the refunds of a payment with the captured, refunded and still refundable amounts
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.PaymentRefunds
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/{id}/refunds [get]
+func (h *PaymentHandler) GetPaymentRefunds(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	refunds, err := h.paymentUseCase.GetPaymentRefunds(id)
+	if err != nil {
+		h.respondRefundError(c, err, "Failed to get payment refunds")
+		return
+	}
+
+	c.JSON(http.StatusOK, refunds)
+}
+
+func (h *PaymentHandler) respondRefundError(c *gin.Context, err error, logMessage string) {
+	switch err {
+	case domain.ErrPaymentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Payment not found",
+		})
+	case domain.ErrPaymentNotRefundable:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Only approved payments can be refunded",
+		})
+	case domain.ErrRefundExceedsPayment:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Refund exceeds the refundable amount",
+		})
+	case domain.ErrPaymentOperationUnsupported, domain.ErrPaymentMethodUnavailable:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Payment gateway does not support refunds",
+		})
+	case domain.ErrPaymentFailed:
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error: "Payment gateway refused the refund",
+		})
+	default:
+		h.logger.Error(logMessage, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+// ListPaymentMethods godoc
+// @Summary List payment methods
+// @Description List the methods a reservation can be paid with, given the configured gateways, the currency and its company's settings
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reservation_id query string true "Reservation ID"
+// @Success 200 {object} domain.PaymentMethods
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/methods [get]
+func (h *PaymentHandler) ListPaymentMethods(c *gin.Context) {
+	reservationID := c.Query("reservation_id")
+	if reservationID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "reservation_id is required",
+		})
+		return
+	}
+
+	methods, err := h.paymentUseCase.ListPaymentMethods(reservationID)
+	if err != nil {
+		switch err {
+		case domain.ErrReservationNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Reservation not found",
+			})
+		default:
+			h.logger.Error("Failed to list payment methods", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, methods)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirm payment
+// @Description Settle a pending payment from the back office: approve a bank transfer once the money arrived, or check a redirect payment with its gateway
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/{id}/confirm [post]
+func (h *PaymentHandler) ConfirmPayment(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentUseCase.ConfirmPayment(id)
+	if err != nil {
+		switch err {
+		case domain.ErrPaymentNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Payment not found",
+			})
+		case domain.ErrPaymentAlreadyPaid:
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error: "Only pending payments can be confirmed",
+			})
+		case domain.ErrPaymentOperationUnsupported, domain.ErrPaymentMethodUnavailable:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Payment gateway does not support confirmations",
+			})
+		case domain.ErrPaymentFailed:
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error: "Payment gateway error",
+			})
+		default:
+			h.logger.Error("Failed to confirm payment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// GetCompanyPaymentMethods godoc
+// @Summary Get company payment methods
+// @Description List the payment methods a company enabled or disabled per currency. Currencies without settings offer every configured method except company credit.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Success 200 {array} domain.CompanyPaymentMethod
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/payment-methods [get]
+func (h *PaymentHandler) GetCompanyPaymentMethods(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	// Company users only see their own company
+	if role, _ := middleware.GetUserRole(c); role != domain.UserRoleAdmin {
+		if orgID, _ := middleware.GetScopeOrgID(c); orgID == nil || *orgID != companyID {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error: "Access denied to this company",
+			})
+			return
+		}
+	}
+
+	methods, err := h.paymentUseCase.GetCompanyPaymentMethods(companyID)
+	if err != nil {
+		h.logger.Error("Failed to get company payment methods", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, methods)
+}
+
+// UpdateCompanyPaymentMethods godoc
+// @Summary Update company payment methods
+// @Description Replace the payment method settings of a company. Once a currency has settings, only the methods enabled for it are offered.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Param request body domain.UpdateCompanyPaymentMethodsRequest true "Payment method settings"
+// @Success 200 {array} domain.CompanyPaymentMethod
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/payment-methods [put]
+func (h *PaymentHandler) UpdateCompanyPaymentMethods(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateCompanyPaymentMethodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body for company payment methods", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		h.logger.Warn("Validation failed for company payment methods", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	methods, err := h.paymentUseCase.UpdateCompanyPaymentMethods(companyID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Each method can only be set once per currency",
+			})
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Company not found",
+			})
+		default:
+			h.logger.Error("Failed to update company payment methods", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, methods)
+}
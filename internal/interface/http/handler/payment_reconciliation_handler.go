@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// maxNotificationBodyBytes caps the webhook bodies read; gateway events are
+// a few kilobytes.
+const maxNotificationBodyBytes = 1 << 20
+
+// PaymentReconciliationHandler receives gateway webhooks and serves the
+// reconciliation of payments against their gateways.
+type PaymentReconciliationHandler struct {
+	reconciliationUseCase *usecase.PaymentReconciliationUseCase
+	logger                *zap.Logger
+}
+
+func NewPaymentReconciliationHandler(reconciliationUseCase *usecase.PaymentReconciliationUseCase, logger *zap.Logger) *PaymentReconciliationHandler {
+	return &PaymentReconciliationHandler{
+		reconciliationUseCase: reconciliationUseCase,
+		logger:                logger,
+	}
+}
+
+// MercadoPagoNotification godoc
+// @Summary Mercado Pago webhook
+// @Description Receive a Mercado Pago payment notification signed with x-signature and settle the payment it refers to. Repeated events are acknowledged without being processed again.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param x-signature header string true "ts=...,v1=... signature"
+// @Param x-request-id header string true "Notification request ID"
+// @Param data.id query string false "Notified resource ID"
+// @Success 200 {object} domain.PaymentNotification
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/payments/mercadopago/notifications [post]
+func (h *PaymentReconciliationHandler) MercadoPagoNotification(c *gin.Context) {
+	h.handleNotification(c, domain.PaymentGatewayMercadoPago)
+}
+
+// StripeNotification godoc
+// @Summary Stripe webhook
+// @Description Receive a Stripe event signed with Stripe-Signature and settle the Checkout session payment it refers to. Repeated events are acknowledged without being processed again.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param Stripe-Signature header string true "t=...,v1=... signature"
+// @Success 200 {object} domain.PaymentNotification
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/payments/stripe/notifications [post]
+func (h *PaymentReconciliationHandler) StripeNotification(c *gin.Context) {
+	h.handleNotification(c, domain.PaymentGatewayStripe)
+}
+
+// handleNotification answers 2xx only once the notification is handled, so
+// the gateway retries the ones that failed.
+func (h *PaymentReconciliationHandler) handleNotification(c *gin.Context, gateway domain.PaymentGateway) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxNotificationBodyBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request body",
+		})
+		return
+	}
+
+	notification, err := h.reconciliationUseCase.HandleNotification(gateway, domain.PaymentNotificationRequest{
+		Header: c.Request.Header,
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+	})
+	if err != nil {
+		switch err {
+		case domain.ErrAlreadyExists:
+			c.Status(http.StatusNoContent)
+		case domain.ErrInvalidSignature:
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error: "Invalid notification signature",
+			})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid notification",
+			})
+		case domain.ErrPaymentMethodUnavailable, domain.ErrPaymentOperationUnsupported:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Payment gateway is not configured",
+			})
+		case domain.ErrPaymentFailed:
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error: "Payment gateway error",
+			})
+		default:
+			h.logger.Error("Failed to handle payment notification", zap.Error(err), zap.String("gateway", string(gateway)))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// ReconcilePendingPayments godoc
+// @Summary Reconcile pending payments
+// @Description Check the payments left pending with their gateways now instead of waiting for the scheduled job. Settled payments take the gateway status; those pending past the abandon delay are rejected.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.PaymentReconciliationRun
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/reconcile [post]
+func (h *PaymentReconciliationHandler) ReconcilePendingPayments(c *gin.Context) {
+	run, err := h.reconciliationUseCase.ReconcilePendingPayments(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to reconcile pending payments", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GetReconciliationReport godoc
+// @Summary Payment reconciliation report
+// @Description Compare the payments created between two days with what their gateways report and list the status and amount mismatches. Covers at most 31 days; payments of gateways that cannot be queried are skipped.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, inclusive (YYYY-MM-DD)"
+// @Param gateway query string false "Only payments of this gateway"
+// @Success 200 {object} domain.PaymentReconciliationReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/payments/reconciliation [get]
+func (h *PaymentReconciliationHandler) GetReconciliationReport(c *gin.Context) {
+	var days [2]time.Time
+	for i, param := range []string{"from", "to"} {
+		day, err := time.ParseInLocation("2006-01-02", c.Query(param), time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Details: param + " must be YYYY-MM-DD",
+			})
+			return
+		}
+		days[i] = day
+	}
+
+	var gateway *domain.PaymentGateway
+	if value := c.Query("gateway"); value != "" {
+		parsed := domain.PaymentGateway(value)
+		gateway = &parsed
+	}
+
+	report, err := h.reconciliationUseCase.GetReconciliationReport(c.Request.Context(), days[0], days[1].AddDate(0, 0, 1), gateway)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid period",
+				Details: "to must not be before from and the period must not exceed 31 days",
+			})
+		default:
+			h.logger.Error("Failed to build payment reconciliation report", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// PaymentFinalURLs are the frontend pages customers end on after paying at
+// each gateway; without one the result is returned as JSON.
+type PaymentFinalURLs struct {
+	Webpay      string
+	MercadoPago string
+	Stripe      string
+}
+
+// PaymentReturnHandler receives customers coming back from the payment pages
+// of the redirect gateways.
+type PaymentReturnHandler struct {
+	paymentUseCase *usecase.PaymentUseCase
+	finalURLs      PaymentFinalURLs
+	logger         *zap.Logger
+}
+
+func NewPaymentReturnHandler(paymentUseCase *usecase.PaymentUseCase, finalURLs PaymentFinalURLs, logger *zap.Logger) *PaymentReturnHandler {
+	return &PaymentReturnHandler{
+		paymentUseCase: paymentUseCase,
+		finalURLs:      finalURLs,
+		logger:         logger,
+	}
+}
+
+// WebpayReturn godoc
+// @Summary Webpay return URL
+// @Description Commit the Webpay transaction the customer completed (token_ws), or reject the payment they aborted (TBK_TOKEN) or let time out (TBK_ORDEN_COMPRA only). Redirects to the configured final URL with payment_id and status when set.
+// @Tags payments
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token_ws query string false "Transaction token of a completed form"
+// @Param TBK_TOKEN query string false "Transaction token of an aborted payment"
+// @Param TBK_ORDEN_COMPRA query string false "Buy order of an aborted or timed out payment"
+// @Param TBK_ID_SESION query string false "Session ID of an aborted or timed out payment"
+// @Success 200 {object} domain.Payment
+// @Success 303
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/payments/webpay/return [get]
+// @Router /api/v1/public/payments/webpay/return [post]
+func (h *PaymentReturnHandler) WebpayReturn(c *gin.Context) {
+	token := c.Request.FormValue("token_ws")
+	abortToken := c.Request.FormValue("TBK_TOKEN")
+	buyOrder := c.Request.FormValue("TBK_ORDEN_COMPRA")
+	sessionID := c.Request.FormValue("TBK_ID_SESION")
+
+	var payment *domain.Payment
+	var err error
+	switch {
+	case buyOrder != "":
+		// Aborted or timed out: Transbank sends the order and session back,
+		// plus TBK_TOKEN only when the customer cancelled
+		paymentID, parseErr := uuid.Parse(sessionID)
+		if parseErr != nil {
+			h.respondReturnError(c, h.finalURLs.Webpay, http.StatusBadRequest, "Invalid session ID", "")
+			return
+		}
+		payment, err = h.paymentUseCase.AbortPayment(paymentID, buyOrder, abortToken == "")
+	case token != "":
+		payment, err = h.paymentUseCase.CommitPayment(domain.PaymentGatewayWebpayPlus, token)
+	default:
+		h.respondReturnError(c, h.finalURLs.Webpay, http.StatusBadRequest, "Missing Webpay token", "")
+		return
+	}
+
+	h.respondReturn(c, h.finalURLs.Webpay, payment, err, sessionID)
+}
+
+// MercadoPagoReturn godoc
+// @Summary Mercado Pago return URL
+// @Description Settle the payment of the Mercado Pago preference the customer comes back from, whatever the outcome. Redirects to the configured final URL with payment_id and status when set.
+// @Tags payments
+// @Produce json
+// @Param preference_id query string true "Preference ID"
+// @Param external_reference query string false "Payment ID"
+// @Success 200 {object} domain.Payment
+// @Success 303
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/payments/mercadopago/return [get]
+func (h *PaymentReturnHandler) MercadoPagoReturn(c *gin.Context) {
+	preferenceID := c.Query("preference_id")
+	if preferenceID == "" {
+		h.respondReturnError(c, h.finalURLs.MercadoPago, http.StatusBadRequest, "Missing Mercado Pago preference", "")
+		return
+	}
+
+	payment, err := h.paymentUseCase.CommitPayment(domain.PaymentGatewayMercadoPago, preferenceID)
+	h.respondReturn(c, h.finalURLs.MercadoPago, payment, err, c.Query("external_reference"))
+}
+
+// StripeReturn godoc
+// @Summary Stripe return URL
+// @Description Settle the payment of the Stripe Checkout session the customer comes back from, paid or cancelled. Redirects to the configured final URL with payment_id and status when set.
+// @Tags payments
+// @Produce json
+// @Param session_id query string true "Checkout session ID"
+// @Success 200 {object} domain.Payment
+// @Success 303
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/payments/stripe/return [get]
+func (h *PaymentReturnHandler) StripeReturn(c *gin.Context) {
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		h.respondReturnError(c, h.finalURLs.Stripe, http.StatusBadRequest, "Missing Stripe session", "")
+		return
+	}
+
+	payment, err := h.paymentUseCase.CommitPayment(domain.PaymentGatewayStripe, sessionID)
+	h.respondReturn(c, h.finalURLs.Stripe, payment, err, "")
+}
+
+func (h *PaymentReturnHandler) respondReturn(c *gin.Context, finalURL string, payment *domain.Payment, err error, paymentID string) {
+	if err != nil {
+		switch err {
+		case domain.ErrPaymentNotFound:
+			h.respondReturnError(c, finalURL, http.StatusNotFound, "Payment not found", paymentID)
+		case domain.ErrPaymentFailed, domain.ErrPaymentOperationUnsupported, domain.ErrPaymentMethodUnavailable:
+			h.respondReturnError(c, finalURL, http.StatusBadGateway, "Payment could not be confirmed", paymentID)
+		default:
+			h.logger.Error("Failed to handle payment return", zap.Error(err))
+			h.respondReturnError(c, finalURL, http.StatusInternalServerError, "Internal server error", paymentID)
+		}
+		return
+	}
+
+	if finalURL == "" {
+		c.JSON(http.StatusOK, payment)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, finalRedirect(finalURL, payment.ID.String(), string(payment.Status)))
+}
+
+// respondReturnError sends the customer to the final URL with status ERROR
+// when one is configured, since they arrive here from the browser.
+func (h *PaymentReturnHandler) respondReturnError(c *gin.Context, finalURL string, status int, message, paymentID string) {
+	if finalURL == "" {
+		c.JSON(status, ErrorResponse{
+			Error: message,
+		})
+		return
+	}
+	c.Redirect(http.StatusSeeOther, finalRedirect(finalURL, paymentID, "ERROR"))
+}
+
+func finalRedirect(finalURL, paymentID, status string) string {
+	params := url.Values{}
+	if paymentID != "" {
+		params.Set("payment_id", paymentID)
+	}
+	params.Set("status", status)
+
+	separator := "?"
+	if strings.Contains(finalURL, "?") {
+		separator = "&"
+	}
+	return finalURL + separator + params.Encode()
+}
@@ -17,13 +17,15 @@ import (
 
 type ReservationHandler struct {
 	reservationUseCase *usecase.ReservationUseCase
+	capacityUseCase    *usecase.VehicleCapacityUseCase
 	validator          *validator.Validate
 	logger             *zap.Logger
 }
 
-func NewReservationHandler(reservationUseCase *usecase.ReservationUseCase, validator *validator.Validate, logger *zap.Logger) *ReservationHandler {
+func NewReservationHandler(reservationUseCase *usecase.ReservationUseCase, capacityUseCase *usecase.VehicleCapacityUseCase, validator *validator.Validate, logger *zap.Logger) *ReservationHandler {
 	return &ReservationHandler{
 		reservationUseCase: reservationUseCase,
+		capacityUseCase:    capacityUseCase,
 		validator:          validator,
 		logger:             logger,
 	}
@@ -32,7 +34,7 @@ func NewReservationHandler(reservationUseCase *usecase.ReservationUseCase, valid
 // CreateReservationRequest extends the domain request with pricing parameters
 type CreateReservationRequest struct {
 	domain.CreateReservationRequest
-	VehicleType        domain.VehicleType `json:"vehicle_type" validate:"required"`
+	VehicleType        domain.VehicleType `json:"vehicle_type" validate:"required,oneof=BUS VAN SEDAN SUV"`
 	HasSpecialLanguage bool               `json:"has_special_language"`
 	Stops              int                `json:"stops" validate:"min=0"`
 }
@@ -137,9 +139,12 @@ func (h *ReservationHandler) ListReservations(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body CreateReservationRequest true "Reservation data"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
 // @Success 201 {object} domain.Reservation
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/reservations [post]
 func (h *ReservationHandler) CreateReservation(c *gin.Context) {
@@ -196,6 +201,16 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Reservation date cannot be in the past",
 			})
+		case domain.ErrNoVehicleCapacity:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "No vehicle of the requested type can carry all passengers",
+				Details: "Use /reservations/vehicle-split to spread the group over several vehicles",
+			})
+		case domain.ErrInsufficientCredit:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "Company balance and credit line do not cover this reservation",
+				Details: "Top up the company wallet or ask for a higher credit limit",
+			})
 		default:
 			h.logger.Error("Failed to create reservation", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -208,6 +223,56 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 	c.JSON(http.StatusCreated, reservation)
 }
 
+// SuggestVehicleSplit godoc
+// @Summary Suggest a multi-vehicle split
+// @Description Suggest how to spread a large group over the vehicles in service, using as few vehicles as possible. Covered is false when the fleet cannot carry everyone.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param passengers query int true "Number of passengers"
+// @Param vehicle_type query string false "Restrict to a vehicle type" Enums(BUS,VAN,SEDAN,SUV)
+// @Success 200 {object} domain.VehicleSplit
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/vehicle-split [get]
+func (h *ReservationHandler) SuggestVehicleSplit(c *gin.Context) {
+	passengers, err := strconv.Atoi(c.Query("passengers"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid passengers",
+			Details: "passengers must be a number",
+		})
+		return
+	}
+
+	req := domain.VehicleSplitRequest{Passengers: passengers}
+	if vehicleType := c.Query("vehicle_type"); vehicleType != "" {
+		t := domain.VehicleType(vehicleType)
+		req.VehicleType = &t
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	split, err := h.capacityUseCase.SuggestSplit(req)
+	if err != nil {
+		h.logger.Error("Failed to suggest vehicle split", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, split)
+}
+
 // GetReservation godoc
 // @Summary Get reservation
 // @Description Get reservation by ID
@@ -399,6 +464,7 @@ func (h *ReservationHandler) ChangeReservationStatus(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ComplianceErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/reservations/{id}/driver [patch]
 func (h *ReservationHandler) AssignDriver(c *gin.Context) {
@@ -431,6 +497,9 @@ func (h *ReservationHandler) AssignDriver(c *gin.Context) {
 
 	reservation, err := h.reservationUseCase.AssignDriver(id, req.DriverID)
 	if err != nil {
+		if respondComplianceError(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrReservationNotFound:
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -444,6 +513,22 @@ func (h *ReservationHandler) AssignDriver(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Cannot assign driver to completed or cancelled reservation",
 			})
+		case domain.ErrOutsideShift:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Reservation is outside the driver's scheduled shift",
+			})
+		case domain.ErrVehicleInMaintenance:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Driver's vehicle is scheduled for maintenance",
+			})
+		case domain.ErrVehicleTypeMismatch:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Driver's vehicle is not of the booked type",
+			})
+		case domain.ErrVehicleTooSmall:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Driver's vehicle cannot carry all passengers",
+			})
 		default:
 			h.logger.Error("Failed to assign driver", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -0,0 +1,621 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/usecase"
+)
+
+// Default roster export window around today.
+const (
+	rosterDefaultPastDays   = 7
+	rosterDefaultFutureDays = 60
+)
+
+type ShiftHandler struct {
+	shiftUseCase  *usecase.ShiftUseCase
+	driverUseCase *usecase.DriverUseCase
+	validator     *validator.Validate
+	logger        *zap.Logger
+}
+
+func NewShiftHandler(shiftUseCase *usecase.ShiftUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *ShiftHandler {
+	return &ShiftHandler{
+		shiftUseCase:  shiftUseCase,
+		driverUseCase: driverUseCase,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// CreateShift godoc
+// @Summary Create a shift
+// @Description Schedule a shift for a driver, optionally with a vehicle and region (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.CreateShiftRequest true "Shift"
+// @Success 201 {object} domain.Shift
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shifts [post]
+func (h *ShiftHandler) CreateShift(c *gin.Context) {
+	var req domain.CreateShiftRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	shift, err := h.shiftUseCase.CreateShift(req, userID)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to create shift")
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+// ListShifts godoc
+// @Summary List shifts
+// @Description List shifts overlapping a date range (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param driver_id query string false "Filter by driver ID"
+// @Param vehicle_id query string false "Filter by vehicle ID"
+// @Param status query string false "Filter by status" Enums(SCHEDULED, CANCELLED)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shifts [get]
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	req := domain.ListShiftsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+	if vehicleID := c.Query("vehicle_id"); vehicleID != "" {
+		req.VehicleID = &vehicleID
+	}
+	if status := c.Query("status"); status != "" {
+		shiftStatus := domain.ShiftStatus(status)
+		req.Status = &shiftStatus
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	req.From, req.To = from, to
+
+	h.listShifts(c, req)
+}
+
+// GetShift godoc
+// @Summary Get a shift
+// @Description Get a shift by ID (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift ID"
+// @Success 200 {object} domain.Shift
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shifts/{id} [get]
+func (h *ShiftHandler) GetShift(c *gin.Context) {
+	shiftID, ok := parseUUIDParam(c, "Invalid shift ID")
+	if !ok {
+		return
+	}
+
+	shift, err := h.shiftUseCase.GetShift(shiftID)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to get shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// UpdateShift godoc
+// @Summary Update a shift
+// @Description Reschedule, reassign the vehicle of, or cancel a shift (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift ID"
+// @Param request body domain.UpdateShiftRequest true "Changes"
+// @Success 200 {object} domain.Shift
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shifts/{id} [patch]
+func (h *ShiftHandler) UpdateShift(c *gin.Context) {
+	shiftID, ok := parseUUIDParam(c, "Invalid shift ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateShiftRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	shift, err := h.shiftUseCase.UpdateShift(shiftID, req)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to update shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// GetDriverRoster godoc
+// @Summary Export a driver's roster
+// @Description Download a driver's shifts as an iCalendar file (Admin only). Defaults to the last week and next 60 days.
+// @Tags drivers
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/roster.ics [get]
+func (h *ShiftHandler) GetDriverRoster(c *gin.Context) {
+	h.exportRoster(c, c.Param("id"))
+}
+
+// ListShiftRequests godoc
+// @Summary List shift requests
+// @Description List swap and time-off requests (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param driver_id query string false "Filter by requesting or target driver ID"
+// @Param type query string false "Filter by type" Enums(SWAP, TIME_OFF)
+// @Param status query string false "Filter by status" Enums(PENDING, APPROVED, REJECTED, CANCELLED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shift-requests [get]
+func (h *ShiftHandler) ListShiftRequests(c *gin.Context) {
+	req := domain.ListShiftChangesRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if driverID := c.Query("driver_id"); driverID != "" {
+		req.DriverID = &driverID
+	}
+	if changeType := c.Query("type"); changeType != "" {
+		shiftChangeType := domain.ShiftChangeType(changeType)
+		req.Type = &shiftChangeType
+	}
+	if status := c.Query("status"); status != "" {
+		shiftChangeStatus := domain.ShiftChangeStatus(status)
+		req.Status = &shiftChangeStatus
+	}
+
+	h.listShiftRequests(c, req)
+}
+
+// ReviewShiftRequest godoc
+// @Summary Review a shift request
+// @Description Approve or reject a pending swap or time-off request (Admin only). Approved swaps move the shifts; approved time off cancels the driver's shifts in the period.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift request ID"
+// @Param request body domain.ReviewShiftChangeRequest true "Decision"
+// @Success 200 {object} domain.ShiftChangeRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/shift-requests/{id} [patch]
+func (h *ShiftHandler) ReviewShiftRequest(c *gin.Context) {
+	requestID, ok := parseUUIDParam(c, "Invalid shift request ID")
+	if !ok {
+		return
+	}
+
+	var req domain.ReviewShiftChangeRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.shiftUseCase.ReviewChange(requestID, req, userID)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to review shift request")
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// GetMyShifts godoc
+// @Summary List my shifts
+// @Description List the shifts of the authenticated driver. Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(SCHEDULED, CANCELLED)
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/shifts [get]
+func (h *ShiftHandler) GetMyShifts(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	req := domain.ListShiftsRequest{DriverID: &driver.ID}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if status := c.Query("status"); status != "" {
+		shiftStatus := domain.ShiftStatus(status)
+		req.Status = &shiftStatus
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	req.From, req.To = from, to
+
+	h.listShifts(c, req)
+}
+
+// GetMyRoster godoc
+// @Summary Export my roster
+// @Description Download the authenticated driver's shifts as an iCalendar file. Defaults to the last week and next 60 days.
+// @Tags Driver
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/roster.ics [get]
+func (h *ShiftHandler) GetMyRoster(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	h.exportRoster(c, driver.ID)
+}
+
+// SubmitShiftRequest godoc
+// @Summary Request a shift swap or time off
+// @Description Ask to hand one of your shifts to another driver (optionally taking one of theirs), or to take time off. Requests need admin approval.
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.SubmitShiftChangeRequest true "Request"
+// @Success 201 {object} domain.ShiftChangeRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/shift-requests [post]
+func (h *ShiftHandler) SubmitShiftRequest(c *gin.Context) {
+	var req domain.SubmitShiftChangeRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.shiftUseCase.SubmitChange(driver.ID, req)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to submit shift request")
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// GetMyShiftRequests godoc
+// @Summary List my shift requests
+// @Description List swap and time-off requests raised by or addressed to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(PENDING, APPROVED, REJECTED, CANCELLED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/shift-requests [get]
+func (h *ShiftHandler) GetMyShiftRequests(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	req := domain.ListShiftChangesRequest{DriverID: &driver.ID}
+	req.Page, req.PageSize = parsePagination(c)
+
+	if status := c.Query("status"); status != "" {
+		shiftChangeStatus := domain.ShiftChangeStatus(status)
+		req.Status = &shiftChangeStatus
+	}
+
+	h.listShiftRequests(c, req)
+}
+
+// CancelMyShiftRequest godoc
+// @Summary Cancel my shift request
+// @Description Withdraw a pending swap or time-off request
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift request ID"
+// @Success 200 {object} domain.ShiftChangeRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/shift-requests/{id} [delete]
+func (h *ShiftHandler) CancelMyShiftRequest(c *gin.Context) {
+	requestID, ok := parseUUIDParam(c, "Invalid shift request ID")
+	if !ok {
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	change, err := h.shiftUseCase.CancelChange(requestID, driver.ID)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to cancel shift request")
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+func (h *ShiftHandler) exportRoster(c *gin.Context, driverID string) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if from == nil {
+		start := today.AddDate(0, 0, -rosterDefaultPastDays)
+		from = &start
+	}
+	if to == nil {
+		end := today.AddDate(0, 0, rosterDefaultFutureDays)
+		to = &end
+	}
+
+	driver, shifts, err := h.shiftUseCase.GetRoster(driverID, *from, *to)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to export roster")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="turnos-%s.ics"`, driver.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", report.RosterICS(driver, shifts, now))
+}
+
+func (h *ShiftHandler) listShifts(c *gin.Context, req domain.ListShiftsRequest) {
+	shifts, total, err := h.shiftUseCase.ListShifts(req)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to list shifts")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       shifts,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *ShiftHandler) listShiftRequests(c *gin.Context, req domain.ListShiftChangesRequest) {
+	changes, total, err := h.shiftUseCase.ListChanges(req)
+	if err != nil {
+		h.respondShiftError(c, err, "Failed to list shift requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       changes,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+func (h *ShiftHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *ShiftHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *ShiftHandler) respondShiftError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrShiftNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Shift not found",
+		})
+	case domain.ErrShiftRequestNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Shift request not found",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrShiftConflict:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift overlaps an existing shift or approved time off",
+		})
+	case domain.ErrShiftRequestAlreadyReviewed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift request has already been reviewed",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid input",
+			Details: "shifts must end after they start and last at most 24 hours; swaps only apply to upcoming scheduled shifts",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
+
+// parseDateRange reads optional from/to query dates. "to" is an inclusive
+// calendar day.
+func parseDateRange(c *gin.Context) (*time.Time, *time.Time, bool) {
+	var from, to *time.Time
+	for _, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Details: param + " must be YYYY-MM-DD",
+			})
+			return nil, nil, false
+		}
+		if param == "from" {
+			from = &day
+		} else {
+			next := day.AddDate(0, 0, 1)
+			to = &next
+		}
+	}
+
+	return from, to, true
+}
+
+func parseUUIDParam(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: message,
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// maxCAFSize bounds uploaded CAF files, which are a few kilobytes.
+const maxCAFSize = 64 << 10
+
+type TaxDocumentHandler struct {
+	taxDocumentUseCase *usecase.TaxDocumentUseCase
+	validator          *validator.Validate
+	logger             *zap.Logger
+}
+
+func NewTaxDocumentHandler(taxDocumentUseCase *usecase.TaxDocumentUseCase, validator *validator.Validate, logger *zap.Logger) *TaxDocumentHandler {
+	return &TaxDocumentHandler{
+		taxDocumentUseCase: taxDocumentUseCase,
+		validator:          validator,
+		logger:             logger,
+	}
+}
+
+// ListTaxDocuments godoc
+// @Summary List tax documents
+// @Description List the electronic tax documents (DTE) issued, newest first (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query int false "Filter by document type" Enums(33, 34, 39, 61)
+// @Param status query string false "Filter by status" Enums(SIGNED, SUBMITTED, ACCEPTED, REJECTED)
+// @Param invoice_id query string false "Filter by invoice ID"
+// @Param payment_id query string false "Filter by payment ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents [get]
+func (h *TaxDocumentHandler) ListTaxDocuments(c *gin.Context) {
+	req := domain.ListTaxDocumentsRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	docType, ok := parseDTEType(c)
+	if !ok {
+		return
+	}
+	req.Type = docType
+
+	if value := c.Query("status"); value != "" {
+		status := domain.TaxDocumentStatus(value)
+		switch status {
+		case domain.TaxDocumentStatusSigned, domain.TaxDocumentStatusSubmitted, domain.TaxDocumentStatusAccepted, domain.TaxDocumentStatusRejected:
+			req.Status = &status
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid status",
+				Details: "status must be SIGNED, SUBMITTED, ACCEPTED or REJECTED",
+			})
+			return
+		}
+	}
+
+	if value := c.Query("invoice_id"); value != "" {
+		invoiceID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid invoice ID",
+			})
+			return
+		}
+		req.InvoiceID = &invoiceID
+	}
+
+	if value := c.Query("payment_id"); value != "" {
+		paymentID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid payment ID",
+			})
+			return
+		}
+		req.PaymentID = &paymentID
+	}
+
+	docs, total, err := h.taxDocumentUseCase.ListDocuments(req)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to list tax documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       docs,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+	})
+}
+
+// IssueTaxDocument godoc
+// @Summary Issue a tax document
+// @Description Issue the factura (33, or 34 when exempt) of an invoice, or the boleta (39) of a captured reservation payment. The document takes the next authorized folio, is stamped and signed, and is sent to the tax authority by the scheduler (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.IssueTaxDocumentRequest true "Invoice or payment"
+// @Success 201 {object} domain.TaxDocument
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents [post]
+func (h *TaxDocumentHandler) IssueTaxDocument(c *gin.Context) {
+	var req domain.IssueTaxDocumentRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	doc, err := h.taxDocumentUseCase.IssueDocument(req)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to issue tax document")
+		return
+	}
+
+	c.JSON(http.StatusCreated, doc)
+}
+
+// GetTaxDocument godoc
+// @Summary Get a tax document
+// @Description Get a tax document with its submission status (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax document ID"
+// @Success 200 {object} domain.TaxDocument
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents/{id} [get]
+func (h *TaxDocumentHandler) GetTaxDocument(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid tax document ID")
+	if !ok {
+		return
+	}
+
+	doc, err := h.taxDocumentUseCase.GetDocument(id)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to get tax document")
+		return
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
+
+// DownloadTaxDocumentXML godoc
+// @Summary Download a tax document
+// @Description Download the signed XML of a tax document (Admin only)
+// @Tags admin
+// @Produce application/xml
+// @Security BearerAuth
+// @Param id path string true "Tax document ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents/{id}/xml [get]
+func (h *TaxDocumentHandler) DownloadTaxDocumentXML(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid tax document ID")
+	if !ok {
+		return
+	}
+
+	doc, err := h.taxDocumentUseCase.GetDocument(id)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to get tax document")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="dte-%d-%d.xml"`, doc.Type, doc.Folio))
+	c.Data(http.StatusOK, "application/xml; charset=ISO-8859-1", doc.XML)
+}
+
+// CreateCreditNote godoc
+// @Summary Credit a tax document
+// @Description Issue a credit note (61) for a factura or boleta. Without an amount it credits what is left of the document, voiding it when nothing was credited before (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax document ID"
+// @Param request body domain.CreateCreditNoteRequest true "Credit note"
+// @Success 201 {object} domain.TaxDocument
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents/{id}/credit-note [post]
+func (h *TaxDocumentHandler) CreateCreditNote(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "Invalid tax document ID")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateCreditNoteRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	doc, err := h.taxDocumentUseCase.CreateCreditNote(id, req)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to create credit note")
+		return
+	}
+
+	c.JSON(http.StatusCreated, doc)
+}
+
+// ListFolioRanges godoc
+// @Summary List folio ranges
+// @Description List the folio ranges authorized by the SII with the folios left (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query int false "Filter by document type" Enums(33, 34, 39, 61)
+// @Success 200 {array} domain.FolioRange
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents/folio-ranges [get]
+func (h *TaxDocumentHandler) ListFolioRanges(c *gin.Context) {
+	docType, ok := parseDTEType(c)
+	if !ok {
+		return
+	}
+
+	ranges, err := h.taxDocumentUseCase.ListFolioRanges(docType)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to list folio ranges")
+		return
+	}
+
+	c.JSON(http.StatusOK, ranges)
+}
+
+// UploadFolioRange godoc
+// @Summary Upload a CAF
+// @Description Add the folios of a Código de Autorización de Folios downloaded from the SII. Folios are used in order, oldest range first (Admin only)
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CAF XML file"
+// @Success 201 {object} domain.FolioRange
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/tax-documents/folio-ranges [post]
+func (h *TaxDocumentHandler) UploadFolioRange(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCAFSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil || fileHeader.Size > maxCAFSize {
+		details := fmt.Sprintf("file must be a CAF of at most %d KB", maxCAFSize>>10)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if !errors.As(err, &maxBytesErr) {
+				details = err.Error()
+			}
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "File is required",
+			Details: details,
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.Error("Failed to open uploaded file", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Failed to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		h.logger.Error("Failed to read uploaded file", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Failed to read uploaded file",
+		})
+		return
+	}
+
+	folioRange, err := h.taxDocumentUseCase.UploadFolioRange(raw)
+	if err != nil {
+		h.respondTaxDocumentError(c, err, "Failed to add folio range")
+		return
+	}
+
+	c.JSON(http.StatusCreated, folioRange)
+}
+
+func (h *TaxDocumentHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *TaxDocumentHandler) respondTaxDocumentError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrInvalidCAF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid CAF",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	switch err {
+	case domain.ErrTaxDocumentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Tax document not found",
+		})
+	case domain.ErrInvoiceNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Invoice not found",
+		})
+	case domain.ErrPaymentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Payment not found",
+		})
+	case domain.ErrFoliosExhausted:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "No folios left for the document type, upload a new CAF",
+		})
+	case domain.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Already issued or uploaded",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Void invoices, uncaptured payments and rejected documents cannot be documented or credited",
+		})
+	case domain.ErrTaxDocumentCurrency:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Tax documents can only be issued in CLP",
+		})
+	case domain.ErrInvalidRUT:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "The company has no valid RUT",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Details: "invoice payments are documented by their invoice's factura, and credit notes cannot be credited or exceed what is left of the document",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
+
+// parseDTEType reads the optional document type filter.
+func parseDTEType(c *gin.Context) (*domain.DTEType, bool) {
+	value := c.Query("type")
+	if value == "" {
+		return nil, true
+	}
+
+	code, err := strconv.Atoi(value)
+	docType := domain.DTEType(code)
+	if err != nil || !docType.Valid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid document type",
+			Details: "type must be 33, 34, 39 or 61",
+		})
+		return nil, false
+	}
+
+	return &docType, true
+}
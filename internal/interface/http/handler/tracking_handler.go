@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type TrackingHandler struct {
+	trackingUseCase   *usecase.TrackingUseCase
+	driverUseCase     *usecase.DriverUseCase
+	validator         *validator.Validate
+	heartbeatInterval time.Duration
+	logger            *zap.Logger
+}
+
+func NewTrackingHandler(trackingUseCase *usecase.TrackingUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, heartbeatInterval time.Duration, logger *zap.Logger) *TrackingHandler {
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = 15 * time.Second
+	}
+	return &TrackingHandler{
+		trackingUseCase:   trackingUseCase,
+		driverUseCase:     driverUseCase,
+		validator:         validator,
+		heartbeatInterval: heartbeatInterval,
+		logger:            logger,
+	}
+}
+
+// RecordLocations godoc
+// @Summary Record driver locations
+// @Description Upload a batch of GPS pings from the authenticated driver. Pings are linked to the trip currently in progress.
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.RecordLocationsRequest true "GPS pings"
+// @Success 202 {object} domain.RecordLocationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/locations [post]
+func (h *TrackingHandler) RecordLocations(c *gin.Context) {
+	var req domain.RecordLocationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userIDRaw.(uuid.UUID).String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return
+	}
+
+	result, err := h.trackingUseCase.RecordLocations(driver.ID, req)
+	if err != nil {
+		h.logger.Error("Failed to record locations", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to record locations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// GetReservationLocation godoc
+// @Summary Get driver location
+// @Description Get the last known location of the driver assigned to a reservation
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} domain.DriverLocation
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/tracking [get]
+func (h *TrackingHandler) GetReservationLocation(c *gin.Context) {
+	viewer, ok := reservationViewer(c)
+	if !ok {
+		return
+	}
+
+	location, err := h.trackingUseCase.GetReservationLocation(c.Param("id"), viewer)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "No location reported yet",
+			})
+			return
+		}
+		h.respondTrackingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// StreamReservationLocation godoc
+// @Summary Stream driver location
+// @Description Follow the driver assigned to a reservation in real time using Server-Sent Events. Emits "location" events and periodic "heartbeat" events.
+// @Tags tracking
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} domain.DriverLocation
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/tracking/stream [get]
+func (h *TrackingHandler) StreamReservationLocation(c *gin.Context) {
+	viewer, ok := reservationViewer(c)
+	if !ok {
+		return
+	}
+
+	latest, updates, unsubscribe, err := h.trackingUseCase.SubscribeReservation(c.Param("id"), viewer)
+	if err != nil {
+		h.respondTrackingError(c, err)
+		return
+	}
+	defer unsubscribe()
+
+	streamLocations(c, latest, updates, h.heartbeatInterval)
+}
+
+// streamLocations writes locations as Server-Sent Events until the client goes
+// away or the feed is closed.
+func streamLocations(c *gin.Context, latest *domain.DriverLocation, updates <-chan *domain.DriverLocation, heartbeatInterval time.Duration) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	if latest != nil {
+		c.SSEvent("location", latest)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case location, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("location", location)
+			return true
+		case at := <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"at": at})
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (h *TrackingHandler) respondTrackingError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrReservationNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Reservation not found",
+		})
+	case domain.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Insufficient permissions",
+		})
+	case domain.ErrDriverNotAvailable:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Reservation has no assigned driver",
+		})
+	default:
+		h.logger.Error("Failed to track reservation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
+
+// reservationViewer builds the read scope of the authenticated user and writes
+// the error response when the auth context is incomplete.
+func reservationViewer(c *gin.Context) (domain.ReservationViewer, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return domain.ReservationViewer{}, false
+	}
+
+	userRoleRaw, exists := c.Get("user_role")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User role not found",
+		})
+		return domain.ReservationViewer{}, false
+	}
+
+	var userRole domain.UserRole
+	switch v := userRoleRaw.(type) {
+	case domain.UserRole:
+		userRole = v
+	case string:
+		userRole = domain.UserRole(v)
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Invalid user role type",
+		})
+		return domain.ReservationViewer{}, false
+	}
+
+	viewer := domain.ReservationViewer{
+		UserID: userIDRaw.(uuid.UUID),
+		Role:   userRole,
+	}
+	if orgID, ok := c.Get("org_id"); ok {
+		if orgUUID, ok := orgID.(*uuid.UUID); ok {
+			viewer.OrgID = orgUUID
+		}
+	}
+
+	return viewer, true
+}
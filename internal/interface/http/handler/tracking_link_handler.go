@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type TrackingLinkHandler struct {
+	linkUseCase *usecase.TrackingLinkUseCase
+	logger      *zap.Logger
+}
+
+func NewTrackingLinkHandler(linkUseCase *usecase.TrackingLinkUseCase, logger *zap.Logger) *TrackingLinkHandler {
+	return &TrackingLinkHandler{
+		linkUseCase: linkUseCase,
+		logger:      logger,
+	}
+}
+
+// CreateTrackingLink godoc
+// @Summary Create tracking link
+// @Description Issue a signed, expiring link that lets guests follow the reservation without an account
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 201 {object} domain.TrackingLinkResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/tracking-links [post]
+func (h *TrackingLinkHandler) CreateTrackingLink(c *gin.Context) {
+	viewer, ok := reservationViewer(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.linkUseCase.CreateLink(c.Param("id"), viewer)
+	if err != nil {
+		h.respondLinkError(c, err, "Failed to create tracking link")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListTrackingLinks godoc
+// @Summary List tracking links
+// @Description List every tracking link issued for a reservation, including revoked and expired ones
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} []domain.TrackingLink
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/tracking-links [get]
+func (h *TrackingLinkHandler) ListTrackingLinks(c *gin.Context) {
+	viewer, ok := reservationViewer(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.linkUseCase.ListLinks(c.Param("id"), viewer)
+	if err != nil {
+		h.respondLinkError(c, err, "Failed to list tracking links")
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// RevokeTrackingLink godoc
+// @Summary Revoke tracking link
+// @Description Disable a tracking link immediately
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Param linkId path string true "Tracking link ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/tracking-links/{linkId} [delete]
+func (h *TrackingLinkHandler) RevokeTrackingLink(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid tracking link ID",
+		})
+		return
+	}
+
+	viewer, ok := reservationViewer(c)
+	if !ok {
+		return
+	}
+
+	if err := h.linkUseCase.RevokeLink(c.Param("id"), linkID, viewer); err != nil {
+		h.respondLinkError(c, err, "Failed to revoke tracking link")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: "Tracking link revoked",
+	})
+}
+
+// GetPublicTracking godoc
+// @Summary Public reservation tracking
+// @Description Get the reduced status view of a reservation using a tracking link token. No authentication required.
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Param token path string true "Tracking token"
+// @Success 200 {object} domain.PublicTrackingView
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/public/tracking/{token} [get]
+func (h *TrackingLinkHandler) GetPublicTracking(c *gin.Context) {
+	view, err := h.linkUseCase.GetPublicView(c.Param("token"))
+	if err != nil {
+		switch err {
+		case domain.ErrUnauthorized:
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error: "Tracking link is invalid, expired or revoked",
+			})
+		default:
+			h.logger.Error("Failed to get public tracking view", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *TrackingLinkHandler) respondLinkError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrReservationNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Reservation not found",
+		})
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Tracking link not found",
+		})
+	case domain.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Insufficient permissions",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
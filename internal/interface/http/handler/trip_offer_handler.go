@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type TripOfferHandler struct {
+	offerUseCase  *usecase.TripOfferUseCase
+	driverUseCase *usecase.DriverUseCase
+	validator     *validator.Validate
+	logger        *zap.Logger
+}
+
+func NewTripOfferHandler(offerUseCase *usecase.TripOfferUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *TripOfferHandler {
+	return &TripOfferHandler{
+		offerUseCase:  offerUseCase,
+		driverUseCase: driverUseCase,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// DispatchOffers godoc
+// @Summary Dispatch trip offers
+// @Description Send a reservation to an ordered list of candidate drivers. The first driver is offered immediately; the rest are offered in turn on decline or timeout.
+// @Tags dispatch
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Param request body domain.DispatchOffersRequest true "Candidate drivers in priority order"
+// @Success 201 {object} []domain.TripOffer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ComplianceErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/offers [post]
+func (h *TripOfferHandler) DispatchOffers(c *gin.Context) {
+	id := c.Param("id")
+
+	var req domain.DispatchOffersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid request body for dispatch offers", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		h.logger.Warn("Validation failed for dispatch offers", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	offers, err := h.offerUseCase.DispatchOffers(id, req)
+	if err != nil {
+		if respondComplianceError(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrReservationNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Reservation not found",
+			})
+		case domain.ErrDriverNotFound:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Driver not found",
+			})
+		case domain.ErrDriverInactive:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Driver is inactive",
+			})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Cannot dispatch completed or cancelled reservation",
+			})
+		case domain.ErrDispatchInProgress:
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error: "Reservation already has open offers",
+			})
+		case domain.ErrOutsideShift:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "Reservation is outside a candidate driver's scheduled shift",
+			})
+		case domain.ErrVehicleInMaintenance:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "A candidate driver's vehicle is scheduled for maintenance",
+			})
+		case domain.ErrVehicleTypeMismatch:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "A candidate driver's vehicle is not of the booked type",
+			})
+		case domain.ErrVehicleTooSmall:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "A candidate driver's vehicle cannot carry all passengers",
+			})
+		default:
+			h.logger.Error("Failed to dispatch offers", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, offers)
+}
+
+// GetReservationOffers godoc
+// @Summary List reservation offers
+// @Description Get every offer sent for a reservation, including queued candidates
+// @Tags dispatch
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} []domain.TripOffer
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/reservations/{id}/offers [get]
+func (h *TripOfferHandler) GetReservationOffers(c *gin.Context) {
+	id := c.Param("id")
+
+	offers, err := h.offerUseCase.GetReservationOffers(id)
+	if err != nil {
+		switch err {
+		case domain.ErrReservationNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Reservation not found",
+			})
+		default:
+			h.logger.Error("Failed to get reservation offers", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// GetDriverOffers godoc
+// @Summary List driver offers
+// @Description Get trip offers sent to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(PENDING,ACCEPTED,DECLINED,EXPIRED,CANCELLED)
+// @Success 200 {object} []domain.TripOffer
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/offers [get]
+func (h *TripOfferHandler) GetDriverOffers(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	var status *domain.TripOfferStatus
+	if s := c.Query("status"); s != "" {
+		offerStatus := domain.TripOfferStatus(s)
+		status = &offerStatus
+	}
+
+	offers, err := h.offerUseCase.GetDriverOffers(driver.ID, status)
+	if err != nil {
+		h.logger.Error("Failed to get driver offers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver offers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// AcceptOffer godoc
+// @Summary Accept trip offer
+// @Description Accept a pending trip offer; the reservation is assigned to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param offerId path string true "Offer ID"
+// @Success 200 {object} domain.TripOffer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ComplianceErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/offers/{offerId}/accept [post]
+func (h *TripOfferHandler) AcceptOffer(c *gin.Context) {
+	offerID, err := uuid.Parse(c.Param("offerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid offer ID",
+		})
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.offerUseCase.AcceptOffer(driver.ID, offerID)
+	if err != nil {
+		h.respondOfferError(c, err, "Failed to accept offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// DeclineOffer godoc
+// @Summary Decline trip offer
+// @Description Decline a pending trip offer; the next candidate driver is offered the trip
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param offerId path string true "Offer ID"
+// @Param request body domain.DeclineOfferRequest false "Decline reason"
+// @Success 200 {object} domain.TripOffer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/offers/{offerId}/decline [post]
+func (h *TripOfferHandler) DeclineOffer(c *gin.Context) {
+	offerID, err := uuid.Parse(c.Param("offerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid offer ID",
+		})
+		return
+	}
+
+	var req domain.DeclineOfferRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.offerUseCase.DeclineOffer(driver.ID, offerID, req)
+	if err != nil {
+		h.respondOfferError(c, err, "Failed to decline offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// currentDriver resolves the driver profile linked to the authenticated user
+// and writes the error response when it cannot be found.
+func (h *TripOfferHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return nil, false
+	}
+
+	userID := userIDRaw.(uuid.UUID).String()
+	driver, err := h.driverUseCase.GetDriverByUserID(userID)
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *TripOfferHandler) respondOfferError(c *gin.Context, err error, message string) {
+	if respondComplianceError(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrOfferNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Offer not found",
+		})
+	case domain.ErrOfferNotPending:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Offer is no longer pending",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
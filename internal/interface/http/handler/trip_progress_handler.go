@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type TripProgressHandler struct {
+	progressUseCase *usecase.TripProgressUseCase
+	driverUseCase   *usecase.DriverUseCase
+	validator       *validator.Validate
+	logger          *zap.Logger
+}
+
+func NewTripProgressHandler(progressUseCase *usecase.TripProgressUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *TripProgressHandler {
+	return &TripProgressHandler{
+		progressUseCase: progressUseCase,
+		driverUseCase:   driverUseCase,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// GetTripProgress godoc
+// @Summary Get trip progress
+// @Description Get the driver-side lifecycle of a trip assigned to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tripId path string true "Trip ID"
+// @Success 200 {object} domain.TripProgress
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/trips/{tripId}/progress [get]
+func (h *TripProgressHandler) GetTripProgress(c *gin.Context) {
+	tripID := c.Param("tripId")
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.progressUseCase.GetTripProgress(driver.ID, tripID)
+	if err != nil {
+		h.respondProgressError(c, err, "Failed to get trip progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// UpdateTripStatus godoc
+// @Summary Update trip status
+// @Description Advance the trip lifecycle for the authenticated driver: EN_CAMINO → EN_ORIGEN → PASAJERO_A_BORDO → FINALIZADO, or EN_ORIGEN → NO_SHOW
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tripId path string true "Trip ID"
+// @Param request body domain.UpdateTripStatusRequest true "Update trip status request"
+// @Success 200 {object} domain.TripProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/trips/{tripId}/status [patch]
+func (h *TripProgressHandler) UpdateTripStatus(c *gin.Context) {
+	tripID := c.Param("tripId")
+
+	var req domain.UpdateTripStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.progressUseCase.UpdateTripStatus(driver.ID, tripID, req.Status)
+	if err != nil {
+		h.respondProgressError(c, err, "Failed to update trip status")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+func (h *TripProgressHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return nil, false
+	}
+
+	userID := userIDRaw.(uuid.UUID).String()
+	driver, err := h.driverUseCase.GetDriverByUserID(userID)
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *TripProgressHandler) respondProgressError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Trip not found",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Invalid trip status transition",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Internal server error",
+		})
+	}
+}
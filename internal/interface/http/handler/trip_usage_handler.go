@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/infrastructure/report"
+	"turivo-backend/internal/usecase"
+)
+
+type TripUsageHandler struct {
+	usageUseCase  *usecase.TripUsageUseCase
+	driverUseCase *usecase.DriverUseCase
+	validator     *validator.Validate
+	logger        *zap.Logger
+}
+
+func NewTripUsageHandler(usageUseCase *usecase.TripUsageUseCase, driverUseCase *usecase.DriverUseCase, validator *validator.Validate, logger *zap.Logger) *TripUsageHandler {
+	return &TripUsageHandler{
+		usageUseCase:  usageUseCase,
+		driverUseCase: driverUseCase,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// GetTripUsage godoc
+// @Summary Get trip odometer and fuel
+// @Description Get the odometer readings, actual distance and fuel events of a trip assigned to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tripId path string true "Trip ID"
+// @Success 200 {object} domain.TripUsage
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/trips/{tripId}/usage [get]
+func (h *TripUsageHandler) GetTripUsage(c *gin.Context) {
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	usage, err := h.usageUseCase.GetTripUsage(driver.ID, c.Param("tripId"))
+	if err != nil {
+		h.respondUsageError(c, err, "Failed to get trip usage")
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// RecordOdometer godoc
+// @Summary Record a trip odometer reading
+// @Description Record the odometer of the driver's vehicle at the START or END of a trip. Readings cannot be lower than the vehicle's last reading; the END reading sets the trip's actual distance
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tripId path string true "Trip ID"
+// @Param request body domain.RecordOdometerRequest true "Odometer reading"
+// @Success 201 {object} domain.TripUsage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/trips/{tripId}/odometer [post]
+func (h *TripUsageHandler) RecordOdometer(c *gin.Context) {
+	var req domain.RecordOdometerRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	usage, err := h.usageUseCase.RecordOdometer(driver.ID, c.Param("tripId"), req)
+	if err != nil {
+		h.respondUsageError(c, err, "Failed to record odometer reading")
+		return
+	}
+
+	c.JSON(http.StatusCreated, usage)
+}
+
+// RecordFuelEvent godoc
+// @Summary Record a fuel or charging stop
+// @Description Record a refuelling (litres) or charging (kWh) stop made during a trip assigned to the authenticated driver
+// @Tags Driver
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tripId path string true "Trip ID"
+// @Param request body domain.RecordFuelEventRequest true "Fuel event"
+// @Success 201 {object} domain.FuelEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/driver/trips/{tripId}/fuel [post]
+func (h *TripUsageHandler) RecordFuelEvent(c *gin.Context) {
+	var req domain.RecordFuelEventRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	driver, ok := h.currentDriver(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.usageUseCase.RecordFuelEvent(driver.ID, c.Param("tripId"), req)
+	if err != nil {
+		h.respondUsageError(c, err, "Failed to record fuel event")
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// GetVehicleUsage godoc
+// @Summary Get vehicle cost and efficiency
+// @Description Measured distance, fuel and maintenance spend, cost per km and fuel efficiency of a vehicle (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags vehicles
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} domain.VehicleUsageReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/usage [get]
+func (h *TripUsageHandler) GetVehicleUsage(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	usageReport, err := h.usageUseCase.GetVehicleReport(c.Param("id"), from, to)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Vehicle not found",
+			})
+			return
+		}
+		h.respondUsageError(c, err, "Failed to get vehicle usage")
+		return
+	}
+
+	h.respondUsageReports(c, []*domain.VehicleUsageReport{usageReport}, usageReport, "uso-"+usageReport.VehicleID)
+}
+
+// ListVehicleUsage godoc
+// @Summary List fleet cost and efficiency
+// @Description Measured distance, fuel and maintenance spend and efficiency of every vehicle active in the period, highest cost per km first (Admin only). Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {array} domain.VehicleUsageReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/vehicle-usage [get]
+func (h *TripUsageHandler) ListVehicleUsage(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.usageUseCase.ListVehicleReports(from, to)
+	if err != nil {
+		h.respondUsageError(c, err, "Failed to list vehicle usage")
+		return
+	}
+
+	h.respondUsageReports(c, reports, reports, "uso-flota-"+time.Now().Format("2006-01-02"))
+}
+
+// respondUsageReports writes the reports as CSV when requested, or body as
+// JSON otherwise.
+func (h *TripUsageHandler) respondUsageReports(c *gin.Context, reports []*domain.VehicleUsageReport, body interface{}, fileName string) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, body)
+
+	case "csv":
+		content, err := report.VehicleUsageCSV(reports)
+		if err != nil {
+			h.respondUsageError(c, err, "Failed to export vehicle usage")
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid format",
+			Details: "format must be json or csv",
+		})
+	}
+}
+
+func (h *TripUsageHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *TripUsageHandler) currentDriver(c *gin.Context) (*domain.Driver, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	driver, err := h.driverUseCase.GetDriverByUserID(userID.String())
+	if err != nil {
+		h.logger.Error("Failed to get driver by user ID", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to get driver information",
+		})
+		return nil, false
+	}
+
+	return driver, true
+}
+
+func (h *TripUsageHandler) respondUsageError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Trip not found",
+		})
+	case domain.ErrOdometerAlreadyRecorded:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Odometer reading already recorded for this trip",
+		})
+	case domain.ErrInvalidStatusTransition:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Trip has been cancelled",
+		})
+	case domain.ErrOdometerStartMissing:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Record the start odometer reading first",
+		})
+	case domain.ErrOdometerReadingDecreased:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Odometer reading is lower than the vehicle's last reading",
+		})
+	case domain.ErrDriverHasNoVehicle:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "No vehicle is assigned to you",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid input",
+			Details: "occurred_at cannot be in the future",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
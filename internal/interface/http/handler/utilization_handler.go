@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+// defaultUtilizationDays is the calendar length when no "to" date is given.
+const defaultUtilizationDays = 7
+
+type UtilizationHandler struct {
+	utilizationUseCase *usecase.UtilizationUseCase
+	validator          *validator.Validate
+	logger             *zap.Logger
+}
+
+func NewUtilizationHandler(utilizationUseCase *usecase.UtilizationUseCase, validator *validator.Validate, logger *zap.Logger) *UtilizationHandler {
+	return &UtilizationHandler{
+		utilizationUseCase: utilizationUseCase,
+		validator:          validator,
+		logger:             logger,
+	}
+}
+
+// GetFleetUtilization godoc
+// @Summary Get fleet utilization calendar
+// @Description Day-by-day booked, maintenance and free blocks of every vehicle, with utilization percentages per vehicle, vehicle type and company of the current driver (Admin only). Trip durations are estimated from the planned distance. Dates are YYYY-MM-DD; "to" is inclusive. Defaults to the 7 days starting today, up to 62 days.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param type query string false "Filter by vehicle type" Enums(BUS, VAN, SEDAN, SUV)
+// @Success 200 {object} domain.FleetUtilizationReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fleet-utilization [get]
+func (h *UtilizationHandler) GetFleetUtilization(c *gin.Context) {
+	from, to, ok := h.calendarRange(c)
+	if !ok {
+		return
+	}
+
+	req := domain.FleetUtilizationRequest{From: from, To: to}
+	if vehicleType := c.Query("type"); vehicleType != "" {
+		t := domain.VehicleType(vehicleType)
+		if err := h.validator.Var(t, "oneof=BUS VAN SEDAN SUV"); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid vehicle type",
+				Details: "type must be one of BUS, VAN, SEDAN, SUV",
+			})
+			return
+		}
+		req.VehicleType = &t
+	}
+
+	report, err := h.utilizationUseCase.GetFleetUtilization(req)
+	if err != nil {
+		h.respondUtilizationError(c, err, "Failed to get fleet utilization")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetVehicleUtilization godoc
+// @Summary Get vehicle utilization calendar
+// @Description Day-by-day booked, maintenance and free blocks of a vehicle (Admin only). Dates are YYYY-MM-DD; "to" is inclusive. Defaults to the 7 days starting today, up to 62 days.
+// @Tags vehicles
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} domain.VehicleUtilization
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/utilization [get]
+func (h *UtilizationHandler) GetVehicleUtilization(c *gin.Context) {
+	from, to, ok := h.calendarRange(c)
+	if !ok {
+		return
+	}
+
+	utilization, err := h.utilizationUseCase.GetVehicleUtilization(c.Param("id"), from, to)
+	if err != nil {
+		h.respondUtilizationError(c, err, "Failed to get vehicle utilization")
+		return
+	}
+
+	c.JSON(http.StatusOK, utilization)
+}
+
+// ListFreeVehicles godoc
+// @Summary List free vehicles
+// @Description List the vehicles in service, optionally of one type, with no booking or maintenance window between from and to (Admin only). Times are RFC3339.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Filter by vehicle type" Enums(BUS, VAN, SEDAN, SUV)
+// @Param from query string true "Start of the period (RFC3339)"
+// @Param to query string true "End of the period (RFC3339)"
+// @Success 200 {array} domain.FleetVehicle
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fleet-availability [get]
+func (h *UtilizationHandler) ListFreeVehicles(c *gin.Context) {
+	var req domain.FreeVehiclesRequest
+	for _, param := range []string{"from", "to"} {
+		value, err := time.Parse(time.RFC3339, c.Query(param))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid time",
+				Details: param + " must be an RFC3339 timestamp",
+			})
+			return
+		}
+		if param == "from" {
+			req.From = value
+		} else {
+			req.To = value
+		}
+	}
+	if vehicleType := c.Query("type"); vehicleType != "" {
+		t := domain.VehicleType(vehicleType)
+		req.VehicleType = &t
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	vehicles, err := h.utilizationUseCase.ListFreeVehicles(req)
+	if err != nil {
+		h.respondUtilizationError(c, err, "Failed to list free vehicles")
+		return
+	}
+
+	c.JSON(http.StatusOK, vehicles)
+}
+
+// calendarRange resolves the from/to dates of a calendar, defaulting to the
+// week starting today.
+func (h *UtilizationHandler) calendarRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := time.Now()
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	if from != nil {
+		start = *from
+	}
+	end := start.AddDate(0, 0, defaultUtilizationDays)
+	if to != nil {
+		end = *to
+	}
+
+	return start, end, true
+}
+
+func (h *UtilizationHandler) respondUtilizationError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid period",
+			Details: "to must be after from and the period cannot exceed 62 days",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -320,6 +321,7 @@ func (h *VehicleHandler) DeleteVehicle(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ComplianceErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/vehicles/{id}/assign [post]
 func (h *VehicleHandler) AssignVehicleToDriver(c *gin.Context) {
@@ -347,7 +349,10 @@ func (h *VehicleHandler) AssignVehicleToDriver(c *gin.Context) {
 
 	vehicle, err := h.vehicleUseCase.AssignVehicleToDriver(req.VehicleID, req.DriverID)
 	if err != nil {
-		if err == domain.ErrNotFound {
+		if respondComplianceError(c, err) {
+			return
+		}
+		if err == domain.ErrNotFound || err == domain.ErrDriverNotFound {
 			c.JSON(http.StatusNotFound, ErrorResponse{
 				Error: "Vehicle or driver not found",
 			})
@@ -372,3 +377,112 @@ func (h *VehicleHandler) AssignVehicleToDriver(c *gin.Context) {
 	c.JSON(http.StatusOK, vehicle)
 }
 
+// ListVehicleAssignments godoc
+// @Summary List vehicle assignment history
+// @Description List every driver who has had the vehicle, newest first
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Success 200 {array} domain.VehicleAssignment
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/assignments [get]
+func (h *VehicleHandler) ListVehicleAssignments(c *gin.Context) {
+	assignments, err := h.vehicleUseCase.ListVehicleAssignments(c.Param("id"))
+	if err != nil {
+		h.respondAssignmentError(c, err, "Failed to list vehicle assignments")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// GetVehicleDriverAt godoc
+// @Summary Get who had a vehicle at a given time
+// @Description Find the driver assigned to the vehicle at a moment in time, e.g. for an infraction or damage report
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Vehicle ID"
+// @Param at query string true "Moment to look up (RFC3339)"
+// @Success 200 {object} domain.VehicleAssignment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/vehicles/{id}/driver-at [get]
+func (h *VehicleHandler) GetVehicleDriverAt(c *gin.Context) {
+	at, err := time.Parse(time.RFC3339, c.Query("at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time",
+			Details: "at must be an RFC3339 timestamp",
+		})
+		return
+	}
+
+	assignment, err := h.vehicleUseCase.GetVehicleDriverAt(c.Param("id"), at)
+	if err != nil {
+		h.respondAssignmentError(c, err, "Failed to get vehicle assignment")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// ListDriverVehicleAssignments godoc
+// @Summary List the vehicles a driver used
+// @Description List the vehicle assignments of a driver that overlap the period, newest first. Dates are YYYY-MM-DD; "to" is inclusive.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {array} domain.VehicleAssignment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/vehicle-assignments [get]
+func (h *VehicleHandler) ListDriverVehicleAssignments(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.vehicleUseCase.ListDriverVehicleAssignments(c.Param("id"), from, to)
+	if err != nil {
+		h.respondAssignmentError(c, err, "Failed to list driver vehicle assignments")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (h *VehicleHandler) respondAssignmentError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Vehicle not found",
+		})
+	case domain.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+		})
+	case domain.ErrVehicleAssignmentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "No driver had the vehicle at that time",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
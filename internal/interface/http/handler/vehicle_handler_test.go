@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockVehicleUseCase implements the assignment history lookups; any other
+// call panics.
+type MockVehicleUseCase struct {
+	domain.VehicleUseCase
+	mock.Mock
+}
+
+func (m *MockVehicleUseCase) GetVehicleDriverAt(vehicleID string, at time.Time) (*domain.VehicleAssignment, error) {
+	args := m.Called(vehicleID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleAssignment), args.Error(1)
+}
+
+func (m *MockVehicleUseCase) ListVehicleAssignments(vehicleID string) ([]*domain.VehicleAssignment, error) {
+	args := m.Called(vehicleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.VehicleAssignment), args.Error(1)
+}
+
+func (m *MockVehicleUseCase) ListDriverVehicleAssignments(driverID string, from, to *time.Time) ([]*domain.VehicleAssignment, error) {
+	args := m.Called(driverID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.VehicleAssignment), args.Error(1)
+}
+
+func newVehicleAssignmentRouter(useCase domain.VehicleUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	h := NewVehicleHandler(useCase, validator.New(), zap.NewNop())
+
+	router := gin.New()
+	router.GET("/vehicles/:id/assignments", h.ListVehicleAssignments)
+	router.GET("/vehicles/:id/driver-at", h.GetVehicleDriverAt)
+	router.GET("/drivers/:id/vehicle-assignments", h.ListDriverVehicleAssignments)
+	return router
+}
+
+func getJSON(router http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+// reassignedVehicleHistory is the history of a vehicle that went from one
+// driver to another, newest first as the repository returns it.
+func reassignedVehicleHistory() []*domain.VehicleAssignment {
+	handedOver := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	first := &domain.VehicleAssignment{
+		ID:         uuid.New(),
+		VehicleID:  "VEH-001",
+		DriverID:   "DRV-001",
+		AssignedAt: time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC),
+		ReleasedAt: &handedOver,
+	}
+	current := &domain.VehicleAssignment{
+		ID:         uuid.New(),
+		VehicleID:  "VEH-001",
+		DriverID:   "DRV-002",
+		AssignedAt: handedOver,
+	}
+	return []*domain.VehicleAssignment{current, first}
+}
+
+func TestVehicleHandler_ListVehicleAssignments(t *testing.T) {
+	t.Run("should return the history newest first", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+		useCase.On("ListVehicleAssignments", "VEH-001").Return(reassignedVehicleHistory(), nil)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/vehicles/VEH-001/assignments")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var history []domain.VehicleAssignment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+		require.Len(t, history, 2)
+
+		assert.Equal(t, "DRV-002", history[0].DriverID)
+		assert.Nil(t, history[0].ReleasedAt)
+		assert.Equal(t, "DRV-001", history[1].DriverID)
+		require.NotNil(t, history[1].ReleasedAt)
+		// The previous period closes when the next one opens
+		assert.True(t, history[1].ReleasedAt.Equal(history[0].AssignedAt))
+	})
+
+	t.Run("should return 404 for an unknown vehicle", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+		useCase.On("ListVehicleAssignments", "VEH-404").Return(nil, domain.ErrNotFound)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/vehicles/VEH-404/assignments")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestVehicleHandler_GetVehicleDriverAt(t *testing.T) {
+	t.Run("should return the driver who had the vehicle", func(t *testing.T) {
+		history := reassignedVehicleHistory()
+		at := time.Date(2026, 9, 15, 10, 30, 0, 0, time.UTC)
+
+		useCase := new(MockVehicleUseCase)
+		useCase.On("GetVehicleDriverAt", "VEH-001", mock.MatchedBy(at.Equal)).Return(history[1], nil)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/vehicles/VEH-001/driver-at?at=2026-09-15T10:30:00Z")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var assignment domain.VehicleAssignment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &assignment))
+		assert.Equal(t, "DRV-001", assignment.DriverID)
+	})
+
+	t.Run("should return 404 when nobody had the vehicle", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+		useCase.On("GetVehicleDriverAt", "VEH-001", mock.Anything).Return(nil, domain.ErrVehicleAssignmentNotFound)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/vehicles/VEH-001/driver-at?at=2026-08-01T00:00:00Z")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should reject a time that is not RFC3339", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/vehicles/VEH-001/driver-at?at=2026-09-15")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		useCase.AssertNotCalled(t, "GetVehicleDriverAt", mock.Anything, mock.Anything)
+	})
+}
+
+func TestVehicleHandler_ListDriverVehicleAssignments(t *testing.T) {
+	t.Run("should include the whole last day of the period", func(t *testing.T) {
+		from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+
+		useCase := new(MockVehicleUseCase)
+		useCase.On("ListDriverVehicleAssignments", "DRV-001",
+			mock.MatchedBy(func(value *time.Time) bool { return value != nil && value.Equal(from) }),
+			mock.MatchedBy(func(value *time.Time) bool { return value != nil && value.Equal(to) }),
+		).Return(reassignedVehicleHistory()[1:], nil)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/drivers/DRV-001/vehicle-assignments?from=2026-09-01&to=2026-09-30")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		useCase.AssertExpectations(t)
+	})
+
+	t.Run("should list every period without dates", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+		useCase.On("ListDriverVehicleAssignments", "DRV-001", (*time.Time)(nil), (*time.Time)(nil)).Return([]*domain.VehicleAssignment{}, nil)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/drivers/DRV-001/vehicle-assignments")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+
+	t.Run("should return 404 for an unknown driver", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+		useCase.On("ListDriverVehicleAssignments", "DRV-404", mock.Anything, mock.Anything).Return(nil, domain.ErrDriverNotFound)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/drivers/DRV-404/vehicle-assignments")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should reject an invalid date", func(t *testing.T) {
+		useCase := new(MockVehicleUseCase)
+
+		w := getJSON(newVehicleAssignmentRouter(useCase), "/drivers/DRV-001/vehicle-assignments?from=01-09-2026")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
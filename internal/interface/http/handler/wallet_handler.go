@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+	"turivo-backend/internal/usecase"
+)
+
+type WalletHandler struct {
+	walletUseCase  *usecase.WalletUseCase
+	paymentUseCase *usecase.PaymentUseCase
+	validator      *validator.Validate
+	logger         *zap.Logger
+}
+
+func NewWalletHandler(walletUseCase *usecase.WalletUseCase, paymentUseCase *usecase.PaymentUseCase, validator *validator.Validate, logger *zap.Logger) *WalletHandler {
+	return &WalletHandler{
+		walletUseCase:  walletUseCase,
+		paymentUseCase: paymentUseCase,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// GetWallet godoc
+// @Summary Get a company's wallet
+// @Description Balance, credit line and upcoming trips of a company's wallet (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Success 200 {object} domain.CompanyWallet
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/wallet [get]
+func (h *WalletHandler) GetWallet(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	h.getWallet(c, companyID)
+}
+
+// UpsertWallet godoc
+// @Summary Open or update a company's wallet
+// @Description Give a company a prepaid balance and an optional credit line: its completed trips are debited from the wallet instead of being invoiced, and reservations that exceed what is left are refused. Wallets are opened in the pricing currency, which cannot change once the wallet is open (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Param request body domain.UpsertWalletRequest true "Wallet settings"
+// @Success 200 {object} domain.CompanyWallet
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/wallet [put]
+func (h *WalletHandler) UpsertWallet(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	var req domain.UpsertWalletRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	wallet, err := h.walletUseCase.UpsertWallet(companyID, req)
+	if err != nil {
+		h.respondWalletError(c, err, "Failed to save wallet")
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// GetWalletTransactions godoc
+// @Summary List a company's wallet transactions
+// @Description Ledger of a company's wallet with the entries of each transaction, newest first (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Param kind query string false "Filter by kind" Enums(TOP_UP, TOP_UP_REFUND, TRIP_DEBIT, TRIP_REFUND, ADJUSTMENT)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/wallet/transactions [get]
+func (h *WalletHandler) GetWalletTransactions(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	h.listTransactions(c, companyID)
+}
+
+// AdjustWallet godoc
+// @Summary Adjust a company's wallet
+// @Description Credit (positive amount) or debit (negative amount) a company's wallet outside the payment flow, e.g. to correct a trip charge (Admin only)
+// @Tags companies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Company ID"
+// @Param request body domain.WalletAdjustmentRequest true "Adjustment"
+// @Success 201 {object} domain.WalletTransaction
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/companies/{id}/wallet/adjustments [post]
+func (h *WalletHandler) AdjustWallet(c *gin.Context) {
+	companyID, ok := parseUUIDParam(c, "Invalid company ID")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.WalletAdjustmentRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	tx, err := h.walletUseCase.Adjust(companyID, req, userID)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Adjustment amount rounds to zero",
+			})
+			return
+		}
+		h.respondWalletError(c, err, "Failed to adjust wallet")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tx)
+}
+
+// GetCompanyWallet godoc
+// @Summary Get my company's wallet
+// @Description Balance, credit line and upcoming trips of the user's organization
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.CompanyWallet
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/wallet [get]
+func (h *WalletHandler) GetCompanyWallet(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	h.getWallet(c, companyID)
+}
+
+// GetCompanyWalletTransactions godoc
+// @Summary List my company's wallet transactions
+// @Description Top-ups, trip debits, refunds and adjustments of the user's organization wallet, newest first
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param kind query string false "Filter by kind" Enums(TOP_UP, TOP_UP_REFUND, TRIP_DEBIT, TRIP_REFUND, ADJUSTMENT)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/company/wallet/transactions [get]
+func (h *WalletHandler) GetCompanyWalletTransactions(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	h.listTransactions(c, companyID)
+}
+
+// CreateTopUp godoc
+// @Summary Top up my company's wallet
+// @Description Pay money into the user's organization wallet through one of its payment methods. The wallet is credited once the payment is approved; follow the redirect, if any, to complete it at the gateway
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.WalletTopUpRequest true "Top-up"
+// @Param Idempotency-Key header string false "Key that makes retries of this request return the first response"
+// @Success 201 {object} domain.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/company/wallet/top-ups [post]
+func (h *WalletHandler) CreateTopUp(c *gin.Context) {
+	companyID, ok := currentCompanyID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.WalletTopUpRequest
+	if !h.bindRequest(c, &req) {
+		return
+	}
+
+	payment, err := h.paymentUseCase.CreateTopUp(companyID, req)
+	if err != nil {
+		h.respondWalletError(c, err, "Failed to create top-up")
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+func (h *WalletHandler) getWallet(c *gin.Context, companyID uuid.UUID) {
+	wallet, err := h.walletUseCase.GetWallet(companyID)
+	if err != nil {
+		h.respondWalletError(c, err, "Failed to get wallet")
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+func (h *WalletHandler) listTransactions(c *gin.Context, companyID uuid.UUID) {
+	page, pageSize := parsePagination(c)
+
+	var kind *domain.WalletTransactionKind
+	if value := c.Query("kind"); value != "" {
+		k := domain.WalletTransactionKind(value)
+		switch k {
+		case domain.WalletTransactionTopUp, domain.WalletTransactionTopUpRefund, domain.WalletTransactionTripDebit,
+			domain.WalletTransactionTripRefund, domain.WalletTransactionAdjustment:
+			kind = &k
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid kind",
+				Details: "kind must be TOP_UP, TOP_UP_REFUND, TRIP_DEBIT, TRIP_REFUND or ADJUSTMENT",
+			})
+			return
+		}
+	}
+
+	transactions, total, err := h.walletUseCase.ListTransactions(companyID, kind, page, pageSize)
+	if err != nil {
+		h.respondWalletError(c, err, "Failed to list wallet transactions")
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:       transactions,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	})
+}
+
+func (h *WalletHandler) bindRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Details: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+func (h *WalletHandler) respondWalletError(c *gin.Context, err error, message string) {
+	switch err {
+	case domain.ErrCompanyNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Company not found",
+		})
+	case domain.ErrWalletNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Company has no wallet",
+		})
+	case domain.ErrInvalidInput:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "The currency of an open wallet cannot change",
+		})
+	case domain.ErrWalletCurrency:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Wallets can only be opened in the pricing currency",
+		})
+	case domain.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Wallet was changed by another request, try again",
+		})
+	case domain.ErrPaymentMethodUnavailable:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Payment method is not available for top-ups",
+		})
+	case domain.ErrPaymentFailed:
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error: "Payment gateway error",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: message,
+		})
+	}
+}
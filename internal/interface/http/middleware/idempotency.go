@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyLockTimeout    = 2 * time.Minute
+	idempotencyPruneBatchSize = 1000
+)
+
+// IdempotencyMiddleware makes POST endpoints safe to retry. A request sent
+// with an Idempotency-Key runs once; retries with the same key and body get
+// the stored response back until the key expires, and a different body
+// under the same key is refused.
+type IdempotencyMiddleware struct {
+	store  domain.IdempotencyStore
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+func NewIdempotencyMiddleware(store domain.IdempotencyStore, ttl time.Duration, logger *zap.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:  store,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Idempotent handles the Idempotency-Key of a route. Requests without the
+// header run as usual. Keys belong to the authenticated user, or to the
+// client IP on public routes, and to the route itself. Server errors and
+// panics are not stored, so the request can be retried with the same key.
+func (m *IdempotencyMiddleware) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid request body",
+				})
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		scope := "public " + c.ClientIP()
+		if userID, ok := GetUserID(c); ok {
+			scope = userID.String()
+		}
+		scope += " " + c.Request.Method + " " + c.FullPath()
+
+		now := time.Now()
+		record := &domain.IdempotencyRecord{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: domain.IdempotencyFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			LockedUntil: now.Add(idempotencyLockTimeout),
+			ExpiresAt:   now.Add(m.ttl),
+		}
+
+		existing, err := m.store.Reserve(record)
+		if err != nil {
+			m.logger.Error("Failed to reserve idempotency key", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Internal server error",
+			})
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			m.replay(c, record, existing)
+			return
+		}
+
+		// A panicking handler must not leave the key locked until it expires
+		defer func() {
+			if r := recover(); r != nil {
+				m.release(scope, key)
+				panic(r)
+			}
+		}()
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			m.release(scope, key)
+			return
+		}
+
+		if err := m.store.Complete(scope, key, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			// The request went through; a retry will wait for the lock to
+			// expire instead of replaying
+			m.logger.Error("Failed to store idempotent response", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+// release frees a key whose request failed so it can be retried.
+func (m *IdempotencyMiddleware) release(scope, key string) {
+	if err := m.store.Release(scope, key); err != nil {
+		m.logger.Warn("Failed to release idempotency key", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// replay answers a retry from the stored record.
+func (m *IdempotencyMiddleware) replay(c *gin.Context, record, existing *domain.IdempotencyRecord) {
+	switch {
+	case existing.Fingerprint != record.Fingerprint:
+		m.logger.Warn("Idempotency key reused with a different request", zap.String("key", record.Key))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency-Key was already used with a different request",
+		})
+	case !existing.IsCompleted():
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A request with this Idempotency-Key is still being processed",
+		})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+	}
+	c.Abort()
+}
+
+// PruneExpired deletes expired keys in batches.
+func (m *IdempotencyMiddleware) PruneExpired(ctx context.Context) error {
+	now := time.Now()
+
+	var total int64
+	for {
+		deleted, err := m.store.DeleteExpired(now, idempotencyPruneBatchSize)
+		if err != nil {
+			return err
+		}
+		total += deleted
+		if deleted < idempotencyPruneBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		m.logger.Info("Pruned idempotency keys", zap.Int64("deleted", total))
+	}
+	return nil
+}
+
+// idempotencyWriter keeps a copy of the response body to store it.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
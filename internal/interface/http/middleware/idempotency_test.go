@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// memoryIdempotencyStore keeps records in memory with the semantics of the
+// database store.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	id := record.Scope + "|" + record.Key
+	if existing, ok := s.records[id]; ok && existing.ExpiresAt.After(now) &&
+		(existing.IsCompleted() || existing.LockedUntil.After(now)) {
+		copied := *existing
+		return &copied, nil
+	}
+
+	stored := *record
+	s.records[id] = &stored
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[scope+"|"+key]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseBody = append([]byte(nil), body...)
+	record.CompletedAt = &now
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, scope+"|"+key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryIdempotencyStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+func newIdempotentRouter(store domain.IdempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	idempotency := NewIdempotencyMiddleware(store, time.Hour, zap.NewNop())
+
+	router := gin.New()
+	router.POST("/payments", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	}, idempotency.Idempotent(), handler)
+	return router
+}
+
+func postWithKey(router http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotent_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	first := postWithKey(router, "key-1", `{"amount":15000,"currency":"CLP"}`)
+	retry := postWithKey(router, "key-1", `{"currency": "CLP", "amount": 15000}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+}
+
+func TestIdempotent_RejectsKeyReusedWithDifferentBody(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	postWithKey(router, "key-1", `{"amount":15000}`)
+	w := postWithKey(router, "key-1", `{"amount":16000}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotent_ConflictWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	router := newIdempotentRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		close(started)
+		<-finish
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- postWithKey(router, "key-1", `{"amount":15000}`)
+	}()
+	<-started
+
+	w := postWithKey(router, "key-1", `{"amount":15000}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	close(finish)
+	first := <-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	// Once the first request finished its response is replayed
+	retry := postWithKey(router, "key-1", `{"amount":15000}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotent_ReleasesKeyAfterServerError(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	router := newIdempotentRouter(store, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Payment gateway error"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	failed := postWithKey(router, "key-1", `{"amount":15000}`)
+	require.Equal(t, http.StatusBadGateway, failed.Code)
+	assert.Zero(t, store.count())
+
+	retry := postWithKey(router, "key-1", `{"amount":15000}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotent_WithoutKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	router := newIdempotentRouter(store, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	postWithKey(router, "", `{"amount":15000}`)
+	postWithKey(router, "", `{"amount":15000}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Zero(t, store.count())
+}
+
+func TestIdempotent_RejectsLongKey(t *testing.T) {
+	router := newIdempotentRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := postWithKey(router, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIdempotent_ReleasesKeyAfterPanic(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	router := newIdempotentRouter(store, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	assert.Panics(t, func() {
+		postWithKey(router, "key-1", `{"amount":15000}`)
+	})
+	assert.Zero(t, store.count())
+
+	retry := postWithKey(router, "key-1", `{"amount":15000}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotent_ScopesAnonymousKeysByClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	idempotency := NewIdempotencyMiddleware(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())
+	router := gin.New()
+	router.POST("/pricing/quote", idempotency.Idempotent(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"quote": calls})
+	})
+
+	quote := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pricing/quote", strings.NewReader(`{"km":12}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := quote("203.0.113.10:40000")
+	other := quote("198.51.100.20:40000")
+	retry := quote("203.0.113.10:40001")
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, other.Header().Get(IdempotentReplayedHeader))
+	assert.JSONEq(t, `{"quote":2}`, other.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+}
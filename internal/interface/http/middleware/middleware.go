@@ -83,8 +83,8 @@ func CORS(origins string) gin.HandlerFunc {
 		config.AllowOrigins = originList
 	}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID"}
-	config.ExposeHeaders = []string{"X-Request-ID"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID", IdempotencyKeyHeader}
+	config.ExposeHeaders = []string{"X-Request-ID", IdempotentReplayedHeader}
 	config.AllowCredentials = true
 	return cors.New(config)
 }
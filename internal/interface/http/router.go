@@ -42,8 +42,8 @@ func NewRouter(
 		authHandler:            handler.NewAuthHandler(authUseCase, validator, logger),
 		userHandler:            handler.NewUserHandler(userUseCase, validator, logger),
 		driverHandler:          handler.NewDriverHandler(driverUseCase, validator, logger),
-		driverDashboardHandler: handler.NewDriverDashboardHandler(driverUseCase, logger),
-		reservationHandler:     handler.NewReservationHandler(reservationUseCase, validator, logger),
+		driverDashboardHandler: handler.NewDriverDashboardHandler(driverUseCase, nil, validator, logger),
+		reservationHandler:     handler.NewReservationHandler(reservationUseCase, nil, validator, logger),
 		paymentHandler:         handler.NewPaymentHandler(paymentUseCase, validator, logger),
 		companyHandler:         handler.NewCompanyHandler(companyUseCase, validator, logger),
 		vehicleHandler:         handler.NewVehicleHandler(vehicleUseCase, validator, logger),
@@ -173,7 +173,6 @@ func (r *Router) SetupRoutes(engine *gin.Engine) {
 				driver.GET("/trips", r.driverDashboardHandler.GetDriverTrips)
 				driver.GET("/vehicle", r.driverDashboardHandler.GetDriverVehicle)
 				driver.GET("/profile", r.driverDashboardHandler.GetDriverProfile)
-				driver.PATCH("/trips/:tripId/status", r.driverDashboardHandler.UpdateTripStatus)
 			}
 
 			// Support routes (All authenticated users)
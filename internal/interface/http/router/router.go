@@ -9,22 +9,48 @@ import (
 )
 
 type RouteHandlers struct {
-	Auth            *handler.AuthHandler
-	User            *handler.UserHandler
-	Driver          *handler.DriverHandler
-	DriverDashboard *handler.DriverDashboardHandler
-	Reservation     *handler.ReservationHandler
-	Payment         *handler.PaymentHandler
-	Company         *handler.CompanyHandler
-	CompanyDetail   *handler.CompanyDetailHandler
-	Vehicle         *handler.VehicleHandler
-	Support         *handler.SupportHandler
-	Admin           *handler.AdminHandler
-	Billing         *handler.BillingHandler
-	Pricing         *handlers.PricingHandler
+	Auth                  *handler.AuthHandler
+	User                  *handler.UserHandler
+	Driver                *handler.DriverHandler
+	DriverDashboard       *handler.DriverDashboardHandler
+	Reservation           *handler.ReservationHandler
+	Payment               *handler.PaymentHandler
+	PaymentReturn         *handler.PaymentReturnHandler
+	PaymentReconciliation *handler.PaymentReconciliationHandler
+	Company               *handler.CompanyHandler
+	CompanyDetail         *handler.CompanyDetailHandler
+	Vehicle               *handler.VehicleHandler
+	Support               *handler.SupportHandler
+	Admin                 *handler.AdminHandler
+	Billing               *handler.BillingHandler
+	Invoice               *handler.InvoiceHandler
+	Wallet                *handler.WalletHandler
+	TaxDocument           *handler.TaxDocumentHandler
+	Pricing               *handlers.PricingHandler
+	TripOffer             *handler.TripOfferHandler
+	TripProgress          *handler.TripProgressHandler
+	Tracking              *handler.TrackingHandler
+	TrackingLink          *handler.TrackingLinkHandler
+	Feedback              *handler.FeedbackHandler
+	Compliance            *handler.ComplianceHandler
+	Document              *handler.DocumentHandler
+	File                  *handler.FileHandler
+	Earnings              *handler.EarningsHandler
+	Shift                 *handler.ShiftHandler
+	Maintenance           *handler.MaintenanceHandler
+	TripUsage             *handler.TripUsageHandler
+	Utilization           *handler.UtilizationHandler
+	Incident              *handler.IncidentHandler
 }
 
-func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *middleware.AuthMiddleware) {
+func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *middleware.AuthMiddleware, idempotencyMiddleware *middleware.IdempotencyMiddleware) {
+	// Creation endpoints accept an Idempotency-Key so retries do not create
+	// duplicates
+	idempotent := func(c *gin.Context) { c.Next() }
+	if idempotencyMiddleware != nil {
+		idempotent = idempotencyMiddleware.Idempotent()
+	}
+
 	// API v1 routes
 	v1 := engine.Group("/api/v1")
 	{
@@ -41,6 +67,36 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 			auth.GET("/tokens", handlers.User.ListRegistrationTokens)
 		}
 
+		// Public tracking and rating links (token acts as the credential)
+		public := v1.Group("/public")
+		{
+			if handlers.TrackingLink != nil {
+				public.GET("/tracking/:token", handlers.TrackingLink.GetPublicTracking)
+			}
+			if handlers.Feedback != nil {
+				public.GET("/feedback/:token", handlers.Feedback.GetPublicFeedbackPrompt)
+				public.POST("/feedback/:token", handlers.Feedback.SubmitPublicFeedback)
+			}
+			if handlers.Document != nil {
+				public.GET("/documents/:id", handlers.Document.GetPublicPhoto)
+			}
+			if handlers.File != nil {
+				public.GET("/files/*key", handlers.File.ServeFile)
+			}
+			// Payment gateways send customers back here; Webpay by GET or POST
+			if handlers.PaymentReturn != nil {
+				public.GET("/payments/webpay/return", handlers.PaymentReturn.WebpayReturn)
+				public.POST("/payments/webpay/return", handlers.PaymentReturn.WebpayReturn)
+				public.GET("/payments/mercadopago/return", handlers.PaymentReturn.MercadoPagoReturn)
+				public.GET("/payments/stripe/return", handlers.PaymentReturn.StripeReturn)
+			}
+			// Signed gateway webhooks
+			if handlers.PaymentReconciliation != nil {
+				public.POST("/payments/mercadopago/notifications", handlers.PaymentReconciliation.MercadoPagoNotification)
+				public.POST("/payments/stripe/notifications", handlers.PaymentReconciliation.StripeNotification)
+			}
+		}
+
 		// Protected routes (authentication required)
 		protected := v1.Group("")
 		protected.Use(authMiddleware.RequireAuth())
@@ -74,6 +130,46 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 				drivers.PATCH("/:id", handlers.Driver.UpdateDriver)
 				drivers.DELETE("/:id", handlers.Driver.DeleteDriver)
 				drivers.GET("/:id/kpis", handlers.Driver.GetDriverKPIs)
+				drivers.GET("/:id/license", handlers.Driver.GetDriverLicense)
+				drivers.PUT("/:id/license", handlers.Driver.UpdateDriverLicense)
+				drivers.GET("/:id/background-check", handlers.Driver.GetDriverBackgroundCheck)
+				drivers.PUT("/:id/background-check", handlers.Driver.UpdateDriverBackgroundCheck)
+				drivers.GET("/:id/availability", handlers.Driver.GetDriverAvailability)
+				drivers.PUT("/:id/availability", handlers.Driver.UpdateDriverAvailability)
+				drivers.GET("/:id/availability/calendar", handlers.Driver.GetDriverAvailabilityCalendar)
+				if handlers.Feedback != nil {
+					drivers.GET("/:id/feedback", handlers.Feedback.GetDriverFeedback)
+				}
+				if handlers.Compliance != nil {
+					drivers.GET("/:id/compliance", handlers.Compliance.GetDriverCompliance)
+				}
+				if handlers.Document != nil {
+					drivers.POST("/:id/documents", handlers.Document.UploadDriverDocument)
+					drivers.GET("/:id/documents", handlers.Document.ListDriverDocuments)
+				}
+				if handlers.Vehicle != nil {
+					drivers.GET("/:id/vehicle-assignments", handlers.Vehicle.ListDriverVehicleAssignments)
+				}
+			}
+
+			// Driver earnings ledger (Admin only)
+			if handlers.Earnings != nil {
+				driverEarnings := protected.Group("/drivers")
+				driverEarnings.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					driverEarnings.GET("/:id/earnings", handlers.Earnings.GetDriverEarnings)
+					driverEarnings.POST("/:id/earnings", handlers.Earnings.CreateEarningEntry)
+					driverEarnings.GET("/:id/balance", handlers.Earnings.GetDriverBalance)
+				}
+			}
+
+			// Driver rosters (Admin only)
+			if handlers.Shift != nil {
+				driverShifts := protected.Group("/drivers")
+				driverShifts.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					driverShifts.GET("/:id/roster.ics", handlers.Shift.GetDriverRoster)
+				}
 			}
 
 			// Driver dashboard routes (Driver role only)
@@ -85,7 +181,57 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 					driverDashboard.GET("/trips", handlers.DriverDashboard.GetDriverTrips)
 					driverDashboard.GET("/vehicle", handlers.DriverDashboard.GetDriverVehicle)
 					driverDashboard.GET("/profile", handlers.DriverDashboard.GetDriverProfile)
-					driverDashboard.PATCH("/trips/:tripId/status", handlers.DriverDashboard.UpdateTripStatus)
+					driverDashboard.GET("/availability", handlers.DriverDashboard.GetMyAvailability)
+					driverDashboard.PUT("/availability", handlers.DriverDashboard.UpdateMyAvailability)
+					driverDashboard.GET("/availability/calendar", handlers.DriverDashboard.GetMyAvailabilityCalendar)
+					if handlers.TripProgress != nil {
+						driverDashboard.GET("/trips/:tripId/progress", handlers.TripProgress.GetTripProgress)
+						driverDashboard.PATCH("/trips/:tripId/status", handlers.TripProgress.UpdateTripStatus)
+					}
+					if handlers.TripUsage != nil {
+						driverDashboard.GET("/trips/:tripId/usage", handlers.TripUsage.GetTripUsage)
+						driverDashboard.POST("/trips/:tripId/odometer", handlers.TripUsage.RecordOdometer)
+						driverDashboard.POST("/trips/:tripId/fuel", handlers.TripUsage.RecordFuelEvent)
+					}
+					if handlers.Tracking != nil {
+						driverDashboard.POST("/locations", handlers.Tracking.RecordLocations)
+					}
+					if handlers.Feedback != nil {
+						driverDashboard.GET("/feedback", handlers.Feedback.GetMyFeedback)
+					}
+					if handlers.Compliance != nil {
+						driverDashboard.GET("/compliance", handlers.Compliance.GetMyCompliance)
+					}
+					if handlers.Document != nil {
+						driverDashboard.POST("/documents", handlers.Document.UploadMyDocument)
+						driverDashboard.GET("/documents", handlers.Document.ListMyDocuments)
+						driverDashboard.GET("/documents/:id/download", handlers.Document.GetMyDownloadURL)
+					}
+					if handlers.Shift != nil {
+						driverDashboard.GET("/shifts", handlers.Shift.GetMyShifts)
+						driverDashboard.GET("/roster.ics", handlers.Shift.GetMyRoster)
+						driverDashboard.GET("/shift-requests", handlers.Shift.GetMyShiftRequests)
+						driverDashboard.POST("/shift-requests", handlers.Shift.SubmitShiftRequest)
+						driverDashboard.DELETE("/shift-requests/:id", handlers.Shift.CancelMyShiftRequest)
+					}
+					if handlers.Earnings != nil {
+						driverDashboard.GET("/earnings", handlers.Earnings.GetMyEarnings)
+						driverDashboard.GET("/balance", handlers.Earnings.GetMyBalance)
+						driverDashboard.GET("/settlements", handlers.Earnings.GetMySettlements)
+						driverDashboard.GET("/settlements/:id", handlers.Earnings.GetMySettlement)
+						driverDashboard.GET("/settlements/:id/export", handlers.Earnings.ExportMySettlement)
+					}
+					if handlers.Incident != nil {
+						driverDashboard.POST("/incidents", handlers.Incident.ReportIncident)
+						driverDashboard.GET("/incidents", handlers.Incident.GetMyIncidents)
+						driverDashboard.GET("/incidents/:id", handlers.Incident.GetMyIncident)
+						driverDashboard.POST("/incidents/:id/photos", handlers.Incident.UploadMyIncidentPhoto)
+					}
+					if handlers.TripOffer != nil {
+						driverDashboard.GET("/offers", handlers.TripOffer.GetDriverOffers)
+						driverDashboard.POST("/offers/:offerId/accept", handlers.TripOffer.AcceptOffer)
+						driverDashboard.POST("/offers/:offerId/decline", handlers.TripOffer.DeclineOffer)
+					}
 				}
 			}
 
@@ -94,26 +240,72 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 			{
 				reservations.GET("", handlers.Reservation.ListReservations)
 				reservations.GET("/my", handlers.Reservation.GetMyReservations) // User's own reservations
-				reservations.POST("", handlers.Reservation.CreateReservation)
+				reservations.GET("/vehicle-split", handlers.Reservation.SuggestVehicleSplit)
+				reservations.POST("", idempotent, handlers.Reservation.CreateReservation)
 				// Specific routes MUST come before generic /:id routes
 				reservations.PATCH("/:id/status", handlers.Reservation.ChangeReservationStatus)
 				reservations.PATCH("/:id/driver", handlers.Reservation.AssignDriver)
 				reservations.GET("/:id/test", func(c *gin.Context) { c.JSON(200, gin.H{"test": "works", "id": c.Param("id")}) })
 				reservations.GET("/:id/timeline", handlers.Reservation.GetReservationTimeline)
 				reservations.POST("/:id/timeline", handlers.Reservation.AddTimelineEvent)
+				if handlers.Tracking != nil {
+					reservations.GET("/:id/tracking", handlers.Tracking.GetReservationLocation)
+					reservations.GET("/:id/tracking/stream", handlers.Tracking.StreamReservationLocation)
+				}
+				if handlers.TrackingLink != nil {
+					reservations.POST("/:id/tracking-links", handlers.TrackingLink.CreateTrackingLink)
+					reservations.GET("/:id/tracking-links", handlers.TrackingLink.ListTrackingLinks)
+					reservations.DELETE("/:id/tracking-links/:linkId", handlers.TrackingLink.RevokeTrackingLink)
+				}
+				if handlers.Feedback != nil {
+					reservations.POST("/:id/feedback", handlers.Feedback.SubmitFeedback)
+				}
 				// Generic routes come last
 				reservations.GET("/:id", handlers.Reservation.GetReservation)
 				reservations.PATCH("/:id", handlers.Reservation.UpdateReservation)
 			}
 
+			// Dispatch routes (Admin only)
+			if handlers.TripOffer != nil {
+				dispatch := protected.Group("/reservations")
+				dispatch.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					dispatch.POST("/:id/offers", handlers.TripOffer.DispatchOffers)
+					dispatch.GET("/:id/offers", handlers.TripOffer.GetReservationOffers)
+				}
+			}
+
+			// Feedback routes (All authenticated users)
+			if handlers.Feedback != nil {
+				feedback := protected.Group("/feedback")
+				{
+					feedback.GET("/pending", handlers.Feedback.GetPendingFeedback)
+				}
+			}
+
 			// Payments routes (All authenticated users)
 			payments := protected.Group("/payments")
 			{
-				payments.POST("", handlers.Payment.CreatePayment)
+				payments.POST("", idempotent, handlers.Payment.CreatePayment)
+				payments.GET("/methods", handlers.Payment.ListPaymentMethods)
 				payments.GET("/:id", handlers.Payment.GetPayment)
 				payments.POST("/:id/simulate", handlers.Payment.SimulatePayment)
 			}
 
+			// Payment gateway status, confirmations and refunds (Admin only)
+			adminPayments := protected.Group("/payments")
+			adminPayments.Use(authMiddleware.RequireRole("ADMIN"))
+			{
+				adminPayments.GET("/:id/gateway-status", handlers.Payment.GetPaymentGatewayStatus)
+				adminPayments.POST("/:id/confirm", handlers.Payment.ConfirmPayment)
+				adminPayments.POST("/:id/refunds", handlers.Payment.RefundPayment)
+				adminPayments.GET("/:id/refunds", handlers.Payment.GetPaymentRefunds)
+				if handlers.PaymentReconciliation != nil {
+					adminPayments.GET("/reconciliation", handlers.PaymentReconciliation.GetReconciliationReport)
+					adminPayments.POST("/reconcile", handlers.PaymentReconciliation.ReconcilePendingPayments)
+				}
+			}
+
 			// Companies routes (Admin can see all, User and Company can see their org)
 			companies := protected.Group("/companies")
 			companies.Use(authMiddleware.RequireRole("ADMIN", "USER", "COMPANY"))
@@ -121,6 +313,7 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 			{
 				companies.GET("", handlers.Company.ListCompanies)
 				companies.GET("/:id", handlers.Company.GetCompany)
+				companies.GET("/:id/payment-methods", handlers.Payment.GetCompanyPaymentMethods)
 			}
 
 			// Company detail route (separate to avoid org scope issues)
@@ -137,6 +330,7 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 				adminCompanies.POST("", handlers.Company.CreateCompany)
 				adminCompanies.PUT("/:id", handlers.Company.UpdateCompany)
 				adminCompanies.DELETE("/:id", handlers.Company.DeleteCompany)
+				adminCompanies.PUT("/:id/payment-methods", handlers.Payment.UpdateCompanyPaymentMethods)
 			}
 
 			// Vehicles routes (Admin and Company)
@@ -150,6 +344,15 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 					vehicles.PUT("/:id", handlers.Vehicle.UpdateVehicle)
 					vehicles.DELETE("/:id", handlers.Vehicle.DeleteVehicle)
 					vehicles.POST("/:id/assign", handlers.Vehicle.AssignVehicleToDriver)
+					vehicles.GET("/:id/assignments", handlers.Vehicle.ListVehicleAssignments)
+					vehicles.GET("/:id/driver-at", handlers.Vehicle.GetVehicleDriverAt)
+					if handlers.Compliance != nil {
+						vehicles.GET("/:id/compliance", handlers.Compliance.GetVehicleCompliance)
+					}
+					if handlers.Document != nil {
+						vehicles.POST("/:id/photos", handlers.Document.UploadVehiclePhoto)
+						vehicles.GET("/:id/documents", handlers.Document.ListVehicleDocuments)
+					}
 				}
 			}
 
@@ -175,6 +378,7 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 				companyBilling.Use(authMiddleware.RequireOrgScope())
 				{
 					companyBilling.GET("/payments", handlers.Billing.GetCompanyPayments)
+					companyBilling.GET("/payments/export", handlers.Billing.ExportCompanyPayments)
 				}
 			}
 
@@ -187,10 +391,228 @@ func SetupRoutes(engine *gin.Engine, handlers RouteHandlers, authMiddleware *mid
 				}
 			}
 
+			// Feedback moderation routes (Admin only)
+			if handlers.Feedback != nil {
+				moderation := protected.Group("/admin/feedback")
+				moderation.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					moderation.GET("", handlers.Feedback.ListFeedback)
+					moderation.PATCH("/:id", handlers.Feedback.ModerateFeedback)
+				}
+			}
+
+			// Document download and review routes
+			if handlers.Document != nil {
+				documents := protected.Group("/documents")
+				documents.Use(authMiddleware.RequireRole("ADMIN", "COMPANY"))
+				{
+					documents.GET("/:id/download", handlers.Document.GetDownloadURL)
+				}
+
+				review := protected.Group("/admin/documents")
+				review.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					review.GET("", handlers.Document.ListDocuments)
+					review.PATCH("/:id", handlers.Document.ReviewDocument)
+				}
+			}
+
+			// Earnings and settlement routes (Admin only)
+			if handlers.Earnings != nil {
+				earnings := protected.Group("/admin/earnings")
+				earnings.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					earnings.GET("", handlers.Earnings.ListEarnings)
+				}
+
+				settlements := protected.Group("/admin/settlements")
+				settlements.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					settlements.GET("", handlers.Earnings.ListSettlements)
+					settlements.POST("/generate", handlers.Earnings.GenerateSettlements)
+					settlements.GET("/:id", handlers.Earnings.GetSettlement)
+					settlements.DELETE("/:id", handlers.Earnings.DiscardSettlement)
+					settlements.POST("/:id/approve", handlers.Earnings.ApproveSettlement)
+					settlements.POST("/:id/pay", handlers.Earnings.MarkSettlementPaid)
+					settlements.GET("/:id/export", handlers.Earnings.ExportSettlement)
+				}
+			}
+
+			// Postpaid billing routes
+			if handlers.Invoice != nil {
+				billingAccounts := protected.Group("/companies")
+				billingAccounts.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					billingAccounts.GET("/:id/billing-account", handlers.Invoice.GetBillingAccount)
+					billingAccounts.PUT("/:id/billing-account", handlers.Invoice.UpsertBillingAccount)
+					billingAccounts.GET("/:id/billing-account/period", handlers.Invoice.GetBillingPeriod)
+					billingAccounts.POST("/:id/billing-account/close-period", handlers.Invoice.CloseBillingPeriod)
+				}
+
+				invoices := protected.Group("/admin/invoices")
+				invoices.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					invoices.GET("", handlers.Invoice.ListInvoices)
+					invoices.GET("/:id", handlers.Invoice.GetInvoice)
+					invoices.POST("/:id/void", handlers.Invoice.VoidInvoice)
+					invoices.GET("/:id/pdf", handlers.Invoice.DownloadInvoicePDF)
+				}
+
+				companyInvoices := protected.Group("/company")
+				companyInvoices.Use(authMiddleware.RequireRole("COMPANY"))
+				companyInvoices.Use(authMiddleware.RequireOrgScope())
+				{
+					companyInvoices.GET("/billing-period", handlers.Invoice.GetCompanyBillingPeriod)
+					companyInvoices.GET("/invoices", handlers.Invoice.GetCompanyInvoices)
+					companyInvoices.GET("/invoices/:id", handlers.Invoice.GetCompanyInvoice)
+					companyInvoices.GET("/invoices/:id/pdf", handlers.Invoice.DownloadCompanyInvoicePDF)
+				}
+			}
+
+			// Company wallet routes
+			if handlers.Wallet != nil {
+				wallets := protected.Group("/companies")
+				wallets.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					wallets.GET("/:id/wallet", handlers.Wallet.GetWallet)
+					wallets.PUT("/:id/wallet", handlers.Wallet.UpsertWallet)
+					wallets.GET("/:id/wallet/transactions", handlers.Wallet.GetWalletTransactions)
+					wallets.POST("/:id/wallet/adjustments", handlers.Wallet.AdjustWallet)
+				}
+
+				companyWallet := protected.Group("/company/wallet")
+				companyWallet.Use(authMiddleware.RequireRole("COMPANY"))
+				companyWallet.Use(authMiddleware.RequireOrgScope())
+				{
+					companyWallet.GET("", handlers.Wallet.GetCompanyWallet)
+					companyWallet.GET("/transactions", handlers.Wallet.GetCompanyWalletTransactions)
+					companyWallet.POST("/top-ups", idempotent, handlers.Wallet.CreateTopUp)
+				}
+			}
+
+			// Electronic tax document routes (Admin only)
+			if handlers.TaxDocument != nil {
+				taxDocuments := protected.Group("/admin/tax-documents")
+				taxDocuments.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					taxDocuments.GET("", handlers.TaxDocument.ListTaxDocuments)
+					taxDocuments.POST("", idempotent, handlers.TaxDocument.IssueTaxDocument)
+					taxDocuments.GET("/folio-ranges", handlers.TaxDocument.ListFolioRanges)
+					taxDocuments.POST("/folio-ranges", handlers.TaxDocument.UploadFolioRange)
+					taxDocuments.GET("/:id", handlers.TaxDocument.GetTaxDocument)
+					taxDocuments.GET("/:id/xml", handlers.TaxDocument.DownloadTaxDocumentXML)
+					taxDocuments.POST("/:id/credit-note", idempotent, handlers.TaxDocument.CreateCreditNote)
+				}
+			}
+
+			// Shift planning routes (Admin only)
+			if handlers.Shift != nil {
+				shifts := protected.Group("/admin/shifts")
+				shifts.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					shifts.GET("", handlers.Shift.ListShifts)
+					shifts.POST("", handlers.Shift.CreateShift)
+					shifts.GET("/:id", handlers.Shift.GetShift)
+					shifts.PATCH("/:id", handlers.Shift.UpdateShift)
+				}
+
+				shiftRequests := protected.Group("/admin/shift-requests")
+				shiftRequests.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					shiftRequests.GET("", handlers.Shift.ListShiftRequests)
+					shiftRequests.PATCH("/:id", handlers.Shift.ReviewShiftRequest)
+				}
+			}
+
+			// Vehicle maintenance routes (Admin only)
+			if handlers.Maintenance != nil {
+				vehicleMaintenance := protected.Group("/vehicles")
+				vehicleMaintenance.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					vehicleMaintenance.GET("/:id/maintenance", handlers.Maintenance.ListVehicleMaintenance)
+					vehicleMaintenance.POST("/:id/maintenance", handlers.Maintenance.CreateVehicleMaintenance)
+					vehicleMaintenance.GET("/:id/maintenance-plans", handlers.Maintenance.ListVehicleMaintenancePlans)
+					vehicleMaintenance.POST("/:id/maintenance-plans", handlers.Maintenance.CreateMaintenancePlan)
+					vehicleMaintenance.GET("/:id/maintenance-costs", handlers.Maintenance.GetVehicleMaintenanceCosts)
+				}
+
+				maintenance := protected.Group("/admin/maintenance")
+				maintenance.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					maintenance.GET("", handlers.Maintenance.ListMaintenance)
+					maintenance.GET("/:id", handlers.Maintenance.GetMaintenance)
+					maintenance.PATCH("/:id", handlers.Maintenance.UpdateMaintenance)
+					maintenance.POST("/:id/attachments", handlers.Maintenance.UploadMaintenanceAttachment)
+				}
+
+				maintenancePlans := protected.Group("/admin/maintenance-plans")
+				maintenancePlans.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					maintenancePlans.GET("", handlers.Maintenance.ListMaintenancePlans)
+					maintenancePlans.PATCH("/:id", handlers.Maintenance.UpdateMaintenancePlan)
+				}
+
+				maintenanceCosts := protected.Group("/admin/maintenance-costs")
+				maintenanceCosts.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					maintenanceCosts.GET("", handlers.Maintenance.ListMaintenanceCosts)
+				}
+			}
+
+			// Vehicle cost and efficiency routes (Admin only)
+			if handlers.TripUsage != nil {
+				vehicleUsage := protected.Group("/vehicles")
+				vehicleUsage.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					vehicleUsage.GET("/:id/usage", handlers.TripUsage.GetVehicleUsage)
+				}
+
+				fleetUsage := protected.Group("/admin/vehicle-usage")
+				fleetUsage.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					fleetUsage.GET("", handlers.TripUsage.ListVehicleUsage)
+				}
+			}
+
+			// Fleet utilization and availability routes (Admin only)
+			if handlers.Utilization != nil {
+				vehicleUtilization := protected.Group("/vehicles")
+				vehicleUtilization.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					vehicleUtilization.GET("/:id/utilization", handlers.Utilization.GetVehicleUtilization)
+				}
+
+				fleet := protected.Group("/admin")
+				fleet.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					fleet.GET("/fleet-utilization", handlers.Utilization.GetFleetUtilization)
+					fleet.GET("/fleet-availability", handlers.Utilization.ListFreeVehicles)
+				}
+			}
+
+			// Vehicle incident routes (Admin only)
+			if handlers.Incident != nil {
+				vehicleIncidents := protected.Group("/vehicles")
+				vehicleIncidents.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					vehicleIncidents.GET("/:id/incidents", handlers.Incident.ListVehicleIncidents)
+				}
+
+				incidents := protected.Group("/admin/incidents")
+				incidents.Use(authMiddleware.RequireRole("ADMIN"))
+				{
+					incidents.GET("", handlers.Incident.ListIncidents)
+					incidents.POST("", handlers.Incident.CreateIncident)
+					incidents.GET("/:id", handlers.Incident.GetIncident)
+					incidents.PATCH("/:id", handlers.Incident.UpdateIncident)
+					incidents.POST("/:id/photos", handlers.Incident.UploadIncidentPhoto)
+				}
+			}
+
 			// Pricing routes (public for now, can be protected later)
 			pricing := v1.Group("/pricing")
 			{
-				pricing.POST("/quote", handlers.Pricing.Quote)
+				pricing.POST("/quote", idempotent, handlers.Pricing.Quote)
 			}
 		}
 	}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type ComplianceUseCase struct {
+	complianceRepo domain.ComplianceRepository
+	driverRepo     domain.DriverRepository
+	vehicleRepo    domain.VehicleRepository
+	userRepo       domain.UserRepository
+	emailService   domain.EmailService
+	logger         *zap.Logger
+}
+
+func NewComplianceUseCase(
+	complianceRepo domain.ComplianceRepository,
+	driverRepo domain.DriverRepository,
+	vehicleRepo domain.VehicleRepository,
+	userRepo domain.UserRepository,
+	emailService domain.EmailService,
+	logger *zap.Logger,
+) *ComplianceUseCase {
+	return &ComplianceUseCase{
+		complianceRepo: complianceRepo,
+		driverRepo:     driverRepo,
+		vehicleRepo:    vehicleRepo,
+		userRepo:       userRepo,
+		emailService:   emailService,
+		logger:         logger,
+	}
+}
+
+// GetDriverCompliance evaluates a driver together with the vehicle assigned to them.
+func (uc *ComplianceUseCase) GetDriverCompliance(driverID string) (*domain.DriverCompliance, error) {
+	driver, err := uc.driverRepo.GetByID(driverID)
+	if err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for compliance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	vehicle, err := uc.vehicleRepo.GetByDriverID(driverID)
+	if err != nil && err != domain.ErrNotFound {
+		uc.logger.Error("Failed to get driver vehicle for compliance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return domain.EvaluateDriverCompliance(driver, vehicle, time.Now()), nil
+}
+
+func (uc *ComplianceUseCase) GetVehicleCompliance(vehicleID string) (*domain.VehicleCompliance, error) {
+	vehicle, err := uc.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get vehicle for compliance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return domain.EvaluateVehicleCompliance(vehicle, time.Now()), nil
+}
+
+// EnsureDriverEligible implements domain.ComplianceChecker. It returns a
+// *domain.ComplianceError listing the blocking issues when the driver cannot
+// take trips.
+func (uc *ComplianceUseCase) EnsureDriverEligible(driverID string) error {
+	compliance, err := uc.GetDriverCompliance(driverID)
+	if err != nil {
+		return err
+	}
+
+	if !compliance.Eligible {
+		return &domain.ComplianceError{SubjectID: driverID, Issues: compliance.BlockingIssues()}
+	}
+	return nil
+}
+
+// EnsureVehicleAssignable implements domain.ComplianceChecker. Only the
+// vehicle's documents and the driver's license class are checked here; the
+// driver's own documents are enforced when trips are assigned.
+func (uc *ComplianceUseCase) EnsureVehicleAssignable(vehicleID, driverID string) error {
+	vehicle, err := uc.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return err
+		}
+		uc.logger.Error("Failed to get vehicle for compliance", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	driver, err := uc.driverRepo.GetByID(driverID)
+	if err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for compliance", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	now := time.Now()
+	issues := domain.EvaluateVehicleCompliance(vehicle, now).BlockingIssues()
+	for _, issue := range domain.EvaluateDriverCompliance(driver, vehicle, now).Issues {
+		if issue.Code == domain.ComplianceIssueLicenseClassMismatch {
+			issues = append(issues, issue)
+		}
+	}
+
+	if len(issues) > 0 {
+		return &domain.ComplianceError{SubjectID: vehicleID, Issues: issues}
+	}
+	return nil
+}
+
+// SendExpiryReminders emails drivers and admins about documents crossing the
+// 30/15/7 day thresholds. It is run daily by the scheduler; each threshold is
+// notified once per document and expiry date. A reminder is only recorded once
+// its emails went out, so a failed send is retried on the next run.
+func (uc *ComplianceUseCase) SendExpiryReminders(ctx context.Context) error {
+	now := time.Now()
+	horizon := now.AddDate(0, 0, domain.ComplianceReminderDays[0])
+
+	documents, err := uc.complianceRepo.ListExpiringDocuments(now, horizon)
+	if err != nil {
+		return err
+	}
+
+	var due []*domain.ExpiringDocument
+	var adminItems []domain.ComplianceReminderItem
+	driverItems := make(map[string][]domain.ComplianceReminderItem)
+	driverNames := make(map[string]string)
+
+	for _, doc := range documents {
+		threshold := doc.ReminderThreshold(now)
+		if threshold == 0 {
+			continue
+		}
+
+		sent, err := uc.complianceRepo.HasReminder(doc, threshold)
+		if err != nil {
+			return err
+		}
+		if sent {
+			continue
+		}
+
+		due = append(due, doc)
+		item := complianceReminderItem(doc, now)
+		adminItems = append(adminItems, item)
+		if doc.DriverEmail != nil && *doc.DriverEmail != "" {
+			driverItems[*doc.DriverEmail] = append(driverItems[*doc.DriverEmail], item)
+			if doc.DriverName != nil {
+				driverNames[*doc.DriverEmail] = *doc.DriverName
+			}
+		}
+	}
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	driversSent := make(map[string]bool, len(driverItems))
+	for email, items := range driverItems {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := uc.emailService.SendComplianceReminder(email, driverNames[email], items); err != nil {
+			uc.logger.Warn("Failed to send compliance reminder to driver", zap.Error(err), zap.String("email", email))
+			continue
+		}
+		driversSent[email] = true
+	}
+
+	admins, err := uc.listAdmins()
+	if err != nil {
+		return err
+	}
+	adminsSent := 0
+	for _, admin := range admins {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := uc.emailService.SendComplianceReminder(admin.Email, admin.Name, adminItems); err != nil {
+			uc.logger.Warn("Failed to send compliance reminder to admin", zap.Error(err), zap.String("email", admin.Email))
+			continue
+		}
+		adminsSent++
+	}
+	if len(admins) > 0 && adminsSent == 0 {
+		uc.logger.Warn("No admin received the compliance reminders, retrying next run", zap.Int("documents", len(due)))
+		return nil
+	}
+
+	recorded := 0
+	for _, doc := range due {
+		if doc.DriverEmail != nil && *doc.DriverEmail != "" && !driversSent[*doc.DriverEmail] {
+			continue
+		}
+		if err := uc.complianceRepo.RecordReminder(doc, doc.ReminderThreshold(now), now); err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+		recorded++
+	}
+
+	uc.logger.Info("Compliance reminders sent",
+		zap.Int("documents", len(due)),
+		zap.Int("recorded", recorded),
+		zap.Int("drivers", len(driversSent)),
+		zap.Int("admins", adminsSent))
+	return nil
+}
+
+func (uc *ComplianceUseCase) listAdmins() ([]*domain.User, error) {
+	role := domain.UserRoleAdmin
+	status := domain.UserStatusActive
+	admins, _, err := uc.userRepo.List(domain.ListUsersRequest{
+		Role:     &role,
+		Status:   &status,
+		Page:     1,
+		PageSize: 100,
+		Sort:     "name",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admins: %w", err)
+	}
+	return admins, nil
+}
+
+func complianceReminderItem(doc *domain.ExpiringDocument, now time.Time) domain.ComplianceReminderItem {
+	item := domain.ComplianceReminderItem{
+		ExpiresAt: doc.ExpiresAt.Format("02/01/2006"),
+		DaysLeft:  doc.DaysLeft(now),
+	}
+
+	driverName := "sin conductor asignado"
+	if doc.DriverName != nil {
+		driverName = *doc.DriverName
+	}
+
+	vehicle := doc.SubjectID
+	if doc.VehiclePlate != nil {
+		vehicle = "Patente " + *doc.VehiclePlate
+	}
+
+	switch doc.DocumentType {
+	case domain.ComplianceDocumentLicense:
+		item.Document = "Licencia de conducir"
+		item.Subject = driverName
+	case domain.ComplianceDocumentInsurance:
+		item.Document = "Seguro del vehículo"
+		item.Subject = fmt.Sprintf("%s (%s)", vehicle, driverName)
+	case domain.ComplianceDocumentInspection:
+		item.Document = "Revisión técnica"
+		item.Subject = fmt.Sprintf("%s (%s)", vehicle, driverName)
+	}
+
+	return item
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type MockComplianceRepository struct {
+	mock.Mock
+}
+
+func (m *MockComplianceRepository) ListExpiringDocuments(from, to time.Time) ([]*domain.ExpiringDocument, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]*domain.ExpiringDocument), args.Error(1)
+}
+
+func (m *MockComplianceRepository) HasReminder(doc *domain.ExpiringDocument, thresholdDays int) (bool, error) {
+	args := m.Called(doc, thresholdDays)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockComplianceRepository) RecordReminder(doc *domain.ExpiringDocument, thresholdDays int, sentAt time.Time) error {
+	args := m.Called(doc, thresholdDays, sentAt)
+	return args.Error(0)
+}
+
+// MockEmailService implements the emails the use cases under test send; any
+// other call panics.
+type MockEmailService struct {
+	domain.EmailService
+	mock.Mock
+}
+
+func (m *MockEmailService) SendComplianceReminder(to, name string, items []domain.ComplianceReminderItem) error {
+	args := m.Called(to, name, items)
+	return args.Error(0)
+}
+
+func (m *MockEmailService) SendMaintenanceReminder(to, name string, items []domain.MaintenanceReminderItem) error {
+	args := m.Called(to, name, items)
+	return args.Error(0)
+}
+
+func expiringLicense(driverID, email string, daysLeft int) *domain.ExpiringDocument {
+	name := "Conductor " + driverID
+	return &domain.ExpiringDocument{
+		DocumentType: domain.ComplianceDocumentLicense,
+		SubjectID:    driverID,
+		ExpiresAt:    time.Now().AddDate(0, 0, daysLeft).Add(-time.Hour),
+		DriverID:     &driverID,
+		DriverName:   &name,
+		DriverEmail:  &email,
+	}
+}
+
+func TestComplianceUseCase_SendExpiryReminders(t *testing.T) {
+	admins := []*domain.User{{Name: "Admin", Email: "admin@turivo.cl"}}
+
+	t.Run("should record reminders only after they were sent", func(t *testing.T) {
+		complianceRepo := new(MockComplianceRepository)
+		userRepo := new(MockUserRepository)
+		emailService := new(MockEmailService)
+		useCase := NewComplianceUseCase(complianceRepo, nil, nil, userRepo, emailService, zap.NewNop())
+
+		delivered := expiringLicense("DRV-001", "ana@turivo.cl", 15)
+		undelivered := expiringLicense("DRV-002", "luis@turivo.cl", 7)
+		reminded := expiringLicense("DRV-003", "eva@turivo.cl", 30)
+
+		complianceRepo.On("ListExpiringDocuments", mock.Anything, mock.Anything).Return([]*domain.ExpiringDocument{delivered, undelivered, reminded}, nil)
+		complianceRepo.On("HasReminder", delivered, 15).Return(false, nil)
+		complianceRepo.On("HasReminder", undelivered, 7).Return(false, nil)
+		complianceRepo.On("HasReminder", reminded, 30).Return(true, nil)
+		emailService.On("SendComplianceReminder", "ana@turivo.cl", mock.Anything, mock.Anything).Return(nil)
+		emailService.On("SendComplianceReminder", "luis@turivo.cl", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+		emailService.On("SendComplianceReminder", "admin@turivo.cl", "Admin", mock.MatchedBy(func(items []domain.ComplianceReminderItem) bool {
+			return len(items) == 2
+		})).Return(nil)
+		userRepo.On("List", mock.Anything).Return(admins, len(admins), nil)
+		complianceRepo.On("RecordReminder", delivered, 15, mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := useCase.SendExpiryReminders(context.Background())
+
+		require.NoError(t, err)
+		complianceRepo.AssertExpectations(t)
+		emailService.AssertExpectations(t)
+		complianceRepo.AssertNotCalled(t, "RecordReminder", undelivered, mock.Anything, mock.Anything)
+		emailService.AssertNotCalled(t, "SendComplianceReminder", "eva@turivo.cl", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should record nothing when no admin was reached", func(t *testing.T) {
+		complianceRepo := new(MockComplianceRepository)
+		userRepo := new(MockUserRepository)
+		emailService := new(MockEmailService)
+		useCase := NewComplianceUseCase(complianceRepo, nil, nil, userRepo, emailService, zap.NewNop())
+
+		doc := expiringLicense("DRV-001", "ana@turivo.cl", 15)
+
+		complianceRepo.On("ListExpiringDocuments", mock.Anything, mock.Anything).Return([]*domain.ExpiringDocument{doc}, nil)
+		complianceRepo.On("HasReminder", doc, 15).Return(false, nil)
+		emailService.On("SendComplianceReminder", "ana@turivo.cl", mock.Anything, mock.Anything).Return(nil)
+		emailService.On("SendComplianceReminder", "admin@turivo.cl", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+		userRepo.On("List", mock.Anything).Return(admins, len(admins), nil)
+
+		err := useCase.SendExpiryReminders(context.Background())
+
+		require.NoError(t, err)
+		complianceRepo.AssertNotCalled(t, "RecordReminder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should skip a reminder recorded by a concurrent run", func(t *testing.T) {
+		complianceRepo := new(MockComplianceRepository)
+		userRepo := new(MockUserRepository)
+		emailService := new(MockEmailService)
+		useCase := NewComplianceUseCase(complianceRepo, nil, nil, userRepo, emailService, zap.NewNop())
+
+		doc := expiringLicense("DRV-001", "", 7)
+
+		complianceRepo.On("ListExpiringDocuments", mock.Anything, mock.Anything).Return([]*domain.ExpiringDocument{doc}, nil)
+		complianceRepo.On("HasReminder", doc, 7).Return(false, nil)
+		emailService.On("SendComplianceReminder", "admin@turivo.cl", mock.Anything, mock.Anything).Return(nil)
+		userRepo.On("List", mock.Anything).Return(admins, len(admins), nil)
+		complianceRepo.On("RecordReminder", doc, 7, mock.AnythingOfType("time.Time")).Return(domain.ErrAlreadyExists)
+
+		err := useCase.SendExpiryReminders(context.Background())
+
+		assert.NoError(t, err)
+		complianceRepo.AssertExpectations(t)
+	})
+}
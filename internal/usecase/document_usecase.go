@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+var documentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type DocumentUseCase struct {
+	documentRepo  domain.DocumentRepository
+	storage       domain.FileStorage
+	driverRepo    domain.DriverRepository
+	vehicleRepo   domain.VehicleRepository
+	publicURL     string
+	maxUploadSize int64
+	signedURLTTL  time.Duration
+	logger        *zap.Logger
+}
+
+func NewDocumentUseCase(
+	documentRepo domain.DocumentRepository,
+	storage domain.FileStorage,
+	driverRepo domain.DriverRepository,
+	vehicleRepo domain.VehicleRepository,
+	publicURL string,
+	maxUploadSize int64,
+	signedURLTTL time.Duration,
+	logger *zap.Logger,
+) *DocumentUseCase {
+	return &DocumentUseCase{
+		documentRepo:  documentRepo,
+		storage:       storage,
+		driverRepo:    driverRepo,
+		vehicleRepo:   vehicleRepo,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+		maxUploadSize: maxUploadSize,
+		signedURLTTL:  signedURLTTL,
+		logger:        logger,
+	}
+}
+
+// UploadDriverDocument stores a driver photo, license or background check
+// scan. The upload stays pending until an admin reviews it.
+func (uc *DocumentUseCase) UploadDriverDocument(driverID string, kind domain.DocumentKind, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	if kind.OwnerType() != domain.DocumentOwnerDriver {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if _, err := uc.driverRepo.GetByID(driverID); err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for upload", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return uc.upload(domain.DocumentOwnerDriver, driverID, kind, fileName, content, uploadedBy)
+}
+
+func (uc *DocumentUseCase) UploadVehiclePhoto(vehicleID string, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	if _, err := uc.vehicleRepo.GetByID(vehicleID); err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get vehicle for upload", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return uc.upload(domain.DocumentOwnerVehicle, vehicleID, domain.DocumentKindVehiclePhoto, fileName, content, uploadedBy)
+}
+
+// UploadMaintenanceAttachment files an invoice or workshop report against a
+// maintenance record. The caller checks that the record exists.
+func (uc *DocumentUseCase) UploadMaintenanceAttachment(recordID uuid.UUID, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	return uc.upload(domain.DocumentOwnerMaintenance, recordID.String(), domain.DocumentKindMaintenance, fileName, content, uploadedBy)
+}
+
+// UploadIncidentPhoto files a photo of the damage or scene of an incident.
+// The caller checks that the incident exists.
+func (uc *DocumentUseCase) UploadIncidentPhoto(incidentID uuid.UUID, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	return uc.upload(domain.DocumentOwnerIncident, incidentID.String(), domain.DocumentKindIncidentPhoto, fileName, content, uploadedBy)
+}
+
+func (uc *DocumentUseCase) GetDocument(id uuid.UUID) (*domain.Document, error) {
+	document, err := uc.documentRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrDocumentNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get document", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return document, nil
+}
+
+func (uc *DocumentUseCase) ListDocuments(req domain.ListDocumentsRequest) ([]*domain.Document, int, error) {
+	documents, total, err := uc.documentRepo.List(req)
+	if err != nil {
+		uc.logger.Error("Failed to list documents", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return documents, total, nil
+}
+
+// ReviewDocument approves or rejects a pending upload. Approved files are
+// linked to the driver or vehicle record they belong to.
+func (uc *DocumentUseCase) ReviewDocument(id uuid.UUID, req domain.ReviewDocumentRequest, reviewerID uuid.UUID) (*domain.Document, error) {
+	if _, err := uc.GetDocument(id); err != nil {
+		return nil, err
+	}
+
+	document, err := uc.documentRepo.Review(id, req.Status, req.Note, reviewerID, time.Now())
+	if err != nil {
+		if err == domain.ErrDocumentAlreadyReviewed {
+			return nil, err
+		}
+		uc.logger.Error("Failed to review document", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if document.Status == domain.DocumentStatusApproved {
+		if err := uc.link(document); err != nil {
+			uc.logger.Error("Failed to link approved document",
+				zap.Error(err),
+				zap.String("document_id", id.String()),
+				zap.String("owner_id", document.OwnerID))
+		}
+	}
+
+	uc.logger.Info("Document reviewed",
+		zap.String("document_id", id.String()),
+		zap.String("status", string(document.Status)),
+		zap.String("reviewed_by", reviewerID.String()),
+	)
+	return document, nil
+}
+
+// GetDownloadURL returns a short-lived signed URL for the document's file.
+func (uc *DocumentUseCase) GetDownloadURL(id uuid.UUID) (*domain.SignedURL, error) {
+	document, err := uc.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.signedURL(document)
+}
+
+// GetPublicPhotoURL signs the file of an approved photo. Licenses and
+// background checks are never served without authentication.
+func (uc *DocumentUseCase) GetPublicPhotoURL(id uuid.UUID) (*domain.SignedURL, error) {
+	document, err := uc.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !document.Kind.IsPhoto() || document.Status != domain.DocumentStatusApproved {
+		return nil, domain.ErrDocumentNotFound
+	}
+
+	return uc.signedURL(document)
+}
+
+func (uc *DocumentUseCase) upload(ownerType domain.DocumentOwnerType, ownerID string, kind domain.DocumentKind, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	data, err := io.ReadAll(io.LimitReader(content, uc.maxUploadSize+1))
+	if err != nil {
+		uc.logger.Error("Failed to read upload", zap.Error(err))
+		return nil, domain.ErrInvalidInput
+	}
+	if len(data) == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	if int64(len(data)) > uc.maxUploadSize {
+		return nil, domain.ErrDocumentTooLarge
+	}
+
+	// Trust the bytes, not the client-supplied file name or Content-Type
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !contentTypeAllowed(kind, contentType) {
+		return nil, domain.ErrUnsupportedDocumentType
+	}
+
+	status := domain.DocumentStatusPending
+	if !kind.RequiresReview() {
+		status = domain.DocumentStatusApproved
+	}
+
+	document := &domain.Document{
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		Kind:        kind,
+		StorageKey:  documentStorageKey(ownerType, ownerID, kind, contentType),
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Status:      status,
+		UploadedBy:  &uploadedBy,
+	}
+
+	ctx := context.Background()
+	if err := uc.storage.Put(ctx, document.StorageKey, data, contentType); err != nil {
+		uc.logger.Error("Failed to store upload", zap.Error(err), zap.String("key", document.StorageKey))
+		return nil, domain.ErrInternalError
+	}
+
+	if err := uc.documentRepo.Create(document); err != nil {
+		if deleteErr := uc.storage.Delete(ctx, document.StorageKey); deleteErr != nil {
+			uc.logger.Warn("Failed to clean up orphaned upload", zap.Error(deleteErr), zap.String("key", document.StorageKey))
+		}
+		uc.logger.Error("Failed to create document", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Document uploaded",
+		zap.String("document_id", document.ID.String()),
+		zap.String("owner_id", ownerID),
+		zap.String("kind", string(kind)),
+		zap.Int64("size", document.Size),
+	)
+	return document, nil
+}
+
+// link stores a stable URL for an approved file on the driver or vehicle
+// record. Photos get a public URL; other documents get the authenticated
+// download endpoint, which hands out a fresh signed URL on each call.
+func (uc *DocumentUseCase) link(document *domain.Document) error {
+	switch document.Kind {
+	case domain.DocumentKindDriverPhoto:
+		photoURL := uc.publicPhotoURL(document)
+		_, err := uc.driverRepo.Update(document.OwnerID, domain.UpdateDriverRequest{PhotoURL: &photoURL})
+		return err
+
+	case domain.DocumentKindVehiclePhoto:
+		return uc.vehicleRepo.AddPhoto(document.OwnerID, uc.publicPhotoURL(document))
+
+	case domain.DocumentKindLicense:
+		driver, err := uc.driverRepo.GetByID(document.OwnerID)
+		if err != nil {
+			return err
+		}
+		if driver.License == nil {
+			// The file is linked once the license number and class are recorded
+			uc.logger.Info("Driver has no license record to link", zap.String("driver_id", driver.ID))
+			return nil
+		}
+		license := *driver.License
+		fileURL := uc.downloadURL(document)
+		license.FileURL = &fileURL
+		return uc.driverRepo.CreateOrUpdateLicense(&license)
+
+	case domain.DocumentKindBackgroundCheck:
+		driver, err := uc.driverRepo.GetByID(document.OwnerID)
+		if err != nil {
+			return err
+		}
+		check := domain.DriverBackgroundCheck{
+			DriverID: driver.ID,
+			Status:   domain.BackgroundCheckStatusPending,
+		}
+		if driver.BackgroundCheck != nil {
+			check = *driver.BackgroundCheck
+		}
+		fileURL := uc.downloadURL(document)
+		check.FileURL = &fileURL
+		return uc.driverRepo.CreateOrUpdateBackgroundCheck(&check)
+	}
+
+	return nil
+}
+
+func (uc *DocumentUseCase) signedURL(document *domain.Document) (*domain.SignedURL, error) {
+	expiresAt := time.Now().Add(uc.signedURLTTL)
+	signed, err := uc.storage.SignedURL(document.StorageKey, uc.signedURLTTL)
+	if err != nil {
+		uc.logger.Error("Failed to sign document URL", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return &domain.SignedURL{URL: signed, ExpiresAt: expiresAt}, nil
+}
+
+func (uc *DocumentUseCase) publicPhotoURL(document *domain.Document) string {
+	return fmt.Sprintf("%s/api/v1/public/documents/%s", uc.publicURL, document.ID)
+}
+
+func (uc *DocumentUseCase) downloadURL(document *domain.Document) string {
+	return fmt.Sprintf("%s/api/v1/documents/%s/download", uc.publicURL, document.ID)
+}
+
+func contentTypeAllowed(kind domain.DocumentKind, contentType string) bool {
+	for _, allowed := range kind.AllowedContentTypes() {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+func documentStorageKey(ownerType domain.DocumentOwnerType, ownerID string, kind domain.DocumentKind, contentType string) string {
+	return fmt.Sprintf("%ss/%s/%s/%s%s",
+		strings.ToLower(string(ownerType)),
+		url.PathEscape(ownerID),
+		strings.ToLower(string(kind)),
+		uuid.New().String(),
+		documentExtensions[contentType],
+	)
+}
+
+func sanitizeFileName(fileName string) string {
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "upload"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -191,7 +191,7 @@ func (uc *DriverUseCase) CreateDriver(req domain.CreateDriverRequest) (*domain.D
 func (uc *DriverUseCase) GetDriverByID(id string) (*domain.Driver, error) {
 	driver, err := uc.driverRepo.GetByID(id)
 	if err != nil {
-		if err == domain.ErrNotFound {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
 			return nil, domain.ErrDriverNotFound
 		}
 		uc.logger.Error("Failed to get driver by ID", zap.Error(err), zap.String("driver_id", id))
@@ -280,80 +280,138 @@ func (uc *DriverUseCase) DeleteDriver(id string) error {
 	return nil
 }
 
-func (uc *DriverUseCase) UpdateDriverLicense(driverID string, license domain.DriverLicense) error {
+func (uc *DriverUseCase) UpdateDriverLicense(driverID string, req domain.UpdateDriverLicenseRequest) (*domain.DriverLicense, error) {
 	uc.logger.Info("Updating driver license", zap.String("driver_id", driverID))
 
 	// Check if driver exists
-	_, err := uc.driverRepo.GetByID(driverID)
+	driver, err := uc.getDriver(driverID, "license update")
 	if err != nil {
-		if err == domain.ErrNotFound {
-			return domain.ErrDriverNotFound
-		}
-		uc.logger.Error("Failed to get driver for license update", zap.Error(err))
-		return domain.ErrInternalError
+		return nil, err
+	}
+
+	if req.IssuedAt != nil && req.ExpiresAt != nil && !req.ExpiresAt.After(*req.IssuedAt) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	license := domain.DriverLicense{
+		DriverID:  driverID,
+		Number:    req.Number,
+		Class:     req.Class,
+		IssuedAt:  req.IssuedAt,
+		ExpiresAt: req.ExpiresAt,
+	}
+	// Keep the scan linked by an approved document upload
+	if driver.License != nil {
+		license.FileURL = driver.License.FileURL
 	}
 
-	license.DriverID = driverID
 	if err := uc.driverRepo.CreateOrUpdateLicense(&license); err != nil {
 		uc.logger.Error("Failed to update driver license", zap.Error(err))
-		return domain.ErrInternalError
+		return nil, domain.ErrInternalError
 	}
 
 	uc.logger.Info("Driver license updated successfully", zap.String("driver_id", driverID))
-	return nil
+	return &license, nil
 }
 
-func (uc *DriverUseCase) UpdateDriverBackgroundCheck(driverID string, check domain.DriverBackgroundCheck) error {
+func (uc *DriverUseCase) UpdateDriverBackgroundCheck(driverID string, req domain.UpdateDriverBackgroundCheckRequest) (*domain.DriverBackgroundCheck, error) {
 	uc.logger.Info("Updating driver background check", zap.String("driver_id", driverID))
 
 	// Check if driver exists
-	_, err := uc.driverRepo.GetByID(driverID)
+	driver, err := uc.getDriver(driverID, "background check update")
 	if err != nil {
-		if err == domain.ErrNotFound {
-			return domain.ErrDriverNotFound
-		}
-		uc.logger.Error("Failed to get driver for background check update", zap.Error(err))
-		return domain.ErrInternalError
+		return nil, err
 	}
 
-	check.DriverID = driverID
+	check := domain.DriverBackgroundCheck{
+		DriverID:  driverID,
+		Status:    req.Status,
+		CheckedAt: req.CheckedAt,
+	}
 	if check.Status == domain.BackgroundCheckStatusApproved && check.CheckedAt == nil {
 		now := time.Now()
 		check.CheckedAt = &now
 	}
+	// Keep the certificate linked by an approved document upload
+	if driver.BackgroundCheck != nil {
+		check.FileURL = driver.BackgroundCheck.FileURL
+	}
 
 	if err := uc.driverRepo.CreateOrUpdateBackgroundCheck(&check); err != nil {
 		uc.logger.Error("Failed to update driver background check", zap.Error(err))
-		return domain.ErrInternalError
+		return nil, domain.ErrInternalError
 	}
 
 	uc.logger.Info("Driver background check updated successfully", zap.String("driver_id", driverID))
-	return nil
+	return &check, nil
 }
 
-func (uc *DriverUseCase) UpdateDriverAvailability(driverID string, availability domain.DriverAvailability) error {
+// UpdateDriverAvailability replaces the driver's weekly availability. Time
+// ranges that are malformed or overlap return an error wrapping
+// domain.ErrInvalidTimeRange.
+func (uc *DriverUseCase) UpdateDriverAvailability(driverID string, req domain.UpdateDriverAvailabilityRequest) (*domain.DriverAvailability, error) {
 	uc.logger.Info("Updating driver availability", zap.String("driver_id", driverID))
 
+	if err := domain.ValidateTimeRanges(req.TimeRanges); err != nil {
+		return nil, err
+	}
+
 	// Check if driver exists
-	_, err := uc.driverRepo.GetByID(driverID)
-	if err != nil {
-		if err == domain.ErrNotFound {
-			return domain.ErrDriverNotFound
-		}
-		uc.logger.Error("Failed to get driver for availability update", zap.Error(err))
-		return domain.ErrInternalError
+	if _, err := uc.getDriver(driverID, "availability update"); err != nil {
+		return nil, err
 	}
 
-	availability.DriverID = driverID
-	availability.UpdatedAt = time.Now()
+	availability := domain.DriverAvailability{
+		DriverID:   driverID,
+		Regions:    req.Regions,
+		Days:       req.Days,
+		TimeRanges: req.TimeRanges,
+		UpdatedAt:  time.Now(),
+	}
 
 	if err := uc.driverRepo.CreateOrUpdateAvailability(&availability); err != nil {
 		uc.logger.Error("Failed to update driver availability", zap.Error(err))
-		return domain.ErrInternalError
+		return nil, domain.ErrInternalError
 	}
 
 	uc.logger.Info("Driver availability updated successfully", zap.String("driver_id", driverID))
-	return nil
+	return &availability, nil
+}
+
+// GetAvailabilityCalendar expands the driver's weekly availability into the
+// concrete slots of the week containing weekOf.
+func (uc *DriverUseCase) GetAvailabilityCalendar(driverID string, weekOf time.Time) (*domain.AvailabilityCalendar, error) {
+	driver, err := uc.getDriver(driverID, "availability calendar")
+	if err != nil {
+		return nil, err
+	}
+
+	weekStart := domain.WeekStart(weekOf)
+	calendar := &domain.AvailabilityCalendar{
+		DriverID:  driverID,
+		WeekStart: weekStart.Format("2006-01-02"),
+		WeekEnd:   weekStart.AddDate(0, 0, 6).Format("2006-01-02"),
+		Regions:   []string{},
+		Slots:     domain.ExpandAvailability(driver.Availability, weekStart),
+	}
+	if driver.Availability != nil {
+		calendar.Regions = driver.Availability.Regions
+	}
+
+	return calendar, nil
+}
+
+func (uc *DriverUseCase) getDriver(driverID, purpose string) (*domain.Driver, error) {
+	driver, err := uc.driverRepo.GetByID(driverID)
+	if err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for "+purpose, zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return driver, nil
 }
 
 func (uc *DriverUseCase) GetDriverKPIs(driverID string) (*domain.DriverKPIs, error) {
@@ -408,17 +466,3 @@ func (uc *DriverUseCase) GetDriverVehicle(driverID string) (*domain.Vehicle, err
 	uc.logger.Info("Driver vehicle retrieved", zap.String("driver_id", driverID))
 	return vehicle, nil
 }
-
-// UpdateTripStatus updates the status of a trip
-func (uc *DriverUseCase) UpdateTripStatus(driverID, tripID, status string) error {
-	uc.logger.Info("Updating trip status", zap.String("driver_id", driverID), zap.String("trip_id", tripID), zap.String("status", status))
-
-	err := uc.driverRepo.UpdateTripStatus(driverID, tripID, status)
-	if err != nil {
-		uc.logger.Error("Failed to update trip status", zap.Error(err))
-		return domain.ErrInternalError
-	}
-
-	uc.logger.Info("Trip status updated successfully", zap.String("driver_id", driverID), zap.String("trip_id", tripID))
-	return nil
-}
@@ -0,0 +1,414 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// payoutBatchSize caps how many trip payouts a single scheduler run records.
+const payoutBatchSize = 100
+
+type EarningsUseCase struct {
+	earningsRepo   domain.EarningsRepository
+	pricingUseCase *PricingUseCase
+	driverRepo     domain.DriverRepository
+	period         domain.SettlementPeriod
+	logger         *zap.Logger
+}
+
+func NewEarningsUseCase(
+	earningsRepo domain.EarningsRepository,
+	pricingUseCase *PricingUseCase,
+	driverRepo domain.DriverRepository,
+	period domain.SettlementPeriod,
+	logger *zap.Logger,
+) *EarningsUseCase {
+	return &EarningsUseCase{
+		earningsRepo:   earningsRepo,
+		pricingUseCase: pricingUseCase,
+		driverRepo:     driverRepo,
+		period:         period,
+		logger:         logger,
+	}
+}
+
+// RecordTripPayouts adds the driver payout of every newly completed trip to
+// the ledger at the split locked with the reservation's price. Reservations
+// booked before the split was stored are split with the current commission
+// rate. It is run by the scheduler; each trip is paid at most once.
+func (uc *EarningsUseCase) RecordTripPayouts(ctx context.Context) error {
+	candidates, err := uc.earningsRepo.ListTripPayoutCandidates(payoutBatchSize)
+	if err != nil {
+		return err
+	}
+
+	recorded := 0
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		split, err := uc.lockedSplit(ctx, candidate)
+		if err != nil {
+			return err
+		}
+
+		reservationID := candidate.ReservationID
+		entry := &domain.EarningEntry{
+			DriverID:      candidate.DriverID,
+			ReservationID: &reservationID,
+			Type:          domain.EarningTypeTripPayout,
+			Amount:        split.DriverPayout,
+			Currency:      split.Currency,
+			GrossFare:     &split.FinalFare,
+			Commission:    &split.Commission,
+			OccurredAt:    candidate.CompletedAt,
+		}
+		if err := uc.earningsRepo.CreateEntry(entry); err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+		recorded++
+	}
+
+	if recorded > 0 {
+		uc.logger.Info("Trip payouts recorded", zap.Int("count", recorded))
+	}
+	return nil
+}
+
+// lockedSplit returns the fare split stored with the candidate's price,
+// splitting the fare now when the reservation predates stored splits.
+func (uc *EarningsUseCase) lockedSplit(ctx context.Context, candidate *domain.TripPayoutCandidate) (*domain.PricingResult, error) {
+	if candidate.Commission == nil || candidate.DriverPayout == nil || candidate.Currency == nil {
+		return uc.pricingUseCase.SplitFare(ctx, candidate.Fare)
+	}
+
+	return &domain.PricingResult{
+		Currency:     *candidate.Currency,
+		FinalFare:    candidate.Fare,
+		Commission:   *candidate.Commission,
+		DriverPayout: *candidate.DriverPayout,
+	}, nil
+}
+
+// GenerateDueSettlements drafts statements for the last complete period. It is
+// run by the scheduler; drivers that already have a statement for the period
+// are skipped.
+func (uc *EarningsUseCase) GenerateDueSettlements(ctx context.Context) error {
+	settlements, err := uc.GenerateSettlements(domain.GenerateSettlementsRequest{Period: uc.period})
+	if err != nil {
+		return err
+	}
+
+	if len(settlements) > 0 {
+		uc.logger.Info("Settlements drafted", zap.Int("count", len(settlements)))
+	}
+	return nil
+}
+
+// CreateEntry records a manual adjustment, tip, cancellation compensation or
+// penalty. Penalties are stored as deductions.
+func (uc *EarningsUseCase) CreateEntry(driverID string, req domain.CreateEarningEntryRequest, createdBy uuid.UUID) (*domain.EarningEntry, error) {
+	if _, err := uc.getDriver(driverID); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	switch req.Type {
+	case domain.EarningTypeAdjustment:
+	case domain.EarningTypePenalty:
+		if amount < 0 {
+			return nil, domain.ErrInvalidInput
+		}
+		amount = -amount
+	case domain.EarningTypeTip, domain.EarningTypeCancellationCompensation:
+		if amount < 0 {
+			return nil, domain.ErrInvalidInput
+		}
+	default:
+		// Trip payouts only come from completed reservations
+		return nil, domain.ErrInvalidInput
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency for earning entry", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		occurredAt = *req.OccurredAt
+	}
+
+	description := req.Description
+	entry := &domain.EarningEntry{
+		DriverID:      driverID,
+		ReservationID: req.ReservationID,
+		Type:          req.Type,
+		Amount:        math.Round(amount*100) / 100,
+		Currency:      currency,
+		Description:   &description,
+		OccurredAt:    occurredAt,
+		CreatedBy:     &createdBy,
+	}
+	if err := uc.earningsRepo.CreateEntry(entry); err != nil {
+		uc.logger.Error("Failed to create earning entry", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Earning entry recorded",
+		zap.String("entry_id", entry.ID.String()),
+		zap.String("driver_id", driverID),
+		zap.String("type", string(entry.Type)),
+		zap.Float64("amount", entry.Amount),
+		zap.String("created_by", createdBy.String()),
+	)
+	return entry, nil
+}
+
+func (uc *EarningsUseCase) ListEntries(req domain.ListEarningsRequest) ([]*domain.EarningEntry, int, error) {
+	entries, total, err := uc.earningsRepo.ListEntries(req)
+	if err != nil {
+		uc.logger.Error("Failed to list earning entries", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return entries, total, nil
+}
+
+func (uc *EarningsUseCase) GetBalance(driverID string) (*domain.DriverBalance, error) {
+	if _, err := uc.getDriver(driverID); err != nil {
+		return nil, err
+	}
+
+	balance, err := uc.earningsRepo.GetBalance(driverID)
+	if err != nil {
+		uc.logger.Error("Failed to get driver balance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency for driver balance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	balance.Currency = currency
+
+	return balance, nil
+}
+
+// GenerateSettlements drafts one statement per driver with unsettled entries
+// up to the end of the requested period. Entries from earlier periods that
+// were recorded late are carried into this statement.
+func (uc *EarningsUseCase) GenerateSettlements(req domain.GenerateSettlementsRequest) ([]*domain.Settlement, error) {
+	now := time.Now()
+	start, end := req.Period.Previous(now)
+	if req.PeriodOf != nil {
+		start, end = req.Period.Bounds(*req.PeriodOf)
+	}
+	if end.After(now) {
+		// The period is still running
+		return nil, domain.ErrInvalidInput
+	}
+
+	var driverIDs []string
+	if req.DriverID != nil {
+		if _, err := uc.getDriver(*req.DriverID); err != nil {
+			return nil, err
+		}
+		driverIDs = []string{*req.DriverID}
+	} else {
+		ids, err := uc.earningsRepo.ListDriversWithUnsettledEntries(end)
+		if err != nil {
+			uc.logger.Error("Failed to list drivers to settle", zap.Error(err))
+			return nil, domain.ErrInternalError
+		}
+		driverIDs = ids
+	}
+
+	settlements := []*domain.Settlement{}
+	for _, driverID := range driverIDs {
+		settlement, err := uc.settle(driverID, req.Period, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if settlement != nil {
+			settlements = append(settlements, settlement)
+		}
+	}
+
+	return settlements, nil
+}
+
+// GetSettlement returns a settlement with its entries.
+func (uc *EarningsUseCase) GetSettlement(id uuid.UUID) (*domain.Settlement, error) {
+	settlement, err := uc.earningsRepo.GetSettlement(id)
+	if err != nil {
+		if err == domain.ErrSettlementNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get settlement", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	entries, err := uc.earningsRepo.ListSettlementEntries(id)
+	if err != nil {
+		uc.logger.Error("Failed to get settlement entries", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	settlement.Entries = entries
+
+	return settlement, nil
+}
+
+// GetDriverSettlement returns a settlement only if it belongs to the driver.
+func (uc *EarningsUseCase) GetDriverSettlement(driverID string, id uuid.UUID) (*domain.Settlement, error) {
+	settlement, err := uc.GetSettlement(id)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.DriverID != driverID {
+		return nil, domain.ErrSettlementNotFound
+	}
+
+	return settlement, nil
+}
+
+func (uc *EarningsUseCase) ListSettlements(req domain.ListSettlementsRequest) ([]*domain.Settlement, int, error) {
+	settlements, total, err := uc.earningsRepo.ListSettlements(req)
+	if err != nil {
+		uc.logger.Error("Failed to list settlements", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return settlements, total, nil
+}
+
+func (uc *EarningsUseCase) ApproveSettlement(id uuid.UUID, approverID uuid.UUID) (*domain.Settlement, error) {
+	if _, err := uc.GetSettlement(id); err != nil {
+		return nil, err
+	}
+
+	settlement, err := uc.earningsRepo.ApproveSettlement(id, approverID, time.Now())
+	if err != nil {
+		if err == domain.ErrInvalidStatusTransition {
+			return nil, err
+		}
+		uc.logger.Error("Failed to approve settlement", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Settlement approved",
+		zap.String("settlement_id", id.String()),
+		zap.String("approved_by", approverID.String()),
+	)
+	return settlement, nil
+}
+
+func (uc *EarningsUseCase) MarkSettlementPaid(id uuid.UUID, req domain.MarkSettlementPaidRequest, payerID uuid.UUID) (*domain.Settlement, error) {
+	if _, err := uc.GetSettlement(id); err != nil {
+		return nil, err
+	}
+
+	settlement, err := uc.earningsRepo.MarkSettlementPaid(id, payerID, time.Now(), req.Reference)
+	if err != nil {
+		if err == domain.ErrInvalidStatusTransition {
+			return nil, err
+		}
+		uc.logger.Error("Failed to mark settlement as paid", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Settlement paid",
+		zap.String("settlement_id", id.String()),
+		zap.Float64("total", settlement.Total),
+		zap.String("paid_by", payerID.String()),
+	)
+	return settlement, nil
+}
+
+// DiscardSettlement deletes a draft and releases its entries so they can be
+// corrected and settled again.
+func (uc *EarningsUseCase) DiscardSettlement(id uuid.UUID) error {
+	if _, err := uc.GetSettlement(id); err != nil {
+		return err
+	}
+
+	if err := uc.earningsRepo.DeleteDraftSettlement(id); err != nil {
+		if err == domain.ErrInvalidStatusTransition {
+			return err
+		}
+		uc.logger.Error("Failed to discard settlement", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	uc.logger.Info("Settlement discarded", zap.String("settlement_id", id.String()))
+	return nil
+}
+
+// settle drafts the statement of one driver. It returns nil when there is
+// nothing to settle or the driver already has a statement for the period.
+func (uc *EarningsUseCase) settle(driverID string, period domain.SettlementPeriod, start, end time.Time) (*domain.Settlement, error) {
+	entries, err := uc.earningsRepo.ListUnsettledEntries(driverID, end)
+	if err != nil {
+		uc.logger.Error("Failed to list unsettled entries", zap.Error(err), zap.String("driver_id", driverID))
+		return nil, domain.ErrInternalError
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	entryIDs := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+
+	settlement := &domain.Settlement{
+		DriverID:         driverID,
+		Period:           period,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+		Status:           domain.SettlementStatusDraft,
+		Currency:         entries[0].Currency,
+		SettlementTotals: domain.SummarizeEarnings(entries),
+	}
+	if err := uc.earningsRepo.CreateSettlement(settlement, entryIDs); err != nil {
+		if err == domain.ErrAlreadyExists {
+			uc.logger.Info("Settlement already exists for period",
+				zap.String("driver_id", driverID),
+				zap.Time("period_start", start))
+			return nil, nil
+		}
+		uc.logger.Error("Failed to create settlement", zap.Error(err), zap.String("driver_id", driverID))
+		return nil, domain.ErrInternalError
+	}
+
+	for _, entry := range entries {
+		entry.SettlementID = &settlement.ID
+	}
+	settlement.Entries = entries
+	return settlement, nil
+}
+
+func (uc *EarningsUseCase) getDriver(driverID string) (*domain.Driver, error) {
+	driver, err := uc.driverRepo.GetByID(driverID)
+	if err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for earnings", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return driver, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockEarningsRepository implements the payout candidates and ledger writes
+// the use cases under test touch; any other call panics.
+type MockEarningsRepository struct {
+	domain.EarningsRepository
+	mock.Mock
+}
+
+func (m *MockEarningsRepository) ListTripPayoutCandidates(limit int) ([]*domain.TripPayoutCandidate, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]*domain.TripPayoutCandidate), args.Error(1)
+}
+
+func (m *MockEarningsRepository) CreateEntry(entry *domain.EarningEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func TestEarningsUseCase_RecordTripPayouts(t *testing.T) {
+	newUseCase := func(candidate *domain.TripPayoutCandidate) (*EarningsUseCase, *MockEarningsRepository) {
+		earningsRepo := new(MockEarningsRepository)
+		pricingRepo := new(MockPricingRepository)
+		pricingRepo.On("GetSettings", mock.Anything).Return(&domain.PricingSettings{
+			CommissionRate:  0.30,
+			DefaultCurrency: "CLP",
+		}, nil)
+
+		earningsRepo.On("ListTripPayoutCandidates", payoutBatchSize).Return([]*domain.TripPayoutCandidate{candidate}, nil)
+
+		useCase := NewEarningsUseCase(earningsRepo, NewPricingUseCase(pricingRepo, zap.NewNop()), nil, domain.SettlementPeriodWeekly, zap.NewNop())
+		return useCase, earningsRepo
+	}
+
+	t.Run("should pay the split locked with the price", func(t *testing.T) {
+		commission, driverPayout, currency := 2000.0, 8000.0, "CLP"
+		useCase, earningsRepo := newUseCase(&domain.TripPayoutCandidate{
+			ReservationID: "RES-001",
+			DriverID:      "CON-001",
+			Fare:          10000,
+			Commission:    &commission,
+			DriverPayout:  &driverPayout,
+			Currency:      &currency,
+			CompletedAt:   time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC),
+		})
+
+		earningsRepo.On("CreateEntry", mock.MatchedBy(func(entry *domain.EarningEntry) bool {
+			return entry.Amount == 8000 && *entry.Commission == 2000 && *entry.GrossFare == 10000 && entry.Currency == "CLP"
+		})).Return(nil)
+
+		err := useCase.RecordTripPayouts(context.Background())
+
+		require.NoError(t, err)
+		earningsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should split trips booked without a locked split at the current rate", func(t *testing.T) {
+		useCase, earningsRepo := newUseCase(&domain.TripPayoutCandidate{
+			ReservationID: "RES-002",
+			DriverID:      "CON-001",
+			Fare:          10000,
+			CompletedAt:   time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC),
+		})
+
+		earningsRepo.On("CreateEntry", mock.MatchedBy(func(entry *domain.EarningEntry) bool {
+			return entry.Amount == 7000 && *entry.Commission == 3000 && entry.Currency == "CLP"
+		})).Return(nil)
+
+		err := useCase.RecordTripPayouts(context.Background())
+
+		require.NoError(t, err)
+		earningsRepo.AssertExpectations(t)
+	})
+}
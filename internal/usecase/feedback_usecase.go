@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// promptBatchSize caps how many rating prompts a single scheduler run sends.
+const promptBatchSize = 50
+
+type FeedbackUseCase struct {
+	feedbackRepo    domain.FeedbackRepository
+	tokenService    domain.FeedbackTokenService
+	reservationRepo domain.ReservationRepository
+	userRepo        domain.UserRepository
+	driverRepo      domain.DriverRepository
+	emailService    domain.EmailService
+	window          time.Duration
+	logger          *zap.Logger
+}
+
+func NewFeedbackUseCase(
+	feedbackRepo domain.FeedbackRepository,
+	tokenService domain.FeedbackTokenService,
+	reservationRepo domain.ReservationRepository,
+	userRepo domain.UserRepository,
+	driverRepo domain.DriverRepository,
+	emailService domain.EmailService,
+	window time.Duration,
+	logger *zap.Logger,
+) *FeedbackUseCase {
+	return &FeedbackUseCase{
+		feedbackRepo:    feedbackRepo,
+		tokenService:    tokenService,
+		reservationRepo: reservationRepo,
+		userRepo:        userRepo,
+		driverRepo:      driverRepo,
+		emailService:    emailService,
+		window:          window,
+		logger:          logger,
+	}
+}
+
+// SendFeedbackPrompts opens a rating prompt for every newly completed trip and
+// emails the owner a signed rating link. It is run by the scheduler; each trip
+// is prompted at most once.
+func (uc *FeedbackUseCase) SendFeedbackPrompts(ctx context.Context) error {
+	now := time.Now()
+
+	candidates, err := uc.feedbackRepo.ListPromptCandidates(now.Add(-uc.window), promptBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		request.ExpiresAt = now.Add(uc.window)
+		if err := uc.feedbackRepo.CreateRequest(request); err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+
+		if err := uc.sendPromptEmail(request); err != nil {
+			// The prompt stays available through the API even if the email fails.
+			uc.logger.Warn("Failed to send feedback email", zap.Error(err), zap.String("reservation_id", request.ReservationID))
+			continue
+		}
+
+		if err := uc.feedbackRepo.MarkRequestSent(request.ReservationID, time.Now()); err != nil {
+			uc.logger.Warn("Failed to mark feedback request as sent", zap.Error(err), zap.String("reservation_id", request.ReservationID))
+		}
+	}
+
+	if len(candidates) > 0 {
+		uc.logger.Info("Feedback prompts processed", zap.Int("count", len(candidates)))
+	}
+	return nil
+}
+
+// GetPendingFeedback lists the open rating prompts of a user.
+func (uc *FeedbackUseCase) GetPendingFeedback(userID uuid.UUID) ([]*domain.FeedbackPrompt, error) {
+	requests, err := uc.feedbackRepo.ListPendingRequests(userID, time.Now())
+	if err != nil {
+		uc.logger.Error("Failed to list pending feedback", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	prompts := []*domain.FeedbackPrompt{}
+	for _, request := range requests {
+		reservation, err := uc.reservationRepo.GetByID(request.ReservationID)
+		if err != nil {
+			uc.logger.Warn("Failed to get reservation for feedback prompt", zap.Error(err), zap.String("reservation_id", request.ReservationID))
+			continue
+		}
+		prompts = append(prompts, buildFeedbackPrompt(reservation, request, nil))
+	}
+
+	return prompts, nil
+}
+
+// SubmitFeedback records the reservation owner's rating.
+func (uc *FeedbackUseCase) SubmitFeedback(reservationID string, userID uuid.UUID, req domain.SubmitFeedbackRequest) (*domain.DriverFeedback, error) {
+	request, err := uc.getOpenRequest(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	return uc.submit(request, req)
+}
+
+// GetFeedbackPrompt resolves an emailed rating link. Invalid or expired tokens
+// are reported as unauthorized.
+func (uc *FeedbackUseCase) GetFeedbackPrompt(token string) (*domain.FeedbackPrompt, error) {
+	reservationID, err := uc.tokenService.Verify(token)
+	if err != nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	request, err := uc.feedbackRepo.GetRequest(reservationID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrUnauthorized
+		}
+		uc.logger.Error("Failed to get feedback request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrUnauthorized
+		}
+		uc.logger.Error("Failed to get reservation for feedback prompt", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	var feedback *domain.DriverFeedback
+	if existing, err := uc.feedbackRepo.GetByReservation(reservationID); err == nil {
+		feedback = existing
+	} else if err != domain.ErrFeedbackNotFound {
+		uc.logger.Error("Failed to get feedback", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return buildFeedbackPrompt(reservation, request, feedback), nil
+}
+
+// SubmitFeedbackWithToken records a rating through an emailed rating link.
+func (uc *FeedbackUseCase) SubmitFeedbackWithToken(token string, req domain.SubmitFeedbackRequest) (*domain.DriverFeedback, error) {
+	reservationID, err := uc.tokenService.Verify(token)
+	if err != nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	request, err := uc.getOpenRequest(reservationID)
+	if err != nil {
+		if err == domain.ErrFeedbackNotAvailable {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	return uc.submit(request, req)
+}
+
+// ListDriverFeedback returns the published ratings shown on a driver profile.
+// Passenger identities are not exposed.
+func (uc *FeedbackUseCase) ListDriverFeedback(driverID string, page, pageSize int) ([]*domain.DriverFeedback, int, error) {
+	if _, err := uc.driverRepo.GetByID(driverID); err != nil {
+		if err == domain.ErrDriverNotFound {
+			return nil, 0, err
+		}
+		uc.logger.Error("Failed to get driver for feedback", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	status := domain.FeedbackStatusPublished
+	feedback, total, err := uc.feedbackRepo.List(domain.ListFeedbackRequest{
+		DriverID: &driverID,
+		Status:   &status,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		uc.logger.Error("Failed to list driver feedback", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	for _, item := range feedback {
+		item.UserID = nil
+		item.ModerationNote = nil
+		item.ModeratedBy = nil
+	}
+
+	return feedback, total, nil
+}
+
+// ListFeedback lists every rating, including hidden ones, for moderation.
+func (uc *FeedbackUseCase) ListFeedback(req domain.ListFeedbackRequest) ([]*domain.DriverFeedback, int, error) {
+	feedback, total, err := uc.feedbackRepo.List(req)
+	if err != nil {
+		uc.logger.Error("Failed to list feedback", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return feedback, total, nil
+}
+
+// ModerateFeedback publishes or hides a rating. Hidden ratings no longer count
+// towards the driver's average.
+func (uc *FeedbackUseCase) ModerateFeedback(id uuid.UUID, req domain.ModerateFeedbackRequest, moderatorID uuid.UUID) (*domain.DriverFeedback, error) {
+	feedback, err := uc.feedbackRepo.Moderate(id, req.Status, req.Note, moderatorID, time.Now())
+	if err != nil {
+		if err == domain.ErrFeedbackNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to moderate feedback", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Feedback moderated",
+		zap.String("feedback_id", id.String()),
+		zap.String("status", string(req.Status)),
+		zap.String("moderated_by", moderatorID.String()),
+	)
+	return feedback, nil
+}
+
+func (uc *FeedbackUseCase) getOpenRequest(reservationID string) (*domain.FeedbackRequest, error) {
+	request, err := uc.feedbackRepo.GetRequest(reservationID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrFeedbackNotAvailable
+		}
+		uc.logger.Error("Failed to get feedback request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if !request.IsOpen(time.Now()) {
+		return nil, domain.ErrFeedbackNotAvailable
+	}
+
+	return request, nil
+}
+
+func (uc *FeedbackUseCase) submit(request *domain.FeedbackRequest, req domain.SubmitFeedbackRequest) (*domain.DriverFeedback, error) {
+	userID := request.UserID
+	feedback := &domain.DriverFeedback{
+		DriverID:      request.DriverID,
+		ReservationID: request.ReservationID,
+		UserID:        &userID,
+		Rating:        float64(req.Rating),
+		Tags:          req.Tags,
+		Comment:       req.Comment,
+		Status:        domain.FeedbackStatusPublished,
+	}
+
+	if err := uc.feedbackRepo.Create(feedback); err != nil {
+		if err == domain.ErrFeedbackAlreadyExists {
+			return nil, err
+		}
+		uc.logger.Error("Failed to create feedback", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	now := time.Now()
+	event := domain.TimelineEvent{
+		ReservationID: request.ReservationID,
+		Title:         "Viaje calificado",
+		Description:   fmt.Sprintf("El pasajero calificó el viaje con %d de 5 estrellas", req.Rating),
+		At:            now,
+		Variant:       "info",
+		CreatedAt:     now,
+	}
+	if err := uc.reservationRepo.AddTimelineEvent(request.ReservationID, event); err != nil {
+		uc.logger.Warn("Failed to add feedback timeline event", zap.Error(err), zap.String("reservation_id", request.ReservationID))
+	}
+
+	uc.logger.Info("Feedback submitted",
+		zap.String("reservation_id", request.ReservationID),
+		zap.String("driver_id", request.DriverID),
+		zap.Int("rating", req.Rating),
+	)
+	return feedback, nil
+}
+
+func (uc *FeedbackUseCase) sendPromptEmail(request *domain.FeedbackRequest) error {
+	user, err := uc.userRepo.GetByID(request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	reservation, err := uc.reservationRepo.GetByID(request.ReservationID)
+	if err != nil {
+		return fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	token, err := uc.tokenService.Sign(request)
+	if err != nil {
+		return fmt.Errorf("failed to sign feedback token: %w", err)
+	}
+
+	return uc.emailService.SendFeedbackRequest(user.Email, reservation, user, token)
+}
+
+func buildFeedbackPrompt(reservation *domain.Reservation, request *domain.FeedbackRequest, feedback *domain.DriverFeedback) *domain.FeedbackPrompt {
+	prompt := &domain.FeedbackPrompt{
+		ReservationID: reservation.ID,
+		ScheduledAt:   reservation.DateTime,
+		Pickup:        reservation.Pickup,
+		Destination:   reservation.Destination,
+		ExpiresAt:     request.ExpiresAt,
+		Submitted:     feedback != nil,
+		Feedback:      feedback,
+	}
+	if reservation.AssignedDriver != nil {
+		firstName := reservation.AssignedDriver.FirstName
+		prompt.DriverFirstName = &firstName
+	}
+	return prompt
+}
@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type IncidentUseCase struct {
+	incidentRepo       domain.IncidentRepository
+	vehicleRepo        domain.VehicleRepository
+	driverRepo         domain.DriverRepository
+	reservationRepo    domain.ReservationRepository
+	documentUseCase    *DocumentUseCase
+	pricingUseCase     *PricingUseCase
+	reservationUseCase *ReservationUseCase
+	utilizationUseCase *UtilizationUseCase
+	logger             *zap.Logger
+}
+
+func NewIncidentUseCase(
+	incidentRepo domain.IncidentRepository,
+	vehicleRepo domain.VehicleRepository,
+	driverRepo domain.DriverRepository,
+	reservationRepo domain.ReservationRepository,
+	documentUseCase *DocumentUseCase,
+	pricingUseCase *PricingUseCase,
+	reservationUseCase *ReservationUseCase,
+	utilizationUseCase *UtilizationUseCase,
+	logger *zap.Logger,
+) *IncidentUseCase {
+	return &IncidentUseCase{
+		incidentRepo:       incidentRepo,
+		vehicleRepo:        vehicleRepo,
+		driverRepo:         driverRepo,
+		reservationRepo:    reservationRepo,
+		documentUseCase:    documentUseCase,
+		pricingUseCase:     pricingUseCase,
+		reservationUseCase: reservationUseCase,
+		utilizationUseCase: utilizationUseCase,
+		logger:             logger,
+	}
+}
+
+// CreateIncident records an incident against a vehicle. Critical incidents
+// take the vehicle out of service straight away.
+func (uc *IncidentUseCase) CreateIncident(req domain.CreateIncidentRequest, reportedBy uuid.UUID) (*domain.Incident, error) {
+	if _, err := uc.getVehicle(req.VehicleID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	incident := &domain.Incident{
+		VehicleID:     req.VehicleID,
+		DriverID:      req.DriverID,
+		ReservationID: req.ReservationID,
+		Type:          req.Type,
+		Severity:      req.Severity,
+		Status:        domain.IncidentStatusOpen,
+		Description:   req.Description,
+		Location:      req.Location,
+		OccurredAt:    now,
+		Cost:          req.Cost,
+		ReportedBy:    &reportedBy,
+	}
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(now) {
+			return nil, domain.ErrInvalidInput
+		}
+		incident.OccurredAt = *req.OccurredAt
+	}
+
+	if incident.DriverID != nil {
+		if _, err := uc.driverRepo.GetByID(*incident.DriverID); err != nil {
+			if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+				return nil, domain.ErrDriverNotFound
+			}
+			uc.logger.Error("Failed to get driver for incident", zap.Error(err))
+			return nil, domain.ErrInternalError
+		}
+	}
+	if incident.ReservationID != nil {
+		reservation, err := uc.getReservation(*incident.ReservationID)
+		if err != nil {
+			return nil, err
+		}
+		if incident.DriverID == nil {
+			incident.DriverID = reservation.AssignedDriverID
+		}
+	}
+	if incident.DriverID == nil {
+		assignment, err := uc.vehicleRepo.GetAssignmentAt(incident.VehicleID, incident.OccurredAt)
+		switch err {
+		case nil:
+			incident.DriverID = &assignment.DriverID
+		case domain.ErrVehicleAssignmentNotFound:
+		default:
+			uc.logger.Error("Failed to get vehicle driver for incident", zap.Error(err))
+			return nil, domain.ErrInternalError
+		}
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get default currency for incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	incident.Currency = currency
+
+	if err := uc.incidentRepo.Create(incident); err != nil {
+		uc.logger.Error("Failed to create incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Incident created",
+		zap.String("incident_id", incident.ID.String()),
+		zap.String("vehicle_id", incident.VehicleID),
+		zap.String("severity", string(incident.Severity)),
+	)
+
+	if incident.IsCritical() {
+		incident.Reassignments = uc.takeOutOfService(incident)
+	}
+	return incident, nil
+}
+
+// ReportIncident records an incident filed by a driver against their current
+// vehicle. A reservation, if given, must be one of theirs.
+func (uc *IncidentUseCase) ReportIncident(driverID string, req domain.ReportIncidentRequest, reportedBy uuid.UUID) (*domain.Incident, error) {
+	vehicle, err := uc.vehicleRepo.GetByDriverID(driverID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrDriverHasNoVehicle
+		}
+		uc.logger.Error("Failed to get driver vehicle for incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if req.ReservationID != nil {
+		reservation, err := uc.getReservation(*req.ReservationID)
+		if err != nil {
+			return nil, err
+		}
+		if reservation.AssignedDriverID == nil || *reservation.AssignedDriverID != driverID {
+			return nil, domain.ErrReservationNotFound
+		}
+	}
+
+	return uc.CreateIncident(domain.CreateIncidentRequest{
+		VehicleID:     vehicle.ID,
+		DriverID:      &driverID,
+		ReservationID: req.ReservationID,
+		Type:          req.Type,
+		Severity:      req.Severity,
+		Description:   req.Description,
+		Location:      req.Location,
+		OccurredAt:    req.OccurredAt,
+	}, reportedBy)
+}
+
+// GetIncident returns the incident together with its photos.
+func (uc *IncidentUseCase) GetIncident(id uuid.UUID) (*domain.Incident, error) {
+	incident, err := uc.getIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerType := domain.DocumentOwnerIncident
+	ownerID := id.String()
+	photos, _, err := uc.documentUseCase.ListDocuments(domain.ListDocumentsRequest{
+		OwnerType: &ownerType,
+		OwnerID:   &ownerID,
+		Page:      1,
+		PageSize:  100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	incident.Photos = photos
+
+	return incident, nil
+}
+
+// GetDriverIncident returns an incident involving the driver. Other drivers'
+// incidents are reported as not found.
+func (uc *IncidentUseCase) GetDriverIncident(id uuid.UUID, driverID string) (*domain.Incident, error) {
+	incident, err := uc.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.DriverID == nil || *incident.DriverID != driverID {
+		return nil, domain.ErrIncidentNotFound
+	}
+
+	return incident, nil
+}
+
+// UpdateIncident moves an incident through review, records its cost and
+// resolution, or changes its severity. Escalating an unresolved incident to
+// CRITICAL takes the vehicle out of service.
+func (uc *IncidentUseCase) UpdateIncident(id uuid.UUID, req domain.UpdateIncidentRequest) (*domain.Incident, error) {
+	incident, err := uc.getIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	wasCritical := incident.IsCritical()
+	if req.Status != nil && *req.Status != incident.Status {
+		if !incident.CanTransitionTo(*req.Status) {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		incident.Status = *req.Status
+		incident.ResolvedAt = nil
+		if incident.Status == domain.IncidentStatusResolved {
+			now := time.Now()
+			incident.ResolvedAt = &now
+		}
+	}
+
+	if req.Severity != nil {
+		incident.Severity = *req.Severity
+	}
+	if req.Description != nil {
+		incident.Description = *req.Description
+	}
+	if req.Location != nil {
+		incident.Location = req.Location
+	}
+	if req.Cost != nil {
+		incident.Cost = req.Cost
+	}
+	if req.ResolutionNotes != nil {
+		incident.ResolutionNotes = req.ResolutionNotes
+	}
+
+	if err := uc.incidentRepo.Update(incident); err != nil {
+		if err == domain.ErrIncidentNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to update incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Incident updated",
+		zap.String("incident_id", id.String()),
+		zap.String("status", string(incident.Status)),
+		zap.String("severity", string(incident.Severity)),
+	)
+
+	if incident.IsCritical() && !wasCritical && incident.Status != domain.IncidentStatusResolved {
+		incident.Reassignments = uc.takeOutOfService(incident)
+	}
+	return incident, nil
+}
+
+func (uc *IncidentUseCase) ListIncidents(req domain.ListIncidentsRequest) ([]*domain.Incident, int, error) {
+	incidents, total, err := uc.incidentRepo.List(req)
+	if err != nil {
+		uc.logger.Error("Failed to list incidents", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return incidents, total, nil
+}
+
+// AddPhoto files a photo of the damage or the scene against an incident.
+func (uc *IncidentUseCase) AddPhoto(id uuid.UUID, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	if _, err := uc.getIncident(id); err != nil {
+		return nil, err
+	}
+
+	return uc.documentUseCase.UploadIncidentPhoto(id, fileName, content, uploadedBy)
+}
+
+// AddDriverPhoto lets a driver add photos to one of their own incidents
+// until it is resolved.
+func (uc *IncidentUseCase) AddDriverPhoto(id uuid.UUID, driverID string, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	incident, err := uc.getIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.DriverID == nil || *incident.DriverID != driverID {
+		return nil, domain.ErrIncidentNotFound
+	}
+	if incident.Status == domain.IncidentStatusResolved {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	return uc.documentUseCase.UploadIncidentPhoto(id, fileName, content, uploadedBy)
+}
+
+// takeOutOfService sets the vehicle of a critical incident to MAINTENANCE
+// and moves the upcoming reservations of its current driver to free vehicles.
+// Reservations no vehicle can take are returned to dispatch. Failures are
+// logged; the incident itself is already saved.
+func (uc *IncidentUseCase) takeOutOfService(incident *domain.Incident) []domain.IncidentReassignment {
+	vehicle, err := uc.vehicleRepo.GetByID(incident.VehicleID)
+	if err != nil {
+		uc.logger.Error("Failed to get vehicle of critical incident", zap.Error(err), zap.String("vehicle_id", incident.VehicleID))
+		return nil
+	}
+
+	if vehicle.Status != domain.VehicleStatusMaintenance && vehicle.Status != domain.VehicleStatusInactive {
+		status := domain.VehicleStatusMaintenance
+		if _, err := uc.vehicleRepo.Update(vehicle.ID, domain.UpdateVehicleRequest{Status: &status}); err != nil {
+			uc.logger.Error("Failed to set vehicle in maintenance", zap.Error(err), zap.String("vehicle_id", vehicle.ID))
+			return nil
+		}
+	}
+
+	if vehicle.DriverID == nil {
+		return nil
+	}
+	driverID := *vehicle.DriverID
+
+	reservationIDs, err := uc.reservationRepo.ListUpcomingIDsByDriver(driverID, time.Now())
+	if err != nil {
+		uc.logger.Error("Failed to list reservations affected by incident", zap.Error(err), zap.String("driver_id", driverID))
+		return nil
+	}
+
+	reassignments := make([]domain.IncidentReassignment, 0, len(reservationIDs))
+	for _, reservationID := range reservationIDs {
+		reassignments = append(reassignments, uc.reassignReservation(reservationID, driverID, vehicle.ID))
+	}
+
+	uc.logger.Info("Vehicle taken out of service by critical incident",
+		zap.String("incident_id", incident.ID.String()),
+		zap.String("vehicle_id", vehicle.ID),
+		zap.Int("reservations", len(reassignments)),
+	)
+	return reassignments
+}
+
+// reassignReservation hands a reservation to the driver of the first free
+// vehicle of the booked type that passes the assignment checks.
+func (uc *IncidentUseCase) reassignReservation(reservationID, driverID, vehicleID string) domain.IncidentReassignment {
+	outcome := domain.IncidentReassignment{
+		ReservationID:    reservationID,
+		PreviousDriverID: driverID,
+	}
+
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		uc.logger.Error("Failed to get reservation to reassign", zap.Error(err), zap.String("reservation_id", reservationID))
+		return outcome
+	}
+
+	candidates, err := uc.utilizationUseCase.ListFreeVehicles(domain.FreeVehiclesRequest{
+		VehicleType: reservation.VehicleType,
+		From:        reservation.DateTime,
+		To:          reservation.DateTime.Add(domain.EstimateTripDuration(reservation.DistanceKM)),
+	})
+	if err != nil {
+		uc.logger.Error("Failed to list free vehicles to reassign", zap.Error(err), zap.String("reservation_id", reservationID))
+		candidates = nil
+	}
+
+	for _, candidate := range candidates {
+		if candidate.ID == vehicleID || candidate.DriverID == nil || *candidate.DriverID == driverID {
+			continue
+		}
+		if _, err := uc.reservationUseCase.AssignDriver(reservationID, *candidate.DriverID); err != nil {
+			continue
+		}
+
+		newVehicleID := candidate.ID
+		outcome.NewDriverID = candidate.DriverID
+		outcome.NewVehicleID = &newVehicleID
+		uc.addTimelineEvent(reservationID,
+			"Reserva reasignada",
+			"El vehículo asignado quedó fuera de servicio por un incidente y la reserva pasó a otro conductor",
+			"warning")
+		return outcome
+	}
+
+	if err := uc.reservationRepo.UnassignDriver(reservationID); err != nil {
+		uc.logger.Error("Failed to unassign reservation", zap.Error(err), zap.String("reservation_id", reservationID))
+		return outcome
+	}
+	uc.addTimelineEvent(reservationID,
+		"Reserva sin conductor",
+		"El vehículo asignado quedó fuera de servicio por un incidente y no hay otro vehículo disponible; la reserva vuelve a despacho",
+		"error")
+	uc.logger.Warn("No free vehicle to take reservation",
+		zap.String("reservation_id", reservationID),
+		zap.String("driver_id", driverID),
+	)
+	return outcome
+}
+
+func (uc *IncidentUseCase) addTimelineEvent(reservationID, title, description, variant string) {
+	event := domain.TimelineEvent{
+		ReservationID: reservationID,
+		Title:         title,
+		Description:   description,
+		At:            time.Now(),
+		Variant:       variant,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := uc.reservationRepo.AddTimelineEvent(reservationID, event); err != nil {
+		uc.logger.Warn("Failed to add incident timeline event", zap.Error(err), zap.String("reservation_id", reservationID))
+	}
+}
+
+func (uc *IncidentUseCase) getIncident(id uuid.UUID) (*domain.Incident, error) {
+	incident, err := uc.incidentRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrIncidentNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return incident, nil
+}
+
+func (uc *IncidentUseCase) getVehicle(id string) (*domain.Vehicle, error) {
+	vehicle, err := uc.vehicleRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get vehicle for incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return vehicle, nil
+}
+
+func (uc *IncidentUseCase) getReservation(id string) (*domain.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for incident", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return reservation, nil
+}
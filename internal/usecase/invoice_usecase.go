@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// accrualBatchSize caps how many trips a single scheduler run accrues.
+const accrualBatchSize = 200
+
+// InvoiceUseCase bills postpaid companies: completed trips accrue to the
+// open period of the company's billing account, and ended periods are
+// closed into invoices paid through the payment flow.
+type InvoiceUseCase struct {
+	invoiceRepo      domain.InvoiceRepository
+	companyRepo      domain.CompanyRepository
+	pricingUseCase   *PricingUseCase
+	taxRate          float64
+	paymentTermsDays int
+	logger           *zap.Logger
+}
+
+func NewInvoiceUseCase(
+	invoiceRepo domain.InvoiceRepository,
+	companyRepo domain.CompanyRepository,
+	pricingUseCase *PricingUseCase,
+	taxRate float64,
+	paymentTermsDays int,
+	logger *zap.Logger,
+) *InvoiceUseCase {
+	return &InvoiceUseCase{
+		invoiceRepo:      invoiceRepo,
+		companyRepo:      companyRepo,
+		pricingUseCase:   pricingUseCase,
+		taxRate:          taxRate,
+		paymentTermsDays: paymentTermsDays,
+		logger:           logger,
+	}
+}
+
+func (uc *InvoiceUseCase) GetBillingAccount(companyID uuid.UUID) (*domain.BillingAccount, error) {
+	account, err := uc.invoiceRepo.GetAccountByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrBillingAccountNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get billing account", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return account, nil
+}
+
+// UpsertBillingAccount makes the company postpaid, opening its first period
+// for the current month, or updates the settings of its account. Settings
+// apply to invoices issued from then on. Trips are priced in the default
+// currency, so accounts are only held in it.
+func (uc *InvoiceUseCase) UpsertBillingAccount(companyID uuid.UUID, req domain.UpsertBillingAccountRequest) (*domain.BillingAccount, error) {
+	if _, err := uc.companyRepo.GetByID(companyID); err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrCompanyNotFound
+		}
+		uc.logger.Error("Failed to get company for billing account", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency for billing account", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if req.Currency != currency {
+		return nil, domain.ErrBillingAccountCurrency
+	}
+
+	account, err := uc.invoiceRepo.GetAccountByCompany(companyID)
+	if err != nil && err != domain.ErrBillingAccountNotFound {
+		uc.logger.Error("Failed to get billing account", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	if account == nil {
+		account = &domain.BillingAccount{
+			CompanyID:        companyID,
+			TaxRate:          uc.taxRate,
+			PaymentTermsDays: uc.paymentTermsDays,
+			Active:           true,
+		}
+	}
+	account.Currency = req.Currency
+	if req.TaxRate != nil {
+		account.TaxRate = *req.TaxRate
+	}
+	if req.PaymentTermsDays != nil {
+		account.PaymentTermsDays = *req.PaymentTermsDays
+	}
+	if req.BillingEmail != nil {
+		account.BillingEmail = req.BillingEmail
+	}
+	if req.Active != nil {
+		account.Active = *req.Active
+	}
+
+	if account.ID == uuid.Nil {
+		start, end := domain.MonthPeriod(time.Now())
+		period := &domain.BillingPeriod{PeriodStart: start, PeriodEnd: end}
+		if err := uc.invoiceRepo.CreateAccount(account, period); err != nil {
+			if err == domain.ErrAlreadyExists {
+				return nil, err
+			}
+			uc.logger.Error("Failed to create billing account", zap.Error(err), zap.String("company_id", companyID.String()))
+			return nil, domain.ErrInternalError
+		}
+
+		uc.logger.Info("Billing account opened",
+			zap.String("company_id", companyID.String()),
+			zap.String("account_id", account.ID.String()))
+		return account, nil
+	}
+
+	if err := uc.invoiceRepo.UpdateAccount(account); err != nil {
+		uc.logger.Error("Failed to update billing account", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return account, nil
+}
+
+// GetCurrentPeriod returns the open period of the company's billing account
+// with the trips accrued so far.
+func (uc *InvoiceUseCase) GetCurrentPeriod(companyID uuid.UUID) (*domain.BillingPeriod, error) {
+	account, err := uc.GetBillingAccount(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := uc.invoiceRepo.GetOpenPeriod(account.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get open billing period", zap.Error(err), zap.String("account_id", account.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	accruals, err := uc.invoiceRepo.ListAccruals(period.ID)
+	if err != nil {
+		uc.logger.Error("Failed to list billing accruals", zap.Error(err), zap.String("period_id", period.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	period.Accruals = accruals
+	for _, accrual := range accruals {
+		period.Subtotal += accrual.Amount
+	}
+	period.Subtotal = math.Round(period.Subtotal*100) / 100
+	return period, nil
+}
+
+// AccrueCompletedTrips adds every newly completed trip of a postpaid company
+// to the open period of its account. It is run by the scheduler; each trip
+// is accrued at most once. Trips are priced in the default currency, so
+// accounts left in another one are not accrued to.
+func (uc *InvoiceUseCase) AccrueCompletedTrips(ctx context.Context) error {
+	currency, err := uc.pricingUseCase.DefaultCurrency(ctx)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := uc.invoiceRepo.ListAccrualCandidates(currency, accrualBatchSize)
+	if err != nil {
+		return err
+	}
+
+	accrued := 0
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		accrual := &domain.BillingAccrual{
+			AccountID:     candidate.AccountID,
+			PeriodID:      candidate.PeriodID,
+			ReservationID: candidate.ReservationID,
+			Description:   domain.AccrualDescription(candidate.ReservationID, candidate.Pickup, candidate.Destination, candidate.CompletedAt),
+			Amount:        candidate.Amount,
+			Currency:      candidate.Currency,
+			OccurredAt:    candidate.CompletedAt,
+		}
+		if err := uc.invoiceRepo.CreateAccrual(accrual); err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+		accrued++
+	}
+
+	if accrued > 0 {
+		uc.logger.Info("Trips accrued to billing periods", zap.Int("count", accrued))
+	}
+	return nil
+}
+
+// CloseDuePeriods closes the periods that ended into invoices and marks the
+// invoices past their due date as overdue. It is run by the scheduler.
+// Trips are accrued first so the last ones of the period are invoiced; a
+// period without trips is closed without an invoice.
+func (uc *InvoiceUseCase) CloseDuePeriods(ctx context.Context) error {
+	if err := uc.AccrueCompletedTrips(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	periods, err := uc.invoiceRepo.ListPeriodsToClose(now)
+	if err != nil {
+		return err
+	}
+
+	issued := 0
+	for _, period := range periods {
+		if ctx.Err() != nil {
+			break
+		}
+
+		invoice, err := uc.closePeriod(period, period.PeriodEnd, now)
+		if err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+		if invoice != nil {
+			issued++
+		}
+	}
+	if issued > 0 {
+		uc.logger.Info("Invoices issued", zap.Int("count", issued))
+	}
+
+	overdue, err := uc.invoiceRepo.MarkOverdue(now)
+	if err != nil {
+		return err
+	}
+	if overdue > 0 {
+		uc.logger.Info("Invoices overdue", zap.Int("count", overdue))
+	}
+	return nil
+}
+
+// CloseCurrentPeriod invoices the company's open period now instead of at
+// the end of the month. The rest of the month becomes a new period.
+func (uc *InvoiceUseCase) CloseCurrentPeriod(ctx context.Context, companyID uuid.UUID) (*domain.Invoice, error) {
+	account, err := uc.GetBillingAccount(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.AccrueCompletedTrips(ctx); err != nil {
+		uc.logger.Error("Failed to accrue trips before closing", zap.Error(err), zap.String("account_id", account.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	period, err := uc.invoiceRepo.GetOpenPeriod(account.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get open billing period", zap.Error(err), zap.String("account_id", account.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	now := time.Now()
+	invoice, err := uc.closePeriod(period, now, now)
+	if err != nil {
+		if err == domain.ErrNothingToInvoice || err == domain.ErrAlreadyExists {
+			return nil, err
+		}
+		uc.logger.Error("Failed to close billing period", zap.Error(err), zap.String("period_id", period.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return invoice, nil
+}
+
+// closePeriod closes the period at the given time into an invoice of its
+// accruals and opens the next one. Closing an empty period at its end
+// returns no invoice; closing it early returns ErrNothingToInvoice and
+// leaves it open.
+func (uc *InvoiceUseCase) closePeriod(period *domain.BillingPeriod, at, issuedAt time.Time) (*domain.Invoice, error) {
+	accruals, err := uc.invoiceRepo.ListAccruals(period.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	early := at.Before(period.PeriodEnd)
+	if len(accruals) == 0 && early {
+		return nil, domain.ErrNothingToInvoice
+	}
+
+	account, err := uc.invoiceRepo.GetAccount(period.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := period.Close(at)
+
+	var invoice *domain.Invoice
+	if len(accruals) > 0 {
+		invoice = domain.NewInvoice(account, period, accruals, issuedAt)
+	}
+
+	if err := uc.invoiceRepo.ClosePeriod(period, invoice, next); err != nil {
+		return nil, err
+	}
+
+	if invoice != nil {
+		uc.logger.Info("Invoice issued",
+			zap.String("invoice_id", invoice.ID.String()),
+			zap.String("number", invoice.Number),
+			zap.String("company_id", invoice.CompanyID.String()),
+			zap.Float64("total", invoice.Total))
+	}
+	return invoice, nil
+}
+
+// GetInvoice returns the invoice with its lines. With a company, invoices of
+// other companies are reported as not found.
+func (uc *InvoiceUseCase) GetInvoice(id uuid.UUID, companyID *uuid.UUID) (*domain.Invoice, error) {
+	invoice, err := uc.invoiceRepo.GetInvoice(id)
+	if err != nil {
+		if err == domain.ErrInvoiceNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get invoice", zap.Error(err), zap.String("invoice_id", id.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	if companyID != nil && invoice.CompanyID != *companyID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+
+	return invoice, nil
+}
+
+// GetInvoiceDocument returns the invoice and the company it bills, for the
+// PDF.
+func (uc *InvoiceUseCase) GetInvoiceDocument(id uuid.UUID, companyID *uuid.UUID) (*domain.Invoice, *domain.Company, error) {
+	invoice, err := uc.GetInvoice(id, companyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	company, err := uc.companyRepo.GetByID(invoice.CompanyID)
+	if err != nil {
+		uc.logger.Error("Failed to get invoiced company", zap.Error(err), zap.String("company_id", invoice.CompanyID.String()))
+		return nil, nil, domain.ErrInternalError
+	}
+
+	return invoice, company, nil
+}
+
+func (uc *InvoiceUseCase) ListInvoices(req domain.ListInvoicesRequest) ([]*domain.Invoice, int, error) {
+	invoices, total, err := uc.invoiceRepo.ListInvoices(req)
+	if err != nil {
+		uc.logger.Error("Failed to list invoices", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return invoices, total, nil
+}
+
+// VoidInvoice cancels an unpaid invoice. Its trips are not billed again.
+func (uc *InvoiceUseCase) VoidInvoice(id uuid.UUID, req domain.VoidInvoiceRequest) (*domain.Invoice, error) {
+	if _, err := uc.GetInvoice(id, nil); err != nil {
+		return nil, err
+	}
+
+	invoice, err := uc.invoiceRepo.Void(id, req.Reason, time.Now())
+	if err != nil {
+		if err == domain.ErrInvalidStatusTransition {
+			return nil, err
+		}
+		uc.logger.Error("Failed to void invoice", zap.Error(err), zap.String("invoice_id", id.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Invoice voided", zap.String("invoice_id", id.String()), zap.String("number", invoice.Number))
+	return invoice, nil
+}
+
+// settleInvoice marks the invoice a payment was made for as paid once the
+// payment is captured. A second payment captured for an already paid
+// invoice is only logged, to be refunded from the back office.
+func settleInvoice(invoiceRepo domain.InvoiceRepository, payment *domain.Payment, logger *zap.Logger) {
+	if payment.InvoiceID == nil || !payment.IsCaptured() {
+		return
+	}
+
+	invoice, err := invoiceRepo.MarkPaid(*payment.InvoiceID, payment.ID, time.Now())
+	if err != nil {
+		if err == domain.ErrInvalidStatusTransition {
+			logger.Warn("Payment captured for an invoice that is no longer payable",
+				zap.String("payment_id", payment.ID.String()),
+				zap.String("invoice_id", payment.InvoiceID.String()))
+			return
+		}
+		logger.Error("Failed to mark invoice as paid", zap.Error(err),
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("invoice_id", payment.InvoiceID.String()))
+		return
+	}
+
+	logger.Info("Invoice paid",
+		zap.String("invoice_id", invoice.ID.String()),
+		zap.String("number", invoice.Number),
+		zap.String("payment_id", payment.ID.String()))
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockInvoiceRepository implements the accounts and accruals the use cases
+// under test touch; any other call panics.
+type MockInvoiceRepository struct {
+	domain.InvoiceRepository
+	mock.Mock
+}
+
+func (m *MockInvoiceRepository) GetAccountByCompany(companyID uuid.UUID) (*domain.BillingAccount, error) {
+	args := m.Called(companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BillingAccount), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) ListAccrualCandidates(currency string, limit int) ([]*domain.BillingAccrualCandidate, error) {
+	args := m.Called(currency, limit)
+	return args.Get(0).([]*domain.BillingAccrualCandidate), args.Error(1)
+}
+
+func (m *MockInvoiceRepository) CreateAccrual(accrual *domain.BillingAccrual) error {
+	args := m.Called(accrual)
+	return args.Error(0)
+}
+
+func TestInvoiceUseCase_UpsertBillingAccount(t *testing.T) {
+	t.Run("should not open an account in another currency", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		companyRepo := new(MockCompanyRepository)
+		useCase := NewInvoiceUseCase(invoiceRepo, companyRepo, newPricingUseCaseForTest("CLP"), 0.19, 30, zap.NewNop())
+
+		companyID := uuid.New()
+		companyRepo.On("GetByID", companyID).Return(&domain.Company{ID: companyID}, nil)
+
+		_, err := useCase.UpsertBillingAccount(companyID, domain.UpsertBillingAccountRequest{Currency: "USD"})
+
+		assert.Equal(t, domain.ErrBillingAccountCurrency, err)
+		invoiceRepo.AssertNotCalled(t, "GetAccountByCompany", mock.Anything)
+	})
+}
+
+func TestInvoiceUseCase_AccrueCompletedTrips(t *testing.T) {
+	t.Run("should accrue trips of accounts in the pricing currency", func(t *testing.T) {
+		invoiceRepo := new(MockInvoiceRepository)
+		useCase := NewInvoiceUseCase(invoiceRepo, nil, newPricingUseCaseForTest("CLP"), 0.19, 30, zap.NewNop())
+
+		candidate := &domain.BillingAccrualCandidate{
+			AccountID:     uuid.New(),
+			PeriodID:      uuid.New(),
+			ReservationID: "RES-001",
+			Pickup:        "Aeropuerto",
+			Destination:   "Las Condes",
+			Amount:        25000,
+			Currency:      "CLP",
+			CompletedAt:   time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC),
+		}
+
+		invoiceRepo.On("ListAccrualCandidates", "CLP", accrualBatchSize).Return([]*domain.BillingAccrualCandidate{candidate}, nil)
+		invoiceRepo.On("CreateAccrual", mock.MatchedBy(func(accrual *domain.BillingAccrual) bool {
+			return accrual.ReservationID == "RES-001" && accrual.Amount == 25000 && accrual.Currency == "CLP"
+		})).Return(nil)
+
+		err := useCase.AccrueCompletedTrips(context.Background())
+
+		require.NoError(t, err)
+		invoiceRepo.AssertExpectations(t)
+	})
+}
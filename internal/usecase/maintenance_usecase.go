@@ -0,0 +1,736 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+var maintenanceServiceLabels = map[domain.MaintenanceServiceType]string{
+	domain.MaintenanceServiceOilChange:      "Cambio de aceite",
+	domain.MaintenanceServiceTires:          "Neumáticos",
+	domain.MaintenanceServiceBrakes:         "Frenos",
+	domain.MaintenanceServiceGeneralService: "Mantención general",
+	domain.MaintenanceServiceInspection:     "Revisión",
+	domain.MaintenanceServiceRepair:         "Reparación",
+	domain.MaintenanceServiceOther:          "Otro",
+}
+
+type MaintenanceUseCase struct {
+	maintenanceRepo domain.MaintenanceRepository
+	vehicleRepo     domain.VehicleRepository
+	userRepo        domain.UserRepository
+	emailService    domain.EmailService
+	documentUseCase *DocumentUseCase
+	pricingUseCase  *PricingUseCase
+	logger          *zap.Logger
+}
+
+func NewMaintenanceUseCase(
+	maintenanceRepo domain.MaintenanceRepository,
+	vehicleRepo domain.VehicleRepository,
+	userRepo domain.UserRepository,
+	emailService domain.EmailService,
+	documentUseCase *DocumentUseCase,
+	pricingUseCase *PricingUseCase,
+	logger *zap.Logger,
+) *MaintenanceUseCase {
+	return &MaintenanceUseCase{
+		maintenanceRepo: maintenanceRepo,
+		vehicleRepo:     vehicleRepo,
+		userRepo:        userRepo,
+		emailService:    emailService,
+		documentUseCase: documentUseCase,
+		pricingUseCase:  pricingUseCase,
+		logger:          logger,
+	}
+}
+
+// CreateRecord schedules a maintenance window for the vehicle or records a
+// service already performed. Completed services linked to a plan restart the
+// plan's intervals.
+func (uc *MaintenanceUseCase) CreateRecord(vehicleID string, req domain.CreateMaintenanceRecordRequest, createdBy uuid.UUID) (*domain.MaintenanceRecord, error) {
+	if _, err := uc.getVehicle(vehicleID); err != nil {
+		return nil, err
+	}
+	if req.PlanID != nil {
+		if _, err := uc.getVehiclePlan(*req.PlanID, vehicleID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	record := &domain.MaintenanceRecord{
+		VehicleID:   vehicleID,
+		PlanID:      req.PlanID,
+		ServiceType: req.ServiceType,
+		Odometer:    req.Odometer,
+		Cost:        req.Cost,
+		Workshop:    req.Workshop,
+		Description: req.Description,
+		CreatedBy:   &createdBy,
+	}
+
+	if req.ScheduledStart != nil {
+		if !req.ScheduledEnd.After(*req.ScheduledStart) || !req.ScheduledEnd.After(now) {
+			return nil, domain.ErrInvalidInput
+		}
+		record.ScheduledStart = req.ScheduledStart
+		record.ScheduledEnd = req.ScheduledEnd
+		record.Status = domain.MaintenanceStatusScheduled
+	} else {
+		if req.PerformedAt.After(now) {
+			return nil, domain.ErrInvalidInput
+		}
+		record.PerformedAt = req.PerformedAt
+		record.Status = domain.MaintenanceStatusCompleted
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get default currency for maintenance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	record.Currency = currency
+
+	if err := uc.maintenanceRepo.CreateRecord(record); err != nil {
+		uc.logger.Error("Failed to create maintenance record", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if record.Status == domain.MaintenanceStatusCompleted {
+		uc.recordPlanService(record)
+	}
+
+	uc.logger.Info("Maintenance record created",
+		zap.String("record_id", record.ID.String()),
+		zap.String("vehicle_id", vehicleID),
+		zap.String("status", string(record.Status)),
+	)
+	return record, nil
+}
+
+// GetRecord returns the record together with its attachments.
+func (uc *MaintenanceUseCase) GetRecord(id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	record, err := uc.getRecord(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerType := domain.DocumentOwnerMaintenance
+	ownerID := id.String()
+	attachments, _, err := uc.documentUseCase.ListDocuments(domain.ListDocumentsRequest{
+		OwnerType: &ownerType,
+		OwnerID:   &ownerID,
+		Page:      1,
+		PageSize:  100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	record.Attachments = attachments
+
+	return record, nil
+}
+
+// UpdateRecord corrects a record or moves it through its workflow. Starting
+// a record takes the vehicle out of service; completing or cancelling it
+// puts the vehicle back unless another window still covers it.
+func (uc *MaintenanceUseCase) UpdateRecord(id uuid.UUID, req domain.UpdateMaintenanceRecordRequest) (*domain.MaintenanceRecord, error) {
+	record, err := uc.getRecord(id)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := record.Status
+	if req.Status != nil && *req.Status != previous {
+		if !maintenanceTransitionAllowed(previous, *req.Status) {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+		record.Status = *req.Status
+	}
+
+	if req.ScheduledStart != nil {
+		record.ScheduledStart = req.ScheduledStart
+	}
+	if req.ScheduledEnd != nil {
+		record.ScheduledEnd = req.ScheduledEnd
+	}
+	if (record.ScheduledStart == nil) != (record.ScheduledEnd == nil) {
+		return nil, domain.ErrInvalidInput
+	}
+	if record.IsWindow() && !record.ScheduledEnd.After(*record.ScheduledStart) {
+		return nil, domain.ErrInvalidInput
+	}
+	if req.PerformedAt != nil {
+		record.PerformedAt = req.PerformedAt
+	}
+	if req.Odometer != nil {
+		record.Odometer = req.Odometer
+	}
+	if req.Cost != nil {
+		record.Cost = req.Cost
+	}
+	if req.Workshop != nil {
+		record.Workshop = req.Workshop
+	}
+	if req.Description != nil {
+		record.Description = req.Description
+	}
+
+	now := time.Now()
+	if record.Status == domain.MaintenanceStatusCompleted && record.PerformedAt == nil {
+		record.PerformedAt = &now
+	}
+	if record.PerformedAt != nil && record.PerformedAt.After(now) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := uc.maintenanceRepo.UpdateRecord(record); err != nil {
+		if err == domain.ErrMaintenanceRecordNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to update maintenance record", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if record.Status != previous {
+		switch record.Status {
+		case domain.MaintenanceStatusInProgress:
+			uc.holdVehicle(record.VehicleID)
+		case domain.MaintenanceStatusCompleted, domain.MaintenanceStatusCancelled:
+			if previous == domain.MaintenanceStatusInProgress {
+				uc.releaseVehicle(record.VehicleID, record.ID, now)
+			}
+		}
+	}
+	if record.Status == domain.MaintenanceStatusCompleted {
+		uc.recordPlanService(record)
+	}
+
+	uc.logger.Info("Maintenance record updated",
+		zap.String("record_id", id.String()),
+		zap.String("status", string(record.Status)),
+	)
+	return record, nil
+}
+
+func (uc *MaintenanceUseCase) ListRecords(req domain.ListMaintenanceRecordsRequest) ([]*domain.MaintenanceRecord, int, error) {
+	records, total, err := uc.maintenanceRepo.ListRecords(req)
+	if err != nil {
+		uc.logger.Error("Failed to list maintenance records", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return records, total, nil
+}
+
+// AddAttachment files an invoice, quote or workshop report against a record.
+func (uc *MaintenanceUseCase) AddAttachment(recordID uuid.UUID, fileName string, content io.Reader, uploadedBy uuid.UUID) (*domain.Document, error) {
+	if _, err := uc.getRecord(recordID); err != nil {
+		return nil, err
+	}
+
+	return uc.documentUseCase.UploadMaintenanceAttachment(recordID, fileName, content, uploadedBy)
+}
+
+// CreatePlan starts a recurring service for the vehicle. Without a last
+// service it counts from now and the vehicle's latest known odometer.
+func (uc *MaintenanceUseCase) CreatePlan(vehicleID string, req domain.CreateMaintenancePlanRequest, createdBy uuid.UUID) (*domain.MaintenancePlan, error) {
+	if _, err := uc.getVehicle(vehicleID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan := &domain.MaintenancePlan{
+		VehicleID:           vehicleID,
+		ServiceType:         req.ServiceType,
+		IntervalKm:          req.IntervalKm,
+		IntervalDays:        req.IntervalDays,
+		LastServiceAt:       now,
+		LastServiceOdometer: req.LastServiceOdometer,
+		Active:              true,
+		Notes:               req.Notes,
+		CreatedBy:           &createdBy,
+	}
+	if req.LastServiceAt != nil {
+		if req.LastServiceAt.After(now) {
+			return nil, domain.ErrInvalidInput
+		}
+		plan.LastServiceAt = *req.LastServiceAt
+	}
+
+	odometer, err := uc.maintenanceRepo.LatestOdometer(vehicleID)
+	if err != nil {
+		uc.logger.Error("Failed to get latest odometer", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if plan.LastServiceOdometer == nil {
+		plan.LastServiceOdometer = odometer
+	}
+
+	if err := uc.maintenanceRepo.CreatePlan(plan); err != nil {
+		uc.logger.Error("Failed to create maintenance plan", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	plan.Evaluate(now, odometer)
+
+	uc.logger.Info("Maintenance plan created",
+		zap.String("plan_id", plan.ID.String()),
+		zap.String("vehicle_id", vehicleID),
+		zap.String("service_type", string(plan.ServiceType)),
+	)
+	return plan, nil
+}
+
+func (uc *MaintenanceUseCase) UpdatePlan(id uuid.UUID, req domain.UpdateMaintenancePlanRequest) (*domain.MaintenancePlan, error) {
+	plan, err := uc.getPlan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IntervalKm != nil {
+		plan.IntervalKm = req.IntervalKm
+	}
+	if req.IntervalDays != nil {
+		plan.IntervalDays = req.IntervalDays
+	}
+	if req.Active != nil {
+		plan.Active = *req.Active
+	}
+	if req.Notes != nil {
+		plan.Notes = req.Notes
+	}
+
+	if err := uc.maintenanceRepo.UpdatePlan(plan); err != nil {
+		if err == domain.ErrMaintenancePlanNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to update maintenance plan", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if err := uc.evaluatePlans([]*domain.MaintenancePlan{plan}, time.Now()); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ListPlans returns the plans with their next due date and odometer, for a
+// single vehicle or the whole fleet.
+func (uc *MaintenanceUseCase) ListPlans(vehicleID *string, activeOnly bool) ([]*domain.MaintenancePlan, error) {
+	if vehicleID != nil {
+		if _, err := uc.getVehicle(*vehicleID); err != nil {
+			return nil, err
+		}
+	}
+
+	plans, err := uc.maintenanceRepo.ListPlans(vehicleID, activeOnly)
+	if err != nil {
+		uc.logger.Error("Failed to list maintenance plans", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if err := uc.evaluatePlans(plans, time.Now()); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// GetCostReport totals the completed maintenance of a vehicle, by service
+// type, between from (inclusive) and to (exclusive).
+func (uc *MaintenanceUseCase) GetCostReport(vehicleID string, from, to *time.Time) (*domain.MaintenanceCostReport, error) {
+	vehicle, err := uc.getVehicle(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := uc.costReports(&vehicleID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		return uc.newCostReport(vehicle, from, to, nil)
+	}
+	return reports[0], nil
+}
+
+// ListCostReports returns one cost report per vehicle with completed
+// maintenance in the period, most expensive first.
+func (uc *MaintenanceUseCase) ListCostReports(from, to *time.Time) ([]*domain.MaintenanceCostReport, error) {
+	reports, err := uc.costReports(nil, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].TotalCost > reports[j].TotalCost
+	})
+	return reports, nil
+}
+
+// ApplyMaintenanceWindows takes vehicles out of service when a scheduled
+// window opens and puts them back when it closes. It is run by the
+// scheduler every few minutes.
+func (uc *MaintenanceUseCase) ApplyMaintenanceWindows(ctx context.Context) error {
+	now := time.Now()
+
+	starting, err := uc.maintenanceRepo.ListWindowsToStart(now)
+	if err != nil {
+		return err
+	}
+	for _, record := range starting {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		record.Status = domain.MaintenanceStatusInProgress
+		if err := uc.maintenanceRepo.UpdateRecord(record); err != nil {
+			return err
+		}
+		uc.holdVehicle(record.VehicleID)
+	}
+
+	ending, err := uc.maintenanceRepo.ListWindowsToEnd(now)
+	if err != nil {
+		return err
+	}
+	for _, record := range ending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		record.Status = domain.MaintenanceStatusCompleted
+		if record.PerformedAt == nil {
+			record.PerformedAt = record.ScheduledEnd
+		}
+		if err := uc.maintenanceRepo.UpdateRecord(record); err != nil {
+			return err
+		}
+		uc.releaseVehicle(record.VehicleID, record.ID, now)
+		uc.recordPlanService(record)
+	}
+
+	if len(starting) > 0 || len(ending) > 0 {
+		uc.logger.Info("Maintenance windows applied",
+			zap.Int("started", len(starting)),
+			zap.Int("ended", len(ending)))
+	}
+	return nil
+}
+
+// SendMaintenanceReminders emails fleet managers about plans that are due
+// soon or overdue. It is run daily by the scheduler; each plan is reminded
+// once per service interval, and only marked once an admin received it.
+func (uc *MaintenanceUseCase) SendMaintenanceReminders(ctx context.Context) error {
+	now := time.Now()
+
+	plans, err := uc.maintenanceRepo.ListPlans(nil, true)
+	if err != nil {
+		return err
+	}
+	if err := uc.evaluatePlans(plans, now); err != nil {
+		return err
+	}
+
+	var due []*domain.MaintenancePlan
+	var items []domain.MaintenanceReminderItem
+	for _, plan := range plans {
+		if plan.DueState == domain.MaintenanceDueOK || plan.ReminderSentAt != nil {
+			continue
+		}
+		due = append(due, plan)
+		items = append(items, uc.maintenanceReminderItem(plan))
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	admins, err := uc.listAdmins()
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, admin := range admins {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := uc.emailService.SendMaintenanceReminder(admin.Email, admin.Name, items); err != nil {
+			uc.logger.Warn("Failed to send maintenance reminder", zap.Error(err), zap.String("email", admin.Email))
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		uc.logger.Warn("No admin received the maintenance reminders, retrying next run", zap.Int("plans", len(due)))
+		return nil
+	}
+
+	for _, plan := range due {
+		if err := uc.maintenanceRepo.MarkPlanReminded(plan.ID, now); err != nil {
+			return err
+		}
+	}
+
+	uc.logger.Info("Maintenance reminders sent",
+		zap.Int("plans", len(due)),
+		zap.Int("admins", sent))
+	return nil
+}
+
+// EnsureDriverVehicleInService implements domain.MaintenanceChecker. It
+// returns domain.ErrVehicleInMaintenance when a maintenance window covers
+// the driver's vehicle at the given time. Drivers without a vehicle pass.
+func (uc *MaintenanceUseCase) EnsureDriverVehicleInService(driverID string, at time.Time) error {
+	vehicle, err := uc.vehicleRepo.GetByDriverID(driverID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil
+		}
+		uc.logger.Error("Failed to get driver vehicle for maintenance", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	blocked, err := uc.maintenanceRepo.HasActiveWindow(vehicle.ID, at, nil)
+	if err != nil {
+		uc.logger.Error("Failed to check maintenance window", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if blocked {
+		return domain.ErrVehicleInMaintenance
+	}
+
+	return nil
+}
+
+func (uc *MaintenanceUseCase) costReports(vehicleID *string, from, to *time.Time) ([]*domain.MaintenanceCostReport, error) {
+	summary, err := uc.maintenanceRepo.CostSummary(vehicleID, from, to)
+	if err != nil {
+		uc.logger.Error("Failed to summarize maintenance costs", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	reports := make([]*domain.MaintenanceCostReport, 0, len(summary))
+	for id, lines := range summary {
+		vehicle, err := uc.getVehicle(id)
+		if err != nil {
+			return nil, err
+		}
+		report, err := uc.newCostReport(vehicle, from, to, lines)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (uc *MaintenanceUseCase) newCostReport(vehicle *domain.Vehicle, from, to *time.Time, lines []domain.MaintenanceCostLine) (*domain.MaintenanceCostReport, error) {
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get default currency for maintenance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	report := &domain.MaintenanceCostReport{
+		VehicleID:     vehicle.ID,
+		Plate:         vehicle.Plate,
+		From:          from,
+		To:            to,
+		Currency:      currency,
+		ByServiceType: []domain.MaintenanceCostLine{},
+	}
+	for _, line := range lines {
+		report.RecordCount += line.Count
+		report.TotalCost += line.Total
+		report.ByServiceType = append(report.ByServiceType, line)
+	}
+
+	return report, nil
+}
+
+// evaluatePlans fills in the due state of each plan, looking up each
+// vehicle's odometer once.
+func (uc *MaintenanceUseCase) evaluatePlans(plans []*domain.MaintenancePlan, now time.Time) error {
+	odometers := make(map[string]*int)
+	for _, plan := range plans {
+		odometer, ok := odometers[plan.VehicleID]
+		if !ok {
+			var err error
+			odometer, err = uc.maintenanceRepo.LatestOdometer(plan.VehicleID)
+			if err != nil {
+				uc.logger.Error("Failed to get latest odometer", zap.Error(err))
+				return domain.ErrInternalError
+			}
+			odometers[plan.VehicleID] = odometer
+		}
+		plan.Evaluate(now, odometer)
+	}
+
+	return nil
+}
+
+// recordPlanService restarts the intervals of the plan a completed record
+// belongs to. Failures are logged; the record itself is already saved.
+func (uc *MaintenanceUseCase) recordPlanService(record *domain.MaintenanceRecord) {
+	if record.PlanID == nil || record.PerformedAt == nil {
+		return
+	}
+
+	if err := uc.maintenanceRepo.RecordPlanService(*record.PlanID, *record.PerformedAt, record.Odometer); err != nil {
+		uc.logger.Error("Failed to record plan service",
+			zap.Error(err),
+			zap.String("plan_id", record.PlanID.String()),
+			zap.String("record_id", record.ID.String()))
+	}
+}
+
+func (uc *MaintenanceUseCase) holdVehicle(vehicleID string) {
+	status := domain.VehicleStatusMaintenance
+	if _, err := uc.vehicleRepo.Update(vehicleID, domain.UpdateVehicleRequest{Status: &status}); err != nil {
+		uc.logger.Error("Failed to set vehicle in maintenance", zap.Error(err), zap.String("vehicle_id", vehicleID))
+	}
+}
+
+// releaseVehicle returns a vehicle to service once no other window covers it.
+// Vehicles an admin moved out of MAINTENANCE in the meantime are left alone.
+func (uc *MaintenanceUseCase) releaseVehicle(vehicleID string, recordID uuid.UUID, now time.Time) {
+	blocked, err := uc.maintenanceRepo.HasActiveWindow(vehicleID, now, &recordID)
+	if err != nil {
+		uc.logger.Error("Failed to check maintenance window", zap.Error(err), zap.String("vehicle_id", vehicleID))
+		return
+	}
+	if blocked {
+		return
+	}
+
+	vehicle, err := uc.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		uc.logger.Error("Failed to get vehicle to release", zap.Error(err), zap.String("vehicle_id", vehicleID))
+		return
+	}
+	if vehicle.Status != domain.VehicleStatusMaintenance {
+		return
+	}
+
+	status := domain.VehicleStatusAvailable
+	if vehicle.DriverID != nil {
+		status = domain.VehicleStatusAssigned
+	}
+	if _, err := uc.vehicleRepo.Update(vehicleID, domain.UpdateVehicleRequest{Status: &status}); err != nil {
+		uc.logger.Error("Failed to release vehicle from maintenance", zap.Error(err), zap.String("vehicle_id", vehicleID))
+	}
+}
+
+func (uc *MaintenanceUseCase) maintenanceReminderItem(plan *domain.MaintenancePlan) domain.MaintenanceReminderItem {
+	item := domain.MaintenanceReminderItem{
+		Vehicle: plan.VehicleID,
+		Service: maintenanceServiceLabels[plan.ServiceType],
+		Overdue: plan.DueState == domain.MaintenanceDueOverdue,
+	}
+	if vehicle, err := uc.vehicleRepo.GetByID(plan.VehicleID); err == nil {
+		item.Vehicle = fmt.Sprintf("%s %s", vehicle.Brand, vehicle.Model)
+		if vehicle.Plate != nil {
+			item.Vehicle = fmt.Sprintf("Patente %s (%s %s)", *vehicle.Plate, vehicle.Brand, vehicle.Model)
+		}
+	}
+	if plan.NextDueAt != nil {
+		item.DueAt = plan.NextDueAt.Format("02/01/2006")
+	}
+	if plan.NextDueOdometer != nil {
+		item.DueKm = fmt.Sprintf("%d km", *plan.NextDueOdometer)
+	}
+
+	return item
+}
+
+func (uc *MaintenanceUseCase) listAdmins() ([]*domain.User, error) {
+	role := domain.UserRoleAdmin
+	status := domain.UserStatusActive
+	admins, _, err := uc.userRepo.List(domain.ListUsersRequest{
+		Role:     &role,
+		Status:   &status,
+		Page:     1,
+		PageSize: 100,
+		Sort:     "name",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admins: %w", err)
+	}
+	return admins, nil
+}
+
+func (uc *MaintenanceUseCase) getRecord(id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	record, err := uc.maintenanceRepo.GetRecord(id)
+	if err != nil {
+		if err == domain.ErrMaintenanceRecordNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get maintenance record", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return record, nil
+}
+
+func (uc *MaintenanceUseCase) getPlan(id uuid.UUID) (*domain.MaintenancePlan, error) {
+	plan, err := uc.maintenanceRepo.GetPlan(id)
+	if err != nil {
+		if err == domain.ErrMaintenancePlanNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get maintenance plan", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return plan, nil
+}
+
+// getVehiclePlan returns the plan, reporting plans of other vehicles as not
+// found.
+func (uc *MaintenanceUseCase) getVehiclePlan(id uuid.UUID, vehicleID string) (*domain.MaintenancePlan, error) {
+	plan, err := uc.getPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.VehicleID != vehicleID {
+		return nil, domain.ErrMaintenancePlanNotFound
+	}
+
+	return plan, nil
+}
+
+func (uc *MaintenanceUseCase) getVehicle(vehicleID string) (*domain.Vehicle, error) {
+	vehicle, err := uc.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get vehicle for maintenance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return vehicle, nil
+}
+
+// maintenanceTransitionAllowed reports whether a record may move from one
+// status to another. Completed and cancelled records are final.
+func maintenanceTransitionAllowed(from, to domain.MaintenanceStatus) bool {
+	switch from {
+	case domain.MaintenanceStatusScheduled:
+		return to == domain.MaintenanceStatusInProgress || to == domain.MaintenanceStatusCompleted || to == domain.MaintenanceStatusCancelled
+	case domain.MaintenanceStatusInProgress:
+		return to == domain.MaintenanceStatusCompleted || to == domain.MaintenanceStatusCancelled
+	default:
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockMaintenanceRepository implements the plan lookups and reminder marks
+// the use cases under test touch; any other call panics.
+type MockMaintenanceRepository struct {
+	domain.MaintenanceRepository
+	mock.Mock
+}
+
+func (m *MockMaintenanceRepository) ListPlans(vehicleID *string, activeOnly bool) ([]*domain.MaintenancePlan, error) {
+	args := m.Called(vehicleID, activeOnly)
+	return args.Get(0).([]*domain.MaintenancePlan), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) LatestOdometer(vehicleID string) (*int, error) {
+	args := m.Called(vehicleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) MarkPlanReminded(planID uuid.UUID, at time.Time) error {
+	args := m.Called(planID, at)
+	return args.Error(0)
+}
+
+func overduePlan() *domain.MaintenancePlan {
+	intervalDays := 90
+	return &domain.MaintenancePlan{
+		ID:            uuid.New(),
+		VehicleID:     "VEH-001",
+		ServiceType:   domain.MaintenanceServiceOilChange,
+		IntervalDays:  &intervalDays,
+		LastServiceAt: time.Now().AddDate(0, 0, -120),
+		Active:        true,
+	}
+}
+
+func TestMaintenanceUseCase_SendMaintenanceReminders(t *testing.T) {
+	admins := []*domain.User{
+		{Name: "Admin", Email: "admin@turivo.cl"},
+		{Name: "Flota", Email: "flota@turivo.cl"},
+	}
+
+	newUseCase := func(plan *domain.MaintenancePlan) (*MaintenanceUseCase, *MockMaintenanceRepository, *MockEmailService) {
+		maintenanceRepo := new(MockMaintenanceRepository)
+		vehicleRepo := new(MockVehicleRepository)
+		userRepo := new(MockUserRepository)
+		emailService := new(MockEmailService)
+
+		maintenanceRepo.On("ListPlans", (*string)(nil), true).Return([]*domain.MaintenancePlan{plan}, nil)
+		maintenanceRepo.On("LatestOdometer", plan.VehicleID).Return(nil, nil)
+		vehicleRepo.On("GetByID", plan.VehicleID).Return(nil, domain.ErrNotFound)
+		userRepo.On("List", mock.Anything).Return(admins, len(admins), nil)
+
+		useCase := NewMaintenanceUseCase(maintenanceRepo, vehicleRepo, userRepo, emailService, nil, nil, zap.NewNop())
+		return useCase, maintenanceRepo, emailService
+	}
+
+	t.Run("should mark plans once an admin received the reminder", func(t *testing.T) {
+		plan := overduePlan()
+		useCase, maintenanceRepo, emailService := newUseCase(plan)
+
+		emailService.On("SendMaintenanceReminder", "admin@turivo.cl", "Admin", mock.Anything).Return(errors.New("smtp down"))
+		emailService.On("SendMaintenanceReminder", "flota@turivo.cl", "Flota", mock.Anything).Return(nil)
+		maintenanceRepo.On("MarkPlanReminded", plan.ID, mock.Anything).Return(nil)
+
+		err := useCase.SendMaintenanceReminders(context.Background())
+
+		require.NoError(t, err)
+		maintenanceRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not mark plans when no reminder was sent", func(t *testing.T) {
+		plan := overduePlan()
+		useCase, maintenanceRepo, emailService := newUseCase(plan)
+
+		emailService.On("SendMaintenanceReminder", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+		err := useCase.SendMaintenanceReminders(context.Background())
+
+		require.NoError(t, err)
+		emailService.AssertNumberOfCalls(t, "SendMaintenanceReminder", 2)
+		maintenanceRepo.AssertNotCalled(t, "MarkPlanReminded", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	// reconciliationBatchSize caps the pending payments checked per run, so a
+	// slow gateway cannot hold the job past its interval
+	reconciliationBatchSize = 100
+	// maxReconciliationReportDays caps the report period; every payment in it
+	// is looked up at its gateway
+	maxReconciliationReportDays = 31
+)
+
+type PaymentReconciliationUseCase struct {
+	paymentRepo      domain.PaymentRepository
+	notificationRepo domain.PaymentNotificationRepository
+	invoiceRepo      domain.InvoiceRepository
+	walletRepo       domain.WalletRepository
+	gateways         domain.PaymentGatewayRegistry
+	pendingAfter     time.Duration
+	abandonAfter     time.Duration
+	logger           *zap.Logger
+}
+
+func NewPaymentReconciliationUseCase(
+	paymentRepo domain.PaymentRepository,
+	notificationRepo domain.PaymentNotificationRepository,
+	invoiceRepo domain.InvoiceRepository,
+	walletRepo domain.WalletRepository,
+	gateways domain.PaymentGatewayRegistry,
+	pendingAfter time.Duration,
+	abandonAfter time.Duration,
+	logger *zap.Logger,
+) *PaymentReconciliationUseCase {
+	return &PaymentReconciliationUseCase{
+		paymentRepo:      paymentRepo,
+		notificationRepo: notificationRepo,
+		invoiceRepo:      invoiceRepo,
+		walletRepo:       walletRepo,
+		gateways:         gateways,
+		pendingAfter:     pendingAfter,
+		abandonAfter:     abandonAfter,
+		logger:           logger,
+	}
+}
+
+// HandleNotification verifies and records a gateway webhook and settles the
+// payment it refers to. The notification only says which payment changed:
+// its status is read back from the gateway. Repeated events are acknowledged
+// without being processed again, and events about other objects are
+// recorded as IGNORED.
+func (uc *PaymentReconciliationUseCase) HandleNotification(gatewayName domain.PaymentGateway, req domain.PaymentNotificationRequest) (*domain.PaymentNotification, error) {
+	gateway, ok := uc.gateways.Get(gatewayName)
+	if !ok {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	verifier, ok := gateway.(domain.PaymentNotificationVerifier)
+	if !ok {
+		return nil, domain.ErrPaymentOperationUnsupported
+	}
+
+	notification, err := verifier.VerifyNotification(req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidSignature, domain.ErrInvalidInput:
+			uc.logger.Warn("Rejected payment notification", zap.Error(err), zap.String("gateway", string(gatewayName)))
+			return nil, err
+		}
+		uc.logger.Error("Failed to verify payment notification", zap.Error(err), zap.String("gateway", string(gatewayName)))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	notification.Status = domain.PaymentNotificationReceived
+	if err := uc.notificationRepo.Create(notification); err != nil {
+		if err == domain.ErrAlreadyExists {
+			return nil, err
+		}
+		uc.logger.Error("Failed to record payment notification", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	payment, err := uc.notifiedPayment(notification)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		uc.finishNotification(notification, domain.PaymentNotificationIgnored, nil)
+		return notification, nil
+	}
+	notification.PaymentID = &payment.ID
+
+	if payment.Status == domain.PaymentStatusPending && payment.TransactionRef != nil {
+		result, err := gateway.GetPaymentStatus(*payment.TransactionRef)
+		if err != nil {
+			uc.logger.Error("Failed to get notified payment status", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+			uc.finishNotification(notification, domain.PaymentNotificationFailed, err)
+			return nil, domain.ErrPaymentFailed
+		}
+		if result.Status != domain.PaymentStatusPending {
+			if _, err := uc.settle(payment, result); err != nil {
+				uc.finishNotification(notification, domain.PaymentNotificationFailed, err)
+				return nil, err
+			}
+		}
+	}
+
+	uc.finishNotification(notification, domain.PaymentNotificationProcessed, nil)
+	return notification, nil
+}
+
+// notifiedPayment finds the payment a notification refers to, or nil when
+// it is not one of ours.
+func (uc *PaymentReconciliationUseCase) notifiedPayment(notification *domain.PaymentNotification) (*domain.Payment, error) {
+	var payment *domain.Payment
+	var err error
+	switch {
+	case notification.PaymentID != nil:
+		payment, err = uc.paymentRepo.GetByID(*notification.PaymentID)
+	case notification.TransactionRef != nil:
+		payment, err = uc.paymentRepo.GetByTransactionRef(*notification.TransactionRef)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, nil
+		}
+		uc.logger.Error("Failed to get notified payment", zap.Error(err))
+		uc.finishNotification(notification, domain.PaymentNotificationFailed, err)
+		return nil, domain.ErrInternalError
+	}
+
+	// A notification of one gateway must not settle a payment of another
+	if payment.Gateway != notification.Gateway {
+		return nil, nil
+	}
+	return payment, nil
+}
+
+func (uc *PaymentReconciliationUseCase) finishNotification(notification *domain.PaymentNotification, status domain.PaymentNotificationStatus, cause error) {
+	now := time.Now()
+	notification.Status = status
+	notification.ProcessedAt = &now
+	if cause != nil {
+		message := cause.Error()
+		notification.Error = &message
+	}
+
+	if err := uc.notificationRepo.Update(notification); err != nil {
+		uc.logger.Warn("Failed to update payment notification", zap.Error(err), zap.String("notification_id", notification.ID.String()))
+	}
+}
+
+// ReconcilePending is the scheduled job that settles stale pending
+// payments.
+func (uc *PaymentReconciliationUseCase) ReconcilePending(ctx context.Context) error {
+	_, err := uc.ReconcilePendingPayments(ctx)
+	return err
+}
+
+// ReconcilePendingPayments checks the payments pending for longer than the
+// configured delay with their gateways. Those the gateway has settled take
+// its status; those still pending past the abandon delay are rejected, as
+// the customer will not finish them. Payments of gateways that cannot be
+// queried, like bank transfers, wait for an admin.
+func (uc *PaymentReconciliationUseCase) ReconcilePendingPayments(ctx context.Context) (*domain.PaymentReconciliationRun, error) {
+	now := time.Now()
+	payments, err := uc.paymentRepo.ListPending(now.Add(-uc.pendingAfter), reconciliationBatchSize)
+	if err != nil {
+		uc.logger.Error("Failed to list pending payments", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	run := &domain.PaymentReconciliationRun{}
+	for _, payment := range payments {
+		if ctx.Err() != nil {
+			return run, ctx.Err()
+		}
+		run.Checked++
+
+		result, err := uc.gatewayStatus(payment)
+		switch {
+		case err == domain.ErrPaymentOperationUnsupported:
+			run.Skipped++
+			continue
+		case err != nil:
+			uc.logger.Warn("Failed to get pending payment status", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+			run.Failed++
+			continue
+		}
+
+		if result.Status != domain.PaymentStatusPending {
+			if _, err := uc.settle(payment, result); err != nil {
+				run.Failed++
+				continue
+			}
+			run.Settled++
+			continue
+		}
+
+		if now.Sub(payment.CreatedAt) < uc.abandonAfter {
+			run.Pending++
+			continue
+		}
+		if err := uc.abandon(payment, now); err != nil {
+			run.Failed++
+			continue
+		}
+		run.Abandoned++
+	}
+
+	if run.Checked > 0 {
+		uc.logger.Info("Pending payments reconciled",
+			zap.Int("checked", run.Checked),
+			zap.Int("settled", run.Settled),
+			zap.Int("abandoned", run.Abandoned),
+			zap.Int("failed", run.Failed))
+	}
+	return run, nil
+}
+
+// GetReconciliationReport compares the payments created in [from, to) with
+// what their gateways report and lists those that disagree.
+func (uc *PaymentReconciliationUseCase) GetReconciliationReport(ctx context.Context, from, to time.Time, gateway *domain.PaymentGateway) (*domain.PaymentReconciliationReport, error) {
+	if !to.After(from) || to.Sub(from) > maxReconciliationReportDays*24*time.Hour {
+		return nil, domain.ErrInvalidInput
+	}
+
+	payments, err := uc.paymentRepo.ListCreatedBetween(from, to, gateway)
+	if err != nil {
+		uc.logger.Error("Failed to list payments to reconcile", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	report := &domain.PaymentReconciliationReport{
+		From:       from,
+		To:         to,
+		Gateway:    gateway,
+		Mismatches: []*domain.PaymentMismatch{},
+	}
+	for _, payment := range payments {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		result, err := uc.gatewayStatus(payment)
+		if err == domain.ErrPaymentOperationUnsupported {
+			report.Skipped++
+			continue
+		}
+		report.Checked++
+
+		if err != nil {
+			report.Mismatches = append(report.Mismatches, &domain.PaymentMismatch{
+				PaymentID:      payment.ID,
+				ReservationID:  payment.ReservationID,
+				Gateway:        payment.Gateway,
+				TransactionRef: payment.TransactionRef,
+				Kind:           domain.PaymentMismatchGatewayError,
+				Status:         payment.Status,
+				Amount:         payment.Amount,
+				Detail:         err.Error(),
+			})
+			continue
+		}
+
+		if mismatch := domain.ComparePayment(payment, result); mismatch != nil {
+			report.Mismatches = append(report.Mismatches, mismatch)
+			continue
+		}
+		report.Matched++
+	}
+
+	report.GeneratedAt = time.Now()
+	return report, nil
+}
+
+// gatewayStatus asks the payment's gateway for its transaction.
+// ErrPaymentOperationUnsupported means the payment cannot be checked.
+func (uc *PaymentReconciliationUseCase) gatewayStatus(payment *domain.Payment) (*domain.PaymentResult, error) {
+	if payment.TransactionRef == nil {
+		return nil, domain.ErrPaymentOperationUnsupported
+	}
+	gateway, ok := uc.gateways.Get(payment.Gateway)
+	if !ok {
+		return nil, domain.ErrPaymentOperationUnsupported
+	}
+	return gateway.GetPaymentStatus(*payment.TransactionRef)
+}
+
+// settle stores the status the gateway reports for a pending payment,
+// keeping the payload stored when it was created.
+func (uc *PaymentReconciliationUseCase) settle(payment *domain.Payment, result *domain.PaymentResult) (*domain.Payment, error) {
+	// Gateways that do not track payments leave the ID unset
+	if result.PaymentID != uuid.Nil && result.PaymentID != payment.ID {
+		uc.logger.Error("Gateway transaction belongs to another payment",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("gateway_payment_id", result.PaymentID.String()))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	payload := payment.Payload
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	for key, value := range result.Payload {
+		payload[key] = value
+	}
+
+	updatedPayment, err := uc.paymentRepo.Update(payment.ID, result.Status, result.TransactionRef, payload)
+	if err != nil {
+		uc.logger.Error("Failed to update reconciled payment", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	settleInvoice(uc.invoiceRepo, updatedPayment, uc.logger)
+	creditTopUp(uc.walletRepo, updatedPayment, uc.logger)
+
+	uc.logger.Info("Payment reconciled",
+		zap.String("payment_id", payment.ID.String()),
+		zap.String("gateway", string(payment.Gateway)),
+		zap.String("status", string(result.Status)))
+
+	return updatedPayment, nil
+}
+
+func (uc *PaymentReconciliationUseCase) abandon(payment *domain.Payment, now time.Time) error {
+	payload := payment.Payload
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	payload["abort_reason"] = "EXPIRED"
+	payload["aborted_at"] = now.Format(time.RFC3339)
+
+	if _, err := uc.paymentRepo.Update(payment.ID, domain.PaymentStatusRejected, nil, payload); err != nil {
+		uc.logger.Error("Failed to reject abandoned payment", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+		return err
+	}
+
+	uc.logger.Info("Abandoned payment rejected", zap.String("payment_id", payment.ID.String()))
+	return nil
+}
@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,28 +12,56 @@ import (
 	"turivo-backend/internal/domain"
 )
 
+// maxCompanyPaymentExport caps the payments exported at once.
+const maxCompanyPaymentExport = 10000
+
 type PaymentUseCase struct {
-	paymentRepo     domain.PaymentRepository
-	reservationRepo domain.ReservationRepository
-	paymentGateway  domain.PaymentGatewayService
-	logger          *zap.Logger
+	paymentRepo       domain.PaymentRepository
+	refundRepo        domain.RefundRepository
+	billingRepo       domain.CompanyBillingRepository
+	invoiceRepo       domain.InvoiceRepository
+	paymentMethodRepo domain.PaymentMethodRepository
+	reservationRepo   domain.ReservationRepository
+	walletRepo        domain.WalletRepository
+	credit            domain.CreditChecker
+	gateways          domain.PaymentGatewayRegistry
+	pricingUseCase    *PricingUseCase
+	logger            *zap.Logger
 }
 
 func NewPaymentUseCase(
 	paymentRepo domain.PaymentRepository,
+	refundRepo domain.RefundRepository,
+	billingRepo domain.CompanyBillingRepository,
+	invoiceRepo domain.InvoiceRepository,
+	paymentMethodRepo domain.PaymentMethodRepository,
 	reservationRepo domain.ReservationRepository,
-	paymentGateway domain.PaymentGatewayService,
+	walletRepo domain.WalletRepository,
+	credit domain.CreditChecker,
+	gateways domain.PaymentGatewayRegistry,
+	pricingUseCase *PricingUseCase,
 	logger *zap.Logger,
 ) *PaymentUseCase {
 	return &PaymentUseCase{
-		paymentRepo:     paymentRepo,
-		reservationRepo: reservationRepo,
-		paymentGateway:  paymentGateway,
-		logger:          logger,
+		paymentRepo:       paymentRepo,
+		refundRepo:        refundRepo,
+		billingRepo:       billingRepo,
+		invoiceRepo:       invoiceRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		reservationRepo:   reservationRepo,
+		walletRepo:        walletRepo,
+		credit:            credit,
+		gateways:          gateways,
+		pricingUseCase:    pricingUseCase,
+		logger:            logger,
 	}
 }
 
 func (uc *PaymentUseCase) CreatePayment(req domain.CreatePaymentRequest) (*domain.Payment, error) {
+	if req.InvoiceID != nil {
+		return uc.createInvoicePayment(*req.InvoiceID, req.Method)
+	}
+
 	uc.logger.Info("Creating payment", zap.String("reservation_id", req.ReservationID))
 
 	// Check if reservation exists
@@ -51,7 +82,7 @@ func (uc *PaymentUseCase) CreatePayment(req domain.CreatePaymentRequest) (*domai
 	}
 
 	for _, payment := range existingPayments {
-		if payment.Status == domain.PaymentStatusApproved {
+		if payment.IsCaptured() {
 			return nil, domain.ErrPaymentAlreadyPaid
 		}
 	}
@@ -62,24 +93,142 @@ func (uc *PaymentUseCase) CreatePayment(req domain.CreatePaymentRequest) (*domai
 		return nil, domain.ErrInvalidInput
 	}
 
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency for payment", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	// The method must be offered for the reservation's company and currency
+	methods, err := uc.availableMethods(reservation, currency)
+	if err != nil {
+		return nil, err
+	}
+	if !containsPaymentGateway(methods, req.Method) {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	gateway, _ := uc.gateways.Get(req.Method)
+
+	// Company credit is debited from the company's wallet, if it has one,
+	// when the trip completes
+	if req.Method == domain.PaymentGatewayCompanyCredit && reservation.OrgID != nil {
+		if err := uc.credit.EnsureCredit(*reservation.OrgID, *reservation.Amount, reservation.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create payment
 	payment := &domain.Payment{
 		ID:            uuid.New(),
 		ReservationID: req.ReservationID,
 		Gateway:       req.Method,
 		Amount:        *reservation.Amount,
-		Currency:      "CLP",
+		Currency:      currency,
 		Status:        domain.PaymentStatusPending,
 		CreatedAt:     time.Now(),
 	}
 
+	return uc.startPayment(payment, gateway)
+}
+
+// createInvoicePayment pays the total of an issued or overdue invoice with
+// one of the methods of the invoiced company. Company credit is not
+// offered: it would bill the invoice to the company again.
+func (uc *PaymentUseCase) createInvoicePayment(invoiceID uuid.UUID, method domain.PaymentGateway) (*domain.Payment, error) {
+	uc.logger.Info("Creating invoice payment", zap.String("invoice_id", invoiceID.String()))
+
+	invoice, err := uc.invoiceRepo.GetInvoice(invoiceID)
+	if err != nil {
+		if err == domain.ErrInvoiceNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get invoice for payment", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if !invoice.IsPayable() {
+		return nil, domain.ErrInvoiceNotPayable
+	}
+
+	if method == domain.PaymentGatewayCompanyCredit {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	settings, err := uc.paymentMethodRepo.ListByCompany(invoice.CompanyID)
+	if err != nil {
+		uc.logger.Error("Failed to get company payment methods", zap.Error(err), zap.String("company_id", invoice.CompanyID.String()))
+		return nil, domain.ErrInternalError
+	}
+	if !containsPaymentGateway(domain.AvailablePaymentMethods(uc.gateways, settings, invoice.Currency), method) {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	gateway, _ := uc.gateways.Get(method)
+
+	payment := &domain.Payment{
+		ID:        uuid.New(),
+		InvoiceID: &invoice.ID,
+		Gateway:   method,
+		Amount:    invoice.Total,
+		Currency:  invoice.Currency,
+		Status:    domain.PaymentStatusPending,
+		CreatedAt: time.Now(),
+		Invoice:   invoice,
+	}
+
+	return uc.startPayment(payment, gateway)
+}
+
+// CreateTopUp adds money to the company's wallet through one of the
+// company's payment methods. The wallet is credited once the payment is
+// captured.
+func (uc *PaymentUseCase) CreateTopUp(companyID uuid.UUID, req domain.WalletTopUpRequest) (*domain.Payment, error) {
+	uc.logger.Info("Creating wallet top-up", zap.String("company_id", companyID.String()))
+
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrWalletNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get wallet for top-up", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	if req.Method == domain.PaymentGatewayCompanyCredit {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	settings, err := uc.paymentMethodRepo.ListByCompany(companyID)
+	if err != nil {
+		uc.logger.Error("Failed to get company payment methods", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+	if !containsPaymentGateway(domain.AvailablePaymentMethods(uc.gateways, settings, wallet.Currency), req.Method) {
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	gateway, _ := uc.gateways.Get(req.Method)
+
+	payment := &domain.Payment{
+		ID:        uuid.New(),
+		WalletID:  &wallet.ID,
+		Gateway:   req.Method,
+		Amount:    math.Round(req.Amount*100) / 100,
+		Currency:  wallet.Currency,
+		Status:    domain.PaymentStatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	return uc.startPayment(payment, gateway)
+}
+
+// startPayment stores the payment and opens it at the gateway. Gateways that
+// settle at once leave it approved or rejected; redirect gateways leave it
+// pending with the redirect the customer must follow.
+func (uc *PaymentUseCase) startPayment(payment *domain.Payment, gateway domain.PaymentGatewayService) (*domain.Payment, error) {
 	if err := uc.paymentRepo.Create(payment); err != nil {
 		uc.logger.Error("Failed to create payment", zap.Error(err))
 		return nil, domain.ErrInternalError
 	}
 
 	// Process payment through gateway
-	result, err := uc.paymentGateway.ProcessPayment(payment)
+	result, err := gateway.ProcessPayment(payment)
 	if err != nil {
 		uc.logger.Error("Failed to process payment", zap.Error(err))
 		return nil, domain.ErrPaymentFailed
@@ -97,6 +246,10 @@ func (uc *PaymentUseCase) CreatePayment(req domain.CreatePaymentRequest) (*domai
 		return nil, domain.ErrInternalError
 	}
 
+	updatedPayment.Redirect = result.Redirect
+	settleInvoice(uc.invoiceRepo, updatedPayment, uc.logger)
+	creditTopUp(uc.walletRepo, updatedPayment, uc.logger)
+
 	uc.logger.Info("Payment created and processed",
 		zap.String("payment_id", payment.ID.String()),
 		zap.String("status", string(result.Status)))
@@ -104,6 +257,184 @@ func (uc *PaymentUseCase) CreatePayment(req domain.CreatePaymentRequest) (*domai
 	return updatedPayment, nil
 }
 
+// CommitPayment settles the payment whose transaction at gateway is token
+// once the customer is back from the gateway. Payments that are no longer
+// pending are returned as they are, so a repeated return does not charge
+// twice.
+func (uc *PaymentUseCase) CommitPayment(gateway domain.PaymentGateway, token string) (*domain.Payment, error) {
+	payment, err := uc.paymentRepo.GetByTransactionRef(token)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment by transaction ref", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	// A token of one gateway must not settle a payment of another
+	if payment.Gateway != gateway {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return payment, nil
+	}
+
+	return uc.commit(payment)
+}
+
+// ConfirmPayment settles a pending payment from the back office: a bank
+// transfer is approved once the money is in the account, and a redirect
+// payment is checked with its gateway in case the customer never came back.
+func (uc *PaymentUseCase) ConfirmPayment(paymentID uuid.UUID) (*domain.Payment, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment to confirm", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return nil, domain.ErrPaymentAlreadyPaid
+	}
+	if payment.TransactionRef == nil {
+		return nil, domain.ErrPaymentOperationUnsupported
+	}
+
+	return uc.commit(payment)
+}
+
+// commit settles a pending payment with its gateway and stores the result.
+func (uc *PaymentUseCase) commit(payment *domain.Payment) (*domain.Payment, error) {
+	gateway, err := uc.gatewayFor(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gateway.CommitPayment(*payment.TransactionRef)
+	if err != nil {
+		if err == domain.ErrPaymentOperationUnsupported {
+			return nil, err
+		}
+		uc.logger.Error("Failed to commit payment", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	// Gateways that do not track payments leave the ID unset
+	if result.PaymentID != uuid.Nil && result.PaymentID != payment.ID {
+		uc.logger.Error("Committed transaction belongs to another payment",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("gateway_payment_id", result.PaymentID.String()))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	// Keep what the gateway returned when the payment was created
+	payload := payment.Payload
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	for key, value := range result.Payload {
+		payload[key] = value
+	}
+
+	updatedPayment, err := uc.paymentRepo.Update(payment.ID, result.Status, result.TransactionRef, payload)
+	if err != nil {
+		uc.logger.Error("Failed to update payment with commit result", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	settleInvoice(uc.invoiceRepo, updatedPayment, uc.logger)
+	creditTopUp(uc.walletRepo, updatedPayment, uc.logger)
+
+	uc.logger.Info("Payment committed",
+		zap.String("payment_id", payment.ID.String()),
+		zap.String("gateway", string(payment.Gateway)),
+		zap.String("status", string(result.Status)))
+
+	return updatedPayment, nil
+}
+
+// AbortPayment rejects a pending payment the customer abandoned at the
+// gateway, either by cancelling or by letting the form time out. buyOrder
+// must match the one the transaction was opened with.
+func (uc *PaymentUseCase) AbortPayment(paymentID uuid.UUID, buyOrder string, timedOut bool) (*domain.Payment, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment to abort", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return payment, nil
+	}
+
+	if stored, _ := payment.Payload["buy_order"].(string); stored == "" || stored != buyOrder {
+		uc.logger.Warn("Abort does not match the payment buy order",
+			zap.String("payment_id", paymentID.String()),
+			zap.String("buy_order", buyOrder))
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	reason := "ABORTED"
+	if timedOut {
+		reason = "TIMEOUT"
+	}
+
+	payload := payment.Payload
+	payload["abort_reason"] = reason
+	payload["aborted_at"] = time.Now().Format(time.RFC3339)
+
+	updatedPayment, err := uc.paymentRepo.Update(paymentID, domain.PaymentStatusRejected, nil, payload)
+	if err != nil {
+		uc.logger.Error("Failed to update aborted payment", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Payment aborted",
+		zap.String("payment_id", paymentID.String()),
+		zap.String("reason", reason))
+
+	return updatedPayment, nil
+}
+
+// GetGatewayStatus asks the gateway for the current state of the payment's
+// transaction without changing the stored payment.
+func (uc *PaymentUseCase) GetGatewayStatus(paymentID uuid.UUID) (*domain.PaymentResult, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment for gateway status", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if payment.TransactionRef == nil {
+		return nil, domain.ErrPaymentOperationUnsupported
+	}
+
+	gateway, err := uc.gatewayFor(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gateway.GetPaymentStatus(*payment.TransactionRef)
+	if err != nil {
+		if err == domain.ErrPaymentOperationUnsupported {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get payment status from gateway", zap.Error(err), zap.String("payment_id", paymentID.String()))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	return result, nil
+}
+
 func (uc *PaymentUseCase) GetPaymentByID(id uuid.UUID) (*domain.Payment, error) {
 	payment, err := uc.paymentRepo.GetByID(id)
 	if err != nil {
@@ -137,9 +468,17 @@ func (uc *PaymentUseCase) SimulatePayment(paymentID uuid.UUID, result domain.Pay
 		return nil, domain.ErrPaymentAlreadyPaid
 	}
 
+	gateway, err := uc.gatewayFor(payment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Simulate payment through gateway
-	simulationResult, err := uc.paymentGateway.SimulatePayment(paymentID, result)
+	simulationResult, err := gateway.SimulatePayment(paymentID, result)
 	if err != nil {
+		if err == domain.ErrPaymentOperationUnsupported {
+			return nil, err
+		}
 		uc.logger.Error("Failed to simulate payment", zap.Error(err))
 		return nil, domain.ErrInternalError
 	}
@@ -156,6 +495,9 @@ func (uc *PaymentUseCase) SimulatePayment(paymentID uuid.UUID, result domain.Pay
 		return nil, domain.ErrInternalError
 	}
 
+	settleInvoice(uc.invoiceRepo, updatedPayment, uc.logger)
+	creditTopUp(uc.walletRepo, updatedPayment, uc.logger)
+
 	uc.logger.Info("Payment simulation completed",
 		zap.String("payment_id", paymentID.String()),
 		zap.String("status", string(simulationResult.Status)))
@@ -173,22 +515,367 @@ func (uc *PaymentUseCase) GetPaymentsByReservationID(reservationID string) ([]*d
 	return payments, nil
 }
 
-// GetCompanyPayments gets payments for a specific company
-func (uc *PaymentUseCase) GetCompanyPayments(companyID uuid.UUID, page, pageSize int, status string) ([]*domain.Payment, int, error) {
-	uc.logger.Info("Getting company payments", 
+// RefundPayment returns part or all of a captured payment through its
+// gateway. The refund is recorded before calling the gateway so concurrent
+// refunds never add up to more than was captured; a failed gateway call
+// leaves it FAILED.
+func (uc *PaymentUseCase) RefundPayment(paymentID uuid.UUID, req domain.CreateRefundRequest, requestedBy uuid.UUID) (*domain.Refund, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment to refund", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if !payment.IsCaptured() {
+		return nil, domain.ErrPaymentNotRefundable
+	}
+
+	gateway, err := uc.gatewayFor(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := uc.refundRepo.ListByPayment(paymentID)
+	if err != nil {
+		uc.logger.Error("Failed to list payment refunds", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	summary := domain.NewPaymentRefunds(payment, refunds)
+	amount := summary.RefundableAmount
+	if req.Amount != nil {
+		amount = math.Round(*req.Amount*100) / 100
+	}
+	if amount <= 0 || amount > summary.RefundableAmount {
+		return nil, domain.ErrRefundExceedsPayment
+	}
+
+	refund := &domain.Refund{
+		PaymentID:   paymentID,
+		Amount:      amount,
+		Currency:    payment.Currency,
+		Reason:      req.Reason,
+		Status:      domain.RefundStatusPending,
+		RequestedBy: &requestedBy,
+	}
+	if err := uc.refundRepo.Create(refund, payment.Amount); err != nil {
+		if err == domain.ErrRefundExceedsPayment {
+			return nil, err
+		}
+		uc.logger.Error("Failed to create refund", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	result, gatewayErr := gateway.RefundPayment(payment, amount)
+	if gatewayErr != nil {
+		failure := gatewayErr.Error()
+		refund.Status = domain.RefundStatusFailed
+		refund.FailureReason = &failure
+		if err := uc.refundRepo.Update(refund); err != nil {
+			uc.logger.Error("Failed to mark refund as failed", zap.Error(err), zap.String("refund_id", refund.ID.String()))
+		}
+
+		if gatewayErr == domain.ErrPaymentOperationUnsupported {
+			return nil, gatewayErr
+		}
+		uc.logger.Error("Payment gateway refused the refund", zap.Error(gatewayErr), zap.String("payment_id", paymentID.String()))
+		return nil, domain.ErrPaymentFailed
+	}
+
+	refund.Status = domain.RefundStatusCompleted
+	refund.TransactionRef = result.TransactionRef
+	refund.Payload = result.Payload
+	if err := uc.refundRepo.Update(refund); err != nil {
+		// The money was returned: report the refund and leave the record to
+		// be fixed rather than failing the request
+		uc.logger.Error("Failed to mark refund as completed", zap.Error(err), zap.String("refund_id", refund.ID.String()))
+	}
+
+	uc.updateRefundedStatus(payment, refund)
+	uc.refundToWallet(payment, refund)
+
+	uc.logger.Info("Payment refunded",
+		zap.String("payment_id", paymentID.String()),
+		zap.String("refund_id", refund.ID.String()),
+		zap.Float64("amount", amount))
+
+	return refund, nil
+}
+
+// GetPaymentRefunds lists the refunds of a payment with its refunded and
+// still refundable totals.
+func (uc *PaymentUseCase) GetPaymentRefunds(paymentID uuid.UUID) (*domain.PaymentRefunds, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, domain.ErrPaymentNotFound
+		}
+		uc.logger.Error("Failed to get payment for refunds", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	refunds, err := uc.refundRepo.ListByPayment(paymentID)
+	if err != nil {
+		uc.logger.Error("Failed to list payment refunds", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return domain.NewPaymentRefunds(payment, refunds), nil
+}
+
+// updateRefundedStatus moves the payment to PARTIALLY_REFUNDED or REFUNDED
+// after a completed refund and records it on the reservation timeline.
+func (uc *PaymentUseCase) updateRefundedStatus(payment *domain.Payment, refund *domain.Refund) {
+	refunds, err := uc.refundRepo.ListByPayment(payment.ID)
+	if err != nil {
+		uc.logger.Warn("Failed to list refunds to update payment status", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+		return
+	}
+
+	summary := domain.NewPaymentRefunds(payment, refunds)
+	status := summary.RefundedStatus()
+	if status != payment.Status {
+		if _, err := uc.paymentRepo.Update(payment.ID, status, nil, nil); err != nil {
+			uc.logger.Warn("Failed to update refunded payment status", zap.Error(err), zap.String("payment_id", payment.ID.String()))
+		}
+	}
+
+	// Invoice payments and top-ups have no reservation timeline
+	if payment.ReservationID == "" {
+		return
+	}
+
+	title := "Reembolso parcial"
+	if status == domain.PaymentStatusRefunded {
+		title = "Pago reembolsado"
+	}
+	uc.addTimelineEvent(payment.ReservationID, title,
+		fmt.Sprintf("Se reembolsaron %.2f %s (total reembolsado: %.2f de %.2f). Motivo: %s",
+			refund.Amount, refund.Currency, summary.RefundedAmount, summary.CapturedAmount, refund.Reason),
+		"info")
+}
+
+// refundToWallet posts a completed refund to the wallet it concerns: a
+// refunded top-up is taken back from the wallet, and a refunded company
+// credit payment of a trip already debited is given back to it. Refunds of
+// trips not debited yet lower the debit instead.
+func (uc *PaymentUseCase) refundToWallet(payment *domain.Payment, refund *domain.Refund) {
+	var tx *domain.WalletTransaction
+	switch {
+	case payment.WalletID != nil:
+		wallet := &domain.CompanyWallet{ID: *payment.WalletID}
+		tx = domain.NewWalletTransaction(wallet, domain.WalletTransactionTopUpRefund, -refund.Amount,
+			"Reembolso de recarga: "+refund.Reason)
+	case payment.Gateway == domain.PaymentGatewayCompanyCredit && payment.ReservationID != "":
+		debited, err := uc.walletRepo.HasTripDebit(payment.ReservationID)
+		if err != nil {
+			uc.logger.Error("Failed to check trip debit for refund", zap.Error(err), zap.String("reservation_id", payment.ReservationID))
+			return
+		}
+		if !debited {
+			return
+		}
+
+		reservation, err := uc.reservationRepo.GetByID(payment.ReservationID)
+		if err != nil || reservation.OrgID == nil {
+			uc.logger.Error("Failed to get debited reservation for refund", zap.Error(err), zap.String("reservation_id", payment.ReservationID))
+			return
+		}
+		wallet, err := uc.walletRepo.GetByCompany(*reservation.OrgID)
+		if err != nil {
+			uc.logger.Error("Failed to get wallet for refund", zap.Error(err), zap.String("reservation_id", payment.ReservationID))
+			return
+		}
+
+		tx = domain.NewWalletTransaction(wallet, domain.WalletTransactionTripRefund, refund.Amount,
+			fmt.Sprintf("Reembolso del viaje %s: %s", payment.ReservationID, refund.Reason))
+		tx.ReservationID = &reservation.ID
+	default:
+		return
+	}
+	tx.PaymentID = &payment.ID
+	tx.RefundID = &refund.ID
+
+	if _, err := uc.walletRepo.Post(tx); err != nil && err != domain.ErrAlreadyExists {
+		uc.logger.Error("Failed to post refund to wallet", zap.Error(err),
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("refund_id", refund.ID.String()))
+	}
+}
+
+func (uc *PaymentUseCase) addTimelineEvent(reservationID, title, description, variant string) {
+	event := domain.TimelineEvent{
+		ReservationID: reservationID,
+		Title:         title,
+		Description:   description,
+		At:            time.Now(),
+		Variant:       variant,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := uc.reservationRepo.AddTimelineEvent(reservationID, event); err != nil {
+		uc.logger.Warn("Failed to add payment timeline event", zap.Error(err), zap.String("reservation_id", reservationID))
+	}
+}
+
+// ListPaymentMethods returns the methods the reservation can be paid with
+// in the current currency.
+func (uc *PaymentUseCase) ListPaymentMethods(reservationID string) (*domain.PaymentMethods, error) {
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for payment methods", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency for payment methods", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	methods, err := uc.availableMethods(reservation, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PaymentMethods{
+		ReservationID: reservationID,
+		Currency:      currency,
+		Methods:       methods,
+	}, nil
+}
+
+// GetCompanyPaymentMethods lists the payment method settings of a company.
+func (uc *PaymentUseCase) GetCompanyPaymentMethods(companyID uuid.UUID) ([]domain.CompanyPaymentMethod, error) {
+	methods, err := uc.paymentMethodRepo.ListByCompany(companyID)
+	if err != nil {
+		uc.logger.Error("Failed to list company payment methods", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return methods, nil
+}
+
+// UpdateCompanyPaymentMethods replaces the payment method settings of a
+// company. A currency without settings falls back to the default methods.
+func (uc *PaymentUseCase) UpdateCompanyPaymentMethods(companyID uuid.UUID, req domain.UpdateCompanyPaymentMethodsRequest) ([]domain.CompanyPaymentMethod, error) {
+	seen := make(map[string]bool, len(req.Methods))
+	methods := make([]domain.CompanyPaymentMethod, 0, len(req.Methods))
+	for _, input := range req.Methods {
+		key := string(input.Gateway) + "/" + input.Currency
+		if seen[key] {
+			return nil, domain.ErrInvalidInput
+		}
+		seen[key] = true
+
+		methods = append(methods, domain.CompanyPaymentMethod{
+			CompanyID: companyID,
+			Gateway:   input.Gateway,
+			Currency:  input.Currency,
+			Enabled:   input.Enabled,
+		})
+	}
+
+	if err := uc.paymentMethodRepo.ReplaceForCompany(companyID, methods); err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrNotFound
+		}
+		uc.logger.Error("Failed to update company payment methods", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Company payment methods updated",
 		zap.String("company_id", companyID.String()),
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
-		zap.String("status", status))
+		zap.Int("count", len(methods)))
+
+	return uc.GetCompanyPaymentMethods(companyID)
+}
+
+// availableMethods applies the settings of the reservation's company, if
+// any, to the configured gateways.
+func (uc *PaymentUseCase) availableMethods(reservation *domain.Reservation, currency string) ([]domain.PaymentGateway, error) {
+	var settings []domain.CompanyPaymentMethod
+	if reservation.OrgID != nil {
+		var err error
+		settings, err = uc.paymentMethodRepo.ListByCompany(*reservation.OrgID)
+		if err != nil {
+			uc.logger.Error("Failed to get company payment methods", zap.Error(err), zap.String("company_id", reservation.OrgID.String()))
+			return nil, domain.ErrInternalError
+		}
+	}
+
+	return domain.AvailablePaymentMethods(uc.gateways, settings, currency), nil
+}
+
+// gatewayFor returns the gateway that processes the payment's method.
+func (uc *PaymentUseCase) gatewayFor(payment *domain.Payment) (domain.PaymentGatewayService, error) {
+	gateway, ok := uc.gateways.Get(payment.Gateway)
+	if !ok {
+		uc.logger.Error("Payment gateway is not configured",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("gateway", string(payment.Gateway)))
+		return nil, domain.ErrPaymentMethodUnavailable
+	}
+	return gateway, nil
+}
+
+func containsPaymentGateway(gateways []domain.PaymentGateway, gateway domain.PaymentGateway) bool {
+	for _, candidate := range gateways {
+		if candidate == gateway {
+			return true
+		}
+	}
+	return false
+}
 
-	// For now, return empty results since we don't have real payment data
-	// TODO: Implement real payment filtering by company
-	payments := []*domain.Payment{}
-	total := 0
+// GetCompanyPayments lists a page of the payments of a company's
+// reservations with the totals of every matching payment.
+func (uc *PaymentUseCase) GetCompanyPayments(filter domain.CompanyPaymentFilter) (*domain.CompanyPaymentList, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	payments, total, err := uc.billingRepo.ListPayments(filter, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	if err != nil {
+		uc.logger.Error("Failed to list company payments", zap.Error(err), zap.String("company_id", filter.CompanyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	totals, err := uc.billingRepo.SummarizePayments(filter)
+	if err != nil {
+		uc.logger.Error("Failed to sum company payments", zap.Error(err), zap.String("company_id", filter.CompanyID.String()))
+		return nil, domain.ErrInternalError
+	}
 
-	uc.logger.Info("Company payments retrieved", 
-		zap.Int("count", len(payments)),
-		zap.Int("total", total))
+	return &domain.CompanyPaymentList{
+		Payments: payments,
+		Total:    total,
+		Totals:   *totals,
+	}, nil
+}
+
+// ExportCompanyPayments returns every payment matching the filter, ignoring
+// its page, up to maxCompanyPaymentExport rows. Larger exports must be
+// split by date range.
+func (uc *PaymentUseCase) ExportCompanyPayments(filter domain.CompanyPaymentFilter) ([]*domain.CompanyPayment, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 
-	return payments, total, nil
+	payments, total, err := uc.billingRepo.ListPayments(filter, maxCompanyPaymentExport, 0)
+	if err != nil {
+		uc.logger.Error("Failed to export company payments", zap.Error(err), zap.String("company_id", filter.CompanyID.String()))
+		return nil, domain.ErrInternalError
+	}
+	if total > maxCompanyPaymentExport {
+		return nil, domain.ErrExportTooLarge
+	}
+
+	return payments, nil
 }
@@ -58,8 +58,7 @@ func (uc *PricingUseCase) CalculatePrice(ctx context.Context, req *domain.Pricin
 
 	// 5. Aplicar redondeo
 	finalFare = uc.roundPrice(finalFare, settings.RoundingDecimals)
-	commission := uc.roundPrice(finalFare*settings.CommissionRate, settings.RoundingDecimals)
-	driverPayout := uc.roundPrice(finalFare-commission, settings.RoundingDecimals)
+	commission, driverPayout := uc.splitFare(finalFare, settings)
 
 	// 6. Obtener tasa de cambio si es necesario
 	exchangeRate := 1.0
@@ -192,6 +191,41 @@ func (uc *PricingUseCase) getFactors(ctx context.Context, req *domain.PricingReq
 	return factors, nil
 }
 
+// SplitFare divide una tarifa ya fijada entre la comisión de Turivo y el pago
+// al conductor, con la misma regla que CalculatePrice
+func (uc *PricingUseCase) SplitFare(ctx context.Context, fare float64) (*domain.PricingResult, error) {
+	settings, err := uc.pricingRepo.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fare = uc.roundPrice(fare, settings.RoundingDecimals)
+	commission, driverPayout := uc.splitFare(fare, settings)
+
+	return &domain.PricingResult{
+		Currency:     settings.DefaultCurrency,
+		FinalFare:    fare,
+		Commission:   commission,
+		DriverPayout: driverPayout,
+	}, nil
+}
+
+// DefaultCurrency retorna la moneda en que se registran tarifas y pagos
+func (uc *PricingUseCase) DefaultCurrency(ctx context.Context) (string, error) {
+	settings, err := uc.pricingRepo.GetSettings(ctx)
+	if err != nil {
+		return "", err
+	}
+	return settings.DefaultCurrency, nil
+}
+
+// splitFare calcula comisión y pago al conductor de una tarifa redondeada
+func (uc *PricingUseCase) splitFare(fare float64, settings *domain.PricingSettings) (float64, float64) {
+	commission := uc.roundPrice(fare*settings.CommissionRate, settings.RoundingDecimals)
+	driverPayout := uc.roundPrice(fare-commission, settings.RoundingDecimals)
+	return commission, driverPayout
+}
+
 // roundPrice redondea el precio según la configuración
 func (uc *PricingUseCase) roundPrice(price float64, decimals int) float64 {
 	multiplier := math.Pow(10, float64(decimals))
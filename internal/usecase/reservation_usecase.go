@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -15,6 +16,13 @@ type ReservationUseCase struct {
 	driverRepo      domain.DriverRepository
 	userRepo        domain.UserRepository
 	emailService    domain.EmailService
+	trackingLinks   domain.TrackingLinkIssuer
+	compliance      domain.ComplianceChecker
+	shifts          domain.ShiftChecker
+	maintenance     domain.MaintenanceChecker
+	capacity        domain.CapacityChecker
+	credit          domain.CreditChecker
+	fares           domain.FareSplitter
 	logger          *zap.Logger
 }
 
@@ -23,6 +31,13 @@ func NewReservationUseCase(
 	driverRepo domain.DriverRepository,
 	userRepo domain.UserRepository,
 	emailService domain.EmailService,
+	trackingLinks domain.TrackingLinkIssuer,
+	compliance domain.ComplianceChecker,
+	shifts domain.ShiftChecker,
+	maintenance domain.MaintenanceChecker,
+	capacity domain.CapacityChecker,
+	credit domain.CreditChecker,
+	fares domain.FareSplitter,
 	logger *zap.Logger,
 ) *ReservationUseCase {
 	return &ReservationUseCase{
@@ -30,6 +45,13 @@ func NewReservationUseCase(
 		driverRepo:      driverRepo,
 		userRepo:        userRepo,
 		emailService:    emailService,
+		trackingLinks:   trackingLinks,
+		compliance:      compliance,
+		shifts:          shifts,
+		maintenance:     maintenance,
+		capacity:        capacity,
+		credit:          credit,
+		fares:           fares,
 		logger:          logger,
 	}
 }
@@ -42,6 +64,16 @@ func (uc *ReservationUseCase) CreateReservation(req domain.CreateReservationRequ
 		return nil, domain.ErrReservationPastDate
 	}
 
+	// At least one vehicle of the requested type must fit the whole group
+	if err := uc.capacity.EnsureCapacityAvailable(vehicleType, req.Passengers); err != nil {
+		uc.logger.Warn("No vehicle can carry the reservation",
+			zap.String("vehicle_type", string(vehicleType)),
+			zap.Int("passengers", req.Passengers),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
 	// Generate reservation ID
 	reservationID := uc.reservationRepo.GenerateID()
 
@@ -54,6 +86,8 @@ func (uc *ReservationUseCase) CreateReservation(req domain.CreateReservationRequ
 		Destination: req.Destination,
 		DateTime:    req.DateTime,
 		Passengers:  req.Passengers,
+		VehicleType: &vehicleType,
+		CostCenter:  req.CostCenter,
 		Status:      domain.ReservationStatusActiva,
 		Notes:       req.Notes,
 		CreatedAt:   time.Now(),
@@ -64,6 +98,24 @@ func (uc *ReservationUseCase) CreateReservation(req domain.CreateReservationRequ
 	price := reservation.CalculatePrice(vehicleType, hasSpecialLanguage, stops)
 	reservation.Amount = &price
 
+	// Lock the driver payout with the price so later commission changes
+	// do not touch booked trips
+	split, err := uc.fares.SplitFare(context.Background(), price)
+	if err != nil {
+		uc.logger.Error("Failed to split reservation fare", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	reservation.Commission = &split.Commission
+	reservation.DriverPayout = &split.DriverPayout
+	reservation.Currency = &split.Currency
+
+	// Companies with a wallet must have balance or credit left for the trip
+	if reservation.OrgID != nil {
+		if err := uc.credit.EnsureCredit(*reservation.OrgID, price, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	// Calculate distance
 	distance := reservation.CalculateDistance()
 	reservation.DistanceKM = &distance
@@ -93,8 +145,12 @@ func (uc *ReservationUseCase) CreateReservation(req domain.CreateReservationRequ
 	if err != nil {
 		uc.logger.Warn("Failed to get user for email notifications", zap.Error(err))
 	} else {
-		// Send confirmation email to user
-		if err := uc.emailService.SendReservationCreated(user.Email, reservation, user); err != nil {
+		// Send confirmation email to user, with a public tracking link when possible
+		trackingToken, err := uc.trackingLinks.IssueTrackingToken(reservationID, req.UserID)
+		if err != nil {
+			uc.logger.Warn("Failed to issue tracking link for confirmation email", zap.Error(err))
+		}
+		if err := uc.emailService.SendReservationCreated(user.Email, reservation, user, trackingToken); err != nil {
 			uc.logger.Warn("Failed to send reservation confirmation email", zap.Error(err))
 			// Don't fail the reservation creation for email errors
 		}
@@ -319,6 +375,46 @@ func (uc *ReservationUseCase) AssignDriver(reservationID string, driverID string
 		return nil, err
 	}
 
+	// Block drivers with expired or unapproved documents
+	if err := uc.compliance.EnsureDriverEligible(driverID); err != nil {
+		uc.logger.Warn("Driver is not eligible for assignment",
+			zap.String("reservation_id", reservationID),
+			zap.String("driver_id", driverID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// The pickup must fall within one of the driver's scheduled shifts
+	if err := uc.shifts.EnsureDriverOnShift(driverID, reservation.DateTime); err != nil {
+		uc.logger.Warn("Reservation is outside the driver's shift",
+			zap.String("reservation_id", reservationID),
+			zap.String("driver_id", driverID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// The driver's vehicle must not be held for maintenance at pickup time
+	if err := uc.maintenance.EnsureDriverVehicleInService(driverID, reservation.DateTime); err != nil {
+		uc.logger.Warn("Driver's vehicle is in maintenance",
+			zap.String("reservation_id", reservationID),
+			zap.String("driver_id", driverID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	// The driver's vehicle must be of the booked type and fit the group
+	if err := uc.capacity.EnsureDriverVehicleFits(driverID, reservation.VehicleType, reservation.Passengers); err != nil {
+		uc.logger.Warn("Driver's vehicle does not match the reservation",
+			zap.String("reservation_id", reservationID),
+			zap.String("driver_id", driverID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
 	// Assign driver using repository
 	err = uc.reservationRepo.AssignDriver(reservationID, driverID)
 	if err != nil {
@@ -0,0 +1,478 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// maxShiftDuration caps a single shift; longer coverage is planned as
+// consecutive shifts.
+const maxShiftDuration = 24 * time.Hour
+
+type ShiftUseCase struct {
+	shiftRepo   domain.ShiftRepository
+	driverRepo  domain.DriverRepository
+	vehicleRepo domain.VehicleRepository
+	enforce     bool
+	logger      *zap.Logger
+}
+
+func NewShiftUseCase(
+	shiftRepo domain.ShiftRepository,
+	driverRepo domain.DriverRepository,
+	vehicleRepo domain.VehicleRepository,
+	enforce bool,
+	logger *zap.Logger,
+) *ShiftUseCase {
+	return &ShiftUseCase{
+		shiftRepo:   shiftRepo,
+		driverRepo:  driverRepo,
+		vehicleRepo: vehicleRepo,
+		enforce:     enforce,
+		logger:      logger,
+	}
+}
+
+func (uc *ShiftUseCase) CreateShift(req domain.CreateShiftRequest, createdBy uuid.UUID) (*domain.Shift, error) {
+	if err := validateShiftWindow(req.StartsAt, req.EndsAt); err != nil {
+		return nil, err
+	}
+	if _, err := uc.getDriver(req.DriverID); err != nil {
+		return nil, err
+	}
+	if req.VehicleID != nil {
+		if err := uc.ensureVehicle(*req.VehicleID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.checkConflicts(req.DriverID, req.VehicleID, req.StartsAt, req.EndsAt, nil); err != nil {
+		return nil, err
+	}
+
+	shift := &domain.Shift{
+		DriverID:  req.DriverID,
+		VehicleID: req.VehicleID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Region:    req.Region,
+		Status:    domain.ShiftStatusScheduled,
+		Notes:     req.Notes,
+		CreatedBy: &createdBy,
+	}
+	if err := uc.shiftRepo.Create(shift); err != nil {
+		uc.logger.Error("Failed to create shift", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Shift created",
+		zap.String("shift_id", shift.ID.String()),
+		zap.String("driver_id", shift.DriverID),
+		zap.Time("starts_at", shift.StartsAt),
+		zap.Time("ends_at", shift.EndsAt),
+	)
+	return shift, nil
+}
+
+func (uc *ShiftUseCase) GetShift(id uuid.UUID) (*domain.Shift, error) {
+	shift, err := uc.shiftRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrShiftNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get shift", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return shift, nil
+}
+
+// UpdateShift edits a shift. Rescheduled or reinstated shifts are checked
+// for overlaps again; cancelling never conflicts.
+func (uc *ShiftUseCase) UpdateShift(id uuid.UUID, req domain.UpdateShiftRequest) (*domain.Shift, error) {
+	shift, err := uc.GetShift(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VehicleID != nil {
+		if err := uc.ensureVehicle(*req.VehicleID); err != nil {
+			return nil, err
+		}
+		shift.VehicleID = req.VehicleID
+	}
+	if req.StartsAt != nil {
+		shift.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		shift.EndsAt = *req.EndsAt
+	}
+	if req.Region != nil {
+		shift.Region = req.Region
+	}
+	if req.Status != nil {
+		shift.Status = *req.Status
+	}
+	if req.Notes != nil {
+		shift.Notes = req.Notes
+	}
+
+	if err := validateShiftWindow(shift.StartsAt, shift.EndsAt); err != nil {
+		return nil, err
+	}
+	if shift.Status == domain.ShiftStatusScheduled {
+		if err := uc.checkConflicts(shift.DriverID, shift.VehicleID, shift.StartsAt, shift.EndsAt, &shift.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.shiftRepo.Update(shift); err != nil {
+		if err == domain.ErrShiftNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to update shift", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Shift updated",
+		zap.String("shift_id", shift.ID.String()),
+		zap.String("status", string(shift.Status)),
+	)
+	return shift, nil
+}
+
+func (uc *ShiftUseCase) ListShifts(req domain.ListShiftsRequest) ([]*domain.Shift, int, error) {
+	shifts, total, err := uc.shiftRepo.List(req)
+	if err != nil {
+		uc.logger.Error("Failed to list shifts", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return shifts, total, nil
+}
+
+// GetRoster returns the driver and every shift of theirs overlapping
+// [from, to), for the calendar export.
+func (uc *ShiftUseCase) GetRoster(driverID string, from, to time.Time) (*domain.Driver, []*domain.Shift, error) {
+	driver, err := uc.getDriver(driverID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shifts, err := uc.shiftRepo.ListByDriver(driverID, from, to)
+	if err != nil {
+		uc.logger.Error("Failed to list driver shifts", zap.Error(err))
+		return nil, nil, domain.ErrInternalError
+	}
+
+	return driver, shifts, nil
+}
+
+// SubmitChange records a swap or time-off request from a driver. Swaps may
+// only hand over the driver's own upcoming shifts, and exchanges only take a
+// shift of the target driver.
+func (uc *ShiftUseCase) SubmitChange(driverID string, req domain.SubmitShiftChangeRequest) (*domain.ShiftChangeRequest, error) {
+	change := &domain.ShiftChangeRequest{
+		Type:     req.Type,
+		DriverID: driverID,
+		Reason:   req.Reason,
+		Status:   domain.ShiftChangeStatusPending,
+	}
+
+	switch req.Type {
+	case domain.ShiftChangeSwap:
+		if req.ShiftID == nil || req.TargetDriverID == nil || *req.TargetDriverID == driverID {
+			return nil, domain.ErrInvalidInput
+		}
+
+		shift, err := uc.GetShift(*req.ShiftID)
+		if err != nil {
+			return nil, err
+		}
+		if shift.DriverID != driverID {
+			return nil, domain.ErrShiftNotFound
+		}
+		if shift.Status != domain.ShiftStatusScheduled || !shift.StartsAt.After(time.Now()) {
+			return nil, domain.ErrInvalidInput
+		}
+
+		if _, err := uc.getDriver(*req.TargetDriverID); err != nil {
+			return nil, err
+		}
+
+		if req.TargetShiftID != nil {
+			target, err := uc.GetShift(*req.TargetShiftID)
+			if err != nil {
+				return nil, err
+			}
+			if target.DriverID != *req.TargetDriverID {
+				return nil, domain.ErrShiftNotFound
+			}
+			if target.Status != domain.ShiftStatusScheduled || !target.StartsAt.After(time.Now()) {
+				return nil, domain.ErrInvalidInput
+			}
+		}
+
+		change.ShiftID = req.ShiftID
+		change.TargetDriverID = req.TargetDriverID
+		change.TargetShiftID = req.TargetShiftID
+
+	case domain.ShiftChangeTimeOff:
+		if req.StartsAt == nil || req.EndsAt == nil || !req.EndsAt.After(*req.StartsAt) || !req.EndsAt.After(time.Now()) {
+			return nil, domain.ErrInvalidInput
+		}
+
+		change.StartsAt = req.StartsAt
+		change.EndsAt = req.EndsAt
+
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := uc.shiftRepo.CreateChange(change); err != nil {
+		uc.logger.Error("Failed to create shift request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Shift request submitted",
+		zap.String("request_id", change.ID.String()),
+		zap.String("type", string(change.Type)),
+		zap.String("driver_id", driverID),
+	)
+	return change, nil
+}
+
+func (uc *ShiftUseCase) GetChange(id uuid.UUID) (*domain.ShiftChangeRequest, error) {
+	change, err := uc.shiftRepo.GetChange(id)
+	if err != nil {
+		if err == domain.ErrShiftRequestNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get shift request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return change, nil
+}
+
+func (uc *ShiftUseCase) ListChanges(req domain.ListShiftChangesRequest) ([]*domain.ShiftChangeRequest, int, error) {
+	changes, total, err := uc.shiftRepo.ListChanges(req)
+	if err != nil {
+		uc.logger.Error("Failed to list shift requests", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return changes, total, nil
+}
+
+// ReviewChange approves or rejects a pending request. An approved swap moves
+// the shift (and the exchanged one, if any) to the other driver; approved
+// time off cancels the driver's shifts in that period.
+func (uc *ShiftUseCase) ReviewChange(id uuid.UUID, req domain.ReviewShiftChangeRequest, reviewerID uuid.UUID) (*domain.ShiftChangeRequest, error) {
+	change, err := uc.GetChange(id)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != domain.ShiftChangeStatusPending {
+		return nil, domain.ErrShiftRequestAlreadyReviewed
+	}
+
+	if req.Status == domain.ShiftChangeStatusApproved && change.Type == domain.ShiftChangeSwap {
+		if err := uc.checkSwap(change); err != nil {
+			return nil, err
+		}
+	}
+
+	change, err = uc.shiftRepo.ReviewChange(id, req.Status, req.Note, reviewerID, time.Now())
+	if err != nil {
+		if err == domain.ErrShiftRequestAlreadyReviewed {
+			return nil, err
+		}
+		uc.logger.Error("Failed to review shift request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if change.Status == domain.ShiftChangeStatusApproved {
+		if err := uc.apply(change); err != nil {
+			uc.logger.Error("Failed to apply approved shift request",
+				zap.Error(err),
+				zap.String("request_id", id.String()))
+			return nil, domain.ErrInternalError
+		}
+	}
+
+	uc.logger.Info("Shift request reviewed",
+		zap.String("request_id", id.String()),
+		zap.String("status", string(change.Status)),
+		zap.String("reviewed_by", reviewerID.String()),
+	)
+	return change, nil
+}
+
+// CancelChange withdraws a driver's own pending request.
+func (uc *ShiftUseCase) CancelChange(id uuid.UUID, driverID string) (*domain.ShiftChangeRequest, error) {
+	change, err := uc.GetChange(id)
+	if err != nil {
+		return nil, err
+	}
+	if change.DriverID != driverID {
+		return nil, domain.ErrShiftRequestNotFound
+	}
+
+	change, err = uc.shiftRepo.CancelChange(id, driverID)
+	if err != nil {
+		if err == domain.ErrShiftRequestAlreadyReviewed {
+			return nil, err
+		}
+		uc.logger.Error("Failed to cancel shift request", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return change, nil
+}
+
+// EnsureDriverOnShift implements domain.ShiftChecker. It always passes when
+// shift enforcement is disabled.
+func (uc *ShiftUseCase) EnsureDriverOnShift(driverID string, at time.Time) error {
+	if !uc.enforce {
+		return nil
+	}
+
+	if _, err := uc.shiftRepo.FindCovering(driverID, at); err != nil {
+		if err == domain.ErrShiftNotFound {
+			return domain.ErrOutsideShift
+		}
+		uc.logger.Error("Failed to find covering shift", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	return nil
+}
+
+// checkSwap verifies that both drivers are free for the shifts they would
+// take over. The shift each one gives away is excluded from the check.
+func (uc *ShiftUseCase) checkSwap(change *domain.ShiftChangeRequest) error {
+	shift, err := uc.GetShift(*change.ShiftID)
+	if err != nil {
+		return err
+	}
+	if shift.Status != domain.ShiftStatusScheduled || shift.DriverID != change.DriverID {
+		return domain.ErrShiftConflict
+	}
+
+	if err := uc.checkDriverFree(*change.TargetDriverID, shift.StartsAt, shift.EndsAt, change.TargetShiftID); err != nil {
+		return err
+	}
+
+	if change.TargetShiftID != nil {
+		target, err := uc.GetShift(*change.TargetShiftID)
+		if err != nil {
+			return err
+		}
+		if target.Status != domain.ShiftStatusScheduled || target.DriverID != *change.TargetDriverID {
+			return domain.ErrShiftConflict
+		}
+		if err := uc.checkDriverFree(change.DriverID, target.StartsAt, target.EndsAt, &shift.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *ShiftUseCase) apply(change *domain.ShiftChangeRequest) error {
+	switch change.Type {
+	case domain.ShiftChangeSwap:
+		return uc.shiftRepo.SwapDrivers(*change.ShiftID, *change.TargetDriverID, change.TargetShiftID, change.DriverID)
+	case domain.ShiftChangeTimeOff:
+		cancelled, err := uc.shiftRepo.CancelDriverShifts(change.DriverID, *change.StartsAt, *change.EndsAt)
+		if err != nil {
+			return err
+		}
+		if cancelled > 0 {
+			uc.logger.Info("Shifts cancelled for time off",
+				zap.String("driver_id", change.DriverID),
+				zap.Int("count", cancelled))
+		}
+	}
+	return nil
+}
+
+func (uc *ShiftUseCase) checkConflicts(driverID string, vehicleID *string, startsAt, endsAt time.Time, excludeID *uuid.UUID) error {
+	if err := uc.checkDriverFree(driverID, startsAt, endsAt, excludeID); err != nil {
+		return err
+	}
+
+	if vehicleID != nil {
+		overlap, err := uc.shiftRepo.HasVehicleOverlap(*vehicleID, startsAt, endsAt, excludeID)
+		if err != nil {
+			uc.logger.Error("Failed to check vehicle shift overlap", zap.Error(err))
+			return domain.ErrInternalError
+		}
+		if overlap {
+			return domain.ErrShiftConflict
+		}
+	}
+
+	return nil
+}
+
+// checkDriverFree fails when the driver already has a shift or approved time
+// off in the window.
+func (uc *ShiftUseCase) checkDriverFree(driverID string, startsAt, endsAt time.Time, excludeID *uuid.UUID) error {
+	overlap, err := uc.shiftRepo.HasDriverOverlap(driverID, startsAt, endsAt, excludeID)
+	if err != nil {
+		uc.logger.Error("Failed to check driver shift overlap", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if overlap {
+		return domain.ErrShiftConflict
+	}
+
+	timeOff, err := uc.shiftRepo.HasApprovedTimeOff(driverID, startsAt, endsAt)
+	if err != nil {
+		uc.logger.Error("Failed to check driver time off", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if timeOff {
+		return domain.ErrShiftConflict
+	}
+
+	return nil
+}
+
+func (uc *ShiftUseCase) getDriver(driverID string) (*domain.Driver, error) {
+	driver, err := uc.driverRepo.GetByID(driverID)
+	if err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for shift", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return driver, nil
+}
+
+func (uc *ShiftUseCase) ensureVehicle(vehicleID string) error {
+	if _, err := uc.vehicleRepo.GetByID(vehicleID); err != nil {
+		if err == domain.ErrNotFound {
+			return err
+		}
+		uc.logger.Error("Failed to get vehicle for shift", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	return nil
+}
+
+func validateShiftWindow(startsAt, endsAt time.Time) error {
+	if !endsAt.After(startsAt) || endsAt.Sub(startsAt) > maxShiftDuration {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
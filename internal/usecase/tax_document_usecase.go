@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// taxDocumentBatchSize caps how many documents a single scheduler run sends
+// or checks.
+const taxDocumentBatchSize = 100
+
+// TaxDocumentUseCase issues Chilean electronic tax documents (DTE) for
+// invoices and reservation payments, using the folios the SII authorized,
+// and sends them to the tax authority.
+type TaxDocumentUseCase struct {
+	taxDocumentRepo domain.TaxDocumentRepository
+	invoiceRepo     domain.InvoiceRepository
+	paymentRepo     domain.PaymentRepository
+	companyRepo     domain.CompanyRepository
+	builder         domain.TaxDocumentBuilder
+	taxAuthority    domain.TaxAuthorityClient
+	taxRate         float64
+	logger          *zap.Logger
+}
+
+func NewTaxDocumentUseCase(
+	taxDocumentRepo domain.TaxDocumentRepository,
+	invoiceRepo domain.InvoiceRepository,
+	paymentRepo domain.PaymentRepository,
+	companyRepo domain.CompanyRepository,
+	builder domain.TaxDocumentBuilder,
+	taxAuthority domain.TaxAuthorityClient,
+	taxRate float64,
+	logger *zap.Logger,
+) *TaxDocumentUseCase {
+	return &TaxDocumentUseCase{
+		taxDocumentRepo: taxDocumentRepo,
+		invoiceRepo:     invoiceRepo,
+		paymentRepo:     paymentRepo,
+		companyRepo:     companyRepo,
+		builder:         builder,
+		taxAuthority:    taxAuthority,
+		taxRate:         taxRate,
+		logger:          logger,
+	}
+}
+
+// UploadFolioRange stores a CAF downloaded from the SII. Errors wrapping
+// ErrInvalidCAF say what is wrong with the file.
+func (uc *TaxDocumentUseCase) UploadFolioRange(raw []byte) (*domain.FolioRange, error) {
+	folioRange, err := uc.builder.ParseCAF(raw)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCAF) {
+			return nil, err
+		}
+		uc.logger.Error("Failed to read CAF", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if err := uc.taxDocumentRepo.CreateFolioRange(folioRange); err != nil {
+		if err == domain.ErrAlreadyExists {
+			return nil, err
+		}
+		uc.logger.Error("Failed to create folio range", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Folio range added",
+		zap.Int("type", int(folioRange.Type)),
+		zap.Int("from", folioRange.From),
+		zap.Int("to", folioRange.To))
+	return folioRange, nil
+}
+
+func (uc *TaxDocumentUseCase) ListFolioRanges(docType *domain.DTEType) ([]*domain.FolioRange, error) {
+	ranges, err := uc.taxDocumentRepo.ListFolioRanges(docType)
+	if err != nil {
+		uc.logger.Error("Failed to list folio ranges", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return ranges, nil
+}
+
+// IssueDocument issues the factura of an invoice or the boleta of a
+// captured reservation payment. Invoice payments get no boleta, the invoice
+// is documented instead, and neither do wallet top-ups.
+func (uc *TaxDocumentUseCase) IssueDocument(req domain.IssueTaxDocumentRequest) (*domain.TaxDocument, error) {
+	var doc *domain.TaxDocument
+	var err error
+	if req.InvoiceID != nil {
+		doc, err = uc.invoiceDocument(*req.InvoiceID)
+	} else {
+		doc, err = uc.paymentDocument(*req.PaymentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.issue(doc, nil)
+}
+
+func (uc *TaxDocumentUseCase) invoiceDocument(invoiceID uuid.UUID) (*domain.TaxDocument, error) {
+	invoice, err := uc.invoiceRepo.GetInvoice(invoiceID)
+	if err != nil {
+		if err == domain.ErrInvoiceNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get invoice to document", zap.Error(err), zap.String("invoice_id", invoiceID.String()))
+		return nil, domain.ErrInternalError
+	}
+	if invoice.Status == domain.InvoiceStatusVoid {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	company, err := uc.companyRepo.GetByID(invoice.CompanyID)
+	if err != nil {
+		uc.logger.Error("Failed to get invoiced company", zap.Error(err), zap.String("company_id", invoice.CompanyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return domain.NewInvoiceTaxDocument(invoice, company, time.Now())
+}
+
+func (uc *TaxDocumentUseCase) paymentDocument(paymentID uuid.UUID) (*domain.TaxDocument, error) {
+	payment, err := uc.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		if err == domain.ErrPaymentNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get payment to document", zap.Error(err), zap.String("payment_id", paymentID.String()))
+		return nil, domain.ErrInternalError
+	}
+	if payment.ReservationID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if !payment.IsCaptured() {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	return domain.NewPaymentTaxDocument(payment, uc.taxRate, time.Now())
+}
+
+// CreateCreditNote credits a factura or boleta, in full unless an amount is
+// given. Credit notes of a document cannot add up to more than it.
+func (uc *TaxDocumentUseCase) CreateCreditNote(id uuid.UUID, req domain.CreateCreditNoteRequest) (*domain.TaxDocument, error) {
+	original, err := uc.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+	if original.Type == domain.DTETypeNotaCredito {
+		return nil, domain.ErrInvalidInput
+	}
+	if original.Status == domain.TaxDocumentStatusRejected {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	credited, err := uc.taxDocumentRepo.CreditedAmount(id)
+	if err != nil {
+		uc.logger.Error("Failed to get credited amount", zap.Error(err), zap.String("tax_document_id", id.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	amount := original.TotalAmount - credited
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if amount <= 0 || credited+amount > original.TotalAmount {
+		return nil, domain.ErrInvalidInput
+	}
+
+	doc := domain.NewCreditNote(original, amount, req.Reason, time.Now())
+
+	// Checked again once folios of the type are locked, which serializes
+	// credit notes
+	return uc.issue(doc, func() error {
+		credited, err := uc.taxDocumentRepo.CreditedAmount(id)
+		if err != nil {
+			return err
+		}
+		if credited+doc.TotalAmount > original.TotalAmount {
+			return domain.ErrInvalidInput
+		}
+		return nil
+	})
+}
+
+// issue takes a folio for the document and builds it, after check if given.
+func (uc *TaxDocumentUseCase) issue(doc *domain.TaxDocument, check func() error) (*domain.TaxDocument, error) {
+	err := uc.taxDocumentRepo.Issue(doc, func(doc *domain.TaxDocument, folios *domain.FolioRange) error {
+		if check != nil {
+			if err := check(); err != nil {
+				return err
+			}
+		}
+		return uc.builder.Build(doc, folios)
+	})
+	if err != nil {
+		switch err {
+		case domain.ErrFoliosExhausted, domain.ErrAlreadyExists, domain.ErrInvalidInput:
+			return nil, err
+		}
+		uc.logger.Error("Failed to issue tax document", zap.Error(err), zap.Int("type", int(doc.Type)))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Tax document issued",
+		zap.String("tax_document_id", doc.ID.String()),
+		zap.Int("type", int(doc.Type)),
+		zap.Int("folio", doc.Folio),
+		zap.Int64("total", doc.TotalAmount))
+	return doc, nil
+}
+
+// GetDocument returns the document with its signed XML.
+func (uc *TaxDocumentUseCase) GetDocument(id uuid.UUID) (*domain.TaxDocument, error) {
+	doc, err := uc.taxDocumentRepo.GetByID(id)
+	if err != nil {
+		if err == domain.ErrTaxDocumentNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get tax document", zap.Error(err), zap.String("tax_document_id", id.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	return doc, nil
+}
+
+func (uc *TaxDocumentUseCase) ListDocuments(req domain.ListTaxDocumentsRequest) ([]*domain.TaxDocument, int, error) {
+	docs, total, err := uc.taxDocumentRepo.List(req)
+	if err != nil {
+		uc.logger.Error("Failed to list tax documents", zap.Error(err))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return docs, total, nil
+}
+
+// SubmitDocuments sends the signed documents to the tax authority, boletas
+// in an envelope of their own, and records the outcome of earlier
+// submissions. It is run by the scheduler.
+func (uc *TaxDocumentUseCase) SubmitDocuments(ctx context.Context) error {
+	signed, err := uc.taxDocumentRepo.ListByStatus(domain.TaxDocumentStatusSigned, taxDocumentBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var boletas, others []*domain.TaxDocument
+	for _, doc := range signed {
+		if doc.Type == domain.DTETypeBoleta {
+			boletas = append(boletas, doc)
+		} else {
+			others = append(others, doc)
+		}
+	}
+	for _, docs := range [][]*domain.TaxDocument{others, boletas} {
+		if len(docs) == 0 || ctx.Err() != nil {
+			continue
+		}
+		if err := uc.submit(docs); err != nil {
+			return err
+		}
+	}
+
+	submitted, err := uc.taxDocumentRepo.ListByStatus(domain.TaxDocumentStatusSubmitted, taxDocumentBatchSize)
+	if err != nil {
+		return err
+	}
+
+	checked := make(map[string]bool)
+	for _, doc := range submitted {
+		if ctx.Err() != nil {
+			break
+		}
+		if doc.TrackID == nil || checked[*doc.TrackID] {
+			continue
+		}
+		trackID := *doc.TrackID
+		checked[trackID] = true
+
+		outcome, err := uc.taxAuthority.GetStatus(trackID)
+		if err != nil {
+			uc.logger.Warn("Failed to check tax document submission", zap.Error(err), zap.String("track_id", trackID))
+			continue
+		}
+		if outcome.Status == domain.TaxDocumentStatusSubmitted {
+			continue
+		}
+
+		if err := uc.taxDocumentRepo.UpdateStatus(trackID, outcome.Status, outcome.Detail); err != nil {
+			return err
+		}
+		if outcome.Status == domain.TaxDocumentStatusRejected {
+			uc.logger.Warn("Tax authority rejected submission", zap.String("track_id", trackID), zap.String("detail", outcome.Detail))
+		}
+	}
+
+	return nil
+}
+
+func (uc *TaxDocumentUseCase) submit(docs []*domain.TaxDocument) error {
+	envelope, err := uc.builder.Envelope(docs)
+	if err != nil {
+		return err
+	}
+
+	trackID, err := uc.taxAuthority.Submit(envelope)
+	if err != nil {
+		// Left signed; the next run sends them again
+		uc.logger.Warn("Failed to submit tax documents", zap.Error(err), zap.Int("count", len(docs)))
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	if err := uc.taxDocumentRepo.MarkSubmitted(ids, trackID, time.Now()); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Tax documents submitted", zap.String("track_id", trackID), zap.Int("count", len(docs)))
+	return nil
+}
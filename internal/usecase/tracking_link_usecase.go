@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TrackingLinkUseCase struct {
+	linkRepo        domain.TrackingLinkRepository
+	tokenService    domain.TrackingTokenService
+	reservationRepo domain.ReservationRepository
+	progressRepo    domain.TripProgressRepository
+	driverRepo      domain.DriverRepository
+	locationRepo    domain.DriverLocationRepository
+	linkTTL         time.Duration
+	logger          *zap.Logger
+}
+
+func NewTrackingLinkUseCase(
+	linkRepo domain.TrackingLinkRepository,
+	tokenService domain.TrackingTokenService,
+	reservationRepo domain.ReservationRepository,
+	progressRepo domain.TripProgressRepository,
+	driverRepo domain.DriverRepository,
+	locationRepo domain.DriverLocationRepository,
+	linkTTL time.Duration,
+	logger *zap.Logger,
+) *TrackingLinkUseCase {
+	return &TrackingLinkUseCase{
+		linkRepo:        linkRepo,
+		tokenService:    tokenService,
+		reservationRepo: reservationRepo,
+		progressRepo:    progressRepo,
+		driverRepo:      driverRepo,
+		locationRepo:    locationRepo,
+		linkTTL:         linkTTL,
+		logger:          logger,
+	}
+}
+
+// CreateLink issues a new tracking link for a reservation the viewer can see.
+func (uc *TrackingLinkUseCase) CreateLink(reservationID string, viewer domain.ReservationViewer) (*domain.TrackingLinkResponse, error) {
+	if _, err := uc.getVisibleReservation(reservationID, viewer); err != nil {
+		return nil, err
+	}
+
+	link, token, err := uc.issue(reservationID, &viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TrackingLinkResponse{Link: link, Token: token}, nil
+}
+
+func (uc *TrackingLinkUseCase) ListLinks(reservationID string, viewer domain.ReservationViewer) ([]*domain.TrackingLink, error) {
+	if _, err := uc.getVisibleReservation(reservationID, viewer); err != nil {
+		return nil, err
+	}
+
+	links, err := uc.linkRepo.ListByReservation(reservationID)
+	if err != nil {
+		uc.logger.Error("Failed to list tracking links", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return links, nil
+}
+
+// RevokeLink disables a link immediately, even if its token has not expired.
+func (uc *TrackingLinkUseCase) RevokeLink(reservationID string, linkID uuid.UUID, viewer domain.ReservationViewer) error {
+	if _, err := uc.getVisibleReservation(reservationID, viewer); err != nil {
+		return err
+	}
+
+	link, err := uc.linkRepo.GetByID(linkID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return err
+		}
+		uc.logger.Error("Failed to get tracking link", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if link.ReservationID != reservationID {
+		return domain.ErrNotFound
+	}
+
+	if err := uc.linkRepo.Revoke(linkID, time.Now()); err != nil {
+		if err == domain.ErrNotFound {
+			return nil // Already revoked
+		}
+		uc.logger.Error("Failed to revoke tracking link", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	uc.logger.Info("Tracking link revoked", zap.String("link_id", linkID.String()), zap.String("reservation_id", reservationID))
+	return nil
+}
+
+// IssueTrackingToken implements domain.TrackingLinkIssuer.
+func (uc *TrackingLinkUseCase) IssueTrackingToken(reservationID string, createdBy *uuid.UUID) (string, error) {
+	_, token, err := uc.issue(reservationID, createdBy)
+	return token, err
+}
+
+// GetPublicView resolves a tracking token into the reduced reservation view.
+// Invalid, expired and revoked tokens are all reported as unauthorized.
+func (uc *TrackingLinkUseCase) GetPublicView(token string) (*domain.PublicTrackingView, error) {
+	linkID, err := uc.tokenService.Verify(token)
+	if err != nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	link, err := uc.linkRepo.GetByID(linkID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrUnauthorized
+		}
+		uc.logger.Error("Failed to get tracking link", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if !link.IsActive(time.Now()) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	reservation, err := uc.reservationRepo.GetByID(link.ReservationID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound {
+			return nil, domain.ErrUnauthorized
+		}
+		uc.logger.Error("Failed to get reservation for tracking link", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	view := &domain.PublicTrackingView{
+		ReservationID: reservation.ID,
+		Status:        reservation.Status,
+		ScheduledAt:   reservation.DateTime,
+		Timeline:      []domain.PublicTimelineRow{},
+	}
+
+	var progress *domain.TripProgress
+	if p, err := uc.progressRepo.GetByReservationID(reservation.ID); err == nil {
+		progress = p
+		view.TripStatus = p.Status
+	} else if err != domain.ErrNotFound {
+		uc.logger.Warn("Failed to get trip progress for tracking link", zap.Error(err))
+	}
+
+	if reservation.Status != domain.ReservationStatusCancelada && reservation.Status != domain.ReservationStatusCompletada {
+		view.ETA = domain.EstimateETA(reservation, progress, uc.pathSinceBoarding(reservation.ID, progress))
+	}
+
+	if reservation.AssignedDriver != nil {
+		firstName := reservation.AssignedDriver.FirstName
+		view.DriverFirstName = &firstName
+
+		if vehicle, err := uc.driverRepo.GetDriverVehicle(reservation.AssignedDriver.ID); err == nil {
+			view.Vehicle = &domain.PublicVehicleView{
+				Brand: vehicle.Brand,
+				Model: vehicle.Model,
+				Color: vehicle.Color,
+				Plate: vehicle.Plate,
+			}
+		} else if err != domain.ErrNotFound {
+			uc.logger.Warn("Failed to get driver vehicle for tracking link", zap.Error(err))
+		}
+	}
+
+	timeline, err := uc.reservationRepo.GetTimeline(reservation.ID)
+	if err != nil {
+		uc.logger.Warn("Failed to get timeline for tracking link", zap.Error(err))
+	}
+	view.Timeline = domain.NewPublicTimeline(timeline)
+
+	return view, nil
+}
+
+// pathSinceBoarding returns the driver's pings since the passenger boarded,
+// or none before boarding.
+func (uc *TrackingLinkUseCase) pathSinceBoarding(reservationID string, progress *domain.TripProgress) []*domain.DriverLocation {
+	if progress == nil || progress.OnboardAt == nil {
+		return nil
+	}
+
+	path, err := uc.locationRepo.ListByReservationSince(reservationID, *progress.OnboardAt)
+	if err != nil {
+		uc.logger.Warn("Failed to get driver path for tracking link", zap.Error(err))
+		return nil
+	}
+	return path
+}
+
+func (uc *TrackingLinkUseCase) issue(reservationID string, createdBy *uuid.UUID) (*domain.TrackingLink, string, error) {
+	link := &domain.TrackingLink{
+		ID:            uuid.New(),
+		ReservationID: reservationID,
+		ExpiresAt:     time.Now().Add(uc.linkTTL),
+		CreatedBy:     createdBy,
+	}
+
+	if err := uc.linkRepo.Create(link); err != nil {
+		uc.logger.Error("Failed to create tracking link", zap.Error(err))
+		return nil, "", domain.ErrInternalError
+	}
+
+	token, err := uc.tokenService.Sign(link)
+	if err != nil {
+		uc.logger.Error("Failed to sign tracking link", zap.Error(err))
+		return nil, "", domain.ErrInternalError
+	}
+
+	return link, token, nil
+}
+
+func (uc *TrackingLinkUseCase) getVisibleReservation(reservationID string, viewer domain.ReservationViewer) (*domain.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for tracking link", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if !reservation.IsVisibleTo(viewer) {
+		return nil, domain.ErrForbidden
+	}
+
+	return reservation, nil
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+const (
+	// maxPingClockSkew tolerates device clocks running slightly ahead.
+	maxPingClockSkew = time.Minute
+	// pruneBatchSize bounds each DELETE issued by the retention job.
+	pruneBatchSize = 5000
+)
+
+type TrackingUseCase struct {
+	locationRepo    domain.DriverLocationRepository
+	progressRepo    domain.TripProgressRepository
+	reservationRepo domain.ReservationRepository
+	broker          domain.LocationBroker
+	retention       time.Duration
+	logger          *zap.Logger
+}
+
+func NewTrackingUseCase(
+	locationRepo domain.DriverLocationRepository,
+	progressRepo domain.TripProgressRepository,
+	reservationRepo domain.ReservationRepository,
+	broker domain.LocationBroker,
+	retention time.Duration,
+	logger *zap.Logger,
+) *TrackingUseCase {
+	return &TrackingUseCase{
+		locationRepo:    locationRepo,
+		progressRepo:    progressRepo,
+		reservationRepo: reservationRepo,
+		broker:          broker,
+		retention:       retention,
+		logger:          logger,
+	}
+}
+
+// RecordLocations stores a batch of pings for the driver. Pings are linked to
+// the trip the driver is currently working on; pings outside the retention
+// window or too far in the future are rejected.
+func (uc *TrackingUseCase) RecordLocations(driverID string, req domain.RecordLocationsRequest) (*domain.RecordLocationsResponse, error) {
+	var reservationID *string
+	active, err := uc.progressRepo.GetActiveByDriver(driverID)
+	if err != nil && err != domain.ErrNotFound {
+		uc.logger.Error("Failed to get active trip for locations", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if active != nil {
+		reservationID = &active.ReservationID
+	}
+
+	now := time.Now()
+	oldest := now.Add(-uc.retention)
+	newest := now.Add(maxPingClockSkew)
+
+	locations := make([]*domain.DriverLocation, 0, len(req.Pings))
+	for _, ping := range req.Pings {
+		if ping.RecordedAt.Before(oldest) || ping.RecordedAt.After(newest) {
+			continue
+		}
+		locations = append(locations, &domain.DriverLocation{
+			DriverID:      driverID,
+			ReservationID: reservationID,
+			Latitude:      ping.Latitude,
+			Longitude:     ping.Longitude,
+			Accuracy:      ping.Accuracy,
+			Speed:         ping.Speed,
+			Heading:       ping.Heading,
+			RecordedAt:    ping.RecordedAt,
+		})
+	}
+
+	if err := uc.locationRepo.CreateBatch(locations); err != nil {
+		uc.logger.Error("Failed to record driver locations", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	// Only the most recent ping matters to live viewers.
+	if latest := latestLocation(locations); latest != nil {
+		uc.broker.Publish(latest)
+	}
+
+	return &domain.RecordLocationsResponse{
+		Accepted: len(locations),
+		Rejected: len(req.Pings) - len(locations),
+	}, nil
+}
+
+// GetReservationLocation returns the last known position of the driver
+// assigned to the reservation.
+func (uc *TrackingUseCase) GetReservationLocation(reservationID string, viewer domain.ReservationViewer) (*domain.DriverLocation, error) {
+	if _, err := uc.getTrackableReservation(reservationID, viewer); err != nil {
+		return nil, err
+	}
+
+	location, err := uc.locationRepo.GetLatestByReservation(reservationID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get reservation location", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return location, nil
+}
+
+// SubscribeReservation opens a live feed of driver locations for the
+// reservation. The last known location, if any, is returned so the stream can
+// start with it.
+func (uc *TrackingUseCase) SubscribeReservation(reservationID string, viewer domain.ReservationViewer) (*domain.DriverLocation, <-chan *domain.DriverLocation, func(), error) {
+	if _, err := uc.getTrackableReservation(reservationID, viewer); err != nil {
+		return nil, nil, nil, err
+	}
+
+	updates, unsubscribe := uc.broker.Subscribe(reservationID)
+
+	latest, err := uc.locationRepo.GetLatestByReservation(reservationID)
+	if err != nil && err != domain.ErrNotFound {
+		unsubscribe()
+		uc.logger.Error("Failed to get reservation location", zap.Error(err))
+		return nil, nil, nil, domain.ErrInternalError
+	}
+
+	return latest, updates, unsubscribe, nil
+}
+
+// PruneLocations enforces the retention window. It is run by the scheduler.
+func (uc *TrackingUseCase) PruneLocations(ctx context.Context) error {
+	cutoff := time.Now().Add(-uc.retention)
+
+	var total int64
+	for {
+		deleted, err := uc.locationRepo.DeleteOlderThan(cutoff, pruneBatchSize)
+		if err != nil {
+			return err
+		}
+		total += deleted
+		if deleted < pruneBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		uc.logger.Info("Pruned driver locations", zap.Int64("deleted", total), zap.Time("cutoff", cutoff))
+	}
+	return nil
+}
+
+func (uc *TrackingUseCase) getTrackableReservation(reservationID string, viewer domain.ReservationViewer) (*domain.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for tracking", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if !reservation.IsVisibleTo(viewer) {
+		return nil, domain.ErrForbidden
+	}
+
+	if reservation.AssignedDriverID == nil {
+		return nil, domain.ErrDriverNotAvailable
+	}
+
+	return reservation, nil
+}
+
+func latestLocation(locations []*domain.DriverLocation) *domain.DriverLocation {
+	var latest *domain.DriverLocation
+	for _, location := range locations {
+		if latest == nil || location.RecordedAt.After(latest.RecordedAt) {
+			latest = location
+		}
+	}
+	return latest
+}
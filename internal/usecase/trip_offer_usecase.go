@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TripOfferUseCase struct {
+	offerRepo       domain.TripOfferRepository
+	reservationRepo domain.ReservationRepository
+	driverRepo      domain.DriverRepository
+	compliance      domain.ComplianceChecker
+	shifts          domain.ShiftChecker
+	maintenance     domain.MaintenanceChecker
+	capacity        domain.CapacityChecker
+	offerTimeout    time.Duration
+	logger          *zap.Logger
+}
+
+func NewTripOfferUseCase(
+	offerRepo domain.TripOfferRepository,
+	reservationRepo domain.ReservationRepository,
+	driverRepo domain.DriverRepository,
+	compliance domain.ComplianceChecker,
+	shifts domain.ShiftChecker,
+	maintenance domain.MaintenanceChecker,
+	capacity domain.CapacityChecker,
+	offerTimeout time.Duration,
+	logger *zap.Logger,
+) *TripOfferUseCase {
+	return &TripOfferUseCase{
+		offerRepo:       offerRepo,
+		reservationRepo: reservationRepo,
+		driverRepo:      driverRepo,
+		compliance:      compliance,
+		shifts:          shifts,
+		maintenance:     maintenance,
+		capacity:        capacity,
+		offerTimeout:    offerTimeout,
+		logger:          logger,
+	}
+}
+
+// DispatchOffers queues one offer per candidate driver, in the given order, and
+// sends the first one immediately. The remaining candidates are offered in turn
+// as previous offers are declined or expire.
+func (uc *TripOfferUseCase) DispatchOffers(reservationID string, req domain.DispatchOffersRequest) ([]*domain.TripOffer, error) {
+	uc.logger.Info("Dispatching trip offers",
+		zap.String("reservation_id", reservationID),
+		zap.Strings("driver_ids", req.DriverIDs))
+
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for dispatch", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if reservation.Status == domain.ReservationStatusCompletada || reservation.Status == domain.ReservationStatusCancelada {
+		return nil, domain.ErrInvalidInput
+	}
+
+	existing, err := uc.offerRepo.ListByReservation(reservationID)
+	if err != nil {
+		uc.logger.Error("Failed to check existing offers", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	for _, offer := range existing {
+		if offer.IsOpen() {
+			return nil, domain.ErrDispatchInProgress
+		}
+	}
+
+	// Validate candidates and drop duplicates while keeping priority order
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, driverID := range req.DriverIDs {
+		if seen[driverID] {
+			continue
+		}
+		seen[driverID] = true
+
+		driver, err := uc.driverRepo.GetByID(driverID)
+		if err != nil {
+			if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+				return nil, domain.ErrDriverNotFound
+			}
+			uc.logger.Error("Failed to get driver for dispatch", zap.Error(err))
+			return nil, domain.ErrInternalError
+		}
+		if driver.Status != domain.DriverStatusActive {
+			return nil, domain.ErrDriverInactive
+		}
+		if err := uc.compliance.EnsureDriverEligible(driverID); err != nil {
+			return nil, err
+		}
+		if err := uc.shifts.EnsureDriverOnShift(driverID, reservation.DateTime); err != nil {
+			return nil, err
+		}
+		if err := uc.maintenance.EnsureDriverVehicleInService(driverID, reservation.DateTime); err != nil {
+			return nil, err
+		}
+		if err := uc.capacity.EnsureDriverVehicleFits(driverID, reservation.VehicleType, reservation.Passengers); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, driverID)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(uc.offerTimeout)
+	offers := make([]*domain.TripOffer, len(candidates))
+	for i, driverID := range candidates {
+		offer := &domain.TripOffer{
+			ID:            uuid.New(),
+			ReservationID: reservationID,
+			DriverID:      driverID,
+			Position:      i + 1,
+			Status:        domain.TripOfferStatusQueued,
+		}
+		if i == 0 {
+			offer.Status = domain.TripOfferStatusPending
+			offer.OfferedAt = &now
+			offer.ExpiresAt = &expiresAt
+		}
+		offers[i] = offer
+	}
+
+	if err := uc.offerRepo.CreateBatch(offers); err != nil {
+		uc.logger.Error("Failed to create trip offers", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.addTimelineEvent(reservationID, "Oferta enviada",
+		fmt.Sprintf("Oferta enviada al conductor %s (vence %s)", offers[0].DriverID, expiresAt.Format("15:04")),
+		"info")
+
+	uc.logger.Info("Trip offers dispatched",
+		zap.String("reservation_id", reservationID),
+		zap.Int("candidates", len(offers)))
+
+	return offers, nil
+}
+
+func (uc *TripOfferUseCase) GetReservationOffers(reservationID string) ([]*domain.TripOffer, error) {
+	if _, err := uc.reservationRepo.GetByID(reservationID); err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrReservationNotFound
+		}
+		uc.logger.Error("Failed to get reservation for offers", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	offers, err := uc.offerRepo.ListByReservation(reservationID)
+	if err != nil {
+		uc.logger.Error("Failed to list reservation offers", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return offers, nil
+}
+
+func (uc *TripOfferUseCase) GetDriverOffers(driverID string, status *domain.TripOfferStatus) ([]*domain.TripOffer, error) {
+	offers, err := uc.offerRepo.ListByDriver(driverID, status)
+	if err != nil {
+		uc.logger.Error("Failed to list driver offers", zap.Error(err), zap.String("driver_id", driverID))
+		return nil, domain.ErrInternalError
+	}
+
+	return offers, nil
+}
+
+// AcceptOffer assigns the reservation to the driver and closes every other
+// offer for the same reservation.
+func (uc *TripOfferUseCase) AcceptOffer(driverID string, offerID uuid.UUID) (*domain.TripOffer, error) {
+	uc.logger.Info("Driver accepting offer", zap.String("driver_id", driverID), zap.String("offer_id", offerID.String()))
+
+	offer, err := uc.getDriverOffer(driverID, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if offer.Status == domain.TripOfferStatusPending && offer.ExpiresAt != nil && now.After(*offer.ExpiresAt) {
+		uc.expireOffer(offer, now)
+		return nil, domain.ErrOfferNotPending
+	}
+
+	reservation, err := uc.reservationRepo.GetByID(offer.ReservationID)
+	if err != nil {
+		uc.logger.Error("Failed to get reservation for offer acceptance", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if reservation.Status == domain.ReservationStatusCompletada || reservation.Status == domain.ReservationStatusCancelada {
+		if err := uc.offerRepo.CancelOpenByReservation(offer.ReservationID); err != nil {
+			uc.logger.Warn("Failed to cancel offers for closed reservation", zap.Error(err))
+		}
+		return nil, domain.ErrOfferNotPending
+	}
+
+	// Documents may have expired since the offer was dispatched
+	if err := uc.compliance.EnsureDriverEligible(driverID); err != nil {
+		var complianceErr *domain.ComplianceError
+		if errors.As(err, &complianceErr) {
+			uc.declineNonCompliant(offer, now)
+		}
+		return nil, err
+	}
+
+	if err := uc.offerRepo.Accept(offer, now); err != nil {
+		if err == domain.ErrOfferNotPending {
+			return nil, err
+		}
+		uc.logger.Error("Failed to accept offer", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	driverName := driverID
+	if driver, err := uc.driverRepo.GetByID(driverID); err == nil {
+		driverName = fmt.Sprintf("%s %s", driver.FirstName, driver.LastName)
+	}
+	uc.addTimelineEvent(offer.ReservationID, "Conductor asignado",
+		fmt.Sprintf("Conductor %s aceptó la oferta y fue asignado a la reserva", driverName),
+		"primary")
+
+	offer.Status = domain.TripOfferStatusAccepted
+	offer.RespondedAt = &now
+
+	uc.logger.Info("Offer accepted", zap.String("offer_id", offerID.String()), zap.String("reservation_id", offer.ReservationID))
+	return offer, nil
+}
+
+// DeclineOffer records the refusal and moves on to the next candidate.
+func (uc *TripOfferUseCase) DeclineOffer(driverID string, offerID uuid.UUID, req domain.DeclineOfferRequest) (*domain.TripOffer, error) {
+	uc.logger.Info("Driver declining offer", zap.String("driver_id", driverID), zap.String("offer_id", offerID.String()))
+
+	offer, err := uc.getDriverOffer(driverID, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := uc.offerRepo.Respond(offer.ID, domain.TripOfferStatusDeclined, now, req.Reason); err != nil {
+		if err == domain.ErrOfferNotPending {
+			return nil, err
+		}
+		uc.logger.Error("Failed to decline offer", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	description := fmt.Sprintf("El conductor %s rechazó la oferta", driverID)
+	if req.Reason != nil && *req.Reason != "" {
+		description += ": " + *req.Reason
+	}
+	uc.addTimelineEvent(offer.ReservationID, "Oferta rechazada", description, "warning")
+
+	uc.offerNext(offer.ReservationID, now)
+
+	offer.Status = domain.TripOfferStatusDeclined
+	offer.RespondedAt = &now
+	offer.DeclineReason = req.Reason
+
+	return offer, nil
+}
+
+// ExpireOffers closes every PENDING offer past its deadline and cascades to the
+// next candidate. It is meant to be run periodically by the scheduler.
+func (uc *TripOfferUseCase) ExpireOffers(ctx context.Context) error {
+	now := time.Now()
+
+	offers, err := uc.offerRepo.ListExpired(now)
+	if err != nil {
+		return fmt.Errorf("failed to list expired offers: %w", err)
+	}
+
+	for _, offer := range offers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		uc.expireOffer(offer, now)
+	}
+
+	if len(offers) > 0 {
+		uc.logger.Info("Expired trip offers", zap.Int("count", len(offers)))
+	}
+	return nil
+}
+
+func (uc *TripOfferUseCase) expireOffer(offer *domain.TripOffer, now time.Time) {
+	if err := uc.offerRepo.Respond(offer.ID, domain.TripOfferStatusExpired, now, nil); err != nil {
+		if err != domain.ErrOfferNotPending {
+			uc.logger.Error("Failed to expire offer", zap.Error(err), zap.String("offer_id", offer.ID.String()))
+		}
+		return
+	}
+
+	uc.addTimelineEvent(offer.ReservationID, "Oferta expirada",
+		fmt.Sprintf("El conductor %s no respondió a tiempo", offer.DriverID),
+		"warning")
+
+	uc.offerNext(offer.ReservationID, now)
+}
+
+// declineNonCompliant closes an offer whose driver is no longer eligible and
+// moves on to the next candidate.
+func (uc *TripOfferUseCase) declineNonCompliant(offer *domain.TripOffer, now time.Time) {
+	reason := "Documentación del conductor no vigente"
+	if err := uc.offerRepo.Respond(offer.ID, domain.TripOfferStatusDeclined, now, &reason); err != nil {
+		if err != domain.ErrOfferNotPending {
+			uc.logger.Error("Failed to decline non-compliant offer", zap.Error(err), zap.String("offer_id", offer.ID.String()))
+		}
+		return
+	}
+
+	uc.addTimelineEvent(offer.ReservationID, "Oferta rechazada",
+		fmt.Sprintf("El conductor %s no cumple los requisitos de documentación", offer.DriverID),
+		"warning")
+
+	uc.offerNext(offer.ReservationID, now)
+}
+
+// offerNext promotes the next queued candidate, if any.
+func (uc *TripOfferUseCase) offerNext(reservationID string, now time.Time) {
+	reservation, err := uc.reservationRepo.GetByID(reservationID)
+	if err != nil {
+		uc.logger.Error("Failed to get reservation for next offer", zap.Error(err))
+		return
+	}
+	if reservation.Status == domain.ReservationStatusCompletada || reservation.Status == domain.ReservationStatusCancelada {
+		if err := uc.offerRepo.CancelOpenByReservation(reservationID); err != nil {
+			uc.logger.Warn("Failed to cancel offers for closed reservation", zap.Error(err))
+		}
+		return
+	}
+
+	next, err := uc.offerRepo.GetNextQueued(reservationID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			uc.addTimelineEvent(reservationID, "Sin conductores disponibles",
+				"Ningún conductor aceptó la oferta. Se requiere asignación manual", "error")
+			return
+		}
+		uc.logger.Error("Failed to get next queued offer", zap.Error(err))
+		return
+	}
+
+	expiresAt := now.Add(uc.offerTimeout)
+	if err := uc.offerRepo.Activate(next.ID, now, expiresAt); err != nil {
+		uc.logger.Error("Failed to activate next offer", zap.Error(err), zap.String("offer_id", next.ID.String()))
+		return
+	}
+
+	uc.addTimelineEvent(reservationID, "Oferta enviada",
+		fmt.Sprintf("Oferta enviada al conductor %s (vence %s)", next.DriverID, expiresAt.Format("15:04")),
+		"info")
+}
+
+func (uc *TripOfferUseCase) getDriverOffer(driverID string, offerID uuid.UUID) (*domain.TripOffer, error) {
+	offer, err := uc.offerRepo.GetByID(offerID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrOfferNotFound
+		}
+		uc.logger.Error("Failed to get offer", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	// Offers belonging to other drivers are reported as missing
+	if offer.DriverID != driverID || offer.Status == domain.TripOfferStatusQueued {
+		return nil, domain.ErrOfferNotFound
+	}
+
+	if offer.Status != domain.TripOfferStatusPending {
+		return nil, domain.ErrOfferNotPending
+	}
+
+	return offer, nil
+}
+
+func (uc *TripOfferUseCase) addTimelineEvent(reservationID, title, description, variant string) {
+	event := domain.TimelineEvent{
+		ReservationID: reservationID,
+		Title:         title,
+		Description:   description,
+		At:            time.Now(),
+		Variant:       variant,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := uc.reservationRepo.AddTimelineEvent(reservationID, event); err != nil {
+		uc.logger.Warn("Failed to add offer timeline event", zap.Error(err), zap.String("reservation_id", reservationID))
+	}
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type MockTripOfferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTripOfferRepository) CreateBatch(offers []*domain.TripOffer) error {
+	args := m.Called(offers)
+	return args.Error(0)
+}
+
+func (m *MockTripOfferRepository) GetByID(id uuid.UUID) (*domain.TripOffer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TripOffer), args.Error(1)
+}
+
+func (m *MockTripOfferRepository) ListByReservation(reservationID string) ([]*domain.TripOffer, error) {
+	args := m.Called(reservationID)
+	return args.Get(0).([]*domain.TripOffer), args.Error(1)
+}
+
+func (m *MockTripOfferRepository) ListByDriver(driverID string, status *domain.TripOfferStatus) ([]*domain.TripOffer, error) {
+	args := m.Called(driverID, status)
+	return args.Get(0).([]*domain.TripOffer), args.Error(1)
+}
+
+func (m *MockTripOfferRepository) ListExpired(now time.Time) ([]*domain.TripOffer, error) {
+	args := m.Called(now)
+	return args.Get(0).([]*domain.TripOffer), args.Error(1)
+}
+
+func (m *MockTripOfferRepository) GetNextQueued(reservationID string) (*domain.TripOffer, error) {
+	args := m.Called(reservationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TripOffer), args.Error(1)
+}
+
+func (m *MockTripOfferRepository) Activate(id uuid.UUID, offeredAt, expiresAt time.Time) error {
+	args := m.Called(id, offeredAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTripOfferRepository) Respond(id uuid.UUID, status domain.TripOfferStatus, respondedAt time.Time, reason *string) error {
+	args := m.Called(id, status, respondedAt, reason)
+	return args.Error(0)
+}
+
+func (m *MockTripOfferRepository) Accept(offer *domain.TripOffer, respondedAt time.Time) error {
+	args := m.Called(offer, respondedAt)
+	return args.Error(0)
+}
+
+func (m *MockTripOfferRepository) CancelOpenByReservation(reservationID string) error {
+	args := m.Called(reservationID)
+	return args.Error(0)
+}
+
+// MockReservationRepository implements the reservation lookups and timeline
+// the use cases under test touch; any other call panics.
+type MockReservationRepository struct {
+	domain.ReservationRepository
+	mock.Mock
+}
+
+func (m *MockReservationRepository) GetByID(id string) (*domain.Reservation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ChangeStatus(id string, newStatus domain.ReservationStatus) error {
+	args := m.Called(id, newStatus)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) AddTimelineEvent(id string, event domain.TimelineEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
+
+// MockDriverRepository implements the driver lookup only.
+type MockDriverRepository struct {
+	domain.DriverRepository
+	mock.Mock
+}
+
+func (m *MockDriverRepository) GetByID(id string) (*domain.Driver, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Driver), args.Error(1)
+}
+
+type MockComplianceChecker struct {
+	mock.Mock
+}
+
+func (m *MockComplianceChecker) EnsureDriverEligible(driverID string) error {
+	args := m.Called(driverID)
+	return args.Error(0)
+}
+
+func (m *MockComplianceChecker) EnsureVehicleAssignable(vehicleID, driverID string) error {
+	args := m.Called(vehicleID, driverID)
+	return args.Error(0)
+}
+
+const offerTimeout = 2 * time.Minute
+
+func newTripOfferUseCaseForTest() (*TripOfferUseCase, *MockTripOfferRepository, *MockReservationRepository, *MockDriverRepository, *MockComplianceChecker) {
+	offerRepo := new(MockTripOfferRepository)
+	reservationRepo := new(MockReservationRepository)
+	driverRepo := new(MockDriverRepository)
+	compliance := new(MockComplianceChecker)
+
+	reservationRepo.On("AddTimelineEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	useCase := NewTripOfferUseCase(offerRepo, reservationRepo, driverRepo, compliance, nil, nil, nil, offerTimeout, zap.NewNop())
+	return useCase, offerRepo, reservationRepo, driverRepo, compliance
+}
+
+func pendingOffer(driverID string, expiresAt time.Time) *domain.TripOffer {
+	return &domain.TripOffer{
+		ID:            uuid.New(),
+		ReservationID: "RES-001",
+		DriverID:      driverID,
+		Position:      1,
+		Status:        domain.TripOfferStatusPending,
+		ExpiresAt:     &expiresAt,
+	}
+}
+
+func TestTripOfferUseCase_AcceptOffer(t *testing.T) {
+	t.Run("should accept the offer and assign the driver", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, driverRepo, compliance := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		compliance.On("EnsureDriverEligible", "DRV-001").Return(nil)
+		offerRepo.On("Accept", offer, mock.AnythingOfType("time.Time")).Return(nil)
+		driverRepo.On("GetByID", "DRV-001").Return(&domain.Driver{FirstName: "Ana", LastName: "Rojas"}, nil)
+
+		accepted, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.TripOfferStatusAccepted, accepted.Status)
+		assert.NotNil(t, accepted.RespondedAt)
+		offerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not accept when the assignment fails", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, compliance := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		compliance.On("EnsureDriverEligible", "DRV-001").Return(nil)
+		offerRepo.On("Accept", offer, mock.AnythingOfType("time.Time")).Return(domain.ErrReservationNotFound)
+
+		accepted, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.Nil(t, accepted)
+		assert.Equal(t, domain.ErrInternalError, err)
+		assert.Equal(t, domain.TripOfferStatusPending, offer.Status)
+	})
+
+	t.Run("should lose the race to a concurrent response", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, compliance := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		compliance.On("EnsureDriverEligible", "DRV-001").Return(nil)
+		offerRepo.On("Accept", offer, mock.AnythingOfType("time.Time")).Return(domain.ErrOfferNotPending)
+
+		_, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.Equal(t, domain.ErrOfferNotPending, err)
+	})
+
+	t.Run("should expire a late acceptance and offer the next driver", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(-time.Second))
+		next := &domain.TripOffer{ID: uuid.New(), ReservationID: "RES-001", DriverID: "DRV-002", Position: 2, Status: domain.TripOfferStatusQueued}
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		offerRepo.On("Respond", offer.ID, domain.TripOfferStatusExpired, mock.AnythingOfType("time.Time"), (*string)(nil)).Return(nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		offerRepo.On("GetNextQueued", "RES-001").Return(next, nil)
+		offerRepo.On("Activate", next.ID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil)
+
+		_, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.Equal(t, domain.ErrOfferNotPending, err)
+		offerRepo.AssertExpectations(t)
+		offerRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should decline a non-compliant driver and cascade", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, compliance := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+		complianceErr := &domain.ComplianceError{}
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		compliance.On("EnsureDriverEligible", "DRV-001").Return(complianceErr)
+		offerRepo.On("Respond", offer.ID, domain.TripOfferStatusDeclined, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*string")).Return(nil)
+		offerRepo.On("GetNextQueued", "RES-001").Return(nil, domain.ErrNotFound)
+
+		_, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.True(t, errors.As(err, &complianceErr))
+		offerRepo.AssertExpectations(t)
+		offerRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should cancel open offers of a closed reservation", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusCancelada}, nil)
+		offerRepo.On("CancelOpenByReservation", "RES-001").Return(nil)
+
+		_, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.Equal(t, domain.ErrOfferNotPending, err)
+		offerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should hide offers of other drivers", func(t *testing.T) {
+		useCase, offerRepo, _, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-002", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+
+		_, err := useCase.AcceptOffer("DRV-001", offer.ID)
+
+		assert.Equal(t, domain.ErrOfferNotFound, err)
+	})
+}
+
+func TestTripOfferUseCase_DeclineOffer(t *testing.T) {
+	t.Run("should decline and offer the next driver", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+		next := &domain.TripOffer{ID: uuid.New(), ReservationID: "RES-001", DriverID: "DRV-002", Position: 2, Status: domain.TripOfferStatusQueued}
+		reason := "Fuera de mi zona"
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		offerRepo.On("Respond", offer.ID, domain.TripOfferStatusDeclined, mock.AnythingOfType("time.Time"), &reason).Return(nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		offerRepo.On("GetNextQueued", "RES-001").Return(next, nil)
+		offerRepo.On("Activate", next.ID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) {
+				offeredAt := args.Get(1).(time.Time)
+				expiresAt := args.Get(2).(time.Time)
+				assert.Equal(t, offerTimeout, expiresAt.Sub(offeredAt))
+			}).
+			Return(nil)
+
+		declined, err := useCase.DeclineOffer("DRV-001", offer.ID, domain.DeclineOfferRequest{Reason: &reason})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.TripOfferStatusDeclined, declined.Status)
+		assert.Equal(t, &reason, declined.DeclineReason)
+		offerRepo.AssertExpectations(t)
+	})
+
+	t.Run("should ask for manual assignment when no driver is left", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+		offerRepo.On("Respond", offer.ID, domain.TripOfferStatusDeclined, mock.AnythingOfType("time.Time"), (*string)(nil)).Return(nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		offerRepo.On("GetNextQueued", "RES-001").Return(nil, domain.ErrNotFound)
+
+		_, err := useCase.DeclineOffer("DRV-001", offer.ID, domain.DeclineOfferRequest{})
+
+		require.NoError(t, err)
+		reservationRepo.AssertCalled(t, "AddTimelineEvent", "RES-001", mock.MatchedBy(func(event domain.TimelineEvent) bool {
+			return event.Title == "Sin conductores disponibles"
+		}))
+		offerRepo.AssertNotCalled(t, "Activate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not decline an answered offer", func(t *testing.T) {
+		useCase, offerRepo, _, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(time.Minute))
+		offer.Status = domain.TripOfferStatusAccepted
+
+		offerRepo.On("GetByID", offer.ID).Return(offer, nil)
+
+		_, err := useCase.DeclineOffer("DRV-001", offer.ID, domain.DeclineOfferRequest{})
+
+		assert.Equal(t, domain.ErrOfferNotPending, err)
+		offerRepo.AssertNotCalled(t, "Respond", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripOfferUseCase_ExpireOffers(t *testing.T) {
+	t.Run("should expire each offer and cascade", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		first := pendingOffer("DRV-001", time.Now().Add(-time.Minute))
+		raced := pendingOffer("DRV-003", time.Now().Add(-time.Minute))
+		raced.ReservationID = "RES-002"
+		next := &domain.TripOffer{ID: uuid.New(), ReservationID: "RES-001", DriverID: "DRV-002", Position: 2, Status: domain.TripOfferStatusQueued}
+
+		offerRepo.On("ListExpired", mock.AnythingOfType("time.Time")).Return([]*domain.TripOffer{first, raced}, nil)
+		offerRepo.On("Respond", first.ID, domain.TripOfferStatusExpired, mock.AnythingOfType("time.Time"), (*string)(nil)).Return(nil)
+		offerRepo.On("Respond", raced.ID, domain.TripOfferStatusExpired, mock.AnythingOfType("time.Time"), (*string)(nil)).Return(domain.ErrOfferNotPending)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusActiva}, nil)
+		offerRepo.On("GetNextQueued", "RES-001").Return(next, nil)
+		offerRepo.On("Activate", next.ID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := useCase.ExpireOffers(context.Background())
+
+		require.NoError(t, err)
+		offerRepo.AssertExpectations(t)
+		// The offer answered in the meantime does not cascade
+		offerRepo.AssertNotCalled(t, "GetNextQueued", "RES-002")
+	})
+
+	t.Run("should cancel the queue of a closed reservation", func(t *testing.T) {
+		useCase, offerRepo, reservationRepo, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(-time.Minute))
+
+		offerRepo.On("ListExpired", mock.AnythingOfType("time.Time")).Return([]*domain.TripOffer{offer}, nil)
+		offerRepo.On("Respond", offer.ID, domain.TripOfferStatusExpired, mock.AnythingOfType("time.Time"), (*string)(nil)).Return(nil)
+		reservationRepo.On("GetByID", "RES-001").Return(&domain.Reservation{ID: "RES-001", Status: domain.ReservationStatusCompletada}, nil)
+		offerRepo.On("CancelOpenByReservation", "RES-001").Return(nil)
+
+		err := useCase.ExpireOffers(context.Background())
+
+		require.NoError(t, err)
+		offerRepo.AssertExpectations(t)
+		offerRepo.AssertNotCalled(t, "GetNextQueued", mock.Anything)
+	})
+
+	t.Run("should stop when the context is done", func(t *testing.T) {
+		useCase, offerRepo, _, _, _ := newTripOfferUseCaseForTest()
+		offer := pendingOffer("DRV-001", time.Now().Add(-time.Minute))
+
+		offerRepo.On("ListExpired", mock.AnythingOfType("time.Time")).Return([]*domain.TripOffer{offer}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := useCase.ExpireOffers(ctx)
+
+		assert.Equal(t, context.Canceled, err)
+		offerRepo.AssertNotCalled(t, "Respond", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
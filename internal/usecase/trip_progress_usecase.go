@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TripProgressUseCase struct {
+	progressRepo    domain.TripProgressRepository
+	reservationRepo domain.ReservationRepository
+	logger          *zap.Logger
+}
+
+func NewTripProgressUseCase(
+	progressRepo domain.TripProgressRepository,
+	reservationRepo domain.ReservationRepository,
+	logger *zap.Logger,
+) *TripProgressUseCase {
+	return &TripProgressUseCase{
+		progressRepo:    progressRepo,
+		reservationRepo: reservationRepo,
+		logger:          logger,
+	}
+}
+
+// GetTripProgress returns the lifecycle of a trip assigned to the driver. A trip
+// that has not been started yet is returned without a status.
+func (uc *TripProgressUseCase) GetTripProgress(driverID, tripID string) (*domain.TripProgress, error) {
+	if _, err := uc.getDriverTrip(driverID, tripID); err != nil {
+		return nil, err
+	}
+
+	return uc.loadProgress(driverID, tripID)
+}
+
+// UpdateTripStatus advances the driver-side lifecycle of a trip, stamps the
+// step and keeps the reservation status and timeline in sync.
+func (uc *TripProgressUseCase) UpdateTripStatus(driverID, tripID string, status domain.DriverTripStatus) (*domain.TripProgress, error) {
+	uc.logger.Info("Updating trip status",
+		zap.String("driver_id", driverID),
+		zap.String("trip_id", tripID),
+		zap.String("status", string(status)))
+
+	reservation, err := uc.getDriverTrip(driverID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A closed reservation only accepts the step that closed it, retried after
+	// its progress failed to save.
+	if reservation.Status == domain.ReservationStatusCompletada || reservation.Status == domain.ReservationStatusCancelada {
+		if target, ok := reservationStatusFor(status); !ok || target != reservation.Status {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+	}
+
+	progress, err := uc.loadProgress(driverID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !progress.CanTransitionTo(status) {
+		uc.logger.Warn("Invalid trip status transition",
+			zap.String("trip_id", tripID),
+			zap.String("to", string(status)))
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	progress.Apply(status, now, reservation.DateTime)
+
+	// The reservation is synced first: if saving the progress then fails the
+	// driver retries the same step, and the sync is a no-op the second time.
+	if err := uc.syncReservationStatus(reservation, status); err != nil {
+		uc.logger.Error("Failed to sync reservation status", zap.Error(err), zap.String("trip_id", tripID))
+		return nil, domain.ErrInternalError
+	}
+
+	if err := uc.progressRepo.Save(progress); err != nil {
+		uc.logger.Error("Failed to save trip progress", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.addStatusTimelineEvent(reservation, progress, status, now)
+
+	uc.logger.Info("Trip status updated successfully", zap.String("driver_id", driverID), zap.String("trip_id", tripID))
+	return progress, nil
+}
+
+func (uc *TripProgressUseCase) getDriverTrip(driverID, tripID string) (*domain.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetByID(tripID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrNotFound
+		}
+		uc.logger.Error("Failed to get trip", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if reservation.AssignedDriverID == nil || *reservation.AssignedDriverID != driverID {
+		return nil, domain.ErrNotFound
+	}
+
+	return reservation, nil
+}
+
+func (uc *TripProgressUseCase) loadProgress(driverID, tripID string) (*domain.TripProgress, error) {
+	progress, err := uc.progressRepo.GetByReservationID(tripID)
+	if err == domain.ErrNotFound {
+		return &domain.TripProgress{
+			ReservationID: tripID,
+			DriverID:      driverID,
+		}, nil
+	}
+	if err != nil {
+		uc.logger.Error("Failed to get trip progress", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return progress, nil
+}
+
+// syncReservationStatus mirrors the trip lifecycle on the reservation: starting
+// the trip schedules it, finishing completes it and a no-show cancels it.
+func (uc *TripProgressUseCase) syncReservationStatus(reservation *domain.Reservation, status domain.DriverTripStatus) error {
+	target, ok := reservationStatusFor(status)
+	if !ok {
+		return nil
+	}
+
+	if reservation.Status == target {
+		return nil
+	}
+
+	// A trip finished straight from ACTIVA still passes through PROGRAMADA so
+	// the reservation history stays valid.
+	if reservation.Status == domain.ReservationStatusActiva && target == domain.ReservationStatusCompletada {
+		if err := uc.reservationRepo.ChangeStatus(reservation.ID, domain.ReservationStatusProgramada); err != nil {
+			return err
+		}
+		reservation.Status = domain.ReservationStatusProgramada
+	}
+
+	if !reservation.CanTransitionTo(target) {
+		return fmt.Errorf("cannot move reservation from %s to %s", reservation.Status, target)
+	}
+
+	if err := uc.reservationRepo.ChangeStatus(reservation.ID, target); err != nil {
+		return err
+	}
+	reservation.Status = target
+
+	return nil
+}
+
+// reservationStatusFor returns the reservation status a trip step moves the
+// reservation to, if any.
+func reservationStatusFor(status domain.DriverTripStatus) (domain.ReservationStatus, bool) {
+	switch status {
+	case domain.DriverTripStatusEnCamino:
+		return domain.ReservationStatusProgramada, true
+	case domain.DriverTripStatusFinalizado:
+		return domain.ReservationStatusCompletada, true
+	case domain.DriverTripStatusNoShow:
+		return domain.ReservationStatusCancelada, true
+	}
+	return "", false
+}
+
+func (uc *TripProgressUseCase) addStatusTimelineEvent(reservation *domain.Reservation, progress *domain.TripProgress, status domain.DriverTripStatus, at time.Time) {
+	var title, description, variant string
+	switch status {
+	case domain.DriverTripStatusEnCamino:
+		title, description, variant = "Conductor en camino", "El conductor se dirige al punto de origen", "info"
+	case domain.DriverTripStatusEnOrigen:
+		title, variant = "Conductor en origen", "info"
+		if progress.ArrivedOnTime != nil && *progress.ArrivedOnTime {
+			description = "El conductor llegó a tiempo al punto de origen"
+		} else {
+			late := at.Sub(reservation.DateTime).Round(time.Minute)
+			description = fmt.Sprintf("El conductor llegó al punto de origen con %d minutos de retraso", int(late.Minutes()))
+			variant = "warning"
+		}
+	case domain.DriverTripStatusPasajeroABordo:
+		title, description, variant = "Pasajero a bordo", "El pasajero subió al vehículo", "primary"
+	case domain.DriverTripStatusFinalizado:
+		title, description, variant = "Viaje finalizado", "El conductor finalizó el viaje", "success"
+	case domain.DriverTripStatusNoShow:
+		title, description, variant = "Pasajero no se presentó", "El conductor esperó en el origen y el pasajero no se presentó", "warning"
+	default:
+		return
+	}
+
+	event := domain.TimelineEvent{
+		ReservationID: reservation.ID,
+		Title:         title,
+		Description:   description,
+		At:            at,
+		Variant:       variant,
+		CreatedAt:     at,
+	}
+
+	if err := uc.reservationRepo.AddTimelineEvent(reservation.ID, event); err != nil {
+		uc.logger.Warn("Failed to add trip timeline event", zap.Error(err), zap.String("reservation_id", reservation.ID))
+	}
+}
@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type TripUsageUseCase struct {
+	usageRepo       domain.TripUsageRepository
+	reservationRepo domain.ReservationRepository
+	vehicleRepo     domain.VehicleRepository
+	maintenanceRepo domain.MaintenanceRepository
+	pricingUseCase  *PricingUseCase
+	logger          *zap.Logger
+}
+
+func NewTripUsageUseCase(
+	usageRepo domain.TripUsageRepository,
+	reservationRepo domain.ReservationRepository,
+	vehicleRepo domain.VehicleRepository,
+	maintenanceRepo domain.MaintenanceRepository,
+	pricingUseCase *PricingUseCase,
+	logger *zap.Logger,
+) *TripUsageUseCase {
+	return &TripUsageUseCase{
+		usageRepo:       usageRepo,
+		reservationRepo: reservationRepo,
+		vehicleRepo:     vehicleRepo,
+		maintenanceRepo: maintenanceRepo,
+		pricingUseCase:  pricingUseCase,
+		logger:          logger,
+	}
+}
+
+// GetTripUsage returns the odometer readings and fuel events recorded on a
+// trip assigned to the driver.
+func (uc *TripUsageUseCase) GetTripUsage(driverID, tripID string) (*domain.TripUsage, error) {
+	reservation, err := uc.getDriverTrip(driverID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.loadUsage(reservation)
+}
+
+// RecordOdometer stores the start or end reading of a trip. Readings are
+// taken on the driver's vehicle and may never go below the vehicle's last
+// known reading; the end reading also sets the trip's actual distance.
+func (uc *TripUsageUseCase) RecordOdometer(driverID, tripID string, req domain.RecordOdometerRequest) (*domain.TripUsage, error) {
+	reservation, err := uc.getDriverTrip(driverID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == domain.ReservationStatusCancelada {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	usage, err := uc.loadUsage(reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	reading := &domain.OdometerReading{
+		ReservationID: tripID,
+		DriverID:      driverID,
+		Kind:          req.Kind,
+		ReadingKm:     *req.ReadingKm,
+		RecordedAt:    time.Now(),
+	}
+
+	switch req.Kind {
+	case domain.OdometerReadingStart:
+		if usage.Start != nil {
+			return nil, domain.ErrOdometerAlreadyRecorded
+		}
+		vehicleID, err := uc.driverVehicleID(driverID)
+		if err != nil {
+			return nil, err
+		}
+		reading.VehicleID = vehicleID
+
+	case domain.OdometerReadingEnd:
+		if usage.Start == nil {
+			return nil, domain.ErrOdometerStartMissing
+		}
+		if usage.End != nil {
+			return nil, domain.ErrOdometerAlreadyRecorded
+		}
+		// The trip is measured on the vehicle it started with
+		reading.VehicleID = usage.Start.VehicleID
+	}
+
+	if err := uc.ensureMonotonic(reading.VehicleID, reading.ReadingKm); err != nil {
+		return nil, err
+	}
+
+	if err := uc.usageRepo.CreateReading(reading); err != nil {
+		if err == domain.ErrOdometerAlreadyRecorded {
+			return nil, err
+		}
+		uc.logger.Error("Failed to create odometer reading", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if reading.Kind == domain.OdometerReadingStart {
+		usage.Start = reading
+	} else {
+		usage.End = reading
+		actualKm := float64(reading.ReadingKm - usage.Start.ReadingKm)
+		usage.ActualKm = &actualKm
+		if err := uc.usageRepo.SetActualDistance(tripID, actualKm); err != nil {
+			uc.logger.Error("Failed to set actual distance", zap.Error(err), zap.String("trip_id", tripID))
+			return nil, domain.ErrInternalError
+		}
+	}
+
+	uc.logger.Info("Odometer reading recorded",
+		zap.String("trip_id", tripID),
+		zap.String("vehicle_id", reading.VehicleID),
+		zap.String("kind", string(reading.Kind)),
+		zap.Int("reading_km", reading.ReadingKm),
+	)
+	return usage, nil
+}
+
+// RecordFuelEvent stores a refuelling or charging stop made during a trip.
+func (uc *TripUsageUseCase) RecordFuelEvent(driverID, tripID string, req domain.RecordFuelEventRequest) (*domain.FuelEvent, error) {
+	reservation, err := uc.getDriverTrip(driverID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == domain.ReservationStatusCancelada {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	event := &domain.FuelEvent{
+		ReservationID: tripID,
+		DriverID:      driverID,
+		Type:          req.Type,
+		Quantity:      req.Quantity,
+		Cost:          req.Cost,
+		OdometerKm:    req.OdometerKm,
+		Station:       req.Station,
+		OccurredAt:    now,
+	}
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(now) {
+			return nil, domain.ErrInvalidInput
+		}
+		event.OccurredAt = *req.OccurredAt
+	}
+
+	readings, err := uc.usageRepo.ListReadings(tripID)
+	if err != nil {
+		uc.logger.Error("Failed to list odometer readings", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	for _, reading := range readings {
+		if reading.Kind == domain.OdometerReadingStart {
+			event.VehicleID = reading.VehicleID
+		}
+	}
+	if event.VehicleID == "" {
+		vehicleID, err := uc.driverVehicleID(driverID)
+		if err != nil {
+			return nil, err
+		}
+		event.VehicleID = vehicleID
+	}
+
+	if event.OdometerKm != nil {
+		if err := uc.ensureMonotonic(event.VehicleID, *event.OdometerKm); err != nil {
+			return nil, err
+		}
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get default currency for fuel event", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	event.Currency = currency
+
+	if err := uc.usageRepo.CreateFuelEvent(event); err != nil {
+		uc.logger.Error("Failed to create fuel event", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Fuel event recorded",
+		zap.String("trip_id", tripID),
+		zap.String("vehicle_id", event.VehicleID),
+		zap.String("type", string(event.Type)),
+	)
+	return event, nil
+}
+
+// GetVehicleReport combines a vehicle's measured distance, fuel and
+// maintenance spend between from (inclusive) and to (exclusive).
+func (uc *TripUsageUseCase) GetVehicleReport(vehicleID string, from, to *time.Time) (*domain.VehicleUsageReport, error) {
+	vehicle, err := uc.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get vehicle for usage report", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	reports, err := uc.vehicleReports(&vehicleID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := reports[vehicleID]
+	if report == nil {
+		report = &domain.VehicleUsageReport{VehicleID: vehicleID}
+	}
+	report.Plate = vehicle.Plate
+	return uc.finishReport(report, from, to)
+}
+
+// ListVehicleReports returns a usage report for every vehicle with trips,
+// fuel or maintenance in the period, most expensive per km first.
+func (uc *TripUsageUseCase) ListVehicleReports(from, to *time.Time) ([]*domain.VehicleUsageReport, error) {
+	reports, err := uc.vehicleReports(nil, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*domain.VehicleUsageReport, 0, len(reports))
+	for id, report := range reports {
+		if vehicle, err := uc.vehicleRepo.GetByID(id); err == nil {
+			report.Plate = vehicle.Plate
+		}
+		if _, err := uc.finishReport(report, from, to); err != nil {
+			return nil, err
+		}
+		list = append(list, report)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return costPerKm(list[i]) > costPerKm(list[j])
+	})
+	return list, nil
+}
+
+func (uc *TripUsageUseCase) vehicleReports(vehicleID *string, from, to *time.Time) (map[string]*domain.VehicleUsageReport, error) {
+	usage, err := uc.usageRepo.UsageSummary(vehicleID, from, to)
+	if err != nil {
+		uc.logger.Error("Failed to summarize vehicle usage", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	costs, err := uc.maintenanceRepo.CostSummary(vehicleID, from, to)
+	if err != nil {
+		uc.logger.Error("Failed to summarize maintenance costs", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	reports := make(map[string]*domain.VehicleUsageReport)
+	for id, totals := range usage {
+		reports[id] = &domain.VehicleUsageReport{VehicleID: id, VehicleUsageTotals: totals}
+	}
+	for id, lines := range costs {
+		report := reports[id]
+		if report == nil {
+			report = &domain.VehicleUsageReport{VehicleID: id}
+			reports[id] = report
+		}
+		for _, line := range lines {
+			report.MaintenanceCost += line.Total
+		}
+	}
+
+	return reports, nil
+}
+
+func (uc *TripUsageUseCase) finishReport(report *domain.VehicleUsageReport, from, to *time.Time) (*domain.VehicleUsageReport, error) {
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get default currency for usage report", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	report.From, report.To = from, to
+	report.Currency = currency
+	report.Compute()
+	return report, nil
+}
+
+func (uc *TripUsageUseCase) loadUsage(reservation *domain.Reservation) (*domain.TripUsage, error) {
+	readings, err := uc.usageRepo.ListReadings(reservation.ID)
+	if err != nil {
+		uc.logger.Error("Failed to list odometer readings", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	events, err := uc.usageRepo.ListFuelEvents(reservation.ID)
+	if err != nil {
+		uc.logger.Error("Failed to list fuel events", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	usage := &domain.TripUsage{
+		ReservationID: reservation.ID,
+		EstimatedKm:   reservation.DistanceKM,
+		FuelEvents:    events,
+	}
+	for _, reading := range readings {
+		switch reading.Kind {
+		case domain.OdometerReadingStart:
+			usage.Start = reading
+		case domain.OdometerReadingEnd:
+			usage.End = reading
+		}
+	}
+	if usage.Start != nil && usage.End != nil {
+		actualKm := float64(usage.End.ReadingKm - usage.Start.ReadingKm)
+		usage.ActualKm = &actualKm
+	}
+
+	return usage, nil
+}
+
+// ensureMonotonic rejects readings below the highest odometer value known for
+// the vehicle.
+func (uc *TripUsageUseCase) ensureMonotonic(vehicleID string, readingKm int) error {
+	latest, err := uc.maintenanceRepo.LatestOdometer(vehicleID)
+	if err != nil {
+		uc.logger.Error("Failed to get latest odometer", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if latest != nil && readingKm < *latest {
+		uc.logger.Warn("Odometer reading decreased",
+			zap.String("vehicle_id", vehicleID),
+			zap.Int("reading_km", readingKm),
+			zap.Int("latest_km", *latest))
+		return domain.ErrOdometerReadingDecreased
+	}
+
+	return nil
+}
+
+func (uc *TripUsageUseCase) driverVehicleID(driverID string) (string, error) {
+	vehicle, err := uc.vehicleRepo.GetByDriverID(driverID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return "", domain.ErrDriverHasNoVehicle
+		}
+		uc.logger.Error("Failed to get driver vehicle", zap.Error(err))
+		return "", domain.ErrInternalError
+	}
+
+	return vehicle.ID, nil
+}
+
+func (uc *TripUsageUseCase) getDriverTrip(driverID, tripID string) (*domain.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetByID(tripID)
+	if err != nil {
+		if err == domain.ErrReservationNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrNotFound
+		}
+		uc.logger.Error("Failed to get trip", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	if reservation.AssignedDriverID == nil || *reservation.AssignedDriverID != driverID {
+		return nil, domain.ErrNotFound
+	}
+
+	return reservation, nil
+}
+
+func costPerKm(report *domain.VehicleUsageReport) float64 {
+	if report.CostPerKm == nil {
+		return 0
+	}
+	return *report.CostPerKm
+}
@@ -51,6 +51,11 @@ func (m *MockUserRepository) Update(id uuid.UUID, req domain.UpdateUserRequest)
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateUser(user *domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -75,7 +80,7 @@ func TestUserUseCase_CreateUser(t *testing.T) {
 	mockPasswordService := new(MockPasswordService)
 	logger := zap.NewNop()
 
-	useCase := NewUserUseCase(mockRepo, mockPasswordService, logger)
+	useCase := NewUserUseCase(mockRepo, nil, mockPasswordService, nil, logger)
 
 	t.Run("should create user successfully", func(t *testing.T) {
 		req := domain.CreateUserRequest{
@@ -139,7 +144,7 @@ func TestUserUseCase_GetUserByID(t *testing.T) {
 	mockPasswordService := new(MockPasswordService)
 	logger := zap.NewNop()
 
-	useCase := NewUserUseCase(mockRepo, mockPasswordService, logger)
+	useCase := NewUserUseCase(mockRepo, nil, mockPasswordService, nil, logger)
 
 	t.Run("should get user successfully", func(t *testing.T) {
 		userID := uuid.New()
@@ -189,7 +194,7 @@ func TestUserUseCase_ListUsers(t *testing.T) {
 	mockPasswordService := new(MockPasswordService)
 	logger := zap.NewNop()
 
-	useCase := NewUserUseCase(mockRepo, mockPasswordService, logger)
+	useCase := NewUserUseCase(mockRepo, nil, mockPasswordService, nil, logger)
 
 	t.Run("should list users successfully", func(t *testing.T) {
 		req := domain.ListUsersRequest{
@@ -231,7 +236,7 @@ func TestUserUseCase_ListUsers(t *testing.T) {
 		// Create fresh mocks for this test
 		mockRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
-		useCase := NewUserUseCase(mockRepo, mockPasswordService, logger)
+		useCase := NewUserUseCase(mockRepo, nil, mockPasswordService, nil, logger)
 
 		req := domain.ListUsersRequest{
 			Page:     0, // Invalid
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type UtilizationUseCase struct {
+	utilizationRepo domain.UtilizationRepository
+	logger          *zap.Logger
+}
+
+func NewUtilizationUseCase(utilizationRepo domain.UtilizationRepository, logger *zap.Logger) *UtilizationUseCase {
+	return &UtilizationUseCase{
+		utilizationRepo: utilizationRepo,
+		logger:          logger,
+	}
+}
+
+// GetFleetUtilization builds the day-by-day calendar of every vehicle in the
+// period and rolls it up by vehicle type and company.
+func (uc *UtilizationUseCase) GetFleetUtilization(req domain.FleetUtilizationRequest) (*domain.FleetUtilizationReport, error) {
+	if !req.To.After(req.From) || req.To.Sub(req.From) > domain.MaxUtilizationDays*24*time.Hour {
+		return nil, domain.ErrInvalidInput
+	}
+
+	vehicles, err := uc.utilizationRepo.ListFleet(req.VehicleID, req.VehicleType)
+	if err != nil {
+		uc.logger.Error("Failed to list fleet for utilization", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+	if req.VehicleID != nil && len(vehicles) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	bookings, windows, err := uc.loadSchedule(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.FleetUtilizationReport{
+		From:      req.From,
+		To:        req.To,
+		ByType:    []*domain.TypeUtilization{},
+		ByCompany: []*domain.CompanyUtilization{},
+		Vehicles:  make([]*domain.VehicleUtilization, 0, len(vehicles)),
+	}
+	byType := make(map[domain.VehicleType]*domain.TypeUtilization)
+	byCompany := make(map[uuid.UUID]*domain.CompanyUtilization)
+
+	for _, vehicle := range vehicles {
+		utilization := domain.BuildVehicleUtilization(vehicle, bookings[vehicle.ID], windows[vehicle.ID], req.From, req.To)
+		report.Vehicles = append(report.Vehicles, utilization)
+		report.Fleet.Add(utilization.UtilizationTotals)
+
+		typeTotals, ok := byType[vehicle.Type]
+		if !ok {
+			typeTotals = &domain.TypeUtilization{Type: vehicle.Type}
+			byType[vehicle.Type] = typeTotals
+			report.ByType = append(report.ByType, typeTotals)
+		}
+		typeTotals.Vehicles++
+		typeTotals.Add(utilization.UtilizationTotals)
+
+		// uuid.Nil collects the vehicles without a company
+		companyKey := uuid.Nil
+		if vehicle.CompanyID != nil {
+			companyKey = *vehicle.CompanyID
+		}
+		companyTotals, ok := byCompany[companyKey]
+		if !ok {
+			companyTotals = &domain.CompanyUtilization{
+				CompanyID:   vehicle.CompanyID,
+				CompanyName: vehicle.CompanyName,
+			}
+			byCompany[companyKey] = companyTotals
+			report.ByCompany = append(report.ByCompany, companyTotals)
+		}
+		companyTotals.Vehicles++
+		companyTotals.Add(utilization.UtilizationTotals)
+	}
+
+	sort.Slice(report.ByType, func(i, j int) bool {
+		return report.ByType[i].Type < report.ByType[j].Type
+	})
+	sort.SliceStable(report.ByCompany, func(i, j int) bool {
+		a, b := report.ByCompany[i].CompanyName, report.ByCompany[j].CompanyName
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return *a < *b
+	})
+
+	return report, nil
+}
+
+// GetVehicleUtilization returns the calendar of a single vehicle.
+func (uc *UtilizationUseCase) GetVehicleUtilization(vehicleID string, from, to time.Time) (*domain.VehicleUtilization, error) {
+	report, err := uc.GetFleetUtilization(domain.FleetUtilizationRequest{
+		VehicleID: &vehicleID,
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report.Vehicles[0], nil
+}
+
+// ListFreeVehicles returns the vehicles in service, optionally of one type,
+// with no booking or maintenance window overlapping [from, to).
+func (uc *UtilizationUseCase) ListFreeVehicles(req domain.FreeVehiclesRequest) ([]*domain.FleetVehicle, error) {
+	if !req.To.After(req.From) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	vehicles, err := uc.utilizationRepo.ListFleet(nil, req.VehicleType)
+	if err != nil {
+		uc.logger.Error("Failed to list fleet for availability", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	bookings, windows, err := uc.loadSchedule(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	free := []*domain.FleetVehicle{}
+	for _, vehicle := range vehicles {
+		if vehicle.Status == domain.VehicleStatusMaintenance {
+			continue
+		}
+		if domain.IsFree(bookings[vehicle.ID], windows[vehicle.ID], req.From, req.To) {
+			free = append(free, vehicle)
+		}
+	}
+
+	return free, nil
+}
+
+// loadSchedule returns the bookings, with their estimated end, and the
+// maintenance windows overlapping [from, to), keyed by vehicle.
+func (uc *UtilizationUseCase) loadSchedule(from, to time.Time) (map[string][]domain.VehicleBooking, map[string][]domain.VehicleWindow, error) {
+	bookingList, err := uc.utilizationRepo.ListBookings(domain.BookingLookbackStart(from), to)
+	if err != nil {
+		uc.logger.Error("Failed to list vehicle bookings", zap.Error(err))
+		return nil, nil, domain.ErrInternalError
+	}
+
+	windowList, err := uc.utilizationRepo.ListWindows(from, to)
+	if err != nil {
+		uc.logger.Error("Failed to list maintenance windows", zap.Error(err))
+		return nil, nil, domain.ErrInternalError
+	}
+
+	bookings := make(map[string][]domain.VehicleBooking)
+	for _, booking := range bookingList {
+		booking.End = booking.Start.Add(domain.EstimateTripDuration(booking.DistanceKm))
+		if !booking.End.After(from) {
+			continue
+		}
+		bookings[booking.VehicleID] = append(bookings[booking.VehicleID], booking)
+	}
+
+	windows := make(map[string][]domain.VehicleWindow)
+	for _, window := range windowList {
+		windows[window.VehicleID] = append(windows[window.VehicleID], window)
+	}
+
+	return bookings, windows, nil
+}
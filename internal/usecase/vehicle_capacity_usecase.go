@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+type VehicleCapacityUseCase struct {
+	vehicleRepo domain.VehicleRepository
+	logger      *zap.Logger
+}
+
+func NewVehicleCapacityUseCase(vehicleRepo domain.VehicleRepository, logger *zap.Logger) *VehicleCapacityUseCase {
+	return &VehicleCapacityUseCase{
+		vehicleRepo: vehicleRepo,
+		logger:      logger,
+	}
+}
+
+// EnsureCapacityAvailable returns domain.ErrNoVehicleCapacity unless at least
+// one vehicle of the type in service can carry the whole group.
+func (uc *VehicleCapacityUseCase) EnsureCapacityAvailable(vehicleType domain.VehicleType, passengers int) error {
+	capacities, err := uc.vehicleRepo.ListActiveCapacities(&vehicleType)
+	if err != nil {
+		uc.logger.Error("Failed to list vehicle capacities", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	for _, capacity := range capacities {
+		if capacity.Capacity >= passengers {
+			return nil
+		}
+	}
+
+	return domain.ErrNoVehicleCapacity
+}
+
+// EnsureDriverVehicleFits checks the driver's vehicle against the type and
+// group size of a reservation. Drivers without a vehicle, reservations booked
+// before the type was recorded and vehicles without a recorded capacity skip
+// the matching checks.
+func (uc *VehicleCapacityUseCase) EnsureDriverVehicleFits(driverID string, vehicleType *domain.VehicleType, passengers int) error {
+	vehicle, err := uc.vehicleRepo.GetByDriverID(driverID)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil
+		}
+		uc.logger.Error("Failed to get driver vehicle for capacity", zap.Error(err))
+		return domain.ErrInternalError
+	}
+
+	if vehicleType != nil && vehicle.Type != *vehicleType {
+		return domain.ErrVehicleTypeMismatch
+	}
+	if vehicle.Capacity != nil && *vehicle.Capacity < passengers {
+		return domain.ErrVehicleTooSmall
+	}
+
+	return nil
+}
+
+// SuggestSplit proposes how to spread a group over the vehicles in service,
+// restricted to one type when given.
+func (uc *VehicleCapacityUseCase) SuggestSplit(req domain.VehicleSplitRequest) (*domain.VehicleSplit, error) {
+	capacities, err := uc.vehicleRepo.ListActiveCapacities(req.VehicleType)
+	if err != nil {
+		uc.logger.Error("Failed to list vehicle capacities", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	split := domain.SuggestVehicleSplit(req.Passengers, capacities)
+	split.VehicleType = req.VehicleType
+	return split, nil
+}
@@ -12,17 +12,20 @@ import (
 type vehicleUseCase struct {
 	vehicleRepo domain.VehicleRepository
 	driverRepo  domain.DriverRepository
+	compliance  domain.ComplianceChecker
 	logger      *zap.Logger
 }
 
 func NewVehicleUseCase(
 	vehicleRepo domain.VehicleRepository,
 	driverRepo domain.DriverRepository,
+	compliance domain.ComplianceChecker,
 	logger *zap.Logger,
 ) domain.VehicleUseCase {
 	return &vehicleUseCase{
 		vehicleRepo: vehicleRepo,
 		driverRepo:  driverRepo,
+		compliance:  compliance,
 		logger:      logger,
 	}
 }
@@ -211,6 +214,12 @@ func (uc *vehicleUseCase) AssignVehicleToDriver(vehicleID string, driverID *stri
 			uc.logger.Error("Driver not found", zap.Error(err), zap.String("id", *driverID))
 			return nil, fmt.Errorf("driver not found: %w", err)
 		}
+
+		// Block expired vehicle documents and license classes that do not fit the vehicle
+		if err := uc.compliance.EnsureVehicleAssignable(vehicleID, *driverID); err != nil {
+			uc.logger.Warn("Vehicle is not assignable", zap.Error(err), zap.String("id", vehicleID))
+			return nil, err
+		}
 	}
 
 	// Perform the assignment
@@ -238,3 +247,60 @@ func (uc *vehicleUseCase) AssignVehicleToDriver(vehicleID string, driverID *stri
 	return vehicle, nil
 }
 
+// GetVehicleDriverAt answers who had the vehicle at a given moment, e.g. when
+// an infraction or damage report arrives weeks later.
+func (uc *vehicleUseCase) GetVehicleDriverAt(vehicleID string, at time.Time) (*domain.VehicleAssignment, error) {
+	if _, err := uc.vehicleRepo.GetByID(vehicleID); err != nil {
+		return nil, err
+	}
+
+	assignment, err := uc.vehicleRepo.GetAssignmentAt(vehicleID, at)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrVehicleAssignmentNotFound
+		}
+		uc.logger.Error("Failed to get vehicle assignment", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return assignment, nil
+}
+
+func (uc *vehicleUseCase) ListVehicleAssignments(vehicleID string) ([]*domain.VehicleAssignment, error) {
+	if _, err := uc.vehicleRepo.GetByID(vehicleID); err != nil {
+		return nil, err
+	}
+
+	assignments, err := uc.vehicleRepo.ListAssignments(domain.ListVehicleAssignmentsRequest{VehicleID: &vehicleID})
+	if err != nil {
+		uc.logger.Error("Failed to list vehicle assignments", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return assignments, nil
+}
+
+// ListDriverVehicleAssignments returns the vehicles a driver had during a
+// period, or over all time when no period is given.
+func (uc *vehicleUseCase) ListDriverVehicleAssignments(driverID string, from, to *time.Time) ([]*domain.VehicleAssignment, error) {
+	if _, err := uc.driverRepo.GetByID(driverID); err != nil {
+		if err == domain.ErrDriverNotFound || err == domain.ErrNotFound {
+			return nil, domain.ErrDriverNotFound
+		}
+		uc.logger.Error("Failed to get driver for vehicle assignments", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	assignments, err := uc.vehicleRepo.ListAssignments(domain.ListVehicleAssignmentsRequest{
+		DriverID: &driverID,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		uc.logger.Error("Failed to list driver vehicle assignments", zap.Error(err))
+		return nil, domain.ErrInternalError
+	}
+
+	return assignments, nil
+}
+
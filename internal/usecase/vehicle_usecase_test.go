@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockVehicleRepository implements the vehicle lookups and assignments the
+// use cases under test touch; any other call panics.
+type MockVehicleRepository struct {
+	domain.VehicleRepository
+	mock.Mock
+}
+
+func (m *MockVehicleRepository) GetByID(id string) (*domain.Vehicle, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Vehicle), args.Error(1)
+}
+
+func (m *MockVehicleRepository) AssignToDriver(vehicleID string, driverID *string) error {
+	args := m.Called(vehicleID, driverID)
+	return args.Error(0)
+}
+
+func (m *MockVehicleRepository) GetAssignmentAt(vehicleID string, at time.Time) (*domain.VehicleAssignment, error) {
+	args := m.Called(vehicleID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VehicleAssignment), args.Error(1)
+}
+
+func (m *MockVehicleRepository) ListAssignments(req domain.ListVehicleAssignmentsRequest) ([]*domain.VehicleAssignment, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*domain.VehicleAssignment), args.Error(1)
+}
+
+func TestVehicleUseCase_AssignVehicleToDriver(t *testing.T) {
+	t.Run("should reassign the vehicle to another driver", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		driverRepo := new(MockDriverRepository)
+		compliance := new(MockComplianceChecker)
+		useCase := NewVehicleUseCase(vehicleRepo, driverRepo, compliance, zap.NewNop())
+
+		previous := "DRV-001"
+		next := "DRV-002"
+
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001", DriverID: &previous}, nil).Once()
+		driverRepo.On("GetByID", next).Return(&domain.Driver{ID: next}, nil)
+		compliance.On("EnsureVehicleAssignable", "VEH-001", next).Return(nil)
+		vehicleRepo.On("AssignToDriver", "VEH-001", &next).Return(nil)
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001", DriverID: &next, Status: domain.VehicleStatusAssigned}, nil).Once()
+
+		vehicle, err := useCase.AssignVehicleToDriver("VEH-001", &next)
+
+		require.NoError(t, err)
+		assert.Equal(t, next, *vehicle.DriverID)
+		vehicleRepo.AssertExpectations(t)
+		compliance.AssertExpectations(t)
+	})
+
+	t.Run("should not reassign to a driver who cannot drive the vehicle", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		driverRepo := new(MockDriverRepository)
+		compliance := new(MockComplianceChecker)
+		useCase := NewVehicleUseCase(vehicleRepo, driverRepo, compliance, zap.NewNop())
+
+		next := "DRV-002"
+		complianceErr := &domain.ComplianceError{SubjectID: "VEH-001"}
+
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001"}, nil)
+		driverRepo.On("GetByID", next).Return(&domain.Driver{ID: next}, nil)
+		compliance.On("EnsureVehicleAssignable", "VEH-001", next).Return(complianceErr)
+
+		_, err := useCase.AssignVehicleToDriver("VEH-001", &next)
+
+		assert.Equal(t, complianceErr, err)
+		vehicleRepo.AssertNotCalled(t, "AssignToDriver", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should unassign without checking a driver", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		driverRepo := new(MockDriverRepository)
+		compliance := new(MockComplianceChecker)
+		useCase := NewVehicleUseCase(vehicleRepo, driverRepo, compliance, zap.NewNop())
+
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001", Status: domain.VehicleStatusAvailable}, nil)
+		vehicleRepo.On("AssignToDriver", "VEH-001", (*string)(nil)).Return(nil)
+
+		vehicle, err := useCase.AssignVehicleToDriver("VEH-001", nil)
+
+		require.NoError(t, err)
+		assert.Nil(t, vehicle.DriverID)
+		driverRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+		compliance.AssertNotCalled(t, "EnsureVehicleAssignable", mock.Anything, mock.Anything)
+	})
+}
+
+func TestVehicleUseCase_GetVehicleDriverAt(t *testing.T) {
+	at := time.Date(2026, 9, 15, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should return the assignment covering the time", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		useCase := NewVehicleUseCase(vehicleRepo, nil, nil, zap.NewNop())
+		assignment := &domain.VehicleAssignment{VehicleID: "VEH-001", DriverID: "DRV-001"}
+
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001"}, nil)
+		vehicleRepo.On("GetAssignmentAt", "VEH-001", at).Return(assignment, nil)
+
+		result, err := useCase.GetVehicleDriverAt("VEH-001", at)
+
+		require.NoError(t, err)
+		assert.Equal(t, assignment, result)
+	})
+
+	t.Run("should report when nobody had the vehicle", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		useCase := NewVehicleUseCase(vehicleRepo, nil, nil, zap.NewNop())
+
+		vehicleRepo.On("GetByID", "VEH-001").Return(&domain.Vehicle{ID: "VEH-001"}, nil)
+		vehicleRepo.On("GetAssignmentAt", "VEH-001", at).Return(nil, domain.ErrNotFound)
+
+		_, err := useCase.GetVehicleDriverAt("VEH-001", at)
+
+		assert.Equal(t, domain.ErrVehicleAssignmentNotFound, err)
+	})
+}
+
+func TestVehicleUseCase_ListDriverVehicleAssignments(t *testing.T) {
+	t.Run("should filter the history by driver and period", func(t *testing.T) {
+		vehicleRepo := new(MockVehicleRepository)
+		driverRepo := new(MockDriverRepository)
+		useCase := NewVehicleUseCase(vehicleRepo, driverRepo, nil, zap.NewNop())
+
+		driverID := "DRV-001"
+		from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		driverRepo.On("GetByID", driverID).Return(&domain.Driver{ID: driverID}, nil)
+		vehicleRepo.On("ListAssignments", domain.ListVehicleAssignmentsRequest{DriverID: &driverID, From: &from, To: &to}).
+			Return([]*domain.VehicleAssignment{}, nil)
+
+		_, err := useCase.ListDriverVehicleAssignments(driverID, &from, &to)
+
+		require.NoError(t, err)
+		vehicleRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for an unknown driver", func(t *testing.T) {
+		driverRepo := new(MockDriverRepository)
+		useCase := NewVehicleUseCase(new(MockVehicleRepository), driverRepo, nil, zap.NewNop())
+
+		driverRepo.On("GetByID", "DRV-404").Return(nil, domain.ErrNotFound)
+
+		_, err := useCase.ListDriverVehicleAssignments("DRV-404", nil, nil)
+
+		assert.Equal(t, domain.ErrDriverNotFound, err)
+	})
+}
@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// walletDebitBatchSize caps how many trips a single scheduler run debits.
+const walletDebitBatchSize = 200
+
+// WalletUseCase runs company wallets: prepaid balances and credit lines
+// that completed trips are debited from, with a ledger of every move.
+// Top-ups are paid through the payment flow.
+type WalletUseCase struct {
+	walletRepo     domain.WalletRepository
+	companyRepo    domain.CompanyRepository
+	emailService   domain.EmailService
+	pricingUseCase *PricingUseCase
+	logger         *zap.Logger
+}
+
+func NewWalletUseCase(
+	walletRepo domain.WalletRepository,
+	companyRepo domain.CompanyRepository,
+	emailService domain.EmailService,
+	pricingUseCase *PricingUseCase,
+	logger *zap.Logger,
+) *WalletUseCase {
+	return &WalletUseCase{
+		walletRepo:     walletRepo,
+		companyRepo:    companyRepo,
+		emailService:   emailService,
+		pricingUseCase: pricingUseCase,
+		logger:         logger,
+	}
+}
+
+// GetWallet returns the company's wallet with the amount of its upcoming
+// trips.
+func (uc *WalletUseCase) GetWallet(companyID uuid.UUID) (*domain.CompanyWallet, error) {
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrWalletNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	committed, err := uc.walletRepo.CommittedAmount(companyID, "")
+	if err != nil {
+		uc.logger.Error("Failed to get committed wallet amount", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	wallet.Committed = &committed
+	return wallet, nil
+}
+
+// UpsertWallet opens a wallet for the company or updates its settings.
+// From then on its completed trips are debited from the wallet instead of
+// being invoiced. Trips are priced in the default currency, so wallets are
+// only opened in it.
+func (uc *WalletUseCase) UpsertWallet(companyID uuid.UUID, req domain.UpsertWalletRequest) (*domain.CompanyWallet, error) {
+	if _, err := uc.companyRepo.GetByID(companyID); err != nil {
+		if err == domain.ErrNotFound {
+			return nil, domain.ErrCompanyNotFound
+		}
+		uc.logger.Error("Failed to get company for wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil && err != domain.ErrWalletNotFound {
+		uc.logger.Error("Failed to get wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	if wallet == nil {
+		currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+		if err != nil {
+			uc.logger.Error("Failed to get currency for wallet", zap.Error(err))
+			return nil, domain.ErrInternalError
+		}
+		if req.Currency != currency {
+			return nil, domain.ErrWalletCurrency
+		}
+
+		wallet = &domain.CompanyWallet{
+			CompanyID: companyID,
+			Currency:  req.Currency,
+		}
+	} else if wallet.Currency != req.Currency {
+		return nil, domain.ErrInvalidInput
+	}
+	if req.CreditLimit != nil {
+		wallet.CreditLimit = *req.CreditLimit
+	}
+	if req.LowBalanceThreshold != nil {
+		wallet.LowBalanceThreshold = *req.LowBalanceThreshold
+	}
+	if req.AlertEmail != nil {
+		wallet.AlertEmail = req.AlertEmail
+	}
+
+	if wallet.ID == uuid.Nil {
+		if err := uc.walletRepo.Create(wallet); err != nil {
+			if err == domain.ErrAlreadyExists {
+				return nil, err
+			}
+			uc.logger.Error("Failed to create wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+			return nil, domain.ErrInternalError
+		}
+
+		uc.logger.Info("Wallet opened",
+			zap.String("company_id", companyID.String()),
+			zap.String("wallet_id", wallet.ID.String()))
+		return wallet, nil
+	}
+
+	if err := uc.walletRepo.Update(wallet); err != nil {
+		uc.logger.Error("Failed to update wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.alertLowBalance(wallet)
+	return wallet, nil
+}
+
+func (uc *WalletUseCase) ListTransactions(companyID uuid.UUID, kind *domain.WalletTransactionKind, page, pageSize int) ([]*domain.WalletTransaction, int, error) {
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrWalletNotFound {
+			return nil, 0, err
+		}
+		uc.logger.Error("Failed to get wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	transactions, total, err := uc.walletRepo.ListTransactions(domain.ListWalletTransactionsRequest{
+		WalletID: wallet.ID,
+		Kind:     kind,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		uc.logger.Error("Failed to list wallet transactions", zap.Error(err), zap.String("wallet_id", wallet.ID.String()))
+		return nil, 0, domain.ErrInternalError
+	}
+
+	return transactions, total, nil
+}
+
+// Adjust corrects the balance of the company's wallet from the back office.
+func (uc *WalletUseCase) Adjust(companyID uuid.UUID, req domain.WalletAdjustmentRequest, createdBy uuid.UUID) (*domain.WalletTransaction, error) {
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrWalletNotFound {
+			return nil, err
+		}
+		uc.logger.Error("Failed to get wallet", zap.Error(err), zap.String("company_id", companyID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	tx := domain.NewWalletTransaction(wallet, domain.WalletTransactionAdjustment, req.Amount, req.Reason)
+	if tx.Amount == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	tx.CreatedBy = &createdBy
+
+	wallet, err = uc.walletRepo.Post(tx)
+	if err != nil {
+		uc.logger.Error("Failed to post wallet adjustment", zap.Error(err), zap.String("wallet_id", tx.WalletID.String()))
+		return nil, domain.ErrInternalError
+	}
+
+	uc.logger.Info("Wallet adjusted",
+		zap.String("wallet_id", wallet.ID.String()),
+		zap.Float64("amount", tx.Amount),
+		zap.String("created_by", createdBy.String()))
+
+	uc.alertLowBalance(wallet)
+	return tx, nil
+}
+
+// EnsureCredit implements domain.CreditChecker. A wallet left in another
+// currency after the default one changed is not debited, so it does not
+// guard bookings either.
+func (uc *WalletUseCase) EnsureCredit(companyID uuid.UUID, amount float64, excludeReservationID string) error {
+	wallet, err := uc.walletRepo.GetByCompany(companyID)
+	if err != nil {
+		if err == domain.ErrWalletNotFound {
+			return nil
+		}
+		uc.logger.Error("Failed to get wallet to check credit", zap.Error(err), zap.String("company_id", companyID.String()))
+		return domain.ErrInternalError
+	}
+
+	currency, err := uc.pricingUseCase.DefaultCurrency(context.Background())
+	if err != nil {
+		uc.logger.Error("Failed to get currency to check credit", zap.Error(err))
+		return domain.ErrInternalError
+	}
+	if wallet.Currency != currency {
+		uc.logger.Warn("Wallet is not in the pricing currency, credit not checked",
+			zap.String("wallet_id", wallet.ID.String()),
+			zap.String("wallet_currency", wallet.Currency),
+			zap.String("currency", currency))
+		return nil
+	}
+
+	committed, err := uc.walletRepo.CommittedAmount(companyID, excludeReservationID)
+	if err != nil {
+		uc.logger.Error("Failed to get committed wallet amount", zap.Error(err), zap.String("company_id", companyID.String()))
+		return domain.ErrInternalError
+	}
+
+	if committed+amount > wallet.Available() {
+		uc.logger.Info("Wallet credit exceeded",
+			zap.String("company_id", companyID.String()),
+			zap.Float64("available", wallet.Available()),
+			zap.Float64("committed", committed),
+			zap.Float64("amount", amount))
+		return domain.ErrInsufficientCredit
+	}
+
+	return nil
+}
+
+// DebitCompletedTrips debits every newly completed trip of a company with a
+// wallet from it. It is run by the scheduler; each trip is debited at most
+// once. Debits post even past the credit line: the trip was made. Trips are
+// priced in the default currency, so wallets in another one are left out.
+func (uc *WalletUseCase) DebitCompletedTrips(ctx context.Context) error {
+	currency, err := uc.pricingUseCase.DefaultCurrency(ctx)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := uc.walletRepo.ListDebitCandidates(currency, walletDebitBatchSize)
+	if err != nil {
+		return err
+	}
+
+	debited := 0
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wallet, err := uc.walletRepo.GetByCompany(candidate.CompanyID)
+		if err != nil {
+			return err
+		}
+
+		description := domain.AccrualDescription(candidate.ReservationID, candidate.Pickup, candidate.Destination, candidate.CompletedAt)
+		tx := domain.NewWalletTransaction(wallet, domain.WalletTransactionTripDebit, -candidate.Amount, description)
+		reservationID := candidate.ReservationID
+		tx.ReservationID = &reservationID
+
+		wallet, err = uc.walletRepo.Post(tx)
+		if err != nil {
+			if err == domain.ErrAlreadyExists {
+				continue
+			}
+			return err
+		}
+		debited++
+
+		uc.alertLowBalance(wallet)
+	}
+
+	if debited > 0 {
+		uc.logger.Info("Trips debited from wallets", zap.Int("count", debited))
+	}
+	return nil
+}
+
+// alertLowBalance emails the company once when its wallet falls below the
+// threshold; the wallet is alerted again after it was topped up over it.
+func (uc *WalletUseCase) alertLowBalance(wallet *domain.CompanyWallet) {
+	if !wallet.IsLow() {
+		return
+	}
+
+	marked, err := uc.walletRepo.MarkLowBalanceAlerted(wallet.ID, time.Now())
+	if err != nil || !marked {
+		return
+	}
+
+	company, err := uc.companyRepo.GetByID(wallet.CompanyID)
+	if err != nil {
+		uc.logger.Warn("Failed to get company for low balance alert", zap.Error(err), zap.String("company_id", wallet.CompanyID.String()))
+		return
+	}
+
+	to := company.ContactEmail
+	if wallet.AlertEmail != nil {
+		to = *wallet.AlertEmail
+	}
+
+	if err := uc.emailService.SendLowBalanceAlert(to, company.Name, wallet); err != nil {
+		uc.logger.Warn("Failed to send low balance alert", zap.Error(err), zap.String("wallet_id", wallet.ID.String()))
+		return
+	}
+
+	uc.logger.Info("Low balance alert sent",
+		zap.String("wallet_id", wallet.ID.String()),
+		zap.Float64("available", wallet.Available()))
+}
+
+// creditTopUp credits a captured top-up payment to its wallet. A top-up
+// settled twice is credited once; one in another currency than the wallet is
+// left for the back office.
+func creditTopUp(walletRepo domain.WalletRepository, payment *domain.Payment, logger *zap.Logger) {
+	if payment.WalletID == nil || !payment.IsCaptured() {
+		return
+	}
+
+	wallet, err := walletRepo.GetByID(*payment.WalletID)
+	if err != nil {
+		logger.Error("Failed to get wallet for top-up", zap.Error(err),
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("wallet_id", payment.WalletID.String()))
+		return
+	}
+	if payment.Currency != wallet.Currency {
+		logger.Warn("Top-up currency does not match the wallet, not credited",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("wallet_id", wallet.ID.String()),
+			zap.String("payment_currency", payment.Currency),
+			zap.String("wallet_currency", wallet.Currency))
+		return
+	}
+
+	tx := domain.NewWalletTransaction(wallet, domain.WalletTransactionTopUp, payment.Amount, payment.Description())
+	tx.PaymentID = &payment.ID
+
+	wallet, err = walletRepo.Post(tx)
+	if err != nil {
+		if err == domain.ErrAlreadyExists {
+			return
+		}
+		logger.Error("Failed to credit wallet top-up", zap.Error(err),
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("wallet_id", payment.WalletID.String()))
+		return
+	}
+
+	logger.Info("Wallet topped up",
+		zap.String("wallet_id", wallet.ID.String()),
+		zap.String("payment_id", payment.ID.String()),
+		zap.Float64("amount", tx.Amount))
+}
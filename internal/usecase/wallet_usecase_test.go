@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"turivo-backend/internal/domain"
+)
+
+// MockWalletRepository implements the wallet lookups and postings the use
+// cases under test touch; any other call panics.
+type MockWalletRepository struct {
+	domain.WalletRepository
+	mock.Mock
+}
+
+func (m *MockWalletRepository) GetByCompany(companyID uuid.UUID) (*domain.CompanyWallet, error) {
+	args := m.Called(companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CompanyWallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) GetByID(id uuid.UUID) (*domain.CompanyWallet, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CompanyWallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) Post(tx *domain.WalletTransaction) (*domain.CompanyWallet, error) {
+	args := m.Called(tx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CompanyWallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) Create(wallet *domain.CompanyWallet) error {
+	args := m.Called(wallet)
+	if args.Error(0) == nil {
+		wallet.ID = uuid.New()
+	}
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) CommittedAmount(companyID uuid.UUID, excludeReservationID string) (float64, error) {
+	args := m.Called(companyID, excludeReservationID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockWalletRepository) ListDebitCandidates(currency string, limit int) ([]*domain.WalletDebitCandidate, error) {
+	args := m.Called(currency, limit)
+	return args.Get(0).([]*domain.WalletDebitCandidate), args.Error(1)
+}
+
+// MockCompanyRepository implements the company lookup only.
+type MockCompanyRepository struct {
+	domain.CompanyRepository
+	mock.Mock
+}
+
+func (m *MockCompanyRepository) GetByID(id uuid.UUID) (*domain.Company, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Company), args.Error(1)
+}
+
+// MockPricingRepository implements the pricing settings only.
+type MockPricingRepository struct {
+	domain.PricingRepository
+	mock.Mock
+}
+
+func (m *MockPricingRepository) GetSettings(ctx context.Context) (*domain.PricingSettings, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PricingSettings), args.Error(1)
+}
+
+func newPricingUseCaseForTest(currency string) *PricingUseCase {
+	pricingRepo := new(MockPricingRepository)
+	pricingRepo.On("GetSettings", mock.Anything).Return(&domain.PricingSettings{DefaultCurrency: currency}, nil)
+	return NewPricingUseCase(pricingRepo, zap.NewNop())
+}
+
+func TestWalletUseCase_UpsertWallet(t *testing.T) {
+	companyID := uuid.New()
+
+	t.Run("should open a wallet in the pricing currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		companyRepo := new(MockCompanyRepository)
+		useCase := NewWalletUseCase(walletRepo, companyRepo, nil, newPricingUseCaseForTest("CLP"), zap.NewNop())
+
+		companyRepo.On("GetByID", companyID).Return(&domain.Company{ID: companyID}, nil)
+		walletRepo.On("GetByCompany", companyID).Return(nil, domain.ErrWalletNotFound)
+		walletRepo.On("Create", mock.MatchedBy(func(wallet *domain.CompanyWallet) bool {
+			return wallet.CompanyID == companyID && wallet.Currency == "CLP"
+		})).Return(nil)
+
+		wallet, err := useCase.UpsertWallet(companyID, domain.UpsertWalletRequest{Currency: "CLP"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "CLP", wallet.Currency)
+		walletRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not open a wallet in another currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		companyRepo := new(MockCompanyRepository)
+		useCase := NewWalletUseCase(walletRepo, companyRepo, nil, newPricingUseCaseForTest("CLP"), zap.NewNop())
+
+		companyRepo.On("GetByID", companyID).Return(&domain.Company{ID: companyID}, nil)
+		walletRepo.On("GetByCompany", companyID).Return(nil, domain.ErrWalletNotFound)
+
+		_, err := useCase.UpsertWallet(companyID, domain.UpsertWalletRequest{Currency: "USD"})
+
+		assert.Equal(t, domain.ErrWalletCurrency, err)
+		walletRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestWalletUseCase_EnsureCredit(t *testing.T) {
+	companyID := uuid.New()
+
+	t.Run("should refuse an amount over the available credit", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		useCase := NewWalletUseCase(walletRepo, nil, nil, newPricingUseCaseForTest("CLP"), zap.NewNop())
+
+		walletRepo.On("GetByCompany", companyID).Return(&domain.CompanyWallet{CompanyID: companyID, Currency: "CLP", Balance: 50000}, nil)
+		walletRepo.On("CommittedAmount", companyID, "RES-001").Return(30000.0, nil)
+
+		err := useCase.EnsureCredit(companyID, 25000, "RES-001")
+
+		assert.Equal(t, domain.ErrInsufficientCredit, err)
+	})
+
+	t.Run("should not compare prices against a wallet in another currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		useCase := NewWalletUseCase(walletRepo, nil, nil, newPricingUseCaseForTest("CLP"), zap.NewNop())
+
+		walletRepo.On("GetByCompany", companyID).Return(&domain.CompanyWallet{CompanyID: companyID, Currency: "USD", Balance: 50}, nil)
+
+		err := useCase.EnsureCredit(companyID, 25000, "RES-001")
+
+		require.NoError(t, err)
+		walletRepo.AssertNotCalled(t, "CommittedAmount", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWalletUseCase_DebitCompletedTrips(t *testing.T) {
+	t.Run("should debit trips from wallets in the pricing currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		useCase := NewWalletUseCase(walletRepo, nil, nil, newPricingUseCaseForTest("CLP"), zap.NewNop())
+
+		clpWallet := &domain.CompanyWallet{ID: uuid.New(), CompanyID: uuid.New(), Currency: "CLP", Balance: 500000}
+		completedAt := time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)
+
+		walletRepo.On("ListDebitCandidates", "CLP", walletDebitBatchSize).Return([]*domain.WalletDebitCandidate{
+			{CompanyID: clpWallet.CompanyID, ReservationID: "RES-001", Pickup: "Aeropuerto", Destination: "Las Condes", Amount: 25000, CompletedAt: completedAt},
+		}, nil)
+		walletRepo.On("GetByCompany", clpWallet.CompanyID).Return(clpWallet, nil)
+		walletRepo.On("Post", mock.MatchedBy(func(tx *domain.WalletTransaction) bool {
+			return tx.WalletID == clpWallet.ID && tx.Kind == domain.WalletTransactionTripDebit &&
+				tx.Amount == -25000 && tx.ReservationID != nil && *tx.ReservationID == "RES-001"
+		})).Return(&domain.CompanyWallet{ID: clpWallet.ID, Currency: "CLP", Balance: 475000}, nil)
+
+		err := useCase.DebitCompletedTrips(context.Background())
+
+		require.NoError(t, err)
+		walletRepo.AssertExpectations(t)
+		walletRepo.AssertNumberOfCalls(t, "Post", 1)
+	})
+}
+
+func TestCreditTopUp(t *testing.T) {
+	walletID := uuid.New()
+	captured := func(currency string) *domain.Payment {
+		return &domain.Payment{
+			ID:       uuid.New(),
+			WalletID: &walletID,
+			Amount:   100000,
+			Currency: currency,
+			Status:   domain.PaymentStatusApproved,
+		}
+	}
+
+	t.Run("should credit a top-up in the wallet currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		payment := captured("CLP")
+
+		walletRepo.On("GetByID", walletID).Return(&domain.CompanyWallet{ID: walletID, Currency: "CLP"}, nil)
+		walletRepo.On("Post", mock.MatchedBy(func(tx *domain.WalletTransaction) bool {
+			return tx.WalletID == walletID && tx.Kind == domain.WalletTransactionTopUp &&
+				tx.Amount == 100000 && tx.PaymentID != nil && *tx.PaymentID == payment.ID
+		})).Return(&domain.CompanyWallet{ID: walletID, Currency: "CLP", Balance: 100000}, nil)
+
+		creditTopUp(walletRepo, payment, zap.NewNop())
+
+		walletRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not credit a top-up in another currency", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+
+		walletRepo.On("GetByID", walletID).Return(&domain.CompanyWallet{ID: walletID, Currency: "USD"}, nil)
+
+		creditTopUp(walletRepo, captured("CLP"), zap.NewNop())
+
+		walletRepo.AssertNotCalled(t, "Post", mock.Anything)
+	})
+
+	t.Run("should ignore payments that are not captured top-ups", func(t *testing.T) {
+		walletRepo := new(MockWalletRepository)
+		pending := captured("CLP")
+		pending.Status = domain.PaymentStatusPending
+
+		creditTopUp(walletRepo, pending, zap.NewNop())
+		creditTopUp(walletRepo, &domain.Payment{ID: uuid.New(), Status: domain.PaymentStatusApproved}, zap.NewNop())
+
+		assert.Empty(t, walletRepo.Calls)
+	})
+}
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_trip_offers_updated_at ON trip_offers;
+DROP TABLE IF EXISTS trip_offers;
@@ -0,0 +1,26 @@
+-- Trip offers sent to drivers during dispatch
+CREATE TABLE trip_offers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reservation_id VARCHAR(20) NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED'
+        CHECK (status IN ('QUEUED', 'PENDING', 'ACCEPTED', 'DECLINED', 'EXPIRED', 'CANCELLED')),
+    offered_at TIMESTAMPTZ NULL,
+    expires_at TIMESTAMPTZ NULL,
+    responded_at TIMESTAMPTZ NULL,
+    decline_reason TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_trip_offers_reservation_id ON trip_offers(reservation_id, position);
+CREATE INDEX idx_trip_offers_driver_id ON trip_offers(driver_id, status);
+CREATE INDEX idx_trip_offers_pending_expiry ON trip_offers(expires_at) WHERE status = 'PENDING';
+
+-- Only one live offer per reservation at a time
+CREATE UNIQUE INDEX idx_trip_offers_one_pending ON trip_offers(reservation_id) WHERE status = 'PENDING';
+
+CREATE TRIGGER update_trip_offers_updated_at BEFORE UPDATE ON trip_offers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_trip_progress_updated_at ON trip_progress;
+DROP TABLE IF EXISTS trip_progress;
@@ -0,0 +1,21 @@
+-- Driver-side trip lifecycle with a timestamp for every step
+CREATE TABLE trip_progress (
+    reservation_id VARCHAR(20) PRIMARY KEY REFERENCES reservations(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('EN_CAMINO', 'EN_ORIGEN', 'PASAJERO_A_BORDO', 'FINALIZADO', 'NO_SHOW')),
+    en_route_at TIMESTAMPTZ NULL,
+    arrived_at TIMESTAMPTZ NULL,
+    onboard_at TIMESTAMPTZ NULL,
+    finished_at TIMESTAMPTZ NULL,
+    no_show_at TIMESTAMPTZ NULL,
+    arrived_on_time BOOLEAN NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_trip_progress_driver_id ON trip_progress(driver_id, status);
+
+CREATE TRIGGER update_trip_progress_updated_at BEFORE UPDATE ON trip_progress
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,19 @@
+-- GPS pings uploaded by the driver app
+CREATE TABLE driver_locations (
+    id BIGSERIAL PRIMARY KEY,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    reservation_id VARCHAR(20) NULL REFERENCES reservations(id) ON DELETE SET NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    accuracy REAL NULL,
+    speed REAL NULL,
+    heading REAL NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_driver_locations_driver_id ON driver_locations(driver_id, recorded_at DESC);
+CREATE INDEX idx_driver_locations_reservation_id ON driver_locations(reservation_id, recorded_at DESC)
+    WHERE reservation_id IS NOT NULL;
+CREATE INDEX idx_driver_locations_recorded_at ON driver_locations(recorded_at);
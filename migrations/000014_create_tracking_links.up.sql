@@ -0,0 +1,12 @@
+-- Revocable public tracking links for reservations
+CREATE TABLE tracking_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reservation_id VARCHAR(20) NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    created_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_tracking_links_reservation_id ON tracking_links(reservation_id, created_at DESC);
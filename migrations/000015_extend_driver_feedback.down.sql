@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS feedback_requests;
+
+DROP TRIGGER IF EXISTS update_driver_feedback_updated_at ON driver_feedback;
+DROP INDEX IF EXISTS idx_driver_feedback_status;
+DROP INDEX IF EXISTS idx_driver_feedback_reservation_id;
+CREATE INDEX idx_driver_feedback_reservation_id ON driver_feedback(reservation_id);
+
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS moderation_note;
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS status;
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS tags;
+ALTER TABLE driver_feedback DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,35 @@
+-- Keep only the most recent feedback per reservation before enforcing uniqueness
+DELETE FROM driver_feedback df
+USING driver_feedback newer
+WHERE df.reservation_id = newer.reservation_id
+  AND (df.created_at, df.id) < (newer.created_at, newer.id);
+
+-- Passenger ratings: owner, quick tags and moderation state
+ALTER TABLE driver_feedback ADD COLUMN user_id UUID NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE driver_feedback ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE driver_feedback ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'PUBLISHED'
+    CHECK (status IN ('PUBLISHED', 'HIDDEN'));
+ALTER TABLE driver_feedback ADD COLUMN moderation_note TEXT NULL;
+ALTER TABLE driver_feedback ADD COLUMN moderated_by UUID NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE driver_feedback ADD COLUMN moderated_at TIMESTAMPTZ NULL;
+
+-- One rating per trip
+DROP INDEX IF EXISTS idx_driver_feedback_reservation_id;
+CREATE UNIQUE INDEX idx_driver_feedback_reservation_id ON driver_feedback(reservation_id);
+CREATE INDEX idx_driver_feedback_status ON driver_feedback(driver_id, status, created_at DESC);
+
+DROP TRIGGER IF EXISTS update_driver_feedback_updated_at ON driver_feedback;
+CREATE TRIGGER update_driver_feedback_updated_at BEFORE UPDATE ON driver_feedback
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- One-time rating prompts sent after a trip is completed
+CREATE TABLE feedback_requests (
+    reservation_id VARCHAR(20) PRIMARY KEY REFERENCES reservations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_feedback_requests_user_id ON feedback_requests(user_id, expires_at);
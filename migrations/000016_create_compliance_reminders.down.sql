@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_vehicles_inspection_expires_at;
+DROP INDEX IF EXISTS idx_vehicles_insurance_expires_at;
+DROP INDEX IF EXISTS idx_driver_licenses_expires_at;
+DROP TABLE IF EXISTS compliance_reminders;
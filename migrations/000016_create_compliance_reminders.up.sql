@@ -0,0 +1,17 @@
+-- Expiry reminders already sent, so the daily job notifies each threshold once.
+-- Keyed on expires_at so renewing a document starts a fresh reminder cycle.
+CREATE TABLE compliance_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_type VARCHAR(20) NOT NULL CHECK (document_type IN ('LICENSE', 'INSURANCE', 'INSPECTION')),
+    subject_id VARCHAR(64) NOT NULL,
+    expires_at DATE NOT NULL,
+    threshold_days INTEGER NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX idx_compliance_reminders_unique
+    ON compliance_reminders(document_type, subject_id, expires_at, threshold_days);
+CREATE INDEX idx_driver_licenses_expires_at ON driver_licenses(expires_at);
+CREATE INDEX idx_vehicles_insurance_expires_at ON vehicles(insurance_expires_at);
+CREATE INDEX idx_vehicles_inspection_expires_at ON vehicles(inspection_expires_at);
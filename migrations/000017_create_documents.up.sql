@@ -0,0 +1,27 @@
+-- Uploaded driver documents and vehicle photos awaiting admin review.
+-- The files themselves live in FileStorage under storage_key.
+CREATE TABLE documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('DRIVER', 'VEHICLE')),
+    owner_id VARCHAR(64) NOT NULL,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('DRIVER_PHOTO', 'LICENSE', 'BACKGROUND_CHECK', 'VEHICLE_PHOTO')),
+    storage_key VARCHAR(500) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX idx_documents_storage_key ON documents(storage_key);
+CREATE INDEX idx_documents_owner ON documents(owner_type, owner_id);
+CREATE INDEX idx_documents_status ON documents(status);
+
+CREATE TRIGGER update_documents_updated_at BEFORE UPDATE ON documents
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
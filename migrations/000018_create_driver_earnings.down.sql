@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS driver_earnings;
+DROP TRIGGER IF EXISTS update_driver_settlements_updated_at ON driver_settlements;
+DROP TABLE IF EXISTS driver_settlements;
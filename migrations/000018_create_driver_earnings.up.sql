@@ -0,0 +1,54 @@
+-- Periodic payout statements grouping a driver's ledger entries.
+CREATE TABLE driver_settlements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    period VARCHAR(20) NOT NULL CHECK (period IN ('WEEKLY', 'BIWEEKLY')),
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'APPROVED', 'PAID')),
+    currency VARCHAR(3) NOT NULL,
+    trip_count INTEGER NOT NULL DEFAULT 0,
+    trip_payouts NUMERIC(12,2) NOT NULL DEFAULT 0,
+    tips NUMERIC(12,2) NOT NULL DEFAULT 0,
+    compensations NUMERIC(12,2) NOT NULL DEFAULT 0,
+    adjustments NUMERIC(12,2) NOT NULL DEFAULT 0,
+    penalties NUMERIC(12,2) NOT NULL DEFAULT 0,
+    total NUMERIC(12,2) NOT NULL DEFAULT 0,
+    payment_reference VARCHAR(100),
+    approved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMPTZ,
+    paid_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end > period_start)
+);
+
+-- Driver earnings ledger. Entries are append-only; settlement_id is set once
+-- the entry is included in a statement and cleared if a draft is discarded.
+CREATE TABLE driver_earnings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    reservation_id VARCHAR(20) REFERENCES reservations(id) ON DELETE SET NULL,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('TRIP_PAYOUT', 'ADJUSTMENT', 'TIP', 'CANCELLATION_COMPENSATION', 'PENALTY')),
+    amount NUMERIC(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    gross_fare NUMERIC(12,2),
+    commission NUMERIC(12,2),
+    description TEXT,
+    settlement_id UUID REFERENCES driver_settlements(id) ON DELETE SET NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX idx_driver_settlements_period ON driver_settlements(driver_id, period_start, period_end);
+CREATE INDEX idx_driver_settlements_status ON driver_settlements(status);
+CREATE UNIQUE INDEX idx_driver_earnings_trip_payout ON driver_earnings(reservation_id) WHERE type = 'TRIP_PAYOUT';
+CREATE INDEX idx_driver_earnings_driver ON driver_earnings(driver_id, occurred_at);
+CREATE INDEX idx_driver_earnings_unsettled ON driver_earnings(driver_id) WHERE settlement_id IS NULL;
+CREATE INDEX idx_driver_earnings_settlement ON driver_earnings(settlement_id);
+
+CREATE TRIGGER update_driver_settlements_updated_at BEFORE UPDATE ON driver_settlements
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_shift_change_requests_updated_at ON shift_change_requests;
+DROP TABLE IF EXISTS shift_change_requests;
+DROP TRIGGER IF EXISTS update_shifts_updated_at ON shifts;
+DROP TABLE IF EXISTS shifts;
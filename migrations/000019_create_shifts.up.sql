@@ -0,0 +1,50 @@
+-- Planned driver shifts. Overlaps per driver and per vehicle are rejected by
+-- the application before insert.
+CREATE TABLE shifts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    vehicle_id UUID REFERENCES vehicles(id) ON DELETE SET NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    region VARCHAR(10),
+    status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'CANCELLED')),
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Swap and time-off requests raised by drivers and decided by admins.
+CREATE TABLE shift_change_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('SWAP', 'TIME_OFF')),
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    shift_id UUID REFERENCES shifts(id) ON DELETE CASCADE,
+    target_driver_id VARCHAR(20) REFERENCES drivers(id) ON DELETE CASCADE,
+    target_shift_id UUID REFERENCES shifts(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'CANCELLED')),
+    review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (type <> 'SWAP' OR (shift_id IS NOT NULL AND target_driver_id IS NOT NULL)),
+    CHECK (type <> 'TIME_OFF' OR (starts_at IS NOT NULL AND ends_at > starts_at))
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_shifts_driver_period ON shifts(driver_id, starts_at, ends_at) WHERE status = 'SCHEDULED';
+CREATE INDEX idx_shifts_vehicle_period ON shifts(vehicle_id, starts_at, ends_at) WHERE status = 'SCHEDULED';
+CREATE INDEX idx_shifts_starts_at ON shifts(starts_at);
+CREATE INDEX idx_shift_change_requests_driver ON shift_change_requests(driver_id);
+CREATE INDEX idx_shift_change_requests_status ON shift_change_requests(status);
+
+CREATE TRIGGER update_shifts_updated_at BEFORE UPDATE ON shifts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_shift_change_requests_updated_at BEFORE UPDATE ON shift_change_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
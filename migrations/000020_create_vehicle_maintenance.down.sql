@@ -0,0 +1,12 @@
+DELETE FROM documents WHERE owner_type = 'MAINTENANCE';
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents ADD CONSTRAINT documents_kind_check
+    CHECK (kind IN ('DRIVER_PHOTO', 'LICENSE', 'BACKGROUND_CHECK', 'VEHICLE_PHOTO'));
+ALTER TABLE documents DROP CONSTRAINT documents_owner_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_owner_type_check
+    CHECK (owner_type IN ('DRIVER', 'VEHICLE'));
+
+DROP TRIGGER IF EXISTS update_maintenance_records_updated_at ON maintenance_records;
+DROP TABLE IF EXISTS maintenance_records;
+DROP TRIGGER IF EXISTS update_maintenance_plans_updated_at ON maintenance_plans;
+DROP TABLE IF EXISTS maintenance_plans;
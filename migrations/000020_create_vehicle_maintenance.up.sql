@@ -0,0 +1,60 @@
+-- Recurring maintenance per vehicle, due every interval_km and/or interval_days
+-- since the last service.
+CREATE TABLE maintenance_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    service_type VARCHAR(30) NOT NULL CHECK (service_type IN ('OIL_CHANGE', 'TIRES', 'BRAKES', 'GENERAL_SERVICE', 'INSPECTION', 'REPAIR', 'OTHER')),
+    interval_km INTEGER CHECK (interval_km > 0),
+    interval_days INTEGER CHECK (interval_days > 0),
+    last_service_at TIMESTAMPTZ NOT NULL,
+    last_service_odometer INTEGER,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    notes TEXT,
+    reminder_sent_at TIMESTAMPTZ,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (interval_km IS NOT NULL OR interval_days IS NOT NULL)
+);
+
+-- Service history and scheduled maintenance windows. While a window is in
+-- progress the vehicle is kept in MAINTENANCE status.
+CREATE TABLE maintenance_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    plan_id UUID REFERENCES maintenance_plans(id) ON DELETE SET NULL,
+    service_type VARCHAR(30) NOT NULL CHECK (service_type IN ('OIL_CHANGE', 'TIRES', 'BRAKES', 'GENERAL_SERVICE', 'INSPECTION', 'REPAIR', 'OTHER')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('SCHEDULED', 'IN_PROGRESS', 'COMPLETED', 'CANCELLED')),
+    scheduled_start TIMESTAMPTZ,
+    scheduled_end TIMESTAMPTZ,
+    performed_at TIMESTAMPTZ,
+    odometer INTEGER CHECK (odometer >= 0),
+    cost NUMERIC(12,2) CHECK (cost >= 0),
+    currency VARCHAR(3) NOT NULL,
+    workshop VARCHAR(200),
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (scheduled_end IS NULL OR scheduled_end > scheduled_start)
+);
+
+-- Allow files attached to maintenance records
+ALTER TABLE documents DROP CONSTRAINT documents_owner_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_owner_type_check
+    CHECK (owner_type IN ('DRIVER', 'VEHICLE', 'MAINTENANCE'));
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents ADD CONSTRAINT documents_kind_check
+    CHECK (kind IN ('DRIVER_PHOTO', 'LICENSE', 'BACKGROUND_CHECK', 'VEHICLE_PHOTO', 'MAINTENANCE_ATTACHMENT'));
+
+-- Create indexes for better performance
+CREATE INDEX idx_maintenance_plans_vehicle ON maintenance_plans(vehicle_id) WHERE active;
+CREATE INDEX idx_maintenance_records_vehicle ON maintenance_records(vehicle_id, performed_at);
+CREATE INDEX idx_maintenance_records_window ON maintenance_records(status, scheduled_start, scheduled_end)
+    WHERE status IN ('SCHEDULED', 'IN_PROGRESS');
+
+CREATE TRIGGER update_maintenance_plans_updated_at BEFORE UPDATE ON maintenance_plans
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_maintenance_records_updated_at BEFORE UPDATE ON maintenance_records
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fuel_events;
+DROP TABLE IF EXISTS odometer_readings;
+ALTER TABLE reservations DROP COLUMN IF EXISTS actual_distance_km;
@@ -0,0 +1,38 @@
+-- Actual distance driven, from the trip's odometer readings, next to the
+-- estimated distance_km
+ALTER TABLE reservations ADD COLUMN actual_distance_km NUMERIC(10,2);
+
+-- Odometer readings taken by the driver at the start and end of a trip.
+-- Readings never decrease per vehicle; this is checked by the application.
+CREATE TABLE odometer_readings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reservation_id VARCHAR(20) NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('START', 'END')),
+    reading_km INTEGER NOT NULL CHECK (reading_km >= 0),
+    recorded_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (reservation_id, kind)
+);
+
+-- Refuelling and charging stops recorded during trips
+CREATE TABLE fuel_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reservation_id VARCHAR(20) NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('FUEL', 'CHARGE')),
+    quantity NUMERIC(10,2) NOT NULL CHECK (quantity > 0),
+    cost NUMERIC(12,2) CHECK (cost >= 0),
+    currency VARCHAR(3) NOT NULL,
+    odometer_km INTEGER CHECK (odometer_km >= 0),
+    station VARCHAR(200),
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_odometer_readings_vehicle ON odometer_readings(vehicle_id, recorded_at);
+CREATE INDEX idx_fuel_events_reservation ON fuel_events(reservation_id);
+CREATE INDEX idx_fuel_events_vehicle ON fuel_events(vehicle_id, occurred_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reservations_vehicle_type;
+ALTER TABLE reservations DROP COLUMN IF EXISTS vehicle_type;
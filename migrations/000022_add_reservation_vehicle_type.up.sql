@@ -0,0 +1,5 @@
+-- Vehicle type requested at booking. It was only used for pricing before;
+-- older reservations keep it NULL and are not matched on type.
+ALTER TABLE reservations ADD COLUMN vehicle_type vehicle_type;
+
+CREATE INDEX idx_reservations_vehicle_type ON reservations(vehicle_type);
@@ -0,0 +1,21 @@
+-- History of which driver had which vehicle. vehicles.driver_id keeps
+-- pointing at the current driver; released_at is NULL while an assignment
+-- is current.
+CREATE TABLE vehicle_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL,
+    released_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_assignment_period CHECK (released_at IS NULL OR released_at >= assigned_at)
+);
+
+CREATE UNIQUE INDEX idx_vehicle_assignments_open_vehicle ON vehicle_assignments(vehicle_id) WHERE released_at IS NULL;
+CREATE UNIQUE INDEX idx_vehicle_assignments_open_driver ON vehicle_assignments(driver_id) WHERE released_at IS NULL;
+CREATE INDEX idx_vehicle_assignments_vehicle_period ON vehicle_assignments(vehicle_id, assigned_at);
+CREATE INDEX idx_vehicle_assignments_driver_period ON vehicle_assignments(driver_id, assigned_at);
+
+-- Current assignments start the history, dated from the vehicle's last update
+INSERT INTO vehicle_assignments (vehicle_id, driver_id, assigned_at)
+SELECT id, driver_id, updated_at FROM vehicles WHERE driver_id IS NOT NULL;
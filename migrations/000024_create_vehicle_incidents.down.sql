@@ -0,0 +1,10 @@
+DELETE FROM documents WHERE owner_type = 'INCIDENT';
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents ADD CONSTRAINT documents_kind_check
+    CHECK (kind IN ('DRIVER_PHOTO', 'LICENSE', 'BACKGROUND_CHECK', 'VEHICLE_PHOTO', 'MAINTENANCE_ATTACHMENT'));
+ALTER TABLE documents DROP CONSTRAINT documents_owner_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_owner_type_check
+    CHECK (owner_type IN ('DRIVER', 'VEHICLE', 'MAINTENANCE'));
+
+DROP TRIGGER IF EXISTS update_incidents_updated_at ON incidents;
+DROP TABLE IF EXISTS incidents;
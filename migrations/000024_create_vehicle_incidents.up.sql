@@ -0,0 +1,38 @@
+-- Accidents, damage, traffic fines and breakdowns reported against a vehicle,
+-- with the driver at the wheel and, when it happened on a trip, the
+-- reservation.
+CREATE TABLE incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id VARCHAR(20) REFERENCES drivers(id) ON DELETE SET NULL,
+    reservation_id VARCHAR(20) REFERENCES reservations(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('ACCIDENT', 'DAMAGE', 'TRAFFIC_FINE', 'BREAKDOWN', 'OTHER')),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('LOW', 'MEDIUM', 'HIGH', 'CRITICAL')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('OPEN', 'UNDER_REVIEW', 'RESOLVED')),
+    description TEXT NOT NULL,
+    location VARCHAR(255),
+    occurred_at TIMESTAMPTZ NOT NULL,
+    cost NUMERIC(12,2) CHECK (cost >= 0),
+    currency VARCHAR(3) NOT NULL,
+    resolution_notes TEXT,
+    resolved_at TIMESTAMPTZ,
+    reported_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Allow photos attached to incidents
+ALTER TABLE documents DROP CONSTRAINT documents_owner_type_check;
+ALTER TABLE documents ADD CONSTRAINT documents_owner_type_check
+    CHECK (owner_type IN ('DRIVER', 'VEHICLE', 'MAINTENANCE', 'INCIDENT'));
+ALTER TABLE documents DROP CONSTRAINT documents_kind_check;
+ALTER TABLE documents ADD CONSTRAINT documents_kind_check
+    CHECK (kind IN ('DRIVER_PHOTO', 'LICENSE', 'BACKGROUND_CHECK', 'VEHICLE_PHOTO', 'MAINTENANCE_ATTACHMENT', 'INCIDENT_PHOTO'));
+
+-- Create indexes for better performance
+CREATE INDEX idx_incidents_vehicle ON incidents(vehicle_id, occurred_at);
+CREATE INDEX idx_incidents_driver ON incidents(driver_id, occurred_at);
+CREATE INDEX idx_incidents_status ON incidents(status) WHERE status <> 'RESOLVED';
+
+CREATE TRIGGER update_incidents_updated_at BEFORE UPDATE ON incidents
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS update_refunds_updated_at ON refunds;
+DROP TABLE IF EXISTS refunds;
+
+-- Enum values cannot be dropped: move refunded payments back to APPROVED and
+-- recreate the type without them.
+ALTER TABLE payments ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE payments ALTER COLUMN status TYPE TEXT;
+UPDATE payments SET status = 'APPROVED' WHERE status IN ('PARTIALLY_REFUNDED', 'REFUNDED');
+DROP TYPE payment_status;
+CREATE TYPE payment_status AS ENUM ('APPROVED', 'REJECTED', 'PENDING');
+ALTER TABLE payments ALTER COLUMN status TYPE payment_status USING status::payment_status;
+ALTER TABLE payments ALTER COLUMN status SET DEFAULT 'PENDING';
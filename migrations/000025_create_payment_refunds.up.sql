@@ -0,0 +1,26 @@
+-- Payments can be refunded in full or in part
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'PARTIALLY_REFUNDED';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'REFUNDED';
+
+-- Refunds issued against a payment. A refund is PENDING while the gateway is
+-- asked for it, so concurrent requests count it against the captured amount.
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED')),
+    transaction_ref VARCHAR(255),
+    payload JSONB,
+    failure_reason TEXT,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_refunds_payment ON refunds(payment_id, created_at);
+
+CREATE TRIGGER update_refunds_updated_at BEFORE UPDATE ON refunds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
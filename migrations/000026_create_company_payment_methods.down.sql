@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS update_company_payment_methods_updated_at ON company_payment_methods;
+DROP TABLE IF EXISTS company_payment_methods;
+
+-- Enum values cannot be dropped: remove payments made with the new gateways
+-- and recreate the type without them.
+DELETE FROM payments WHERE gateway IN ('MERCADO_PAGO', 'STRIPE', 'BANK_TRANSFER', 'COMPANY_CREDIT');
+ALTER TABLE payments ALTER COLUMN gateway TYPE TEXT;
+DROP TYPE payment_gateway;
+CREATE TYPE payment_gateway AS ENUM ('WEBPAY_PLUS');
+ALTER TABLE payments ALTER COLUMN gateway TYPE payment_gateway USING gateway::payment_gateway;
@@ -0,0 +1,20 @@
+-- More payment gateways besides Webpay Plus
+ALTER TYPE payment_gateway ADD VALUE IF NOT EXISTS 'MERCADO_PAGO';
+ALTER TYPE payment_gateway ADD VALUE IF NOT EXISTS 'STRIPE';
+ALTER TYPE payment_gateway ADD VALUE IF NOT EXISTS 'BANK_TRANSFER';
+ALTER TYPE payment_gateway ADD VALUE IF NOT EXISTS 'COMPANY_CREDIT';
+
+-- Payment methods each company turned on or off, per currency. Companies
+-- without settings for a currency get the default methods.
+CREATE TABLE company_payment_methods (
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    gateway VARCHAR(20) NOT NULL CHECK (gateway IN ('WEBPAY_PLUS', 'MERCADO_PAGO', 'STRIPE', 'BANK_TRANSFER', 'COMPANY_CREDIT')),
+    currency VARCHAR(3) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (company_id, gateway, currency)
+);
+
+CREATE TRIGGER update_company_payment_methods_updated_at BEFORE UPDATE ON company_payment_methods
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
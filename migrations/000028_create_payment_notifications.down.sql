@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_payments_pending;
+DROP TABLE IF EXISTS payment_notifications;
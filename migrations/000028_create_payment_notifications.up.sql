@@ -0,0 +1,20 @@
+-- Webhooks received from payment gateways. A gateway retries until it gets
+-- a 2xx, so the same event is stored once per gateway.
+CREATE TABLE payment_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    gateway VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('RECEIVED', 'PROCESSED', 'IGNORED', 'FAILED')),
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    transaction_ref VARCHAR(255),
+    payload JSONB,
+    error TEXT,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ,
+    UNIQUE (gateway, event_id)
+);
+
+-- Create indexes for better performance
+CREATE INDEX idx_payment_notifications_payment ON payment_notifications(payment_id);
+CREATE INDEX idx_payments_pending ON payments(created_at) WHERE status = 'PENDING';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reservations_org_cost_center;
+ALTER TABLE reservations DROP COLUMN IF EXISTS cost_center;
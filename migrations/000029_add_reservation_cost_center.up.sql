@@ -0,0 +1,5 @@
+-- Company cost center a reservation is billed to, used to split company
+-- billing. Reservations booked before keep it NULL.
+ALTER TABLE reservations ADD COLUMN cost_center VARCHAR(100);
+
+CREATE INDEX idx_reservations_org_cost_center ON reservations(org_id, cost_center);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_payments_invoice_id;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payments_subject;
+ALTER TABLE payments DROP COLUMN IF EXISTS invoice_id;
+DELETE FROM payments WHERE reservation_id IS NULL;
+ALTER TABLE payments ALTER COLUMN reservation_id SET NOT NULL;
+
+DROP TABLE IF EXISTS billing_accruals;
+DROP TABLE IF EXISTS billing_periods;
+DROP TABLE IF EXISTS invoice_lines;
+DROP TABLE IF EXISTS invoices;
+DROP SEQUENCE IF EXISTS invoice_number_seq;
+DROP TABLE IF EXISTS billing_accounts;
@@ -0,0 +1,104 @@
+-- Postpaid billing of corporate accounts: completed trips accrue to the
+-- account's open monthly period, which is closed into an invoice.
+CREATE TABLE billing_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL UNIQUE REFERENCES companies(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL,
+    tax_rate NUMERIC(5,4) NOT NULL CHECK (tax_rate >= 0 AND tax_rate <= 1),
+    payment_terms_days INTEGER NOT NULL CHECK (payment_terms_days >= 0),
+    billing_email VARCHAR(255),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE SEQUENCE invoice_number_seq;
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    number VARCHAR(20) NOT NULL UNIQUE DEFAULT ('F-' || lpad(nextval('invoice_number_seq')::text, 6, '0')),
+    account_id UUID NOT NULL REFERENCES billing_accounts(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ISSUED' CHECK (status IN ('ISSUED', 'PAID', 'OVERDUE', 'VOID')),
+    currency VARCHAR(3) NOT NULL,
+    subtotal NUMERIC(12,2) NOT NULL,
+    tax_rate NUMERIC(5,4) NOT NULL,
+    tax_amount NUMERIC(12,2) NOT NULL,
+    total NUMERIC(12,2) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL,
+    due_date DATE NOT NULL,
+    paid_at TIMESTAMPTZ,
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    voided_at TIMESTAMPTZ,
+    void_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end > period_start)
+);
+
+CREATE TABLE invoice_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    reservation_id VARCHAR(20) REFERENCES reservations(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price NUMERIC(12,2) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    UNIQUE (invoice_id, position)
+);
+
+-- An account has exactly one OPEN period; closing it opens the next one.
+CREATE TABLE billing_periods (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID NOT NULL REFERENCES billing_accounts(id) ON DELETE CASCADE,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED')),
+    invoice_id UUID REFERENCES invoices(id) ON DELETE SET NULL,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end > period_start),
+    UNIQUE (account_id, period_start)
+);
+
+-- Trips billed to a period. A reservation is accrued once.
+CREATE TABLE billing_accruals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID NOT NULL REFERENCES billing_accounts(id) ON DELETE CASCADE,
+    period_id UUID NOT NULL REFERENCES billing_periods(id) ON DELETE CASCADE,
+    reservation_id VARCHAR(20) NOT NULL UNIQUE REFERENCES reservations(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Invoices are paid through the payment flow: a payment is for either a
+-- reservation or an invoice.
+ALTER TABLE payments ALTER COLUMN reservation_id DROP NOT NULL;
+ALTER TABLE payments ADD COLUMN invoice_id UUID REFERENCES invoices(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD CONSTRAINT check_payments_subject CHECK (reservation_id IS NOT NULL OR invoice_id IS NOT NULL);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX idx_billing_periods_open ON billing_periods(account_id) WHERE status = 'OPEN';
+CREATE INDEX idx_billing_periods_open_end ON billing_periods(period_end) WHERE status = 'OPEN';
+CREATE INDEX idx_billing_accruals_period ON billing_accruals(period_id);
+CREATE UNIQUE INDEX idx_invoices_period ON invoices(account_id, period_start);
+CREATE INDEX idx_invoices_company ON invoices(company_id, issued_at);
+CREATE INDEX idx_invoices_status ON invoices(status);
+CREATE INDEX idx_invoice_lines_invoice ON invoice_lines(invoice_id);
+CREATE INDEX idx_payments_invoice_id ON payments(invoice_id);
+
+CREATE TRIGGER update_billing_accounts_updated_at BEFORE UPDATE ON billing_accounts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_billing_periods_updated_at BEFORE UPDATE ON billing_periods
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_invoices_updated_at BEFORE UPDATE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
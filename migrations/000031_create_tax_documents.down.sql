@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tax_documents;
+DROP TABLE IF EXISTS dte_folio_ranges;
@@ -0,0 +1,57 @@
+-- Folio authorizations (CAF) issued by the SII per document type. Folios
+-- are taken in order from next_folio.
+CREATE TABLE dte_folio_ranges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    doc_type SMALLINT NOT NULL CHECK (doc_type IN (33, 34, 39, 61)),
+    folio_from INTEGER NOT NULL CHECK (folio_from > 0),
+    folio_to INTEGER NOT NULL,
+    next_folio INTEGER NOT NULL,
+    authorized_at DATE NOT NULL,
+    caf BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (doc_type, folio_from),
+    CHECK (folio_to >= folio_from),
+    CHECK (next_folio BETWEEN folio_from AND folio_to + 1)
+);
+
+-- Electronic tax documents (DTE) as signed and sent to the SII
+CREATE TABLE tax_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    doc_type SMALLINT NOT NULL CHECK (doc_type IN (33, 34, 39, 61)),
+    folio INTEGER NOT NULL,
+    folio_range_id UUID NOT NULL REFERENCES dte_folio_ranges(id),
+    issue_date DATE NOT NULL,
+    invoice_id UUID REFERENCES invoices(id) ON DELETE RESTRICT,
+    payment_id UUID REFERENCES payments(id) ON DELETE RESTRICT,
+    reference_id UUID REFERENCES tax_documents(id) ON DELETE RESTRICT,
+    reference_code SMALLINT,
+    reference_reason VARCHAR(90),
+    receiver_rut VARCHAR(12) NOT NULL,
+    receiver_name VARCHAR(100) NOT NULL,
+    net_amount BIGINT NOT NULL DEFAULT 0,
+    exempt_amount BIGINT NOT NULL DEFAULT 0,
+    tax_rate NUMERIC(5,4) NOT NULL,
+    tax_amount BIGINT NOT NULL DEFAULT 0,
+    total_amount BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'SIGNED' CHECK (status IN ('SIGNED', 'SUBMITTED', 'ACCEPTED', 'REJECTED')),
+    track_id VARCHAR(50),
+    status_detail TEXT,
+    submitted_at TIMESTAMPTZ,
+    ted TEXT NOT NULL,
+    xml BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (doc_type, folio),
+    CHECK ((doc_type = 61) = (reference_id IS NOT NULL))
+);
+
+-- An invoice gets one factura and a payment one boleta; credit notes
+-- reference them
+CREATE UNIQUE INDEX idx_tax_documents_invoice ON tax_documents(invoice_id) WHERE doc_type IN (33, 34);
+CREATE UNIQUE INDEX idx_tax_documents_payment ON tax_documents(payment_id) WHERE doc_type = 39;
+CREATE INDEX idx_tax_documents_reference ON tax_documents(reference_id) WHERE reference_id IS NOT NULL;
+CREATE INDEX idx_tax_documents_status ON tax_documents(status, created_at) WHERE status IN ('SIGNED', 'SUBMITTED');
+CREATE INDEX idx_tax_documents_track_id ON tax_documents(track_id) WHERE track_id IS NOT NULL;
+
+CREATE TRIGGER update_tax_documents_updated_at BEFORE UPDATE ON tax_documents
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
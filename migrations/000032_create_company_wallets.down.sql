@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_payments_wallet_id;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payments_subject;
+DELETE FROM payments WHERE reservation_id IS NULL AND invoice_id IS NULL;
+ALTER TABLE payments DROP COLUMN IF EXISTS wallet_id;
+ALTER TABLE payments ADD CONSTRAINT check_payments_subject CHECK (reservation_id IS NOT NULL OR invoice_id IS NOT NULL);
+
+DROP TABLE IF EXISTS wallet_ledger_entries;
+DROP FUNCTION IF EXISTS check_wallet_transaction_balanced();
+DROP TABLE IF EXISTS wallet_transactions;
+DROP TABLE IF EXISTS company_wallets;
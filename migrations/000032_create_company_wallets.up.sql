@@ -0,0 +1,72 @@
+-- Prepaid balance and credit line of a company. The balance goes negative
+-- while the company draws on its credit line.
+CREATE TABLE company_wallets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL UNIQUE REFERENCES companies(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL,
+    balance NUMERIC(12,2) NOT NULL DEFAULT 0,
+    credit_limit NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0),
+    low_balance_threshold NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (low_balance_threshold >= 0),
+    alert_email VARCHAR(255),
+    low_balance_alerted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Postings that move a wallet's balance. A top-up, a trip and a refund are
+-- posted once.
+CREATE TABLE wallet_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES company_wallets(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('TOP_UP', 'TOP_UP_REFUND', 'TRIP_DEBIT', 'TRIP_REFUND', 'ADJUSTMENT')),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount <> 0),
+    balance_after NUMERIC(12,2) NOT NULL,
+    description TEXT NOT NULL,
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    reservation_id VARCHAR(20) REFERENCES reservations(id) ON DELETE SET NULL,
+    refund_id UUID REFERENCES refunds(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Double-entry lines of a transaction. Wallet lines name the wallet; the
+-- other accounts are shared. The lines of a transaction add up to zero.
+CREATE TABLE wallet_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES wallet_transactions(id) ON DELETE CASCADE,
+    account VARCHAR(20) NOT NULL CHECK (account IN ('WALLET', 'GATEWAY_CLEARING', 'TRIP_REVENUE', 'ADJUSTMENTS')),
+    wallet_id UUID REFERENCES company_wallets(id) ON DELETE CASCADE,
+    amount NUMERIC(12,2) NOT NULL,
+    CHECK ((account = 'WALLET') = (wallet_id IS NOT NULL))
+);
+
+CREATE OR REPLACE FUNCTION check_wallet_transaction_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT COALESCE(SUM(amount), 0) FROM wallet_ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger entries of transaction % do not balance', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE CONSTRAINT TRIGGER check_wallet_ledger_entries_balanced AFTER INSERT ON wallet_ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_wallet_transaction_balanced();
+
+-- Wallets are topped up through the payment flow: a payment is for a
+-- reservation, an invoice or a wallet.
+ALTER TABLE payments ADD COLUMN wallet_id UUID REFERENCES company_wallets(id) ON DELETE SET NULL;
+ALTER TABLE payments DROP CONSTRAINT check_payments_subject;
+ALTER TABLE payments ADD CONSTRAINT check_payments_subject CHECK (reservation_id IS NOT NULL OR invoice_id IS NOT NULL OR wallet_id IS NOT NULL);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX idx_wallet_transactions_top_up ON wallet_transactions(payment_id) WHERE kind = 'TOP_UP';
+CREATE UNIQUE INDEX idx_wallet_transactions_trip_debit ON wallet_transactions(reservation_id) WHERE kind = 'TRIP_DEBIT';
+CREATE UNIQUE INDEX idx_wallet_transactions_refund ON wallet_transactions(refund_id) WHERE refund_id IS NOT NULL;
+CREATE INDEX idx_wallet_transactions_wallet ON wallet_transactions(wallet_id, created_at);
+CREATE INDEX idx_wallet_ledger_entries_transaction ON wallet_ledger_entries(transaction_id);
+CREATE INDEX idx_payments_wallet_id ON payments(wallet_id);
+
+CREATE TRIGGER update_company_wallets_updated_at BEFORE UPDATE ON company_wallets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
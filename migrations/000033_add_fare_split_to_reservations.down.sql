@@ -0,0 +1,4 @@
+ALTER TABLE reservations
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS driver_payout,
+DROP COLUMN IF EXISTS commission;
@@ -0,0 +1,6 @@
+-- Commission and driver payout split from the amount when the price is
+-- locked, so later commission rate changes do not touch booked trips
+ALTER TABLE reservations
+ADD COLUMN commission NUMERIC(12,2) NULL,
+ADD COLUMN driver_payout NUMERIC(12,2) NULL,
+ADD COLUMN currency VARCHAR(3) NULL;
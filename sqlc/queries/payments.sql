@@ -1,6 +1,6 @@
 -- name: CreatePayment :one
-INSERT INTO payments (id, reservation_id, gateway, amount, currency, status, transaction_ref, payload)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO payments (id, reservation_id, gateway, amount, currency, status, transaction_ref, payload, invoice_id, wallet_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
 -- name: GetPaymentByID :one